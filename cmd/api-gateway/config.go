@@ -0,0 +1,124 @@
+package main
+
+import (
+	"time"
+
+	"github.com/zeromicro/go-zero/gateway"
+
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/httpcache"
+	"ecommerce-system/internal/pkg/signedurl"
+)
+
+// Config 网关配置：在 go-zero GatewayConf 基础上增加网关自身使用的组件配置
+type Config struct {
+	gateway.GatewayConf
+	// BizRedis 网关业务侧使用的 Redis（幂等键等），避免与 RestConf 内置字段冲突
+	BizRedis    RedisConfig       `json:",optional"`
+	Idempotency IdempotencyConfig `json:",optional"`
+	Canary      CanaryConfig      `json:",optional"`
+	// ResponseCache 匿名 GET 响应缓存
+	ResponseCache ResponseCacheConfig `json:",optional"`
+	// Kafka 消费 outbox 事件（清除响应缓存）
+	Kafka KafkaConfig `json:",optional"`
+	// BFF 页面聚合接口
+	BFF BFFConfig `json:",optional"`
+	// Static /uploads/、/images/ 静态文件访问控制与缓存
+	Static StaticConfig `json:",optional"`
+	// Upload 文件上传（流式转发给 file-service）
+	Upload UploadConfig `json:",optional"`
+	// OpenAPI 开放平台 API Key 验签、限流与调用审计（依赖 BizRedis）
+	OpenAPI OpenAPIConfig `json:",optional"`
+}
+
+// RedisConfig Redis配置
+type RedisConfig struct {
+	Host         string `json:",optional"`
+	Port         int    `json:",default=6379"`
+	Password     string `json:",optional"`
+	Database     int    `json:",optional"`
+	PoolSize     int    `json:",default=10"`
+	MinIdleConns int    `json:",default=5"`
+}
+
+// IdempotencyConfig Idempotency-Key 配置
+type IdempotencyConfig struct {
+	Enabled bool  `json:",optional"`
+	TTL     int64 `json:",default=86400"` // 最终响应保存时长（秒）
+	LockTTL int64 `json:",default=30"`    // 处理中占位时长（秒），应大于上游超时
+	// JWTSecret 校验令牌后按用户 ID 隔离幂等键（与 user-service 保持一致）
+	JWTSecret string `json:",optional"`
+}
+
+// CanaryConfig 灰度路由配置，版本权重与规则放在独立文件中，修改后无需重启
+type CanaryConfig struct {
+	Enabled        bool   `json:",optional"`
+	File           string `json:",default=configs/dev/canary.yaml"`
+	ReloadInterval int64  `json:",default=5"` // 检查配置文件变更的间隔（秒）
+}
+
+// ResponseCacheConfig 网关响应缓存配置（依赖 BizRedis）
+type ResponseCacheConfig struct {
+	Enabled bool              `json:",optional"`
+	Routes  []httpcache.Route `json:",optional"`
+}
+
+// KafkaConfig Kafka配置
+type KafkaConfig struct {
+	Brokers       []string `json:",optional"`
+	Version       string   `json:",default=2.8.0"`
+	ConsumerGroup string   `json:",default=api-gateway"`
+}
+
+// BFFConfig 页面聚合接口配置
+type BFFConfig struct {
+	Enabled   bool        `json:",optional"`
+	CacheTTL  int64       `json:",default=30"` // 商品详情页缓存时长（秒），0 表示不缓存
+	Product   BFFUpstream `json:",optional"`
+	Inventory BFFUpstream `json:",optional"`
+	Review    BFFUpstream `json:",optional"`
+	Promotion BFFUpstream `json:",optional"`
+	Recommend BFFUpstream `json:",optional"`
+	Seckill   BFFUpstream `json:",optional"`
+}
+
+// BFFUpstream 聚合接口依赖的下游服务
+type BFFUpstream struct {
+	Endpoint string `json:",optional"`
+	Timeout  int64  `json:",default=800"` // 该依赖的超时时间（毫秒），超时即降级
+}
+
+func (u BFFUpstream) rpcConf() client.RpcConf {
+	return client.RpcConf{Endpoint: u.Endpoint, Timeout: time.Duration(u.Timeout) * time.Millisecond}
+}
+
+// StaticConfig 静态文件配置
+type StaticConfig struct {
+	UploadsDir   string `json:",default=uploads"`
+	PublicMaxAge int64  `json:",default=86400"` // 公开文件的 Cache-Control max-age（秒）
+	// JWTSecret 校验绑定用户的签名链接（与 user-service 保持一致）
+	JWTSecret string `json:",optional"`
+	// Signing 签名 URL 配置（与 file-service Signing 保持一致）
+	Signing signedurl.Config `json:",optional"`
+}
+
+// UploadConfig 文件上传配置，大小和类型限制在流式转发过程中校验
+type UploadConfig struct {
+	FileService  string   `json:",default=127.0.0.1:8012"`
+	MaxFileSize  int64    `json:",default=104857600"` // 单文件大小上限（字节）
+	MaxBatchSize int64    `json:",default=209715200"` // 批量上传请求体总大小上限（字节）
+	MaxFiles     int      `json:",default=20"`        // 批量上传的最大文件数
+	AllowedTypes []string `json:",optional"`          // 允许的 MIME 类型（按内容嗅探），如 image/*、application/pdf
+}
+
+// OpenAPIConfig 开放平台配置，Scopes 定义权限范围可访问的路由，未覆盖的路由 API Key 一律不能访问
+type OpenAPIConfig struct {
+	Enabled          bool               `json:",optional"`
+	UserService      BFFUpstream        `json:",optional"`    // 查询凭证、上报调用记录
+	JWTSecret        string             `json:",optional"`    // 与 user-service 保持一致，网关以 Key 所属用户身份签发内部 JWT
+	TimestampWindow  int64              `json:",default=300"` // 请求时间戳允许的偏差（秒）
+	DefaultRateLimit int                `json:",default=600"` // Key 未单独设置时的每分钟请求数
+	CredentialTTL    int64              `json:",default=30"`  // 凭证本地缓存时长（秒），也是内部 JWT 的有效期，吊销最多延迟这么久生效
+	Scopes           []apikey.ScopeRule `json:",optional"`
+}
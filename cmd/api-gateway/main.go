@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -8,13 +9,25 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"strconv"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/gateway"
 
 	"ecommerce-system/internal/handler"
 	"ecommerce-system/internal/middleware"
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/canary"
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/httpcache"
+	"ecommerce-system/internal/pkg/idempotency"
+	"ecommerce-system/internal/pkg/mq"
+	"ecommerce-system/internal/pkg/outbox"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/signedurl"
 )
 
 var configFile = flag.String("f", "configs/dev/gateway.yaml", "配置文件路径")
@@ -22,15 +35,15 @@ var configFile = flag.String("f", "configs/dev/gateway.yaml", "配置文件路
 func main() {
 	flag.Parse()
 
-	var c gateway.GatewayConf
+	var c Config
 	conf.MustLoad(*configFile, &c)
 
-	// 启动 Swagger 静态文件服务和 UI，单独端口，避免影响原有网关
+	// 启动 Swagger 静态文件服务和 UI，单独端口，避免影响原有网关（文档由 make swagger 生成到 docs/openapi）
 	go func() {
 		mux := http.NewServeMux()
 
 		// 静态文件服务
-		fs := http.FileServer(http.Dir("docs/swagger"))
+		fs := http.FileServer(http.Dir("docs/openapi"))
 		mux.Handle("/swagger/", http.StripPrefix("/swagger/", fs))
 
 		// Swagger UI 首页
@@ -55,23 +68,7 @@ func main() {
     <script>
         window.onload = function() {
             const ui = SwaggerUIBundle({
-                urls: [
-                    {url: "/swagger/api/user/v1/user.swagger.json", name: "用户服务"},
-                    {url: "/swagger/api/product/v1/product.swagger.json", name: "商品服务"},
-                    {url: "/swagger/api/order/v1/order.swagger.json", name: "订单服务"},
-                    {url: "/swagger/api/payment/v1/payment.swagger.json", name: "支付服务"},
-                    {url: "/swagger/api/cart/v1/cart.swagger.json", name: "购物车服务"},
-                    {url: "/swagger/api/inventory/v1/inventory.swagger.json", name: "库存服务"},
-                    {url: "/swagger/api/promotion/v1/promotion.swagger.json", name: "营销服务"},
-                    {url: "/swagger/api/message/v1/message.swagger.json", name: "消息服务"},
-                    {url: "/swagger/api/search/v1/search.swagger.json", name: "搜索服务"},
-                    {url: "/swagger/api/recommend/v1/recommend.swagger.json", name: "推荐服务"},
-                    {url: "/swagger/api/review/v1/review.swagger.json", name: "评价服务"},
-                    {url: "/swagger/api/logistics/v1/logistics.swagger.json", name: "物流服务"},
-                    {url: "/swagger/api/file/v1/file.swagger.json", name: "文件服务"},
-                    {url: "/swagger/api/job/v1/job.swagger.json", name: "任务服务"}
-                ],
-                "urls.primaryName": "用户服务",
+                url: "/swagger/openapi.json",
                 dom_id: '#swagger-ui',
                 deepLinking: true,
                 presets: [
@@ -157,7 +154,12 @@ func main() {
 	}()
 
 	// 创建文件上传处理器
-	fileUploadHandler, err := handler.NewFileUploadHandler("127.0.0.1:8012")
+	fileUploadHandler, err := handler.NewFileUploadHandler(c.Upload.FileService, handler.FileUploadConfig{
+		MaxFileSize:  c.Upload.MaxFileSize,
+		MaxBatchSize: c.Upload.MaxBatchSize,
+		MaxFiles:     c.Upload.MaxFiles,
+		AllowedTypes: c.Upload.AllowedTypes,
+	})
 	if err != nil {
 		log.Printf("⚠️  创建文件上传处理器失败: %v，文件上传功能将不可用", err)
 		fileUploadHandler = nil
@@ -166,16 +168,157 @@ func main() {
 	// 创建 CORS 中间件（按照 go-zero 最佳实践）
 	corsMiddleware := middleware.NewCorsMiddleware()
 
+	// 网关业务 Redis（幂等键等），连接失败时相关功能降级为关闭
+	var bizRedis *redis.Client
+	if c.BizRedis.Host != "" {
+		rdb, err := cache.NewRedis(&cache.Config{
+			Host:         c.BizRedis.Host,
+			Port:         c.BizRedis.Port,
+			Password:     c.BizRedis.Password,
+			Database:     c.BizRedis.Database,
+			PoolSize:     c.BizRedis.PoolSize,
+			MinIdleConns: c.BizRedis.MinIdleConns,
+		})
+		if err != nil {
+			log.Printf("⚠️  连接网关 Redis 失败: %v，幂等等依赖 Redis 的功能将不可用", err)
+		} else {
+			bizRedis = rdb
+		}
+	}
+
+	// 创建幂等中间件：POST/PUT 请求带 Idempotency-Key 时回放首次响应
+	var idempotencyMiddleware *middleware.IdempotencyMiddleware
+	if c.Idempotency.Enabled && bizRedis != nil {
+		store := idempotency.NewStore(bizRedis,
+			time.Duration(c.Idempotency.TTL)*time.Second,
+			time.Duration(c.Idempotency.LockTTL)*time.Second)
+		idempotencyMiddleware = middleware.NewIdempotencyMiddleware(store, c.Idempotency.JWTSecret)
+		log.Printf("✅ Idempotency-Key 支持已启用 (TTL=%ds)", c.Idempotency.TTL)
+	}
+
+	// 创建开放平台 API Key 中间件：带 X-Api-Key 的请求验签、防重放、按 Key 限流并上报调用记录
+	var apiKeyMiddleware *middleware.ApiKeyMiddleware
+	if c.OpenAPI.Enabled && bizRedis != nil {
+		apiKeyClient, err := client.NewApiKeyClient(c.OpenAPI.UserService.rpcConf())
+		if err != nil {
+			log.Printf("⚠️  创建 API Key 客户端失败: %v，开放平台将不可用", err)
+		} else {
+			defer apiKeyClient.Close()
+			apiKeyMiddleware = middleware.NewApiKeyMiddleware(apiKeyClient,
+				apikey.NewGuard(bizRedis, time.Duration(c.OpenAPI.TimestampWindow)*time.Second),
+				revocation.NewDenylist(bizRedis),
+				middleware.ApiKeyConfig{
+					Scopes:           c.OpenAPI.Scopes,
+					DefaultRateLimit: c.OpenAPI.DefaultRateLimit,
+					JWTSecret:        c.OpenAPI.JWTSecret,
+					CredentialTTL:    time.Duration(c.OpenAPI.CredentialTTL) * time.Second,
+				})
+			reportCtx, cancelReport := context.WithCancel(context.Background())
+			defer cancelReport()
+			go apiKeyMiddleware.RunReporter(reportCtx)
+			log.Printf("✅ 开放平台 API Key 鉴权已启用，共 %d 个权限范围", len(c.OpenAPI.Scopes))
+		}
+	}
+
+	// 创建响应缓存中间件：匿名 GET 请求按路由缓存，商品变更事件到达时清除
+	var responseCacheMiddleware *middleware.ResponseCacheMiddleware
+	if c.ResponseCache.Enabled && bizRedis != nil && len(c.ResponseCache.Routes) > 0 {
+		responseCacheMiddleware = middleware.NewResponseCacheMiddleware(httpcache.NewStore(bizRedis), c.ResponseCache.Routes)
+		log.Printf("✅ 响应缓存已启用，共 %d 条路由", len(c.ResponseCache.Routes))
+	}
+
+	// 创建商品详情页聚合处理器（BFF）：并发调用各下游服务，部分依赖失败时降级
+	var productPageHandler *handler.ProductPageHandler
+	if c.BFF.Enabled {
+		h, err := handler.NewProductPageHandler(handler.ProductPageConfig{
+			Product:   c.BFF.Product.rpcConf(),
+			Inventory: c.BFF.Inventory.rpcConf(),
+			Review:    c.BFF.Review.rpcConf(),
+			Promotion: c.BFF.Promotion.rpcConf(),
+			Recommend: c.BFF.Recommend.rpcConf(),
+			Seckill:   c.BFF.Seckill.rpcConf(),
+			CacheTTL:  time.Duration(c.BFF.CacheTTL) * time.Second,
+		}, bizRedis)
+		if err != nil {
+			log.Printf("⚠️  创建商品详情页聚合处理器失败: %v，/api/v1/pages/product 将不可用", err)
+		} else {
+			productPageHandler = h
+			defer productPageHandler.Close()
+		}
+	}
+
+	// 消费商品变更事件，清除响应缓存和商品详情页缓存；Kafka 可选，未配置时缓存只按 TTL 过期
+	if len(c.Kafka.Brokers) > 0 && (responseCacheMiddleware != nil || productPageHandler != nil) {
+		consumer, err := mq.NewConsumer(&mq.Config{
+			Brokers:       c.Kafka.Brokers,
+			Version:       c.Kafka.Version,
+			ConsumerGroup: c.Kafka.ConsumerGroup,
+		})
+		if err != nil {
+			log.Printf("⚠️  初始化 Kafka 消费者失败: %v，网关缓存将只按 TTL 过期", err)
+		} else {
+			defer consumer.Close()
+			consumer.RegisterHandler(mq.TopicDataSync, func(ctx context.Context, msg *mq.Message) error {
+				switch msg.EventType {
+				case outbox.EventProductUpserted, outbox.EventProductDeleted:
+					if responseCacheMiddleware != nil {
+						responseCacheMiddleware.Purge(ctx, msg.EventType)
+					}
+					if productPageHandler != nil {
+						if id, err := strconv.ParseInt(fmt.Sprint(msg.Data["aggregate_id"]), 10, 64); err == nil {
+							productPageHandler.Invalidate(ctx, id)
+						}
+					}
+				}
+				return nil
+			})
+			go func() {
+				_ = consumer.Start(context.Background(), []string{mq.TopicDataSync})
+			}()
+		}
+	}
+
+	// 灰度路由：必须在创建 Gateway（拨号上游）之前注册解析器和负载均衡器
+	var canaryMiddleware *middleware.CanaryMiddleware
+	if c.Canary.Enabled {
+		router, err := canary.LoadFile(c.Canary.File)
+		if err != nil {
+			log.Fatalf("加载灰度路由配置失败: %v", err)
+		}
+		canary.Register(router)
+		watchCtx, cancelWatch := context.WithCancel(context.Background())
+		defer cancelWatch()
+		go router.Watch(watchCtx, c.Canary.File, time.Duration(c.Canary.ReloadInterval)*time.Second)
+		canaryMiddleware = middleware.NewCanaryMiddleware(router)
+		log.Printf("✅ 灰度路由已启用: %s (每 %ds 检查一次变更)", c.Canary.File, c.Canary.ReloadInterval)
+	}
+
 	// 创建 Gateway 服务器（使用内部端口，不直接暴露）
 	// Gateway 将在内部端口运行，然后通过反向代理暴露
 	internalPort := c.Port + 1000 // 使用 9080 作为内部端口
-	internalConfig := c
+	internalConfig := c.GatewayConf
 	internalConfig.Port = internalPort
 
 	gw := gateway.MustNewServer(internalConfig, func(svr *gateway.Server) {
 		// 添加 CORS 中间件
 		svr.Use(corsMiddleware.Handle)
-	})
+		// 添加开放平台 API Key 中间件（放在幂等之前，幂等键按 Key 隔离）
+		if apiKeyMiddleware != nil {
+			svr.Use(apiKeyMiddleware.Handle)
+		}
+		// 添加幂等中间件
+		if idempotencyMiddleware != nil {
+			svr.Use(idempotencyMiddleware.Handle)
+		}
+		// 添加灰度路由中间件
+		if canaryMiddleware != nil {
+			svr.Use(canaryMiddleware.Handle)
+		}
+		// 添加响应缓存中间件（放在灰度之后，命中缓存时不再访问上游）
+		if responseCacheMiddleware != nil {
+			svr.Use(responseCacheMiddleware.Handle)
+		}
+	}, gateway.WithHeaderProcessor(middleware.ForwardHeaders("Authorization", "X-Forwarded-For", "User-Agent", "X-Device-Id", idempotency.HeaderKey)))
 	defer gw.Stop()
 
 	// 在后台启动 Gateway（使用内部端口）
@@ -204,35 +347,17 @@ func main() {
 	// 创建主 HTTP 服务器（使用 Gateway 的原始端口）
 	mainMux := http.NewServeMux()
 
-	// 静态文件服务：提供上传的图片文件访问
-	// 文件保存在 uploads/ 目录下，通过 /uploads/ 路径访问
-	// 添加 CORS 支持，允许前端跨域访问图片
-	uploadsFS := http.FileServer(http.Dir("uploads"))
-	mainMux.HandleFunc("/uploads/", func(w http.ResponseWriter, r *http.Request) {
-		// 设置 CORS 头
-		origin := r.Header.Get("Origin")
-		if origin != "" {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
-		} else {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-		}
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin")
-		w.Header().Set("Access-Control-Max-Age", "3600")
-
-		// 处理 OPTIONS 预检请求
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-
-		// 提供静态文件
-		http.StripPrefix("/uploads/", uploadsFS).ServeHTTP(w, r)
-	})
-	log.Printf("✅ 静态文件服务已注册: /uploads/ -> uploads/ (已启用 CORS)")
+	// 静态文件服务：提供上传文件访问，文件保存在 uploads/ 目录下，通过 /uploads/ 路径访问
+	// 私有分类需要 file-service 签发的签名 URL，公开分类可被浏览器/CDN 缓存
+	// 绑定用户的链接同时校验令牌黑名单，登出或改密后旧令牌不能再访问
+	var staticDenylist *revocation.Denylist
+	if bizRedis != nil {
+		staticDenylist = revocation.NewDenylist(bizRedis)
+	}
+	mainMux.Handle("/uploads/", handler.NewStaticHandler("/uploads/", c.Static.UploadsDir, c.Static.Signing, c.Static.PublicMaxAge, c.Static.JWTSecret, staticDenylist))
+	log.Printf("✅ 静态文件服务已注册: /uploads/ -> %s (私有分类: %v)", c.Static.UploadsDir, c.Static.Signing.PrivateCategories)
 
-	// 静态文件服务：提供爬虫下载的图片文件访问
+	// 静态文件服务：提供爬虫下载的图片文件访问（商品图片，全部公开）
 	// 兼容两种目录：
 	// 1. 项目根目录 images/
 	// 2. 爬虫目录 cmd/mi-crawler/images/
@@ -240,30 +365,8 @@ func main() {
 	if _, err := os.Stat(imagesDir); os.IsNotExist(err) {
 		imagesDir = "cmd/mi-crawler/images"
 	}
-	imagesFS := http.FileServer(http.Dir(imagesDir))
-	mainMux.HandleFunc("/images/", func(w http.ResponseWriter, r *http.Request) {
-		// 设置 CORS 头
-		origin := r.Header.Get("Origin")
-		if origin != "" {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
-		} else {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-		}
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin")
-		w.Header().Set("Access-Control-Max-Age", "3600")
-
-		// 处理 OPTIONS 预检请求
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-
-		// 提供静态文件
-		http.StripPrefix("/images/", imagesFS).ServeHTTP(w, r)
-	})
-	log.Printf("✅ 静态文件服务已注册: /images/ -> %s (已启用 CORS)", imagesDir)
+	mainMux.Handle("/images/", handler.NewStaticHandler("/images/", imagesDir, signedurl.Config{}, c.Static.PublicMaxAge, "", nil))
+	log.Printf("✅ 静态文件服务已注册: /images/ -> %s", imagesDir)
 
 	// 文件上传路由（直接处理，不经过 Gateway）
 	if fileUploadHandler != nil {
@@ -272,6 +375,12 @@ func main() {
 		log.Printf("✅ 文件上传路由已注册: /api/v1/files/upload, /api/v1/files/batch-upload")
 	}
 
+	// 商品详情页聚合路由（BFF，直接处理，不经过 Gateway）
+	if productPageHandler != nil {
+		mainMux.HandleFunc(handler.ProductPagePath, productPageHandler.HandleProductPage)
+		log.Printf("✅ 商品详情页聚合路由已注册: %s{id}", handler.ProductPagePath)
+	}
+
 	// 修改反向代理，添加 CORS 支持
 	originalDirector := gatewayProxy.Director
 	gatewayProxy.Director = func(req *http.Request) {
@@ -296,8 +405,8 @@ func main() {
 			resp.Header.Set("Access-Control-Allow-Origin", "*")
 		}
 		resp.Header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		resp.Header.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length")
-		resp.Header.Set("Access-Control-Expose-Headers", "Content-Length, Content-Type")
+		resp.Header.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length, Idempotency-Key, X-Canary, X-Device-Id, If-None-Match, If-Modified-Since")
+		resp.Header.Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, Idempotent-Replayed, ETag, Last-Modified, X-Cache")
 		resp.Header.Set("Access-Control-Max-Age", "3600")
 		return nil
 	}
@@ -315,7 +424,7 @@ func main() {
 				w.Header().Set("Access-Control-Allow-Origin", "*")
 			}
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length, Idempotency-Key, X-Canary, X-Device-Id, If-None-Match, If-Modified-Since")
 			w.Header().Set("Access-Control-Max-Age", "3600")
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/conf"
+
+	"ecommerce-system/internal/pkg/constants"
+	"ecommerce-system/internal/pkg/database"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+	userservice "ecommerce-system/internal/service/user/service"
+)
+
+// bootstrap-admin 初始化管理员：同步内置权限和角色，并给指定用户分配超级管理员角色。
+// 用户不存在时按 -password 创建。可以重复执行。
+var (
+	configFile = flag.String("f", "configs/dev/user-config.yaml", "user-service 配置文件路径")
+	username   = flag.String("username", "admin", "管理员用户名")
+	password   = flag.String("password", "", "管理员密码（用户不存在时必填，至少8位且不能是常见密码）")
+)
+
+func main() {
+	flag.Parse()
+
+	var c user.Config
+	conf.MustLoad(*configFile, &c)
+
+	db := database.MustNewMySQL(&database.Config{
+		Host:            c.Database.Host,
+		Port:            c.Database.Port,
+		User:            c.Database.User,
+		Password:        c.Database.Password,
+		Database:        c.Database.Database,
+		Charset:         c.Database.Charset,
+		MaxOpenConns:    c.Database.MaxOpenConns,
+		MaxIdleConns:    c.Database.MaxIdleConns,
+		ConnMaxLifetime: c.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: c.Database.ConnMaxIdleTime,
+	})
+
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	credentialRepo := repository.NewCredentialRepository(db)
+	// 新分配的角色对已签发的令牌不生效，管理员重新登录即可，这里不连 Redis
+	roleLogic := userservice.NewRoleLogic(repository.NewRoleRepository(db), userRepo, nil, c.JWT.Expire)
+
+	// 1. 同步权限点和内置角色
+	if err := roleLogic.SyncBuiltin(ctx); err != nil {
+		log.Fatalf("同步内置角色失败: %v", err)
+	}
+
+	// 2. 查找或创建管理员用户
+	admin, err := userRepo.GetByUsername(ctx, *username)
+	if err != nil {
+		log.Fatalf("查询用户失败: %v", err)
+	}
+	if admin == nil {
+		admin, err = createAdmin(ctx, userRepo, credentialRepo)
+		if err != nil {
+			log.Fatalf("创建管理员失败: %v", err)
+		}
+		fmt.Printf("已创建用户 %s (id=%d)\n", admin.Username, admin.ID)
+	}
+
+	// 3. 分配超级管理员角色
+	roles, err := roleLogic.AssignRole(ctx, admin.ID, rbac.RoleSuperAdmin)
+	if err != nil {
+		log.Fatalf("分配角色失败: %v", err)
+	}
+	codes := make([]string, 0, len(roles))
+	for _, r := range roles {
+		codes = append(codes, r.Code)
+	}
+	fmt.Printf("用户 %s (id=%d) 当前角色: %v\n", admin.Username, admin.ID, codes)
+	for _, r := range c.MFA.RequiredRoles {
+		if r == rbac.RoleSuperAdmin {
+			fmt.Println("该角色要求两步验证，首次登录时按提示绑定验证器应用")
+			break
+		}
+	}
+}
+
+// createAdmin 创建带密码凭证的用户
+func createAdmin(ctx context.Context, userRepo repository.UserRepository, credentialRepo repository.CredentialRepository) (*model.User, error) {
+	if *password == "" {
+		return nil, fmt.Errorf("用户 %s 不存在，需要通过 -password 指定密码", *username)
+	}
+	if err := utils.ValidatePassword(*password, *username); err != nil {
+		return nil, fmt.Errorf("-password 不符合密码策略: %w", err)
+	}
+	hashedPassword, err := utils.HashPassword(*password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	admin := &model.User{
+		Username:    *username,
+		Status:      constants.UserStatusNormal,
+		MemberLevel: constants.MemberLevelNormal,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := userRepo.CreateWithOmit(ctx, admin); err != nil {
+		return nil, err
+	}
+	if err := credentialRepo.Create(ctx, &model.Credential{
+		UserID:          admin.ID,
+		CredentialType:  1, // 1-密码
+		CredentialKey:   *username,
+		CredentialValue: hashedPassword,
+		Extra:           "{}",
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}); err != nil {
+		_ = userRepo.Delete(ctx, admin.ID)
+		return nil, err
+	}
+	return admin, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -11,8 +12,13 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	cartpb "ecommerce-system/api/cart/v1"
+	privacypb "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/datasubject"
 	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/service/cart"
+	cartservice "ecommerce-system/internal/service/cart/service"
 )
 
 var configFile = flag.String("f", "configs/dev/cart-config.yaml", "配置文件路径")
@@ -26,14 +32,17 @@ func main() {
 	svcCtx := cart.NewServiceContext(c)
 	cartSvc := cart.NewCartService(svcCtx)
 
-	// 获取 JWT Secret（从配置中获取，如果没有则使用默认值）
+	// 个人数据接口的权限校验依赖 JWT Secret，未配置时拒绝启动，不能退回到公开的默认值
 	jwtSecret := c.JWT.Secret
 	if jwtSecret == "" {
-		jwtSecret = "default-secret-key" // 开发环境默认值
+		log.Fatal("未配置 JWT.Secret")
 	}
 
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		cartpb.RegisterCartServiceServer(grpcServer, cartSvc)
+		// 个人数据导出/删除，供用户服务编排调用
+		privacypb.RegisterDataSubjectServiceServer(grpcServer,
+			datasubject.NewServer("cart-service", cartservice.NewDataSubjectHandler(svcCtx.CartRepo)))
 
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
@@ -41,7 +50,9 @@ func main() {
 	})
 
 	// 添加认证拦截器
-	s.AddUnaryInterceptors(middleware.AuthInterceptor(jwtSecret))
+	s.AddUnaryInterceptors(middleware.AuthInterceptor(jwtSecret, revocation.NewDenylist(svcCtx.Redis)))
+	// 个人数据接口按 rbac.MethodPermissions 校验服务令牌的权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(jwtSecret, revocation.NewDenylist(svcCtx.Redis), rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("购物车服务启动在 %s\\n", c.ListenOn)
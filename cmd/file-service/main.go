@@ -11,6 +11,8 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	filepb "ecommerce-system/api/file/v1"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/service/file"
 )
 
@@ -34,6 +36,13 @@ func main() {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 解析网关转发的登录令牌：上传时记录上传者，GetFileURL 只为上传者签发私有文件链接
+	var denylist *revocation.Denylist
+	if svcCtx.Redis != nil {
+		denylist = revocation.NewDenylist(svcCtx.Redis)
+	}
+	s.AddUnaryInterceptors(middleware.AuthInterceptor(c.JWT.Secret, denylist))
+	s.AddStreamInterceptors(middleware.AuthStreamInterceptor(c.JWT.Secret, denylist))
 	defer s.Stop()
 
 	fmt.Printf("文件服务启动在 %s\\n", c.ListenOn)
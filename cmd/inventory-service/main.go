@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -11,6 +12,9 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	inventorypb "ecommerce-system/api/inventory/v1"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/service/inventory"
 )
 
@@ -29,6 +33,12 @@ func main() {
 	// 创建库存服务
 	inventorySvc := inventory.NewInventoryService(svcCtx)
 
+	// 管理接口的权限校验依赖 JWT Secret，未配置时拒绝启动，不能退回到公开的默认值
+	jwtSecret := c.JWT.Secret
+	if jwtSecret == "" {
+		log.Fatal("未配置 JWT.Secret")
+	}
+
 	// 创建 gRPC 服务器
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		inventorypb.RegisterInventoryServiceServer(grpcServer, inventorySvc)
@@ -38,6 +48,8 @@ func main() {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 管理接口按 rbac.MethodPermissions 校验权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(jwtSecret, revocation.NewDenylist(svcCtx.Redis), rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("库存服务启动在 %s\\n", c.ListenOn)
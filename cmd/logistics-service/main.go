@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -11,7 +12,12 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	logisticspb "ecommerce-system/api/logistics/v1"
+	privacypb "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
 	"ecommerce-system/internal/service/logistics"
+	logisticsservice "ecommerce-system/internal/service/logistics/service"
 )
 
 var configFile = flag.String("f", "configs/dev/logistics-config.yaml", "配置文件路径")
@@ -25,15 +31,25 @@ func main() {
 	svcCtx := logistics.NewServiceContext(c)
 	logisticsSvc := logistics.NewLogisticsService(svcCtx)
 
+	// 个人数据接口只接受 user-service 签发的服务令牌，未配置 JWT Secret 时拒绝启动
+	if c.JWT.Secret == "" {
+		log.Fatal("未配置 JWT.Secret")
+	}
+
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		// 注册服务
 		logisticspb.RegisterLogisticsServiceServer(grpcServer, logisticsSvc)
+		// 个人数据导出/删除，供用户服务编排调用
+		privacypb.RegisterDataSubjectServiceServer(grpcServer, datasubject.NewServer("logistics-service",
+			logisticsservice.NewDataSubjectHandler(svcCtx.LogisticsRepo, svcCtx.OrderClient)))
 
 		// 开发/测试环境开启 gRPC 反射（用于调试工具如 grpcurl 和 Gateway）
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 个人数据接口按 rbac.MethodPermissions 校验服务令牌的权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(c.JWT.Secret, nil, rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("物流服务启动在 %s\\n", c.ListenOn)
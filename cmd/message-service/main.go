@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -11,7 +12,13 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	messagepb "ecommerce-system/api/message/v1"
+	privacypb "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/service/message"
+	messageservice "ecommerce-system/internal/service/message/service"
 )
 
 var configFile = flag.String("f", "configs/dev/message-config.yaml", "配置文件路径")
@@ -25,15 +32,25 @@ func main() {
 	svcCtx := message.NewServiceContext(c)
 	messageSvc := message.NewMessageService(svcCtx)
 
+	// 个人数据接口只接受 user-service 签发的服务令牌，未配置 JWT Secret 时拒绝启动
+	if c.JWT.Secret == "" {
+		log.Fatal("未配置 JWT.Secret")
+	}
+
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		// 注册服务
 		messagepb.RegisterMessageServiceServer(grpcServer, messageSvc)
+		// 个人数据导出/删除，供用户服务编排调用
+		privacypb.RegisterDataSubjectServiceServer(grpcServer,
+			datasubject.NewServer("message-service", messageservice.NewDataSubjectHandler(svcCtx.MessageRepo)))
 
 		// 开发/测试环境开启 gRPC 反射（用于调试工具如 grpcurl 和 Gateway）
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 个人数据接口按 rbac.MethodPermissions 校验服务令牌的权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(c.JWT.Secret, revocation.NewDenylist(svcCtx.Redis), rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("消息服务启动在 %s\\n", c.ListenOn)
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"ecommerce-system/internal/pkg/oidc/mockoidc"
+)
+
+// mock-oidc 本地模拟 OIDC 提供方，配合 user-service OAuth.Providers 中的 mock 配置联调第三方登录。
+// 授权地址可追加 login_hint=xxx 切换模拟用户。
+var (
+	addr         = flag.String("addr", ":9400", "监听地址")
+	issuer       = flag.String("issuer", "http://localhost:9400", "对外 issuer 地址，需与 user-service 配置一致")
+	clientID     = flag.String("client-id", "go-ecom", "client_id")
+	clientSecret = flag.String("client-secret", "go-ecom-secret", "client_secret")
+)
+
+func main() {
+	flag.Parse()
+
+	srv, err := mockoidc.New(*issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatalf("创建模拟 OIDC 提供方失败: %v", err)
+	}
+	log.Printf("mock OIDC provider listening on %s, issuer=%s", *addr, *issuer)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 	"os"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -12,7 +14,14 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	orderpb "ecommerce-system/api/order/v1"
+	privacypb "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/pkg/idempotency"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/service/order"
+	orderservice "ecommerce-system/internal/service/order/service"
 )
 
 var configFile = flag.String("f", "configs/dev/order-config.yaml", "配置文件路径")
@@ -37,16 +46,32 @@ func main() {
 	// 创建订单服务
 	orderSvc := order.NewOrderService(svcCtx)
 
+	// 管理接口的权限校验依赖 JWT Secret，未配置时拒绝启动，不能退回到公开的默认值
+	jwtSecret := c.JWT.Secret
+	if jwtSecret == "" {
+		log.Fatal("未配置 JWT.Secret")
+	}
+
 	// 创建 gRPC 服务器
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		// 注册服务
 		orderpb.RegisterOrderServiceServer(grpcServer, orderSvc)
+		// 个人数据导出/删除，供用户服务编排调用
+		privacypb.RegisterDataSubjectServiceServer(grpcServer,
+			datasubject.NewServer("order-service", orderservice.NewDataSubjectHandler(svcCtx.OrderRepo, svcCtx.Cache)))
 
 		// 开发/测试环境开启 gRPC 反射（用于调试工具如 grpcurl 和 Gateway）
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 幂等拦截器：CreateOrder 带 idempotency-key 时，重复提交直接回放首次结果
+	s.AddUnaryInterceptors(middleware.IdempotencyInterceptor(
+		idempotency.NewStore(svcCtx.Redis, 24*time.Hour, 30*time.Second), jwtSecret,
+		"/order.v1.OrderService/CreateOrder",
+	))
+	// 管理接口按 rbac.MethodPermissions 校验权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(jwtSecret, revocation.NewDenylist(svcCtx.Redis), rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("订单服务启动在 %s\\n", c.ListenOn)
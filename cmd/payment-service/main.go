@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -11,7 +13,14 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	paymentpb "ecommerce-system/api/payment/v1"
+	privacypb "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/pkg/idempotency"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/service/payment"
+	paymentservice "ecommerce-system/internal/service/payment/service"
 )
 
 var configFile = flag.String("f", "configs/dev/payment-config.yaml", "配置文件路径")
@@ -25,13 +34,28 @@ func main() {
 	svcCtx := payment.NewServiceContext(c)
 	paymentSvc := payment.NewPaymentService(svcCtx)
 
+	// 个人数据接口只接受 user-service 签发的服务令牌，未配置 JWT Secret 时拒绝启动
+	if c.JWT.Secret == "" {
+		log.Fatal("未配置 JWT.Secret")
+	}
+
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		paymentpb.RegisterPaymentServiceServer(grpcServer, paymentSvc)
+		// 个人数据导出/删除，供用户服务编排调用
+		privacypb.RegisterDataSubjectServiceServer(grpcServer,
+			datasubject.NewServer("payment-service", paymentservice.NewDataSubjectHandler(svcCtx.PaymentRepo)))
 
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 幂等拦截器：CreatePayment 带 idempotency-key 时，重复提交直接回放首次结果（按令牌中的用户隔离）
+	s.AddUnaryInterceptors(middleware.IdempotencyInterceptor(
+		idempotency.NewStore(svcCtx.Redis, 24*time.Hour, 30*time.Second), c.JWT.Secret,
+		"/payment.v1.PaymentService/CreatePayment",
+	))
+	// 个人数据接口按 rbac.MethodPermissions 校验服务令牌的权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(c.JWT.Secret, revocation.NewDenylist(svcCtx.Redis), rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("支付服务启动在 %s\\n", c.ListenOn)
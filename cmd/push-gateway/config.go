@@ -0,0 +1,41 @@
+package main
+
+// Config 推送网关配置
+type Config struct {
+	Name     string
+	Host     string `json:",default=0.0.0.0"`
+	Port     int    `json:",default=8096"`
+	JWT      JWTConfig
+	BizRedis RedisConfig
+	Kafka    KafkaConfig
+	Push     PushConfig `json:",optional"`
+}
+
+// JWTConfig JWT配置（与 user-service 保持一致）
+type JWTConfig struct {
+	Secret string
+}
+
+// RedisConfig Redis配置（所有副本必须连接同一个 Redis，用于 pub/sub 路由和断线续传）
+type RedisConfig struct {
+	Host         string
+	Port         int    `json:",default=6379"`
+	Password     string `json:",optional"`
+	Database     int    `json:",optional"`
+	PoolSize     int    `json:",default=10"`
+	MinIdleConns int    `json:",default=5"`
+}
+
+// KafkaConfig Kafka配置
+type KafkaConfig struct {
+	Brokers       []string
+	Version       string `json:",default=2.8.0"`
+	ConsumerGroup string `json:",default=push-gateway"` // 所有副本共用一个消费组，每条事件只处理一次
+}
+
+// PushConfig 推送参数
+type PushConfig struct {
+	StreamMaxLen int64 `json:",default=200"`   // 每个用户保留的最近事件数（断线续传窗口）
+	StreamTTL    int64 `json:",default=86400"` // 用户事件流过期时间（秒）
+	Heartbeat    int64 `json:",default=25"`    // 心跳间隔（秒）
+}
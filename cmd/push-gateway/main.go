@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/conf"
+
+	"ecommerce-system/internal/handler"
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/mq"
+	"ecommerce-system/internal/pkg/push"
+	"ecommerce-system/internal/pkg/revocation"
+)
+
+var configFile = flag.String("f", "configs/dev/push-gateway.yaml", "配置文件路径")
+
+func main() {
+	flag.Parse()
+
+	var c Config
+	conf.MustLoad(*configFile, &c)
+
+	// Redis 是多副本路由和断线续传的基础，连接失败直接退出
+	rdb := cache.MustNewRedis(&cache.Config{
+		Host:         c.BizRedis.Host,
+		Port:         c.BizRedis.Port,
+		Password:     c.BizRedis.Password,
+		Database:     c.BizRedis.Database,
+		PoolSize:     c.BizRedis.PoolSize,
+		MinIdleConns: c.BizRedis.MinIdleConns,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := push.NewHub()
+	broker := push.NewBroker(rdb, hub, push.Config{
+		StreamMaxLen: c.Push.StreamMaxLen,
+		StreamTTL:    time.Duration(c.Push.StreamTTL) * time.Second,
+	})
+	go broker.Run(ctx)
+
+	// 消费业务事件：所有副本共用一个消费组，每条事件只由一个副本写入 Redis 并广播
+	consumer, err := mq.NewConsumer(&mq.Config{
+		Brokers:       c.Kafka.Brokers,
+		Version:       c.Kafka.Version,
+		ConsumerGroup: c.Kafka.ConsumerGroup,
+	})
+	if err != nil {
+		log.Fatalf("创建Kafka消费者失败: %v", err)
+	}
+	defer consumer.Close()
+	push.NewDispatcher(broker).Register(consumer)
+	go func() {
+		if err := consumer.Start(ctx, push.Topics); err != nil {
+			log.Printf("Kafka消费者退出: %v", err)
+		}
+	}()
+
+	// 与各服务共用令牌黑名单，登出或下线所有设备后已建立的连接在下次心跳时关闭
+	pushHandler := handler.NewPushHandler(hub, broker, c.JWT.Secret, revocation.NewDenylist(rdb), time.Duration(c.Push.Heartbeat)*time.Second)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(handler.PushEventsPath, pushHandler.HandleEvents)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "online": hub.Online()})
+	})
+
+	// 长连接不设置 WriteTimeout，连接存活由心跳和客户端断开决定
+	server := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", c.Host, c.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		<-sigChan
+		cancel()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("推送网关启动在 %s:%d，SSE 地址: %s\n", c.Host, c.Port, handler.PushEventsPath)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("推送网关启动失败: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -10,8 +11,14 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	privacypb "ecommerce-system/api/privacy/v1"
 	recommendpb "ecommerce-system/api/recommend/v1"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/service/recommend"
+	recommendservice "ecommerce-system/internal/service/recommend/service"
 )
 
 var configFile = flag.String("f", "configs/dev/recommend-config.yaml", "配置文件路径")
@@ -25,15 +32,25 @@ func main() {
 	svcCtx := recommend.NewServiceContext(c)
 	recommendSvc := recommend.NewRecommendService(svcCtx)
 
+	// 个人数据接口只接受 user-service 签发的服务令牌，未配置 JWT Secret 时拒绝启动
+	if c.JWT.Secret == "" {
+		log.Fatal("未配置 JWT.Secret")
+	}
+
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		// 注册服务
 		recommendpb.RegisterRecommendServiceServer(grpcServer, recommendSvc)
+		// 个人数据导出/删除，供用户服务编排调用
+		privacypb.RegisterDataSubjectServiceServer(grpcServer, datasubject.NewServer("recommend-service",
+			recommendservice.NewDataSubjectHandler(svcCtx.RecommendRepo)))
 
 		// 开发/测试环境开启 gRPC 反射（用于调试工具如 grpcurl 和 Gateway）
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 个人数据接口按 rbac.MethodPermissions 校验服务令牌的权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(c.JWT.Secret, revocation.NewDenylist(svcCtx.Redis), rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("推荐服务启动在 %s\\n", c.ListenOn)
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/service"
@@ -10,8 +11,13 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	privacypb "ecommerce-system/api/privacy/v1"
 	reviewpb "ecommerce-system/api/review/v1"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
 	"ecommerce-system/internal/service/review"
+	reviewservice "ecommerce-system/internal/service/review/service"
 )
 
 var configFile = flag.String("f", "configs/dev/review-config.yaml", "配置文件路径")
@@ -25,15 +31,25 @@ func main() {
 	svcCtx := review.NewServiceContext(c)
 	reviewSvc := review.NewReviewService(svcCtx)
 
+	// 个人数据接口只接受 user-service 签发的服务令牌，未配置 JWT Secret 时拒绝启动
+	if c.JWT.Secret == "" {
+		log.Fatal("未配置 JWT.Secret")
+	}
+
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		// 注册服务
 		reviewpb.RegisterReviewServiceServer(grpcServer, reviewSvc)
+		// 个人数据导出/删除，供用户服务编排调用
+		privacypb.RegisterDataSubjectServiceServer(grpcServer, datasubject.NewServer("review-service",
+			reviewservice.NewDataSubjectHandler(svcCtx.ReviewRepo, svcCtx.ReviewReplyRepo)))
 
 		// 开发/测试环境开启 gRPC 反射（用于调试工具如 grpcurl 和 Gateway）
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
 		}
 	})
+	// 个人数据接口按 rbac.MethodPermissions 校验服务令牌的权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(c.JWT.Secret, nil, rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("评价服务启动在 %s\\n", c.ListenOn)
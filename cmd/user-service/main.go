@@ -12,6 +12,7 @@ import (
 
 	userpb "ecommerce-system/api/user/v1"
 	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
 	"ecommerce-system/internal/service/user"
 )
 
@@ -29,6 +30,8 @@ func main() {
 
 	// 创建用户服务
 	userSvc := user.NewUserService(svcCtx)
+	apiKeySvc := user.NewApiKeyService(svcCtx)
+	roleSvc := user.NewRoleService(svcCtx)
 
 	// 获取 JWT Secret（从配置中获取，如果没有则使用默认值）
 	jwtSecret := c.JWT.Secret
@@ -40,14 +43,22 @@ func main() {
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		// 注册服务
 		userpb.RegisterUserServiceServer(grpcServer, userSvc)
+		userpb.RegisterApiKeyServiceServer(grpcServer, apiKeySvc)
+		userpb.RegisterRoleServiceServer(grpcServer, roleSvc)
 
 		// 开发/测试环境开启 gRPC 反射（用于调试工具如 grpcurl 和 Gateway）
 		if c.Mode == service.DevMode || c.Mode == service.TestMode {
 			reflection.Register(grpcServer)
 		}
 	})
-	// 添加认证拦截器：从 metadata.authorization 解析 JWT，把 user_id 写进 ctx
-	s.AddUnaryInterceptors(middleware.AuthInterceptor(jwtSecret))
+	// 客户端 IP（验证码按 IP 限流）
+	s.AddUnaryInterceptors(middleware.ClientIPInterceptor())
+	// 客户端 User-Agent 和设备标识（登录会话记录、新设备登录提醒）
+	s.AddUnaryInterceptors(middleware.DeviceInterceptor())
+	// 添加认证拦截器：从 metadata.authorization 解析 JWT，把 user_id 写进 ctx（已吊销的令牌视为未登录）
+	s.AddUnaryInterceptors(middleware.AuthInterceptor(jwtSecret, svcCtx.Denylist))
+	// 管理接口按 rbac.MethodPermissions 校验权限
+	s.AddUnaryInterceptors(middleware.RequirePermissionInterceptor(jwtSecret, svcCtx.Denylist, rbac.MethodPermissions))
 	defer s.Stop()
 
 	fmt.Printf("用户服务启动在 %s\\n", c.ListenOn)
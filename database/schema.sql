@@ -33,6 +33,9 @@ CREATE TABLE IF NOT EXISTS `address` (
     `district` VARCHAR(50) NOT NULL COMMENT '区县',
     `detail` VARCHAR(200) NOT NULL COMMENT '详细地址',
     `postal_code` VARCHAR(10) DEFAULT NULL COMMENT '邮编',
+    `province_code` VARCHAR(6) DEFAULT NULL COMMENT '省级行政区划代码',
+    `city_code` VARCHAR(6) DEFAULT NULL COMMENT '地级行政区划代码，港澳台为空',
+    `district_code` VARCHAR(6) DEFAULT NULL COMMENT '县级行政区划代码，不设区县的地级市为空',
     `is_default` TINYINT DEFAULT 0 COMMENT '是否默认: 0-否, 1-是',
     `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
@@ -46,7 +49,7 @@ CREATE TABLE IF NOT EXISTS `address` (
 CREATE TABLE IF NOT EXISTS `credential` (
     `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '凭证ID',
     `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
-    `credential_type` TINYINT NOT NULL COMMENT '凭证类型: 1-密码, 2-微信, 3-支付宝, 4-QQ',
+    `credential_type` TINYINT NOT NULL COMMENT '凭证类型: 1-密码, 2-微信, 3-支付宝, 4-QQ, 5-OIDC第三方登录, 6-TOTP两步验证',
     `credential_key` VARCHAR(100) NOT NULL COMMENT '凭证标识（手机号/邮箱/第三方openid）',
     `credential_value` VARCHAR(255) DEFAULT NULL COMMENT '凭证值（加密后的密码）',
     `extra` JSON DEFAULT NULL COMMENT '扩展信息（第三方用户信息等）',
@@ -57,6 +60,220 @@ CREATE TABLE IF NOT EXISTS `credential` (
     KEY `idx_credential_key` (`credential_key`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户凭证表';
 
+-- 角色表
+CREATE TABLE IF NOT EXISTS `role` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '角色ID',
+    `code` VARCHAR(50) NOT NULL COMMENT '角色编码（如 super_admin、operator）',
+    `name` VARCHAR(50) NOT NULL COMMENT '角色名称',
+    `description` VARCHAR(255) DEFAULT NULL COMMENT '描述',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='角色表';
+
+-- 权限点表（权限点定义在 internal/pkg/rbac，由 bootstrap-admin 同步）
+CREATE TABLE IF NOT EXISTS `permission` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '权限ID',
+    `code` VARCHAR(50) NOT NULL COMMENT '权限编码（如 product:write，* 表示全部）',
+    `name` VARCHAR(100) NOT NULL COMMENT '权限名称',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='权限点表';
+
+-- 角色权限关联表
+CREATE TABLE IF NOT EXISTS `role_permission` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `role_id` BIGINT UNSIGNED NOT NULL COMMENT '角色ID',
+    `permission` VARCHAR(50) NOT NULL COMMENT '权限编码',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_role_permission` (`role_id`, `permission`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='角色权限关联表';
+
+-- 用户角色关联表
+CREATE TABLE IF NOT EXISTS `user_role` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `role_id` BIGINT UNSIGNED NOT NULL COMMENT '角色ID',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '分配时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_user_role` (`user_id`, `role_id`),
+    KEY `idx_role_id` (`role_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户角色关联表';
+
+-- 刷新令牌表（只保存哈希，同一次登录轮换出的令牌属于同一 family）
+CREATE TABLE IF NOT EXISTS `refresh_token` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `family_id` VARCHAR(36) NOT NULL COMMENT '令牌族ID，检测到重放时整族吊销',
+    `token_hash` CHAR(64) NOT NULL COMMENT '刷新令牌 SHA-256',
+    `access_jti` VARCHAR(36) NOT NULL COMMENT '同时签发的访问令牌 jti',
+    `access_expires_at` DATETIME NOT NULL COMMENT '访问令牌过期时间',
+    `expires_at` DATETIME NOT NULL COMMENT '刷新令牌过期时间',
+    `used_at` DATETIME DEFAULT NULL COMMENT '已轮换时间，再次使用视为重放',
+    `revoked_at` DATETIME DEFAULT NULL COMMENT '吊销时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_token_hash` (`token_hash`),
+    KEY `idx_family_id` (`family_id`),
+    KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='刷新令牌表';
+
+-- 登录会话表（一次登录一条，对应一个刷新令牌族）
+CREATE TABLE IF NOT EXISTS `user_session` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '会话ID',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `family_id` VARCHAR(36) NOT NULL COMMENT '刷新令牌族ID',
+    `device_fingerprint` CHAR(64) NOT NULL DEFAULT '' COMMENT '设备指纹（设备标识或 User-Agent 的 SHA-256），用于识别新设备',
+    `device_name` VARCHAR(64) DEFAULT NULL COMMENT '设备名称，由 User-Agent 解析',
+    `user_agent` VARCHAR(512) DEFAULT NULL COMMENT 'User-Agent',
+    `ip` VARCHAR(64) DEFAULT NULL COMMENT '最近一次使用的IP',
+    `location` VARCHAR(128) DEFAULT NULL COMMENT 'IP 所在地（本地地址库解析）',
+    `login_ip` VARCHAR(64) DEFAULT NULL COMMENT '登录IP',
+    `access_jti` VARCHAR(36) DEFAULT NULL COMMENT '最近签发的访问令牌 jti，用于标记当前会话',
+    `last_seen_at` DATETIME NOT NULL COMMENT '最近活跃时间（登录或刷新令牌）',
+    `expires_at` DATETIME NOT NULL COMMENT '刷新令牌过期时间',
+    `revoked_at` DATETIME DEFAULT NULL COMMENT '下线时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '登录时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_family_id` (`family_id`),
+    KEY `idx_user_last_seen` (`user_id`, `last_seen_at`),
+    KEY `idx_user_device` (`user_id`, `device_fingerprint`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='登录会话表';
+
+-- 账号安全审计日志表（锁定、解锁、重置密码等）
+CREATE TABLE IF NOT EXISTS `user_security_log` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '日志ID',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `event` VARCHAR(32) NOT NULL COMMENT '事件: account_locked/account_unlocked/password_reset/password_changed/data_export/erasure_request/account_erased/new_device_login/session_revoked',
+    `operator_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '操作人ID，0 表示系统',
+    `client_ip` VARCHAR(64) DEFAULT NULL COMMENT '客户端IP',
+    `detail` VARCHAR(255) DEFAULT NULL COMMENT '说明',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_user_created` (`user_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='账号安全审计日志表';
+
+-- 会员消费记录表（订单完成时写入，等级按滚动窗口内的记录统计）
+CREATE TABLE IF NOT EXISTS `member_spend` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `order_no` VARCHAR(64) NOT NULL COMMENT '订单号',
+    `amount` DECIMAL(10,2) NOT NULL COMMENT '实付金额',
+    `refund_amount` DECIMAL(10,2) NOT NULL DEFAULT 0.00 COMMENT '退款金额',
+    `completed_at` DATETIME NOT NULL COMMENT '订单完成时间',
+    `refunded_at` DATETIME DEFAULT NULL COMMENT '退款时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_order_no` (`order_no`),
+    KEY `idx_user_completed` (`user_id`, `completed_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='会员消费记录表';
+
+-- 会员等级变更历史表
+CREATE TABLE IF NOT EXISTS `member_level_log` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `from_level` TINYINT NOT NULL COMMENT '变更前等级',
+    `to_level` TINYINT NOT NULL COMMENT '变更后等级',
+    `reason` VARCHAR(32) NOT NULL COMMENT '原因: order_completed/refunded/expired',
+    `gmv` DECIMAL(12,2) NOT NULL COMMENT '评定时统计窗口内实付金额',
+    `order_count` INT NOT NULL COMMENT '评定时统计窗口内订单数',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_user_created` (`user_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='会员等级变更历史表';
+
+-- 个人数据请求表（数据导出、账号注销），记录保留作为审计依据
+CREATE TABLE IF NOT EXISTS `user_data_request` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `request_no` VARCHAR(32) NOT NULL COMMENT '请求编号',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `type` VARCHAR(16) NOT NULL COMMENT '类型: export-数据导出 erase-注销删除',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态: 0-待处理 1-处理中 2-已完成 3-失败',
+    `progress` INT NOT NULL DEFAULT 0 COMMENT '进度百分比',
+    `file_id` VARCHAR(64) DEFAULT NULL COMMENT '导出包文件ID',
+    `error` VARCHAR(500) DEFAULT NULL COMMENT '失败原因',
+    `operator_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '发起人ID，管理员删除或重试时为管理员',
+    `started_at` DATETIME DEFAULT NULL COMMENT '开始处理时间',
+    `finished_at` DATETIME DEFAULT NULL COMMENT '处理结束时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_request_no` (`request_no`),
+    KEY `idx_user_type` (`user_id`, `type`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='个人数据请求表';
+
+-- 个人数据请求任务表（每个服务一条）
+CREATE TABLE IF NOT EXISTS `user_data_request_task` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `request_id` BIGINT UNSIGNED NOT NULL COMMENT '请求ID',
+    `service` VARCHAR(64) NOT NULL COMMENT '服务名，user-service 为本地数据',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态: 0-待处理 1-处理中 2-已完成 3-失败',
+    `summary` VARCHAR(1000) DEFAULT NULL COMMENT '处理结果摘要',
+    `error` VARCHAR(500) DEFAULT NULL COMMENT '失败原因',
+    `finished_at` DATETIME DEFAULT NULL COMMENT '完成时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_request_service` (`request_id`, `service`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='个人数据请求任务表';
+
+-- 开放平台 API Key 表
+CREATE TABLE IF NOT EXISTS `api_key` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `key_id` VARCHAR(40) NOT NULL COMMENT 'Key ID（ak_ 开头）',
+    `owner_user_id` BIGINT UNSIGNED NOT NULL COMMENT '所属用户ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '名称（如 ERP、某平台）',
+    `secret_cipher` VARCHAR(255) NOT NULL COMMENT '当前 secret（AES-GCM 加密）',
+    `prev_secret_cipher` VARCHAR(255) DEFAULT NULL COMMENT '轮换前的 secret（宽限期内仍可验签）',
+    `prev_secret_expires_at` DATETIME DEFAULT NULL COMMENT '旧 secret 失效时间',
+    `scopes` VARCHAR(500) DEFAULT NULL COMMENT '权限范围，逗号分隔',
+    `rate_limit` INT NOT NULL DEFAULT 0 COMMENT '每分钟最大请求数，0 表示使用网关默认值',
+    `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态: 1-启用, 2-已吊销',
+    `expires_at` DATETIME DEFAULT NULL COMMENT '过期时间，NULL 表示永不过期',
+    `last_used_at` DATETIME DEFAULT NULL COMMENT '最近调用时间',
+    `rotated_at` DATETIME DEFAULT NULL COMMENT '最近轮换时间',
+    `revoked_at` DATETIME DEFAULT NULL COMMENT '吊销时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_key_id` (`key_id`),
+    KEY `idx_owner_user_id` (`owner_user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='开放平台API Key表';
+
+-- API Key 调用审计日志表
+CREATE TABLE IF NOT EXISTS `api_key_audit_log` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '日志ID',
+    `key_id` VARCHAR(40) NOT NULL COMMENT 'Key ID',
+    `owner_user_id` BIGINT UNSIGNED NOT NULL COMMENT '所属用户ID',
+    `method` VARCHAR(10) DEFAULT NULL COMMENT 'HTTP 方法',
+    `path` VARCHAR(255) DEFAULT NULL COMMENT '请求路径',
+    `status_code` INT DEFAULT NULL COMMENT '响应状态码',
+    `result` VARCHAR(32) DEFAULT NULL COMMENT '结果: ok/invalid_signature/replayed_nonce/forbidden_scope/rate_limited 等',
+    `client_ip` VARCHAR(64) DEFAULT NULL COMMENT '客户端IP',
+    `latency_ms` BIGINT DEFAULT NULL COMMENT '耗时（毫秒）',
+    `created_at` DATETIME(3) NOT NULL COMMENT '调用时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_key_created` (`key_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API Key调用审计日志表';
+
+-- API Key 按天用量表
+CREATE TABLE IF NOT EXISTS `api_key_usage` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `key_id` VARCHAR(40) NOT NULL COMMENT 'Key ID',
+    `day` CHAR(10) NOT NULL COMMENT '日期 yyyy-MM-dd',
+    `calls` BIGINT NOT NULL DEFAULT 0 COMMENT '调用次数',
+    `errors` BIGINT NOT NULL DEFAULT 0 COMMENT '失败次数（状态码 >= 400）',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_key_day` (`key_id`, `day`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API Key按天用量表';
+
 -- ============================================
 -- 二、商品域服务 (product-service)
 -- ============================================
@@ -94,8 +311,11 @@ CREATE TABLE IF NOT EXISTS `product` (
     `original_price` DECIMAL(10, 2) DEFAULT NULL COMMENT '原价',
     `stock` INT DEFAULT 0 COMMENT '总库存（所有SKU库存之和）',
     `sales` INT DEFAULT 0 COMMENT '销量',
-    `status` TINYINT DEFAULT 1 COMMENT '状态: 0-下架, 1-上架, 2-待审核',
+    `status` TINYINT DEFAULT 1 COMMENT '状态: 0-下架, 1-上架, 2-待审核（从未通过审核）',
+    `audit_status` TINYINT DEFAULT 2 COMMENT '最近一次修订的审核状态: 0-草稿, 1-待审核, 2-已通过, 3-已驳回',
+    `audit_comment` VARCHAR(500) DEFAULT NULL COMMENT '最近一次审核意见',
     `sort` INT DEFAULT 0 COMMENT '排序值',
+    `attrs` JSON DEFAULT NULL COMMENT '规格参数和基础属性（按类目属性模板校验，多选值以逗号分隔）',
     `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     `deleted_at` DATETIME DEFAULT NULL COMMENT '删除时间',
@@ -135,7 +355,7 @@ CREATE TABLE IF NOT EXISTS `attr` (
     `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '属性ID',
     `category_id` BIGINT UNSIGNED NOT NULL COMMENT '类目ID',
     `name` VARCHAR(50) NOT NULL COMMENT '属性名称',
-    `type` TINYINT NOT NULL COMMENT '属性类型: 1-规格属性, 2-销售属性, 3-基础属性',
+    `type` TINYINT NOT NULL COMMENT '属性类型: 1-规格属性, 2-销售属性（SKU规格，只能单选）, 3-基础属性',
     `input_type` TINYINT NOT NULL COMMENT '输入类型: 1-单选, 2-多选, 3-文本输入',
     `values` JSON DEFAULT NULL COMMENT '属性可选值列表',
     `sort` INT DEFAULT 0 COMMENT '排序值',
@@ -145,7 +365,84 @@ CREATE TABLE IF NOT EXISTS `attr` (
     PRIMARY KEY (`id`),
     KEY `idx_category_id` (`category_id`),
     KEY `idx_type` (`type`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品属性表';
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='类目属性模板表（子类目继承父类目属性，同名时子类目覆盖）';
+
+-- 商品修订表
+CREATE TABLE IF NOT EXISTS `product_revision` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '修订ID',
+    `product_id` BIGINT UNSIGNED NOT NULL COMMENT '商品ID',
+    `content` JSON NOT NULL COMMENT '修订后的完整商品内容（名称、图片、价格、属性等）',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态: 0-草稿, 1-待审核, 2-已通过, 3-已驳回',
+    `created_by` BIGINT UNSIGNED DEFAULT 0 COMMENT '创建人',
+    `reviewed_by` BIGINT UNSIGNED DEFAULT 0 COMMENT '审核人',
+    `review_comment` VARCHAR(500) DEFAULT NULL COMMENT '审核意见',
+    `submitted_at` DATETIME DEFAULT NULL COMMENT '提交审核时间',
+    `reviewed_at` DATETIME DEFAULT NULL COMMENT '审核时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_product_id` (`product_id`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品修订表（内容修改审核通过后才应用到商品）';
+
+-- 商品审核日志表
+CREATE TABLE IF NOT EXISTS `product_audit_log` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '日志ID',
+    `product_id` BIGINT UNSIGNED NOT NULL COMMENT '商品ID',
+    `revision_id` BIGINT UNSIGNED NOT NULL COMMENT '修订ID',
+    `action` VARCHAR(20) NOT NULL COMMENT '动作: create-创建, edit-修改, submit-提交审核, approve-通过, reject-驳回',
+    `operator_id` BIGINT UNSIGNED DEFAULT 0 COMMENT '操作人',
+    `comment` VARCHAR(500) DEFAULT NULL COMMENT '审核意见',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_product_id` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品审核日志表';
+
+-- 商品定时任务表
+CREATE TABLE IF NOT EXISTS `product_schedule` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '任务ID',
+    `target_type` VARCHAR(20) NOT NULL COMMENT '目标类型: product-商品, sku-SKU',
+    `target_id` BIGINT UNSIGNED NOT NULL COMMENT '目标ID',
+    `product_id` BIGINT UNSIGNED NOT NULL COMMENT '商品ID（目标为SKU时是其所属商品）',
+    `action` VARCHAR(20) NOT NULL COMMENT '动作: list-上架, delist-下架, price-改价',
+    `payload` JSON DEFAULT NULL COMMENT '动作参数，改价时为 {"price":99,"original_price":129}',
+    `run_at` DATETIME NOT NULL COMMENT '执行时间',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态: 0-待执行, 1-已执行, 2-已取消, 3-执行失败',
+    `error` VARCHAR(500) DEFAULT NULL COMMENT '执行失败原因',
+    `created_by` BIGINT UNSIGNED DEFAULT 0 COMMENT '创建人',
+    `executed_at` DATETIME DEFAULT NULL COMMENT '执行时间（实际）',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_product_id` (`product_id`),
+    KEY `idx_status_run_at` (`status`, `run_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品定时任务表（定时上下架和改价，每个任务只执行一次）';
+
+-- 商品批量导入导出任务表
+CREATE TABLE IF NOT EXISTS `catalog_job` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '任务ID',
+    `kind` VARCHAR(20) NOT NULL COMMENT '类型: import-导入, export-导出',
+    `format` VARCHAR(10) NOT NULL COMMENT '文件格式: csv, xlsx',
+    `file_id` VARCHAR(64) DEFAULT NULL COMMENT '导入的源文件ID',
+    `dry_run` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否只校验不写入',
+    `params` JSON DEFAULT NULL COMMENT '任务参数（导入是否提交审核、导出筛选条件）',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态: 0-待处理, 1-处理中, 2-已完成, 3-处理失败',
+    `total_rows` INT NOT NULL DEFAULT 0 COMMENT '总数（导入为数据行数，导出为商品数）',
+    `processed_rows` INT NOT NULL DEFAULT 0 COMMENT '已处理数',
+    `success_rows` INT NOT NULL DEFAULT 0 COMMENT '成功行数',
+    `failed_rows` INT NOT NULL DEFAULT 0 COMMENT '失败行数',
+    `errors` JSON DEFAULT NULL COMMENT '逐行错误（最多保留前1000条）',
+    `result_file_id` VARCHAR(64) DEFAULT NULL COMMENT '导出文件或导入错误报告的文件ID',
+    `message` VARCHAR(500) DEFAULT NULL COMMENT '整体失败原因',
+    `created_by` BIGINT UNSIGNED DEFAULT 0 COMMENT '创建人',
+    `started_at` DATETIME DEFAULT NULL COMMENT '开始处理时间',
+    `finished_at` DATETIME DEFAULT NULL COMMENT '完成时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_kind` (`kind`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='商品批量导入导出任务表';
 
 -- ============================================
 -- 三、库存服务 (inventory-service)
@@ -203,6 +500,9 @@ CREATE TABLE IF NOT EXISTS `orders` (
     `receiver_name` VARCHAR(50) NOT NULL COMMENT '收货人姓名',
     `receiver_phone` VARCHAR(20) NOT NULL COMMENT '收货人电话',
     `receiver_address` VARCHAR(500) NOT NULL COMMENT '收货地址',
+    `receiver_province_code` VARCHAR(6) DEFAULT NULL COMMENT '收货地址省级行政区划代码',
+    `receiver_city_code` VARCHAR(6) DEFAULT NULL COMMENT '收货地址地级行政区划代码',
+    `receiver_district_code` VARCHAR(6) DEFAULT NULL COMMENT '收货地址县级行政区划代码',
     `payment_method` TINYINT DEFAULT NULL COMMENT '支付方式: 1-微信, 2-支付宝, 3-银联',
     `payment_time` DATETIME DEFAULT NULL COMMENT '支付时间',
     `delivery_time` DATETIME DEFAULT NULL COMMENT '发货时间',
@@ -217,7 +517,8 @@ CREATE TABLE IF NOT EXISTS `orders` (
     KEY `idx_user_id` (`user_id`),
     KEY `idx_status` (`status`),
     KEY `idx_created_at` (`created_at`),
-    KEY `idx_payment_time` (`payment_time`)
+    KEY `idx_payment_time` (`payment_time`),
+    KEY `idx_receiver_region` (`receiver_province_code`, `receiver_city_code`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='订单表';
 
 -- 订单商品项表
@@ -1,4 +1,5 @@
 import { apiClient } from "@/api/client";
+import * as gen from "@/api/generated";
 
 export interface ApiResponse<T> {
   code: number;
@@ -23,6 +24,7 @@ function normalizeProduct(input: Record<string, unknown>) {
     subtitle: pickString(input.subtitle),
     category_id: pickNumber(input.category_id ?? input.categoryId),
     brand_id: pickNumber(input.brand_id ?? input.brandId),
+    brand_name: pickString(input.brand_name ?? input.brandName),
     main_image: pickString(input.main_image ?? input.mainImage),
     local_main_image: pickString(input.local_main_image ?? input.localMainImage),
     detail: pickString(input.detail),
@@ -32,6 +34,24 @@ function normalizeProduct(input: Record<string, unknown>) {
     sales: pickNumber(input.sales),
     status: pickNumber(input.status),
     is_hot: pickNumber(input.is_hot ?? input.isHot),
+    attrs: (input.attrs ?? {}) as Record<string, string>,
+    audit_status: pickNumber(input.audit_status ?? input.auditStatus, 2),
+    audit_comment: pickString(input.audit_comment ?? input.auditComment),
+  };
+}
+
+function normalizeRevision(input: Record<string, unknown>) {
+  return {
+    id: pickNumber(input.id),
+    product_id: pickNumber(input.product_id ?? input.productId),
+    content: normalizeProduct((input.content ?? {}) as Record<string, unknown>),
+    status: pickNumber(input.status),
+    created_by: pickNumber(input.created_by ?? input.createdBy),
+    reviewed_by: pickNumber(input.reviewed_by ?? input.reviewedBy),
+    review_comment: pickString(input.review_comment ?? input.reviewComment),
+    submitted_at: pickString(input.submitted_at ?? input.submittedAt),
+    reviewed_at: pickString(input.reviewed_at ?? input.reviewedAt),
+    created_at: pickString(input.created_at ?? input.createdAt),
   };
 }
 
@@ -59,6 +79,7 @@ function normalizeSku(input: Record<string, unknown>) {
     stock: pickNumber(input.stock),
     image: pickString(input.image),
     status: pickNumber(input.status),
+    specs: (input.specs ?? {}) as Record<string, string>,
   };
 }
 
@@ -97,6 +118,31 @@ function normalizeBanner(input: Record<string, unknown>) {
   };
 }
 
+function normalizeBrand(input: Record<string, unknown>) {
+  return {
+    id: pickNumber(input.id),
+    name: pickString(input.name),
+    logo: pickString(input.logo),
+    description: pickString(input.description),
+    sort: pickNumber(input.sort),
+    status: pickNumber(input.status),
+  };
+}
+
+function normalizeAttr(input: Record<string, unknown>) {
+  return {
+    id: pickNumber(input.id),
+    category_id: pickNumber(input.category_id ?? input.categoryId),
+    name: pickString(input.name),
+    type: pickNumber(input.type),
+    input_type: pickNumber(input.input_type ?? input.inputType),
+    values: Array.isArray(input.values) ? input.values.map((item) => String(item)) : [],
+    sort: pickNumber(input.sort),
+    is_required: pickNumber(input.is_required ?? input.isRequired),
+    inherited: Boolean(input.inherited),
+  };
+}
+
 function normalizeOrder(input: Record<string, unknown>) {
   return {
     id: pickNumber(input.id),
@@ -131,51 +177,93 @@ export async function adminLogin(values: {
   password: string;
   login_type: number;
 }) {
-  const response = await apiClient.post<
-    ApiResponse<{
-      token: string;
-      user: { id: number; username: string; nickname?: string; member_level?: number };
-    }>
-  >("/api/v1/user/login", values);
-  const payload = response.data;
+  const payload = await gen.login({
+    username: values.username,
+    password: values.password,
+    loginType: values.login_type,
+  });
   return {
-    ...payload,
+    code: payload.code ?? 0,
+    message: payload.message ?? "",
     data: {
-      ...payload.data,
-      user: normalizeUser(payload.data.user as unknown as Record<string, unknown>),
+      token: payload.data?.token ?? "",
+      refresh_token: payload.data?.refreshToken ?? "",
+      user: normalizeUser((payload.data?.user ?? {}) as unknown as Record<string, unknown>),
+      mfa_required: payload.data?.mfaRequired ?? false,
+      mfa_token: payload.data?.mfaToken ?? "",
+      mfa_enroll_required: payload.data?.mfaEnrollRequired ?? false,
     },
   };
 }
 
+export async function adminSetupTOTP(mfaToken: string) {
+  const payload = await gen.setupTOTP({ mfaToken });
+  return {
+    secret: payload.data?.secret ?? "",
+    provisioning_uri: payload.data?.provisioningUri ?? "",
+  };
+}
+
+export async function adminVerifyMFA(mfaToken: string, code: string) {
+  const payload = await gen.verifyMFA({ mfaToken, code });
+  return {
+    token: payload.data?.token ?? "",
+    refresh_token: payload.data?.refreshToken ?? "",
+    user: normalizeUser((payload.data?.user ?? {}) as unknown as Record<string, unknown>),
+    recovery_codes: payload.recoveryCodes ?? [],
+  };
+}
+
+export async function adminLogout(refreshToken: string) {
+  return gen.logout({ refreshToken });
+}
+
 export async function listUsers(params: Record<string, string | number | undefined>) {
-  const response = await apiClient.get<
-    ApiResponse<{
-      users: Array<{
-        id: number;
-        username: string;
-        nickname?: string;
-        phone?: string;
-        email?: string;
-        status: number;
-        member_level?: number;
-        created_at?: string;
-      }>;
-      total: number;
-      page: number;
-      page_size: number;
-    }>
-  >("/api/v1/users", { params });
-  const payload = response.data;
+  const payload = await gen.listUsers({
+    page: pickNumber(params.page, 1),
+    pageSize: pickNumber(params.page_size, 10),
+    keyword: pickString(params.keyword),
+    status: pickNumber(params.status),
+  });
   return {
-    ...payload,
+    code: payload.code ?? 0,
+    message: payload.message ?? "",
     data: {
-      ...payload.data,
-      users: (payload.data?.users || []).map((item) => normalizeUser(item as unknown as Record<string, unknown>)),
-      page_size: pickNumber((payload.data as Record<string, unknown>)?.page_size ?? (payload.data as Record<string, unknown>)?.pageSize),
+      users: (payload.data?.users ?? []).map((item) => normalizeUser(item as unknown as Record<string, unknown>)),
+      total: payload.data?.total ?? 0,
+      page: payload.data?.page ?? 1,
+      page_size: payload.data?.pageSize ?? 0,
     },
   };
 }
 
+function normalizeSession(input: Record<string, unknown>) {
+  return {
+    id: pickNumber(input.id),
+    device_name: pickString(input.device_name ?? input.deviceName),
+    user_agent: pickString(input.user_agent ?? input.userAgent),
+    ip: pickString(input.ip),
+    location: pickString(input.location),
+    login_ip: pickString(input.login_ip ?? input.loginIp),
+    created_at: pickString(input.created_at ?? input.createdAt),
+    last_seen_at: pickString(input.last_seen_at ?? input.lastSeenAt),
+    revoked_at: pickString(input.revoked_at ?? input.revokedAt),
+    active: Boolean(input.active),
+  };
+}
+
+export async function listUserSessions(userId: number, includeInactive: boolean, page = 1, pageSize = 20) {
+  const payload = await gen.listUserSessions({ userId, includeInactive, page, pageSize });
+  return {
+    items: (payload.data ?? []).map((item) => normalizeSession(item as unknown as Record<string, unknown>)),
+    total: pickNumber(payload.total),
+  };
+}
+
+export async function revokeUserSession(userId: number, sessionId: number) {
+  return gen.revokeUserSession({ userId, sessionId });
+}
+
 export async function listProducts(params: Record<string, string | number | undefined>) {
   const response = await apiClient.get<
     ApiResponse<{
@@ -219,6 +307,135 @@ export async function deleteProduct(id: number) {
   return response.data;
 }
 
+// status: -1-全部, 0-草稿, 1-待审核, 2-已通过, 3-已驳回
+export async function listProductRevisions(params: { productId?: number; status?: number; page?: number; pageSize?: number }) {
+  const payload = await gen.listProductRevisions({
+    productId: params.productId ?? 0,
+    status: params.status ?? -1,
+    page: params.page ?? 1,
+    pageSize: params.pageSize ?? 20,
+  });
+  return {
+    items: (payload.data ?? []).map((item) => normalizeRevision(item as unknown as Record<string, unknown>)),
+    total: pickNumber(payload.total),
+  };
+}
+
+export async function submitProductRevision(productId: number) {
+  return gen.submitProductRevision({ productId });
+}
+
+// 驳回时 comment 必填
+export async function reviewProductRevision(payload: { id: number; approve: boolean; comment: string }) {
+  const { approve, ...body } = payload;
+  return approve ? gen.approveProductRevision(body) : gen.rejectProductRevision(body);
+}
+
+export async function listProductAuditLogs(productId: number) {
+  const payload = await gen.listProductAuditLogs({ productId, page: 1, pageSize: 50 });
+  return (payload.data ?? []).map((item) => ({
+    id: pickNumber(item.id),
+    revision_id: pickNumber(item.revisionId),
+    action: pickString(item.action),
+    operator_id: pickNumber(item.operatorId),
+    comment: pickString(item.comment),
+    created_at: pickString(item.createdAt),
+  }));
+}
+
+// status: -1-全部, 0-待执行, 1-已执行, 2-已取消, 3-执行失败
+export async function listProductSchedules(params: { productId?: number; status?: number; page?: number; pageSize?: number }) {
+  const payload = await gen.listProductSchedules({
+    productId: params.productId ?? 0,
+    status: params.status ?? -1,
+    page: params.page ?? 1,
+    pageSize: params.pageSize ?? 20,
+  });
+  return {
+    items: (payload.data ?? []).map((item) => ({
+      id: pickNumber(item.id),
+      target_type: pickString(item.targetType),
+      target_id: pickNumber(item.targetId),
+      product_id: pickNumber(item.productId),
+      action: pickString(item.action),
+      price: pickNumber(item.price),
+      original_price: pickNumber(item.originalPrice),
+      run_at: pickString(item.runAt),
+      status: pickNumber(item.status),
+      error: pickString(item.error),
+      executed_at: pickString(item.executedAt),
+    })),
+    total: pickNumber(payload.total),
+  };
+}
+
+// runAt 为本地时间，转换为 RFC3339 提交
+export async function createProductSchedule(payload: {
+  targetType: string;
+  targetId: number;
+  action: string;
+  price?: number;
+  originalPrice?: number;
+  runAt: string;
+}) {
+  return gen.createProductSchedule({ ...payload, runAt: new Date(payload.runAt).toISOString() });
+}
+
+export async function cancelProductSchedule(id: number) {
+  return gen.cancelProductSchedule({ id });
+}
+
+function normalizeCatalogJob(item: gen.CatalogJob = {}) {
+  return {
+    id: pickNumber(item.id),
+    kind: pickString(item.kind),
+    format: pickString(item.format),
+    dry_run: Boolean(item.dryRun),
+    status: pickNumber(item.status),
+    total_rows: pickNumber(item.totalRows),
+    processed_rows: pickNumber(item.processedRows),
+    success_rows: pickNumber(item.successRows),
+    failed_rows: pickNumber(item.failedRows),
+    errors: (item.errors ?? []).map((e) => ({
+      row: pickNumber(e.row),
+      spu_code: pickString(e.spuCode),
+      sku_code: pickString(e.skuCode),
+      message: pickString(e.message),
+    })),
+    result_url: pickString(item.resultUrl),
+    message: pickString(item.message),
+    created_at: pickString(item.createdAt),
+    finished_at: pickString(item.finishedAt),
+  };
+}
+
+export async function listCatalogJobs(params: { kind?: string; page?: number; pageSize?: number }) {
+  const payload = await gen.listCatalogJobs({
+    kind: params.kind ?? "",
+    page: params.page ?? 1,
+    pageSize: params.pageSize ?? 20,
+  });
+  return {
+    items: (payload.data ?? []).map(normalizeCatalogJob),
+    total: pickNumber(payload.total),
+  };
+}
+
+export async function getCatalogJob(id: number) {
+  const payload = await gen.getCatalogJob({ id });
+  return normalizeCatalogJob(payload.data);
+}
+
+export async function importCatalog(payload: { fileId: string; dryRun: boolean; submit: boolean }) {
+  const response = await gen.importCatalog(payload);
+  return normalizeCatalogJob(response.data);
+}
+
+export async function exportCatalog(payload: { format: string; categoryId?: number; status?: number; keyword?: string }) {
+  const response = await gen.exportCatalog({ ...payload, status: payload.status ?? -1 });
+  return normalizeCatalogJob(response.data);
+}
+
 export async function listSkus(params: Record<string, string | number | undefined>) {
   const response = await apiClient.get<
     ApiResponse<{ list: Array<Record<string, unknown>>; total: number; page: number; total_pages: number }>
@@ -312,6 +529,77 @@ export async function deleteBanner(id: number) {
   return response.data;
 }
 
+export async function listBrands(params: { status?: number; keyword?: string; page?: number; pageSize?: number } = {}) {
+  const payload = await gen.listBrands({
+    status: params.status ?? -1,
+    keyword: params.keyword,
+    page: params.page ?? 1,
+    pageSize: params.pageSize ?? 100,
+  });
+  return {
+    items: (payload.data ?? []).map((item) => normalizeBrand(item as unknown as Record<string, unknown>)),
+    total: pickNumber(payload.total),
+  };
+}
+
+// logoFileId 为上传到文件服务后得到的文件ID，由商品服务换成公开 URL
+export async function saveBrand(payload: {
+  id?: number;
+  name: string;
+  logo: string;
+  logoFileId?: string;
+  description: string;
+  sort: number;
+  status: number;
+}) {
+  const { id, ...body } = payload;
+  return id ? gen.updateBrand({ id, ...body }) : gen.createBrand(body);
+}
+
+export async function deleteBrand(id: number) {
+  return gen.deleteBrand({ id });
+}
+
+// includeInherited 为 true 时返回合并上级类目后的完整模板
+export async function listCategoryAttrs(categoryId: number, includeInherited = true) {
+  const payload = await gen.listCategoryAttrs({ categoryId, includeInherited });
+  return (payload.data ?? []).map((item) => normalizeAttr(item as unknown as Record<string, unknown>));
+}
+
+export async function saveAttr(payload: {
+  id?: number;
+  categoryId: number;
+  name: string;
+  type: number;
+  inputType: number;
+  values: string[];
+  sort: number;
+  isRequired: number;
+}) {
+  const { id, categoryId, type, ...body } = payload;
+  return id ? gen.updateAttr({ id, ...body }) : gen.createAttr({ categoryId, type, ...body });
+}
+
+export async function deleteAttr(id: number) {
+  return gen.deleteAttr({ id });
+}
+
+// dryRun 为 true 时只返回将要生成的 SKU，不写库
+export async function generateSkus(payload: {
+  productId: number;
+  attrs?: Array<{ name: string; values: string[] }>;
+  price?: number;
+  stock?: number;
+  status?: number;
+  dryRun?: boolean;
+}) {
+  const response = await gen.generateSkus(payload);
+  return {
+    items: (response.data ?? []).map((item) => normalizeSku(item as unknown as Record<string, unknown>)),
+    skipped: pickNumber(response.skipped),
+  };
+}
+
 export async function listOrders(params: Record<string, string | number | undefined>) {
   const response = await apiClient.get<
     ApiResponse<{
@@ -1,4 +1,4 @@
-import axios from "axios";
+import axios, { type InternalAxiosRequestConfig } from "axios";
 import { useAdminAuthStore } from "@/stores/adminAuth";
 
 const baseURL = import.meta.env.VITE_API_BASE_URL || "";
@@ -16,9 +16,49 @@ apiClient.interceptors.request.use((config) => {
   return config;
 });
 
+// 同一时间只发一个刷新请求，并发的 401 共用结果（刷新令牌只能使用一次）
+let refreshing: Promise<string> | null = null;
+
+function refreshAccessToken(): Promise<string> {
+  if (!refreshing) {
+    const { refreshToken, setTokens, logout } = useAdminAuthStore.getState();
+    refreshing = axios
+      .post(`${baseURL}/api/v1/user/token/refresh`, { refreshToken }, { timeout: 12000 })
+      .then((response) => {
+        const data = response.data?.data ?? {};
+        if (!data.token || !data.refreshToken) {
+          throw new Error("登录已过期，请重新登录");
+        }
+        setTokens(data.token, data.refreshToken);
+        return data.token as string;
+      })
+      .catch((error) => {
+        logout();
+        throw error;
+      })
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+}
+
 apiClient.interceptors.response.use(
   (response) => response,
-  (error) => {
+  async (error) => {
+    const config = error?.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined;
+    // 访问令牌过期或被吊销：用刷新令牌换新后重试一次
+    if (error?.response?.status === 401 && config && !config._retried && useAdminAuthStore.getState().refreshToken) {
+      config._retried = true;
+      try {
+        const token = await refreshAccessToken();
+        config.headers.Authorization = `Bearer ${token}`;
+        return apiClient(config);
+      } catch {
+        return Promise.reject(new Error("登录已过期，请重新登录"));
+      }
+    }
+
     const message =
       error?.response?.data?.message || error?.message || "请求失败，请稍后重试";
     return Promise.reject(new Error(message));
@@ -0,0 +1,5481 @@
+// Code generated by cmd/generate-swagger. DO NOT EDIT.
+// 由 api/**/*.proto 和 configs/dev/gateway.yaml 生成，修改后执行 make swagger 重新生成。
+
+import type { AxiosRequestConfig } from "axios";
+import { apiClient } from "@/api/client";
+
+/** 64 位整数：响应中按 protojson 规则为字符串，请求中字符串和数字均可 */
+export type Int64 = string | number;
+
+function pathParam(value: unknown): string {
+  return encodeURIComponent(String(value ?? ""));
+}
+
+/** 注册请求 */
+export interface RegisterRequest {
+  username?: string;
+  password?: string;
+  phone?: string;
+  email?: string;
+  /** 手机号（没有手机号时为邮箱）的验证码，场景 1 */
+  verifyCode?: string;
+}
+
+/** 注册响应 */
+export interface RegisterResponse {
+  code?: number;
+  message?: string;
+  data?: User;
+}
+
+/** 用户信息 */
+export interface User {
+  id?: Int64;
+  username?: string;
+  nickname?: string;
+  phone?: string;
+  email?: string;
+  avatar?: string;
+  gender?: number;
+  birthday?: string;
+  status?: number;
+  memberLevel?: number;
+  points?: number;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 登录请求 */
+export interface LoginRequest {
+  /** 用户名/手机号/邮箱 */
+  username?: string;
+  /** login_type=4 时不需要 */
+  password?: string;
+  /** 1-用户名, 2-手机号, 3-邮箱, 4-手机号+短信验证码 */
+  loginType?: number;
+  /** 短信验证码（login_type=4 时必填，场景 2） */
+  verifyCode?: string;
+  /** 人机验证 token，返回 code=2007 后必填 */
+  captchaToken?: string;
+}
+
+/** 登录响应。失败时 code：2002-用户名或密码错误，2007-需要人机验证，2008-账号已被临时锁定，429-请稍后再试 */
+export interface LoginResponse {
+  code?: number;
+  message?: string;
+  data?: LoginData;
+}
+
+export interface LoginData {
+  user?: User;
+  /** 访问令牌 */
+  token?: string;
+  /** 访问令牌过期时间（Unix 秒） */
+  expireTime?: Int64;
+  /** 刷新令牌，只能使用一次 */
+  refreshToken?: string;
+  /** 刷新令牌过期时间（Unix 秒） */
+  refreshExpireTime?: Int64;
+  /** 需要两步验证时令牌字段为空，用 mfa_token 调用 VerifyMFA 完成登录 */
+  mfaRequired?: boolean;
+  mfaToken?: string;
+  /** mfa_token 过期时间（Unix 秒） */
+  mfaExpireTime?: Int64;
+  /** 角色要求开启但尚未绑定：先用 mfa_token 调用 SetupTOTP，再 VerifyMFA */
+  mfaEnrollRequired?: boolean;
+}
+
+/** 刷新令牌请求 */
+export interface RefreshTokenRequest {
+  refreshToken?: string;
+}
+
+/** 刷新令牌响应 */
+export interface RefreshTokenResponse {
+  code?: number;
+  message?: string;
+  data?: LoginData;
+}
+
+/** 登出请求 */
+export interface LogoutRequest {
+  /** 可选，传入时同时吊销该登录的刷新令牌 */
+  refreshToken?: string;
+}
+
+/** 登出响应 */
+export interface LogoutResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 退出所有设备请求 */
+export type RevokeAllSessionsRequest = Record<string, never>;
+
+/** 退出所有设备响应 */
+export interface RevokeAllSessionsResponse {
+  code?: number;
+  message?: string;
+  /** 被吊销的登录数 */
+  revoked?: number;
+}
+
+/** 我的登录设备请求 */
+export type ListMySessionsRequest = Record<string, never>;
+
+/** 我的登录设备响应 */
+export interface ListMySessionsResponse {
+  code?: number;
+  message?: string;
+  data?: Session[];
+}
+
+/** 登录会话（一次登录对应一个会话，刷新令牌时更新最近活跃时间） */
+export interface Session {
+  id?: Int64;
+  /** 由 User-Agent 解析，如 Chrome / Windows */
+  deviceName?: string;
+  userAgent?: string;
+  /** 最近一次使用的 IP */
+  ip?: string;
+  /** IP 所在地，未配置地址库时为空 */
+  location?: string;
+  loginIp?: string;
+  /** 登录时间 */
+  createdAt?: string;
+  lastSeenAt?: string;
+  expiresAt?: string;
+  revokedAt?: string;
+  /** 未下线且未过期 */
+  active?: boolean;
+  /** 是否为发起本次请求的会话 */
+  current?: boolean;
+}
+
+/** 下线登录设备请求 */
+export interface RevokeSessionRequest {
+  /** 路径参数 :session_id */
+  sessionId?: Int64;
+}
+
+/** 下线登录设备响应 */
+export interface RevokeSessionResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 发送验证码请求 */
+export interface SendVerifyCodeRequest {
+  /** 1-注册, 2-验证码登录, 3-重置密码, 4-更换手机号 */
+  scene?: number;
+  /** 手机号或邮箱（验证码登录、更换手机号只支持手机号） */
+  target?: string;
+}
+
+/** 发送验证码响应 */
+export interface SendVerifyCodeResponse {
+  code?: number;
+  message?: string;
+  /** 多少秒后可以重新发送 */
+  retryAfter?: Int64;
+}
+
+/** 校验验证码请求 */
+export interface CheckVerifyCodeRequest {
+  scene?: number;
+  target?: string;
+  verifyCode?: string;
+}
+
+/** 校验验证码响应 */
+export interface CheckVerifyCodeResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 更换手机号请求 */
+export interface ChangePhoneRequest {
+  phone?: string;
+  /** 新手机号的验证码，场景 4 */
+  verifyCode?: string;
+}
+
+/** 更换手机号响应 */
+export interface ChangePhoneResponse {
+  code?: number;
+  message?: string;
+  data?: User;
+}
+
+/** 申请重置密码请求 */
+export interface RequestPasswordResetRequest {
+  /** 注册时填写的手机号或邮箱 */
+  target?: string;
+}
+
+/** 申请重置密码响应 */
+export interface RequestPasswordResetResponse {
+  code?: number;
+  message?: string;
+  /** 多少秒后可以重新申请 */
+  retryAfter?: Int64;
+}
+
+/** 重置密码请求 */
+export interface ResetPasswordRequest {
+  /** 重置链接中的 token，只能使用一次 */
+  token?: string;
+  newPassword?: string;
+}
+
+/** 重置密码响应 */
+export interface ResetPasswordResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 修改密码请求 */
+export interface ChangePasswordRequest {
+  oldPassword?: string;
+  newPassword?: string;
+  /** 开启两步验证时必填，验证码或恢复码 */
+  mfaCode?: string;
+}
+
+/** 修改密码响应 */
+export interface ChangePasswordResponse {
+  code?: number;
+  message?: string;
+  /** 当前设备的新令牌 */
+  data?: LoginData;
+}
+
+/** 获取会员等级定义请求 */
+export type ListMemberTiersRequest = Record<string, never>;
+
+/** 获取会员等级定义响应 */
+export interface ListMemberTiersResponse {
+  code?: number;
+  message?: string;
+  data?: MemberTier[];
+}
+
+/** 会员等级定义 */
+export interface MemberTier {
+  level?: number;
+  name?: string;
+  /** 统计窗口内实付金额门槛（元） */
+  minGmv?: number;
+  /** 统计窗口内订单数门槛 */
+  minOrders?: number;
+  /** 会员折扣，0.95 表示 95 折 */
+  discountRate?: number;
+  /** 每月包邮次数 */
+  freeShippingQuota?: number;
+  /** 积分倍数 */
+  pointsMultiplier?: number;
+}
+
+/** 获取会员权益请求 */
+export interface GetMemberBenefitsRequest {
+  /** 内部调用时指定，已登录时忽略 */
+  userId?: Int64;
+}
+
+/** 获取会员权益响应 */
+export interface GetMemberBenefitsResponse {
+  code?: number;
+  message?: string;
+  data?: MemberBenefits;
+}
+
+export interface MemberBenefits {
+  /** 当前等级 */
+  tier?: MemberTier;
+  /** 统计窗口内扣除退款后的实付金额 */
+  gmv?: number;
+  /** 统计窗口内订单数 */
+  orderCount?: number;
+  /** 下一等级，已是最高等级时为空 */
+  nextTier?: MemberTier;
+  /** 本月已使用包邮次数 */
+  freeShippingUsed?: number;
+  /** 本月剩余包邮次数 */
+  freeShippingRemaining?: number;
+}
+
+/** 获取会员等级变更记录请求 */
+export interface ListMemberLevelLogsRequest {
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取会员等级变更记录响应 */
+export interface ListMemberLevelLogsResponse {
+  code?: number;
+  message?: string;
+  data?: MemberLevelLog[];
+  total?: Int64;
+}
+
+/** 会员等级变更记录 */
+export interface MemberLevelLog {
+  id?: Int64;
+  fromLevel?: number;
+  toLevel?: number;
+  /** order_completed-订单完成 refunded-退款 expired-周期评估 */
+  reason?: string;
+  /** 评定时统计窗口内实付金额 */
+  gmv?: number;
+  /** 评定时统计窗口内订单数 */
+  orderCount?: number;
+  createdAt?: string;
+}
+
+/** 申请导出个人数据请求 */
+export type ExportMyDataRequest = Record<string, never>;
+
+/** 申请导出个人数据响应 */
+export interface ExportMyDataResponse {
+  code?: number;
+  message?: string;
+  data?: DataRequest;
+}
+
+/** 个人数据请求 */
+export interface DataRequest {
+  requestNo?: string;
+  userId?: Int64;
+  /** export-数据导出 erase-注销删除 */
+  type?: string;
+  /** 0-待处理 1-处理中 2-已完成 3-失败 */
+  status?: number;
+  /** 进度百分比 */
+  progress?: number;
+  error?: string;
+  createdAt?: string;
+  finishedAt?: string;
+  tasks?: DataRequestTask[];
+  /** 导出完成后的下载地址（签名 URL，仅本人查询时返回） */
+  downloadUrl?: string;
+  /** 下载地址过期时间（Unix 秒） */
+  downloadExpiresAt?: Int64;
+}
+
+/** 个人数据请求在单个服务中的处理情况 */
+export interface DataRequestTask {
+  /** 服务名，user-service 为本地数据 */
+  service?: string;
+  /** 0-待处理 1-处理中 2-已完成 3-失败 */
+  status?: number;
+  /** 处理结果摘要，如 orders: 匿名化 3 条 */
+  summary?: string;
+  error?: string;
+  finishedAt?: string;
+}
+
+/** 注销账号请求 */
+export interface EraseMyAccountRequest {
+  /** 设置过密码时必填 */
+  password?: string;
+  /** 开启两步验证时必填，验证码或恢复码 */
+  mfaCode?: string;
+}
+
+/** 注销账号响应 */
+export interface EraseMyAccountResponse {
+  code?: number;
+  message?: string;
+  data?: DataRequest;
+}
+
+/** 获取我的个人数据请求列表请求 */
+export interface ListMyDataRequestsRequest {
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取我的个人数据请求列表响应 */
+export interface ListMyDataRequestsResponse {
+  code?: number;
+  message?: string;
+  data?: DataRequest[];
+  total?: Int64;
+}
+
+/** 获取个人数据请求详情请求 */
+export interface GetDataRequestRequest {
+  requestNo?: string;
+}
+
+/** 获取个人数据请求详情响应 */
+export interface GetDataRequestResponse {
+  code?: number;
+  message?: string;
+  data?: DataRequest;
+}
+
+/** 获取第三方登录方式请求 */
+export type ListOAuthProvidersRequest = Record<string, never>;
+
+/** 获取第三方登录方式响应 */
+export interface ListOAuthProvidersResponse {
+  code?: number;
+  message?: string;
+  data?: OAuthProvider[];
+}
+
+/** 第三方登录方式 */
+export interface OAuthProvider {
+  /** 提供方标识，用于接口路径 */
+  name?: string;
+  displayName?: string;
+}
+
+/** 获取第三方授权地址请求 */
+export interface GetOAuthAuthURLRequest {
+  /** 路径参数 :provider */
+  provider?: string;
+  /** true-已登录用户绑定，授权回调后调用 LinkOAuthIdentity */
+  link?: boolean;
+}
+
+/** 获取第三方授权地址响应 */
+export interface GetOAuthAuthURLResponse {
+  code?: number;
+  message?: string;
+  data?: OAuthAuthURL;
+}
+
+/** 第三方授权地址 */
+export interface OAuthAuthURL {
+  /** 前端跳转到该地址 */
+  authUrl?: string;
+  /** 回调时原样带回，有效期 10 分钟，只能使用一次 */
+  state?: string;
+}
+
+/** 第三方登录请求 */
+export interface OAuthLoginRequest {
+  /** 路径参数 :provider */
+  provider?: string;
+  /** 授权回调中的 code */
+  code?: string;
+  /** 授权回调中的 state */
+  state?: string;
+}
+
+/** 第三方登录响应 */
+export interface OAuthLoginResponse {
+  code?: number;
+  message?: string;
+  data?: LoginData;
+  /** 本次登录自动注册了新用户 */
+  isNewUser?: boolean;
+}
+
+/** 获取已绑定的第三方账号请求 */
+export type ListOAuthIdentitiesRequest = Record<string, never>;
+
+/** 获取已绑定的第三方账号响应 */
+export interface ListOAuthIdentitiesResponse {
+  code?: number;
+  message?: string;
+  data?: OAuthIdentity[];
+}
+
+/** 已绑定的第三方账号 */
+export interface OAuthIdentity {
+  provider?: string;
+  /** 第三方用户标识 */
+  subject?: string;
+  /** 第三方已验证的邮箱 */
+  email?: string;
+  name?: string;
+  linkedAt?: string;
+}
+
+/** 绑定第三方账号请求 */
+export interface LinkOAuthIdentityRequest {
+  /** 路径参数 :provider */
+  provider?: string;
+  code?: string;
+  state?: string;
+}
+
+/** 绑定第三方账号响应 */
+export interface LinkOAuthIdentityResponse {
+  code?: number;
+  message?: string;
+  data?: OAuthIdentity;
+}
+
+/** 解绑第三方账号请求 */
+export interface UnlinkOAuthIdentityRequest {
+  /** 路径参数 :provider */
+  provider?: string;
+}
+
+/** 解绑第三方账号响应 */
+export interface UnlinkOAuthIdentityResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 登录第二步请求 */
+export interface VerifyMFARequest {
+  mfaToken?: string;
+  /** 验证器应用中的 6 位验证码，或恢复码（xxxxx-xxxxx） */
+  code?: string;
+}
+
+/** 登录第二步响应 */
+export interface VerifyMFAResponse {
+  code?: number;
+  message?: string;
+  data?: LoginData;
+  /** 本次完成绑定时返回恢复码，只显示这一次 */
+  recoveryCodes?: string[];
+}
+
+/** 获取两步验证状态请求 */
+export type GetMFAStatusRequest = Record<string, never>;
+
+/** 获取两步验证状态响应 */
+export interface GetMFAStatusResponse {
+  code?: number;
+  message?: string;
+  data?: MFAStatus;
+}
+
+/** 两步验证状态 */
+export interface MFAStatus {
+  enabled?: boolean;
+  /** 当前角色要求开启 */
+  required?: boolean;
+  recoveryCodesLeft?: number;
+}
+
+/** 生成 TOTP 密钥请求 */
+export interface SetupTOTPRequest {
+  /** 登录过程中绑定时填写，已登录时留空 */
+  mfaToken?: string;
+}
+
+/** 生成 TOTP 密钥响应 */
+export interface SetupTOTPResponse {
+  code?: number;
+  message?: string;
+  data?: TOTPSetup;
+}
+
+/** TOTP 密钥 */
+export interface TOTPSetup {
+  /** 无法扫码时手动输入 */
+  secret?: string;
+  /** otpauth:// 地址，渲染成二维码 */
+  provisioningUri?: string;
+}
+
+/** 确认绑定 TOTP 请求 */
+export interface ConfirmTOTPRequest {
+  code?: string;
+}
+
+/** 确认绑定 TOTP 响应 */
+export interface ConfirmTOTPResponse {
+  code?: number;
+  message?: string;
+  recoveryCodes?: string[];
+}
+
+/** 关闭两步验证请求 */
+export interface DisableTOTPRequest {
+  /** 验证码或恢复码 */
+  code?: string;
+}
+
+/** 关闭两步验证响应 */
+export interface DisableTOTPResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 重新生成恢复码请求 */
+export interface RegenerateRecoveryCodesRequest {
+  /** 验证码或恢复码 */
+  code?: string;
+}
+
+/** 重新生成恢复码响应 */
+export interface RegenerateRecoveryCodesResponse {
+  code?: number;
+  message?: string;
+  recoveryCodes?: string[];
+}
+
+/** 获取用户信息请求 */
+export interface GetUserInfoRequest {
+  userId?: Int64;
+}
+
+/** 获取用户信息响应 */
+export interface GetUserInfoResponse {
+  code?: number;
+  message?: string;
+  data?: User;
+}
+
+/** 更新用户信息请求 */
+export interface UpdateUserInfoRequest {
+  userId?: Int64;
+  nickname?: string;
+  avatar?: string;
+  gender?: number;
+  birthday?: string;
+}
+
+/** 更新用户信息响应 */
+export interface UpdateUserInfoResponse {
+  code?: number;
+  message?: string;
+  data?: User;
+}
+
+/** 获取用户列表请求（管理后台） */
+export interface ListUsersRequest {
+  /** 页码，从1开始 */
+  page?: number;
+  /** 每页数量 */
+  pageSize?: number;
+  /** 搜索关键词（用户名/手机号/邮箱） */
+  keyword?: string;
+  /** 状态筛选（0-全部，1-正常，2-禁用） */
+  status?: number;
+}
+
+/** 获取用户列表响应 */
+export interface ListUsersResponse {
+  code?: number;
+  message?: string;
+  data?: ListUsersData;
+}
+
+export interface ListUsersData {
+  users?: User[];
+  /** 总数量 */
+  total?: number;
+  /** 当前页码 */
+  page?: number;
+  /** 每页数量 */
+  pageSize?: number;
+}
+
+/** 删除用户请求（管理后台） */
+export interface DeleteUserRequest {
+  /** 路径参数 :id 会映射到这里 */
+  id?: Int64;
+}
+
+/** 删除用户响应 */
+export interface DeleteUserResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 解除账号锁定请求 */
+export interface UnlockUserRequest {
+  /** 路径参数 :user_id */
+  userId?: Int64;
+}
+
+/** 解除账号锁定响应 */
+export interface UnlockUserResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 查看用户登录会话请求（管理后台） */
+export interface ListUserSessionsRequest {
+  /** 路径参数 :user_id */
+  userId?: Int64;
+  /** 是否包含已下线和已过期的会话 */
+  includeInactive?: boolean;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 查看用户登录会话响应 */
+export interface ListUserSessionsResponse {
+  code?: number;
+  message?: string;
+  data?: Session[];
+  total?: Int64;
+}
+
+/** 强制下线用户登录会话请求（管理后台） */
+export interface RevokeUserSessionRequest {
+  /** 路径参数 :user_id */
+  userId?: Int64;
+  /** 路径参数 :session_id */
+  sessionId?: Int64;
+}
+
+/** 强制下线用户登录会话响应 */
+export interface RevokeUserSessionResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 执行会员等级评估请求（管理后台） */
+export type EvaluateMemberLevelsRequest = Record<string, never>;
+
+/** 执行会员等级评估响应 */
+export interface EvaluateMemberLevelsResponse {
+  code?: number;
+  message?: string;
+  /** 等级发生变化的用户数 */
+  changed?: number;
+}
+
+/** 获取个人数据请求列表请求（管理后台） */
+export interface ListDataRequestsRequest {
+  /** 0 表示全部 */
+  userId?: Int64;
+  /** 空表示全部 */
+  type?: string;
+  /** -1 表示全部 */
+  status?: number;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取个人数据请求列表响应（管理后台） */
+export interface ListDataRequestsResponse {
+  code?: number;
+  message?: string;
+  data?: DataRequest[];
+  total?: Int64;
+}
+
+/** 重新执行个人数据请求（管理后台） */
+export interface RetryDataRequestRequest {
+  requestNo?: string;
+}
+
+/** 重新执行个人数据请求响应 */
+export interface RetryDataRequestResponse {
+  code?: number;
+  message?: string;
+  data?: DataRequest;
+}
+
+/** 获取角色列表请求 */
+export type ListRolesRequest = Record<string, never>;
+
+/** 获取角色列表响应 */
+export interface ListRolesResponse {
+  code?: number;
+  message?: string;
+  data?: Role[];
+}
+
+/** 角色信息 */
+export interface Role {
+  id?: Int64;
+  /** 角色编码，如 super_admin、operator */
+  code?: string;
+  name?: string;
+  description?: string;
+  /** 权限点，如 product:write，* 表示全部权限 */
+  permissions?: string[];
+}
+
+/** 获取用户角色请求 */
+export interface GetUserRolesRequest {
+  userId?: Int64;
+}
+
+/** 获取用户角色响应 */
+export interface GetUserRolesResponse {
+  code?: number;
+  message?: string;
+  data?: Role[];
+}
+
+/** 分配角色请求 */
+export interface AssignUserRoleRequest {
+  userId?: Int64;
+  roleCode?: string;
+}
+
+/** 分配角色响应（返回用户当前的全部角色） */
+export interface AssignUserRoleResponse {
+  code?: number;
+  message?: string;
+  data?: Role[];
+}
+
+/** 移除角色请求 */
+export interface RemoveUserRoleRequest {
+  userId?: Int64;
+  roleCode?: string;
+}
+
+/** 移除角色响应（返回用户当前的全部角色） */
+export interface RemoveUserRoleResponse {
+  code?: number;
+  message?: string;
+  data?: Role[];
+}
+
+/** 获取地址列表请求 */
+export interface GetAddressListRequest {
+  userId?: Int64;
+}
+
+/** 获取地址列表响应 */
+export interface GetAddressListResponse {
+  code?: number;
+  message?: string;
+  data?: Address[];
+}
+
+/** 用户地址 */
+export interface Address {
+  id?: Int64;
+  userId?: Int64;
+  receiverName?: string;
+  receiverPhone?: string;
+  province?: string;
+  city?: string;
+  district?: string;
+  detail?: string;
+  postalCode?: string;
+  isDefault?: number;
+  createdAt?: string;
+  updatedAt?: string;
+  /** 省级行政区划代码 */
+  provinceCode?: string;
+  /** 地级行政区划代码，港澳台为空 */
+  cityCode?: string;
+  /** 县级行政区划代码，东莞市等不设区县的地级市为空 */
+  districtCode?: string;
+}
+
+/** 添加地址请求 */
+export interface AddAddressRequest {
+  userId?: Int64;
+  receiverName?: string;
+  receiverPhone?: string;
+  /** 省市区可以填名称，也可以只填对应的区划代码 */
+  province?: string;
+  city?: string;
+  district?: string;
+  detail?: string;
+  postalCode?: string;
+  isDefault?: number;
+  /** 区划代码，填写时优先于名称 */
+  provinceCode?: string;
+  cityCode?: string;
+  districtCode?: string;
+}
+
+/** 添加地址响应 */
+export interface AddAddressResponse {
+  code?: number;
+  message?: string;
+  data?: Address;
+}
+
+/** 更新地址请求 */
+export interface UpdateAddressRequest {
+  id?: Int64;
+  userId?: Int64;
+  receiverName?: string;
+  receiverPhone?: string;
+  /** 修改省市区中的任意一级时，未填写的级别沿用原地址并整体重新校验 */
+  province?: string;
+  city?: string;
+  district?: string;
+  detail?: string;
+  postalCode?: string;
+  isDefault?: number;
+  /** 区划代码，填写时优先于名称 */
+  provinceCode?: string;
+  cityCode?: string;
+  districtCode?: string;
+}
+
+/** 更新地址响应 */
+export interface UpdateAddressResponse {
+  code?: number;
+  message?: string;
+  data?: Address;
+}
+
+/** 删除地址请求 */
+export interface DeleteAddressRequest {
+  id?: Int64;
+  userId?: Int64;
+}
+
+/** 删除地址响应 */
+export interface DeleteAddressResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取行政区划请求 */
+export interface GetRegionsRequest {
+  /** 上级区划代码，为空时返回省级列表 */
+  parentCode?: string;
+}
+
+/** 获取行政区划响应 */
+export interface GetRegionsResponse {
+  code?: number;
+  message?: string;
+  data?: Region[];
+}
+
+/** 行政区划 */
+export interface Region {
+  /** 6 位行政区划代码 */
+  code?: string;
+  name?: string;
+  /** 1-省级 2-地级 3-县级 */
+  level?: number;
+  parentCode?: string;
+  /** 是否还有下级，为 false 时级联选择到此结束 */
+  hasChildren?: boolean;
+}
+
+/** 签发 API Key 请求 */
+export interface CreateApiKeyRequest {
+  name?: string;
+  /** 权限范围，如 orders:read、inventory:write */
+  scopes?: string[];
+  rateLimit?: number;
+  expiresAt?: Int64;
+}
+
+/** 签发 API Key 响应 */
+export interface CreateApiKeyResponse {
+  code?: number;
+  message?: string;
+  data?: ApiKey;
+  /** 只返回这一次，请妥善保存 */
+  secret?: string;
+}
+
+/** API Key 信息（不含 secret） */
+export interface ApiKey {
+  keyId?: string;
+  name?: string;
+  scopes?: string[];
+  /** 每分钟最大请求数，0 表示使用网关默认值 */
+  rateLimit?: number;
+  /** 1-启用, 2-已吊销 */
+  status?: number;
+  /** 过期时间（Unix 秒），0 表示永不过期 */
+  expiresAt?: Int64;
+  lastUsedAt?: Int64;
+  createdAt?: Int64;
+  rotatedAt?: Int64;
+}
+
+/** 获取 API Key 列表请求 */
+export interface ListApiKeysRequest {
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取 API Key 列表响应 */
+export interface ListApiKeysResponse {
+  code?: number;
+  message?: string;
+  data?: ApiKey[];
+  total?: number;
+}
+
+/** 更新 API Key 请求 */
+export interface UpdateApiKeyRequest {
+  keyId?: string;
+  /** 为空表示不修改 */
+  name?: string;
+  /** 为空表示不修改 */
+  scopes?: string[];
+  /** 小于 0 表示不修改 */
+  rateLimit?: number;
+}
+
+/** 更新 API Key 响应 */
+export interface UpdateApiKeyResponse {
+  code?: number;
+  message?: string;
+  data?: ApiKey;
+}
+
+/** 吊销 API Key 请求 */
+export interface RevokeApiKeyRequest {
+  keyId?: string;
+}
+
+/** 吊销 API Key 响应 */
+export interface RevokeApiKeyResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 轮换 secret 请求 */
+export interface RotateApiKeyRequest {
+  keyId?: string;
+  /** 旧 secret 的宽限期（秒），0 表示立即失效 */
+  graceSeconds?: Int64;
+}
+
+/** 轮换 secret 响应 */
+export interface RotateApiKeyResponse {
+  code?: number;
+  message?: string;
+  data?: ApiKey;
+  secret?: string;
+}
+
+/** 调用量查询请求 */
+export interface GetApiKeyUsageRequest {
+  keyId?: string;
+  /** 最近 N 天，默认 7，最多 90 */
+  days?: number;
+}
+
+/** 调用量查询响应 */
+export interface GetApiKeyUsageResponse {
+  code?: number;
+  message?: string;
+  data?: ApiKeyUsage[];
+}
+
+/** 单日调用量 */
+export interface ApiKeyUsage {
+  /** yyyy-MM-dd */
+  day?: string;
+  calls?: Int64;
+  /** 状态码 >= 400 的调用数 */
+  errors?: Int64;
+}
+
+/** 审计日志查询请求 */
+export interface ListApiKeyAuditLogsRequest {
+  keyId?: string;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 审计日志查询响应 */
+export interface ListApiKeyAuditLogsResponse {
+  code?: number;
+  message?: string;
+  data?: ApiKeyCall[];
+  total?: number;
+}
+
+/** 调用记录 */
+export interface ApiKeyCall {
+  keyId?: string;
+  method?: string;
+  path?: string;
+  statusCode?: number;
+  /** ok / invalid_signature / expired_timestamp / replayed_nonce / forbidden_scope / rate_limited / ... */
+  result?: string;
+  clientIp?: string;
+  latencyMs?: Int64;
+  /** Unix 毫秒 */
+  createdAt?: Int64;
+}
+
+/** 获取商品列表请求 */
+export interface ListProductsRequest {
+  categoryId?: Int64;
+  brandId?: Int64;
+  keyword?: string;
+  status?: number;
+  page?: number;
+  pageSize?: number;
+  /** price_asc, price_desc, sales_desc, created_desc */
+  sort?: string;
+  /** 是否热门: -1-全部, 0-否, 1-是 */
+  isHot?: number;
+}
+
+/** 获取商品列表响应 */
+export interface ListProductsResponse {
+  code?: number;
+  message?: string;
+  data?: ProductListData;
+}
+
+export interface ProductListData {
+  list?: Product[];
+  page?: number;
+  pageSize?: number;
+  total?: Int64;
+  totalPages?: number;
+}
+
+/** 商品信息 */
+export interface Product {
+  id?: Int64;
+  spuCode?: string;
+  name?: string;
+  subtitle?: string;
+  categoryId?: Int64;
+  brandId?: Int64;
+  /** 主图URL */
+  mainImage?: string;
+  /** 本地主图路径 */
+  localMainImage?: string;
+  /** 图片URL列表 */
+  images?: string[];
+  /** 本地图片路径列表 */
+  localImages?: string[];
+  detail?: string;
+  price?: number;
+  originalPrice?: number;
+  stock?: number;
+  sales?: number;
+  /** 0-下架, 1-上架, 2-待审核（从未通过审核） */
+  status?: number;
+  /** 是否热门: 0-否, 1-是 */
+  isHot?: number;
+  createdAt?: string;
+  updatedAt?: string;
+  /** 品牌名称 */
+  brandName?: string;
+  /** 规格参数和基础属性，多选值以逗号分隔 */
+  attrs?: Record<string, string>;
+  /** 最近一次修订的审核状态: 0-草稿, 1-待审核, 2-已通过, 3-已驳回 */
+  auditStatus?: number;
+  /** 最近一次审核意见 */
+  auditComment?: string;
+}
+
+/** 创建商品请求（管理后台） */
+export interface CreateProductRequest {
+  name?: string;
+  subtitle?: string;
+  categoryId?: Int64;
+  brandId?: Int64;
+  /** 主图URL */
+  mainImage?: string;
+  /** 本地主图路径 */
+  localMainImage?: string;
+  /** 图片URL列表 */
+  images?: string[];
+  /** 本地图片路径列表 */
+  localImages?: string[];
+  detail?: string;
+  price?: number;
+  originalPrice?: number;
+  stock?: number;
+  /** 新商品为待审核状态，审核通过后自动上架，此字段不再生效 */
+  status?: number;
+  /** 是否热门: 0-否, 1-是 */
+  isHot?: number;
+  /** 规格参数和基础属性，按类目属性模板校验 */
+  attrs?: Record<string, string>;
+  /** 创建后直接提交审核 */
+  submit?: boolean;
+}
+
+/** 创建商品响应 */
+export interface CreateProductResponse {
+  code?: number;
+  message?: string;
+  data?: Product;
+  /** 商品的第一个修订 */
+  revision?: ProductRevision;
+}
+
+/** 商品修订：名称、图片、价格等内容的修改，审核通过后才应用到商品上 */
+export interface ProductRevision {
+  id?: Int64;
+  productId?: Int64;
+  /** 应用修订后的商品内容 */
+  content?: Product;
+  /** 0-草稿, 1-待审核, 2-已通过, 3-已驳回 */
+  status?: number;
+  createdBy?: Int64;
+  reviewedBy?: Int64;
+  /** 审核意见 */
+  reviewComment?: string;
+  submittedAt?: string;
+  reviewedAt?: string;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 获取商品详情请求 */
+export interface GetProductRequest {
+  id?: Int64;
+  spuCode?: string;
+}
+
+/** 获取商品详情响应 */
+export interface GetProductResponse {
+  code?: number;
+  message?: string;
+  data?: Product;
+}
+
+/** 更新商品请求（管理后台） */
+export interface UpdateProductRequest {
+  id?: Int64;
+  name?: string;
+  subtitle?: string;
+  categoryId?: Int64;
+  brandId?: Int64;
+  /** 主图URL */
+  mainImage?: string;
+  /** 本地主图路径 */
+  localMainImage?: string;
+  /** 图片URL列表 */
+  images?: string[];
+  /** 本地图片路径列表 */
+  localImages?: string[];
+  detail?: string;
+  price?: number;
+  originalPrice?: number;
+  stock?: number;
+  /** 0-下架, 1-上架, -1-不更新；未通过审核的商品忽略 */
+  status?: number;
+  /** 是否热门: 0-否, 1-是 */
+  isHot?: number;
+  /** 规格参数和基础属性，为空表示不更新 */
+  attrs?: Record<string, string>;
+  /** 保存后直接提交审核 */
+  submit?: boolean;
+}
+
+/** 更新商品响应。内容修改写入修订，data 为当前线上的商品 */
+export interface UpdateProductResponse {
+  code?: number;
+  message?: string;
+  data?: Product;
+  /** 商品未通过审核的修订，没有时为空 */
+  revision?: ProductRevision;
+}
+
+/** 删除商品请求（管理后台） */
+export interface DeleteProductRequest {
+  id?: Int64;
+}
+
+/** 删除商品响应 */
+export interface DeleteProductResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取SKU列表请求（管理后台） */
+export interface ListSkusRequest {
+  /** 商品ID，0表示查询所有 */
+  productId?: Int64;
+  /** 状态: -1-全部, 0-下架, 1-上架 */
+  status?: number;
+  page?: number;
+  pageSize?: number;
+  /** 关键词（SKU编码/名称） */
+  keyword?: string;
+}
+
+/** 获取SKU列表响应 */
+export interface ListSkusResponse {
+  code?: number;
+  message?: string;
+  data?: SkuListData;
+}
+
+export interface SkuListData {
+  list?: Sku[];
+  page?: number;
+  pageSize?: number;
+  total?: Int64;
+  totalPages?: number;
+}
+
+/** SKU信息 */
+export interface Sku {
+  id?: Int64;
+  productId?: Int64;
+  skuCode?: string;
+  name?: string;
+  /** 规格属性 */
+  specs?: Record<string, string>;
+  price?: number;
+  originalPrice?: number;
+  stock?: number;
+  image?: string;
+  weight?: number;
+  volume?: number;
+  status?: number;
+}
+
+/** 创建SKU请求（管理后台） */
+export interface CreateSkuRequest {
+  /** 商品ID（必填） */
+  productId?: Int64;
+  /** SKU编码（必填） */
+  skuCode?: string;
+  /** SKU名称（必填） */
+  name?: string;
+  /** 规格属性（必填） */
+  specs?: Record<string, string>;
+  /** 价格（必填） */
+  price?: number;
+  /** 原价 */
+  originalPrice?: number;
+  /** 库存 */
+  stock?: number;
+  /** SKU图片 */
+  image?: string;
+  /** 重量(kg) */
+  weight?: number;
+  /** 体积(立方米) */
+  volume?: number;
+  /** 状态: 0-下架, 1-上架 */
+  status?: number;
+}
+
+/** 创建SKU响应 */
+export interface CreateSkuResponse {
+  code?: number;
+  message?: string;
+  data?: Sku;
+}
+
+/** 获取SKU详情请求 */
+export interface GetSkuRequest {
+  id?: Int64;
+  skuCode?: string;
+}
+
+/** 获取SKU详情响应 */
+export interface GetSkuResponse {
+  code?: number;
+  message?: string;
+  data?: Sku;
+}
+
+/** 更新SKU请求（管理后台） */
+export interface UpdateSkuRequest {
+  /** SKU ID（必填） */
+  id?: Int64;
+  /** SKU编码 */
+  skuCode?: string;
+  /** SKU名称 */
+  name?: string;
+  /** 规格属性 */
+  specs?: Record<string, string>;
+  /** 价格 */
+  price?: number;
+  /** 原价 */
+  originalPrice?: number;
+  /** 库存 */
+  stock?: number;
+  /** SKU图片 */
+  image?: string;
+  /** 重量(kg) */
+  weight?: number;
+  /** 体积(立方米) */
+  volume?: number;
+  /** 状态: 0-下架, 1-上架 */
+  status?: number;
+}
+
+/** 更新SKU响应 */
+export interface UpdateSkuResponse {
+  code?: number;
+  message?: string;
+  data?: Sku;
+}
+
+/** 删除SKU请求（管理后台） */
+export interface DeleteSkuRequest {
+  /** SKU ID（必填） */
+  id?: Int64;
+}
+
+/** 删除SKU响应 */
+export interface DeleteSkuResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取类目列表请求 */
+export interface GetCategoryListRequest {
+  parentId?: Int64;
+  level?: number;
+  status?: number;
+  /** 关键词（分类名/描述） */
+  keyword?: string;
+}
+
+/** 获取类目列表响应 */
+export interface GetCategoryListResponse {
+  code?: number;
+  message?: string;
+  data?: Category[];
+}
+
+/** 类目信息 */
+export interface Category {
+  id?: Int64;
+  parentId?: Int64;
+  name?: string;
+  level?: number;
+  sort?: number;
+  /** 图标URL */
+  icon?: string;
+  /** 本地图标路径 */
+  iconLocal?: string;
+  /** 图片URL */
+  image?: string;
+  /** 本地图片路径 */
+  imageLocal?: string;
+  /** 类目描述 */
+  description?: string;
+  status?: number;
+  /** 子类目 */
+  children?: Category[];
+}
+
+/** 创建类目请求（管理后台） */
+export interface CreateCategoryRequest {
+  parentId?: Int64;
+  name?: string;
+  level?: number;
+  sort?: number;
+  /** 图标URL */
+  icon?: string;
+  /** 本地图标路径 */
+  iconLocal?: string;
+  /** 图片URL */
+  image?: string;
+  /** 本地图片路径 */
+  imageLocal?: string;
+  /** 类目描述 */
+  description?: string;
+  status?: number;
+}
+
+/** 创建类目响应 */
+export interface CreateCategoryResponse {
+  code?: number;
+  message?: string;
+  data?: Category;
+}
+
+/** 获取类目树请求 */
+export interface GetCategoryTreeRequest {
+  status?: number;
+}
+
+/** 获取类目树响应 */
+export interface GetCategoryTreeResponse {
+  code?: number;
+  message?: string;
+  data?: Category[];
+}
+
+/** 获取类目详情请求 */
+export interface GetCategoryRequest {
+  id?: Int64;
+}
+
+/** 获取类目详情响应 */
+export interface GetCategoryResponse {
+  code?: number;
+  message?: string;
+  data?: Category;
+}
+
+/** 更新类目请求（管理后台） */
+export interface UpdateCategoryRequest {
+  id?: Int64;
+  parentId?: Int64;
+  name?: string;
+  level?: number;
+  sort?: number;
+  /** 图标URL */
+  icon?: string;
+  /** 本地图标路径 */
+  iconLocal?: string;
+  /** 图片URL */
+  image?: string;
+  /** 本地图片路径 */
+  imageLocal?: string;
+  /** 类目描述 */
+  description?: string;
+  status?: number;
+}
+
+/** 更新类目响应 */
+export interface UpdateCategoryResponse {
+  code?: number;
+  message?: string;
+  data?: Category;
+}
+
+/** 删除类目请求（管理后台） */
+export interface DeleteCategoryRequest {
+  id?: Int64;
+}
+
+/** 删除类目响应 */
+export interface DeleteCategoryResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取Banner列表请求 */
+export interface ListBannersRequest {
+  /** -1-全部, 0-禁用, 1-启用（只返回在投放时间内的） */
+  status?: number;
+  /** 限制数量，0表示不限制 */
+  limit?: number;
+  /** 关键词（标题/描述/链接） */
+  keyword?: string;
+}
+
+/** 获取Banner列表响应 */
+export interface ListBannersResponse {
+  code?: number;
+  message?: string;
+  data?: Banner[];
+}
+
+/** Banner信息 */
+export interface Banner {
+  id?: Int64;
+  title?: string;
+  description?: string;
+  /** 封面图片URL */
+  image?: string;
+  /** 本地图片路径 */
+  imageLocal?: string;
+  /** 跳转链接 */
+  link?: string;
+  /** 1-商品详情, 2-分类页面, 3-外部链接, 4-无链接 */
+  linkType?: number;
+  /** 排序值 */
+  sort?: number;
+  /** 0-禁用, 1-启用 */
+  status?: number;
+  /** 开始时间 */
+  startTime?: string;
+  /** 结束时间 */
+  endTime?: string;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 获取Banner详情请求 */
+export interface GetBannerRequest {
+  id?: Int64;
+}
+
+/** 获取Banner详情响应 */
+export interface GetBannerResponse {
+  code?: number;
+  message?: string;
+  data?: Banner;
+}
+
+/** 创建Banner请求（管理后台） */
+export interface CreateBannerRequest {
+  title?: string;
+  description?: string;
+  /** 封面图片URL */
+  image?: string;
+  /** 本地图片路径 */
+  imageLocal?: string;
+  /** 跳转链接 */
+  link?: string;
+  /** 1-商品详情, 2-分类页面, 3-外部链接, 4-无链接 */
+  linkType?: number;
+  /** 排序值 */
+  sort?: number;
+  /** 0-禁用, 1-启用 */
+  status?: number;
+  /** 开始时间 */
+  startTime?: string;
+  /** 结束时间 */
+  endTime?: string;
+}
+
+/** 创建Banner响应 */
+export interface CreateBannerResponse {
+  code?: number;
+  message?: string;
+  data?: Banner;
+}
+
+/** 更新Banner请求（管理后台） */
+export interface UpdateBannerRequest {
+  id?: Int64;
+  title?: string;
+  description?: string;
+  /** 封面图片URL */
+  image?: string;
+  /** 本地图片路径 */
+  imageLocal?: string;
+  /** 跳转链接 */
+  link?: string;
+  /** 1-商品详情, 2-分类页面, 3-外部链接, 4-无链接 */
+  linkType?: number;
+  /** 排序值 */
+  sort?: number;
+  /** 0-禁用, 1-启用 */
+  status?: number;
+  /** 开始时间 */
+  startTime?: string;
+  /** 结束时间 */
+  endTime?: string;
+}
+
+/** 更新Banner响应 */
+export interface UpdateBannerResponse {
+  code?: number;
+  message?: string;
+  data?: Banner;
+}
+
+/** 删除Banner请求（管理后台） */
+export interface DeleteBannerRequest {
+  id?: Int64;
+}
+
+/** 删除Banner响应 */
+export interface DeleteBannerResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取品牌列表请求 */
+export interface ListBrandsRequest {
+  /** -1-全部, 0-禁用, 1-启用 */
+  status?: number;
+  /** 关键词（名称/描述） */
+  keyword?: string;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取品牌列表响应 */
+export interface ListBrandsResponse {
+  code?: number;
+  message?: string;
+  data?: Brand[];
+  total?: Int64;
+}
+
+/** 品牌信息 */
+export interface Brand {
+  id?: Int64;
+  name?: string;
+  /** Logo URL */
+  logo?: string;
+  description?: string;
+  /** 排序值 */
+  sort?: number;
+  /** 0-禁用, 1-启用 */
+  status?: number;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 获取品牌详情请求 */
+export interface GetBrandRequest {
+  id?: Int64;
+}
+
+/** 获取品牌详情响应 */
+export interface GetBrandResponse {
+  code?: number;
+  message?: string;
+  data?: Brand;
+}
+
+/** 创建品牌请求（管理后台） */
+export interface CreateBrandRequest {
+  name?: string;
+  /** Logo URL */
+  logo?: string;
+  /** 文件服务的文件ID（公开分类），优先于 logo */
+  logoFileId?: string;
+  description?: string;
+  sort?: number;
+  /** 0-禁用, 1-启用 */
+  status?: number;
+}
+
+/** 创建品牌响应 */
+export interface CreateBrandResponse {
+  code?: number;
+  message?: string;
+  data?: Brand;
+}
+
+/** 更新品牌请求（管理后台） */
+export interface UpdateBrandRequest {
+  id?: Int64;
+  name?: string;
+  logo?: string;
+  logoFileId?: string;
+  description?: string;
+  sort?: number;
+  /** -1 表示不更新 */
+  status?: number;
+}
+
+/** 更新品牌响应 */
+export interface UpdateBrandResponse {
+  code?: number;
+  message?: string;
+  data?: Brand;
+}
+
+/** 删除品牌请求（管理后台） */
+export interface DeleteBrandRequest {
+  id?: Int64;
+}
+
+/** 删除品牌响应 */
+export interface DeleteBrandResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取类目属性模板请求 */
+export interface ListCategoryAttrsRequest {
+  categoryId?: Int64;
+  /** 0-全部, 1-规格参数, 2-销售属性, 3-基础属性 */
+  type?: number;
+  /** 是否包含上级类目的属性 */
+  includeInherited?: boolean;
+}
+
+/** 获取类目属性模板响应 */
+export interface ListCategoryAttrsResponse {
+  code?: number;
+  message?: string;
+  data?: Attr[];
+}
+
+/** 类目属性 */
+export interface Attr {
+  id?: Int64;
+  /** 所属类目，继承来的属性为上级类目ID */
+  categoryId?: Int64;
+  name?: string;
+  /** 1-规格参数, 2-销售属性, 3-基础属性 */
+  type?: number;
+  /** 1-单选, 2-多选, 3-文本 */
+  inputType?: number;
+  /** 可选值（文本类型为空） */
+  values?: string[];
+  sort?: number;
+  /** 0-否, 1-是 */
+  isRequired?: number;
+  /** 是否继承自上级类目 */
+  inherited?: boolean;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 创建类目属性请求（管理后台） */
+export interface CreateAttrRequest {
+  categoryId?: Int64;
+  name?: string;
+  /** 1-规格参数, 2-销售属性, 3-基础属性 */
+  type?: number;
+  /** 1-单选, 2-多选, 3-文本；销售属性只能单选 */
+  inputType?: number;
+  /** 可选值，单选/多选必填 */
+  values?: string[];
+  sort?: number;
+  /** 0-否, 1-是 */
+  isRequired?: number;
+}
+
+/** 创建类目属性响应 */
+export interface CreateAttrResponse {
+  code?: number;
+  message?: string;
+  data?: Attr;
+}
+
+/** 更新类目属性请求（管理后台） */
+export interface UpdateAttrRequest {
+  id?: Int64;
+  name?: string;
+  /** 0 表示不更新 */
+  inputType?: number;
+  /** 为空表示不更新 */
+  values?: string[];
+  sort?: number;
+  /** -1 表示不更新 */
+  isRequired?: number;
+}
+
+/** 更新类目属性响应 */
+export interface UpdateAttrResponse {
+  code?: number;
+  message?: string;
+  data?: Attr;
+}
+
+/** 删除类目属性请求（管理后台） */
+export interface DeleteAttrRequest {
+  id?: Int64;
+}
+
+/** 删除类目属性响应 */
+export interface DeleteAttrResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 批量生成SKU请求（管理后台） */
+export interface GenerateSkusRequest {
+  productId?: Int64;
+  /** 参与组合的取值，未传的销售属性使用模板全部可选值 */
+  attrs?: SalesAttrValues[];
+  /** 为 0 时使用商品价格 */
+  price?: number;
+  stock?: number;
+  /** 0-下架, 1-上架 */
+  status?: number;
+  /** 只预览，不写库 */
+  dryRun?: boolean;
+}
+
+/** 销售属性取值 */
+export interface SalesAttrValues {
+  name?: string;
+  values?: string[];
+}
+
+/** 批量生成SKU响应 */
+export interface GenerateSkusResponse {
+  code?: number;
+  message?: string;
+  /** 新生成（或预览）的SKU */
+  data?: Sku[];
+  /** 已存在而跳过的组合数 */
+  skipped?: number;
+}
+
+/** 提交修订请求（管理后台） */
+export interface SubmitProductRevisionRequest {
+  productId?: Int64;
+}
+
+/** 修订响应 */
+export interface ProductRevisionResponse {
+  code?: number;
+  message?: string;
+  data?: ProductRevision;
+}
+
+/** 获取商品审核日志请求（管理后台） */
+export interface ListProductAuditLogsRequest {
+  productId?: Int64;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取商品审核日志响应 */
+export interface ListProductAuditLogsResponse {
+  code?: number;
+  message?: string;
+  data?: ProductAuditLog[];
+  total?: Int64;
+}
+
+/** 商品审核日志 */
+export interface ProductAuditLog {
+  id?: Int64;
+  productId?: Int64;
+  revisionId?: Int64;
+  /** create-创建, edit-修改, submit-提交审核, approve-通过, reject-驳回 */
+  action?: string;
+  operatorId?: Int64;
+  comment?: string;
+  createdAt?: string;
+}
+
+/** 获取商品修订列表请求（管理后台） */
+export interface ListProductRevisionsRequest {
+  /** 0 表示全部商品 */
+  productId?: Int64;
+  /** -1-全部, 0-草稿, 1-待审核, 2-已通过, 3-已驳回 */
+  status?: number;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取商品修订列表响应 */
+export interface ListProductRevisionsResponse {
+  code?: number;
+  message?: string;
+  data?: ProductRevision[];
+  total?: Int64;
+}
+
+/** 审核修订请求（管理后台） */
+export interface ReviewProductRevisionRequest {
+  /** 修订ID */
+  id?: Int64;
+  /** 审核意见，驳回时必填 */
+  comment?: string;
+}
+
+/** 创建定时任务请求（管理后台） */
+export interface CreateProductScheduleRequest {
+  /** product-商品, sku-SKU */
+  targetType?: string;
+  targetId?: Int64;
+  /** list-上架, delist-下架, price-改价 */
+  action?: string;
+  /** 改价时必填 */
+  price?: number;
+  /** 改价时可选，0 表示不修改 */
+  originalPrice?: number;
+  /** 执行时间（RFC3339），必须晚于当前时间 */
+  runAt?: string;
+}
+
+/** 定时任务响应 */
+export interface ProductScheduleResponse {
+  code?: number;
+  message?: string;
+  data?: ProductSchedule;
+}
+
+/** 商品定时任务 */
+export interface ProductSchedule {
+  id?: Int64;
+  /** product-商品, sku-SKU */
+  targetType?: string;
+  targetId?: Int64;
+  /** 目标为 SKU 时是其所属商品 */
+  productId?: Int64;
+  /** list-上架, delist-下架, price-改价 */
+  action?: string;
+  /** 改价任务的新价格 */
+  price?: number;
+  /** 改价任务的新原价，0 表示不修改 */
+  originalPrice?: number;
+  /** 执行时间（RFC3339） */
+  runAt?: string;
+  /** 0-待执行, 1-已执行, 2-已取消, 3-执行失败 */
+  status?: number;
+  /** 执行失败原因 */
+  error?: string;
+  createdBy?: Int64;
+  executedAt?: string;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 获取定时任务列表请求（管理后台） */
+export interface ListProductSchedulesRequest {
+  /** 0 表示全部商品 */
+  productId?: Int64;
+  /** -1-全部, 0-待执行, 1-已执行, 2-已取消, 3-执行失败 */
+  status?: number;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取定时任务列表响应 */
+export interface ListProductSchedulesResponse {
+  code?: number;
+  message?: string;
+  data?: ProductSchedule[];
+  total?: Int64;
+}
+
+/** 取消定时任务请求（管理后台） */
+export interface CancelProductScheduleRequest {
+  id?: Int64;
+}
+
+/** 批量导入商品请求（管理后台） */
+export interface ImportCatalogRequest {
+  /** 上传到文件服务得到的文件ID */
+  fileId?: string;
+  /** csv / xlsx，为空时按文件扩展名判断 */
+  format?: string;
+  /** 只校验不写入，用于先检查文件 */
+  dryRun?: boolean;
+  /** 新建或修改的商品内容直接提交审核 */
+  submit?: boolean;
+}
+
+/** 批量任务响应 */
+export interface CatalogJobResponse {
+  code?: number;
+  message?: string;
+  data?: CatalogJob;
+}
+
+/** 商品批量导入导出任务 */
+export interface CatalogJob {
+  id?: Int64;
+  /** import-导入, export-导出 */
+  kind?: string;
+  /** csv / xlsx */
+  format?: string;
+  /** 导入的源文件 */
+  fileId?: string;
+  /** 只校验不写入 */
+  dryRun?: boolean;
+  /** 0-待处理, 1-处理中, 2-已完成, 3-处理失败 */
+  status?: number;
+  /** 导入为数据行数，导出为商品数 */
+  totalRows?: number;
+  processedRows?: number;
+  /** 导出为写入的行数 */
+  successRows?: number;
+  failedRows?: number;
+  /** 逐行错误，最多返回前 1000 条，完整内容见错误报告 */
+  errors?: CatalogRowError[];
+  /** 导出文件或导入错误报告的下载地址 */
+  resultUrl?: string;
+  /** 下载地址过期时间（Unix 秒） */
+  resultUrlExpiresAt?: Int64;
+  /** 整体失败原因 */
+  message?: string;
+  createdBy?: Int64;
+  startedAt?: string;
+  finishedAt?: string;
+  createdAt?: string;
+}
+
+/** 批量导入的逐行错误 */
+export interface CatalogRowError {
+  /** 文件中的行号，表头为第 1 行 */
+  row?: number;
+  spuCode?: string;
+  skuCode?: string;
+  message?: string;
+}
+
+/** 批量导出商品请求（管理后台） */
+export interface ExportCatalogRequest {
+  /** csv / xlsx，为空时为 csv */
+  format?: string;
+  /** 0 表示全部类目，包含子类目 */
+  categoryId?: Int64;
+  /** 0 表示全部品牌 */
+  brandId?: Int64;
+  /** -1-全部, 0-下架, 1-上架, 2-待审核 */
+  status?: number;
+  keyword?: string;
+}
+
+/** 获取批量任务列表请求（管理后台） */
+export interface ListCatalogJobsRequest {
+  /** 为空表示全部，import-导入, export-导出 */
+  kind?: string;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取批量任务列表响应 */
+export interface ListCatalogJobsResponse {
+  code?: number;
+  message?: string;
+  data?: CatalogJob[];
+  total?: Int64;
+}
+
+/** 获取批量任务请求（管理后台） */
+export interface GetCatalogJobRequest {
+  id?: Int64;
+}
+
+/** 获取秒杀活动列表请求 */
+export interface ListSeckillActivitiesRequest {
+  page?: number;
+  pageSize?: number;
+  /** 状态：0-未开始，1-进行中，2-已结束 */
+  status?: number;
+  /** 是否包含已禁用活动（管理后台用） */
+  includeDisabled?: boolean;
+  /** 关键词（活动名/SKU名称） */
+  keyword?: string;
+}
+
+/** 获取秒杀活动列表响应 */
+export interface ListSeckillActivitiesResponse {
+  code?: number;
+  message?: string;
+  data?: SeckillActivityListData;
+}
+
+/** 秒杀活动列表数据 */
+export interface SeckillActivityListData {
+  list?: SeckillActivity[];
+  page?: number;
+  pageSize?: number;
+  total?: Int64;
+  totalPages?: number;
+}
+
+/** 秒杀活动 */
+export interface SeckillActivity {
+  id?: Int64;
+  name?: string;
+  skuId?: Int64;
+  skuName?: string;
+  skuImage?: string;
+  /** 秒杀价 */
+  seckillPrice?: string;
+  /** 原价 */
+  originalPrice?: string;
+  /** 库存 */
+  stock?: number;
+  /** 已售 */
+  sold?: number;
+  /** 开始时间（时间戳） */
+  startTime?: Int64;
+  /** 结束时间（时间戳） */
+  endTime?: Int64;
+  /** 状态：0-未开始，1-进行中，2-已结束 */
+  status?: number;
+  /** 配置状态：0-禁用，1-启用（管理后台用） */
+  enableStatus?: number;
+}
+
+/** 创建秒杀活动请求 */
+export interface CreateSeckillActivityRequest {
+  name?: string;
+  skuId?: Int64;
+  seckillPrice?: string;
+  stock?: number;
+  /** Unix 秒 */
+  startTime?: Int64;
+  /** Unix 秒 */
+  endTime?: Int64;
+  /** 0-禁用,1-启用 */
+  enableStatus?: number;
+}
+
+export interface CreateSeckillActivityResponse {
+  code?: number;
+  message?: string;
+  data?: SeckillActivity;
+}
+
+/** 获取秒杀活动详情请求 */
+export interface GetSeckillActivityRequest {
+  id?: Int64;
+}
+
+/** 获取秒杀活动详情响应 */
+export interface GetSeckillActivityResponse {
+  code?: number;
+  message?: string;
+  data?: SeckillActivity;
+}
+
+/** 更新秒杀活动请求 */
+export interface UpdateSeckillActivityRequest {
+  id?: Int64;
+  name?: string;
+  skuId?: Int64;
+  seckillPrice?: string;
+  stock?: number;
+  /** Unix 秒 */
+  startTime?: Int64;
+  /** Unix 秒 */
+  endTime?: Int64;
+  /** 0-禁用,1-启用 */
+  enableStatus?: number;
+}
+
+export interface UpdateSeckillActivityResponse {
+  code?: number;
+  message?: string;
+  data?: SeckillActivity;
+}
+
+export interface DeleteSeckillActivityRequest {
+  id?: Int64;
+}
+
+export interface DeleteSeckillActivityResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 秒杀请求 */
+export interface SeckillRequest {
+  userId?: Int64;
+  skuId?: Int64;
+  /** 数量，默认1 */
+  quantity?: number;
+}
+
+/** 秒杀响应 */
+export interface SeckillResponse {
+  code?: number;
+  message?: string;
+  data?: SeckillData;
+}
+
+/** 秒杀数据 */
+export interface SeckillData {
+  success?: boolean;
+  /** 订单号（如果成功） */
+  orderNo?: string;
+  /** 提示信息 */
+  message?: string;
+}
+
+/** 创建订单请求 */
+export interface CreateOrderRequest {
+  userId?: Int64;
+  /** 收货地址ID */
+  addressId?: Int64;
+  /** 订单商品项 */
+  items?: OrderItemRequest[];
+  /** 订单类型: 1-普通订单, 2-秒杀订单, 3-拼团订单 */
+  orderType?: number;
+  /** 优惠券ID（可选） */
+  couponId?: Int64;
+  /** 订单备注 */
+  remark?: string;
+}
+
+/** 订单商品项请求 */
+export interface OrderItemRequest {
+  skuId?: Int64;
+  quantity?: number;
+}
+
+/** 创建订单响应 */
+export interface CreateOrderResponse {
+  code?: number;
+  message?: string;
+  data?: Order;
+}
+
+/** 订单信息 */
+export interface Order {
+  id?: Int64;
+  orderNo?: string;
+  userId?: Int64;
+  /** 1-普通订单, 2-秒杀订单, 3-拼团订单 */
+  orderType?: number;
+  /** 0-已取消, 1-待支付, 2-待发货, 3-待收货, 4-已完成, 5-已退款 */
+  status?: number;
+  /** 订单总金额 */
+  totalAmount?: string;
+  /** 实付金额 */
+  payAmount?: string;
+  /** 优惠金额 */
+  discountAmount?: string;
+  /** 运费 */
+  freightAmount?: string;
+  /** 收货人姓名 */
+  receiverName?: string;
+  /** 收货人电话 */
+  receiverPhone?: string;
+  /** 收货地址 */
+  receiverAddress?: string;
+  /** 支付方式: 1-微信, 2-支付宝, 3-银联 */
+  paymentMethod?: number;
+  /** 支付时间 */
+  paymentTime?: string;
+  /** 发货时间 */
+  deliveryTime?: string;
+  /** 收货时间 */
+  receiveTime?: string;
+  /** 取消时间 */
+  cancelTime?: string;
+  /** 取消原因 */
+  cancelReason?: string;
+  /** 订单备注 */
+  remark?: string;
+  /** 订单商品项 */
+  items?: OrderItem[];
+  createdAt?: string;
+  updatedAt?: string;
+  /** 收货地址省级行政区划代码 */
+  receiverProvinceCode?: string;
+  /** 收货地址地级行政区划代码 */
+  receiverCityCode?: string;
+  /** 收货地址县级行政区划代码 */
+  receiverDistrictCode?: string;
+}
+
+/** 订单商品项 */
+export interface OrderItem {
+  id?: Int64;
+  orderId?: Int64;
+  orderNo?: string;
+  productId?: Int64;
+  productName?: string;
+  skuId?: Int64;
+  skuCode?: string;
+  skuName?: string;
+  skuImage?: string;
+  /** SKU规格 */
+  skuSpecs?: Record<string, string>;
+  /** 单价 */
+  price?: string;
+  /** 数量 */
+  quantity?: number;
+  /** 小计金额 */
+  totalAmount?: string;
+  createdAt?: string;
+}
+
+/** 获取订单列表请求 */
+export interface ListOrdersRequest {
+  userId?: Int64;
+  /** 订单状态，-1表示全部 */
+  status?: number;
+  page?: number;
+  pageSize?: number;
+  /** 关键词（订单号/收货人） */
+  keyword?: string;
+}
+
+/** 获取订单列表响应 */
+export interface ListOrdersResponse {
+  code?: number;
+  message?: string;
+  data?: OrderListData;
+}
+
+export interface OrderListData {
+  list?: Order[];
+  page?: number;
+  pageSize?: number;
+  total?: Int64;
+  totalPages?: number;
+}
+
+/** 获取订单详情请求 */
+export interface GetOrderRequest {
+  id?: Int64;
+  orderNo?: string;
+}
+
+/** 获取订单详情响应 */
+export interface GetOrderResponse {
+  code?: number;
+  message?: string;
+  data?: Order;
+}
+
+/** 取消订单请求 */
+export interface CancelOrderRequest {
+  id?: Int64;
+  orderNo?: string;
+  /** 取消原因 */
+  reason?: string;
+}
+
+/** 取消订单响应 */
+export interface CancelOrderResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 确认收货请求 */
+export interface ConfirmReceiveRequest {
+  id?: Int64;
+  orderNo?: string;
+}
+
+/** 确认收货响应 */
+export interface ConfirmReceiveResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 支付成功通知请求 */
+export interface PayOrderRequest {
+  orderId?: Int64;
+  orderNo?: string;
+  /** 支付方式: 1-微信, 2-支付宝, 3-银联 */
+  paymentMethod?: number;
+  /** 支付单号 */
+  paymentNo?: string;
+}
+
+/** 支付成功通知响应 */
+export interface PayOrderResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 发货通知请求 */
+export interface ShipOrderRequest {
+  orderId?: Int64;
+  orderNo?: string;
+  /** 快递单号 */
+  trackingNo?: string;
+  /** 快递公司 */
+  carrier?: string;
+}
+
+/** 发货通知响应 */
+export interface ShipOrderResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 退款通知请求 */
+export interface RefundOrderRequest {
+  orderId?: Int64;
+  orderNo?: string;
+  reason?: string;
+}
+
+/** 退款通知响应 */
+export interface RefundOrderResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 创建支付单请求 */
+export interface CreatePaymentRequest {
+  orderId?: Int64;
+  orderNo?: string;
+  userId?: Int64;
+  amount?: string;
+  paymentMethod?: number;
+}
+
+/** 创建支付单响应 */
+export interface CreatePaymentResponse {
+  code?: number;
+  message?: string;
+  data?: Payment;
+  /** 支付链接（用于跳转支付） */
+  payUrl?: string;
+}
+
+/** 支付单信息 */
+export interface Payment {
+  id?: Int64;
+  paymentNo?: string;
+  orderId?: Int64;
+  orderNo?: string;
+  userId?: Int64;
+  amount?: string;
+  paymentMethod?: number;
+  status?: number;
+  thirdPartyNo?: string;
+  paidAt?: string;
+  expireAt?: string;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 获取支付单请求 */
+export interface GetPaymentRequest {
+  paymentNo?: string;
+}
+
+/** 获取支付单响应 */
+export interface GetPaymentResponse {
+  code?: number;
+  message?: string;
+  data?: Payment;
+}
+
+/** 退款请求 */
+export interface RefundRequest {
+  paymentNo?: string;
+  refundAmount?: string;
+  reason?: string;
+}
+
+/** 退款响应 */
+export interface RefundResponse {
+  code?: number;
+  message?: string;
+  refundNo?: string;
+}
+
+/** 查询支付状态请求 */
+export interface QueryPaymentStatusRequest {
+  paymentNo?: string;
+}
+
+/** 查询支付状态响应 */
+export interface QueryPaymentStatusResponse {
+  code?: number;
+  message?: string;
+  status?: number;
+}
+
+/** 支付回调请求 */
+export interface PaymentCallbackRequest {
+  paymentNo?: string;
+  thirdPartyNo?: string;
+  status?: number;
+  /** JSON格式的第三方回调数据 */
+  callbackData?: string;
+}
+
+/** 支付回调响应 */
+export interface PaymentCallbackResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 创建评价请求 */
+export interface CreateReviewRequest {
+  userId?: Int64;
+  orderId?: Int64;
+  orderItemId?: Int64;
+  productId?: Int64;
+  skuId?: Int64;
+  rating?: number;
+  content?: string;
+  images?: string[];
+  videos?: string[];
+}
+
+/** 创建评价响应 */
+export interface CreateReviewResponse {
+  code?: number;
+  message?: string;
+  data?: Review;
+}
+
+/** 评价信息 */
+export interface Review {
+  id?: Int64;
+  userId?: Int64;
+  orderId?: Int64;
+  productId?: Int64;
+  skuId?: Int64;
+  rating?: number;
+  content?: string;
+  images?: string[];
+  videos?: string[];
+  status?: number;
+  replyContent?: string;
+  createdAt?: string;
+}
+
+/** 获取商品评价列表请求 */
+export interface GetProductReviewsRequest {
+  productId?: Int64;
+  page?: number;
+  pageSize?: number;
+  /** 0-全部, 1-5星 */
+  rating?: number;
+}
+
+/** 获取商品评价列表响应 */
+export interface GetProductReviewsResponse {
+  code?: number;
+  message?: string;
+  data?: Review[];
+  total?: number;
+}
+
+/** 获取评价详情请求 */
+export interface GetReviewRequest {
+  reviewId?: Int64;
+}
+
+/** 获取评价详情响应 */
+export interface GetReviewResponse {
+  code?: number;
+  message?: string;
+  data?: Review;
+}
+
+/** 回复评价请求 */
+export interface ReplyReviewRequest {
+  reviewId?: Int64;
+  userId?: Int64;
+  content?: string;
+  /** 0表示直接回复评价 */
+  parentId?: Int64;
+}
+
+/** 回复评价响应 */
+export interface ReplyReviewResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取评价统计请求 */
+export interface GetReviewStatsRequest {
+  productId?: Int64;
+}
+
+/** 获取评价统计响应 */
+export interface GetReviewStatsResponse {
+  code?: number;
+  message?: string;
+  data?: ReviewStats;
+}
+
+/** 评价统计信息 */
+export interface ReviewStats {
+  totalCount?: Int64;
+  rating5Count?: Int64;
+  rating4Count?: Int64;
+  rating3Count?: Int64;
+  rating2Count?: Int64;
+  rating1Count?: Int64;
+  averageRating?: number;
+}
+
+/** 获取库存请求 */
+export interface GetInventoryRequest {
+  skuId?: Int64;
+}
+
+/** 获取库存响应 */
+export interface GetInventoryResponse {
+  code?: number;
+  message?: string;
+  data?: Inventory;
+}
+
+/** 库存信息 */
+export interface Inventory {
+  id?: Int64;
+  skuId?: Int64;
+  totalStock?: number;
+  availableStock?: number;
+  lockedStock?: number;
+  soldStock?: number;
+  lowStockThreshold?: number;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 扣减库存请求 */
+export interface DeductStockRequest {
+  skuId?: Int64;
+  quantity?: number;
+  orderId?: Int64;
+  remark?: string;
+}
+
+/** 扣减库存响应 */
+export interface DeductStockResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 批量获取库存请求 */
+export interface BatchGetInventoryRequest {
+  skuIds?: Int64[];
+}
+
+/** 批量获取库存响应 */
+export interface BatchGetInventoryResponse {
+  code?: number;
+  message?: string;
+  data?: Inventory[];
+}
+
+/** 锁定库存请求 */
+export interface LockStockRequest {
+  skuId?: Int64;
+  quantity?: number;
+  orderId?: Int64;
+  remark?: string;
+}
+
+/** 锁定库存响应 */
+export interface LockStockResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 解锁库存请求 */
+export interface UnlockStockRequest {
+  skuId?: Int64;
+  quantity?: number;
+  orderId?: Int64;
+  remark?: string;
+}
+
+/** 解锁库存响应 */
+export interface UnlockStockResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 回退库存请求 */
+export interface RollbackStockRequest {
+  skuId?: Int64;
+  quantity?: number;
+  orderId?: Int64;
+  remark?: string;
+}
+
+/** 回退库存响应 */
+export interface RollbackStockResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 入库请求 */
+export interface StockInRequest {
+  skuId?: Int64;
+  quantity?: number;
+  remark?: string;
+}
+
+/** 入库响应 */
+export interface StockInResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取库存流水请求 */
+export interface GetInventoryLogRequest {
+  skuId?: Int64;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取库存流水响应 */
+export interface GetInventoryLogResponse {
+  code?: number;
+  message?: string;
+  data?: InventoryLog[];
+  total?: number;
+}
+
+/** 库存流水信息 */
+export interface InventoryLog {
+  id?: Int64;
+  skuId?: Int64;
+  orderId?: Int64;
+  type?: number;
+  quantity?: number;
+  beforeStock?: number;
+  afterStock?: number;
+  remark?: string;
+  createdAt?: string;
+}
+
+/** 获取购物车请求 */
+export interface GetCartRequest {
+  userId?: Int64;
+}
+
+/** 获取购物车响应 */
+export interface GetCartResponse {
+  code?: number;
+  message?: string;
+  data?: CartItem[];
+}
+
+/** 购物车商品项 */
+export interface CartItem {
+  id?: Int64;
+  userId?: Int64;
+  skuId?: Int64;
+  quantity?: number;
+  isSelected?: number;
+  createdAt?: string;
+  updatedAt?: string;
+  /** 商品富信息（AddItem / GetCart 时由 product 服务填充） */
+  productId?: Int64;
+  productName?: string;
+  skuName?: string;
+  skuImage?: string;
+  /** 单价（字符串保留精度） */
+  price?: string;
+  /** "in_stock" | "low_stock" | "out_of_stock" */
+  stockStatus?: string;
+}
+
+/** 添加商品到购物车请求 */
+export interface AddItemRequest {
+  userId?: Int64;
+  skuId?: Int64;
+  quantity?: number;
+}
+
+/** 添加商品到购物车响应 */
+export interface AddItemResponse {
+  code?: number;
+  message?: string;
+  data?: CartItem;
+}
+
+/** 更新数量请求 */
+export interface UpdateQuantityRequest {
+  userId?: Int64;
+  skuId?: Int64;
+  quantity?: number;
+}
+
+/** 更新数量响应 */
+export interface UpdateQuantityResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 删除商品请求 */
+export interface RemoveItemRequest {
+  userId?: Int64;
+  skuIds?: Int64[];
+}
+
+/** 删除商品响应 */
+export interface RemoveItemResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 清空购物车请求 */
+export interface ClearCartRequest {
+  userId?: Int64;
+}
+
+/** 清空购物车响应 */
+export interface ClearCartResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 选择商品请求 */
+export interface SelectItemRequest {
+  userId?: Int64;
+  skuId?: Int64;
+  isSelected?: number;
+}
+
+/** 选择商品响应 */
+export interface SelectItemResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 批量选择请求 */
+export interface BatchSelectRequest {
+  userId?: Int64;
+  skuIds?: Int64[];
+  isSelected?: number;
+}
+
+/** 批量选择响应 */
+export interface BatchSelectResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取优惠券列表请求 */
+export interface GetCouponListRequest {
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取优惠券列表响应 */
+export interface GetCouponListResponse {
+  code?: number;
+  message?: string;
+  data?: Coupon[];
+  total?: number;
+}
+
+/** 优惠券信息 */
+export interface Coupon {
+  id?: Int64;
+  name?: string;
+  type?: number;
+  discountType?: number;
+  discountValue?: string;
+  minAmount?: string;
+  maxDiscount?: string;
+  validStartTime?: string;
+  validEndTime?: string;
+  status?: number;
+}
+
+/** 获取用户优惠券列表请求 */
+export interface GetUserCouponListRequest {
+  userId?: Int64;
+  /** 0-未使用, 1-已使用, 2-已过期 */
+  status?: number;
+}
+
+/** 获取用户优惠券列表响应 */
+export interface GetUserCouponListResponse {
+  code?: number;
+  message?: string;
+  data?: UserCoupon[];
+}
+
+/** 用户优惠券信息 */
+export interface UserCoupon {
+  id?: Int64;
+  userId?: Int64;
+  couponId?: Int64;
+  status?: number;
+  orderId?: Int64;
+  expireAt?: string;
+  createdAt?: string;
+}
+
+/** 获取促销活动列表请求 */
+export interface GetPromotionListRequest {
+  productId?: Int64;
+  categoryId?: Int64;
+}
+
+/** 获取促销活动列表响应 */
+export interface GetPromotionListResponse {
+  code?: number;
+  message?: string;
+  data?: Promotion[];
+}
+
+/** 促销活动信息 */
+export interface Promotion {
+  id?: Int64;
+  name?: string;
+  type?: number;
+  rule?: string;
+  productIds?: Int64[];
+  categoryIds?: Int64[];
+  startTime?: string;
+  endTime?: string;
+  status?: number;
+}
+
+/** 领取优惠券请求 */
+export interface ReceiveCouponRequest {
+  userId?: Int64;
+  couponId?: Int64;
+}
+
+/** 领取优惠券响应 */
+export interface ReceiveCouponResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 使用优惠券请求 */
+export interface UseCouponRequest {
+  userId?: Int64;
+  userCouponId?: Int64;
+  orderId?: Int64;
+}
+
+/** 使用优惠券响应 */
+export interface UseCouponResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 计算优惠金额请求 */
+export interface CalculateDiscountRequest {
+  userId?: Int64;
+  productIds?: Int64[];
+  quantities?: number[];
+  couponId?: Int64;
+  totalAmount?: string;
+}
+
+/** 计算优惠金额响应 */
+export interface CalculateDiscountResponse {
+  code?: number;
+  message?: string;
+  /** 优惠总额（含会员折扣） */
+  discountAmount?: string;
+  finalAmount?: string;
+  /** 其中会员等级折扣 */
+  memberDiscountAmount?: string;
+}
+
+/** 获取用户积分请求 */
+export interface GetUserPointsRequest {
+  userId?: Int64;
+}
+
+/** 获取用户积分响应 */
+export interface GetUserPointsResponse {
+  code?: number;
+  message?: string;
+  points?: Int64;
+}
+
+/** 积分兑换请求 */
+export interface ExchangePointsRequest {
+  userId?: Int64;
+  points?: Int64;
+}
+
+/** 积分兑换响应 */
+export interface ExchangePointsResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 创建物流单请求 */
+export interface CreateLogisticsRequest {
+  orderId?: Int64;
+  orderNo?: string;
+  companyCode?: string;
+  receiverName?: string;
+  receiverPhone?: string;
+  receiverAddress?: string;
+}
+
+/** 创建物流单响应 */
+export interface CreateLogisticsResponse {
+  code?: number;
+  message?: string;
+  data?: Logistics;
+}
+
+/** 物流信息 */
+export interface Logistics {
+  id?: Int64;
+  orderId?: Int64;
+  orderNo?: string;
+  logisticsNo?: string;
+  companyCode?: string;
+  companyName?: string;
+  status?: number;
+  receiverName?: string;
+  receiverPhone?: string;
+  receiverAddress?: string;
+  senderName?: string;
+  senderPhone?: string;
+  senderAddress?: string;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+/** 获取物流信息请求 */
+export interface GetLogisticsRequest {
+  orderId?: Int64;
+}
+
+/** 获取物流信息响应 */
+export interface GetLogisticsResponse {
+  code?: number;
+  message?: string;
+  data?: Logistics;
+}
+
+/** 更新物流状态请求 */
+export interface UpdateLogisticsStatusRequest {
+  logisticsNo?: string;
+  status?: number;
+  remark?: string;
+}
+
+/** 更新物流状态响应 */
+export interface UpdateLogisticsStatusResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 查询物流轨迹请求 */
+export interface QueryTrackingRequest {
+  logisticsNo?: string;
+}
+
+/** 查询物流轨迹响应 */
+export interface QueryTrackingResponse {
+  code?: number;
+  message?: string;
+  data?: TrackingNode[];
+}
+
+/** 物流轨迹节点 */
+export interface TrackingNode {
+  time?: string;
+  status?: string;
+  location?: string;
+  remark?: string;
+}
+
+/** 计算运费请求 */
+export interface CalculateFreightRequest {
+  province?: string;
+  city?: string;
+  district?: string;
+  weight?: number;
+  volume?: number;
+  /** 可选，会员有包邮次数时免运费 */
+  userId?: Int64;
+  /** 可选，下单结算时传入，包邮时占用一次会员包邮次数 */
+  orderNo?: string;
+}
+
+/** 计算运费响应 */
+export interface CalculateFreightResponse {
+  code?: number;
+  message?: string;
+  freight?: string;
+  /** 是否使用了会员包邮 */
+  memberFreeShipping?: boolean;
+}
+
+/** 商品搜索请求 */
+export interface SearchProductsRequest {
+  keyword?: string;
+  page?: number;
+  pageSize?: number;
+  categoryId?: Int64;
+  /** price_asc, price_desc, sales_desc, score_desc */
+  sortBy?: string;
+  /** 按品牌筛选，0 表示不筛选 */
+  brandId?: Int64;
+  /** 按属性筛选，格式 "颜色:红色,蓝色;尺码:L"，同一属性的多个值为“或” */
+  attrFilter?: string;
+}
+
+/** 商品搜索响应 */
+export interface SearchProductsResponse {
+  code?: number;
+  message?: string;
+  data?: ProductSearchResult[];
+  total?: number;
+  /** 属性分面统计 */
+  facets?: AttrFacet[];
+}
+
+/** 商品搜索结果 */
+export interface ProductSearchResult {
+  productId?: Int64;
+  name?: string;
+  mainImage?: string;
+  price?: string;
+  sales?: number;
+  score?: number;
+  brandId?: Int64;
+  brandName?: string;
+}
+
+/** 属性分面 */
+export interface AttrFacet {
+  name?: string;
+  values?: FacetValue[];
+}
+
+/** 分面取值及命中商品数 */
+export interface FacetValue {
+  value?: string;
+  count?: Int64;
+}
+
+/** 搜索建议请求 */
+export interface GetSearchSuggestionsRequest {
+  keyword?: string;
+  limit?: number;
+}
+
+/** 搜索建议响应 */
+export interface GetSearchSuggestionsResponse {
+  code?: number;
+  message?: string;
+  suggestions?: string[];
+}
+
+/** 搜索热词请求 */
+export interface GetHotKeywordsRequest {
+  limit?: number;
+}
+
+/** 搜索热词响应 */
+export interface GetHotKeywordsResponse {
+  code?: number;
+  message?: string;
+  keywords?: string[];
+}
+
+/** 构建商品索引请求 */
+export interface BuildProductIndexRequest {
+  productIds?: Int64[];
+}
+
+/** 构建商品索引响应 */
+export interface BuildProductIndexResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 个性化推荐请求 */
+export interface GetPersonalizedRecommendRequest {
+  userId?: Int64;
+  limit?: number;
+}
+
+/** 个性化推荐响应 */
+export interface GetPersonalizedRecommendResponse {
+  code?: number;
+  message?: string;
+  data?: RecommendProduct[];
+}
+
+/** 推荐商品 */
+export interface RecommendProduct {
+  productId?: Int64;
+  name?: string;
+  mainImage?: string;
+  price?: string;
+  score?: number;
+  /** 推荐理由 */
+  reason?: string;
+}
+
+/** 相似商品推荐请求 */
+export interface GetSimilarProductsRequest {
+  productId?: Int64;
+  limit?: number;
+}
+
+/** 相似商品推荐响应 */
+export interface GetSimilarProductsResponse {
+  code?: number;
+  message?: string;
+  data?: RecommendProduct[];
+}
+
+/** 热门推荐请求 */
+export interface GetHotProductsRequest {
+  limit?: number;
+  categoryId?: Int64;
+}
+
+/** 热门推荐响应 */
+export interface GetHotProductsResponse {
+  code?: number;
+  message?: string;
+  data?: RecommendProduct[];
+}
+
+/** 实时推荐请求 */
+export interface GetRealtimeRecommendRequest {
+  userId?: Int64;
+  limit?: number;
+}
+
+/** 实时推荐响应 */
+export interface GetRealtimeRecommendResponse {
+  code?: number;
+  message?: string;
+  data?: RecommendProduct[];
+}
+
+/** 获取消息列表请求 */
+export interface GetMessageListRequest {
+  userId?: Int64;
+  /** 0-全部 */
+  type?: number;
+  page?: number;
+  pageSize?: number;
+}
+
+/** 获取消息列表响应 */
+export interface GetMessageListResponse {
+  code?: number;
+  message?: string;
+  data?: Message[];
+  total?: number;
+}
+
+/** 消息信息 */
+export interface Message {
+  id?: Int64;
+  userId?: Int64;
+  type?: number;
+  title?: string;
+  content?: string;
+  link?: string;
+  isRead?: number;
+  createdAt?: string;
+}
+
+/** 发送消息请求 */
+export interface SendMessageRequest {
+  userId?: Int64;
+  type?: number;
+  title?: string;
+  content?: string;
+  link?: string;
+}
+
+/** 发送消息响应 */
+export interface SendMessageResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 标记消息已读请求 */
+export interface MarkAsReadRequest {
+  userId?: Int64;
+  messageId?: Int64;
+}
+
+/** 标记消息已读响应 */
+export interface MarkAsReadResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 批量标记已读请求 */
+export interface BatchMarkAsReadRequest {
+  userId?: Int64;
+  messageIds?: Int64[];
+}
+
+/** 批量标记已读响应 */
+export interface BatchMarkAsReadResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取未读消息数量请求 */
+export interface GetUnreadCountRequest {
+  userId?: Int64;
+}
+
+/** 获取未读消息数量响应 */
+export interface GetUnreadCountResponse {
+  code?: number;
+  message?: string;
+  count?: number;
+}
+
+/** 订单超时取消请求 */
+export interface CancelExpiredOrdersRequest {
+  /** 超时分钟数 */
+  timeoutMinutes?: number;
+}
+
+/** 订单超时取消响应 */
+export interface CancelExpiredOrdersResponse {
+  code?: number;
+  message?: string;
+  cancelledCount?: number;
+}
+
+/** 优惠券过期处理请求 */
+export type ProcessExpiredCouponsRequest = Record<string, never>;
+
+/** 优惠券过期处理响应 */
+export interface ProcessExpiredCouponsResponse {
+  code?: number;
+  message?: string;
+  expiredCount?: number;
+}
+
+/** 数据统计请求 */
+export interface GenerateStatisticsRequest {
+  /** 日期 YYYY-MM-DD */
+  date?: string;
+}
+
+/** 数据统计响应 */
+export interface GenerateStatisticsResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 删除文件请求 */
+export interface DeleteFileRequest {
+  fileId?: string;
+}
+
+/** 删除文件响应 */
+export interface DeleteFileResponse {
+  code?: number;
+  message?: string;
+}
+
+/** 获取文件URL请求：签名绑定的用户取自登录令牌，有效期由服务端决定 */
+export interface GetFileURLRequest {
+  fileId?: string;
+}
+
+/** 获取文件URL响应 */
+export interface GetFileURLResponse {
+  code?: number;
+  message?: string;
+  fileUrl?: string;
+  /** 签名过期时间（Unix 秒），公开文件为 0 */
+  expiresAt?: Int64;
+}
+
+/**
+ * 用户注册
+ *
+ * `POST /api/v1/user/register` → user.v1.UserService/Register（免登录）
+ */
+export async function register(req: RegisterRequest = {}, config?: AxiosRequestConfig): Promise<RegisterResponse> {
+  const { data } = await apiClient.post<RegisterResponse>("/api/v1/user/register", req, config);
+  return data;
+}
+
+/**
+ * 用户登录
+ *
+ * `POST /api/v1/user/login` → user.v1.UserService/Login（免登录）
+ */
+export async function login(req: LoginRequest = {}, config?: AxiosRequestConfig): Promise<LoginResponse> {
+  const { data } = await apiClient.post<LoginResponse>("/api/v1/user/login", req, config);
+  return data;
+}
+
+/**
+ * 刷新令牌：用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+ *
+ * `POST /api/v1/user/token/refresh` → user.v1.UserService/RefreshToken（免登录）
+ */
+export async function refreshToken(req: RefreshTokenRequest = {}, config?: AxiosRequestConfig): Promise<RefreshTokenResponse> {
+  const { data } = await apiClient.post<RefreshTokenResponse>("/api/v1/user/token/refresh", req, config);
+  return data;
+}
+
+/**
+ * 登出：吊销当前访问令牌和对应的刷新令牌
+ *
+ * `POST /api/v1/user/logout` → user.v1.UserService/Logout
+ */
+export async function logout(req: LogoutRequest = {}, config?: AxiosRequestConfig): Promise<LogoutResponse> {
+  const { data } = await apiClient.post<LogoutResponse>("/api/v1/user/logout", req, config);
+  return data;
+}
+
+/**
+ * 退出所有设备：吊销当前用户的全部令牌
+ *
+ * `POST /api/v1/user/sessions/revoke-all` → user.v1.UserService/RevokeAllSessions
+ */
+export async function revokeAllSessions(req: RevokeAllSessionsRequest = {}, config?: AxiosRequestConfig): Promise<RevokeAllSessionsResponse> {
+  const { data } = await apiClient.post<RevokeAllSessionsResponse>("/api/v1/user/sessions/revoke-all", req, config);
+  return data;
+}
+
+/**
+ * 我的登录设备（仍有效的登录会话）
+ *
+ * `GET /api/v1/user/sessions` → user.v1.UserService/ListMySessions
+ */
+export async function listMySessions(req: ListMySessionsRequest = {}, config?: AxiosRequestConfig): Promise<ListMySessionsResponse> {
+  const { data } = await apiClient.get<ListMySessionsResponse>("/api/v1/user/sessions", config);
+  return data;
+}
+
+/**
+ * 下线一个登录设备
+ *
+ * `DELETE /api/v1/user/sessions/{session_id}` → user.v1.UserService/RevokeSession
+ */
+export async function revokeSession(req: RevokeSessionRequest, config?: AxiosRequestConfig): Promise<RevokeSessionResponse> {
+  const { data } = await apiClient.delete<RevokeSessionResponse>(`/api/v1/user/sessions/${pathParam(req.sessionId)}`, config);
+  return data;
+}
+
+/**
+ * 发送短信/邮件验证码
+ *
+ * `POST /api/v1/user/verify-code/send` → user.v1.UserService/SendVerifyCode（免登录）
+ */
+export async function sendVerifyCode(req: SendVerifyCodeRequest = {}, config?: AxiosRequestConfig): Promise<SendVerifyCodeResponse> {
+  const { data } = await apiClient.post<SendVerifyCodeResponse>("/api/v1/user/verify-code/send", req, config);
+  return data;
+}
+
+/**
+ * 校验验证码（不作废，供分步表单提前校验）
+ *
+ * `POST /api/v1/user/verify-code/check` → user.v1.UserService/CheckVerifyCode（免登录）
+ */
+export async function checkVerifyCode(req: CheckVerifyCodeRequest = {}, config?: AxiosRequestConfig): Promise<CheckVerifyCodeResponse> {
+  const { data } = await apiClient.post<CheckVerifyCodeResponse>("/api/v1/user/verify-code/check", req, config);
+  return data;
+}
+
+/**
+ * 更换手机号（需要新手机号的验证码）
+ *
+ * `PUT /api/v1/user/phone` → user.v1.UserService/ChangePhone
+ */
+export async function changePhone(req: ChangePhoneRequest = {}, config?: AxiosRequestConfig): Promise<ChangePhoneResponse> {
+  const { data } = await apiClient.put<ChangePhoneResponse>("/api/v1/user/phone", req, config);
+  return data;
+}
+
+/**
+ * 申请重置密码：向手机号/邮箱发送一次性重置链接，账号不存在时同样返回成功
+ *
+ * `POST /api/v1/user/password/reset-request` → user.v1.UserService/RequestPasswordReset（免登录）
+ */
+export async function requestPasswordReset(req: RequestPasswordResetRequest = {}, config?: AxiosRequestConfig): Promise<RequestPasswordResetResponse> {
+  const { data } = await apiClient.post<RequestPasswordResetResponse>("/api/v1/user/password/reset-request", req, config);
+  return data;
+}
+
+/**
+ * 重置密码：校验重置 token 后设置新密码，并退出所有设备
+ *
+ * `POST /api/v1/user/password/reset` → user.v1.UserService/ResetPassword（免登录）
+ */
+export async function resetPassword(req: ResetPasswordRequest = {}, config?: AxiosRequestConfig): Promise<ResetPasswordResponse> {
+  const { data } = await apiClient.post<ResetPasswordResponse>("/api/v1/user/password/reset", req, config);
+  return data;
+}
+
+/**
+ * 修改密码（需要登录）：其他设备退出登录，当前设备返回新令牌
+ *
+ * `PUT /api/v1/user/password` → user.v1.UserService/ChangePassword
+ */
+export async function changePassword(req: ChangePasswordRequest = {}, config?: AxiosRequestConfig): Promise<ChangePasswordResponse> {
+  const { data } = await apiClient.put<ChangePasswordResponse>("/api/v1/user/password", req, config);
+  return data;
+}
+
+/**
+ * 获取会员等级定义
+ *
+ * `GET /api/v1/member/tiers` → user.v1.UserService/ListMemberTiers（免登录）
+ */
+export async function listMemberTiers(req: ListMemberTiersRequest = {}, config?: AxiosRequestConfig): Promise<ListMemberTiersResponse> {
+  const { data } = await apiClient.get<ListMemberTiersResponse>("/api/v1/member/tiers", config);
+  return data;
+}
+
+/**
+ * 获取会员等级和权益（已登录取当前用户，内部调用按 user_id 查询）
+ *
+ * `GET /api/v1/user/member` → user.v1.UserService/GetMemberBenefits
+ */
+export async function getMemberBenefits(req: GetMemberBenefitsRequest = {}, config?: AxiosRequestConfig): Promise<GetMemberBenefitsResponse> {
+  const { data } = await apiClient.get<GetMemberBenefitsResponse>("/api/v1/user/member", {
+    ...config,
+    params: {
+      user_id: req.userId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取会员等级变更记录（需要登录）
+ *
+ * `GET /api/v1/user/member/level-logs` → user.v1.UserService/ListMemberLevelLogs
+ */
+export async function listMemberLevelLogs(req: ListMemberLevelLogsRequest = {}, config?: AxiosRequestConfig): Promise<ListMemberLevelLogsResponse> {
+  const { data } = await apiClient.get<ListMemberLevelLogsResponse>("/api/v1/user/member/level-logs", {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 申请导出个人数据（需要登录），异步打包后通过 GetDataRequest 获取下载地址
+ *
+ * `POST /api/v1/user/data-export` → user.v1.UserService/ExportMyData
+ */
+export async function exportMyData(req: ExportMyDataRequest = {}, config?: AxiosRequestConfig): Promise<ExportMyDataResponse> {
+  const { data } = await apiClient.post<ExportMyDataResponse>("/api/v1/user/data-export", req, config);
+  return data;
+}
+
+/**
+ * 注销账号（需要登录），立即停用账号并异步删除或匿名化各服务中的个人数据
+ *
+ * `POST /api/v1/user/account/erase` → user.v1.UserService/EraseMyAccount
+ */
+export async function eraseMyAccount(req: EraseMyAccountRequest = {}, config?: AxiosRequestConfig): Promise<EraseMyAccountResponse> {
+  const { data } = await apiClient.post<EraseMyAccountResponse>("/api/v1/user/account/erase", req, config);
+  return data;
+}
+
+/**
+ * 获取我的个人数据请求列表（需要登录）
+ *
+ * `GET /api/v1/user/data-requests` → user.v1.UserService/ListMyDataRequests
+ */
+export async function listMyDataRequests(req: ListMyDataRequestsRequest = {}, config?: AxiosRequestConfig): Promise<ListMyDataRequestsResponse> {
+  const { data } = await apiClient.get<ListMyDataRequestsResponse>("/api/v1/user/data-requests", {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取个人数据请求详情和进度（需要登录）
+ *
+ * `GET /api/v1/user/data-requests/{request_no}` → user.v1.UserService/GetDataRequest
+ */
+export async function getDataRequest(req: GetDataRequestRequest, config?: AxiosRequestConfig): Promise<GetDataRequestResponse> {
+  const { data } = await apiClient.get<GetDataRequestResponse>(`/api/v1/user/data-requests/${pathParam(req.requestNo)}`, config);
+  return data;
+}
+
+/**
+ * 获取可用的第三方登录方式
+ *
+ * `GET /api/v1/oauth/providers` → user.v1.UserService/ListOAuthProviders（免登录）
+ */
+export async function listOAuthProviders(req: ListOAuthProvidersRequest = {}, config?: AxiosRequestConfig): Promise<ListOAuthProvidersResponse> {
+  const { data } = await apiClient.get<ListOAuthProvidersResponse>("/api/v1/oauth/providers", config);
+  return data;
+}
+
+/**
+ * 获取第三方授权地址（link=true 时为已登录用户绑定）
+ *
+ * `GET /api/v1/oauth/{provider}/auth-url` → user.v1.UserService/GetOAuthAuthURL（免登录）
+ */
+export async function getOAuthAuthURL(req: GetOAuthAuthURLRequest, config?: AxiosRequestConfig): Promise<GetOAuthAuthURLResponse> {
+  const { data } = await apiClient.get<GetOAuthAuthURLResponse>(`/api/v1/oauth/${pathParam(req.provider)}/auth-url`, {
+    ...config,
+    params: {
+      link: req.link,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 第三方登录：用授权回调的 code/state 登录，未绑定时自动注册
+ *
+ * `POST /api/v1/oauth/{provider}/login` → user.v1.UserService/OAuthLogin（免登录）
+ */
+export async function oAuthLogin(req: OAuthLoginRequest, config?: AxiosRequestConfig): Promise<OAuthLoginResponse> {
+  const { data } = await apiClient.post<OAuthLoginResponse>(`/api/v1/oauth/${pathParam(req.provider)}/login`, req, config);
+  return data;
+}
+
+/**
+ * 获取已绑定的第三方账号（需要登录）
+ *
+ * `GET /api/v1/user/oauth/identities` → user.v1.UserService/ListOAuthIdentities
+ */
+export async function listOAuthIdentities(req: ListOAuthIdentitiesRequest = {}, config?: AxiosRequestConfig): Promise<ListOAuthIdentitiesResponse> {
+  const { data } = await apiClient.get<ListOAuthIdentitiesResponse>("/api/v1/user/oauth/identities", config);
+  return data;
+}
+
+/**
+ * 绑定第三方账号（需要登录）
+ *
+ * `POST /api/v1/user/oauth/identities/{provider}` → user.v1.UserService/LinkOAuthIdentity
+ */
+export async function linkOAuthIdentity(req: LinkOAuthIdentityRequest, config?: AxiosRequestConfig): Promise<LinkOAuthIdentityResponse> {
+  const { data } = await apiClient.post<LinkOAuthIdentityResponse>(`/api/v1/user/oauth/identities/${pathParam(req.provider)}`, req, config);
+  return data;
+}
+
+/**
+ * 解绑第三方账号（需要登录）
+ *
+ * `DELETE /api/v1/user/oauth/identities/{provider}` → user.v1.UserService/UnlinkOAuthIdentity
+ */
+export async function unlinkOAuthIdentity(req: UnlinkOAuthIdentityRequest, config?: AxiosRequestConfig): Promise<UnlinkOAuthIdentityResponse> {
+  const { data } = await apiClient.delete<UnlinkOAuthIdentityResponse>(`/api/v1/user/oauth/identities/${pathParam(req.provider)}`, config);
+  return data;
+}
+
+/**
+ * 登录第二步：校验两步验证码或恢复码，完成登录
+ *
+ * `POST /api/v1/user/mfa/verify` → user.v1.UserService/VerifyMFA（免登录）
+ */
+export async function verifyMFA(req: VerifyMFARequest = {}, config?: AxiosRequestConfig): Promise<VerifyMFAResponse> {
+  const { data } = await apiClient.post<VerifyMFAResponse>("/api/v1/user/mfa/verify", req, config);
+  return data;
+}
+
+/**
+ * 获取两步验证状态（需要登录）
+ *
+ * `GET /api/v1/user/mfa` → user.v1.UserService/GetMFAStatus
+ */
+export async function getMFAStatus(req: GetMFAStatusRequest = {}, config?: AxiosRequestConfig): Promise<GetMFAStatusResponse> {
+  const { data } = await apiClient.get<GetMFAStatusResponse>("/api/v1/user/mfa", config);
+  return data;
+}
+
+/**
+ * 生成 TOTP 密钥：已登录用户，或登录过程中角色要求开启时带 mfa_token
+ *
+ * `POST /api/v1/user/mfa/totp/setup` → user.v1.UserService/SetupTOTP（免登录）
+ */
+export async function setupTOTP(req: SetupTOTPRequest = {}, config?: AxiosRequestConfig): Promise<SetupTOTPResponse> {
+  const { data } = await apiClient.post<SetupTOTPResponse>("/api/v1/user/mfa/totp/setup", req, config);
+  return data;
+}
+
+/**
+ * 确认绑定 TOTP 并返回恢复码（需要登录）
+ *
+ * `POST /api/v1/user/mfa/totp/confirm` → user.v1.UserService/ConfirmTOTP
+ */
+export async function confirmTOTP(req: ConfirmTOTPRequest = {}, config?: AxiosRequestConfig): Promise<ConfirmTOTPResponse> {
+  const { data } = await apiClient.post<ConfirmTOTPResponse>("/api/v1/user/mfa/totp/confirm", req, config);
+  return data;
+}
+
+/**
+ * 关闭两步验证（需要登录，角色要求开启时不允许）
+ *
+ * `POST /api/v1/user/mfa/totp/disable` → user.v1.UserService/DisableTOTP
+ */
+export async function disableTOTP(req: DisableTOTPRequest = {}, config?: AxiosRequestConfig): Promise<DisableTOTPResponse> {
+  const { data } = await apiClient.post<DisableTOTPResponse>("/api/v1/user/mfa/totp/disable", req, config);
+  return data;
+}
+
+/**
+ * 重新生成恢复码（需要登录）
+ *
+ * `POST /api/v1/user/mfa/recovery-codes` → user.v1.UserService/RegenerateRecoveryCodes
+ */
+export async function regenerateRecoveryCodes(req: RegenerateRecoveryCodesRequest = {}, config?: AxiosRequestConfig): Promise<RegenerateRecoveryCodesResponse> {
+  const { data } = await apiClient.post<RegenerateRecoveryCodesResponse>("/api/v1/user/mfa/recovery-codes", req, config);
+  return data;
+}
+
+/**
+ * 获取用户信息
+ *
+ * `GET /api/v1/user/info` → user.v1.UserService/GetUserInfo
+ */
+export async function getUserInfo(req: GetUserInfoRequest = {}, config?: AxiosRequestConfig): Promise<GetUserInfoResponse> {
+  const { data } = await apiClient.get<GetUserInfoResponse>("/api/v1/user/info", {
+    ...config,
+    params: {
+      user_id: req.userId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 更新用户信息
+ *
+ * `PUT /api/v1/user/info` → user.v1.UserService/UpdateUserInfo
+ */
+export async function updateUserInfo(req: UpdateUserInfoRequest = {}, config?: AxiosRequestConfig): Promise<UpdateUserInfoResponse> {
+  const { data } = await apiClient.put<UpdateUserInfoResponse>("/api/v1/user/info", req, config);
+  return data;
+}
+
+/**
+ * 获取用户列表（管理后台）
+ *
+ * `GET /api/v1/users` → user.v1.UserService/ListUsers
+ */
+export async function listUsers(req: ListUsersRequest = {}, config?: AxiosRequestConfig): Promise<ListUsersResponse> {
+  const { data } = await apiClient.get<ListUsersResponse>("/api/v1/users", {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+      keyword: req.keyword,
+      status: req.status,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 删除用户（管理后台），与注销账号相同：停用账号并异步删除或匿名化各服务中的个人数据
+ *
+ * `DELETE /api/v1/users/{id}` → user.v1.UserService/DeleteUser
+ */
+export async function deleteUser(req: DeleteUserRequest, config?: AxiosRequestConfig): Promise<DeleteUserResponse> {
+  const { data } = await apiClient.delete<DeleteUserResponse>(`/api/v1/users/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 解除登录失败导致的账号锁定（管理后台）
+ *
+ * `POST /api/v1/users/{user_id}/unlock` → user.v1.UserService/UnlockUser
+ */
+export async function unlockUser(req: UnlockUserRequest, config?: AxiosRequestConfig): Promise<UnlockUserResponse> {
+  const { data } = await apiClient.post<UnlockUserResponse>(`/api/v1/users/${pathParam(req.userId)}/unlock`, req, config);
+  return data;
+}
+
+/**
+ * 查看用户的登录会话（管理后台）
+ *
+ * `GET /api/v1/users/{user_id}/sessions` → user.v1.UserService/ListUserSessions
+ */
+export async function listUserSessions(req: ListUserSessionsRequest, config?: AxiosRequestConfig): Promise<ListUserSessionsResponse> {
+  const { data } = await apiClient.get<ListUserSessionsResponse>(`/api/v1/users/${pathParam(req.userId)}/sessions`, {
+    ...config,
+    params: {
+      include_inactive: req.includeInactive,
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 强制下线用户的一个登录会话（管理后台）
+ *
+ * `DELETE /api/v1/users/{user_id}/sessions/{session_id}` → user.v1.UserService/RevokeUserSession
+ */
+export async function revokeUserSession(req: RevokeUserSessionRequest, config?: AxiosRequestConfig): Promise<RevokeUserSessionResponse> {
+  const { data } = await apiClient.delete<RevokeUserSessionResponse>(`/api/v1/users/${pathParam(req.userId)}/sessions/${pathParam(req.sessionId)}`, config);
+  return data;
+}
+
+/**
+ * 立即执行一次会员等级评估（管理后台）
+ *
+ * `POST /api/v1/member-levels/evaluate` → user.v1.UserService/EvaluateMemberLevels
+ */
+export async function evaluateMemberLevels(req: EvaluateMemberLevelsRequest = {}, config?: AxiosRequestConfig): Promise<EvaluateMemberLevelsResponse> {
+  const { data } = await apiClient.post<EvaluateMemberLevelsResponse>("/api/v1/member-levels/evaluate", req, config);
+  return data;
+}
+
+/**
+ * 获取个人数据请求列表（管理后台）
+ *
+ * `GET /api/v1/data-requests` → user.v1.UserService/ListDataRequests
+ */
+export async function listDataRequests(req: ListDataRequestsRequest = {}, config?: AxiosRequestConfig): Promise<ListDataRequestsResponse> {
+  const { data } = await apiClient.get<ListDataRequestsResponse>("/api/v1/data-requests", {
+    ...config,
+    params: {
+      user_id: req.userId,
+      type: req.type,
+      status: req.status,
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 重新执行失败的个人数据请求（管理后台），已完成的服务不会重复处理
+ *
+ * `POST /api/v1/data-requests/{request_no}/retry` → user.v1.UserService/RetryDataRequest
+ */
+export async function retryDataRequest(req: RetryDataRequestRequest, config?: AxiosRequestConfig): Promise<RetryDataRequestResponse> {
+  const { data } = await apiClient.post<RetryDataRequestResponse>(`/api/v1/data-requests/${pathParam(req.requestNo)}/retry`, req, config);
+  return data;
+}
+
+/**
+ * 获取全部角色
+ *
+ * `GET /api/v1/roles` → user.v1.RoleService/ListRoles
+ */
+export async function listRoles(req: ListRolesRequest = {}, config?: AxiosRequestConfig): Promise<ListRolesResponse> {
+  const { data } = await apiClient.get<ListRolesResponse>("/api/v1/roles", config);
+  return data;
+}
+
+/**
+ * 获取用户的角色
+ *
+ * `GET /api/v1/users/{user_id}/roles` → user.v1.RoleService/GetUserRoles
+ */
+export async function getUserRoles(req: GetUserRolesRequest, config?: AxiosRequestConfig): Promise<GetUserRolesResponse> {
+  const { data } = await apiClient.get<GetUserRolesResponse>(`/api/v1/users/${pathParam(req.userId)}/roles`, config);
+  return data;
+}
+
+/**
+ * 给用户分配角色
+ *
+ * `POST /api/v1/users/{user_id}/roles` → user.v1.RoleService/AssignUserRole
+ */
+export async function assignUserRole(req: AssignUserRoleRequest, config?: AxiosRequestConfig): Promise<AssignUserRoleResponse> {
+  const { data } = await apiClient.post<AssignUserRoleResponse>(`/api/v1/users/${pathParam(req.userId)}/roles`, req, config);
+  return data;
+}
+
+/**
+ * 移除用户角色
+ *
+ * `DELETE /api/v1/users/{user_id}/roles/{role_code}` → user.v1.RoleService/RemoveUserRole
+ */
+export async function removeUserRole(req: RemoveUserRoleRequest, config?: AxiosRequestConfig): Promise<RemoveUserRoleResponse> {
+  const { data } = await apiClient.delete<RemoveUserRoleResponse>(`/api/v1/users/${pathParam(req.userId)}/roles/${pathParam(req.roleCode)}`, config);
+  return data;
+}
+
+/**
+ * 获取用户地址列表
+ *
+ * `GET /api/v1/user/address` → user.v1.UserService/GetAddressList
+ */
+export async function getAddressList(req: GetAddressListRequest = {}, config?: AxiosRequestConfig): Promise<GetAddressListResponse> {
+  const { data } = await apiClient.get<GetAddressListResponse>("/api/v1/user/address", {
+    ...config,
+    params: {
+      user_id: req.userId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 添加地址
+ *
+ * `POST /api/v1/user/address` → user.v1.UserService/AddAddress
+ */
+export async function addAddress(req: AddAddressRequest = {}, config?: AxiosRequestConfig): Promise<AddAddressResponse> {
+  const { data } = await apiClient.post<AddAddressResponse>("/api/v1/user/address", req, config);
+  return data;
+}
+
+/**
+ * 更新地址
+ *
+ * `PUT /api/v1/user/address/{id}` → user.v1.UserService/UpdateAddress
+ */
+export async function updateAddress(req: UpdateAddressRequest, config?: AxiosRequestConfig): Promise<UpdateAddressResponse> {
+  const { data } = await apiClient.put<UpdateAddressResponse>(`/api/v1/user/address/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除地址
+ *
+ * `DELETE /api/v1/user/address/{id}` → user.v1.UserService/DeleteAddress
+ */
+export async function deleteAddress(req: DeleteAddressRequest, config?: AxiosRequestConfig): Promise<DeleteAddressResponse> {
+  const { data } = await apiClient.delete<DeleteAddressResponse>(`/api/v1/user/address/${pathParam(req.id)}`, {
+    ...config,
+    params: {
+      user_id: req.userId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取行政区划（省市区级联选择），parent_code 为空时返回省级列表
+ *
+ * `GET /api/v1/regions` → user.v1.UserService/GetRegions（免登录）
+ */
+export async function getRegions(req: GetRegionsRequest = {}, config?: AxiosRequestConfig): Promise<GetRegionsResponse> {
+  const { data } = await apiClient.get<GetRegionsResponse>("/api/v1/regions", {
+    ...config,
+    params: {
+      parent_code: req.parentCode,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 签发 API Key（secret 只在响应中返回一次）
+ *
+ * `POST /api/v1/open/keys` → user.v1.ApiKeyService/CreateApiKey
+ */
+export async function createApiKey(req: CreateApiKeyRequest = {}, config?: AxiosRequestConfig): Promise<CreateApiKeyResponse> {
+  const { data } = await apiClient.post<CreateApiKeyResponse>("/api/v1/open/keys", req, config);
+  return data;
+}
+
+/**
+ * 获取 API Key 列表
+ *
+ * `GET /api/v1/open/keys` → user.v1.ApiKeyService/ListApiKeys
+ */
+export async function listApiKeys(req: ListApiKeysRequest = {}, config?: AxiosRequestConfig): Promise<ListApiKeysResponse> {
+  const { data } = await apiClient.get<ListApiKeysResponse>("/api/v1/open/keys", {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 更新名称、权限范围和限流
+ *
+ * `PUT /api/v1/open/keys/{key_id}` → user.v1.ApiKeyService/UpdateApiKey
+ */
+export async function updateApiKey(req: UpdateApiKeyRequest, config?: AxiosRequestConfig): Promise<UpdateApiKeyResponse> {
+  const { data } = await apiClient.put<UpdateApiKeyResponse>(`/api/v1/open/keys/${pathParam(req.keyId)}`, req, config);
+  return data;
+}
+
+/**
+ * 吊销 API Key
+ *
+ * `DELETE /api/v1/open/keys/{key_id}` → user.v1.ApiKeyService/RevokeApiKey
+ */
+export async function revokeApiKey(req: RevokeApiKeyRequest, config?: AxiosRequestConfig): Promise<RevokeApiKeyResponse> {
+  const { data } = await apiClient.delete<RevokeApiKeyResponse>(`/api/v1/open/keys/${pathParam(req.keyId)}`, config);
+  return data;
+}
+
+/**
+ * 轮换 secret，旧 secret 在宽限期内仍然有效
+ *
+ * `POST /api/v1/open/keys/{key_id}/rotate` → user.v1.ApiKeyService/RotateApiKey
+ */
+export async function rotateApiKey(req: RotateApiKeyRequest, config?: AxiosRequestConfig): Promise<RotateApiKeyResponse> {
+  const { data } = await apiClient.post<RotateApiKeyResponse>(`/api/v1/open/keys/${pathParam(req.keyId)}/rotate`, req, config);
+  return data;
+}
+
+/**
+ * 查询按天统计的调用量
+ *
+ * `GET /api/v1/open/keys/{key_id}/usage` → user.v1.ApiKeyService/GetApiKeyUsage
+ */
+export async function getApiKeyUsage(req: GetApiKeyUsageRequest, config?: AxiosRequestConfig): Promise<GetApiKeyUsageResponse> {
+  const { data } = await apiClient.get<GetApiKeyUsageResponse>(`/api/v1/open/keys/${pathParam(req.keyId)}/usage`, {
+    ...config,
+    params: {
+      days: req.days,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 查询调用审计日志
+ *
+ * `GET /api/v1/open/keys/{key_id}/logs` → user.v1.ApiKeyService/ListApiKeyAuditLogs
+ */
+export async function listApiKeyAuditLogs(req: ListApiKeyAuditLogsRequest, config?: AxiosRequestConfig): Promise<ListApiKeyAuditLogsResponse> {
+  const { data } = await apiClient.get<ListApiKeyAuditLogsResponse>(`/api/v1/open/keys/${pathParam(req.keyId)}/logs`, {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取商品列表
+ *
+ * `GET /api/v1/products` → product.v1.ProductService/ListProducts（免登录）
+ */
+export async function listProducts(req: ListProductsRequest = {}, config?: AxiosRequestConfig): Promise<ListProductsResponse> {
+  const { data } = await apiClient.get<ListProductsResponse>("/api/v1/products", {
+    ...config,
+    params: {
+      category_id: req.categoryId,
+      brand_id: req.brandId,
+      keyword: req.keyword,
+      status: req.status,
+      page: req.page,
+      page_size: req.pageSize,
+      sort: req.sort,
+      is_hot: req.isHot,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 创建商品（管理后台）
+ *
+ * `POST /api/v1/products` → product.v1.ProductService/CreateProduct
+ */
+export async function createProduct(req: CreateProductRequest = {}, config?: AxiosRequestConfig): Promise<CreateProductResponse> {
+  const { data } = await apiClient.post<CreateProductResponse>("/api/v1/products", req, config);
+  return data;
+}
+
+/**
+ * 获取商品详情
+ *
+ * `GET /api/v1/products/{id}` → product.v1.ProductService/GetProduct（免登录）
+ */
+export async function getProduct(req: GetProductRequest, config?: AxiosRequestConfig): Promise<GetProductResponse> {
+  const { data } = await apiClient.get<GetProductResponse>(`/api/v1/products/${pathParam(req.id)}`, {
+    ...config,
+    params: {
+      spu_code: req.spuCode,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 更新商品（管理后台）
+ *
+ * `PUT /api/v1/products/{id}` → product.v1.ProductService/UpdateProduct
+ */
+export async function updateProduct(req: UpdateProductRequest, config?: AxiosRequestConfig): Promise<UpdateProductResponse> {
+  const { data } = await apiClient.put<UpdateProductResponse>(`/api/v1/products/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除商品（管理后台）
+ *
+ * `DELETE /api/v1/products/{id}` → product.v1.ProductService/DeleteProduct
+ */
+export async function deleteProduct(req: DeleteProductRequest, config?: AxiosRequestConfig): Promise<DeleteProductResponse> {
+  const { data } = await apiClient.delete<DeleteProductResponse>(`/api/v1/products/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 获取SKU列表（管理后台）
+ *
+ * `GET /api/v1/skus` → product.v1.ProductService/ListSkus（免登录）
+ */
+export async function listSkus(req: ListSkusRequest = {}, config?: AxiosRequestConfig): Promise<ListSkusResponse> {
+  const { data } = await apiClient.get<ListSkusResponse>("/api/v1/skus", {
+    ...config,
+    params: {
+      product_id: req.productId,
+      status: req.status,
+      page: req.page,
+      page_size: req.pageSize,
+      keyword: req.keyword,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 创建SKU（管理后台）
+ *
+ * `POST /api/v1/skus` → product.v1.ProductService/CreateSku
+ */
+export async function createSku(req: CreateSkuRequest = {}, config?: AxiosRequestConfig): Promise<CreateSkuResponse> {
+  const { data } = await apiClient.post<CreateSkuResponse>("/api/v1/skus", req, config);
+  return data;
+}
+
+/**
+ * 获取SKU详情
+ *
+ * `GET /api/v1/skus/{id}` → product.v1.ProductService/GetSku（免登录）
+ */
+export async function getSku(req: GetSkuRequest, config?: AxiosRequestConfig): Promise<GetSkuResponse> {
+  const { data } = await apiClient.get<GetSkuResponse>(`/api/v1/skus/${pathParam(req.id)}`, {
+    ...config,
+    params: {
+      sku_code: req.skuCode,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 更新SKU（管理后台）
+ *
+ * `PUT /api/v1/skus/{id}` → product.v1.ProductService/UpdateSku
+ */
+export async function updateSku(req: UpdateSkuRequest, config?: AxiosRequestConfig): Promise<UpdateSkuResponse> {
+  const { data } = await apiClient.put<UpdateSkuResponse>(`/api/v1/skus/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除SKU（管理后台）
+ *
+ * `DELETE /api/v1/skus/{id}` → product.v1.ProductService/DeleteSku
+ */
+export async function deleteSku(req: DeleteSkuRequest, config?: AxiosRequestConfig): Promise<DeleteSkuResponse> {
+  const { data } = await apiClient.delete<DeleteSkuResponse>(`/api/v1/skus/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 获取类目列表
+ *
+ * `GET /api/v1/categories` → product.v1.ProductService/GetCategoryList（免登录）
+ */
+export async function getCategoryList(req: GetCategoryListRequest = {}, config?: AxiosRequestConfig): Promise<GetCategoryListResponse> {
+  const { data } = await apiClient.get<GetCategoryListResponse>("/api/v1/categories", {
+    ...config,
+    params: {
+      parent_id: req.parentId,
+      level: req.level,
+      status: req.status,
+      keyword: req.keyword,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 创建类目（管理后台）
+ *
+ * `POST /api/v1/categories` → product.v1.ProductService/CreateCategory
+ */
+export async function createCategory(req: CreateCategoryRequest = {}, config?: AxiosRequestConfig): Promise<CreateCategoryResponse> {
+  const { data } = await apiClient.post<CreateCategoryResponse>("/api/v1/categories", req, config);
+  return data;
+}
+
+/**
+ * 获取类目树
+ *
+ * `GET /api/v1/categories/tree` → product.v1.ProductService/GetCategoryTree（免登录）
+ */
+export async function getCategoryTree(req: GetCategoryTreeRequest = {}, config?: AxiosRequestConfig): Promise<GetCategoryTreeResponse> {
+  const { data } = await apiClient.get<GetCategoryTreeResponse>("/api/v1/categories/tree", {
+    ...config,
+    params: {
+      status: req.status,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取类目详情
+ *
+ * `GET /api/v1/categories/{id}` → product.v1.ProductService/GetCategory（免登录）
+ */
+export async function getCategory(req: GetCategoryRequest, config?: AxiosRequestConfig): Promise<GetCategoryResponse> {
+  const { data } = await apiClient.get<GetCategoryResponse>(`/api/v1/categories/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 更新类目（管理后台）
+ *
+ * `PUT /api/v1/categories/{id}` → product.v1.ProductService/UpdateCategory
+ */
+export async function updateCategory(req: UpdateCategoryRequest, config?: AxiosRequestConfig): Promise<UpdateCategoryResponse> {
+  const { data } = await apiClient.put<UpdateCategoryResponse>(`/api/v1/categories/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除类目（管理后台）
+ *
+ * `DELETE /api/v1/categories/{id}` → product.v1.ProductService/DeleteCategory
+ */
+export async function deleteCategory(req: DeleteCategoryRequest, config?: AxiosRequestConfig): Promise<DeleteCategoryResponse> {
+  const { data } = await apiClient.delete<DeleteCategoryResponse>(`/api/v1/categories/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 获取Banner列表
+ *
+ * `GET /api/v1/banners` → product.v1.ProductService/ListBanners（免登录）
+ */
+export async function listBanners(req: ListBannersRequest = {}, config?: AxiosRequestConfig): Promise<ListBannersResponse> {
+  const { data } = await apiClient.get<ListBannersResponse>("/api/v1/banners", {
+    ...config,
+    params: {
+      status: req.status,
+      limit: req.limit,
+      keyword: req.keyword,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取Banner详情
+ *
+ * `GET /api/v1/banners/{id}` → product.v1.ProductService/GetBanner（免登录）
+ */
+export async function getBanner(req: GetBannerRequest, config?: AxiosRequestConfig): Promise<GetBannerResponse> {
+  const { data } = await apiClient.get<GetBannerResponse>(`/api/v1/banners/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 创建Banner（管理后台）
+ *
+ * `POST /api/v1/banners` → product.v1.ProductService/CreateBanner
+ */
+export async function createBanner(req: CreateBannerRequest = {}, config?: AxiosRequestConfig): Promise<CreateBannerResponse> {
+  const { data } = await apiClient.post<CreateBannerResponse>("/api/v1/banners", req, config);
+  return data;
+}
+
+/**
+ * 更新Banner（管理后台）
+ *
+ * `PUT /api/v1/banners/{id}` → product.v1.ProductService/UpdateBanner
+ */
+export async function updateBanner(req: UpdateBannerRequest, config?: AxiosRequestConfig): Promise<UpdateBannerResponse> {
+  const { data } = await apiClient.put<UpdateBannerResponse>(`/api/v1/banners/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除Banner（管理后台）
+ *
+ * `DELETE /api/v1/banners/{id}` → product.v1.ProductService/DeleteBanner
+ */
+export async function deleteBanner(req: DeleteBannerRequest, config?: AxiosRequestConfig): Promise<DeleteBannerResponse> {
+  const { data } = await apiClient.delete<DeleteBannerResponse>(`/api/v1/banners/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 获取品牌列表
+ *
+ * `GET /api/v1/brands` → product.v1.ProductService/ListBrands（免登录）
+ */
+export async function listBrands(req: ListBrandsRequest = {}, config?: AxiosRequestConfig): Promise<ListBrandsResponse> {
+  const { data } = await apiClient.get<ListBrandsResponse>("/api/v1/brands", {
+    ...config,
+    params: {
+      status: req.status,
+      keyword: req.keyword,
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取品牌详情
+ *
+ * `GET /api/v1/brands/{id}` → product.v1.ProductService/GetBrand（免登录）
+ */
+export async function getBrand(req: GetBrandRequest, config?: AxiosRequestConfig): Promise<GetBrandResponse> {
+  const { data } = await apiClient.get<GetBrandResponse>(`/api/v1/brands/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 创建品牌（管理后台）
+ *
+ * `POST /api/v1/brands` → product.v1.ProductService/CreateBrand
+ */
+export async function createBrand(req: CreateBrandRequest = {}, config?: AxiosRequestConfig): Promise<CreateBrandResponse> {
+  const { data } = await apiClient.post<CreateBrandResponse>("/api/v1/brands", req, config);
+  return data;
+}
+
+/**
+ * 更新品牌（管理后台）
+ *
+ * `PUT /api/v1/brands/{id}` → product.v1.ProductService/UpdateBrand
+ */
+export async function updateBrand(req: UpdateBrandRequest, config?: AxiosRequestConfig): Promise<UpdateBrandResponse> {
+  const { data } = await apiClient.put<UpdateBrandResponse>(`/api/v1/brands/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除品牌（管理后台）
+ *
+ * `DELETE /api/v1/brands/{id}` → product.v1.ProductService/DeleteBrand
+ */
+export async function deleteBrand(req: DeleteBrandRequest, config?: AxiosRequestConfig): Promise<DeleteBrandResponse> {
+  const { data } = await apiClient.delete<DeleteBrandResponse>(`/api/v1/brands/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 获取类目属性模板（可包含从上级类目继承的属性）
+ *
+ * `GET /api/v1/categories/{category_id}/attrs` → product.v1.ProductService/ListCategoryAttrs（免登录）
+ */
+export async function listCategoryAttrs(req: ListCategoryAttrsRequest, config?: AxiosRequestConfig): Promise<ListCategoryAttrsResponse> {
+  const { data } = await apiClient.get<ListCategoryAttrsResponse>(`/api/v1/categories/${pathParam(req.categoryId)}/attrs`, {
+    ...config,
+    params: {
+      type: req.type,
+      include_inherited: req.includeInherited,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 创建类目属性（管理后台）
+ *
+ * `POST /api/v1/categories/{category_id}/attrs` → product.v1.ProductService/CreateAttr
+ */
+export async function createAttr(req: CreateAttrRequest, config?: AxiosRequestConfig): Promise<CreateAttrResponse> {
+  const { data } = await apiClient.post<CreateAttrResponse>(`/api/v1/categories/${pathParam(req.categoryId)}/attrs`, req, config);
+  return data;
+}
+
+/**
+ * 更新类目属性（管理后台）
+ *
+ * `PUT /api/v1/attrs/{id}` → product.v1.ProductService/UpdateAttr
+ */
+export async function updateAttr(req: UpdateAttrRequest, config?: AxiosRequestConfig): Promise<UpdateAttrResponse> {
+  const { data } = await apiClient.put<UpdateAttrResponse>(`/api/v1/attrs/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除类目属性（管理后台）
+ *
+ * `DELETE /api/v1/attrs/{id}` → product.v1.ProductService/DeleteAttr
+ */
+export async function deleteAttr(req: DeleteAttrRequest, config?: AxiosRequestConfig): Promise<DeleteAttrResponse> {
+  const { data } = await apiClient.delete<DeleteAttrResponse>(`/api/v1/attrs/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 按销售属性批量生成SKU（管理后台）
+ *
+ * `POST /api/v1/products/{product_id}/skus/generate` → product.v1.ProductService/GenerateSkus
+ */
+export async function generateSkus(req: GenerateSkusRequest, config?: AxiosRequestConfig): Promise<GenerateSkusResponse> {
+  const { data } = await apiClient.post<GenerateSkusResponse>(`/api/v1/products/${pathParam(req.productId)}/skus/generate`, req, config);
+  return data;
+}
+
+/**
+ * 提交商品未通过的修订等待审核（管理后台）
+ *
+ * `POST /api/v1/products/{product_id}/revisions/submit` → product.v1.ProductService/SubmitProductRevision
+ */
+export async function submitProductRevision(req: SubmitProductRevisionRequest, config?: AxiosRequestConfig): Promise<ProductRevisionResponse> {
+  const { data } = await apiClient.post<ProductRevisionResponse>(`/api/v1/products/${pathParam(req.productId)}/revisions/submit`, req, config);
+  return data;
+}
+
+/**
+ * 获取商品审核日志（管理后台）
+ *
+ * `GET /api/v1/products/{product_id}/audit-logs` → product.v1.ProductService/ListProductAuditLogs
+ */
+export async function listProductAuditLogs(req: ListProductAuditLogsRequest, config?: AxiosRequestConfig): Promise<ListProductAuditLogsResponse> {
+  const { data } = await apiClient.get<ListProductAuditLogsResponse>(`/api/v1/products/${pathParam(req.productId)}/audit-logs`, {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取商品修订列表，审核队列按待审核状态查询（管理后台）
+ *
+ * `GET /api/v1/product-revisions` → product.v1.ProductService/ListProductRevisions
+ */
+export async function listProductRevisions(req: ListProductRevisionsRequest = {}, config?: AxiosRequestConfig): Promise<ListProductRevisionsResponse> {
+  const { data } = await apiClient.get<ListProductRevisionsResponse>("/api/v1/product-revisions", {
+    ...config,
+    params: {
+      product_id: req.productId,
+      status: req.status,
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 审核通过，修订内容应用到商品上（管理后台）
+ *
+ * `POST /api/v1/product-revisions/{id}/approve` → product.v1.ProductService/ApproveProductRevision
+ */
+export async function approveProductRevision(req: ReviewProductRevisionRequest, config?: AxiosRequestConfig): Promise<ProductRevisionResponse> {
+  const { data } = await apiClient.post<ProductRevisionResponse>(`/api/v1/product-revisions/${pathParam(req.id)}/approve`, req, config);
+  return data;
+}
+
+/**
+ * 审核驳回（管理后台）
+ *
+ * `POST /api/v1/product-revisions/{id}/reject` → product.v1.ProductService/RejectProductRevision
+ */
+export async function rejectProductRevision(req: ReviewProductRevisionRequest, config?: AxiosRequestConfig): Promise<ProductRevisionResponse> {
+  const { data } = await apiClient.post<ProductRevisionResponse>(`/api/v1/product-revisions/${pathParam(req.id)}/reject`, req, config);
+  return data;
+}
+
+/**
+ * 创建定时上下架或改价任务（管理后台）
+ *
+ * `POST /api/v1/product-schedules` → product.v1.ProductService/CreateProductSchedule
+ */
+export async function createProductSchedule(req: CreateProductScheduleRequest = {}, config?: AxiosRequestConfig): Promise<ProductScheduleResponse> {
+  const { data } = await apiClient.post<ProductScheduleResponse>("/api/v1/product-schedules", req, config);
+  return data;
+}
+
+/**
+ * 获取定时任务列表（管理后台）
+ *
+ * `GET /api/v1/product-schedules` → product.v1.ProductService/ListProductSchedules
+ */
+export async function listProductSchedules(req: ListProductSchedulesRequest = {}, config?: AxiosRequestConfig): Promise<ListProductSchedulesResponse> {
+  const { data } = await apiClient.get<ListProductSchedulesResponse>("/api/v1/product-schedules", {
+    ...config,
+    params: {
+      product_id: req.productId,
+      status: req.status,
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 取消待执行的定时任务（管理后台）
+ *
+ * `POST /api/v1/product-schedules/{id}/cancel` → product.v1.ProductService/CancelProductSchedule
+ */
+export async function cancelProductSchedule(req: CancelProductScheduleRequest, config?: AxiosRequestConfig): Promise<ProductScheduleResponse> {
+  const { data } = await apiClient.post<ProductScheduleResponse>(`/api/v1/product-schedules/${pathParam(req.id)}/cancel`, req, config);
+  return data;
+}
+
+/**
+ * 批量导入商品（管理后台）：CSV/XLSX 文件先上传到文件服务，异步处理，通过 GetCatalogJob 轮询进度
+ *
+ * `POST /api/v1/catalog-jobs/import` → product.v1.ProductService/ImportCatalog
+ */
+export async function importCatalog(req: ImportCatalogRequest = {}, config?: AxiosRequestConfig): Promise<CatalogJobResponse> {
+  const { data } = await apiClient.post<CatalogJobResponse>("/api/v1/catalog-jobs/import", req, config);
+  return data;
+}
+
+/**
+ * 批量导出商品（管理后台）：异步生成与导入格式相同的文件
+ *
+ * `POST /api/v1/catalog-jobs/export` → product.v1.ProductService/ExportCatalog
+ */
+export async function exportCatalog(req: ExportCatalogRequest = {}, config?: AxiosRequestConfig): Promise<CatalogJobResponse> {
+  const { data } = await apiClient.post<CatalogJobResponse>("/api/v1/catalog-jobs/export", req, config);
+  return data;
+}
+
+/**
+ * 获取批量任务列表（管理后台）
+ *
+ * `GET /api/v1/catalog-jobs` → product.v1.ProductService/ListCatalogJobs
+ */
+export async function listCatalogJobs(req: ListCatalogJobsRequest = {}, config?: AxiosRequestConfig): Promise<ListCatalogJobsResponse> {
+  const { data } = await apiClient.get<ListCatalogJobsResponse>("/api/v1/catalog-jobs", {
+    ...config,
+    params: {
+      kind: req.kind,
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取批量任务进度、逐行错误和下载地址（管理后台）
+ *
+ * `GET /api/v1/catalog-jobs/{id}` → product.v1.ProductService/GetCatalogJob
+ */
+export async function getCatalogJob(req: GetCatalogJobRequest, config?: AxiosRequestConfig): Promise<CatalogJobResponse> {
+  const { data } = await apiClient.get<CatalogJobResponse>(`/api/v1/catalog-jobs/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 获取秒杀活动列表
+ *
+ * `GET /api/v1/seckill/activities` → seckill.v1.SeckillService/ListSeckillActivities（免登录）
+ */
+export async function listSeckillActivities(req: ListSeckillActivitiesRequest = {}, config?: AxiosRequestConfig): Promise<ListSeckillActivitiesResponse> {
+  const { data } = await apiClient.get<ListSeckillActivitiesResponse>("/api/v1/seckill/activities", {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+      status: req.status,
+      include_disabled: req.includeDisabled,
+      keyword: req.keyword,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 创建秒杀活动（管理后台）
+ *
+ * `POST /api/v1/seckill/activities` → seckill.v1.SeckillService/CreateSeckillActivity
+ */
+export async function createSeckillActivity(req: CreateSeckillActivityRequest = {}, config?: AxiosRequestConfig): Promise<CreateSeckillActivityResponse> {
+  const { data } = await apiClient.post<CreateSeckillActivityResponse>("/api/v1/seckill/activities", req, config);
+  return data;
+}
+
+/**
+ * 获取秒杀活动详情
+ *
+ * `GET /api/v1/seckill/activities/{id}` → seckill.v1.SeckillService/GetSeckillActivity（免登录）
+ */
+export async function getSeckillActivity(req: GetSeckillActivityRequest, config?: AxiosRequestConfig): Promise<GetSeckillActivityResponse> {
+  const { data } = await apiClient.get<GetSeckillActivityResponse>(`/api/v1/seckill/activities/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 更新秒杀活动（管理后台）
+ *
+ * `PUT /api/v1/seckill/activities/{id}` → seckill.v1.SeckillService/UpdateSeckillActivity
+ */
+export async function updateSeckillActivity(req: UpdateSeckillActivityRequest, config?: AxiosRequestConfig): Promise<UpdateSeckillActivityResponse> {
+  const { data } = await apiClient.put<UpdateSeckillActivityResponse>(`/api/v1/seckill/activities/${pathParam(req.id)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除秒杀活动（管理后台）
+ *
+ * `DELETE /api/v1/seckill/activities/{id}` → seckill.v1.SeckillService/DeleteSeckillActivity
+ */
+export async function deleteSeckillActivity(req: DeleteSeckillActivityRequest, config?: AxiosRequestConfig): Promise<DeleteSeckillActivityResponse> {
+  const { data } = await apiClient.delete<DeleteSeckillActivityResponse>(`/api/v1/seckill/activities/${pathParam(req.id)}`, config);
+  return data;
+}
+
+/**
+ * 秒杀下单
+ *
+ * `POST /api/v1/seckill` → seckill.v1.SeckillService/Seckill
+ */
+export async function seckill(req: SeckillRequest = {}, config?: AxiosRequestConfig): Promise<SeckillResponse> {
+  const { data } = await apiClient.post<SeckillResponse>("/api/v1/seckill", req, config);
+  return data;
+}
+
+/**
+ * 创建订单
+ *
+ * `POST /api/v1/orders` → order.v1.OrderService/CreateOrder
+ */
+export async function createOrder(req: CreateOrderRequest = {}, config?: AxiosRequestConfig): Promise<CreateOrderResponse> {
+  const { data } = await apiClient.post<CreateOrderResponse>("/api/v1/orders", req, config);
+  return data;
+}
+
+/**
+ * 获取订单列表
+ *
+ * `GET /api/v1/orders` → order.v1.OrderService/ListOrders
+ */
+export async function listOrders(req: ListOrdersRequest = {}, config?: AxiosRequestConfig): Promise<ListOrdersResponse> {
+  const { data } = await apiClient.get<ListOrdersResponse>("/api/v1/orders", {
+    ...config,
+    params: {
+      user_id: req.userId,
+      status: req.status,
+      page: req.page,
+      page_size: req.pageSize,
+      keyword: req.keyword,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取订单详情
+ *
+ * `GET /api/v1/orders/{id}` → order.v1.OrderService/GetOrder
+ */
+export async function getOrder(req: GetOrderRequest, config?: AxiosRequestConfig): Promise<GetOrderResponse> {
+  const { data } = await apiClient.get<GetOrderResponse>(`/api/v1/orders/${pathParam(req.id)}`, {
+    ...config,
+    params: {
+      order_no: req.orderNo,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 取消订单
+ *
+ * `PUT /api/v1/orders/{id}/cancel` → order.v1.OrderService/CancelOrder
+ */
+export async function cancelOrder(req: CancelOrderRequest, config?: AxiosRequestConfig): Promise<CancelOrderResponse> {
+  const { data } = await apiClient.put<CancelOrderResponse>(`/api/v1/orders/${pathParam(req.id)}/cancel`, req, config);
+  return data;
+}
+
+/**
+ * 确认收货
+ *
+ * `PUT /api/v1/orders/{id}/confirm-receive` → order.v1.OrderService/ConfirmReceive
+ */
+export async function confirmReceive(req: ConfirmReceiveRequest, config?: AxiosRequestConfig): Promise<ConfirmReceiveResponse> {
+  const { data } = await apiClient.put<ConfirmReceiveResponse>(`/api/v1/orders/${pathParam(req.id)}/confirm-receive`, req, config);
+  return data;
+}
+
+/**
+ * 支付成功通知（由支付服务回调，订单状态 待支付→待发货）
+ *
+ * `PUT /api/v1/orders/{id}/pay` → order.v1.OrderService/PayOrder
+ */
+export async function payOrder(req: PayOrderRequest, config?: AxiosRequestConfig): Promise<PayOrderResponse> {
+  const { data } = await apiClient.put<PayOrderResponse>(`/api/v1/orders/${pathParam(undefined)}/pay`, req, config);
+  return data;
+}
+
+/**
+ * 发货通知（由物流服务调用，订单状态 待发货→待收货）
+ *
+ * `PUT /api/v1/orders/{id}/ship` → order.v1.OrderService/ShipOrder
+ */
+export async function shipOrder(req: ShipOrderRequest, config?: AxiosRequestConfig): Promise<ShipOrderResponse> {
+  const { data } = await apiClient.put<ShipOrderResponse>(`/api/v1/orders/${pathParam(undefined)}/ship`, req, config);
+  return data;
+}
+
+/**
+ * 退款通知（由支付服务调用，订单状态→已退款，回退库存）
+ *
+ * `PUT /api/v1/orders/{id}/refund` → order.v1.OrderService/RefundOrder
+ */
+export async function refundOrder(req: RefundOrderRequest, config?: AxiosRequestConfig): Promise<RefundOrderResponse> {
+  const { data } = await apiClient.put<RefundOrderResponse>(`/api/v1/orders/${pathParam(undefined)}/refund`, req, config);
+  return data;
+}
+
+/**
+ * 创建支付单
+ *
+ * `POST /api/v1/payments` → payment.v1.PaymentService/CreatePayment
+ */
+export async function createPayment(req: CreatePaymentRequest = {}, config?: AxiosRequestConfig): Promise<CreatePaymentResponse> {
+  const { data } = await apiClient.post<CreatePaymentResponse>("/api/v1/payments", req, config);
+  return data;
+}
+
+/**
+ * 获取支付单
+ *
+ * `GET /api/v1/payments/{payment_no}` → payment.v1.PaymentService/GetPayment
+ */
+export async function getPayment(req: GetPaymentRequest, config?: AxiosRequestConfig): Promise<GetPaymentResponse> {
+  const { data } = await apiClient.get<GetPaymentResponse>(`/api/v1/payments/${pathParam(req.paymentNo)}`, config);
+  return data;
+}
+
+/**
+ * 申请退款
+ *
+ * `POST /api/v1/payments/{payment_no}/refund` → payment.v1.PaymentService/Refund
+ */
+export async function refund(req: RefundRequest, config?: AxiosRequestConfig): Promise<RefundResponse> {
+  const { data } = await apiClient.post<RefundResponse>(`/api/v1/payments/${pathParam(req.paymentNo)}/refund`, req, config);
+  return data;
+}
+
+/**
+ * 查询支付状态
+ *
+ * `GET /api/v1/payments/{payment_no}/status` → payment.v1.PaymentService/QueryPaymentStatus
+ */
+export async function queryPaymentStatus(req: QueryPaymentStatusRequest, config?: AxiosRequestConfig): Promise<QueryPaymentStatusResponse> {
+  const { data } = await apiClient.get<QueryPaymentStatusResponse>(`/api/v1/payments/${pathParam(req.paymentNo)}/status`, config);
+  return data;
+}
+
+/**
+ * 支付回调处理
+ *
+ * `POST /api/v1/payments/callback` → payment.v1.PaymentService/PaymentCallback（免登录）
+ */
+export async function paymentCallback(req: PaymentCallbackRequest = {}, config?: AxiosRequestConfig): Promise<PaymentCallbackResponse> {
+  const { data } = await apiClient.post<PaymentCallbackResponse>("/api/v1/payments/callback", req, config);
+  return data;
+}
+
+/**
+ * 创建评价
+ *
+ * `POST /api/v1/reviews` → review.v1.ReviewService/CreateReview
+ */
+export async function createReview(req: CreateReviewRequest = {}, config?: AxiosRequestConfig): Promise<CreateReviewResponse> {
+  const { data } = await apiClient.post<CreateReviewResponse>("/api/v1/reviews", req, config);
+  return data;
+}
+
+/**
+ * 获取商品评价列表
+ *
+ * `GET /api/v1/reviews/product/{product_id}` → review.v1.ReviewService/GetProductReviews（免登录）
+ */
+export async function getProductReviews(req: GetProductReviewsRequest, config?: AxiosRequestConfig): Promise<GetProductReviewsResponse> {
+  const { data } = await apiClient.get<GetProductReviewsResponse>(`/api/v1/reviews/product/${pathParam(req.productId)}`, {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+      rating: req.rating,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取评价详情
+ *
+ * `GET /api/v1/reviews/{review_id}` → review.v1.ReviewService/GetReview（免登录）
+ */
+export async function getReview(req: GetReviewRequest, config?: AxiosRequestConfig): Promise<GetReviewResponse> {
+  const { data } = await apiClient.get<GetReviewResponse>(`/api/v1/reviews/${pathParam(req.reviewId)}`, config);
+  return data;
+}
+
+/**
+ * 回复评价
+ *
+ * `PUT /api/v1/reviews/{review_id}/reply` → review.v1.ReviewService/ReplyReview
+ */
+export async function replyReview(req: ReplyReviewRequest, config?: AxiosRequestConfig): Promise<ReplyReviewResponse> {
+  const { data } = await apiClient.put<ReplyReviewResponse>(`/api/v1/reviews/${pathParam(req.reviewId)}/reply`, req, config);
+  return data;
+}
+
+/**
+ * 获取评价统计
+ *
+ * `GET /api/v1/reviews/stats/{product_id}` → review.v1.ReviewService/GetReviewStats（免登录）
+ */
+export async function getReviewStats(req: GetReviewStatsRequest, config?: AxiosRequestConfig): Promise<GetReviewStatsResponse> {
+  const { data } = await apiClient.get<GetReviewStatsResponse>(`/api/v1/reviews/stats/${pathParam(req.productId)}`, config);
+  return data;
+}
+
+/**
+ * 获取库存
+ *
+ * `GET /api/v1/inventory/{sku_id}` → inventory.v1.InventoryService/GetInventory
+ */
+export async function getInventory(req: GetInventoryRequest, config?: AxiosRequestConfig): Promise<GetInventoryResponse> {
+  const { data } = await apiClient.get<GetInventoryResponse>(`/api/v1/inventory/${pathParam(req.skuId)}`, config);
+  return data;
+}
+
+/**
+ * 扣减库存
+ *
+ * `POST /api/v1/inventory/deduct` → inventory.v1.InventoryService/DeductStock
+ */
+export async function deductStock(req: DeductStockRequest = {}, config?: AxiosRequestConfig): Promise<DeductStockResponse> {
+  const { data } = await apiClient.post<DeductStockResponse>("/api/v1/inventory/deduct", req, config);
+  return data;
+}
+
+/**
+ * 批量获取库存
+ *
+ * `POST /api/v1/inventory/batch` → inventory.v1.InventoryService/BatchGetInventory
+ */
+export async function batchGetInventory(req: BatchGetInventoryRequest = {}, config?: AxiosRequestConfig): Promise<BatchGetInventoryResponse> {
+  const { data } = await apiClient.post<BatchGetInventoryResponse>("/api/v1/inventory/batch", req, config);
+  return data;
+}
+
+/**
+ * 锁定库存（预占）
+ *
+ * `POST /api/v1/inventory/lock` → inventory.v1.InventoryService/LockStock
+ */
+export async function lockStock(req: LockStockRequest = {}, config?: AxiosRequestConfig): Promise<LockStockResponse> {
+  const { data } = await apiClient.post<LockStockResponse>("/api/v1/inventory/lock", req, config);
+  return data;
+}
+
+/**
+ * 解锁库存
+ *
+ * `POST /api/v1/inventory/unlock` → inventory.v1.InventoryService/UnlockStock
+ */
+export async function unlockStock(req: UnlockStockRequest = {}, config?: AxiosRequestConfig): Promise<UnlockStockResponse> {
+  const { data } = await apiClient.post<UnlockStockResponse>("/api/v1/inventory/unlock", req, config);
+  return data;
+}
+
+/**
+ * 回退库存
+ *
+ * `POST /api/v1/inventory/rollback` → inventory.v1.InventoryService/RollbackStock
+ */
+export async function rollbackStock(req: RollbackStockRequest = {}, config?: AxiosRequestConfig): Promise<RollbackStockResponse> {
+  const { data } = await apiClient.post<RollbackStockResponse>("/api/v1/inventory/rollback", req, config);
+  return data;
+}
+
+/**
+ * 入库
+ *
+ * `POST /api/v1/inventory/stock-in` → inventory.v1.InventoryService/StockIn
+ */
+export async function stockIn(req: StockInRequest = {}, config?: AxiosRequestConfig): Promise<StockInResponse> {
+  const { data } = await apiClient.post<StockInResponse>("/api/v1/inventory/stock-in", req, config);
+  return data;
+}
+
+/**
+ * 获取库存流水
+ *
+ * `GET /api/v1/inventory/{sku_id}/logs` → inventory.v1.InventoryService/GetInventoryLog
+ */
+export async function getInventoryLog(req: GetInventoryLogRequest, config?: AxiosRequestConfig): Promise<GetInventoryLogResponse> {
+  const { data } = await apiClient.get<GetInventoryLogResponse>(`/api/v1/inventory/${pathParam(req.skuId)}/logs`, {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取购物车
+ *
+ * `GET /api/v1/cart` → cart.v1.CartService/GetCart
+ */
+export async function getCart(req: GetCartRequest = {}, config?: AxiosRequestConfig): Promise<GetCartResponse> {
+  const { data } = await apiClient.get<GetCartResponse>("/api/v1/cart", {
+    ...config,
+    params: {
+      user_id: req.userId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 添加商品到购物车
+ *
+ * `POST /api/v1/cart` → cart.v1.CartService/AddItem
+ */
+export async function addItem(req: AddItemRequest = {}, config?: AxiosRequestConfig): Promise<AddItemResponse> {
+  const { data } = await apiClient.post<AddItemResponse>("/api/v1/cart", req, config);
+  return data;
+}
+
+/**
+ * 更新购物车商品数量
+ *
+ * `PUT /api/v1/cart/{sku_id}` → cart.v1.CartService/UpdateQuantity
+ */
+export async function updateQuantity(req: UpdateQuantityRequest, config?: AxiosRequestConfig): Promise<UpdateQuantityResponse> {
+  const { data } = await apiClient.put<UpdateQuantityResponse>(`/api/v1/cart/${pathParam(req.skuId)}`, req, config);
+  return data;
+}
+
+/**
+ * 删除购物车商品
+ *
+ * `DELETE /api/v1/cart/{sku_ids}` → cart.v1.CartService/RemoveItem
+ */
+export async function removeItem(req: RemoveItemRequest, config?: AxiosRequestConfig): Promise<RemoveItemResponse> {
+  const { data } = await apiClient.delete<RemoveItemResponse>(`/api/v1/cart/${pathParam(req.skuIds)}`, {
+    ...config,
+    params: {
+      user_id: req.userId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 清空购物车
+ *
+ * `DELETE /api/v1/cart/clear` → cart.v1.CartService/ClearCart
+ */
+export async function clearCart(req: ClearCartRequest = {}, config?: AxiosRequestConfig): Promise<ClearCartResponse> {
+  const { data } = await apiClient.delete<ClearCartResponse>("/api/v1/cart/clear", {
+    ...config,
+    params: {
+      user_id: req.userId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 选择/取消选择购物车商品
+ *
+ * `PUT /api/v1/cart/select/{sku_id}` → cart.v1.CartService/SelectItem
+ */
+export async function selectItem(req: SelectItemRequest, config?: AxiosRequestConfig): Promise<SelectItemResponse> {
+  const { data } = await apiClient.put<SelectItemResponse>(`/api/v1/cart/select/${pathParam(req.skuId)}`, req, config);
+  return data;
+}
+
+/**
+ * 批量选择/取消选择
+ *
+ * `PUT /api/v1/cart/select` → cart.v1.CartService/BatchSelect
+ */
+export async function batchSelect(req: BatchSelectRequest = {}, config?: AxiosRequestConfig): Promise<BatchSelectResponse> {
+  const { data } = await apiClient.put<BatchSelectResponse>("/api/v1/cart/select", req, config);
+  return data;
+}
+
+/**
+ * 获取优惠券列表
+ *
+ * `GET /api/v1/promotion/coupons` → promotion.v1.PromotionService/GetCouponList（免登录）
+ */
+export async function getCouponList(req: GetCouponListRequest = {}, config?: AxiosRequestConfig): Promise<GetCouponListResponse> {
+  const { data } = await apiClient.get<GetCouponListResponse>("/api/v1/promotion/coupons", {
+    ...config,
+    params: {
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取用户优惠券列表
+ *
+ * `GET /api/v1/promotion/user-coupons/{user_id}` → promotion.v1.PromotionService/GetUserCouponList
+ */
+export async function getUserCouponList(req: GetUserCouponListRequest, config?: AxiosRequestConfig): Promise<GetUserCouponListResponse> {
+  const { data } = await apiClient.get<GetUserCouponListResponse>(`/api/v1/promotion/user-coupons/${pathParam(req.userId)}`, {
+    ...config,
+    params: {
+      status: req.status,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取促销活动列表
+ *
+ * `GET /api/v1/promotion/promotions` → promotion.v1.PromotionService/GetPromotionList（免登录）
+ */
+export async function getPromotionList(req: GetPromotionListRequest = {}, config?: AxiosRequestConfig): Promise<GetPromotionListResponse> {
+  const { data } = await apiClient.get<GetPromotionListResponse>("/api/v1/promotion/promotions", {
+    ...config,
+    params: {
+      product_id: req.productId,
+      category_id: req.categoryId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 领取优惠券
+ *
+ * `POST /api/v1/promotion/coupons/{coupon_id}/receive` → promotion.v1.PromotionService/ReceiveCoupon
+ */
+export async function receiveCoupon(req: ReceiveCouponRequest, config?: AxiosRequestConfig): Promise<ReceiveCouponResponse> {
+  const { data } = await apiClient.post<ReceiveCouponResponse>(`/api/v1/promotion/coupons/${pathParam(req.couponId)}/receive`, req, config);
+  return data;
+}
+
+/**
+ * 使用优惠券
+ *
+ * `POST /api/v1/promotion/user-coupons/{user_coupon_id}/use` → promotion.v1.PromotionService/UseCoupon
+ */
+export async function useCoupon(req: UseCouponRequest, config?: AxiosRequestConfig): Promise<UseCouponResponse> {
+  const { data } = await apiClient.post<UseCouponResponse>(`/api/v1/promotion/user-coupons/${pathParam(req.userCouponId)}/use`, req, config);
+  return data;
+}
+
+/**
+ * 计算优惠金额
+ *
+ * `POST /api/v1/promotion/discount/calculate` → promotion.v1.PromotionService/CalculateDiscount
+ */
+export async function calculateDiscount(req: CalculateDiscountRequest = {}, config?: AxiosRequestConfig): Promise<CalculateDiscountResponse> {
+  const { data } = await apiClient.post<CalculateDiscountResponse>("/api/v1/promotion/discount/calculate", req, config);
+  return data;
+}
+
+/**
+ * 获取用户积分
+ *
+ * `GET /api/v1/promotion/points/{user_id}` → promotion.v1.PromotionService/GetUserPoints
+ */
+export async function getUserPoints(req: GetUserPointsRequest, config?: AxiosRequestConfig): Promise<GetUserPointsResponse> {
+  const { data } = await apiClient.get<GetUserPointsResponse>(`/api/v1/promotion/points/${pathParam(req.userId)}`, config);
+  return data;
+}
+
+/**
+ * 积分兑换
+ *
+ * `POST /api/v1/promotion/points/exchange` → promotion.v1.PromotionService/ExchangePoints
+ */
+export async function exchangePoints(req: ExchangePointsRequest = {}, config?: AxiosRequestConfig): Promise<ExchangePointsResponse> {
+  const { data } = await apiClient.post<ExchangePointsResponse>("/api/v1/promotion/points/exchange", req, config);
+  return data;
+}
+
+/**
+ * 创建物流单
+ *
+ * `POST /api/v1/logistics` → logistics.v1.LogisticsService/CreateLogistics
+ */
+export async function createLogistics(req: CreateLogisticsRequest = {}, config?: AxiosRequestConfig): Promise<CreateLogisticsResponse> {
+  const { data } = await apiClient.post<CreateLogisticsResponse>("/api/v1/logistics", req, config);
+  return data;
+}
+
+/**
+ * 获取物流信息
+ *
+ * `GET /api/v1/logistics/{order_id}` → logistics.v1.LogisticsService/GetLogistics
+ */
+export async function getLogistics(req: GetLogisticsRequest, config?: AxiosRequestConfig): Promise<GetLogisticsResponse> {
+  const { data } = await apiClient.get<GetLogisticsResponse>(`/api/v1/logistics/${pathParam(req.orderId)}`, config);
+  return data;
+}
+
+/**
+ * 更新物流状态
+ *
+ * `PUT /api/v1/logistics/status` → logistics.v1.LogisticsService/UpdateLogisticsStatus
+ */
+export async function updateLogisticsStatus(req: UpdateLogisticsStatusRequest = {}, config?: AxiosRequestConfig): Promise<UpdateLogisticsStatusResponse> {
+  const { data } = await apiClient.put<UpdateLogisticsStatusResponse>("/api/v1/logistics/status", req, config);
+  return data;
+}
+
+/**
+ * 查询物流轨迹
+ *
+ * `GET /api/v1/logistics/tracking/{logistics_no}` → logistics.v1.LogisticsService/QueryTracking（免登录）
+ */
+export async function queryTracking(req: QueryTrackingRequest, config?: AxiosRequestConfig): Promise<QueryTrackingResponse> {
+  const { data } = await apiClient.get<QueryTrackingResponse>(`/api/v1/logistics/tracking/${pathParam(req.logisticsNo)}`, config);
+  return data;
+}
+
+/**
+ * 计算运费
+ *
+ * `POST /api/v1/logistics/freight/calculate` → logistics.v1.LogisticsService/CalculateFreight（免登录）
+ */
+export async function calculateFreight(req: CalculateFreightRequest = {}, config?: AxiosRequestConfig): Promise<CalculateFreightResponse> {
+  const { data } = await apiClient.post<CalculateFreightResponse>("/api/v1/logistics/freight/calculate", req, config);
+  return data;
+}
+
+/**
+ * 商品搜索
+ *
+ * `GET /api/v1/search/products` → search.v1.SearchService/SearchProducts（免登录）
+ */
+export async function searchProducts(req: SearchProductsRequest = {}, config?: AxiosRequestConfig): Promise<SearchProductsResponse> {
+  const { data } = await apiClient.get<SearchProductsResponse>("/api/v1/search/products", {
+    ...config,
+    params: {
+      keyword: req.keyword,
+      page: req.page,
+      page_size: req.pageSize,
+      category_id: req.categoryId,
+      sort_by: req.sortBy,
+      brand_id: req.brandId,
+      attr_filter: req.attrFilter,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 搜索建议
+ *
+ * `GET /api/v1/search/suggestions` → search.v1.SearchService/GetSearchSuggestions（免登录）
+ */
+export async function getSearchSuggestions(req: GetSearchSuggestionsRequest = {}, config?: AxiosRequestConfig): Promise<GetSearchSuggestionsResponse> {
+  const { data } = await apiClient.get<GetSearchSuggestionsResponse>("/api/v1/search/suggestions", {
+    ...config,
+    params: {
+      keyword: req.keyword,
+      limit: req.limit,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 搜索热词
+ *
+ * `GET /api/v1/search/hot-keywords` → search.v1.SearchService/GetHotKeywords（免登录）
+ */
+export async function getHotKeywords(req: GetHotKeywordsRequest = {}, config?: AxiosRequestConfig): Promise<GetHotKeywordsResponse> {
+  const { data } = await apiClient.get<GetHotKeywordsResponse>("/api/v1/search/hot-keywords", {
+    ...config,
+    params: {
+      limit: req.limit,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 构建商品索引
+ *
+ * `POST /api/v1/search/index/build` → search.v1.SearchService/BuildProductIndex
+ */
+export async function buildProductIndex(req: BuildProductIndexRequest = {}, config?: AxiosRequestConfig): Promise<BuildProductIndexResponse> {
+  const { data } = await apiClient.post<BuildProductIndexResponse>("/api/v1/search/index/build", req, config);
+  return data;
+}
+
+/**
+ * 个性化推荐
+ *
+ * `GET /api/v1/recommend/personalized/{user_id}` → recommend.v1.RecommendService/GetPersonalizedRecommend
+ */
+export async function getPersonalizedRecommend(req: GetPersonalizedRecommendRequest, config?: AxiosRequestConfig): Promise<GetPersonalizedRecommendResponse> {
+  const { data } = await apiClient.get<GetPersonalizedRecommendResponse>(`/api/v1/recommend/personalized/${pathParam(req.userId)}`, {
+    ...config,
+    params: {
+      limit: req.limit,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 相似商品推荐
+ *
+ * `GET /api/v1/recommend/similar/{product_id}` → recommend.v1.RecommendService/GetSimilarProducts（免登录）
+ */
+export async function getSimilarProducts(req: GetSimilarProductsRequest, config?: AxiosRequestConfig): Promise<GetSimilarProductsResponse> {
+  const { data } = await apiClient.get<GetSimilarProductsResponse>(`/api/v1/recommend/similar/${pathParam(req.productId)}`, {
+    ...config,
+    params: {
+      limit: req.limit,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 热门推荐
+ *
+ * `GET /api/v1/recommend/hot` → recommend.v1.RecommendService/GetHotProducts（免登录）
+ */
+export async function getHotProducts(req: GetHotProductsRequest = {}, config?: AxiosRequestConfig): Promise<GetHotProductsResponse> {
+  const { data } = await apiClient.get<GetHotProductsResponse>("/api/v1/recommend/hot", {
+    ...config,
+    params: {
+      limit: req.limit,
+      category_id: req.categoryId,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 实时推荐
+ *
+ * `GET /api/v1/recommend/realtime/{user_id}` → recommend.v1.RecommendService/GetRealtimeRecommend
+ */
+export async function getRealtimeRecommend(req: GetRealtimeRecommendRequest, config?: AxiosRequestConfig): Promise<GetRealtimeRecommendResponse> {
+  const { data } = await apiClient.get<GetRealtimeRecommendResponse>(`/api/v1/recommend/realtime/${pathParam(req.userId)}`, {
+    ...config,
+    params: {
+      limit: req.limit,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 获取消息列表
+ *
+ * `GET /api/v1/messages` → message.v1.MessageService/GetMessageList
+ */
+export async function getMessageList(req: GetMessageListRequest = {}, config?: AxiosRequestConfig): Promise<GetMessageListResponse> {
+  const { data } = await apiClient.get<GetMessageListResponse>("/api/v1/messages", {
+    ...config,
+    params: {
+      user_id: req.userId,
+      type: req.type,
+      page: req.page,
+      page_size: req.pageSize,
+    },
+    paramsSerializer: { indexes: null },
+  });
+  return data;
+}
+
+/**
+ * 发送消息
+ *
+ * `POST /api/v1/messages` → message.v1.MessageService/SendMessage
+ */
+export async function sendMessage(req: SendMessageRequest = {}, config?: AxiosRequestConfig): Promise<SendMessageResponse> {
+  const { data } = await apiClient.post<SendMessageResponse>("/api/v1/messages", req, config);
+  return data;
+}
+
+/**
+ * 标记消息已读
+ *
+ * `PUT /api/v1/messages/{message_id}/read` → message.v1.MessageService/MarkAsRead
+ */
+export async function markAsRead(req: MarkAsReadRequest, config?: AxiosRequestConfig): Promise<MarkAsReadResponse> {
+  const { data } = await apiClient.put<MarkAsReadResponse>(`/api/v1/messages/${pathParam(req.messageId)}/read`, req, config);
+  return data;
+}
+
+/**
+ * 批量标记已读
+ *
+ * `PUT /api/v1/messages/read` → message.v1.MessageService/BatchMarkAsRead
+ */
+export async function batchMarkAsRead(req: BatchMarkAsReadRequest = {}, config?: AxiosRequestConfig): Promise<BatchMarkAsReadResponse> {
+  const { data } = await apiClient.put<BatchMarkAsReadResponse>("/api/v1/messages/read", req, config);
+  return data;
+}
+
+/**
+ * 获取未读消息数量
+ *
+ * `GET /api/v1/messages/unread-count/{user_id}` → message.v1.MessageService/GetUnreadCount
+ */
+export async function getUnreadCount(req: GetUnreadCountRequest, config?: AxiosRequestConfig): Promise<GetUnreadCountResponse> {
+  const { data } = await apiClient.get<GetUnreadCountResponse>(`/api/v1/messages/unread-count/${pathParam(req.userId)}`, config);
+  return data;
+}
+
+/**
+ * 订单超时取消
+ *
+ * `POST /api/v1/jobs/cancel-expired-orders` → job.v1.JobService/CancelExpiredOrders
+ */
+export async function cancelExpiredOrders(req: CancelExpiredOrdersRequest = {}, config?: AxiosRequestConfig): Promise<CancelExpiredOrdersResponse> {
+  const { data } = await apiClient.post<CancelExpiredOrdersResponse>("/api/v1/jobs/cancel-expired-orders", req, config);
+  return data;
+}
+
+/**
+ * 优惠券过期处理
+ *
+ * `POST /api/v1/jobs/process-expired-coupons` → job.v1.JobService/ProcessExpiredCoupons
+ */
+export async function processExpiredCoupons(req: ProcessExpiredCouponsRequest = {}, config?: AxiosRequestConfig): Promise<ProcessExpiredCouponsResponse> {
+  const { data } = await apiClient.post<ProcessExpiredCouponsResponse>("/api/v1/jobs/process-expired-coupons", req, config);
+  return data;
+}
+
+/**
+ * 数据统计
+ *
+ * `POST /api/v1/jobs/generate-statistics` → job.v1.JobService/GenerateStatistics
+ */
+export async function generateStatistics(req: GenerateStatisticsRequest = {}, config?: AxiosRequestConfig): Promise<GenerateStatisticsResponse> {
+  const { data } = await apiClient.post<GenerateStatisticsResponse>("/api/v1/jobs/generate-statistics", req, config);
+  return data;
+}
+
+/**
+ * 删除文件
+ *
+ * `DELETE /api/v1/files/{file_id}` → file.v1.FileService/DeleteFile
+ */
+export async function deleteFile(req: DeleteFileRequest, config?: AxiosRequestConfig): Promise<DeleteFileResponse> {
+  const { data } = await apiClient.delete<DeleteFileResponse>(`/api/v1/files/${pathParam(req.fileId)}`, config);
+  return data;
+}
+
+/**
+ * 获取文件URL：调用方必须登录，私有文件只签发给上传者本人
+ *
+ * `GET /api/v1/files/{file_id}/url` → file.v1.FileService/GetFileURL
+ */
+export async function getFileURL(req: GetFileURLRequest, config?: AxiosRequestConfig): Promise<GetFileURLResponse> {
+  const { data } = await apiClient.get<GetFileURLResponse>(`/api/v1/files/${pathParam(req.fileId)}/url`, config);
+  return data;
+}
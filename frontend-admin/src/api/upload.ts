@@ -2,8 +2,9 @@ import { apiClient } from "@/api/client";
 
 export async function uploadImage(file: File, category = "image") {
   const formData = new FormData();
-  formData.append("file", file);
+  // category 必须在 file 之前：网关流式转发文件时需要先确定分类
   formData.append("category", category);
+  formData.append("file", file);
   const response = await apiClient.post<{
     code: number;
     message: string;
@@ -1,19 +1,32 @@
 import { NavLink, Outlet } from "react-router-dom";
+import { adminLogout } from "@/api/admin";
 import { useAdminAuthStore } from "@/stores/adminAuth";
 
 const menuItems = [
   { to: "/", label: "仪表盘" },
   { to: "/users", label: "用户管理" },
   { to: "/products", label: "商品管理" },
+  { to: "/reviews", label: "商品审核" },
+  { to: "/schedules", label: "定时任务" },
+  { to: "/catalog", label: "批量导入导出" },
   { to: "/skus", label: "SKU 管理" },
   { to: "/categories", label: "分类管理" },
+  { to: "/attrs", label: "属性模板" },
+  { to: "/brands", label: "品牌管理" },
   { to: "/banners", label: "Banner 管理" },
   { to: "/orders", label: "订单管理" },
   { to: "/seckill", label: "秒杀活动" },
 ];
 
 export function AdminLayout() {
-  const { username, logout } = useAdminAuthStore();
+  const { username, refreshToken, logout } = useAdminAuthStore();
+
+  // 先通知服务端吊销令牌，失败也清除本地登录状态
+  const handleLogout = () => {
+    adminLogout(refreshToken)
+      .catch(() => undefined)
+      .finally(logout);
+  };
 
   return (
     <div className="admin-shell">
@@ -34,7 +47,7 @@ export function AdminLayout() {
             </NavLink>
           ))}
         </nav>
-        <button className="outline-button" onClick={logout} type="button">
+        <button className="outline-button" onClick={handleLogout} type="button">
           退出登录
         </button>
       </aside>
@@ -1,13 +1,22 @@
 import { FormEvent, useState } from "react";
 import { useNavigate } from "react-router-dom";
-import { adminLogin } from "@/api/admin";
+import { adminLogin, adminSetupTOTP, adminVerifyMFA } from "@/api/admin";
 import { useAdminAuthStore } from "@/stores/adminAuth";
 
+// 两步验证：mfa_token 来自第一步登录；enroll 时先展示密钥供验证器应用绑定
+interface MFAStep {
+  token: string;
+  secret: string;
+  provisioningUri: string;
+}
+
 export function AdminLoginPage() {
   const navigate = useNavigate();
   const setAuth = useAdminAuthStore((state) => state.setAuth);
   const [error, setError] = useState("");
   const [submitting, setSubmitting] = useState(false);
+  const [mfa, setMfa] = useState<MFAStep | null>(null);
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
 
   async function handleSubmit(event: FormEvent<HTMLFormElement>) {
     event.preventDefault();
@@ -22,7 +31,21 @@ export function AdminLoginPage() {
         password: String(formData.get("password") || ""),
         login_type: 1,
       });
-      setAuth(response.data.token, response.data.user.nickname || response.data.user.username);
+      if (response.data.mfa_required) {
+        const step: MFAStep = { token: response.data.mfa_token, secret: "", provisioningUri: "" };
+        if (response.data.mfa_enroll_required) {
+          const setup = await adminSetupTOTP(step.token);
+          step.secret = setup.secret;
+          step.provisioningUri = setup.provisioning_uri;
+        }
+        setMfa(step);
+        return;
+      }
+      setAuth(
+        response.data.token,
+        response.data.user.nickname || response.data.user.username,
+        response.data.refresh_token,
+      );
       navigate("/");
     } catch (submitError) {
       setError(submitError instanceof Error ? submitError.message : "登录失败");
@@ -31,6 +54,80 @@ export function AdminLoginPage() {
     }
   }
 
+  async function handleVerify(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    if (!mfa) return;
+    setSubmitting(true);
+    setError("");
+
+    const formData = new FormData(event.currentTarget);
+
+    try {
+      const response = await adminVerifyMFA(mfa.token, String(formData.get("code") || "").trim());
+      setAuth(response.token, response.user.nickname || response.user.username, response.refresh_token);
+      // 首次绑定时返回恢复码，确认保存后再进入后台
+      if (response.recovery_codes.length > 0) {
+        setRecoveryCodes(response.recovery_codes);
+        return;
+      }
+      navigate("/");
+    } catch (submitError) {
+      setError(submitError instanceof Error ? submitError.message : "验证失败");
+    } finally {
+      setSubmitting(false);
+    }
+  }
+
+  if (recoveryCodes.length > 0) {
+    return (
+      <section className="login-screen">
+        <div className="login-panel">
+          <span className="chip">两步验证已开启</span>
+          <h1>保存恢复码</h1>
+          <p>手机丢失时可用恢复码登录，每个只能使用一次。恢复码只显示这一次。</p>
+          <pre>{recoveryCodes.join("\n")}</pre>
+          <button className="primary-button" onClick={() => navigate("/")} type="button">
+            我已保存，进入后台
+          </button>
+        </div>
+      </section>
+    );
+  }
+
+  if (mfa) {
+    return (
+      <section className="login-screen">
+        <div className="login-panel">
+          <span className="chip">两步验证</span>
+          <h1>{mfa.secret ? "绑定验证器" : "输入验证码"}</h1>
+          {mfa.secret ? (
+            <>
+              <p>当前角色要求开启两步验证。请在验证器应用中添加账号（扫描下面地址生成的二维码，或手动输入密钥），然后输入应用中的 6 位验证码。</p>
+              <p>
+                密钥：<code>{mfa.secret}</code>
+              </p>
+              <p>
+                <code>{mfa.provisioningUri}</code>
+              </p>
+            </>
+          ) : (
+            <p>请输入验证器应用中的 6 位验证码，或一个恢复码。</p>
+          )}
+          <form className="admin-form" onSubmit={handleVerify}>
+            <label>
+              验证码
+              <input autoComplete="one-time-code" name="code" placeholder="123456" required />
+            </label>
+            {error ? <div className="error-box">{error}</div> : null}
+            <button className="primary-button" disabled={submitting} type="submit">
+              {submitting ? "验证中..." : "验证"}
+            </button>
+          </form>
+        </div>
+      </section>
+    );
+  }
+
   return (
     <section className="login-screen">
       <div className="login-panel">
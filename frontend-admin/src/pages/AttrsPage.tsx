@@ -0,0 +1,161 @@
+import { FormEvent, useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { deleteAttr, listCategories, listCategoryAttrs, saveAttr } from "@/api/admin";
+
+type AttrForm = {
+  id?: number;
+  name: string;
+  type: number;
+  input_type: number;
+  values: string[];
+  sort: number;
+  is_required: number;
+};
+
+const attrTypeLabels: Record<number, string> = { 1: "规格参数", 2: "销售属性", 3: "基础属性" };
+const inputTypeLabels: Record<number, string> = { 1: "单选", 2: "多选", 3: "文本" };
+
+export function AttrsPage() {
+  const queryClient = useQueryClient();
+  const [categoryId, setCategoryId] = useState(0);
+  const [editing, setEditing] = useState<AttrForm | null>(null);
+  const categoriesQuery = useQuery({
+    queryKey: ["admin-categories", ""],
+    queryFn: () => listCategories({ status: 1 }),
+  });
+  // 包含上级类目继承来的属性，继承项只读，需到所属类目修改
+  const query = useQuery({
+    queryKey: ["admin-attrs", categoryId],
+    queryFn: () => listCategoryAttrs(categoryId, true),
+    enabled: categoryId > 0,
+  });
+  const saveMutation = useMutation({
+    mutationFn: saveAttr,
+    onSuccess: () => {
+      setEditing(null);
+      void queryClient.invalidateQueries({ queryKey: ["admin-attrs"] });
+    },
+  });
+  const deleteMutation = useMutation({
+    mutationFn: deleteAttr,
+    onSuccess: () => void queryClient.invalidateQueries({ queryKey: ["admin-attrs"] }),
+  });
+
+  function handleSubmit(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    const formData = new FormData(event.currentTarget);
+    saveMutation.mutate({
+      id: editing?.id,
+      categoryId,
+      name: String(formData.get("name") || ""),
+      type: Number(formData.get("type") || 1),
+      inputType: Number(formData.get("input_type") || 1),
+      values: String(formData.get("values") || "")
+        .split(/[,，\n]/)
+        .map((item) => item.trim())
+        .filter(Boolean),
+      sort: Number(formData.get("sort") || 0),
+      isRequired: Number(formData.get("is_required") || 0),
+    });
+  }
+
+  const categories = categoriesQuery.data?.data ?? [];
+  const list = query.data ?? [];
+
+  return (
+    <section className="admin-grid two-panel">
+      <div className="table-card">
+        <div className="card-head">
+          <h2>属性模板</h2>
+          <button className="outline-button" disabled={categoryId === 0} onClick={() => setEditing(null)} type="button">
+            新建属性
+          </button>
+        </div>
+        <select
+          onChange={(event) => {
+            setCategoryId(Number(event.target.value));
+            setEditing(null);
+          }}
+          value={String(categoryId)}
+        >
+          <option value="0">选择分类</option>
+          {categories.map((item) => (
+            <option key={String(item.id)} value={String(item.id)}>
+              {String(item.name)}
+            </option>
+          ))}
+        </select>
+        {query.isError ? <div className="error-box">{(query.error as Error).message}</div> : null}
+        {deleteMutation.isError ? <div className="error-box">{(deleteMutation.error as Error).message}</div> : null}
+        <table className="table">
+          <thead>
+            <tr>
+              <th>名称</th>
+              <th>类型</th>
+              <th>输入方式</th>
+              <th>可选值</th>
+              <th>必填</th>
+              <th>操作</th>
+            </tr>
+          </thead>
+          <tbody>
+            {list.map((attr) => (
+              <tr key={attr.id}>
+                <td>{attr.name}</td>
+                <td>{attrTypeLabels[attr.type] ?? attr.type}</td>
+                <td>{inputTypeLabels[attr.input_type] ?? attr.input_type}</td>
+                <td>{attr.values.join("、") || "-"}</td>
+                <td>{attr.is_required === 1 ? "是" : "否"}</td>
+                <td>
+                  {attr.inherited ? (
+                    <span className="muted">继承自分类 {attr.category_id}</span>
+                  ) : (
+                    <div className="action-row">
+                      <button className="table-button" onClick={() => setEditing(attr)} type="button">
+                        编辑
+                      </button>
+                      <button className="table-button danger" onClick={() => deleteMutation.mutate(attr.id)} type="button">
+                        删除
+                      </button>
+                    </div>
+                  )}
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      </div>
+      <div className="table-card">
+        <h2>{editing ? "编辑属性" : "新建属性"}</h2>
+        <form className="admin-form" key={editing?.id ?? `new-attr-${categoryId}`} onSubmit={handleSubmit}>
+          <input defaultValue={editing?.name ?? ""} name="name" placeholder="属性名称" required />
+          <select defaultValue={String(editing?.type ?? 1)} disabled={Boolean(editing)} name="type">
+            <option value="1">规格参数</option>
+            <option value="2">销售属性（SKU 规格）</option>
+            <option value="3">基础属性</option>
+          </select>
+          <select defaultValue={String(editing?.input_type ?? 1)} name="input_type">
+            <option value="1">单选</option>
+            <option value="2">多选</option>
+            <option value="3">文本</option>
+          </select>
+          <textarea
+            className="admin-text-area"
+            defaultValue={editing?.values.join("\n") ?? ""}
+            name="values"
+            placeholder="可选值，每行一个（文本类型不填）"
+          />
+          <input defaultValue={editing?.sort ?? 0} name="sort" placeholder="排序" />
+          <select defaultValue={String(editing?.is_required ?? 0)} name="is_required">
+            <option value="0">选填</option>
+            <option value="1">必填</option>
+          </select>
+          {saveMutation.isError ? <div className="error-box">{(saveMutation.error as Error).message}</div> : null}
+          <button className="primary-button" disabled={categoryId === 0} type="submit">
+            保存属性
+          </button>
+        </form>
+      </div>
+    </section>
+  );
+}
@@ -0,0 +1,170 @@
+import { FormEvent, useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { deleteBrand, listBrands, saveBrand } from "@/api/admin";
+import { DataTableControls } from "@/components/DataTableControls";
+import { uploadImage } from "@/api/upload";
+
+type BrandForm = {
+  id?: number;
+  name: string;
+  logo: string;
+  description: string;
+  sort: number;
+  status: number;
+};
+
+export function BrandsPage() {
+  const queryClient = useQueryClient();
+  const [editing, setEditing] = useState<BrandForm | null>(null);
+  const [keyword, setKeyword] = useState("");
+  const [page, setPage] = useState(1);
+  const [pageSize, setPageSize] = useState(10);
+  // 上传 Logo 后保存文件ID，由商品服务向文件服务换取公开地址
+  const [logoFileId, setLogoFileId] = useState("");
+  const query = useQuery({
+    queryKey: ["admin-brands", keyword, page, pageSize],
+    queryFn: () => listBrands({ status: -1, keyword, page, pageSize }),
+  });
+  const saveMutation = useMutation({
+    mutationFn: saveBrand,
+    onSuccess: () => {
+      setEditing(null);
+      setLogoFileId("");
+      void queryClient.invalidateQueries({ queryKey: ["admin-brands"] });
+    },
+  });
+  const deleteMutation = useMutation({
+    mutationFn: deleteBrand,
+    onSuccess: () => void queryClient.invalidateQueries({ queryKey: ["admin-brands"] }),
+  });
+  const uploadMutation = useMutation({
+    mutationFn: (file: File) => uploadImage(file, "image"),
+  });
+
+  function handleSubmit(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    const formData = new FormData(event.currentTarget);
+    saveMutation.mutate({
+      id: editing?.id,
+      name: String(formData.get("name") || ""),
+      logo: String(formData.get("logo") || ""),
+      logoFileId: logoFileId || undefined,
+      description: String(formData.get("description") || ""),
+      sort: Number(formData.get("sort") || 0),
+      status: Number(formData.get("status") || 1),
+    });
+  }
+
+  const list = query.data?.items ?? [];
+
+  return (
+    <section className="admin-grid two-panel">
+      <div className="table-card">
+        <div className="card-head">
+          <h2>品牌管理</h2>
+          <button
+            className="outline-button"
+            onClick={() => {
+              setEditing(null);
+              setLogoFileId("");
+            }}
+            type="button"
+          >
+            新建品牌
+          </button>
+        </div>
+        <DataTableControls
+          onPageChange={setPage}
+          onPageSizeChange={(size) => {
+            setPageSize(size);
+            setPage(1);
+          }}
+          onSearchChange={(value) => {
+            setKeyword(value);
+            setPage(1);
+          }}
+          page={page}
+          pageSize={pageSize}
+          searchPlaceholder="搜索名称、描述"
+          searchValue={keyword}
+          total={query.data?.total ?? 0}
+        />
+        {deleteMutation.isError ? <div className="error-box">{(deleteMutation.error as Error).message}</div> : null}
+        <table className="table">
+          <thead>
+            <tr>
+              <th>ID</th>
+              <th>Logo</th>
+              <th>名称</th>
+              <th>排序</th>
+              <th>状态</th>
+              <th>操作</th>
+            </tr>
+          </thead>
+          <tbody>
+            {list.map((brand) => (
+              <tr key={brand.id}>
+                <td>{brand.id}</td>
+                <td>{brand.logo ? <img alt={brand.name} height={32} src={brand.logo} /> : "-"}</td>
+                <td>{brand.name}</td>
+                <td>{brand.sort}</td>
+                <td>{brand.status === 1 ? "启用" : "禁用"}</td>
+                <td>
+                  <div className="action-row">
+                    <button
+                      className="table-button"
+                      onClick={() => {
+                        setEditing(brand);
+                        setLogoFileId("");
+                      }}
+                      type="button"
+                    >
+                      编辑
+                    </button>
+                    <button className="table-button danger" onClick={() => deleteMutation.mutate(brand.id)} type="button">
+                      删除
+                    </button>
+                  </div>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      </div>
+      <div className="table-card">
+        <h2>{editing ? "编辑品牌" : "新建品牌"}</h2>
+        <form className="admin-form" key={editing?.id ?? "new-brand"} onSubmit={handleSubmit}>
+          <input defaultValue={editing?.name ?? ""} name="name" placeholder="品牌名称" required />
+          <input defaultValue={editing?.logo ?? ""} name="logo" placeholder="Logo URL（或上传图片）" />
+          <input
+            accept="image/*"
+            onChange={(event) => {
+              const file = event.target.files?.[0];
+              if (!file) return;
+              uploadMutation.mutate(file, {
+                onSuccess: (response) => {
+                  setLogoFileId(response.data.file_id);
+                  const target = document.querySelector<HTMLInputElement>('input[name="logo"]');
+                  if (target && response.data.file_url) {
+                    target.value = response.data.file_url;
+                  }
+                },
+              });
+            }}
+            type="file"
+          />
+          <input defaultValue={editing?.description ?? ""} name="description" placeholder="描述" />
+          <input defaultValue={editing?.sort ?? 0} name="sort" placeholder="排序" />
+          <select defaultValue={String(editing?.status ?? 1)} name="status">
+            <option value="0">禁用</option>
+            <option value="1">启用</option>
+          </select>
+          {saveMutation.isError ? <div className="error-box">{(saveMutation.error as Error).message}</div> : null}
+          <button className="primary-button" type="submit">
+            保存品牌
+          </button>
+        </form>
+      </div>
+    </section>
+  );
+}
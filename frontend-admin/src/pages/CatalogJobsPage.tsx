@@ -0,0 +1,232 @@
+import { FormEvent, useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { exportCatalog, getCatalogJob, importCatalog, listCatalogJobs } from "@/api/admin";
+import { uploadImage } from "@/api/upload";
+import { DataTableControls } from "@/components/DataTableControls";
+
+const jobStatusLabels: Record<number, string> = { 0: "待处理", 1: "处理中", 2: "已完成", 3: "处理失败" };
+const jobKindLabels: Record<string, string> = { import: "导入", export: "导出" };
+
+function isRunning(status: number) {
+  return status === 0 || status === 1;
+}
+
+export function CatalogJobsPage() {
+  const queryClient = useQueryClient();
+  const [kind, setKind] = useState("");
+  const [page, setPage] = useState(1);
+  const [pageSize, setPageSize] = useState(10);
+  const [selectedId, setSelectedId] = useState(0);
+  const [jobId, setJobId] = useState("");
+  const query = useQuery({
+    queryKey: ["admin-catalog-jobs", kind, page, pageSize],
+    queryFn: () => listCatalogJobs({ kind, page, pageSize }),
+    // 有未完成的任务时轮询进度
+    refetchInterval: (current) => (current.state.data?.items.some((job) => isRunning(job.status)) ? 2000 : false),
+  });
+  const detailQuery = useQuery({
+    queryKey: ["admin-catalog-job", selectedId],
+    queryFn: () => getCatalogJob(selectedId),
+    enabled: selectedId > 0,
+    refetchInterval: (current) => (current.state.data && isRunning(current.state.data.status) ? 2000 : false),
+  });
+  const importMutation = useMutation({
+    mutationFn: async (payload: { file: File; dryRun: boolean; submit: boolean }) => {
+      const uploaded = await uploadImage(payload.file, "catalog");
+      return importCatalog({ fileId: uploaded.data.file_id, dryRun: payload.dryRun, submit: payload.submit });
+    },
+    onSuccess: (job) => {
+      setSelectedId(job.id);
+      void queryClient.invalidateQueries({ queryKey: ["admin-catalog-jobs"] });
+    },
+  });
+  const exportMutation = useMutation({
+    mutationFn: exportCatalog,
+    onSuccess: (job) => {
+      setSelectedId(job.id);
+      void queryClient.invalidateQueries({ queryKey: ["admin-catalog-jobs"] });
+    },
+  });
+
+  function handleImport(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    const formData = new FormData(event.currentTarget);
+    const file = formData.get("file");
+    if (!(file instanceof File) || !file.name) {
+      return;
+    }
+    importMutation.mutate({
+      file,
+      dryRun: formData.get("dry_run") === "on",
+      submit: formData.get("submit") === "on",
+    });
+  }
+
+  function handleExport(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    const formData = new FormData(event.currentTarget);
+    exportMutation.mutate({
+      format: String(formData.get("format") || "csv"),
+      categoryId: Number(formData.get("category_id") || 0),
+      status: Number(formData.get("status") ?? -1),
+      keyword: String(formData.get("keyword") || ""),
+    });
+  }
+
+  const list = query.data?.items ?? [];
+  const detail = detailQuery.data;
+
+  return (
+    <section className="admin-grid two-panel">
+      <div className="table-card">
+        <div className="card-head">
+          <h2>批量导入导出</h2>
+          <select
+            onChange={(event) => {
+              setKind(event.target.value);
+              setPage(1);
+            }}
+            value={kind}
+          >
+            <option value="">全部</option>
+            <option value="import">导入</option>
+            <option value="export">导出</option>
+          </select>
+        </div>
+        <DataTableControls
+          onPageChange={setPage}
+          onPageSizeChange={(size) => {
+            setPageSize(size);
+            setPage(1);
+          }}
+          onSearchChange={(value) => {
+            const digits = value.replace(/\D/g, "");
+            setJobId(digits);
+            setSelectedId(Number(digits) || 0);
+          }}
+          page={page}
+          pageSize={pageSize}
+          searchPlaceholder="按任务 ID 查看详情"
+          searchValue={jobId}
+          total={query.data?.total ?? 0}
+        />
+        {query.isError ? <div className="error-box">{(query.error as Error).message}</div> : null}
+        {detailQuery.isError ? <div className="error-box">{(detailQuery.error as Error).message}</div> : null}
+        <table className="table">
+          <thead>
+            <tr>
+              <th>任务</th>
+              <th>类型</th>
+              <th>进度</th>
+              <th>结果</th>
+              <th>状态</th>
+              <th>操作</th>
+            </tr>
+          </thead>
+          <tbody>
+            {list.map((job) => (
+              <tr key={job.id}>
+                <td>#{job.id}</td>
+                <td>
+                  {jobKindLabels[job.kind] ?? job.kind} {job.format.toUpperCase()}
+                  {job.dry_run ? <span className="muted">（仅校验）</span> : null}
+                </td>
+                <td>
+                  {job.processed_rows} / {job.total_rows}
+                </td>
+                <td>
+                  成功 {job.success_rows}
+                  {job.failed_rows ? <span className="muted">，失败 {job.failed_rows}</span> : null}
+                </td>
+                <td>
+                  {jobStatusLabels[job.status] ?? job.status}
+                  {job.message ? <span className="muted">：{job.message}</span> : null}
+                </td>
+                <td>
+                  <button className="table-button" onClick={() => setSelectedId(job.id)} type="button">
+                    详情
+                  </button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+        {detail ? (
+          <div className="table-card">
+            <h2>
+              任务 #{detail.id} · {jobStatusLabels[detail.status] ?? detail.status}
+            </h2>
+            <p className="muted">
+              已处理 {detail.processed_rows} / {detail.total_rows}，成功 {detail.success_rows}，失败 {detail.failed_rows}
+              {detail.finished_at ? `，完成于 ${detail.finished_at}` : ""}
+            </p>
+            {detail.message ? <div className="error-box">{detail.message}</div> : null}
+            {detail.result_url ? (
+              <a className="table-button" href={detail.result_url} rel="noreferrer" target="_blank">
+                {detail.kind === "export" ? "下载导出文件" : "下载错误报告"}
+              </a>
+            ) : null}
+            {detail.errors.length ? (
+              <table className="table">
+                <thead>
+                  <tr>
+                    <th>行号</th>
+                    <th>SPU</th>
+                    <th>SKU</th>
+                    <th>错误</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {detail.errors.map((item, index) => (
+                    <tr key={`${item.row}-${index}`}>
+                      <td>{item.row || "-"}</td>
+                      <td>{item.spu_code || "-"}</td>
+                      <td>{item.sku_code || "-"}</td>
+                      <td>{item.message}</td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            ) : null}
+          </div>
+        ) : null}
+      </div>
+      <div className="table-card">
+        <h2>导入商品</h2>
+        <form className="admin-form" onSubmit={handleImport}>
+          <input accept=".csv,.xlsx" name="file" required type="file" />
+          <label>
+            <input defaultChecked name="dry_run" type="checkbox" /> 仅校验，不写入
+          </label>
+          <label>
+            <input name="submit" type="checkbox" /> 导入后直接提交审核
+          </label>
+          {importMutation.isError ? <div className="error-box">{(importMutation.error as Error).message}</div> : null}
+          <button className="primary-button" disabled={importMutation.isPending} type="submit">
+            {importMutation.isPending ? "上传中..." : "开始导入"}
+          </button>
+          <p className="muted">文件格式与导出一致，每行一个 SKU；建议先仅校验，确认无误后再正式导入。</p>
+        </form>
+        <h2>导出商品</h2>
+        <form className="admin-form" onSubmit={handleExport}>
+          <select defaultValue="csv" name="format">
+            <option value="csv">CSV</option>
+            <option value="xlsx">XLSX</option>
+          </select>
+          <input min={0} name="category_id" placeholder="类目 ID（含子类目，可选）" type="number" />
+          <select defaultValue="-1" name="status">
+            <option value="-1">全部状态</option>
+            <option value="1">上架</option>
+            <option value="0">下架</option>
+            <option value="2">待审核</option>
+          </select>
+          <input name="keyword" placeholder="关键词（可选）" />
+          {exportMutation.isError ? <div className="error-box">{(exportMutation.error as Error).message}</div> : null}
+          <button className="primary-button" disabled={exportMutation.isPending} type="submit">
+            开始导出
+          </button>
+        </form>
+      </div>
+    </section>
+  );
+}
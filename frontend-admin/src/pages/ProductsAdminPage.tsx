@@ -1,8 +1,18 @@
 import { FormEvent, useState } from "react";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
-import { createProduct, deleteProduct, listProducts, updateProduct } from "@/api/admin";
+import {
+  createProduct,
+  deleteProduct,
+  listBrands,
+  listCategoryAttrs,
+  listProductRevisions,
+  listProducts,
+  submitProductRevision,
+  updateProduct,
+} from "@/api/admin";
 import { DataTableControls } from "@/components/DataTableControls";
 import { uploadImage } from "@/api/upload";
+import { revisionStatusLabels } from "@/pages/ReviewsPage";
 
 type ProductForm = {
   id?: number;
@@ -18,8 +28,12 @@ type ProductForm = {
   stock: number;
   status: number;
   is_hot: number;
+  attrs: Record<string, string>;
+  submit?: boolean;
 };
 
+const productStatusLabels: Record<number, string> = { 0: "下架", 1: "上架", 2: "待审核" };
+
 const emptyForm: ProductForm = {
   name: "",
   subtitle: "",
@@ -33,6 +47,7 @@ const emptyForm: ProductForm = {
   stock: 0,
   status: 1,
   is_hot: 0,
+  attrs: {},
 };
 
 function toProductForm(input: Record<string, unknown>): ProductForm {
@@ -50,6 +65,7 @@ function toProductForm(input: Record<string, unknown>): ProductForm {
     stock: Number(input.stock || 0),
     status: Number(input.status || 1),
     is_hot: Number(input.is_hot || 0),
+    attrs: (input.attrs ?? {}) as Record<string, string>,
   };
 }
 
@@ -61,15 +77,50 @@ export function ProductsAdminPage() {
   const [pageSize, setPageSize] = useState(10);
   const query = useQuery({
     queryKey: ["admin-products", page, pageSize, keyword],
-    queryFn: () => listProducts({ page, page_size: pageSize, status: 1, keyword }),
+    queryFn: () => listProducts({ page, page_size: pageSize, status: -1, keyword }),
+  });
+  // 编辑有未通过修订的商品时，表单以修订内容为准（线上内容审核通过前不变）
+  const [editingAuditStatus, setEditingAuditStatus] = useState(2);
+  const revisionQuery = useQuery({
+    queryKey: ["admin-revisions", "open", editing?.id],
+    queryFn: () => listProductRevisions({ productId: editing?.id, page: 1, pageSize: 1 }),
+    enabled: Boolean(editing?.id) && editingAuditStatus !== 2,
+  });
+  const openRevision = revisionQuery.data?.items.find((item) => item.status !== 2);
+  const formValues: ProductForm | null =
+    editing && openRevision
+      ? { ...editing, ...toProductForm(openRevision.content as unknown as Record<string, unknown>), id: editing.id }
+      : editing;
+  const [notice, setNotice] = useState("");
+  const brandsQuery = useQuery({
+    queryKey: ["admin-brands", "enabled"],
+    queryFn: () => listBrands({ status: 1 }),
   });
+  // 表单中当前填写的分类，用于加载属性模板（销售属性在 SKU 上填写）
+  const [formCategoryId, setFormCategoryId] = useState(0);
+  const attrsQuery = useQuery({
+    queryKey: ["admin-attrs", formCategoryId],
+    queryFn: () => listCategoryAttrs(formCategoryId, true),
+    enabled: formCategoryId > 0,
+  });
+  const productAttrs = (attrsQuery.data ?? []).filter((attr) => attr.type !== 2);
 
   const saveMutation = useMutation({
     mutationFn: async (payload: ProductForm) =>
       payload.id ? updateProduct(payload.id, payload) : createProduct(payload),
-    onSuccess: () => {
+    onSuccess: (_, payload) => {
       setEditing(null);
+      setNotice(payload.submit ? "已提交审核，审核通过后生效" : "已保存草稿，提交审核并通过后生效");
       void queryClient.invalidateQueries({ queryKey: ["admin-products"] });
+      void queryClient.invalidateQueries({ queryKey: ["admin-revisions"] });
+    },
+  });
+  const submitMutation = useMutation({
+    mutationFn: submitProductRevision,
+    onSuccess: () => {
+      setNotice("已提交审核");
+      void queryClient.invalidateQueries({ queryKey: ["admin-products"] });
+      void queryClient.invalidateQueries({ queryKey: ["admin-revisions"] });
     },
   });
   const deleteMutation = useMutation({
@@ -85,6 +136,16 @@ export function ProductsAdminPage() {
   function handleSubmit(event: FormEvent<HTMLFormElement>) {
     event.preventDefault();
     const formData = new FormData(event.currentTarget);
+    const submitter = (event.nativeEvent as SubmitEvent).submitter as HTMLButtonElement | null;
+    const attrs: Record<string, string> = {};
+    productAttrs.forEach((attr) => {
+      const value = formData
+        .getAll(`attr:${attr.name}`)
+        .map((item) => String(item).trim())
+        .filter(Boolean)
+        .join(",");
+      if (value) attrs[attr.name] = value;
+    });
     saveMutation.mutate({
       ...(editing ?? {}),
       name: String(formData.get("name") || ""),
@@ -97,17 +158,27 @@ export function ProductsAdminPage() {
       price: Number(formData.get("price") || 0),
       original_price: Number(formData.get("original_price") || 0),
       stock: Number(formData.get("stock") || 0),
-      status: Number(formData.get("status") || 1),
+      // 未通过审核的商品没有上下架选项，审核通过后自动上架
+      status: Number(formData.get("status") ?? -1),
       is_hot: Number(formData.get("is_hot") || 0),
+      attrs,
+      submit: submitter?.value === "submit",
     });
   }
 
+  function openForm(form: ProductForm | null, auditStatus = 2) {
+    setEditing(form);
+    setEditingAuditStatus(auditStatus);
+    setFormCategoryId(form?.category_id ?? 0);
+    setNotice("");
+  }
+
   return (
     <section className="admin-grid two-panel">
       <div className="table-card">
         <div className="card-head">
           <h2>商品管理</h2>
-          <button className="outline-button" onClick={() => setEditing(null)} type="button">
+          <button className="outline-button" onClick={() => openForm(null)} type="button">
             新建商品
           </button>
         </div>
@@ -136,6 +207,7 @@ export function ProductsAdminPage() {
               <th>库存</th>
               <th>销量</th>
               <th>状态</th>
+              <th>审核</th>
               <th>操作</th>
             </tr>
           </thead>
@@ -147,12 +219,25 @@ export function ProductsAdminPage() {
                 <td>¥{product.price}</td>
                 <td>{product.stock}</td>
                 <td>{product.sales}</td>
-                <td>{product.status}</td>
+                <td>{productStatusLabels[product.status] ?? product.status}</td>
+                <td title={product.audit_comment}>
+                  {revisionStatusLabels[product.audit_status] ?? product.audit_status}
+                  {product.audit_status === 3 && product.audit_comment ? `：${product.audit_comment}` : ""}
+                </td>
                 <td>
                   <div className="action-row">
-                    <button className="table-button" onClick={() => setEditing(toProductForm(product as unknown as Record<string, unknown>))} type="button">
+                    <button
+                      className="table-button"
+                      onClick={() => openForm(toProductForm(product as unknown as Record<string, unknown>), product.audit_status)}
+                      type="button"
+                    >
                       编辑
                     </button>
+                    {product.audit_status === 0 || product.audit_status === 3 ? (
+                      <button className="table-button" onClick={() => submitMutation.mutate(product.id)} type="button">
+                        提交审核
+                      </button>
+                    ) : null}
                     <button className="table-button danger" onClick={() => deleteMutation.mutate(product.id)} type="button">
                       删除
                     </button>
@@ -162,18 +247,38 @@ export function ProductsAdminPage() {
             ))}
           </tbody>
         </table>
+        {submitMutation.isError ? <div className="error-box">{(submitMutation.error as Error).message}</div> : null}
       </div>
       <div className="table-card">
         <h2>{editing ? "编辑商品" : "新建商品"}</h2>
-        <form className="admin-form" key={editing?.id ?? "new"} onSubmit={handleSubmit}>
-          <input defaultValue={editing?.name ?? emptyForm.name} name="name" placeholder="商品名" required />
-          <input defaultValue={editing?.subtitle ?? emptyForm.subtitle} name="subtitle" placeholder="副标题" />
-          <input defaultValue={editing?.category_id ?? emptyForm.category_id} name="category_id" placeholder="分类 ID" />
-          <input defaultValue={editing?.brand_id ?? emptyForm.brand_id} name="brand_id" placeholder="品牌 ID" />
-          <input defaultValue={editing?.price ?? emptyForm.price} name="price" placeholder="价格" />
-          <input defaultValue={editing?.original_price ?? emptyForm.original_price} name="original_price" placeholder="原价" />
-          <input defaultValue={editing?.stock ?? emptyForm.stock} name="stock" placeholder="库存" />
-          <input defaultValue={editing?.main_image ?? emptyForm.main_image} name="main_image" placeholder="主图 URL" />
+        {notice ? <p className="muted">{notice}</p> : null}
+        {openRevision ? (
+          <p className="muted">
+            正在编辑{revisionStatusLabels[openRevision.status]}的修订 #{openRevision.id}
+            {openRevision.review_comment ? `，审核意见：${openRevision.review_comment}` : ""}
+          </p>
+        ) : null}
+        <form className="admin-form" key={`${formValues?.id ?? "new"}-${openRevision?.id ?? 0}`} onSubmit={handleSubmit}>
+          <input defaultValue={formValues?.name ?? emptyForm.name} name="name" placeholder="商品名" required />
+          <input defaultValue={formValues?.subtitle ?? emptyForm.subtitle} name="subtitle" placeholder="副标题" />
+          <input
+            defaultValue={formValues?.category_id ?? emptyForm.category_id}
+            name="category_id"
+            onBlur={(event) => setFormCategoryId(Number(event.target.value) || 0)}
+            placeholder="分类 ID"
+          />
+          <select defaultValue={String(formValues?.brand_id ?? emptyForm.brand_id)} name="brand_id">
+            <option value="0">无品牌</option>
+            {(brandsQuery.data?.items ?? []).map((brand) => (
+              <option key={brand.id} value={brand.id}>
+                {brand.name}
+              </option>
+            ))}
+          </select>
+          <input defaultValue={formValues?.price ?? emptyForm.price} name="price" placeholder="价格" />
+          <input defaultValue={formValues?.original_price ?? emptyForm.original_price} name="original_price" placeholder="原价" />
+          <input defaultValue={formValues?.stock ?? emptyForm.stock} name="stock" placeholder="库存" />
+          <input defaultValue={formValues?.main_image ?? emptyForm.main_image} name="main_image" placeholder="主图 URL" />
           <input
             accept="image/*"
             onChange={(event) => {
@@ -190,19 +295,50 @@ export function ProductsAdminPage() {
             }}
             type="file"
           />
-          <textarea className="admin-text-area" defaultValue={editing?.detail ?? emptyForm.detail} name="detail" placeholder="详情" />
-          <select defaultValue={String(editing?.status ?? emptyForm.status)} name="status">
-            <option value="0">下架</option>
-            <option value="1">上架</option>
-            <option value="2">待审核</option>
-          </select>
-          <select defaultValue={String(editing?.is_hot ?? emptyForm.is_hot)} name="is_hot">
+          {productAttrs.map((attr) => {
+            const current = formValues?.attrs?.[attr.name] ?? "";
+            const label = `${attr.name}${attr.is_required === 1 ? "（必填）" : ""}`;
+            if (attr.input_type === 3) {
+              return <input defaultValue={current} key={attr.id} name={`attr:${attr.name}`} placeholder={label} required={attr.is_required === 1} />;
+            }
+            return (
+              <select
+                defaultValue={attr.input_type === 2 ? current.split(",").filter(Boolean) : current}
+                key={attr.id}
+                multiple={attr.input_type === 2}
+                name={`attr:${attr.name}`}
+                required={attr.is_required === 1}
+              >
+                {attr.input_type === 1 ? <option value="">{label}</option> : null}
+                {attr.values.map((value) => (
+                  <option key={value} value={value}>
+                    {value}
+                  </option>
+                ))}
+              </select>
+            );
+          })}
+          {attrsQuery.isError ? <div className="error-box">{(attrsQuery.error as Error).message}</div> : null}
+          <textarea className="admin-text-area" defaultValue={formValues?.detail ?? emptyForm.detail} name="detail" placeholder="详情" />
+          {formValues && formValues.status !== 2 ? (
+            <select defaultValue={String(formValues.status)} name="status">
+              <option value="0">下架</option>
+              <option value="1">上架</option>
+            </select>
+          ) : null}
+          <select defaultValue={String(formValues?.is_hot ?? emptyForm.is_hot)} name="is_hot">
             <option value="0">普通</option>
             <option value="1">热门</option>
           </select>
-          <button className="primary-button" disabled={saveMutation.isPending} type="submit">
-            {saveMutation.isPending ? "保存中..." : "保存商品"}
-          </button>
+          {saveMutation.isError ? <div className="error-box">{(saveMutation.error as Error).message}</div> : null}
+          <div className="action-row">
+            <button className="outline-button" disabled={saveMutation.isPending} type="submit" value="draft">
+              保存草稿
+            </button>
+            <button className="primary-button" disabled={saveMutation.isPending} type="submit" value="submit">
+              {saveMutation.isPending ? "保存中..." : "保存并提交审核"}
+            </button>
+          </div>
         </form>
       </div>
     </section>
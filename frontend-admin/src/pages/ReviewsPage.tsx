@@ -0,0 +1,195 @@
+import { useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { listProductAuditLogs, listProductRevisions, reviewProductRevision } from "@/api/admin";
+import { DataTableControls } from "@/components/DataTableControls";
+
+type Revision = Awaited<ReturnType<typeof listProductRevisions>>["items"][number];
+
+export const revisionStatusLabels: Record<number, string> = { 0: "草稿", 1: "待审核", 2: "已通过", 3: "已驳回" };
+const actionLabels: Record<string, string> = {
+  create: "创建",
+  edit: "修改",
+  submit: "提交审核",
+  approve: "审核通过",
+  reject: "驳回",
+};
+
+export function ReviewsPage() {
+  const queryClient = useQueryClient();
+  const [status, setStatus] = useState(1);
+  const [productId, setProductId] = useState("");
+  const [page, setPage] = useState(1);
+  const [pageSize, setPageSize] = useState(10);
+  const [selected, setSelected] = useState<Revision | null>(null);
+  const [comment, setComment] = useState("");
+  const query = useQuery({
+    queryKey: ["admin-revisions", status, productId, page, pageSize],
+    queryFn: () => listProductRevisions({ productId: Number(productId) || 0, status, page, pageSize }),
+  });
+  const logsQuery = useQuery({
+    queryKey: ["admin-audit-logs", selected?.product_id],
+    queryFn: () => listProductAuditLogs(selected?.product_id ?? 0),
+    enabled: Boolean(selected?.product_id),
+  });
+  const reviewMutation = useMutation({
+    mutationFn: reviewProductRevision,
+    onSuccess: () => {
+      setSelected(null);
+      setComment("");
+      void queryClient.invalidateQueries({ queryKey: ["admin-revisions"] });
+      void queryClient.invalidateQueries({ queryKey: ["admin-products"] });
+    },
+  });
+
+  const list = query.data?.items ?? [];
+  const content = selected?.content;
+
+  return (
+    <section className="admin-grid two-panel">
+      <div className="table-card">
+        <div className="card-head">
+          <h2>商品审核</h2>
+          <select
+            onChange={(event) => {
+              setStatus(Number(event.target.value));
+              setPage(1);
+            }}
+            value={String(status)}
+          >
+            <option value="1">待审核</option>
+            <option value="3">已驳回</option>
+            <option value="2">已通过</option>
+            <option value="0">草稿</option>
+            <option value="-1">全部</option>
+          </select>
+        </div>
+        <DataTableControls
+          onPageChange={setPage}
+          onPageSizeChange={(size) => {
+            setPageSize(size);
+            setPage(1);
+          }}
+          onSearchChange={(value) => {
+            setProductId(value.replace(/\D/g, ""));
+            setPage(1);
+          }}
+          page={page}
+          pageSize={pageSize}
+          searchPlaceholder="按商品 ID 筛选"
+          searchValue={productId}
+          total={query.data?.total ?? 0}
+        />
+        {query.isError ? <div className="error-box">{(query.error as Error).message}</div> : null}
+        <table className="table">
+          <thead>
+            <tr>
+              <th>修订</th>
+              <th>商品</th>
+              <th>价格</th>
+              <th>状态</th>
+              <th>提交时间</th>
+              <th>操作</th>
+            </tr>
+          </thead>
+          <tbody>
+            {list.map((revision) => (
+              <tr key={revision.id}>
+                <td>#{revision.id}</td>
+                <td>
+                  {revision.product_id} · {revision.content.name}
+                </td>
+                <td>¥{revision.content.price}</td>
+                <td>{revisionStatusLabels[revision.status] ?? revision.status}</td>
+                <td>{revision.submitted_at || "-"}</td>
+                <td>
+                  <button
+                    className="table-button"
+                    onClick={() => {
+                      setSelected(revision);
+                      setComment("");
+                    }}
+                    type="button"
+                  >
+                    查看
+                  </button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      </div>
+      <div className="table-card">
+        <h2>{selected ? `修订 #${selected.id}` : "选择修订查看"}</h2>
+        {selected && content ? (
+          <div className="admin-form">
+            <p>
+              {content.name}
+              {content.subtitle ? ` · ${content.subtitle}` : ""}
+            </p>
+            <p className="muted">
+              分类 {content.category_id} · 品牌 {content.brand_name || content.brand_id || "-"} · 价格 ¥{content.price}
+              {content.original_price ? `（原价 ¥${content.original_price}）` : ""}
+            </p>
+            {content.main_image ? <img alt={content.name} height={96} src={content.main_image} /> : null}
+            {Object.keys(content.attrs).length > 0 ? (
+              <p className="muted">
+                {Object.entries(content.attrs)
+                  .map(([name, value]) => `${name}：${value}`)
+                  .join("；")}
+              </p>
+            ) : null}
+            {content.detail ? <p className="muted">{content.detail}</p> : null}
+            {selected.review_comment ? <p>审核意见：{selected.review_comment}</p> : null}
+            {selected.status === 1 ? (
+              <>
+                <textarea
+                  className="admin-text-area"
+                  onChange={(event) => setComment(event.target.value)}
+                  placeholder="审核意见（驳回时必填）"
+                  value={comment}
+                />
+                {reviewMutation.isError ? <div className="error-box">{(reviewMutation.error as Error).message}</div> : null}
+                <div className="action-row">
+                  <button
+                    className="primary-button"
+                    disabled={reviewMutation.isPending}
+                    onClick={() => reviewMutation.mutate({ id: selected.id, approve: true, comment })}
+                    type="button"
+                  >
+                    通过
+                  </button>
+                  <button
+                    className="outline-button"
+                    disabled={reviewMutation.isPending || comment.trim() === ""}
+                    onClick={() => reviewMutation.mutate({ id: selected.id, approve: false, comment })}
+                    type="button"
+                  >
+                    驳回
+                  </button>
+                </div>
+              </>
+            ) : null}
+            <h3>审核记录</h3>
+            {logsQuery.isError ? <div className="error-box">{(logsQuery.error as Error).message}</div> : null}
+            <table className="table">
+              <tbody>
+                {(logsQuery.data ?? []).map((log) => (
+                  <tr key={log.id}>
+                    <td>{log.created_at}</td>
+                    <td>
+                      修订 #{log.revision_id} {actionLabels[log.action] ?? log.action}
+                    </td>
+                    <td>操作人 {log.operator_id || "-"}</td>
+                    <td>{log.comment || ""}</td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+          </div>
+        ) : (
+          <p className="muted">内容修改审核通过后才会对用户展示并同步到搜索。</p>
+        )}
+      </div>
+    </section>
+  );
+}
@@ -0,0 +1,159 @@
+import { FormEvent, useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { cancelProductSchedule, createProductSchedule, listProductSchedules } from "@/api/admin";
+import { DataTableControls } from "@/components/DataTableControls";
+
+const scheduleStatusLabels: Record<number, string> = { 0: "待执行", 1: "已执行", 2: "已取消", 3: "执行失败" };
+const actionLabels: Record<string, string> = { list: "上架", delist: "下架", price: "改价" };
+const targetLabels: Record<string, string> = { product: "商品", sku: "SKU" };
+
+export function SchedulesPage() {
+  const queryClient = useQueryClient();
+  const [status, setStatus] = useState(0);
+  const [productId, setProductId] = useState("");
+  const [page, setPage] = useState(1);
+  const [pageSize, setPageSize] = useState(10);
+  const [action, setAction] = useState("list");
+  const query = useQuery({
+    queryKey: ["admin-schedules", status, productId, page, pageSize],
+    queryFn: () => listProductSchedules({ productId: Number(productId) || 0, status, page, pageSize }),
+  });
+  const createMutation = useMutation({
+    mutationFn: createProductSchedule,
+    onSuccess: () => void queryClient.invalidateQueries({ queryKey: ["admin-schedules"] }),
+  });
+  const cancelMutation = useMutation({
+    mutationFn: cancelProductSchedule,
+    onSuccess: () => void queryClient.invalidateQueries({ queryKey: ["admin-schedules"] }),
+  });
+
+  function handleSubmit(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    const formData = new FormData(event.currentTarget);
+    createMutation.mutate({
+      targetType: String(formData.get("target_type") || "product"),
+      targetId: Number(formData.get("target_id") || 0),
+      action,
+      price: Number(formData.get("price") || 0),
+      originalPrice: Number(formData.get("original_price") || 0),
+      runAt: String(formData.get("run_at") || ""),
+    });
+  }
+
+  const list = query.data?.items ?? [];
+
+  return (
+    <section className="admin-grid two-panel">
+      <div className="table-card">
+        <div className="card-head">
+          <h2>定时任务</h2>
+          <select
+            onChange={(event) => {
+              setStatus(Number(event.target.value));
+              setPage(1);
+            }}
+            value={String(status)}
+          >
+            <option value="0">待执行</option>
+            <option value="1">已执行</option>
+            <option value="3">执行失败</option>
+            <option value="2">已取消</option>
+            <option value="-1">全部</option>
+          </select>
+        </div>
+        <DataTableControls
+          onPageChange={setPage}
+          onPageSizeChange={(size) => {
+            setPageSize(size);
+            setPage(1);
+          }}
+          onSearchChange={(value) => {
+            setProductId(value.replace(/\D/g, ""));
+            setPage(1);
+          }}
+          page={page}
+          pageSize={pageSize}
+          searchPlaceholder="按商品 ID 筛选"
+          searchValue={productId}
+          total={query.data?.total ?? 0}
+        />
+        {query.isError ? <div className="error-box">{(query.error as Error).message}</div> : null}
+        {cancelMutation.isError ? <div className="error-box">{(cancelMutation.error as Error).message}</div> : null}
+        <table className="table">
+          <thead>
+            <tr>
+              <th>任务</th>
+              <th>目标</th>
+              <th>动作</th>
+              <th>执行时间</th>
+              <th>状态</th>
+              <th>操作</th>
+            </tr>
+          </thead>
+          <tbody>
+            {list.map((schedule) => (
+              <tr key={schedule.id}>
+                <td>#{schedule.id}</td>
+                <td>
+                  {targetLabels[schedule.target_type] ?? schedule.target_type} {schedule.target_id}
+                  {schedule.target_type === "sku" ? <span className="muted">（商品 {schedule.product_id}）</span> : null}
+                </td>
+                <td>
+                  {actionLabels[schedule.action] ?? schedule.action}
+                  {schedule.action === "price" ? ` ¥${schedule.price}` : ""}
+                  {schedule.action === "price" && schedule.original_price ? `（原价 ¥${schedule.original_price}）` : ""}
+                </td>
+                <td>{schedule.run_at}</td>
+                <td>
+                  {scheduleStatusLabels[schedule.status] ?? schedule.status}
+                  {schedule.error ? <span className="muted">：{schedule.error}</span> : null}
+                </td>
+                <td>
+                  {schedule.status === 0 ? (
+                    <button
+                      className="table-button danger"
+                      disabled={cancelMutation.isPending}
+                      onClick={() => cancelMutation.mutate(schedule.id)}
+                      type="button"
+                    >
+                      取消
+                    </button>
+                  ) : (
+                    <span className="muted">{schedule.executed_at || "-"}</span>
+                  )}
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      </div>
+      <div className="table-card">
+        <h2>新建定时任务</h2>
+        <form className="admin-form" onSubmit={handleSubmit}>
+          <select defaultValue="product" name="target_type">
+            <option value="product">商品</option>
+            <option value="sku">SKU</option>
+          </select>
+          <input min={1} name="target_id" placeholder="商品 / SKU ID" required type="number" />
+          <select onChange={(event) => setAction(event.target.value)} value={action}>
+            <option value="list">上架</option>
+            <option value="delist">下架</option>
+            <option value="price">改价</option>
+          </select>
+          {action === "price" ? (
+            <>
+              <input min={0.01} name="price" placeholder="新价格" required step="0.01" type="number" />
+              <input min={0} name="original_price" placeholder="新原价（可选）" step="0.01" type="number" />
+            </>
+          ) : null}
+          <input name="run_at" required type="datetime-local" />
+          {createMutation.isError ? <div className="error-box">{(createMutation.error as Error).message}</div> : null}
+          <button className="primary-button" disabled={createMutation.isPending} type="submit">
+            创建
+          </button>
+          <p className="muted">到执行时间后自动生效；周末改价需分别创建改价和恢复原价两个任务。</p>
+        </form>
+      </div>
+    </section>
+  );
+}
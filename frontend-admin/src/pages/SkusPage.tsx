@@ -1,6 +1,6 @@
 import { FormEvent, useState } from "react";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
-import { createSku, deleteSku, listSkus, updateSku } from "@/api/admin";
+import { createSku, deleteSku, generateSkus, listSkus, updateSku } from "@/api/admin";
 import { DataTableControls } from "@/components/DataTableControls";
 
 type SkuForm = {
@@ -13,8 +13,25 @@ type SkuForm = {
   stock: number;
   image: string;
   status: number;
+  specs: Record<string, string>;
 };
 
+// 规格写成 "颜色:红色;尺码:L"，按分类的销售属性校验
+function parseSpecs(text: string) {
+  const specs: Record<string, string> = {};
+  text.split(/[;；\n]/).forEach((part) => {
+    const [name, value] = part.split(/[:：]/).map((item) => item?.trim() ?? "");
+    if (name && value) specs[name] = value;
+  });
+  return specs;
+}
+
+function formatSpecs(specs?: Record<string, string>) {
+  return Object.entries(specs ?? {})
+    .map(([name, value]) => `${name}:${value}`)
+    .join(";");
+}
+
 export function SkusPage() {
   const queryClient = useQueryClient();
   const [editing, setEditing] = useState<SkuForm | null>(null);
@@ -26,10 +43,7 @@ export function SkusPage() {
     queryFn: () => listSkus({ page, page_size: pageSize, status: -1, product_id: 0, keyword }),
   });
   const saveMutation = useMutation({
-    mutationFn: (payload: SkuForm) =>
-      payload.id
-        ? updateSku(payload.id, { ...payload, specs: {} })
-        : createSku({ ...payload, specs: {} }),
+    mutationFn: (payload: SkuForm) => (payload.id ? updateSku(payload.id, payload) : createSku(payload)),
     onSuccess: () => {
       setEditing(null);
       void queryClient.invalidateQueries({ queryKey: ["admin-skus"] });
@@ -39,6 +53,26 @@ export function SkusPage() {
     mutationFn: deleteSku,
     onSuccess: () => void queryClient.invalidateQueries({ queryKey: ["admin-skus"] }),
   });
+  const generateMutation = useMutation({
+    mutationFn: generateSkus,
+    onSuccess: (_, variables) => {
+      if (!variables.dryRun) {
+        void queryClient.invalidateQueries({ queryKey: ["admin-skus"] });
+      }
+    },
+  });
+
+  // 按商品分类的销售属性组合生成 SKU，先预览再确认
+  function handleGenerate(form: HTMLFormElement, dryRun: boolean) {
+    const formData = new FormData(form);
+    generateMutation.mutate({
+      productId: Number(formData.get("product_id") || 0),
+      price: Number(formData.get("price") || 0),
+      stock: Number(formData.get("stock") || 0),
+      status: Number(formData.get("status") || 0),
+      dryRun,
+    });
+  }
 
   function handleSubmit(event: FormEvent<HTMLFormElement>) {
     event.preventDefault();
@@ -53,6 +87,7 @@ export function SkusPage() {
       stock: Number(formData.get("stock") || 0),
       image: String(formData.get("image") || ""),
       status: Number(formData.get("status") || 1),
+      specs: parseSpecs(String(formData.get("specs") || "")),
     });
   }
 
@@ -90,6 +125,7 @@ export function SkusPage() {
               <th>商品 ID</th>
               <th>SKU 编码</th>
               <th>名称</th>
+              <th>规格</th>
               <th>价格</th>
               <th>库存</th>
               <th>操作</th>
@@ -102,6 +138,7 @@ export function SkusPage() {
                 <td>{String(sku.product_id)}</td>
                 <td>{String(sku.sku_code)}</td>
                 <td>{String(sku.name)}</td>
+                <td>{formatSpecs(sku.specs) || "-"}</td>
                 <td>{String(sku.price)}</td>
                 <td>{String(sku.stock)}</td>
                 <td>
@@ -129,14 +166,57 @@ export function SkusPage() {
           <input defaultValue={editing?.original_price ?? ""} name="original_price" placeholder="原价" />
           <input defaultValue={editing?.stock ?? ""} name="stock" placeholder="库存" />
           <input defaultValue={editing?.image ?? ""} name="image" placeholder="图片 URL" />
+          <input defaultValue={formatSpecs(editing?.specs)} name="specs" placeholder="规格，如 颜色:红色;尺码:L" required={!editing} />
           <select defaultValue={String(editing?.status ?? 1)} name="status">
             <option value="0">下架</option>
             <option value="1">上架</option>
           </select>
+          {saveMutation.isError ? <div className="error-box">{(saveMutation.error as Error).message}</div> : null}
           <button className="primary-button" type="submit">
             保存 SKU
           </button>
         </form>
+        <h2>按销售属性生成</h2>
+        <form
+          className="admin-form"
+          onSubmit={(event) => {
+            event.preventDefault();
+            handleGenerate(event.currentTarget, false);
+          }}
+        >
+          <input name="product_id" placeholder="商品 ID" required />
+          <input name="price" placeholder="价格（留空使用商品价格）" />
+          <input name="stock" placeholder="库存" />
+          <select defaultValue="0" name="status">
+            <option value="0">生成后下架</option>
+            <option value="1">生成后上架</option>
+          </select>
+          {generateMutation.isError ? <div className="error-box">{(generateMutation.error as Error).message}</div> : null}
+          {generateMutation.data ? (
+            <div className="muted">
+              {generateMutation.variables?.dryRun ? "将生成" : "已生成"} {generateMutation.data.items.length} 个，跳过已存在{" "}
+              {generateMutation.data.skipped} 个：
+              {generateMutation.data.items.map((item) => formatSpecs(item.specs)).join("，")}
+            </div>
+          ) : null}
+          <div className="action-row">
+            <button
+              className="outline-button"
+              onClick={(event) => {
+                const form = event.currentTarget.form;
+                if (form && form.reportValidity()) {
+                  handleGenerate(form, true);
+                }
+              }}
+              type="button"
+            >
+              预览
+            </button>
+            <button className="primary-button" type="submit">
+              生成 SKU
+            </button>
+          </div>
+        </form>
       </div>
     </section>
   );
@@ -1,6 +1,6 @@
 import { useState } from "react";
-import { useQuery } from "@tanstack/react-query";
-import { listUsers } from "@/api/admin";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { listUserSessions, listUsers, revokeUserSession } from "@/api/admin";
 import { DataTableControls } from "@/components/DataTableControls";
 
 export function UsersPage() {
@@ -12,6 +12,7 @@ export function UsersPage() {
     queryFn: () => listUsers({ page, page_size: pageSize, status: 0, keyword }),
   });
   const users = query.data?.data?.users ?? [];
+  const [sessionUserId, setSessionUserId] = useState<number | null>(null);
 
   return (
     <section className="table-card">
@@ -46,6 +47,7 @@ export function UsersPage() {
             <th>联系方式</th>
             <th>状态</th>
             <th>等级</th>
+            <th>操作</th>
           </tr>
         </thead>
         <tbody>
@@ -57,10 +59,90 @@ export function UsersPage() {
               <td>{user.phone || user.email || "-"}</td>
               <td>{user.status}</td>
               <td>{user.member_level ?? 0}</td>
+              <td>
+                <button className="outline-button" onClick={() => setSessionUserId(user.id)} type="button">
+                  登录设备
+                </button>
+              </td>
             </tr>
           ))}
         </tbody>
       </table>
+      {sessionUserId ? <UserSessions onClose={() => setSessionUserId(null)} userId={sessionUserId} /> : null}
     </section>
   );
 }
+
+function formatTime(value: string) {
+  return value ? new Date(value).toLocaleString() : "-";
+}
+
+// 用户的登录会话：默认只看仍有效的，可强制下线
+function UserSessions({ userId, onClose }: { userId: number; onClose: () => void }) {
+  const queryClient = useQueryClient();
+  const [includeInactive, setIncludeInactive] = useState(false);
+  const query = useQuery({
+    queryKey: ["admin-user-sessions", userId, includeInactive],
+    queryFn: () => listUserSessions(userId, includeInactive, 1, 100),
+  });
+  const revokeMutation = useMutation({
+    mutationFn: (sessionId: number) => revokeUserSession(userId, sessionId),
+    onSuccess: () => void queryClient.invalidateQueries({ queryKey: ["admin-user-sessions", userId] }),
+  });
+  const sessions = query.data?.items ?? [];
+
+  return (
+    <div className="table-card">
+      <div className="card-head">
+        <h2>用户 {userId} 的登录设备</h2>
+        <label>
+          <input checked={includeInactive} onChange={(event) => setIncludeInactive(event.target.checked)} type="checkbox" />
+          包含已下线/已过期
+        </label>
+        <button className="outline-button" onClick={onClose} type="button">
+          关闭
+        </button>
+      </div>
+      {query.isError ? <div className="error-box">{(query.error as Error).message}</div> : null}
+      {revokeMutation.isError ? <div className="error-box">{(revokeMutation.error as Error).message}</div> : null}
+      <table className="table">
+        <thead>
+          <tr>
+            <th>设备</th>
+            <th>IP / 所在地</th>
+            <th>登录 IP</th>
+            <th>登录时间</th>
+            <th>最近活跃</th>
+            <th>状态</th>
+            <th>操作</th>
+          </tr>
+        </thead>
+        <tbody>
+          {sessions.map((session) => (
+            <tr key={session.id}>
+              <td title={session.user_agent}>{session.device_name || "-"}</td>
+              <td>{[session.ip, session.location].filter(Boolean).join(" · ") || "-"}</td>
+              <td>{session.login_ip || "-"}</td>
+              <td>{formatTime(session.created_at)}</td>
+              <td>{formatTime(session.last_seen_at)}</td>
+              <td>{session.active ? "有效" : session.revoked_at ? "已下线" : "已过期"}</td>
+              <td>
+                {session.active ? (
+                  <button
+                    className="outline-button"
+                    disabled={revokeMutation.isPending}
+                    onClick={() => revokeMutation.mutate(session.id)}
+                    type="button"
+                  >
+                    强制下线
+                  </button>
+                ) : null}
+              </td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+      {!query.isLoading && sessions.length === 0 ? <div className="muted">暂无登录记录</div> : null}
+    </div>
+  );
+}
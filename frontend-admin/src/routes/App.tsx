@@ -9,6 +9,11 @@ import { SeckillPage } from "@/pages/SeckillPage";
 import { SkusPage } from "@/pages/SkusPage";
 import { CategoriesPage } from "@/pages/CategoriesPage";
 import { BannersPage } from "@/pages/BannersPage";
+import { BrandsPage } from "@/pages/BrandsPage";
+import { AttrsPage } from "@/pages/AttrsPage";
+import { ReviewsPage } from "@/pages/ReviewsPage";
+import { CatalogJobsPage } from "@/pages/CatalogJobsPage";
+import { SchedulesPage } from "@/pages/SchedulesPage";
 import { useAdminAuthStore } from "@/stores/adminAuth";
 
 function Guard({ children }: { children: JSX.Element }) {
@@ -34,9 +39,14 @@ export function App() {
         <Route index element={<DashboardPage />} />
         <Route path="users" element={<UsersPage />} />
         <Route path="products" element={<ProductsAdminPage />} />
+        <Route path="reviews" element={<ReviewsPage />} />
+        <Route path="schedules" element={<SchedulesPage />} />
+        <Route path="catalog" element={<CatalogJobsPage />} />
         <Route path="skus" element={<SkusPage />} />
         <Route path="categories" element={<CategoriesPage />} />
+        <Route path="attrs" element={<AttrsPage />} />
         <Route path="banners" element={<BannersPage />} />
+        <Route path="brands" element={<BrandsPage />} />
         <Route path="orders" element={<OrdersPage />} />
         <Route path="seckill" element={<SeckillPage />} />
       </Route>
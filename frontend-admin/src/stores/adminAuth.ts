@@ -2,25 +2,36 @@ import { create } from "zustand";
 
 interface AdminAuthState {
   token: string;
+  refreshToken: string;
   username: string;
-  setAuth: (token: string, username: string) => void;
+  setAuth: (token: string, username: string, refreshToken?: string) => void;
+  setTokens: (token: string, refreshToken: string) => void;
   logout: () => void;
 }
 
 const TOKEN_KEY = "go-ecom-admin-token";
+const REFRESH_TOKEN_KEY = "go-ecom-admin-refresh-token";
 const USERNAME_KEY = "go-ecom-admin-username";
 
 export const useAdminAuthStore = create<AdminAuthState>((set) => ({
   token: window.localStorage.getItem(TOKEN_KEY) ?? "",
+  refreshToken: window.localStorage.getItem(REFRESH_TOKEN_KEY) ?? "",
   username: window.localStorage.getItem(USERNAME_KEY) ?? "",
-  setAuth: (token, username) => {
+  setAuth: (token, username, refreshToken = "") => {
     window.localStorage.setItem(TOKEN_KEY, token);
+    window.localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
     window.localStorage.setItem(USERNAME_KEY, username);
-    set({ token, username });
+    set({ token, refreshToken, username });
+  },
+  setTokens: (token, refreshToken) => {
+    window.localStorage.setItem(TOKEN_KEY, token);
+    window.localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+    set({ token, refreshToken });
   },
   logout: () => {
     window.localStorage.removeItem(TOKEN_KEY);
+    window.localStorage.removeItem(REFRESH_TOKEN_KEY);
     window.localStorage.removeItem(USERNAME_KEY);
-    set({ token: "", username: "" });
+    set({ token: "", refreshToken: "", username: "" });
   },
 }));
@@ -1,15 +1,21 @@
 import { apiClient } from "@/api/client";
+import * as gen from "@/api/generated";
 import type {
   Address,
   ApiResponse,
+  AttrFacet,
   Banner,
+  Brand,
   CartItem,
   Coupon,
   LoginPayload,
+  LoginSession,
   Order,
   OrderListData,
   Product,
   ProductListData,
+  ProductPageData,
+  Region,
   Review,
   ReviewStats,
   SearchProductResult,
@@ -83,6 +89,7 @@ function normalizeProduct(input: Record<string, unknown>): Product {
     sales: pickNumber(input.sales),
     detail: pickString(input.detail),
     is_hot: pickNumber(input.is_hot ?? input.isHot),
+    attrs: (input.attrs ?? {}) as Record<string, string>,
   };
 }
 
@@ -94,6 +101,8 @@ function normalizeSearchProduct(input: Record<string, unknown>): SearchProductRe
     price: pickString(input.price, String(pickNumber(input.price))),
     sales: pickNumber(input.sales),
     score: pickNumber(input.score),
+    brand_id: pickNumber(input.brand_id ?? input.brandId),
+    brand_name: pickString(input.brand_name ?? input.brandName),
   };
 }
 
@@ -163,6 +172,32 @@ function normalizeAddress(input: Record<string, unknown>): Address {
     detail: pickString(input.detail),
     postal_code: pickString(input.postal_code ?? input.postalCode),
     is_default: pickNumber(input.is_default ?? input.isDefault),
+    province_code: pickString(input.province_code ?? input.provinceCode),
+    city_code: pickString(input.city_code ?? input.cityCode),
+    district_code: pickString(input.district_code ?? input.districtCode),
+  };
+}
+
+function normalizeRegion(input: Record<string, unknown>): Region {
+  return {
+    code: pickString(input.code),
+    name: pickString(input.name),
+    level: pickNumber(input.level),
+    parent_code: pickString(input.parent_code ?? input.parentCode),
+    has_children: Boolean(input.has_children ?? input.hasChildren),
+  };
+}
+
+function normalizeSession(input: Record<string, unknown>): LoginSession {
+  return {
+    id: pickNumber(input.id),
+    device_name: pickString(input.device_name ?? input.deviceName),
+    user_agent: pickString(input.user_agent ?? input.userAgent),
+    ip: pickString(input.ip),
+    location: pickString(input.location),
+    created_at: pickString(input.created_at ?? input.createdAt),
+    last_seen_at: pickString(input.last_seen_at ?? input.lastSeenAt),
+    current: Boolean(input.current),
   };
 }
 
@@ -302,26 +337,51 @@ export interface LoginFormValues {
 }
 
 export async function login(values: LoginFormValues) {
-  const response = await apiClient.post<ApiResponse<LoginPayload>>("/api/v1/user/login", values);
-  const payload = response.data;
+  const payload = await gen.login({
+    username: values.username,
+    password: values.password,
+    loginType: values.login_type,
+  });
   return {
-    ...payload,
+    code: payload.code ?? 0,
+    message: payload.message ?? "",
     data: {
-      ...payload.data,
-      user: normalizeUserProfile(payload.data.user as unknown as Record<string, unknown>),
-      expire_time: pickNumber((payload.data as unknown as Record<string, unknown>).expire_time ?? (payload.data as unknown as Record<string, unknown>).expireTime),
-    },
+      user: normalizeUserProfile((payload.data?.user ?? {}) as unknown as Record<string, unknown>),
+      token: payload.data?.token ?? "",
+      expire_time: pickNumber(payload.data?.expireTime),
+      refresh_token: payload.data?.refreshToken ?? "",
+    } as LoginPayload,
   };
 }
 
+export async function logout(refreshToken: string) {
+  return gen.logout({ refreshToken });
+}
+
+export async function listMySessions() {
+  const payload = await gen.listMySessions();
+  return (payload.data || []).map((item) => normalizeSession(item as unknown as Record<string, unknown>));
+}
+
+export async function revokeSession(sessionId: number) {
+  return gen.revokeSession({ sessionId });
+}
+
 export async function register(values: {
   username: string;
   password: string;
   phone: string;
   email: string;
 }) {
-  const response = await apiClient.post<ApiResponse<unknown>>("/api/v1/user/register", values);
-  return response.data;
+  return gen.register(values);
+}
+
+export async function requestPasswordReset(target: string) {
+  return gen.requestPasswordReset({ target });
+}
+
+export async function resetPassword(token: string, newPassword: string) {
+  return gen.resetPassword({ token, newPassword });
 }
 
 export async function listProducts(params: Record<string, string | number | undefined>) {
@@ -360,6 +420,27 @@ export async function getProduct(id: string) {
   };
 }
 
+export async function getProductPage(id: string) {
+  const response = await apiClient.get<ApiResponse<ProductPageData>>(`/api/v1/pages/product/${id}`);
+  const payload = response.data;
+  const raw = payload.data as unknown as Record<string, unknown>;
+  const list = (key: string) => (raw?.[key] as unknown[] | undefined) || [];
+  return {
+    ...payload,
+    data: {
+      product: normalizeProduct((raw?.product || {}) as Record<string, unknown>),
+      skus: list("skus").map((item) => normalizeSku(item as Record<string, unknown>)),
+      reviews: list("reviews").map((item) => normalizeReview(item as Record<string, unknown>)),
+      review_total: pickNumber(raw?.review_total),
+      review_stats: raw?.review_stats
+        ? normalizeReviewStats(raw.review_stats as Record<string, unknown>)
+        : undefined,
+      seckill: list("seckill").map((item) => normalizeSeckillActivity(item as Record<string, unknown>)),
+      degraded: (raw?.degraded as string[] | undefined) || [],
+    } as ProductPageData,
+  };
+}
+
 export async function listSkus(params: Record<string, string | number | undefined>) {
   const response = await apiClient.get<ApiResponse<SkuListData>>("/api/v1/skus", { params });
   const payload = response.data;
@@ -458,6 +539,11 @@ export async function updateAddress(payload: Address) {
   };
 }
 
+export async function getRegions(parentCode = "") {
+  const payload = await gen.getRegions({ parentCode });
+  return (payload.data || []).map((item) => normalizeRegion(item as unknown as Record<string, unknown>));
+}
+
 export async function deleteAddress(id: number, userId: number) {
   const response = await apiClient.delete<ApiResponse<unknown>>(`/api/v1/user/address/${id}`, {
     data: { id, user_id: userId },
@@ -642,19 +728,39 @@ export async function searchProducts(params: {
   page?: number;
   page_size?: number;
   category_id?: number;
+  brand_id?: number;
+  attr_filter?: string;
   sort_by?: string;
 }) {
-  const response = await apiClient.get<ApiResponse<SearchProductResult[]> & { total?: number }>(
-    "/api/v1/search/products",
-    { params },
-  );
+  const response = await apiClient.get<
+    ApiResponse<SearchProductResult[]> & { total?: number; facets?: Array<Record<string, unknown>> }
+  >("/api/v1/search/products", { params });
   const payload = response.data;
   return {
     ...payload,
     data: (payload.data || []).map((item) => normalizeSearchProduct(item as unknown as Record<string, unknown>)),
+    facets: (payload.facets || []).map(
+      (facet): AttrFacet => ({
+        name: pickString(facet.name),
+        values: (Array.isArray(facet.values) ? facet.values : []).map((item: Record<string, unknown>) => ({
+          value: pickString(item.value),
+          count: pickNumber(item.count),
+        })),
+      }),
+    ),
   };
 }
 
+// 启用中的品牌，用于搜索页按品牌筛选
+export async function listBrands(): Promise<Brand[]> {
+  const payload = await gen.listBrands({ status: 1, page: 1, pageSize: 100 });
+  return (payload.data ?? []).map((item) => ({
+    id: pickNumber(item.id),
+    name: pickString(item.name),
+    logo: resolveAssetUrl(pickString(item.logo)),
+  }));
+}
+
 export async function getSearchSuggestions(keyword: string, limit = 8) {
   const response = await apiClient.get<ApiResponse<string[]>>("/api/v1/search/suggestions", {
     params: { keyword, limit },
@@ -697,8 +803,9 @@ export async function confirmReceive(orderId: number, orderNo?: string) {
 
 export async function uploadFile(file: File, category = "image") {
   const formData = new FormData();
-  formData.append("file", file);
+  // category 必须在 file 之前：网关流式转发文件时需要先确定分类
   formData.append("category", category);
+  formData.append("file", file);
   const response = await apiClient.post<ApiResponse<{ file_url?: string; file_name?: string; file_id?: string }>>(
     "/api/v1/files/upload",
     formData,
@@ -1,4 +1,5 @@
 import { Link, NavLink, Outlet } from "react-router-dom";
+import { logout as logoutSession } from "@/api/store";
 import { useAuthStore } from "@/stores/auth";
 
 const navItems = [
@@ -11,7 +12,14 @@ const navItems = [
 ];
 
 export function StoreLayout() {
-  const { profile, logout } = useAuthStore();
+  const { profile, refreshToken, logout } = useAuthStore();
+
+  // 先通知服务端吊销令牌，失败也清除本地登录状态
+  const handleLogout = () => {
+    logoutSession(refreshToken)
+      .catch(() => undefined)
+      .finally(logout);
+  };
 
   return (
     <div className="app-shell">
@@ -34,7 +42,7 @@ export function StoreLayout() {
           {profile ? (
             <>
               <span className="muted">你好，{profile.nickname || profile.username}</span>
-              <button className="ghost-button" onClick={logout} type="button">
+              <button className="ghost-button" onClick={handleLogout} type="button">
                 退出
               </button>
             </>
@@ -1,6 +1,6 @@
 import { FormEvent, useState } from "react";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
-import { addAddress, deleteAddress, getAddresses, updateAddress } from "@/api/store";
+import { addAddress, deleteAddress, getAddresses, getRegions, updateAddress } from "@/api/store";
 import { useAuthStore } from "@/stores/auth";
 import type { Address } from "@/types";
 
@@ -49,9 +49,13 @@ export function AddressesPage() {
       user_id: profile.id,
       receiver_name: String(formData.get("receiver_name") || ""),
       receiver_phone: String(formData.get("receiver_phone") || ""),
-      province: String(formData.get("province") || ""),
+      // 省市区提交区划代码，没有下级数据的地区（港澳台、东莞市等）提交手填的名称
+      province: "",
       city: String(formData.get("city") || ""),
       district: String(formData.get("district") || ""),
+      province_code: String(formData.get("province_code") || ""),
+      city_code: String(formData.get("city_code") || ""),
+      district_code: String(formData.get("district_code") || ""),
       detail: String(formData.get("detail") || ""),
       postal_code: String(formData.get("postal_code") || ""),
       is_default: Number(formData.get("is_default") || 0),
@@ -108,18 +112,7 @@ export function AddressesPage() {
             手机号
             <input defaultValue={editing?.receiver_phone ?? emptyForm.receiver_phone} name="receiver_phone" required />
           </label>
-          <label>
-            省份
-            <input defaultValue={editing?.province ?? emptyForm.province} name="province" required />
-          </label>
-          <label>
-            城市
-            <input defaultValue={editing?.city ?? emptyForm.city} name="city" required />
-          </label>
-          <label>
-            区县
-            <input defaultValue={editing?.district ?? emptyForm.district} name="district" required />
-          </label>
+          <RegionFields initial={editing ?? emptyForm} />
           <label>
             详细地址
             <input defaultValue={editing?.detail ?? emptyForm.detail} name="detail" required />
@@ -143,3 +136,101 @@ export function AddressesPage() {
     </section>
   );
 }
+
+function RegionFields({ initial }: { initial: Omit<Address, "id"> }) {
+  const [provinceCode, setProvinceCode] = useState(initial.province_code ?? "");
+  const [cityCode, setCityCode] = useState(initial.city_code ?? "");
+  const [districtCode, setDistrictCode] = useState(initial.district_code ?? "");
+
+  const provinces = useQuery({ queryKey: ["regions", ""], queryFn: () => getRegions(), staleTime: Infinity });
+  const province = provinces.data?.find((item) => item.code === provinceCode);
+  const cities = useQuery({
+    queryKey: ["regions", provinceCode],
+    queryFn: () => getRegions(provinceCode),
+    enabled: Boolean(province?.has_children),
+    staleTime: Infinity,
+  });
+  const city = cities.data?.find((item) => item.code === cityCode);
+  const districts = useQuery({
+    queryKey: ["regions", cityCode],
+    queryFn: () => getRegions(cityCode),
+    enabled: Boolean(city?.has_children),
+    staleTime: Infinity,
+  });
+
+  const freeCity = Boolean(province && !province.has_children);
+  const freeDistrict = freeCity || Boolean(city && !city.has_children);
+
+  return (
+    <>
+      {initial.province && !initial.province_code ? (
+        <div className="muted">
+          原地址：{initial.province} {initial.city} {initial.district}，请重新选择省市区
+        </div>
+      ) : null}
+      <label>
+        省份
+        <select
+          name="province_code"
+          onChange={(event) => {
+            setProvinceCode(event.target.value);
+            setCityCode("");
+            setDistrictCode("");
+          }}
+          required
+          value={provinceCode}
+        >
+          <option value="">请选择</option>
+          {provinces.data?.map((item) => (
+            <option key={item.code} value={item.code}>
+              {item.name}
+            </option>
+          ))}
+        </select>
+      </label>
+      <label>
+        城市
+        {freeCity ? (
+          <input defaultValue={initial.city} name="city" />
+        ) : (
+          <select
+            name="city_code"
+            onChange={(event) => {
+              setCityCode(event.target.value);
+              setDistrictCode("");
+            }}
+            required
+            value={cityCode}
+          >
+            <option value="">请选择</option>
+            {cities.data?.map((item) => (
+              <option key={item.code} value={item.code}>
+                {item.name}
+              </option>
+            ))}
+          </select>
+        )}
+      </label>
+      <label>
+        区县
+        {freeDistrict ? (
+          <input defaultValue={initial.district} name="district" />
+        ) : (
+          <select
+            name="district_code"
+            onChange={(event) => setDistrictCode(event.target.value)}
+            required
+            value={districtCode}
+          >
+            <option value="">请选择</option>
+            {districts.data?.map((item) => (
+              <option key={item.code} value={item.code}>
+                {item.name}
+              </option>
+            ))}
+          </select>
+        )}
+      </label>
+    </>
+  );
+}
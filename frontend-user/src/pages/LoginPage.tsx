@@ -1,5 +1,5 @@
 import { FormEvent, useState } from "react";
-import { useNavigate } from "react-router-dom";
+import { Link, useNavigate } from "react-router-dom";
 import { login, register } from "@/api/store";
 import { useAuthStore } from "@/stores/auth";
 
@@ -25,7 +25,7 @@ export function LoginPage() {
           login_type: Number(formData.get("login_type") || 1),
         });
 
-        setAuth(response.data.token, response.data.user);
+        setAuth(response.data.token, response.data.user, response.data.refresh_token);
         navigate("/");
       } else {
         await register({
@@ -99,6 +99,11 @@ export function LoginPage() {
             {submitting ? "提交中..." : mode === "login" ? "登录" : "注册"}
           </button>
         </form>
+        {mode === "login" ? (
+          <p className="muted">
+            <Link to="/reset-password">忘记密码？</Link>
+          </p>
+        ) : null}
       </div>
     </section>
   );
@@ -1,14 +1,7 @@
 import { FormEvent, useEffect, useMemo, useState } from "react";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 import { useParams } from "react-router-dom";
-import {
-  addCartItem,
-  createReview,
-  getProduct,
-  getProductReviews,
-  getReviewStats,
-  listSkus,
-} from "@/api/store";
+import { addCartItem, createReview, getProductPage } from "@/api/store";
 import { useAuthStore } from "@/stores/auth";
 
 export function ProductDetailPage() {
@@ -18,27 +11,10 @@ export function ProductDetailPage() {
   const [reviewMessage, setReviewMessage] = useState("");
   const [selectedSkuId, setSelectedSkuId] = useState<number>(0);
 
-  const productQuery = useQuery({
-    queryKey: ["product", id],
-    queryFn: () => getProduct(id),
-    enabled: Boolean(id),
-  });
-
-  const reviewsQuery = useQuery({
-    queryKey: ["product-reviews", id],
-    queryFn: () => getProductReviews(id),
-    enabled: Boolean(id),
-  });
-
-  const skusQuery = useQuery({
-    queryKey: ["product-skus", id],
-    queryFn: () => listSkus({ product_id: id, status: 1, page: 1, page_size: 50 }),
-    enabled: Boolean(id),
-  });
-
-  const statsQuery = useQuery({
-    queryKey: ["product-review-stats", id],
-    queryFn: () => getReviewStats(id),
+  // 商品详情页数据由网关 BFF 接口一次聚合返回（商品、SKU、评价、评价统计、秒杀等）
+  const pageQuery = useQuery({
+    queryKey: ["product-page", id],
+    queryFn: () => getProductPage(id),
     enabled: Boolean(id),
   });
 
@@ -54,13 +30,15 @@ export function ProductDetailPage() {
     mutationFn: createReview,
     onSuccess: () => {
       setReviewMessage("评价已提交");
-      void queryClient.invalidateQueries({ queryKey: ["product-reviews", id] });
-      void queryClient.invalidateQueries({ queryKey: ["product-review-stats", id] });
+      void queryClient.invalidateQueries({ queryKey: ["product-page", id] });
     },
   });
 
-  const product = productQuery.data?.data;
-  const skuList = skusQuery.data?.data?.list ?? [];
+  const pageData = pageQuery.data?.data;
+  const product = pageData?.product;
+  const skuList = pageData?.skus ?? [];
+  const reviews = pageData?.reviews ?? [];
+  const reviewStats = pageData?.review_stats;
   const selectedSku =
     skuList.find((item) => item.id === selectedSkuId) ??
     skuList.find((item) => item.status === 1) ??
@@ -88,12 +66,9 @@ export function ProductDetailPage() {
   return (
     <section className="detail-shell">
       <div className="detail-main panel">
-        {productQuery.isLoading ? <div>正在加载商品详情...</div> : null}
-        {productQuery.isError ? (
-          <div className="error-box">{(productQuery.error as Error).message}</div>
-        ) : null}
-        {skusQuery.isError ? (
-          <div className="error-box">{(skusQuery.error as Error).message}</div>
+        {pageQuery.isLoading ? <div>正在加载商品详情...</div> : null}
+        {pageQuery.isError ? (
+          <div className="error-box">{(pageQuery.error as Error).message}</div>
         ) : null}
         {product ? (
           <>
@@ -117,7 +92,7 @@ export function ProductDetailPage() {
               <div className="detail-meta">
                 <span>库存 {selectedSku?.stock ?? product.stock}</span>
                 <span>销量 {product.sales}</span>
-                <span>评分 {statsQuery.data?.data?.average_rating ?? 0}</span>
+                <span>评分 {reviewStats?.average_rating ?? 0}</span>
               </div>
               {skuList.length > 0 ? (
                 <div className="sku-section">
@@ -168,6 +143,17 @@ export function ProductDetailPage() {
               {addCartMutation.isSuccess ? (
                 <div className="success-box">已加入购物车。</div>
               ) : null}
+              {Object.keys(product.attrs ?? {}).length > 0 ? (
+                <article className="description">
+                  <h2>规格参数</h2>
+                  {Object.entries(product.attrs ?? {}).map(([name, value]) => (
+                    <p key={name}>
+                      <span className="muted">{name}：</span>
+                      {value.split(",").join("、")}
+                    </p>
+                  ))}
+                </article>
+              ) : null}
               <article className="description">
                 <h2>商品描述</h2>
                 <p>{product.detail || "暂无更多商品描述。"}</p>
@@ -181,12 +167,12 @@ export function ProductDetailPage() {
         <div className="section-head">
           <h2>用户评价</h2>
           <span className="muted">
-            共 {statsQuery.data?.data?.total_count ?? 0} 条，均分{" "}
-            {statsQuery.data?.data?.average_rating ?? 0}
+            共 {reviewStats?.total_count ?? 0} 条，均分{" "}
+            {reviewStats?.average_rating ?? 0}
           </span>
         </div>
         <div className="review-list">
-          {(reviewsQuery.data?.data || []).map((review) => (
+          {reviews.map((review) => (
             <article className="review-card" key={review.id}>
               <div className="review-head">
                 <strong>用户 {review.user_id}</strong>
@@ -197,7 +183,7 @@ export function ProductDetailPage() {
               {review.reply_content ? <div className="reply-box">回复：{review.reply_content}</div> : null}
             </article>
           ))}
-          {!reviewsQuery.isLoading && (reviewsQuery.data?.data?.length ?? 0) === 0 ? (
+          {!pageQuery.isLoading && reviews.length === 0 ? (
             <div className="muted">暂无评价</div>
           ) : null}
         </div>
@@ -1,7 +1,14 @@
 import { FormEvent, useEffect, useState } from "react";
-import { useMutation, useQuery } from "@tanstack/react-query";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 import { Link } from "react-router-dom";
-import { getUserInfo, getUserCoupons, updateUserInfo, uploadFile } from "@/api/store";
+import {
+  getUserInfo,
+  getUserCoupons,
+  listMySessions,
+  revokeSession,
+  updateUserInfo,
+  uploadFile,
+} from "@/api/store";
 import { useAuthStore } from "@/stores/auth";
 
 export function ProfilePage() {
@@ -144,6 +151,59 @@ export function ProfilePage() {
           </div>
         </div>
       </div>
+      <LoginSessionsPanel />
     </section>
   );
 }
+
+function formatSessionTime(value: string) {
+  return value ? new Date(value).toLocaleString() : "-";
+}
+
+// 登录设备：列出仍有效的登录，可下线其他设备
+function LoginSessionsPanel() {
+  const queryClient = useQueryClient();
+  const sessionsQuery = useQuery({
+    queryKey: ["login-sessions"],
+    queryFn: listMySessions,
+  });
+  const revokeMutation = useMutation({
+    mutationFn: revokeSession,
+    onSuccess: () => void queryClient.invalidateQueries({ queryKey: ["login-sessions"] }),
+  });
+
+  return (
+    <div className="panel">
+      <h2>登录设备</h2>
+      {revokeMutation.error ? <div className="error-box">{revokeMutation.error.message}</div> : null}
+      <div className="stack compact">
+        {(sessionsQuery.data || []).map((session) => (
+          <div className="cart-row" key={session.id}>
+            <div>
+              <strong>{session.device_name || "未知设备"}</strong>
+              {session.current ? <span className="muted">（当前设备）</span> : null}
+              <p className="muted">
+                {[session.ip, session.location].filter(Boolean).join(" · ")}
+                {" · 最近活跃 "}
+                {formatSessionTime(session.last_seen_at)}
+                {" · 登录于 "}
+                {formatSessionTime(session.created_at)}
+              </p>
+            </div>
+            {session.current ? null : (
+              <button
+                className="ghost-button"
+                disabled={revokeMutation.isPending}
+                onClick={() => revokeMutation.mutate(session.id)}
+                type="button"
+              >
+                下线
+              </button>
+            )}
+          </div>
+        ))}
+        {sessionsQuery.data?.length === 0 ? <p className="muted">暂无登录设备</p> : null}
+      </div>
+    </div>
+  );
+}
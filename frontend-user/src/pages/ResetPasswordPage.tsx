@@ -0,0 +1,96 @@
+import { FormEvent, useState } from "react";
+import { Link, useNavigate, useSearchParams } from "react-router-dom";
+import { requestPasswordReset, resetPassword } from "@/api/store";
+
+// 找回密码：没有 token 时申请重置链接，从短信/邮件中的链接打开时带 token 设置新密码
+export function ResetPasswordPage() {
+  const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get("token") || "";
+  const [error, setError] = useState("");
+  const [notice, setNotice] = useState("");
+  const [submitting, setSubmitting] = useState(false);
+
+  async function handleRequest(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    setSubmitting(true);
+    setError("");
+
+    const formData = new FormData(event.currentTarget);
+
+    try {
+      const response = await requestPasswordReset(String(formData.get("target") || "").trim());
+      setNotice(response.message || "如果该手机号/邮箱已注册，重置链接已发送");
+    } catch (submitError) {
+      setError(submitError instanceof Error ? submitError.message : "提交失败");
+    } finally {
+      setSubmitting(false);
+    }
+  }
+
+  async function handleReset(event: FormEvent<HTMLFormElement>) {
+    event.preventDefault();
+    const formData = new FormData(event.currentTarget);
+    const password = String(formData.get("password") || "");
+    if (password !== String(formData.get("confirm") || "")) {
+      setError("两次输入的密码不一致");
+      return;
+    }
+    setSubmitting(true);
+    setError("");
+
+    try {
+      await resetPassword(token, password);
+      navigate("/login");
+    } catch (submitError) {
+      setError(submitError instanceof Error ? submitError.message : "重置失败");
+    } finally {
+      setSubmitting(false);
+    }
+  }
+
+  return (
+    <section className="auth-shell">
+      <div className="auth-card">
+        <h1>{token ? "设置新密码" : "找回密码"}</h1>
+        {token ? (
+          <>
+            <p className="muted">至少 8 位，不能使用过于简单的密码。重置后所有设备需要重新登录。</p>
+            <form className="form" onSubmit={handleReset}>
+              <label>
+                新密码
+                <input autoComplete="new-password" name="password" required type="password" />
+              </label>
+              <label>
+                确认新密码
+                <input autoComplete="new-password" name="confirm" required type="password" />
+              </label>
+              {error ? <div className="error-box">{error}</div> : null}
+              <button className="primary-button" disabled={submitting} type="submit">
+                {submitting ? "提交中..." : "重置密码"}
+              </button>
+            </form>
+          </>
+        ) : (
+          <>
+            <p className="muted">输入注册时填写的手机号或邮箱，我们会发送重置链接。</p>
+            <form className="form" onSubmit={handleRequest}>
+              <label>
+                手机号 / 邮箱
+                <input name="target" placeholder="请输入手机号或邮箱" required />
+              </label>
+              {error ? <div className="error-box">{error}</div> : null}
+              {notice ? <p className="muted">{notice}</p> : null}
+              <button className="primary-button" disabled={submitting} type="submit">
+                {submitting ? "发送中..." : "发送重置链接"}
+              </button>
+            </form>
+          </>
+        )}
+        <p className="muted">
+          <Link to="/login">返回登录</Link>
+        </p>
+      </div>
+    </section>
+  );
+}
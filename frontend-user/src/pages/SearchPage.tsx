@@ -1,20 +1,74 @@
 import { useEffect, useState } from "react";
 import { useQuery } from "@tanstack/react-query";
 import { Link, useSearchParams } from "react-router-dom";
-import { getSearchSuggestions, searchProducts } from "@/api/store";
+import { getSearchSuggestions, listBrands, searchProducts } from "@/api/store";
+
+// 属性筛选在地址栏里写成 "颜色:红色,蓝色;尺码:L"，与搜索接口的 attr_filter 一致
+function parseAttrFilter(text: string) {
+  const result: Record<string, string[]> = {};
+  text.split(";").forEach((part) => {
+    const [name, values = ""] = part.split(":");
+    const picked = values.split(",").filter(Boolean);
+    if (name && picked.length > 0) result[name] = picked;
+  });
+  return result;
+}
+
+function formatAttrFilter(filter: Record<string, string[]>) {
+  return Object.entries(filter)
+    .filter(([, values]) => values.length > 0)
+    .map(([name, values]) => `${name}:${values.join(",")}`)
+    .join(";");
+}
 
 export function SearchPage() {
   const [searchParams, setSearchParams] = useSearchParams();
   const [keyword, setKeyword] = useState(searchParams.get("keyword") || "");
   const activeKeyword = searchParams.get("keyword") || "";
   const activeSort = searchParams.get("sort_by") || "score_desc";
+  const activeBrand = Number(searchParams.get("brand_id") || 0);
+  const activeAttrFilter = searchParams.get("attr_filter") || "";
+  const activeAttrs = parseAttrFilter(activeAttrFilter);
 
   const resultQuery = useQuery({
-    queryKey: ["search-products", activeKeyword, activeSort],
+    queryKey: ["search-products", activeKeyword, activeSort, activeBrand, activeAttrFilter],
     queryFn: () =>
-      searchProducts({ keyword: activeKeyword, page: 1, page_size: 20, sort_by: activeSort }),
+      searchProducts({
+        keyword: activeKeyword,
+        page: 1,
+        page_size: 20,
+        sort_by: activeSort,
+        brand_id: activeBrand || undefined,
+        attr_filter: activeAttrFilter || undefined,
+      }),
+  });
+
+  const brandsQuery = useQuery({
+    queryKey: ["brands"],
+    queryFn: listBrands,
+    staleTime: 5 * 60 * 1000,
   });
 
+  // 更新查询参数，保留其他筛选条件
+  const applyFilters = (next: { keyword?: string; sort_by?: string; brand_id?: number; attr_filter?: string }) => {
+    const params: Record<string, string> = {};
+    const nextKeyword = next.keyword ?? keyword;
+    const nextBrand = next.brand_id ?? activeBrand;
+    const nextAttrFilter = next.attr_filter ?? activeAttrFilter;
+    if (nextKeyword) params.keyword = nextKeyword;
+    params.sort_by = next.sort_by ?? activeSort;
+    if (nextBrand) params.brand_id = String(nextBrand);
+    if (nextAttrFilter) params.attr_filter = nextAttrFilter;
+    setSearchParams(params);
+  };
+
+  // 切换某个属性值：同一属性可多选，结果为任一取值命中
+  const toggleAttr = (name: string, value: string) => {
+    const current = activeAttrs[name] ?? [];
+    const nextValues = current.includes(value) ? current.filter((item) => item !== value) : [...current, value];
+    applyFilters({ attr_filter: formatAttrFilter({ ...activeAttrs, [name]: nextValues }) });
+  };
+
   const suggestionsQuery = useQuery({
     queryKey: ["search-suggestions", keyword],
     queryFn: () => getSearchSuggestions(keyword, 6),
@@ -35,19 +89,24 @@ export function SearchPage() {
           className="toolbar-form"
           onSubmit={(event) => {
             event.preventDefault();
-            setSearchParams(keyword ? { keyword, sort_by: activeSort } : { sort_by: activeSort });
+            applyFilters({});
           }}
         >
           <input onChange={(e) => setKeyword(e.target.value)} placeholder="输入关键词搜索商品" value={keyword} />
-          <select
-            onChange={(e) => setSearchParams(keyword ? { keyword, sort_by: e.target.value } : { sort_by: e.target.value })}
-            value={activeSort}
-          >
+          <select onChange={(e) => applyFilters({ sort_by: e.target.value })} value={activeSort}>
             <option value="score_desc">综合排序</option>
             <option value="sales_desc">销量优先</option>
             <option value="price_asc">价格从低到高</option>
             <option value="price_desc">价格从高到低</option>
           </select>
+          <select onChange={(e) => applyFilters({ brand_id: Number(e.target.value) })} value={String(activeBrand)}>
+            <option value="0">全部品牌</option>
+            {(brandsQuery.data ?? []).map((brand) => (
+              <option key={brand.id} value={brand.id}>
+                {brand.name}
+              </option>
+            ))}
+          </select>
           <button className="primary-button" type="submit">
             搜索
           </button>
@@ -62,7 +121,7 @@ export function SearchPage() {
               <button
                 className="tab-button"
                 key={item}
-                onClick={() => setSearchParams({ keyword: item, sort_by: activeSort })}
+                onClick={() => applyFilters({ keyword: item })}
                 type="button"
               >
                 {item}
@@ -72,6 +131,31 @@ export function SearchPage() {
         </div>
       ) : null}
 
+      {(resultQuery.data?.facets?.length ?? 0) > 0 || activeAttrFilter ? (
+        <div className="panel">
+          {(resultQuery.data?.facets ?? []).map((facet) => (
+            <div className="filter-chips" key={facet.name}>
+              <span className="muted">{facet.name}</span>
+              {facet.values.map((item) => (
+                <button
+                  className={`tab-button ${activeAttrs[facet.name]?.includes(item.value) ? "active" : ""}`}
+                  key={item.value}
+                  onClick={() => toggleAttr(facet.name, item.value)}
+                  type="button"
+                >
+                  {item.value}（{item.count}）
+                </button>
+              ))}
+            </div>
+          ))}
+          {activeAttrFilter ? (
+            <button className="tab-button" onClick={() => applyFilters({ attr_filter: "" })} type="button">
+              清除属性筛选
+            </button>
+          ) : null}
+        </div>
+      ) : null}
+
       <div className="product-grid">
         {(resultQuery.data?.data ?? []).map((item) => (
           <Link className="product-card" key={item.product_id} to={`/products/${item.product_id}`}>
@@ -80,6 +164,7 @@ export function SearchPage() {
             </div>
             <div className="product-content">
               <h3>{item.name}</h3>
+              {item.brand_name ? <p className="muted">{item.brand_name}</p> : null}
               <p>相关度 {item.score.toFixed(2)}</p>
               <div className="price-row">
                 <strong>¥{item.price}</strong>
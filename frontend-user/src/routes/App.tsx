@@ -2,6 +2,7 @@ import { Navigate, Route, Routes } from "react-router-dom";
 import { StoreLayout } from "@/components/StoreLayout";
 import { HomePage } from "@/pages/HomePage";
 import { LoginPage } from "@/pages/LoginPage";
+import { ResetPasswordPage } from "@/pages/ResetPasswordPage";
 import { ProductDetailPage } from "@/pages/ProductDetailPage";
 import { CartPage } from "@/pages/CartPage";
 import { SearchPage } from "@/pages/SearchPage";
@@ -21,6 +22,7 @@ export function App() {
       <Route element={<StoreLayout />}>
         <Route path="/" element={<HomePage />} />
         <Route path="/login" element={<LoginPage />} />
+        <Route path="/reset-password" element={<ResetPasswordPage />} />
         <Route path="/products" element={<SearchPage />} />
         <Route path="/products/:id" element={<ProductDetailPage />} />
         <Route path="/search" element={<Navigate replace to="/products" />} />
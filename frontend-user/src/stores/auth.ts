@@ -3,12 +3,15 @@ import type { UserProfile } from "@/types";
 
 interface AuthState {
   token: string;
+  refreshToken: string;
   profile: UserProfile | null;
-  setAuth: (token: string, profile: UserProfile) => void;
+  setAuth: (token: string, profile: UserProfile, refreshToken?: string) => void;
+  setTokens: (token: string, refreshToken: string) => void;
   logout: () => void;
 }
 
 const TOKEN_KEY = "go-ecom-user-token";
+const REFRESH_TOKEN_KEY = "go-ecom-user-refresh-token";
 const PROFILE_KEY = "go-ecom-user-profile";
 
 function loadProfile(): UserProfile | null {
@@ -26,15 +29,23 @@ function loadProfile(): UserProfile | null {
 
 export const useAuthStore = create<AuthState>((set) => ({
   token: window.localStorage.getItem(TOKEN_KEY) ?? "",
+  refreshToken: window.localStorage.getItem(REFRESH_TOKEN_KEY) ?? "",
   profile: loadProfile(),
-  setAuth: (token, profile) => {
+  setAuth: (token, profile, refreshToken = "") => {
     window.localStorage.setItem(TOKEN_KEY, token);
+    window.localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
     window.localStorage.setItem(PROFILE_KEY, JSON.stringify(profile));
-    set({ token, profile });
+    set({ token, refreshToken, profile });
+  },
+  setTokens: (token, refreshToken) => {
+    window.localStorage.setItem(TOKEN_KEY, token);
+    window.localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+    set({ token, refreshToken });
   },
   logout: () => {
     window.localStorage.removeItem(TOKEN_KEY);
+    window.localStorage.removeItem(REFRESH_TOKEN_KEY);
     window.localStorage.removeItem(PROFILE_KEY);
-    set({ token: "", profile: null });
+    set({ token: "", refreshToken: "", profile: null });
   },
 }));
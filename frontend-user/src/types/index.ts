@@ -22,6 +22,7 @@ export interface LoginPayload {
   user: UserProfile;
   token: string;
   expire_time: number;
+  refresh_token: string;
 }
 
 export interface Product {
@@ -36,6 +37,7 @@ export interface Product {
   sales: number;
   detail?: string;
   is_hot?: number;
+  attrs?: Record<string, string>; // 规格参数，多选值以逗号分隔
 }
 
 export interface Sku {
@@ -106,6 +108,28 @@ export interface Address {
   detail: string;
   postal_code?: string;
   is_default: number;
+  province_code?: string;
+  city_code?: string;
+  district_code?: string;
+}
+
+export interface Region {
+  code: string;
+  name: string;
+  level: number;
+  parent_code: string;
+  has_children: boolean;
+}
+
+export interface LoginSession {
+  id: number;
+  device_name: string;
+  user_agent: string;
+  ip: string;
+  location: string;
+  created_at: string;
+  last_seen_at: string;
+  current: boolean;
 }
 
 export interface OrderItem {
@@ -187,6 +211,16 @@ export interface SeckillActivity {
   enable_status: number;
 }
 
+export interface ProductPageData {
+  product: Product;
+  skus: Sku[];
+  reviews: Review[];
+  review_total: number;
+  review_stats?: ReviewStats;
+  seckill: SeckillActivity[];
+  degraded?: string[];
+}
+
 export interface SeckillActivityListData {
   list: SeckillActivity[];
   page: number;
@@ -212,6 +246,20 @@ export interface SearchProductResult {
   price: string;
   sales: number;
   score: number;
+  brand_id: number;
+  brand_name: string;
+}
+
+export interface Brand {
+  id: number;
+  name: string;
+  logo: string;
+}
+
+// 搜索结果的属性分面：属性名及各取值命中的商品数
+export interface AttrFacet {
+  name: string;
+  values: Array<{ value: string; count: number }>;
 }
 
 export interface LogisticsInfo {
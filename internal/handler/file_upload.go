@@ -3,21 +3,43 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"strings"
 
+	"github.com/zeromicro/go-zero/core/logx"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	v1 "ecommerce-system/api/file/v1"
 )
 
+// uploadChunkSize 流式转发给 file-service 的分片大小
+const uploadChunkSize = 64 << 10
+
+// sniffLen http.DetectContentType 最多使用的字节数
+const sniffLen = 512
+
+// FileUploadConfig 上传限制（在流式转发过程中校验，不会先把整个文件读入内存）
+type FileUploadConfig struct {
+	MaxFileSize  int64    // 单文件大小上限（字节）
+	MaxBatchSize int64    // 批量上传请求体总大小上限（字节）
+	MaxFiles     int      // 批量上传的最大文件数
+	AllowedTypes []string // 允许的 MIME 类型（按文件内容嗅探），支持 image/* 形式，为空表示不限制
+}
+
 // FileUploadHandler 文件上传处理器
 type FileUploadHandler struct {
 	fileServiceClient v1.FileServiceClient
 	conn              *grpc.ClientConn
+	conf              FileUploadConfig
 }
 
 // apiResp 统一 HTTP JSON 响应结构（避免 proto 的 `omitempty` 导致 code=0 被省略）
@@ -27,8 +49,18 @@ type apiResp[T any] struct {
 	Data    T      `json:"data,omitempty"`
 }
 
+// uploadError 上传失败，携带返回给客户端的 HTTP 状态码
+type uploadError struct {
+	status  int
+	message string
+}
+
+func (e *uploadError) Error() string {
+	return e.message
+}
+
 // NewFileUploadHandler 创建文件上传处理器
-func NewFileUploadHandler(fileServiceAddr string) (*FileUploadHandler, error) {
+func NewFileUploadHandler(fileServiceAddr string, conf FileUploadConfig) (*FileUploadHandler, error) {
 	conn, err := grpc.NewClient(
 		fileServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -42,6 +74,7 @@ func NewFileUploadHandler(fileServiceAddr string) (*FileUploadHandler, error) {
 	return &FileUploadHandler{
 		fileServiceClient: client,
 		conn:              conn,
+		conf:              conf,
 	}, nil
 }
 
@@ -53,106 +86,137 @@ func (h *FileUploadHandler) Close() error {
 	return nil
 }
 
-// HandleUpload 处理文件上传
+// HandleUpload 处理文件上传：逐个读取 multipart 分段，文件分段直接流式转发给 file-service。
+// category 可以放在查询参数中，或作为表单字段放在 file 字段之前（文件开始转发时分类必须已确定）。
 func (h *FileUploadHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
-	// 设置 CORS 头
-	origin := r.Header.Get("Origin")
-	if origin != "" {
-		w.Header().Set("Access-Control-Allow-Origin", origin)
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-	} else {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+	if !h.preflight(w, r) {
+		return
 	}
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length")
-	w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type")
-	w.Header().Set("Access-Control-Max-Age", "3600")
 
-	// 处理 OPTIONS 预检请求
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusNoContent)
+	// 限制请求体大小：单文件上限 + 表单字段等开销
+	if r.ContentLength > h.conf.MaxFileSize+(1<<20) {
+		writeUploadError(w, &uploadError{status: http.StatusRequestEntityTooLarge, message: "文件大小超过限制"})
 		return
 	}
+	r.Body = http.MaxBytesReader(w, r.Body, h.conf.MaxFileSize+(1<<20))
 
-	// 只处理 POST 请求
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeUploadError(w, &uploadError{status: http.StatusBadRequest, message: fmt.Sprintf("解析表单失败: %v", err)})
 		return
 	}
 
-	// 限制请求体大小（100MB，增加限制避免 413 错误）
-	r.Body = http.MaxBytesReader(w, r.Body, 100<<20) // 100MB
+	category := r.URL.Query().Get("category")
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeUploadError(w, readError(err))
+			return
+		}
 
-	// 解析 multipart/form-data（增加限制）
-	err := r.ParseMultipartForm(100 << 20) // 100MB
-	if err != nil {
-		http.Error(w, fmt.Sprintf("解析表单失败: %v", err), http.StatusBadRequest)
+		switch {
+		case part.FormName() == "category" && part.FileName() == "":
+			if category, err = readFormValue(part); err != nil {
+				writeUploadError(w, readError(err))
+				return
+			}
+		case part.FormName() == "file" && part.FileName() != "":
+			resp, err := h.streamFile(r.Context(), part, defaultCategory(category), r.Header.Get("Authorization"))
+			if err != nil {
+				writeUploadError(w, err)
+				return
+			}
+			// 返回 JSON 响应
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(apiResp[*v1.FileInfo]{
+				Code:    resp.GetCode(),
+				Message: resp.GetMessage(),
+				Data:    resp.GetData(),
+			})
+			return
+		}
+		part.Close()
+	}
+
+	writeUploadError(w, &uploadError{status: http.StatusBadRequest, message: "获取文件失败: 缺少 file 字段"})
+}
+
+// HandleBatchUpload 处理批量文件上传：每个 files 分段单独发起一次流式上传
+func (h *FileUploadHandler) HandleBatchUpload(w http.ResponseWriter, r *http.Request) {
+	if !h.preflight(w, r) {
 		return
 	}
 
-	// 获取文件
-	file, header, err := r.FormFile("file")
-	if err != nil {
-		http.Error(w, fmt.Sprintf("获取文件失败: %v", err), http.StatusBadRequest)
+	if r.ContentLength > h.conf.MaxBatchSize {
+		writeUploadError(w, &uploadError{status: http.StatusRequestEntityTooLarge, message: "上传内容超过限制"})
 		return
 	}
-	defer file.Close()
+	r.Body = http.MaxBytesReader(w, r.Body, h.conf.MaxBatchSize)
 
-	// 读取文件数据
-	fileData, err := io.ReadAll(file)
+	reader, err := r.MultipartReader()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("读取文件失败: %v", err), http.StatusInternalServerError)
+		writeUploadError(w, &uploadError{status: http.StatusBadRequest, message: fmt.Sprintf("解析表单失败: %v", err)})
 		return
 	}
 
-	// 获取 category（可选）
-	category := r.FormValue("category")
-	if category == "" {
-		category = "image" // 默认分类
-	}
-
-	// 获取文件类型
-	contentType := header.Header.Get("Content-Type")
-	if contentType == "" {
-		// 根据文件扩展名推断
-		fileName := header.Filename
-		if strings.HasSuffix(strings.ToLower(fileName), ".jpg") || strings.HasSuffix(strings.ToLower(fileName), ".jpeg") {
-			contentType = "image/jpeg"
-		} else if strings.HasSuffix(strings.ToLower(fileName), ".png") {
-			contentType = "image/png"
-		} else if strings.HasSuffix(strings.ToLower(fileName), ".gif") {
-			contentType = "image/gif"
-		} else {
-			contentType = "application/octet-stream"
-		}
+	category := r.URL.Query().Get("category")
+	authorization := r.Header.Get("Authorization")
+	var fileInfos []*v1.FileInfo
+	// 整批要么全部成功，要么失败：中途出错时删除本批已存入的文件，避免留下客户端不知道的孤儿文件
+	fail := func(err error) {
+		h.discardFiles(r.Context(), fileInfos, authorization)
+		writeUploadError(w, err)
 	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fail(readError(err))
+			return
+		}
 
-	// 调用 gRPC 服务上传文件
-	ctx := context.Background()
-	req := &v1.UploadFileRequest{
-		FileData: fileData,
-		FileName: header.Filename,
-		FileType: contentType,
-		Category: category,
+		switch {
+		case part.FormName() == "category" && part.FileName() == "":
+			if category, err = readFormValue(part); err != nil {
+				fail(readError(err))
+				return
+			}
+		case part.FormName() == "files" && part.FileName() != "":
+			if h.conf.MaxFiles > 0 && len(fileInfos) >= h.conf.MaxFiles {
+				fail(&uploadError{status: http.StatusBadRequest, message: fmt.Sprintf("单次最多上传 %d 个文件", h.conf.MaxFiles)})
+				return
+			}
+			resp, err := h.streamFile(r.Context(), part, defaultCategory(category), authorization)
+			if err != nil {
+				fail(err)
+				return
+			}
+			fileInfos = append(fileInfos, resp.GetData())
+		}
+		part.Close()
 	}
 
-	resp, err := h.fileServiceClient.UploadFile(ctx, req)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("上传文件失败: %v", err), http.StatusInternalServerError)
+	if len(fileInfos) == 0 {
+		writeUploadError(w, &uploadError{status: http.StatusBadRequest, message: "没有上传文件"})
 		return
 	}
 
 	// 返回 JSON 响应
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(apiResp[*v1.FileInfo]{
-		Code:    resp.GetCode(),
-		Message: resp.GetMessage(),
-		Data:    resp.GetData(),
+	_ = json.NewEncoder(w).Encode(apiResp[[]*v1.FileInfo]{
+		Code:    0,
+		Message: "上传成功",
+		Data:    fileInfos,
 	})
 }
 
-// HandleBatchUpload 处理批量文件上传
-func (h *FileUploadHandler) HandleBatchUpload(w http.ResponseWriter, r *http.Request) {
+// preflight 设置 CORS 头并处理预检请求，返回 false 表示请求已处理完毕
+func (h *FileUploadHandler) preflight(w http.ResponseWriter, r *http.Request) bool {
 	// 设置 CORS 头
 	origin := r.Header.Get("Origin")
 	if origin != "" {
@@ -169,79 +233,175 @@ func (h *FileUploadHandler) HandleBatchUpload(w http.ResponseWriter, r *http.Req
 	// 处理 OPTIONS 预检请求
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusNoContent)
-		return
+		return false
 	}
 
 	// 只处理 POST 请求
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+		return false
 	}
+	return true
+}
 
-	// 限制请求体大小（200MB，批量上传需要更大空间）
-	r.Body = http.MaxBytesReader(w, r.Body, 200<<20) // 200MB
-
-	// 解析 multipart/form-data（增加限制）
-	err := r.ParseMultipartForm(200 << 20) // 200MB
-	if err != nil {
-		http.Error(w, fmt.Sprintf("解析表单失败: %v", err), http.StatusBadRequest)
-		return
+// streamFile 将一个文件分段流式转发给 file-service：
+//  1. 先读取前 512 字节嗅探内容类型，不在白名单内直接拒绝，此时还未发起 RPC
+//  2. 之后按 uploadChunkSize 边读边发，累计超过大小上限时取消 RPC，file-service 会删除未完成的文件
+//
+// gRPC 流控窗口写满时 Send 会阻塞，读取请求体随之暂停，客户端上传速度受 file-service 写盘速度约束（背压）。
+func (h *FileUploadHandler) streamFile(ctx context.Context, part *multipart.Part, category, authorization string) (*v1.UploadFileResponse, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(part, head)
+	switch {
+	case err == io.EOF:
+		return nil, &uploadError{status: http.StatusBadRequest, message: "文件内容不能为空"}
+	case err != nil && err != io.ErrUnexpectedEOF:
+		return nil, readError(err)
 	}
+	head = head[:n]
 
-	// 获取文件列表
-	files := r.MultipartForm.File["files"]
-	if len(files) == 0 {
-		http.Error(w, "没有上传文件", http.StatusBadRequest)
-		return
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !h.typeAllowed(detected) {
+		return nil, &uploadError{status: http.StatusUnsupportedMediaType, message: fmt.Sprintf("不支持的文件类型: %s", detected)}
 	}
-
-	// 获取 category（可选）
-	category := r.FormValue("category")
-	if category == "" {
-		category = "image" // 默认分类
+	fileType := part.Header.Get("Content-Type")
+	if fileType == "" || fileType == "application/octet-stream" {
+		fileType = detected
 	}
 
-	var fileDataList [][]byte
-	var fileNames []string
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := h.fileServiceClient.UploadFileStream(withAuthorization(ctx, authorization))
+	if err != nil {
+		return nil, &uploadError{status: http.StatusBadGateway, message: fmt.Sprintf("上传文件失败: %v", err)}
+	}
+	if err := stream.Send(&v1.UploadFileChunk{Meta: &v1.UploadFileMeta{
+		FileName: part.FileName(),
+		FileType: fileType,
+		Category: category,
+	}}); err != nil {
+		return nil, h.closeError(stream)
+	}
 
-	// 读取所有文件
-	for _, fileHeader := range files {
-		file, err := fileHeader.Open()
+	total := int64(len(head))
+	if err := stream.Send(&v1.UploadFileChunk{Data: head}); err != nil {
+		return nil, h.closeError(stream)
+	}
+	buf := make([]byte, uploadChunkSize)
+	for {
+		n, err := part.Read(buf)
+		if n > 0 {
+			total += int64(n)
+			if h.conf.MaxFileSize > 0 && total > h.conf.MaxFileSize {
+				return nil, &uploadError{status: http.StatusRequestEntityTooLarge, message: "文件大小超过限制"}
+			}
+			// Send 返回前已完成序列化，buf 可以复用
+			if sendErr := stream.Send(&v1.UploadFileChunk{Data: buf[:n]}); sendErr != nil {
+				return nil, h.closeError(stream)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			http.Error(w, fmt.Sprintf("打开文件失败: %v", err), http.StatusInternalServerError)
-			return
+			return nil, readError(err)
 		}
+	}
 
-		fileData, err := io.ReadAll(file)
-		file.Close()
-		if err != nil {
-			http.Error(w, fmt.Sprintf("读取文件失败: %v", err), http.StatusInternalServerError)
-			return
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, rpcUploadError(err)
+	}
+	return resp, nil
+}
+
+// discardFiles 删除批量上传中已存入的文件；客户端可能已断开，不沿用请求的取消信号，删除失败只记录日志
+func (h *FileUploadHandler) discardFiles(ctx context.Context, files []*v1.FileInfo, authorization string) {
+	ctx = withAuthorization(context.WithoutCancel(ctx), authorization)
+	for _, f := range files {
+		if _, err := h.fileServiceClient.DeleteFile(ctx, &v1.DeleteFileRequest{FileId: f.GetFileId()}); err != nil {
+			logx.Errorf("upload: 删除批量上传失败残留的文件 %s 失败: %v", f.GetFileId(), err)
 		}
+	}
+}
 
-		fileDataList = append(fileDataList, fileData)
-		fileNames = append(fileNames, fileHeader.Filename)
+// withAuthorization 把登录令牌转发给 file-service，由其记录上传者并签发绑定上传者的私有文件链接
+func withAuthorization(ctx context.Context, authorization string) context.Context {
+	if authorization == "" {
+		return ctx
 	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", authorization)
+}
 
-	// 调用 gRPC 服务批量上传文件
-	ctx := context.Background()
-	req := &v1.BatchUploadFileRequest{
-		FileData:  fileDataList,
-		FileNames: fileNames,
-		Category:  category,
+// closeError Send 失败（通常是服务端提前结束了流）时，通过 CloseAndRecv 取回真实的错误状态
+func (h *FileUploadHandler) closeError(stream grpc.ClientStreamingClient[v1.UploadFileChunk, v1.UploadFileResponse]) error {
+	_, err := stream.CloseAndRecv()
+	if err == nil {
+		err = status.Error(codes.Internal, "上传流被提前关闭")
 	}
+	return rpcUploadError(err)
+}
 
-	resp, err := h.fileServiceClient.BatchUploadFile(ctx, req)
+// typeAllowed 判断嗅探出的 MIME 类型是否在白名单中
+func (h *FileUploadHandler) typeAllowed(mimeType string) bool {
+	if len(h.conf.AllowedTypes) == 0 {
+		return true
+	}
+	for _, allowed := range h.conf.AllowedTypes {
+		if allowed == mimeType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// readFormValue 读取普通表单字段（最多 256 字节）
+func readFormValue(part *multipart.Part) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(part, 256))
 	if err != nil {
-		http.Error(w, fmt.Sprintf("批量上传文件失败: %v", err), http.StatusInternalServerError)
-		return
+		return "", err
 	}
+	return strings.TrimSpace(string(data)), nil
+}
 
-	// 返回 JSON 响应
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(apiResp[[]*v1.FileInfo]{
-		Code:    resp.GetCode(),
-		Message: resp.GetMessage(),
-		Data:    resp.GetData(),
-	})
+// defaultCategory 未指定分类时使用 image
+func defaultCategory(category string) string {
+	if category == "" {
+		return "image" // 默认分类
+	}
+	return category
+}
+
+// readError 读取请求体失败：超过 MaxBytesReader 限制返回 413，其余按请求格式错误处理
+func readError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return &uploadError{status: http.StatusRequestEntityTooLarge, message: "上传内容超过限制"}
+	}
+	return &uploadError{status: http.StatusBadRequest, message: fmt.Sprintf("读取文件失败: %v", err)}
+}
+
+// rpcUploadError 将 file-service 返回的 gRPC 错误转换为上传错误
+func rpcUploadError(err error) error {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return &uploadError{status: http.StatusBadRequest, message: st.Message()}
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return &uploadError{status: http.StatusBadGateway, message: "文件服务暂不可用，请稍后重试"}
+	default:
+		return &uploadError{status: http.StatusInternalServerError, message: fmt.Sprintf("上传文件失败: %s", st.Message())}
+	}
+}
+
+// writeUploadError 输出上传错误的 JSON 响应
+func writeUploadError(w http.ResponseWriter, err error) {
+	var upErr *uploadError
+	if !errors.As(err, &upErr) {
+		upErr = &uploadError{status: http.StatusInternalServerError, message: err.Error()}
+	}
+	writePageJSON(w, upErr.status, apiResp[any]{Code: int32(upErr.status), Message: upErr.message})
 }
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	v1 "ecommerce-system/api/file/v1"
+)
+
+// fakeFileServer 记录每次流式上传收到的元信息、字节数和结束原因
+type fakeFileServer struct {
+	v1.UnimplementedFileServiceServer
+
+	mu            sync.Mutex
+	calls         int
+	meta          *v1.UploadFileMeta
+	received      int
+	authorization string
+	deleted       []string
+	done          chan error // 每次上传结束时写入（正常结束为 nil）
+}
+
+func (s *fakeFileServer) UploadFileStream(stream grpc.ClientStreamingServer[v1.UploadFileChunk, v1.UploadFileResponse]) error {
+	s.mu.Lock()
+	s.calls++
+	s.received = 0
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok && len(md.Get("authorization")) > 0 {
+		s.authorization = md.Get("authorization")[0]
+	}
+	s.mu.Unlock()
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			s.done <- err
+			return err
+		}
+		s.mu.Lock()
+		if chunk.GetMeta() != nil {
+			s.meta = chunk.GetMeta()
+		}
+		s.received += len(chunk.GetData())
+		s.mu.Unlock()
+	}
+	s.done <- nil
+	return stream.SendAndClose(&v1.UploadFileResponse{
+		Message: "上传成功",
+		Data:    &v1.FileInfo{FileId: fmt.Sprintf("file-%d", s.calls), FileName: s.meta.GetFileName(), FileSize: int64(s.received)},
+	})
+}
+
+func (s *fakeFileServer) DeleteFile(ctx context.Context, req *v1.DeleteFileRequest) (*v1.DeleteFileResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, req.GetFileId())
+	return &v1.DeleteFileResponse{Message: "删除成功"}, nil
+}
+
+func newTestUploadHandler(t *testing.T, conf FileUploadConfig) (*FileUploadHandler, *fakeFileServer) {
+	t.Helper()
+	fake := &fakeFileServer{done: make(chan error, 4)}
+	srv := grpc.NewServer()
+	v1.RegisterFileServiceServer(srv, fake)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	h, err := NewFileUploadHandler(lis.Addr().String(), conf)
+	if err != nil {
+		t.Fatalf("NewFileUploadHandler: %v", err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+	return h, fake
+}
+
+// multipartUpload 构造只含一个 file 字段的上传请求
+func multipartUpload(t *testing.T, fileName string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write(content)
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/files/upload?category=image", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer test-token")
+	return req
+}
+
+// pngContent 以 PNG 文件头开头、总长为 size 的内容
+func pngContent(size int) []byte {
+	content := make([]byte, size)
+	copy(content, "\x89PNG\r\n\x1a\n")
+	return content
+}
+
+func waitUploadDone(t *testing.T, fake *fakeFileServer) error {
+	t.Helper()
+	select {
+	case err := <-fake.done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("file-service stream did not finish")
+		return nil
+	}
+}
+
+func TestUploadStreamsFileWithAuthorization(t *testing.T) {
+	h, fake := newTestUploadHandler(t, FileUploadConfig{MaxFileSize: 1 << 20, AllowedTypes: []string{"image/*"}})
+	content := pngContent(200 << 10) // 跨越多个转发分片
+
+	rec := httptest.NewRecorder()
+	h.HandleUpload(rec, multipartUpload(t, "a.png", content))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if err := waitUploadDone(t, fake); err != nil {
+		t.Fatalf("stream finished with %v", err)
+	}
+
+	var resp apiResp[*v1.FileInfo]
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Data.GetFileSize() != int64(len(content)) || fake.received != len(content) {
+		t.Fatalf("received %d bytes (reported %d), want %d", fake.received, resp.Data.GetFileSize(), len(content))
+	}
+	if fake.meta.GetFileType() != "image/png" || fake.meta.GetCategory() != "image" || fake.meta.GetFileName() != "a.png" {
+		t.Fatalf("unexpected meta: %+v", fake.meta)
+	}
+	if fake.authorization != "Bearer test-token" {
+		t.Fatalf("authorization forwarded as %q", fake.authorization)
+	}
+}
+
+func TestUploadRejectsDisallowedType(t *testing.T) {
+	h, fake := newTestUploadHandler(t, FileUploadConfig{MaxFileSize: 1 << 20, AllowedTypes: []string{"image/*"}})
+
+	rec := httptest.NewRecorder()
+	h.HandleUpload(rec, multipartUpload(t, "evil.png", []byte("<html><script>alert(1)</script></html>")))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("status = %d, want 415", rec.Code)
+	}
+	// 按内容嗅探，扩展名伪装无效；拒绝时还未发起 RPC
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.calls != 0 {
+		t.Fatalf("file-service called %d times for a rejected type", fake.calls)
+	}
+}
+
+func TestUploadCutsOffOversizedFile(t *testing.T) {
+	const maxSize = 100 << 10
+	h, fake := newTestUploadHandler(t, FileUploadConfig{MaxFileSize: maxSize, AllowedTypes: []string{"image/*"}})
+
+	// 不声明 Content-Length，只能在转发过程中发现超限
+	req := multipartUpload(t, "big.png", pngContent(maxSize+uploadChunkSize*2))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	h.HandleUpload(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413", rec.Code)
+	}
+
+	// 流被取消而不是正常结束，file-service 据此删除临时文件
+	if err := waitUploadDone(t, fake); err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("oversized upload stream finished with %v, want cancellation", err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.received > maxSize {
+		t.Fatalf("forwarded %d bytes, limit is %d", fake.received, maxSize)
+	}
+}
+
+func TestBatchUploadDeletesStoredFilesOnFailure(t *testing.T) {
+	h, fake := newTestUploadHandler(t, FileUploadConfig{MaxFileSize: 1 << 20, MaxBatchSize: 4 << 20, AllowedTypes: []string{"image/*"}})
+
+	// 前两个文件已存入 file-service，第三个文件类型不允许
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, f := range []struct {
+		name    string
+		content []byte
+	}{
+		{"a.png", pngContent(1024)},
+		{"b.png", pngContent(2048)},
+		{"evil.png", []byte("<html><script>alert(1)</script></html>")},
+	} {
+		fw, err := mw.CreateFormFile("files", f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write(f.content)
+	}
+	_ = mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/files/batch-upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer test-token")
+
+	rec := httptest.NewRecorder()
+	h.HandleBatchUpload(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("status = %d, want 415", rec.Code)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.calls != 2 {
+		t.Fatalf("file-service uploads = %d, want 2", fake.calls)
+	}
+	if len(fake.deleted) != 2 || fake.deleted[0] != "file-1" || fake.deleted[1] != "file-2" {
+		t.Fatalf("deleted %v, want both stored files", fake.deleted)
+	}
+	if fake.authorization != "Bearer test-token" {
+		t.Fatalf("authorization forwarded as %q", fake.authorization)
+	}
+}
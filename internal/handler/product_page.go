@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	inventoryv1 "ecommerce-system/api/inventory/v1"
+	productv1 "ecommerce-system/api/product/v1"
+	promotionv1 "ecommerce-system/api/promotion/v1"
+	recommendv1 "ecommerce-system/api/recommend/v1"
+	reviewv1 "ecommerce-system/api/review/v1"
+	seckillv1 "ecommerce-system/api/seckill/v1"
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/client"
+)
+
+// ProductPagePath 商品详情页聚合接口路径前缀（/api/v1/pages/product/{id}）
+const ProductPagePath = "/api/v1/pages/product/"
+
+// 可降级的依赖名称，出现在响应 degraded 字段中
+const (
+	depInventory  = "inventory"
+	depReviews    = "reviews"
+	depPromotions = "promotions"
+	depSimilar    = "similar"
+	depSeckill    = "seckill"
+)
+
+// ProductPageConfig 商品详情页聚合配置，每个下游的 Timeout 即该依赖的超时时间
+type ProductPageConfig struct {
+	Product   client.RpcConf
+	Inventory client.RpcConf
+	Review    client.RpcConf
+	Promotion client.RpcConf
+	Recommend client.RpcConf
+	Seckill   client.RpcConf
+	CacheTTL  time.Duration // 0 表示不缓存
+}
+
+// ProductPage 商品详情页聚合结果
+type ProductPage struct {
+	Product     *productv1.Product              `json:"product"`
+	Skus        []*productv1.Sku                `json:"skus"`
+	Inventory   []*inventoryv1.Inventory        `json:"inventory"`
+	ReviewStats *reviewv1.ReviewStats           `json:"review_stats"`
+	Reviews     []*reviewv1.Review              `json:"reviews"`
+	ReviewTotal int32                           `json:"review_total"`
+	Promotions  []*promotionv1.Promotion        `json:"promotions"`
+	Similar     []*recommendv1.RecommendProduct `json:"similar"`
+	Seckill     []*seckillv1.SeckillActivity    `json:"seckill"`            // 商品 SKU 正在进行的秒杀活动
+	Degraded    []string                        `json:"degraded,omitempty"` // 本次未能获取的依赖
+}
+
+// ProductPageHandler 商品详情页 BFF：并发调用各下游服务聚合成一个响应，
+// 商品信息是必需的，其余依赖失败或超时时省略对应字段并记录在 degraded 中。
+type ProductPageHandler struct {
+	product   *client.ProductClient
+	inventory *client.InventoryClient
+	review    *client.ReviewClient
+	promotion *client.PromotionClient
+	recommend *client.RecommendClient
+	seckill   *client.SeckillClient
+	cache     *cache.CacheOperations // 为空时不缓存
+	cacheTTL  time.Duration
+}
+
+// NewProductPageHandler 创建商品详情页聚合处理器
+func NewProductPageHandler(conf ProductPageConfig, rdb *redis.Client) (*ProductPageHandler, error) {
+	h := &ProductPageHandler{cacheTTL: conf.CacheTTL}
+	if rdb != nil && conf.CacheTTL > 0 {
+		h.cache = cache.NewCacheOperations(rdb)
+	}
+
+	var err error
+	if h.product, err = client.NewProductClient(conf.Product); err != nil {
+		return nil, err
+	}
+	if h.inventory, err = client.NewInventoryClient(conf.Inventory); err != nil {
+		return nil, err
+	}
+	if h.review, err = client.NewReviewClient(conf.Review); err != nil {
+		return nil, err
+	}
+	if h.promotion, err = client.NewPromotionClient(conf.Promotion); err != nil {
+		return nil, err
+	}
+	if h.recommend, err = client.NewRecommendClient(conf.Recommend); err != nil {
+		return nil, err
+	}
+	if h.seckill, err = client.NewSeckillClient(conf.Seckill); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Close 关闭连接
+func (h *ProductPageHandler) Close() error {
+	for _, c := range []interface{ Close() error }{h.product, h.inventory, h.review, h.promotion, h.recommend, h.seckill} {
+		_ = c.Close()
+	}
+	return nil
+}
+
+// HandleProductPage 处理 GET /api/v1/pages/product/{id}
+func (h *ProductPageHandler) HandleProductPage(w http.ResponseWriter, r *http.Request) {
+	// 设置 CORS 头
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin")
+	w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, X-Cache")
+	w.Header().Set("Access-Control-Max-Age", "3600")
+
+	// 处理 OPTIONS 预检请求
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	productID, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(r.URL.Path, ProductPagePath), "/"), 10, 64)
+	if err != nil || productID <= 0 {
+		writePageJSON(w, http.StatusBadRequest, apiResp[any]{Code: http.StatusBadRequest, Message: "商品ID无效"})
+		return
+	}
+
+	ctx := r.Context()
+	if page := h.getCached(ctx, productID); page != nil {
+		w.Header().Set("X-Cache", "HIT")
+		writePageJSON(w, http.StatusOK, apiResp[*ProductPage]{Code: 0, Message: "success", Data: page})
+		return
+	}
+
+	page, err := h.load(ctx, productID)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			writePageJSON(w, http.StatusServiceUnavailable, apiResp[any]{Code: http.StatusServiceUnavailable, Message: "商品服务暂不可用，请稍后重试"})
+		default:
+			writePageJSON(w, http.StatusNotFound, apiResp[any]{Code: http.StatusNotFound, Message: "商品不存在"})
+		}
+		return
+	}
+
+	// 降级结果不缓存，依赖恢复后下一次请求即可拿到完整数据
+	if len(page.Degraded) == 0 {
+		h.setCached(ctx, productID, page)
+	}
+	w.Header().Set("X-Cache", "MISS")
+	writePageJSON(w, http.StatusOK, apiResp[*ProductPage]{Code: 0, Message: "success", Data: page})
+}
+
+// Invalidate 清除商品详情页缓存（商品变更事件到达时调用）
+func (h *ProductPageHandler) Invalidate(ctx context.Context, productID int64) {
+	if h.cache == nil {
+		return
+	}
+	if err := h.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixPageProduct, productID)); err != nil {
+		logx.Errorf("product page cache invalidate failed, product=%d: %v", productID, err)
+	}
+}
+
+// load 两阶段聚合：先取商品和 SKU（后续依赖需要 SKU ID、类目 ID），再并发取其余依赖
+func (h *ProductPageHandler) load(ctx context.Context, productID int64) (*ProductPage, error) {
+	page := &ProductPage{}
+
+	var (
+		wg         sync.WaitGroup
+		productErr error
+		skuErr     error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		page.Product, productErr = h.product.GetProduct(ctx, productID)
+	}()
+	go func() {
+		defer wg.Done()
+		page.Skus, skuErr = h.product.ListSkus(ctx, productID)
+	}()
+	wg.Wait()
+	if productErr != nil {
+		return nil, productErr
+	}
+	if skuErr != nil {
+		logx.Errorf("product page: list skus failed, product=%d: %v", productID, skuErr)
+	}
+
+	skuIDs := make([]int64, 0, len(page.Skus))
+	for _, sku := range page.Skus {
+		skuIDs = append(skuIDs, sku.Id)
+	}
+
+	var mu sync.Mutex
+	degrade := func(dep string, err error) {
+		logx.Errorf("product page: %s degraded, product=%d: %v", dep, productID, err)
+		mu.Lock()
+		if !slices.Contains(page.Degraded, dep) {
+			page.Degraded = append(page.Degraded, dep)
+		}
+		mu.Unlock()
+	}
+
+	tasks := []func(){
+		func() {
+			if len(skuIDs) == 0 {
+				return
+			}
+			inv, err := h.inventory.BatchGetInventory(ctx, skuIDs)
+			if err != nil {
+				degrade(depInventory, err)
+				return
+			}
+			page.Inventory = inv
+		},
+		func() {
+			stats, err := h.review.GetReviewStats(ctx, productID)
+			if err != nil {
+				degrade(depReviews, err)
+				return
+			}
+			page.ReviewStats = stats
+		},
+		func() {
+			reviews, total, err := h.review.GetProductReviews(ctx, productID, 1, 10)
+			if err != nil {
+				degrade(depReviews, err)
+				return
+			}
+			page.Reviews, page.ReviewTotal = reviews, total
+		},
+		func() {
+			promotions, err := h.promotion.GetPromotionList(ctx, productID, page.Product.CategoryId)
+			if err != nil {
+				degrade(depPromotions, err)
+				return
+			}
+			page.Promotions = promotions
+		},
+		func() {
+			similar, err := h.recommend.GetSimilarProducts(ctx, productID, 8)
+			if err != nil {
+				degrade(depSimilar, err)
+				return
+			}
+			page.Similar = similar
+		},
+		func() {
+			if len(skuIDs) == 0 {
+				return
+			}
+			activities, err := h.seckill.ListActiveActivities(ctx, 100)
+			if err != nil {
+				degrade(depSeckill, err)
+				return
+			}
+			for _, a := range activities {
+				if slices.Contains(skuIDs, a.SkuId) {
+					page.Seckill = append(page.Seckill, a)
+				}
+			}
+		},
+	}
+
+	// 每个任务只写自己的字段，degraded 由 mu 保护
+	wg.Add(len(tasks))
+	for _, task := range tasks {
+		go func() {
+			defer wg.Done()
+			task()
+		}()
+	}
+	wg.Wait()
+	return page, nil
+}
+
+func (h *ProductPageHandler) getCached(ctx context.Context, productID int64) *ProductPage {
+	if h.cache == nil {
+		return nil
+	}
+	var page ProductPage
+	if err := h.cache.GetJSON(ctx, cache.BuildKey(cache.KeyPrefixPageProduct, productID), &page); err != nil {
+		if !cache.IsNil(err) {
+			logx.Errorf("product page cache get failed, product=%d: %v", productID, err)
+		}
+		return nil
+	}
+	return &page
+}
+
+func (h *ProductPageHandler) setCached(ctx context.Context, productID int64, page *ProductPage) {
+	if h.cache == nil {
+		return
+	}
+	if err := h.cache.Set(ctx, cache.BuildKey(cache.KeyPrefixPageProduct, productID), page, h.cacheTTL); err != nil {
+		logx.Errorf("product page cache set failed, product=%d: %v", productID, err)
+	}
+}
+
+func writePageJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
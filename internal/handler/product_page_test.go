@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+
+	inventoryv1 "ecommerce-system/api/inventory/v1"
+	productv1 "ecommerce-system/api/product/v1"
+	promotionv1 "ecommerce-system/api/promotion/v1"
+	recommendv1 "ecommerce-system/api/recommend/v1"
+	reviewv1 "ecommerce-system/api/review/v1"
+	seckillv1 "ecommerce-system/api/seckill/v1"
+	"ecommerce-system/internal/pkg/client"
+)
+
+// fakeBackend 在一个 gRPC 端口上模拟商品详情页依赖的全部下游服务，
+// healthy 为 false 时营销返回业务错误、评价列表超时，推荐和秒杀未实现。
+type fakeBackend struct {
+	healthy atomic.Bool
+
+	productv1.UnimplementedProductServiceServer
+	inventoryv1.UnimplementedInventoryServiceServer
+	reviewv1.UnimplementedReviewServiceServer
+	promotionv1.UnimplementedPromotionServiceServer
+}
+
+// fakeRecommend、fakeSeckill 不健康时返回 Unimplemented
+
+type fakeRecommend struct {
+	*fakeBackend
+	recommendv1.UnimplementedRecommendServiceServer
+}
+
+func (f fakeRecommend) GetSimilarProducts(ctx context.Context, req *recommendv1.GetSimilarProductsRequest) (*recommendv1.GetSimilarProductsResponse, error) {
+	if !f.healthy.Load() {
+		return f.UnimplementedRecommendServiceServer.GetSimilarProducts(ctx, req)
+	}
+	return &recommendv1.GetSimilarProductsResponse{Data: []*recommendv1.RecommendProduct{{ProductId: 2}}}, nil
+}
+
+type fakeSeckill struct {
+	*fakeBackend
+	seckillv1.UnimplementedSeckillServiceServer
+}
+
+func (f fakeSeckill) ListSeckillActivities(ctx context.Context, req *seckillv1.ListSeckillActivitiesRequest) (*seckillv1.ListSeckillActivitiesResponse, error) {
+	if !f.healthy.Load() {
+		return f.UnimplementedSeckillServiceServer.ListSeckillActivities(ctx, req)
+	}
+	return &seckillv1.ListSeckillActivitiesResponse{Data: &seckillv1.SeckillActivityListData{List: []*seckillv1.SeckillActivity{
+		{Id: 1, SkuId: 11},
+		{Id: 2, SkuId: 99}, // 其他商品的秒杀
+	}}}, nil
+}
+
+func (f *fakeBackend) GetProduct(ctx context.Context, req *productv1.GetProductRequest) (*productv1.GetProductResponse, error) {
+	if req.Id != 1 {
+		return &productv1.GetProductResponse{Code: 404, Message: "商品不存在"}, nil
+	}
+	return &productv1.GetProductResponse{Data: &productv1.Product{Id: 1, Name: "手机", CategoryId: 3}}, nil
+}
+
+func (f *fakeBackend) ListSkus(ctx context.Context, req *productv1.ListSkusRequest) (*productv1.ListSkusResponse, error) {
+	return &productv1.ListSkusResponse{Data: &productv1.SkuListData{List: []*productv1.Sku{{Id: 11, ProductId: 1}, {Id: 12, ProductId: 1}}}}, nil
+}
+
+func (f *fakeBackend) BatchGetInventory(ctx context.Context, req *inventoryv1.BatchGetInventoryRequest) (*inventoryv1.BatchGetInventoryResponse, error) {
+	data := make([]*inventoryv1.Inventory, 0, len(req.SkuIds))
+	for _, id := range req.SkuIds {
+		data = append(data, &inventoryv1.Inventory{SkuId: id, AvailableStock: 5})
+	}
+	return &inventoryv1.BatchGetInventoryResponse{Data: data}, nil
+}
+
+func (f *fakeBackend) GetReviewStats(ctx context.Context, req *reviewv1.GetReviewStatsRequest) (*reviewv1.GetReviewStatsResponse, error) {
+	return &reviewv1.GetReviewStatsResponse{Data: &reviewv1.ReviewStats{TotalCount: 3}}, nil
+}
+
+func (f *fakeBackend) GetProductReviews(ctx context.Context, req *reviewv1.GetProductReviewsRequest) (*reviewv1.GetProductReviewsResponse, error) {
+	if !f.healthy.Load() {
+		<-ctx.Done() // 模拟超时
+		return nil, ctx.Err()
+	}
+	return &reviewv1.GetProductReviewsResponse{Data: []*reviewv1.Review{{Id: 1}}, Total: 1}, nil
+}
+
+func (f *fakeBackend) GetPromotionList(ctx context.Context, req *promotionv1.GetPromotionListRequest) (*promotionv1.GetPromotionListResponse, error) {
+	if !f.healthy.Load() {
+		return &promotionv1.GetPromotionListResponse{Code: 500, Message: "营销服务异常"}, nil
+	}
+	return &promotionv1.GetPromotionListResponse{Data: []*promotionv1.Promotion{{Id: 1}}}, nil
+}
+
+func newTestProductPage(t *testing.T) (*ProductPageHandler, *fakeBackend) {
+	t.Helper()
+	backend := &fakeBackend{}
+	srv := grpc.NewServer()
+	productv1.RegisterProductServiceServer(srv, backend)
+	inventoryv1.RegisterInventoryServiceServer(srv, backend)
+	reviewv1.RegisterReviewServiceServer(srv, backend)
+	promotionv1.RegisterPromotionServiceServer(srv, backend)
+	recommendv1.RegisterRecommendServiceServer(srv, fakeRecommend{fakeBackend: backend})
+	seckillv1.RegisterSeckillServiceServer(srv, fakeSeckill{fakeBackend: backend})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	conf := client.RpcConf{Endpoint: lis.Addr().String(), Timeout: time.Second}
+	h, err := NewProductPageHandler(ProductPageConfig{
+		Product:   conf,
+		Inventory: conf,
+		Review:    client.RpcConf{Endpoint: conf.Endpoint, Timeout: 100 * time.Millisecond},
+		Promotion: conf,
+		Recommend: conf,
+		Seckill:   conf,
+		CacheTTL:  time.Minute,
+	}, rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+	return h, backend
+}
+
+func requestPage(t *testing.T, h *ProductPageHandler, id string) (*httptest.ResponseRecorder, *ProductPage) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.HandleProductPage(rec, httptest.NewRequest(http.MethodGet, ProductPagePath+id, nil))
+	var body apiResp[*ProductPage]
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v, body=%s", err, rec.Body.String())
+	}
+	return rec, body.Data
+}
+
+func TestProductPagePartialFailure(t *testing.T) {
+	h, backend := newTestProductPage(t)
+
+	rec, page := requestPage(t, h, "1")
+	if rec.Code != http.StatusOK || page == nil || page.Product.GetId() != 1 {
+		t.Fatalf("degraded page should still return the product, code=%d body=%s", rec.Code, rec.Body.String())
+	}
+	// 成功的依赖照常返回
+	if len(page.Skus) != 2 || len(page.Inventory) != 2 || page.ReviewStats.GetTotalCount() != 3 {
+		t.Fatalf("healthy dependencies missing: skus=%d inventory=%d stats=%v", len(page.Skus), len(page.Inventory), page.ReviewStats)
+	}
+	// 失败、超时、未实现的依赖都记录在 degraded 中，且对应字段为空
+	for _, dep := range []string{depReviews, depPromotions, depSimilar, depSeckill} {
+		if !slices.Contains(page.Degraded, dep) {
+			t.Errorf("expected %s in degraded, got %v", dep, page.Degraded)
+		}
+	}
+	if slices.Contains(page.Degraded, depInventory) || len(page.Degraded) != 4 {
+		t.Errorf("unexpected degraded list %v", page.Degraded)
+	}
+	if page.Reviews != nil || page.Promotions != nil || page.Similar != nil || page.Seckill != nil {
+		t.Errorf("degraded fields should be empty: %+v", page)
+	}
+
+	// 降级结果不缓存，依赖恢复后下一次请求拿到完整数据并写入缓存
+	backend.healthy.Store(true)
+	rec, page = requestPage(t, h, "1")
+	if rec.Header().Get("X-Cache") != "MISS" || len(page.Degraded) != 0 {
+		t.Fatalf("recovered request: x-cache=%q degraded=%v", rec.Header().Get("X-Cache"), page.Degraded)
+	}
+	if page.ReviewTotal != 1 || len(page.Promotions) != 1 || len(page.Similar) != 1 {
+		t.Fatalf("recovered dependencies missing: %+v", page)
+	}
+	if len(page.Seckill) != 1 || page.Seckill[0].SkuId != 11 {
+		t.Fatalf("seckill should only include this product's SKUs, got %v", page.Seckill)
+	}
+	rec, _ = requestPage(t, h, "1")
+	if rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("complete page should be cached, x-cache=%q", rec.Header().Get("X-Cache"))
+	}
+}
+
+func TestProductPageRequiresProduct(t *testing.T) {
+	h, _ := newTestProductPage(t)
+
+	if rec, _ := requestPage(t, h, "2"); rec.Code != http.StatusNotFound {
+		t.Fatalf("missing product should return 404, got %d", rec.Code)
+	}
+	if rec, _ := requestPage(t, h, "abc"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid id should return 400, got %d", rec.Code)
+	}
+
+	// 商品服务不可用时整体失败，不返回降级页面
+	down, err := NewProductPageHandler(ProductPageConfig{Product: client.RpcConf{Endpoint: "127.0.0.1:1", Timeout: 200 * time.Millisecond}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer down.Close()
+	if rec, _ := requestPage(t, down, "1"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unavailable product service should return 503, got %d", rec.Code)
+	}
+}
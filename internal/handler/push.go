@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/push"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+// PushEventsPath SSE 推送接口路径
+const PushEventsPath = "/api/v1/push/events"
+
+// PushHandler 服务端推送（SSE）处理器：校验 JWT 后保持长连接，实时下发该用户的事件
+type PushHandler struct {
+	hub       *push.Hub
+	broker    *push.Broker
+	jwtSecret string
+	denylist  *revocation.Denylist // 为 nil 时不检查令牌吊销
+	heartbeat time.Duration
+}
+
+// NewPushHandler 创建推送处理器；连接建立时和每次心跳都会检查令牌是否已被吊销，令牌到期时关闭连接
+func NewPushHandler(hub *push.Hub, broker *push.Broker, jwtSecret string, denylist *revocation.Denylist, heartbeat time.Duration) *PushHandler {
+	if heartbeat <= 0 {
+		heartbeat = 25 * time.Second
+	}
+	return &PushHandler{hub: hub, broker: broker, jwtSecret: jwtSecret, denylist: denylist, heartbeat: heartbeat}
+}
+
+// HandleEvents 处理 GET /api/v1/push/events
+// 浏览器 EventSource 无法设置请求头，token 也可以通过 ?access_token= 传入；
+// 断线重连时浏览器会自动带上 Last-Event-ID，也可以通过 ?last_event_id= 指定。
+func (h *PushHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	// 设置 CORS 头
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Last-Event-ID, Accept, Origin")
+	w.Header().Set("Access-Control-Max-Age", "3600")
+
+	// 处理 OPTIONS 预检请求
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := parseRequestToken(r, h.jwtSecret, h.denylist)
+	if err != nil {
+		writePageJSON(w, http.StatusUnauthorized, apiResp[any]{Code: http.StatusUnauthorized, Message: "未登录或登录已过期"})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	if lastID != "" && !push.ValidEventID(lastID) {
+		writePageJSON(w, http.StatusBadRequest, apiResp[any]{Code: http.StatusBadRequest, Message: "Last-Event-ID 格式无效"})
+		return
+	}
+
+	// 先注册再回放，避免回放期间产生的事件丢失；重叠部分由 MarkSent 去重
+	conn := h.hub.Register(claims.UserID, 64)
+	defer h.hub.Unregister(conn)
+
+	ctx := r.Context()
+	replay, err := h.broker.Replay(ctx, claims.UserID, lastID)
+	if err != nil {
+		logx.Errorf("push: replay for user %d failed: %v", claims.UserID, err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "retry: 3000\n\n")
+
+	for _, evt := range replay {
+		if conn.MarkSent(evt.ID) {
+			writeSSE(w, evt)
+		}
+	}
+	flusher.Flush()
+
+	// 长连接不能比令牌活得更久：到期后通知客户端刷新令牌再重连
+	var expired <-chan time.Time
+	if claims.ExpiresAt != nil {
+		expiry := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired:
+			_, _ = fmt.Fprint(w, "event: expired\ndata: {}\n\n")
+			flusher.Flush()
+			return
+		case evt := <-conn.Events:
+			if !conn.MarkSent(evt.ID) {
+				continue
+			}
+			writeSSE(w, evt)
+			flusher.Flush()
+		case <-ticker.C:
+			// 登出、改密、下线所有设备后关闭已建立的连接，客户端需要用新令牌重连
+			if tokenRevoked(ctx, h.denylist, claims) {
+				_, _ = fmt.Fprint(w, "event: revoked\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+			// 心跳注释行，防止代理因空闲断开连接
+			_, _ = fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// parseRequestToken 从 Authorization 头或 access_token 查询参数中解析并校验 JWT
+// （EventSource、<img> 等浏览器原生请求无法自定义请求头）；denylist 不为 nil 时已吊销的令牌视为无效
+func parseRequestToken(r *http.Request, jwtSecret string, denylist *revocation.Denylist) (*utils.JWTClaims, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("access_token")
+	}
+	if token == "" {
+		return nil, utils.ErrTokenInvalid
+	}
+	claims, err := utils.ParseToken(token, jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+	if claims.UserID == 0 || tokenRevoked(r.Context(), denylist, claims) {
+		return nil, utils.ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// tokenRevoked 查询令牌是否已被吊销；查询失败时放行（只记录日志），与各服务的鉴权拦截器一致
+func tokenRevoked(ctx context.Context, denylist *revocation.Denylist, claims *utils.JWTClaims) bool {
+	if denylist == nil {
+		return false
+	}
+	revoked, err := denylist.IsRevoked(ctx, claims)
+	if err != nil {
+		logx.Errorf("push: 查询令牌黑名单失败: %v", err)
+		return false
+	}
+	return revoked
+}
+
+// writeSSE 按 SSE 格式写出事件：id / event / data
+func writeSSE(w http.ResponseWriter, evt *push.Event) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", evt.ID, evt.Type, data)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/push"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+const pushTestSecret = "push-test-secret"
+
+func newTestPushHandler(t *testing.T) (*PushHandler, *revocation.Denylist) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	hub := push.NewHub()
+	broker := push.NewBroker(rdb, hub, push.Config{})
+	denylist := revocation.NewDenylist(rdb)
+	return NewPushHandler(hub, broker, pushTestSecret, denylist, 20*time.Millisecond), denylist
+}
+
+func pushToken(t *testing.T, uid uint64) (string, *utils.JWTClaims) {
+	t.Helper()
+	return pushTokenExpiring(t, uid, 3600)
+}
+
+func pushTokenExpiring(t *testing.T, uid uint64, expire int64) (string, *utils.JWTClaims) {
+	t.Helper()
+	token, err := utils.GenerateToken(uid, "alice", pushTestSecret, expire)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	claims, err := utils.ParseToken(token, pushTestSecret)
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	return token, claims
+}
+
+func TestPushRejectsRevokedToken(t *testing.T) {
+	h, denylist := newTestPushHandler(t)
+	token, claims := pushToken(t, 7)
+	if err := denylist.RevokeToken(context.Background(), claims.ID, claims.ExpiresAt.Time); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, PushEventsPath+"?access_token="+token, nil),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, PushEventsPath, nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+			return r
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		h.HandleEvents(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("revoked token status = %d, want 401", rec.Code)
+		}
+	}
+}
+
+func TestPushClosesStreamAfterRevocation(t *testing.T) {
+	h, denylist := newTestPushHandler(t)
+	srv := httptest.NewServer(http.HandlerFunc(h.HandleEvents))
+	defer srv.Close()
+
+	token, claims := pushToken(t, 7)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+PushEventsPath+"?access_token="+token, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	// 连接建立后下线所有设备：签发时间早于吊销时间的令牌全部失效
+	if err := denylist.RevokeUser(context.Background(), claims.UserID, time.Now().Add(time.Second), time.Hour); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if ctx.Err() != nil {
+		t.Fatal("stream was not closed after revocation")
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), "event: revoked") {
+		t.Fatalf("stream did not announce revocation: %q", lines)
+	}
+}
+
+func TestPushClosesStreamWhenTokenExpires(t *testing.T) {
+	h, _ := newTestPushHandler(t)
+	srv := httptest.NewServer(http.HandlerFunc(h.HandleEvents))
+	defer srv.Close()
+
+	token, _ := pushTokenExpiring(t, 7, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+PushEventsPath+"?access_token="+token, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	// 令牌未被吊销，但到期后服务端主动关闭连接
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if ctx.Err() != nil {
+		t.Fatal("stream outlived the token")
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), "event: expired") {
+		t.Fatalf("stream did not announce expiry: %q", lines)
+	}
+}
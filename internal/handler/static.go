@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/signedurl"
+)
+
+// StaticHandler 静态文件处理器（/uploads/、/images/）：
+//   - 私有分类（signing.PrivateCategories）必须携带文件服务签发的签名，绑定用户的链接还要校验 JWT
+//   - 公开文件返回 public Cache-Control，便于浏览器和 CDN 缓存
+//   - 不提供目录列表和点开头的文件（文件元信息、上传中的临时文件），避免按路径枚举文件
+//   - 通过 http.ServeContent 支持 Range / If-Range / If-None-Match / If-Modified-Since
+type StaticHandler struct {
+	prefix       string
+	root         http.Dir
+	signing      signedurl.Config
+	signer       *signedurl.Signer
+	publicMaxAge int64
+	jwtSecret    string
+	denylist     *revocation.Denylist // 为 nil 时不检查令牌吊销
+}
+
+// NewStaticHandler 创建静态文件处理器，prefix 形如 "/uploads/"；signing 未配置密钥时所有文件按公开处理
+func NewStaticHandler(prefix, root string, signing signedurl.Config, publicMaxAge int64, jwtSecret string, denylist *revocation.Denylist) *StaticHandler {
+	h := &StaticHandler{
+		prefix:       prefix,
+		root:         http.Dir(root),
+		signing:      signing,
+		publicMaxAge: publicMaxAge,
+		jwtSecret:    jwtSecret,
+		denylist:     denylist,
+	}
+	if signing.Enabled() {
+		h.signer = signedurl.NewSigner(signing.Secret)
+	}
+	return h
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *StaticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 设置 CORS 头
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Add("Vary", "Origin")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Range, If-Range, If-None-Match, If-Modified-Since, Accept, Origin")
+	w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Range, Accept-Ranges, ETag, Last-Modified")
+	w.Header().Set("Access-Control-Max-Age", "3600")
+
+	// 处理 OPTIONS 预检请求
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), strings.TrimSuffix(h.prefix, "/"))
+	if name == "" || name == "/" || strings.Contains(name, "/.") {
+		http.NotFound(w, r)
+		return
+	}
+
+	cacheControl := fmt.Sprintf("public, max-age=%d", h.publicMaxAge)
+	if category, _, _ := strings.Cut(strings.TrimPrefix(name, "/"), "/"); h.signer != nil && h.signing.IsPrivate(category) {
+		claims, status, msg := h.authorize(r)
+		if claims == nil {
+			writePageJSON(w, status, apiResp[any]{Code: int32(status), Message: msg})
+			return
+		}
+		// 私有文件只允许客户端缓存到签名过期为止，且不允许共享缓存（CDN / 代理）保存
+		maxAge := int64(time.Until(claims.ExpiresAt).Seconds())
+		if maxAge < 0 {
+			maxAge = 0
+		}
+		cacheControl = fmt.Sprintf("private, max-age=%d", maxAge)
+	}
+
+	f, err := h.root.Open(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, stat.ModTime().UnixNano(), stat.Size()))
+	http.ServeContent(w, r, stat.Name(), stat.ModTime(), f)
+}
+
+// authorize 校验签名（以及绑定用户的 JWT），失败时返回 HTTP 状态码和错误信息
+func (h *StaticHandler) authorize(r *http.Request) (*signedurl.Claims, int, string) {
+	claims, err := h.signer.Verify(r.URL.Path, r.URL.Query(), time.Now())
+	switch {
+	case err == nil:
+	case errors.Is(err, signedurl.ErrExpired):
+		return nil, http.StatusForbidden, "链接已过期"
+	default:
+		return nil, http.StatusForbidden, "无权访问该文件"
+	}
+	if claims.UserID == 0 {
+		return claims, 0, ""
+	}
+
+	user, err := parseRequestToken(r, h.jwtSecret, h.denylist)
+	if err != nil {
+		return nil, http.StatusUnauthorized, "未登录或登录已过期"
+	}
+	if user.UserID != claims.UserID {
+		return nil, http.StatusForbidden, "无权访问该文件"
+	}
+	return claims, 0, ""
+}
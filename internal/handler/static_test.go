@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/signedurl"
+)
+
+const staticTestSigningSecret = "static-test-signing-secret"
+
+func newTestStaticHandler(t *testing.T) (*StaticHandler, *revocation.Denylist) {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"invoice/abc.pdf":       "%PDF-1.4 private",
+		"invoice/.abc.meta":     `{"owner_id":42}`,
+		"avatar/me.png":         "png",
+		"avatar/.upload-123456": "partial",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	denylist := revocation.NewDenylist(rdb)
+
+	signing := signedurl.Config{Secret: staticTestSigningSecret, PrivateCategories: []string{"invoice"}}
+	return NewStaticHandler("/uploads/", root, signing, 60, pushTestSecret, denylist), denylist
+}
+
+func serveStatic(h http.Handler, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStaticPrivateFileSignature(t *testing.T) {
+	h, _ := newTestStaticHandler(t)
+	signer := signedurl.NewSigner(staticTestSigningSecret)
+	unbound := signer.Sign("/uploads/invoice/abc.pdf", time.Now().Add(time.Minute), 0)
+
+	if rec := serveStatic(h, unbound, ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "private") {
+		t.Fatalf("valid signature: status %d body %q", rec.Code, rec.Body.String())
+	}
+	if rec := serveStatic(h, "/uploads/invoice/abc.pdf", ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("missing signature: status %d, want 403", rec.Code)
+	}
+
+	tampered := map[string]string{
+		"uid":     strings.Replace(signer.Sign("/uploads/invoice/abc.pdf", time.Now().Add(time.Minute), 42), "uid=42", "uid=7", 1),
+		"expires": strings.Replace(unbound, "expires=", "expires=9", 1),
+		"sig":     unbound[:len(unbound)-2] + "AA",
+		"path":    strings.Replace(unbound, "abc.pdf", ".abc.meta", 1),
+	}
+	for field, target := range tampered {
+		if rec := serveStatic(h, target, ""); rec.Code != http.StatusForbidden && rec.Code != http.StatusNotFound {
+			t.Fatalf("tampered %s: status %d, want 403/404", field, rec.Code)
+		}
+	}
+
+	expired := signer.Sign("/uploads/invoice/abc.pdf", time.Now().Add(-time.Second), 0)
+	rec := serveStatic(h, expired, "")
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "链接已过期") {
+		t.Fatalf("expired signature: status %d body %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestStaticBoundLinkRequiresOwnerToken(t *testing.T) {
+	h, denylist := newTestStaticHandler(t)
+	bound := signedurl.NewSigner(staticTestSigningSecret).Sign("/uploads/invoice/abc.pdf", time.Now().Add(time.Minute), 42)
+
+	if rec := serveStatic(h, bound, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no token: status %d, want 401", rec.Code)
+	}
+	other, _ := pushToken(t, 7)
+	if rec := serveStatic(h, bound, other); rec.Code != http.StatusForbidden {
+		t.Fatalf("non-owner token: status %d, want 403", rec.Code)
+	}
+	owner, claims := pushToken(t, 42)
+	if rec := serveStatic(h, bound, owner); rec.Code != http.StatusOK {
+		t.Fatalf("owner token: status %d, want 200", rec.Code)
+	}
+
+	if err := denylist.RevokeToken(context.Background(), claims.ID, claims.ExpiresAt.Time); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if rec := serveStatic(h, bound, owner); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked owner token: status %d, want 401", rec.Code)
+	}
+}
+
+func TestStaticHidesDotFiles(t *testing.T) {
+	h, _ := newTestStaticHandler(t)
+	if rec := serveStatic(h, "/uploads/avatar/me.png", ""); rec.Code != http.StatusOK {
+		t.Fatalf("public file: status %d, want 200", rec.Code)
+	}
+	for _, target := range []string{"/uploads/avatar/.upload-123456", "/uploads/invoice/.abc.meta"} {
+		if rec := serveStatic(h, target, ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: status %d, want 404", target, rec.Code)
+		}
+	}
+}
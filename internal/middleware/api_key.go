@@ -0,0 +1,390 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	userv1 "ecommerce-system/api/user/v1"
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+// maxSignedBodyBytes 参与签名的请求体上限
+const maxSignedBodyBytes = 1 << 20
+
+// apiKeyTokenMinRemaining 内部 JWT 剩余有效期不足时换新，避免令牌在转发途中过期
+const apiKeyTokenMinRemaining = 5 * time.Second
+
+// errApiKeyRevoked 重新校验时发现 Key 已吊销
+var errApiKeyRevoked = errors.New("api key revoked")
+
+// maxCachedCredentials 凭证缓存条目上限，超过后整体清空，避免随机 Key ID 撑爆内存
+const maxCachedCredentials = 10000
+
+// ApiKeyConfig 开放平台鉴权配置
+type ApiKeyConfig struct {
+	Scopes           []apikey.ScopeRule
+	DefaultRateLimit int           // Key 未单独配置限流时的每分钟请求数
+	JWTSecret        string        // 与 user-service 一致，用于以 Key 所属用户身份调用上游
+	CredentialTTL    time.Duration // 凭证本地缓存时长，同时是内部 JWT 的有效期，吊销/轮换最多延迟这么久生效
+	ReportInterval   time.Duration // 调用记录批量上报间隔
+}
+
+// ApiKeyMiddleware 开放平台 API Key 鉴权中间件。
+// 带 X-Api-Key 的请求依次校验：时间戳窗口 → Key 状态 → HMAC 签名 → nonce 防重放 → 权限范围 → 限流，
+// 通过后用网关签发的短期 JWT 替换 Authorization，以 Key 所属用户的身份调用上游服务
+// （有效期不超过凭证缓存时长，每次请求都检查令牌黑名单，所属用户下线所有设备或改密后立即重新校验 Key）；
+// 每次调用（含被拒绝的）都会异步批量上报到 user-service，用于审计和用量统计。
+// 不带 X-Api-Key 的请求原样放行，走普通的用户 JWT 鉴权。
+type ApiKeyMiddleware struct {
+	client   *client.ApiKeyClient
+	guard    *apikey.Guard
+	denylist *revocation.Denylist // 为 nil 时不检查令牌吊销
+	scopes   apikey.Scopes
+	conf     ApiKeyConfig
+
+	mu    sync.Mutex
+	creds map[string]*cachedCredential
+	calls chan *userv1.ApiKeyCall
+}
+
+// cachedCredential 本地缓存的凭证，cred 为 nil 表示 Key 不存在（负缓存）
+type cachedCredential struct {
+	cred      *userv1.ApiKeyCredential
+	expiresAt time.Time
+
+	token          string
+	tokenClaims    *utils.JWTClaims
+	tokenExpiresAt time.Time
+}
+
+// NewApiKeyMiddleware 创建 API Key 鉴权中间件，需要调用 RunReporter 才会上报调用记录
+func NewApiKeyMiddleware(apiKeyClient *client.ApiKeyClient, guard *apikey.Guard, denylist *revocation.Denylist, conf ApiKeyConfig) *ApiKeyMiddleware {
+	if conf.CredentialTTL <= 0 {
+		conf.CredentialTTL = 30 * time.Second
+	}
+	if conf.ReportInterval <= 0 {
+		conf.ReportInterval = 2 * time.Second
+	}
+	return &ApiKeyMiddleware{
+		client:   apiKeyClient,
+		guard:    guard,
+		denylist: denylist,
+		scopes:   apikey.NewScopes(conf.Scopes),
+		conf:     conf,
+		creds:    make(map[string]*cachedCredential),
+		calls:    make(chan *userv1.ApiKeyCall, 1024),
+	}
+}
+
+// Handle 返回 go-zero 的 rest.Middleware 类型
+func (m *ApiKeyMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keyID := r.Header.Get(apikey.HeaderKeyID)
+		if keyID == "" {
+			next(w, r)
+			return
+		}
+
+		start := time.Now()
+		ctx := r.Context()
+		reject := func(entry *cachedCredential, statusCode int, result, message string) {
+			if entry != nil && entry.cred != nil {
+				m.record(keyID, r, statusCode, result, start)
+			}
+			writeApiKeyError(w, statusCode, message)
+		}
+
+		tsHeader := r.Header.Get(apikey.HeaderTimestamp)
+		ts, err := strconv.ParseInt(tsHeader, 10, 64)
+		if err != nil {
+			reject(nil, http.StatusUnauthorized, "invalid_timestamp", "X-Timestamp 无效")
+			return
+		}
+		if !m.guard.CheckTimestamp(time.Unix(ts, 0), start) {
+			reject(nil, http.StatusUnauthorized, "expired_timestamp", "X-Timestamp 超出允许的时间窗口")
+			return
+		}
+		nonce := r.Header.Get(apikey.HeaderNonce)
+		if len(nonce) < 8 || len(nonce) > 64 {
+			reject(nil, http.StatusUnauthorized, "invalid_nonce", "X-Nonce 长度必须在 8 到 64 之间")
+			return
+		}
+		signature := r.Header.Get(apikey.HeaderSignature)
+		if signature == "" {
+			reject(nil, http.StatusUnauthorized, "missing_signature", "缺少 X-Signature")
+			return
+		}
+
+		entry, err := m.credential(ctx, keyID, start)
+		if err != nil {
+			logx.Errorf("api key credential lookup failed, key=%s: %v", keyID, err)
+			reject(nil, http.StatusServiceUnavailable, "auth_unavailable", "认证服务暂不可用，请稍后重试")
+			return
+		}
+		if entry.cred == nil || !entry.cred.Active {
+			reject(entry, http.StatusUnauthorized, "invalid_key", "API Key 无效或已吊销")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodyBytes+1))
+		if err != nil {
+			reject(entry, http.StatusBadRequest, "bad_request", "读取请求体失败")
+			return
+		}
+		if len(body) > maxSignedBodyBytes {
+			reject(entry, http.StatusRequestEntityTooLarge, "body_too_large", "请求体过大")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		stringToSign := apikey.StringToSign(r.Method, r.URL.Path, r.URL.Query(), tsHeader, nonce, body)
+		if !apikey.VerifySignature(entry.cred.Secrets, stringToSign, signature) {
+			reject(entry, http.StatusUnauthorized, "invalid_signature", "签名校验失败")
+			return
+		}
+
+		// 验签通过后再占用 nonce，避免未签名的请求消耗合作方的 nonce
+		fresh, err := m.guard.UseNonce(ctx, keyID, nonce)
+		if err != nil {
+			logx.Errorf("api key nonce check failed, key=%s: %v", keyID, err)
+			reject(entry, http.StatusServiceUnavailable, "auth_unavailable", "认证服务暂不可用，请稍后重试")
+			return
+		}
+		if !fresh {
+			reject(entry, http.StatusUnauthorized, "replayed_nonce", "X-Nonce 已被使用")
+			return
+		}
+
+		if !m.scopes.Allowed(entry.cred.Scopes, r.Method, r.URL.Path) {
+			reject(entry, http.StatusForbidden, "forbidden_scope", "API Key 无权访问该接口")
+			return
+		}
+
+		limit := int(entry.cred.RateLimit)
+		if limit <= 0 {
+			limit = m.conf.DefaultRateLimit
+		}
+		allowed, remaining, err := m.guard.Allow(ctx, keyID, limit, start)
+		if err != nil {
+			// 限流计数失败时放行，避免 Redis 抖动影响合作方
+			logx.Errorf("api key rate limit failed, key=%s: %v", keyID, err)
+			allowed = true
+		}
+		if limit > 0 {
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		}
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.FormatInt(60-start.Unix()%60, 10))
+			reject(entry, http.StatusTooManyRequests, "rate_limited", "请求过于频繁，请稍后再试")
+			return
+		}
+
+		token, err := m.ownerToken(ctx, keyID, entry, start)
+		if err != nil {
+			if errors.Is(err, errApiKeyRevoked) {
+				reject(entry, http.StatusUnauthorized, "invalid_key", "API Key 无效或已吊销")
+				return
+			}
+			logx.Errorf("api key owner token failed, key=%s: %v", keyID, err)
+			reject(entry, http.StatusInternalServerError, "internal_error", "签发内部凭证失败")
+			return
+		}
+		r.Header.Set("Authorization", "Bearer "+token)
+
+		rw := &statusResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rw, r)
+		m.record(keyID, r, rw.statusCode, "ok", start)
+	}
+}
+
+// RunReporter 批量上报调用记录，直到 ctx 结束（结束前会上报剩余记录）
+func (m *ApiKeyMiddleware) RunReporter(ctx context.Context) {
+	ticker := time.NewTicker(m.conf.ReportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*userv1.ApiKeyCall, 0, 100)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		reportCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := m.client.ReportCalls(reportCtx, batch); err != nil {
+			logx.Errorf("report api key calls failed, dropped %d records: %v", len(batch), err)
+		}
+		cancel()
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case call := <-m.calls:
+					batch = append(batch, call)
+				default:
+					flush()
+					return
+				}
+			}
+		case call := <-m.calls:
+			batch = append(batch, call)
+			if len(batch) >= 100 {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// credential 获取凭证（带本地缓存和负缓存）
+func (m *ApiKeyMiddleware) credential(ctx context.Context, keyID string, now time.Time) (*cachedCredential, error) {
+	m.mu.Lock()
+	entry, ok := m.creds[keyID]
+	m.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry, nil
+	}
+	return m.refreshCredential(ctx, keyID, now)
+}
+
+// refreshCredential 从 user-service 重新获取凭证，内部 JWT 随凭证一起作废
+func (m *ApiKeyMiddleware) refreshCredential(ctx context.Context, keyID string, now time.Time) (*cachedCredential, error) {
+	cred, err := m.client.GetCredential(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	fresh := &cachedCredential{cred: cred, expiresAt: now.Add(m.conf.CredentialTTL)}
+
+	m.mu.Lock()
+	if len(m.creds) >= maxCachedCredentials {
+		m.creds = make(map[string]*cachedCredential)
+	}
+	m.creds[keyID] = fresh
+	m.mu.Unlock()
+	return fresh, nil
+}
+
+// ownerToken 返回以 Key 所属用户身份调用上游的 JWT，有效期与凭证缓存时长相同。
+// 缓存的令牌已被吊销（所属用户下线所有设备、改密等）时不再使用：先向 user-service 重新校验 Key，再签发新令牌。
+func (m *ApiKeyMiddleware) ownerToken(ctx context.Context, keyID string, entry *cachedCredential, now time.Time) (string, error) {
+	m.mu.Lock()
+	token, claims := entry.token, entry.tokenClaims
+	if token == "" || !now.Add(apiKeyTokenMinRemaining).Before(entry.tokenExpiresAt) {
+		token = ""
+	}
+	m.mu.Unlock()
+
+	if token != "" && m.tokenRevoked(ctx, claims) {
+		fresh, err := m.refreshCredential(ctx, keyID, now)
+		if err != nil {
+			return "", err
+		}
+		if fresh.cred == nil || !fresh.cred.Active {
+			return "", errApiKeyRevoked
+		}
+		entry, token = fresh, ""
+	}
+	if token != "" {
+		return token, nil
+	}
+
+	ttl := int64(m.conf.CredentialTTL.Seconds())
+	if ttl < 1 {
+		ttl = 1
+	}
+	token, err := utils.GenerateToken(entry.cred.OwnerUserId, apikey.OwnerUsernamePrefix+keyID, m.conf.JWTSecret, ttl)
+	if err != nil {
+		return "", err
+	}
+	claims, err = utils.ParseToken(token, m.conf.JWTSecret)
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	entry.token, entry.tokenClaims, entry.tokenExpiresAt = token, claims, claims.ExpiresAt.Time
+	m.mu.Unlock()
+	return token, nil
+}
+
+// tokenRevoked 查询内部令牌是否已被吊销；查询失败时放行（只记录日志），与各服务的鉴权拦截器一致
+func (m *ApiKeyMiddleware) tokenRevoked(ctx context.Context, claims *utils.JWTClaims) bool {
+	if m.denylist == nil || claims == nil {
+		return false
+	}
+	revoked, err := m.denylist.IsRevoked(ctx, claims)
+	if err != nil {
+		logx.Errorf("api key token denylist check failed, user=%d: %v", claims.UserID, err)
+		return false
+	}
+	return revoked
+}
+
+// record 记录一次调用，队列满时丢弃（审计上报不能阻塞请求）
+func (m *ApiKeyMiddleware) record(keyID string, r *http.Request, statusCode int, result string, start time.Time) {
+	call := &userv1.ApiKeyCall{
+		KeyId:      keyID,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		StatusCode: int32(statusCode),
+		Result:     result,
+		ClientIp:   clientIP(r),
+		LatencyMs:  time.Since(start).Milliseconds(),
+		CreatedAt:  start.UnixMilli(),
+	}
+	select {
+	case m.calls <- call:
+	default:
+		logx.Errorf("api key call queue full, dropping record key=%s path=%s", keyID, r.URL.Path)
+	}
+}
+
+// clientIP 优先取反向代理写入的 X-Forwarded-For
+func clientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		ip, _, _ := strings.Cut(xff, ",")
+		return strings.TrimSpace(ip)
+	}
+	host := r.RemoteAddr
+	if i := strings.LastIndexByte(host, ':'); i > 0 {
+		host = host[:i]
+	}
+	return host
+}
+
+// writeApiKeyError 输出与网关一致的 JSON 错误体
+func writeApiKeyError(w http.ResponseWriter, statusCode int, message string) {
+	writeIdempotencyError(w, statusCode, message)
+}
+
+// statusResponseWriter 记录上游响应状态码
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *statusResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Flush 透传给底层 ResponseWriter，保证流式响应可用
+func (rw *statusResponseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	userv1 "ecommerce-system/api/user/v1"
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+const (
+	testKeyID     = "ak_test"
+	testKeySecret = "sk_test"
+	testKeyOwner  = 42
+)
+
+// fakeApiKeyService 模拟 user-service 的凭证接口，active 可在测试中切换
+type fakeApiKeyService struct {
+	userv1.UnimplementedApiKeyServiceServer
+
+	mu     sync.Mutex
+	active bool
+	calls  int
+}
+
+func (s *fakeApiKeyService) GetApiKeyCredential(ctx context.Context, req *userv1.GetApiKeyCredentialRequest) (*userv1.GetApiKeyCredentialResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	cred := &userv1.ApiKeyCredential{
+		KeyId:       req.KeyId,
+		OwnerUserId: testKeyOwner,
+		Scopes:      []string{"orders:read"},
+		Active:      s.active,
+	}
+	if s.active {
+		cred.Secrets = []string{testKeySecret}
+	}
+	return &userv1.GetApiKeyCredentialResponse{Data: cred}, nil
+}
+
+func (s *fakeApiKeyService) setActive(active bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = active
+}
+
+func (s *fakeApiKeyService) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// apiKeyHarness 一个 API Key 中间件加记录上游收到的 Authorization 的下游处理器
+type apiKeyHarness struct {
+	service  *fakeApiKeyService
+	denylist *revocation.Denylist
+	handler  http.HandlerFunc
+	upstream []string
+	nonce    int
+}
+
+func newApiKeyHarness(t *testing.T, credentialTTL time.Duration) *apiKeyHarness {
+	t.Helper()
+	svc := &fakeApiKeyService{active: true}
+	srv := grpc.NewServer()
+	userv1.RegisterApiKeyServiceServer(srv, svc)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	apiKeyClient, err := client.NewApiKeyClient(client.RpcConf{Endpoint: lis.Addr().String(), Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("NewApiKeyClient: %v", err)
+	}
+	t.Cleanup(func() { _ = apiKeyClient.Close() })
+
+	rdb := newTestRedis(t)
+	h := &apiKeyHarness{service: svc, denylist: revocation.NewDenylist(rdb)}
+	m := NewApiKeyMiddleware(apiKeyClient, apikey.NewGuard(rdb, 5*time.Minute), h.denylist, ApiKeyConfig{
+		Scopes:        []apikey.ScopeRule{{Name: "orders:read", Methods: []string{http.MethodGet}, Paths: []string{"/api/v1/orders"}}},
+		JWTSecret:     testJWTSecret,
+		CredentialTTL: credentialTTL,
+	})
+	h.handler = m.Handle(func(w http.ResponseWriter, r *http.Request) {
+		h.upstream = append(h.upstream, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	})
+	return h
+}
+
+// call 发送一个签名正确的请求
+func (h *apiKeyHarness) call() *httptest.ResponseRecorder {
+	h.nonce++
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := "nonce-" + strconv.Itoa(h.nonce) + "-0000"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	req.Header.Set(apikey.HeaderKeyID, testKeyID)
+	req.Header.Set(apikey.HeaderTimestamp, ts)
+	req.Header.Set(apikey.HeaderNonce, nonce)
+	req.Header.Set(apikey.HeaderSignature, apikey.Sign(testKeySecret, apikey.StringToSign(http.MethodGet, "/api/v1/orders", req.URL.Query(), ts, nonce, nil)))
+	rec := httptest.NewRecorder()
+	h.handler(rec, req)
+	return rec
+}
+
+func (h *apiKeyHarness) lastToken(t *testing.T) *utils.JWTClaims {
+	t.Helper()
+	claims, err := utils.ParseToken(h.upstream[len(h.upstream)-1], testJWTSecret)
+	if err != nil {
+		t.Fatalf("upstream token invalid: %v", err)
+	}
+	return claims
+}
+
+func TestApiKeyOwnerTokenNotLongerThanCredentialTTL(t *testing.T) {
+	h := newApiKeyHarness(t, 30*time.Second)
+	if rec := h.call(); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	claims := h.lastToken(t)
+	if claims.UserID != testKeyOwner || claims.Username != apikey.OwnerUsernamePrefix+testKeyID {
+		t.Fatalf("unexpected owner token claims: %+v", claims)
+	}
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl > 30*time.Second {
+		t.Fatalf("owner token lives %v, longer than the credential TTL", ttl)
+	}
+
+	// 凭证缓存期内复用同一个令牌
+	h.call()
+	if h.upstream[0] != h.upstream[1] || h.service.callCount() != 1 {
+		t.Fatalf("expected cached credential and token, got %d lookups", h.service.callCount())
+	}
+}
+
+func TestApiKeyRevokedKeyRejected(t *testing.T) {
+	h := newApiKeyHarness(t, time.Second)
+	if rec := h.call(); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	issued := h.lastToken(t)
+
+	h.service.setActive(false)
+	time.Sleep(1100 * time.Millisecond)
+
+	if rec := h.call(); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked key: status = %d, want 401", rec.Code)
+	}
+	if len(h.upstream) != 1 {
+		t.Fatalf("revoked key reached upstream %d times", len(h.upstream)-1)
+	}
+	// 吊销前签发的内部令牌也已经过期，不能继续使用
+	if _, err := utils.ParseToken(h.upstream[0], testJWTSecret); err == nil {
+		t.Fatalf("token issued before revocation (exp %v) is still valid", issued.ExpiresAt)
+	}
+}
+
+func TestApiKeyRevokedOwnerTokenNotReused(t *testing.T) {
+	h := newApiKeyHarness(t, time.Minute)
+	if rec := h.call(); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	before := h.lastToken(t)
+
+	// 所属用户下线所有设备：之前签发的令牌全部失效
+	ctx := context.Background()
+	if err := h.denylist.RevokeUser(ctx, testKeyOwner, time.Now().Add(time.Second), time.Hour); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second))) // 新令牌的签发时间不早于吊销时间
+
+	if rec := h.call(); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	after := h.lastToken(t)
+	if after.ID == before.ID {
+		t.Fatal("revoked owner token was forwarded again")
+	}
+	if revoked, err := h.denylist.IsRevoked(ctx, after); err != nil || revoked {
+		t.Fatalf("new owner token revoked=%v err=%v", revoked, err)
+	}
+	// 换发前向 user-service 重新校验了 Key
+	if h.service.callCount() != 2 {
+		t.Fatalf("credential lookups = %d, want 2", h.service.callCount())
+	}
+
+	// Key 在此期间被吊销时，重新校验后直接拒绝
+	if err := h.denylist.RevokeToken(ctx, after.ID, after.ExpiresAt.Time); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	h.service.setActive(false)
+	if rec := h.call(); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked owner and key: status = %d, want 401", rec.Code)
+	}
+	if len(h.upstream) != 2 {
+		t.Fatalf("upstream called %d times, want 2", len(h.upstream))
+	}
+}
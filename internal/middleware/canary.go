@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"ecommerce-system/internal/pkg/canary"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+// CanaryMiddleware 灰度路由中间件：按请求头、Cookie、用户 ID 为本次请求选定各服务的版本并写入 context，
+// 上游 gRPC 连接上的 canary 负载均衡器按选定的版本转发，响应缓存也按它区分条目。
+type CanaryMiddleware struct {
+	router *canary.Router
+}
+
+// NewCanaryMiddleware 创建灰度路由中间件
+func NewCanaryMiddleware(router *canary.Router) *CanaryMiddleware {
+	return &CanaryMiddleware{router: router}
+}
+
+// Handle 返回 go-zero 的 rest.Middleware 类型
+func (m *CanaryMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &canary.Request{
+			Header: r.Header,
+			UserID: userIDFromBearer(r.Header.Get("Authorization")),
+		}
+		m.router.Resolve(req)
+		next(w, r.WithContext(canary.WithRequest(r.Context(), req)))
+	}
+}
+
+// userIDFromBearer 从 Bearer token 中取出用户 ID。
+// 只用于分流，不做签名校验（鉴权仍由下游服务完成），伪造 token 最多影响落到哪个版本。
+func userIDFromBearer(auth string) string {
+	token, ok := strings.CutPrefix(auth, "Bearer ")
+	if !ok || token == "" {
+		return ""
+	}
+	var claims utils.JWTClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil || claims.UserID == 0 {
+		return ""
+	}
+	return strconv.FormatUint(claims.UserID, 10)
+}
@@ -32,8 +32,8 @@ func (m *CorsMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 		}
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length")
-		w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length, Idempotency-Key, X-Canary, If-None-Match, If-Modified-Since")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, Idempotent-Replayed, ETag, Last-Modified, X-Cache")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		// 处理 OPTIONS 预检请求
@@ -58,8 +58,8 @@ func CORSMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length")
-		w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Content-Length, Idempotency-Key, X-Canary, If-None-Match, If-Modified-Since")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, Idempotent-Replayed, ETag, Last-Modified, X-Cache")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		if r.Method == "OPTIONS" {
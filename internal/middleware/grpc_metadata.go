@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	grpcmw "ecommerce-system/internal/pkg/middleware"
+)
+
+// renamedHeaders gRPC 保留的头换一个 metadata key 转发，原样转发会被 gRPC 客户端覆盖
+var renamedHeaders = map[string]string{
+	"user-agent": grpcmw.MetadataUserAgent,
+}
+
+// ForwardHeaders 返回 gateway.WithHeaderProcessor 使用的头处理函数：
+// 把指定的 HTTP 头转发为 gRPC metadata（key 统一小写，gRPC 保留头按 renamedHeaders 改名）。
+// go-zero gateway 默认只转发 Grpc-Metadata- 前缀的头，Authorization 等需要显式转发。
+func ForwardHeaders(names ...string) func(http.Header) []string {
+	return func(header http.Header) []string {
+		var md []string
+		for _, name := range names {
+			key := strings.ToLower(name)
+			if renamed, ok := renamedHeaders[key]; ok {
+				key = renamed
+			}
+			for _, v := range header.Values(name) {
+				md = append(md, key+":"+v)
+			}
+		}
+		return md
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/idempotency"
+)
+
+// maxIdempotentBodyBytes 参与指纹计算的请求体上限（网关 JSON 请求体，文件上传不走这里）
+const maxIdempotentBodyBytes = 1 << 20
+
+// IdempotencyMiddleware 网关幂等中间件：POST/PUT 请求带 Idempotency-Key 时，
+// 保存首次响应并在重试时回放，避免重复下单 / 重复创建支付单。
+// 幂等键按令牌中的用户（开放平台请求按 API Key）隔离，jwtSecret 需与 user-service 保持一致。
+type IdempotencyMiddleware struct {
+	store     *idempotency.Store
+	jwtSecret string
+}
+
+// NewIdempotencyMiddleware 创建幂等中间件
+func NewIdempotencyMiddleware(store *idempotency.Store, jwtSecret string) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{store: store, jwtSecret: jwtSecret}
+}
+
+// Handle 返回 go-zero 的 rest.Middleware 类型
+func (m *IdempotencyMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m.store == nil || (r.Method != http.MethodPost && r.Method != http.MethodPut) {
+			next(w, r)
+			return
+		}
+		idemKey := r.Header.Get(idempotency.HeaderKey)
+		if idemKey == "" {
+			next(w, r)
+			return
+		}
+		if len(idemKey) > 128 {
+			writeIdempotencyError(w, http.StatusBadRequest, "Idempotency-Key 过长（最多 128 个字符）")
+			return
+		}
+
+		// 读取请求体计算指纹，再放回去交给后续 handler
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodyBytes+1))
+		if err != nil {
+			writeIdempotencyError(w, http.StatusBadRequest, "读取请求体失败")
+			return
+		}
+		if len(body) > maxIdempotentBodyBytes {
+			writeIdempotencyError(w, http.StatusRequestEntityTooLarge, "请求体过大，无法进行幂等校验")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := idempotency.BuildKey(idempotency.Scope(r.Header.Get("Authorization"), m.jwtSecret), idemKey)
+		fingerprint := idempotency.Fingerprint([]byte(r.Method), []byte(r.URL.Path), []byte(r.URL.RawQuery), body)
+
+		ctx := r.Context()
+		rec, err := m.store.Begin(ctx, key, fingerprint)
+		switch err {
+		case nil:
+		case idempotency.ErrInProgress:
+			writeIdempotencyError(w, http.StatusConflict, "相同 Idempotency-Key 的请求正在处理中，请稍后重试")
+			return
+		case idempotency.ErrKeyReused:
+			writeIdempotencyError(w, http.StatusUnprocessableEntity, "Idempotency-Key 已被用于不同的请求")
+			return
+		default:
+			// Redis 不可用时降级为不做幂等，避免影响主链路
+			logx.Errorf("idempotency begin failed, path=%s: %v", r.URL.Path, err)
+			next(w, r)
+			return
+		}
+
+		if rec != nil {
+			for k, v := range rec.Header {
+				w.Header().Set(k, v)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(rec.StatusCode)
+			_, _ = w.Write(rec.Body)
+			return
+		}
+
+		rw := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rw, r)
+
+		// 5xx 视为未完成，释放 key 允许客户端重试；其余响应（含业务错误）都保存下来
+		if rw.statusCode >= http.StatusInternalServerError {
+			_ = m.store.Release(ctx, key)
+			return
+		}
+		header := make(map[string]string, 1)
+		if ct := rw.Header().Get("Content-Type"); ct != "" {
+			header["Content-Type"] = ct
+		}
+		if err := m.store.Complete(ctx, key, &idempotency.Record{
+			Fingerprint: fingerprint,
+			StatusCode:  rw.statusCode,
+			Header:      header,
+			Body:        rw.body.Bytes(),
+			CreatedAt:   time.Now().Unix(),
+		}); err != nil {
+			logx.Errorf("idempotency complete failed, path=%s: %v", r.URL.Path, err)
+			_ = m.store.Release(ctx, key)
+		}
+	}
+}
+
+// writeIdempotencyError 输出与网关一致的 JSON 错误体
+func writeIdempotencyError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    statusCode,
+		"message": message,
+	})
+}
+
+// recordingResponseWriter 在写出响应的同时保留一份副本
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/idempotency"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+const testJWTSecret = "test-secret"
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func bearer(t *testing.T, userID uint64, username string) string {
+	t.Helper()
+	token, err := utils.GenerateToken(userID, username, testJWTSecret, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+func TestIdempotencyReplaysAfterTokenRefresh(t *testing.T) {
+	store := idempotency.NewStore(newTestRedis(t), time.Hour, 30*time.Second)
+	m := NewIdempotencyMiddleware(store, testJWTSecret)
+
+	calls := 0
+	handler := m.Handle(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"order_no":"O1"}}`))
+	})
+	send := func(auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"sku_id":1}`))
+		req.Header.Set(idempotency.HeaderKey, "order-1")
+		req.Header.Set("Authorization", auth)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	first := send(bearer(t, 7, "alice"))
+	// 访问令牌刷新后用同一个 key 重试，应回放而不是重新下单
+	retry := send(bearer(t, 7, "alice"))
+	if calls != 1 {
+		t.Fatalf("handler executed %d times, want 1", calls)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry was not replayed: %d %q", retry.Code, retry.Body.String())
+	}
+
+	// 其他用户使用同一个 key 互不影响
+	send(bearer(t, 8, "bob"))
+	if calls != 2 {
+		t.Fatalf("other user's request should execute, calls=%d", calls)
+	}
+}
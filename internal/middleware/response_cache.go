@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/canary"
+	"ecommerce-system/internal/pkg/httpcache"
+)
+
+// ResponseCacheMiddleware 网关响应缓存中间件：只缓存匿名 GET 请求的 200 响应，
+// 生成 ETag / Last-Modified，支持 If-None-Match / If-Modified-Since 返回 304，并输出 CDN 可用的 Cache-Control。
+type ResponseCacheMiddleware struct {
+	store  *httpcache.Store
+	routes []httpcache.Route
+}
+
+// NewResponseCacheMiddleware 创建响应缓存中间件
+func NewResponseCacheMiddleware(store *httpcache.Store, routes []httpcache.Route) *ResponseCacheMiddleware {
+	return &ResponseCacheMiddleware{store: store, routes: routes}
+}
+
+// Handle 返回 go-zero 的 rest.Middleware 类型
+func (m *ResponseCacheMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 带 Authorization 的请求可能包含个性化数据，不走缓存
+		if r.Method != http.MethodGet || r.Header.Get("Authorization") != "" {
+			next(w, r)
+			return
+		}
+		route := m.match(r.URL.Path)
+		if route == nil {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		// 灰度中间件先于缓存执行，按选定的版本区分条目，稳定版和灰度版的响应互不串用
+		key := httpcache.BuildKey(route.Name, r, route.Vary, canary.VersionKey(ctx))
+		entry, err := m.store.Get(ctx, key)
+		if err != nil {
+			logx.Errorf("response cache get failed, path=%s: %v", r.URL.Path, err)
+		}
+		if entry != nil {
+			w.Header().Set("X-Cache", "HIT")
+			writeCachedEntry(w, r, route, entry)
+			return
+		}
+
+		bw := &bufferedResponseWriter{header: make(http.Header), statusCode: http.StatusOK}
+		next(bw, r)
+
+		// 只缓存成功且未设置 Cookie 的响应
+		if bw.statusCode != http.StatusOK || bw.header.Get("Set-Cookie") != "" {
+			bw.flushTo(w)
+			return
+		}
+
+		entry = &httpcache.Entry{
+			StatusCode:   bw.statusCode,
+			Body:         bw.body.Bytes(),
+			ETag:         httpcache.ETag(bw.body.Bytes()),
+			LastModified: time.Now().Unix(),
+		}
+		if ct := bw.header.Get("Content-Type"); ct != "" {
+			entry.Header = map[string]string{"Content-Type": ct}
+		}
+		if err := m.store.Set(ctx, key, entry, time.Duration(route.TTL)*time.Second); err != nil {
+			logx.Errorf("response cache set failed, path=%s: %v", r.URL.Path, err)
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+		writeCachedEntry(w, r, route, entry)
+	}
+}
+
+// Purge 按事件类型清除配置了 PurgeOn 的路由
+func (m *ResponseCacheMiddleware) Purge(ctx context.Context, eventType string) {
+	for i := range m.routes {
+		route := &m.routes[i]
+		if !slices.Contains(route.PurgeOn, eventType) {
+			continue
+		}
+		if err := m.store.PurgeRoute(ctx, route.Name); err != nil {
+			logx.Errorf("response cache purge failed, route=%s, event=%s: %v", route.Name, eventType, err)
+			continue
+		}
+		logx.Infof("response cache purged, route=%s, event=%s", route.Name, eventType)
+	}
+}
+
+func (m *ResponseCacheMiddleware) match(path string) *httpcache.Route {
+	for i := range m.routes {
+		if m.routes[i].Match(path) {
+			return &m.routes[i]
+		}
+	}
+	return nil
+}
+
+// writeCachedEntry 写出缓存条目（含校验头），条件请求命中时返回 304
+func writeCachedEntry(w http.ResponseWriter, r *http.Request, route *httpcache.Route, entry *httpcache.Entry) {
+	h := w.Header()
+	for k, v := range entry.Header {
+		h.Set(k, v)
+	}
+	lastModified := time.Unix(entry.LastModified, 0).UTC()
+	h.Set("ETag", entry.ETag)
+	h.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	h.Set("Cache-Control", cacheControl(route))
+	if len(route.Vary) > 0 {
+		h.Set("Vary", strings.Join(route.Vary, ", "))
+	}
+
+	if notModified(r, entry.ETag, lastModified) {
+		h.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Length", strconv.Itoa(len(entry.Body)))
+	w.WriteHeader(entry.StatusCode)
+	_, _ = w.Write(entry.Body)
+}
+
+// notModified If-None-Match 优先；没有 If-None-Match 时才看 If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return httpcache.MatchETag(inm, etag)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(t)
+	}
+	return false
+}
+
+func cacheControl(route *httpcache.Route) string {
+	cc := "public, max-age=" + strconv.FormatInt(route.TTL, 10)
+	if route.SMaxAge > 0 {
+		cc += ", s-maxage=" + strconv.FormatInt(route.SMaxAge, 10)
+	}
+	return cc
+}
+
+// bufferedResponseWriter 先缓冲完整响应，计算 ETag 后再决定返回 200 还是 304
+type bufferedResponseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (bw *bufferedResponseWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferedResponseWriter) WriteHeader(code int) {
+	bw.statusCode = code
+}
+
+func (bw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return bw.body.Write(b)
+}
+
+// flushTo 原样写出未缓存的响应
+func (bw *bufferedResponseWriter) flushTo(w http.ResponseWriter) {
+	for k, v := range bw.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(bw.statusCode)
+	_, _ = w.Write(bw.body.Bytes())
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecommerce-system/internal/pkg/canary"
+	"ecommerce-system/internal/pkg/httpcache"
+	"ecommerce-system/internal/pkg/outbox"
+)
+
+// newTestResponseCache 返回缓存中间件和包装好的 handler，calls 记录上游被调用的次数
+func newTestResponseCache(t *testing.T, upstream http.HandlerFunc) (*ResponseCacheMiddleware, http.HandlerFunc, *int) {
+	t.Helper()
+	m := NewResponseCacheMiddleware(httpcache.NewStore(newTestRedis(t)), []httpcache.Route{{
+		Name:    "product-detail",
+		Path:    "/api/v1/products/:id",
+		TTL:     60,
+		PurgeOn: []string{outbox.EventProductUpserted},
+	}})
+	calls := 0
+	return m, m.Handle(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		upstream(w, r)
+	}), &calls
+}
+
+func jsonUpstream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"code":0,"data":{"id":1}}`))
+}
+
+func get(handler http.HandlerFunc, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestResponseCacheETagAndNotModified(t *testing.T) {
+	_, handler, calls := newTestResponseCache(t, jsonUpstream)
+
+	miss := get(handler, "/api/v1/products/1", nil)
+	etag := miss.Header().Get("ETag")
+	if miss.Code != http.StatusOK || miss.Header().Get("X-Cache") != "MISS" || etag == "" {
+		t.Fatalf("first request: code=%d x-cache=%q etag=%q", miss.Code, miss.Header().Get("X-Cache"), etag)
+	}
+
+	hit := get(handler, "/api/v1/products/1", nil)
+	if hit.Header().Get("X-Cache") != "HIT" || hit.Body.String() != miss.Body.String() || hit.Header().Get("ETag") != etag {
+		t.Fatalf("second request should hit cache: x-cache=%q body=%q", hit.Header().Get("X-Cache"), hit.Body.String())
+	}
+
+	notModified := get(handler, "/api/v1/products/1", map[string]string{"If-None-Match": etag})
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Fatalf("If-None-Match should return 304, got %d %q", notModified.Code, notModified.Body.String())
+	}
+	changed := get(handler, "/api/v1/products/1", map[string]string{"If-None-Match": `"stale"`})
+	if changed.Code != http.StatusOK {
+		t.Fatalf("mismatched ETag should return 200, got %d", changed.Code)
+	}
+	if *calls != 1 {
+		t.Fatalf("upstream called %d times, want 1", *calls)
+	}
+}
+
+func TestResponseCacheSkipsPersonalizedRequests(t *testing.T) {
+	_, handler, calls := newTestResponseCache(t, jsonUpstream)
+	for i := 0; i < 2; i++ {
+		rec := get(handler, "/api/v1/products/1", map[string]string{"Authorization": "Bearer token"})
+		if rec.Header().Get("X-Cache") != "" || rec.Header().Get("ETag") != "" {
+			t.Fatalf("authorized request must bypass cache, headers=%v", rec.Header())
+		}
+	}
+	if *calls != 2 {
+		t.Fatalf("upstream called %d times, want 2", *calls)
+	}
+	// 未配置的路由不缓存
+	get(handler, "/api/v1/orders", nil)
+	get(handler, "/api/v1/orders", nil)
+	if *calls != 4 {
+		t.Fatalf("unmatched route should not be cached, calls=%d", *calls)
+	}
+
+	_, cookieHandler, cookieCalls := newTestResponseCache(t, func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc"})
+		jsonUpstream(w, r)
+	})
+	for i := 0; i < 2; i++ {
+		rec := get(cookieHandler, "/api/v1/products/1", nil)
+		if rec.Header().Get("Set-Cookie") == "" || rec.Header().Get("X-Cache") != "" {
+			t.Fatalf("response with Set-Cookie must pass through uncached, headers=%v", rec.Header())
+		}
+	}
+	if *cookieCalls != 2 {
+		t.Fatalf("upstream called %d times, want 2", *cookieCalls)
+	}
+}
+
+func TestResponseCachePurgeOnEvent(t *testing.T) {
+	m, handler, calls := newTestResponseCache(t, jsonUpstream)
+	ctx := context.Background()
+
+	get(handler, "/api/v1/products/1", nil)
+	get(handler, "/api/v1/products/2", nil)
+
+	m.Purge(ctx, outbox.EventProductDeleted) // 路由未订阅该事件
+	if rec := get(handler, "/api/v1/products/1", nil); rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatal("unsubscribed event must not purge the route")
+	}
+
+	m.Purge(ctx, outbox.EventProductUpserted)
+	for _, path := range []string{"/api/v1/products/1", "/api/v1/products/2"} {
+		if rec := get(handler, path, nil); rec.Header().Get("X-Cache") != "MISS" {
+			t.Fatalf("%s should be purged, x-cache=%q", path, rec.Header().Get("X-Cache"))
+		}
+	}
+	if *calls != 4 {
+		t.Fatalf("upstream called %d times, want 4", *calls)
+	}
+}
+
+func TestResponseCacheSeparatesCanaryVersions(t *testing.T) {
+	router, err := canary.NewRouter(&canary.Config{Services: []canary.ServiceConfig{{
+		Name:     "product-service",
+		Versions: []canary.Version{{Name: "v1", Target: "127.0.0.1:8081"}, {Name: "v2", Target: "127.0.0.1:18081"}},
+		Rules:    []canary.Rule{{Type: canary.RuleHeader, Name: "X-Canary", Version: "v2"}},
+	}}})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	// 上游按选定的版本返回不同内容，模拟稳定版和灰度版的响应差异
+	_, cached, calls := newTestResponseCache(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(canary.VersionKey(r.Context())))
+	})
+	handler := NewCanaryMiddleware(router).Handle(cached)
+
+	stable := get(handler, "/api/v1/products/1", nil)
+	canaryUser := get(handler, "/api/v1/products/1", map[string]string{"X-Canary": "1"})
+	if canaryUser.Header().Get("X-Cache") != "MISS" || canaryUser.Body.String() != "product-service=v2" {
+		t.Fatalf("canary request served stable entry: x-cache=%q body=%q", canaryUser.Header().Get("X-Cache"), canaryUser.Body.String())
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := get(handler, "/api/v1/products/1", nil); rec.Header().Get("X-Cache") != "HIT" || rec.Body.String() != stable.Body.String() {
+			t.Fatalf("stable request: x-cache=%q body=%q", rec.Header().Get("X-Cache"), rec.Body.String())
+		}
+		if rec := get(handler, "/api/v1/products/1", map[string]string{"X-Canary": "1"}); rec.Header().Get("X-Cache") != "HIT" || rec.Body.String() != "product-service=v2" {
+			t.Fatalf("canary request: x-cache=%q body=%q", rec.Header().Get("X-Cache"), rec.Body.String())
+		}
+	}
+	if *calls != 2 {
+		t.Fatalf("upstream called %d times, want 2", *calls)
+	}
+}
@@ -0,0 +1,147 @@
+// Package apikey 开放平台 API Key：密钥生成、HMAC-SHA256 请求签名、secret 加密存储和网关侧的防重放/限流。
+//
+// 合作方每个请求需要携带以下请求头：
+//
+//	X-Api-Key:   ak_xxx                 Key ID
+//	X-Timestamp: 1700000000             Unix 秒，与网关时间相差不能超过 5 分钟
+//	X-Nonce:     随机串（8~64 个字符）   同一个 Key 在时间窗口内不能重复
+//	X-Signature: base64(HMAC-SHA256(secret, StringToSign))
+//
+// StringToSign 由以下部分用换行符连接：
+//
+//	METHOD
+//	PATH
+//	按 key 排序后的查询串（url.Values.Encode）
+//	X-Timestamp
+//	X-Nonce
+//	hex(sha256(请求体))
+package apikey
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// 请求头
+const (
+	HeaderKeyID     = "X-Api-Key"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// OwnerUsernamePrefix 网关以 Key 所属用户身份签发内部 JWT 时使用的用户名前缀，后接 Key ID
+const OwnerUsernamePrefix = "apikey:"
+
+// KeyIDPrefix Key ID 前缀，便于在日志和代码扫描中识别
+const KeyIDPrefix = "ak_"
+
+// ErrInvalidCiphertext secret 密文损坏或加密密钥不匹配
+var ErrInvalidCiphertext = errors.New("apikey: invalid ciphertext")
+
+// GenerateKey 生成新的 Key ID 和 secret
+func GenerateKey() (keyID, secret string, err error) {
+	id := make([]byte, 12)
+	if _, err = rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret, err = GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	return KeyIDPrefix + hex.EncodeToString(id), secret, nil
+}
+
+// GenerateSecret 生成 32 字节随机 secret（URL 安全 base64）
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// StringToSign 构造待签名串
+func StringToSign(method, path string, query url.Values, timestamp, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		query.Encode(),
+		timestamp,
+		nonce,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+// Sign 计算签名
+func Sign(secret, stringToSign string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// VerifySignature 使用任一 secret（轮换宽限期内会有两个）校验签名
+func VerifySignature(secrets []string, stringToSign, signature string) bool {
+	for _, secret := range secrets {
+		if secret != "" && hmac.Equal([]byte(Sign(secret, stringToSign)), []byte(signature)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cipher 使用 AES-256-GCM 加密存储 secret（HMAC 验签需要明文 secret，因此不能只存哈希）
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher 由任意长度的主密钥派生 AES-256 密钥
+func NewCipher(masterKey string) (*Cipher, error) {
+	if masterKey == "" {
+		return nil, errors.New("apikey: empty encryption key")
+	}
+	key := sha256.Sum256([]byte(masterKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt 加密，输出 base64(nonce || ciphertext)
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密 Encrypt 的输出
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plain), nil
+}
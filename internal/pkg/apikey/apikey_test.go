@@ -0,0 +1,80 @@
+package apikey
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	keyID, secret, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(keyID, KeyIDPrefix) {
+		t.Fatalf("unexpected key id %q", keyID)
+	}
+
+	query := url.Values{"page": {"1"}, "status": {"2"}}
+	sts := StringToSign("get", "/api/v1/orders", query, "1700000000", "n0nce123", nil)
+	sig := Sign(secret, sts)
+
+	if !VerifySignature([]string{secret}, sts, sig) {
+		t.Fatal("signature should verify")
+	}
+	// 轮换宽限期：新旧 secret 任一匹配即可
+	newSecret, _ := GenerateSecret()
+	if !VerifySignature([]string{newSecret, secret}, sts, sig) {
+		t.Fatal("previous secret should still verify during grace period")
+	}
+	if VerifySignature([]string{newSecret}, sts, sig) {
+		t.Fatal("signature should not verify with another secret")
+	}
+	tampered := StringToSign("GET", "/api/v1/orders", url.Values{"page": {"2"}, "status": {"2"}}, "1700000000", "n0nce123", nil)
+	if VerifySignature([]string{secret}, tampered, sig) {
+		t.Fatal("tampered query should not verify")
+	}
+}
+
+func TestCipher(t *testing.T) {
+	c, err := NewCipher("master-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := c.Encrypt("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := c.Decrypt(enc); err != nil || plain != "s3cret" {
+		t.Fatalf("decrypt = %q, %v", plain, err)
+	}
+	other, _ := NewCipher("other-key")
+	if _, err := other.Decrypt(enc); err != ErrInvalidCiphertext {
+		t.Fatalf("expected ErrInvalidCiphertext, got %v", err)
+	}
+}
+
+func TestScopes(t *testing.T) {
+	scopes := NewScopes([]ScopeRule{
+		{Name: "orders:read", Methods: []string{"GET"}, Paths: []string{"/api/v1/orders", "/api/v1/orders/*"}},
+		{Name: "inventory:write", Methods: []string{"POST", "PUT"}, Paths: []string{"/api/v1/inventory/*"}},
+	})
+
+	cases := []struct {
+		granted      []string
+		method, path string
+		want         bool
+	}{
+		{[]string{"orders:read"}, "GET", "/api/v1/orders", true},
+		{[]string{"orders:read"}, "GET", "/api/v1/orders/123", true},
+		{[]string{"orders:read"}, "POST", "/api/v1/orders", false},
+		{[]string{"orders:read"}, "GET", "/api/v1/ordersx", false},
+		{[]string{"inventory:write"}, "PUT", "/api/v1/inventory/sku/1", true},
+		{[]string{"unknown"}, "GET", "/api/v1/orders", false},
+	}
+	for _, c := range cases {
+		if got := scopes.Allowed(c.granted, c.method, c.path); got != c.want {
+			t.Errorf("Allowed(%v, %s %s) = %v, want %v", c.granted, c.method, c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/cache"
+)
+
+// Guard 网关侧的防重放和限流，状态保存在 Redis 中供多个网关实例共享
+type Guard struct {
+	client *redis.Client
+	window time.Duration // 时间戳允许的偏差，nonce 至少保存 2 倍窗口
+}
+
+// NewGuard 创建 Guard，window <= 0 时使用 5 分钟
+func NewGuard(client *redis.Client, window time.Duration) *Guard {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	return &Guard{client: client, window: window}
+}
+
+// Window 时间戳允许的偏差
+func (g *Guard) Window() time.Duration {
+	return g.window
+}
+
+// CheckTimestamp 校验请求时间戳是否在窗口内
+func (g *Guard) CheckTimestamp(ts, now time.Time) bool {
+	d := now.Sub(ts)
+	return d <= g.window && d >= -g.window
+}
+
+// UseNonce 占用 nonce，已被使用过时返回 false
+func (g *Guard) UseNonce(ctx context.Context, keyID, nonce string) (bool, error) {
+	return g.client.SetNX(ctx, cache.BuildKey(cache.KeyPrefixApiKeyNonce, keyID, nonce), 1, 2*g.window).Result()
+}
+
+// rateScript 固定窗口计数：首次 INCR 时设置过期时间
+var rateScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// Allow 按分钟窗口限流，返回是否放行和当前窗口剩余次数
+func (g *Guard) Allow(ctx context.Context, keyID string, limitPerMinute int, now time.Time) (bool, int, error) {
+	if limitPerMinute <= 0 {
+		return true, -1, nil
+	}
+	minute := now.Unix() / 60
+	key := cache.BuildKey(cache.KeyPrefixApiKeyRate, keyID, minute)
+	n, err := rateScript.Run(ctx, g.client, []string{key}, time.Minute.Milliseconds()).Int()
+	if err != nil {
+		return false, 0, err
+	}
+	remaining := limitPerMinute - n
+	if remaining < 0 {
+		remaining = 0
+	}
+	return n <= limitPerMinute, remaining, nil
+}
@@ -0,0 +1,66 @@
+package apikey
+
+import (
+	"strings"
+)
+
+// ScopeRule 权限范围到网关路由的映射，由网关配置；一个 Key 只能访问其 scopes 覆盖的路由
+type ScopeRule struct {
+	Name    string   // 权限范围名，如 orders:read
+	Methods []string `json:",optional"` // HTTP 方法，为空表示全部
+	Paths   []string // 路由路径，以 /* 结尾表示前缀匹配，如 /api/v1/orders/*
+}
+
+// Match 判断请求是否在该权限范围内
+func (r *ScopeRule) Match(method, path string) bool {
+	if len(r.Methods) > 0 {
+		ok := false
+		for _, m := range r.Methods {
+			if strings.EqualFold(m, method) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	for _, p := range r.Paths {
+		if prefix, ok := strings.CutSuffix(p, "/*"); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+		} else if path == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Scopes 权限范围表
+type Scopes map[string]ScopeRule
+
+// NewScopes 从配置构建权限范围表
+func NewScopes(rules []ScopeRule) Scopes {
+	s := make(Scopes, len(rules))
+	for _, r := range rules {
+		s[r.Name] = r
+	}
+	return s
+}
+
+// Known 判断权限范围名是否已定义
+func (s Scopes) Known(name string) bool {
+	_, ok := s[name]
+	return ok
+}
+
+// Allowed 判断持有 granted 权限范围的 Key 能否访问该请求
+func (s Scopes) Allowed(granted []string, method, path string) bool {
+	for _, name := range granted {
+		if rule, ok := s[name]; ok && rule.Match(method, path) {
+			return true
+		}
+	}
+	return false
+}
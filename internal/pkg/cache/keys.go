@@ -8,11 +8,23 @@ import (
 // 缓存键前缀定义
 const (
 	// 用户相关
-	KeyPrefixUserInfo    = "user:info:"    // user:info:{user_id}
-	KeyPrefixUserSession = "user:session:" // user:session:{token}
-	KeyPrefixUserAddress = "user:address:" // user:address:{user_id}
-	KeyPrefixVerifyCode  = "verify:code:"  // verify:code:{phone/email}:{type}
-	KeyPrefixLoginFail   = "login:fail:"   // login:fail:{username}
+	KeyPrefixUserInfo       = "user:info:"       // user:info:{user_id}
+	KeyPrefixUserSession    = "user:session:"    // user:session:{token}
+	KeyPrefixUserAddress    = "user:address:"    // user:address:{user_id}
+	KeyPrefixVerifyCode     = "verify:code:"     // verify:code:{phone/email}:{type}
+	KeyPrefixVerifyInterval = "verify:interval:" // verify:interval:{phone/email}
+	KeyPrefixVerifyTarget   = "verify:target:"   // verify:target:{phone/email}
+	KeyPrefixVerifyIP       = "verify:ip:"       // verify:ip:{ip}
+	KeyPrefixLoginFail      = "login:fail:"      // login:fail:{account} / login:fail:ip:{ip}
+	KeyPrefixLoginLock      = "login:lock:"      // login:lock:{account}
+	KeyPrefixOAuthState     = "oauth:state:"     // oauth:state:{state}
+	KeyPrefixMFAChallenge   = "mfa:challenge:"   // mfa:challenge:{token}
+	KeyPrefixPasswordReset  = "pwd:reset:"       // pwd:reset:{token_hash} / pwd:reset:user:{user_id} / pwd:reset:interval:{target}
+	KeyPrefixMemberFreeShip = "member:freeship:" // member:freeship:{user_id}:{yyyymm}，当月已包邮的订单号集合
+
+	// 令牌吊销
+	KeyPrefixTokenDenylist = "auth:deny:"    // auth:deny:{jti}
+	KeyPrefixUserRevokedAt = "auth:revoked:" // auth:revoked:{user_id}
 
 	// 商品相关
 	KeyPrefixProductDetail = "product:detail:" // product:detail:{product_id}
@@ -54,6 +66,22 @@ const (
 
 	// 分布式锁
 	KeyPrefixLock = "lock:" // lock:{resource}:{id}
+
+	// 幂等键
+	KeyPrefixIdempotency = "idempotency:" // idempotency:{scope}:{key}
+
+	// 网关响应缓存
+	KeyPrefixHTTPCache = "httpcache:" // httpcache:{route}:{hash}
+
+	// BFF 页面聚合
+	KeyPrefixPageProduct = "page:product:" // page:product:{product_id}
+
+	// 服务端推送
+	KeyPrefixPushStream = "push:stream:" // push:stream:{user_id}
+
+	// 开放平台 API Key
+	KeyPrefixApiKeyNonce = "apikey:nonce:" // apikey:nonce:{key_id}:{nonce}
+	KeyPrefixApiKeyRate  = "apikey:rate:"  // apikey:rate:{key_id}:{minute}
 )
 
 // BuildKey 构建缓存键（带分隔符）
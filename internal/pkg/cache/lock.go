@@ -57,6 +57,29 @@ func (dl *DistributedLock) Unlock(ctx context.Context) error {
 	return err
 }
 
+// Renew 续期锁，仅当锁仍由自己持有时把过期时间重置为 expiration，返回是否续期成功
+func (dl *DistributedLock) Renew(ctx context.Context) (bool, error) {
+	script := `
+		if redis.call("get", KEYS[1]) == ARGV[1] then
+			return redis.call("pexpire", KEYS[1], ARGV[2])
+		else
+			return 0
+		end
+	`
+
+	result, err := dl.client.Eval(
+		ctx,
+		script,
+		[]string{dl.key},             // KEYS[1]
+		dl.value,                     // ARGV[1]
+		dl.expiration.Milliseconds(), // ARGV[2]
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
 // TryLock 尝试获取锁，带重试
 func (dl *DistributedLock) TryLock(ctx context.Context, maxRetries int, retryInterval time.Duration) (bool, error) {
 	for i := 0; i < maxRetries; i++ {
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestLockClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, client
+}
+
+func TestLockRenew(t *testing.T) {
+	mr, client := newTestLockClient(t)
+	ctx := context.Background()
+	lock := NewDistributedLock(client, "lock:test", 3*time.Second)
+
+	if ok, err := lock.Lock(ctx); err != nil || !ok {
+		t.Fatalf("Lock: ok=%v err=%v", ok, err)
+	}
+	mr.FastForward(2 * time.Second)
+	if ok, err := lock.Renew(ctx); err != nil || !ok {
+		t.Fatalf("Renew: ok=%v err=%v", ok, err)
+	}
+	// 续期后过期时间重新从 expiration 开始计算
+	if ttl := mr.TTL("lock:test"); ttl != 3*time.Second {
+		t.Fatalf("ttl after renew = %v, want 3s", ttl)
+	}
+	mr.FastForward(2 * time.Second)
+	if !mr.Exists("lock:test") {
+		t.Fatal("renewed lock expired early")
+	}
+}
+
+func TestLockRenewFailsAfterLockLost(t *testing.T) {
+	mr, client := newTestLockClient(t)
+	ctx := context.Background()
+	lock := NewDistributedLock(client, "lock:test", 3*time.Second)
+
+	if ok, err := lock.Lock(ctx); err != nil || !ok {
+		t.Fatalf("Lock: ok=%v err=%v", ok, err)
+	}
+	// 锁已过期：续期失败，也不会重新创建锁
+	mr.FastForward(4 * time.Second)
+	if ok, err := lock.Renew(ctx); err != nil || ok {
+		t.Fatalf("Renew expired lock: ok=%v err=%v", ok, err)
+	}
+	if mr.Exists("lock:test") {
+		t.Fatal("renew recreated an expired lock")
+	}
+
+	// 锁已被其他实例取得：续期失败，不能延长别人的锁
+	other := NewDistributedLock(client, "lock:test", 5*time.Second)
+	if ok, err := other.Lock(ctx); err != nil || !ok {
+		t.Fatalf("other Lock: ok=%v err=%v", ok, err)
+	}
+	if ok, err := lock.Renew(ctx); err != nil || ok {
+		t.Fatalf("Renew lock held by another: ok=%v err=%v", ok, err)
+	}
+	if ttl := mr.TTL("lock:test"); ttl != 5*time.Second {
+		t.Fatalf("other holder's ttl changed to %v", ttl)
+	}
+	// 旧的持有者也不能释放别人的锁
+	if err := lock.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if !mr.Exists("lock:test") {
+		t.Fatal("stale holder released another holder's lock")
+	}
+	if ok, err := other.Renew(ctx); err != nil || !ok {
+		t.Fatalf("current holder Renew: ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,95 @@
+package canary
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+
+	"ecommerce-system/internal/pkg/monitoring"
+)
+
+// BalancerName 灰度负载均衡器名称，上游配置 BalancerName: canary
+const BalancerName = "canary"
+
+// Register 注册灰度解析器和负载均衡器（全局，进程启动时调用一次）
+func Register(router *Router) {
+	resolver.Register(&resolverBuilder{router: router})
+	balancer.Register(base.NewBalancerBuilder(BalancerName, &pickerBuilder{router: router}, base.Config{HealthCheck: true}))
+}
+
+func errUnknownService(service string) error {
+	return fmt.Errorf("canary: service %s is not configured", service)
+}
+
+type pickerBuilder struct {
+	router *Router
+}
+
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	p := &picker{router: b.router, byVersion: make(map[string][]balancer.SubConn)}
+	for sc, scInfo := range info.ReadySCs {
+		ai, ok := getAddrInfo(scInfo.Address)
+		if !ok {
+			continue
+		}
+		p.service = ai.Service
+		p.byVersion[ai.Version] = append(p.byVersion[ai.Version], sc)
+		p.all = append(p.all, versionedConn{version: ai.Version, conn: sc})
+	}
+	if len(p.all) == 0 {
+		return base.NewErrPicker(status.Error(codes.Unavailable, "canary: no versioned address"))
+	}
+	return p
+}
+
+type versionedConn struct {
+	version string
+	conn    balancer.SubConn
+}
+
+// picker 每次调用根据 context 中的请求信息选择版本，版本内轮询
+type picker struct {
+	router    *Router
+	service   string
+	byVersion map[string][]balancer.SubConn
+	all       []versionedConn
+	next      atomic.Uint32
+}
+
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	version := p.router.Route(p.service, requestFromContext(info.Ctx))
+	conns := p.byVersion[version]
+	if len(conns) == 0 {
+		// 目标版本没有可用连接时回退到稳定版本，仍不可用则任选一个
+		version = p.router.Stable(p.service)
+		conns = p.byVersion[version]
+	}
+
+	n := p.next.Add(1)
+	var sc balancer.SubConn
+	if len(conns) > 0 {
+		sc = conns[n%uint32(len(conns))]
+	} else {
+		vc := p.all[n%uint32(len(p.all))]
+		version, sc = vc.version, vc.conn
+	}
+
+	start := time.Now()
+	service := p.service
+	return balancer.PickResult{
+		SubConn: sc,
+		Done: func(di balancer.DoneInfo) {
+			monitoring.RecordCanaryRequest(service, version, status.Code(di.Err).String(), time.Since(start).Seconds())
+		},
+	}, nil
+}
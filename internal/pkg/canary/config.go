@@ -0,0 +1,111 @@
+// Package canary 网关灰度路由：为同一个服务配置多个版本（权重分组），
+// 按请求头、Cookie、用户 ID 哈希或百分比把流量导向指定版本。
+//
+// 接入方式：上游 Grpc 配置 Target: canary:///<服务名>、BalancerName: canary，
+// 解析器会把该服务所有版本的地址交给 canary 负载均衡器，由它按请求选择版本。
+package canary
+
+import (
+	"fmt"
+)
+
+// 规则类型
+const (
+	RuleHeader  = "header"  // 请求头匹配
+	RuleCookie  = "cookie"  // Cookie 匹配
+	RuleUser    = "user"    // 用户 ID 白名单或用户 ID 哈希百分比
+	RulePercent = "percent" // 按请求随机百分比
+)
+
+// Config 灰度路由配置（独立文件，支持不重启热加载）
+type Config struct {
+	Services []ServiceConfig `json:",optional"`
+}
+
+// ServiceConfig 单个服务的版本分组与路由规则
+type ServiceConfig struct {
+	Name     string    // 与上游 Target 中的服务名一致，如 order-service
+	Versions []Version // 第一个版本视为稳定版本，目标版本不可用时回退到稳定版本
+	Rules    []Rule    `json:",optional"` // 按顺序匹配，命中第一条即返回；都未命中时按权重分配
+}
+
+// Version 服务版本
+type Version struct {
+	Name      string   // 版本名，如 v1、v2
+	Target    string   `json:",optional"` // 单个地址 host:port
+	Endpoints []string `json:",optional"` // 多个地址，与 Target 二选一
+	Weight    int      `json:",optional"` // 未命中规则的流量按权重分配
+}
+
+// Rule 路由规则
+type Rule struct {
+	Type    string   // header / cookie / user / percent
+	Name    string   `json:",optional"` // header / cookie 名称
+	Values  []string `json:",optional"` // 匹配值（header / cookie 为空表示只要存在即命中；user 为用户 ID 白名单）
+	Percent int      `json:",optional"` // user 规则按用户 ID 哈希取模，percent 规则按随机数，取值 0-100
+	Version string   // 命中后路由到的版本
+}
+
+// Addresses 返回版本的全部地址
+func (v Version) Addresses() []string {
+	if len(v.Endpoints) > 0 {
+		return v.Endpoints
+	}
+	if v.Target != "" {
+		return []string{v.Target}
+	}
+	return nil
+}
+
+// Validate 校验配置，热加载时校验失败会保留旧配置
+func (c *Config) Validate() error {
+	seen := make(map[string]struct{}, len(c.Services))
+	for _, svc := range c.Services {
+		if svc.Name == "" {
+			return fmt.Errorf("canary: service name is required")
+		}
+		if _, ok := seen[svc.Name]; ok {
+			return fmt.Errorf("canary: duplicate service %s", svc.Name)
+		}
+		seen[svc.Name] = struct{}{}
+
+		if len(svc.Versions) == 0 {
+			return fmt.Errorf("canary: service %s has no versions", svc.Name)
+		}
+		versions := make(map[string]struct{}, len(svc.Versions))
+		for _, v := range svc.Versions {
+			if v.Name == "" {
+				return fmt.Errorf("canary: service %s has a version without name", svc.Name)
+			}
+			if _, ok := versions[v.Name]; ok {
+				return fmt.Errorf("canary: service %s has duplicate version %s", svc.Name, v.Name)
+			}
+			if len(v.Addresses()) == 0 {
+				return fmt.Errorf("canary: %s/%s has no target", svc.Name, v.Name)
+			}
+			if v.Weight < 0 {
+				return fmt.Errorf("canary: %s/%s weight must not be negative", svc.Name, v.Name)
+			}
+			versions[v.Name] = struct{}{}
+		}
+
+		for i, r := range svc.Rules {
+			if _, ok := versions[r.Version]; !ok {
+				return fmt.Errorf("canary: %s rule #%d routes to unknown version %q", svc.Name, i+1, r.Version)
+			}
+			if r.Percent < 0 || r.Percent > 100 {
+				return fmt.Errorf("canary: %s rule #%d percent must be within 0-100", svc.Name, i+1)
+			}
+			switch r.Type {
+			case RuleHeader, RuleCookie:
+				if r.Name == "" {
+					return fmt.Errorf("canary: %s rule #%d (%s) requires name", svc.Name, i+1, r.Type)
+				}
+			case RuleUser, RulePercent:
+			default:
+				return fmt.Errorf("canary: %s rule #%d has unknown type %q", svc.Name, i+1, r.Type)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package canary
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme 灰度解析器的 scheme，上游 Target 写作 canary:///order-service
+const Scheme = "canary"
+
+// addrInfo 挂在地址属性上的版本信息，供负载均衡器按版本分组
+type addrInfo struct {
+	Service string
+	Version string
+}
+
+type addrInfoKey struct{}
+
+// Equal 实现 attributes 的比较约定
+func (a addrInfo) Equal(o any) bool {
+	other, ok := o.(addrInfo)
+	return ok && other == a
+}
+
+func getAddrInfo(addr resolver.Address) (addrInfo, bool) {
+	if addr.Attributes == nil {
+		return addrInfo{}, false
+	}
+	info, ok := addr.Attributes.Value(addrInfoKey{}).(addrInfo)
+	return info, ok
+}
+
+type resolverBuilder struct {
+	router *Router
+}
+
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r := &canaryResolver{
+		service: strings.TrimPrefix(target.Endpoint(), "/"),
+		router:  b.router,
+		cc:      cc,
+	}
+	r.unsubscribe = b.router.subscribe(r.update)
+	r.update()
+	return r, nil
+}
+
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+// canaryResolver 把服务所有版本的地址交给 ClientConn，配置热加载后重新推送
+type canaryResolver struct {
+	service     string
+	router      *Router
+	cc          resolver.ClientConn
+	unsubscribe func()
+}
+
+func (r *canaryResolver) update() {
+	var addrs []resolver.Address
+	for _, v := range r.router.Versions(r.service) {
+		for _, a := range v.Addresses() {
+			addrs = append(addrs, resolver.Address{
+				Addr:       a,
+				Attributes: attributes.New(addrInfoKey{}, addrInfo{Service: r.service, Version: v.Name}),
+			})
+		}
+	}
+	if len(addrs) == 0 {
+		r.cc.ReportError(errUnknownService(r.service))
+		return
+	}
+	_ = r.cc.UpdateState(resolver.State{Addresses: addrs})
+}
+
+func (r *canaryResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (r *canaryResolver) Close() {
+	r.unsubscribe()
+}
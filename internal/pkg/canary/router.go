@@ -0,0 +1,281 @@
+package canary
+
+import (
+	"context"
+	"hash/crc32"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Request 参与路由决策的请求信息，由网关中间件写入 context
+type Request struct {
+	Header   http.Header
+	UserID   string            // 未登录为空
+	Versions map[string]string // Resolve 预先选定的各服务版本，同一请求的所有调用和响应缓存都按它区分
+}
+
+type requestKey struct{}
+
+// WithRequest 把请求信息写入 context，gRPC 调用时由 canary 负载均衡器读取
+func WithRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// VersionKey 返回 context 中请求选定的版本组合（按服务名排序，如 "order-service=v1,product-service=v2"），
+// 未启用灰度时为空。供响应缓存等按版本区分数据的场景使用
+func VersionKey(ctx context.Context) string {
+	req := requestFromContext(ctx)
+	if req == nil || len(req.Versions) == 0 {
+		return ""
+	}
+	services := make([]string, 0, len(req.Versions))
+	for name := range req.Versions {
+		services = append(services, name)
+	}
+	slices.Sort(services)
+	parts := make([]string, 0, len(services))
+	for _, name := range services {
+		parts = append(parts, name+"="+req.Versions[name])
+	}
+	return strings.Join(parts, ",")
+}
+
+func requestFromContext(ctx context.Context) *Request {
+	if ctx == nil {
+		return nil
+	}
+	req, _ := ctx.Value(requestKey{}).(*Request)
+	return req
+}
+
+// Router 灰度路由器：持有当前生效的配置，配置文件变更时原子替换
+type Router struct {
+	cfg atomic.Pointer[Config]
+
+	mu        sync.Mutex
+	nextID    uint64
+	listeners map[uint64]func() // 地址变更回调（解析器注册）
+}
+
+// NewRouter 创建路由器
+func NewRouter(cfg *Config) (*Router, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	r := &Router{listeners: make(map[uint64]func())}
+	r.cfg.Store(cfg)
+	return r, nil
+}
+
+// LoadFile 从文件加载配置并创建路由器
+func LoadFile(path string) (*Router, error) {
+	var cfg Config
+	if err := conf.Load(path, &cfg); err != nil {
+		return nil, err
+	}
+	return NewRouter(&cfg)
+}
+
+// Update 替换配置（权重、规则、版本地址），校验失败时保留旧配置
+func (r *Router) Update(cfg *Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	r.cfg.Store(cfg)
+
+	r.mu.Lock()
+	var callbacks []func()
+	for _, fn := range r.listeners {
+		callbacks = append(callbacks, fn)
+	}
+	r.mu.Unlock()
+	for _, fn := range callbacks {
+		fn()
+	}
+	return nil
+}
+
+// Watch 定时检查配置文件修改时间，变更后热加载；ctx 取消时退出
+func (r *Router) Watch(ctx context.Context, path string, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	var lastMod time.Time
+	if fi, err := os.Stat(path); err == nil {
+		lastMod = fi.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil || !fi.ModTime().After(lastMod) {
+			continue
+		}
+		lastMod = fi.ModTime()
+
+		var cfg Config
+		if err := conf.Load(path, &cfg); err != nil {
+			logx.Errorf("canary: reload %s failed: %v", path, err)
+			continue
+		}
+		if err := r.Update(&cfg); err != nil {
+			logx.Errorf("canary: reload %s rejected, keep previous config: %v", path, err)
+			continue
+		}
+		logx.Infof("canary: reloaded %s", path)
+	}
+}
+
+// Resolve 为请求选定每个服务的版本并记录在 req.Versions 中。
+// 百分比和权重是随机的，先选定再调用，保证同一请求内各次调用、以及响应缓存的 key 使用同一组版本
+func (r *Router) Resolve(req *Request) {
+	cfg := r.cfg.Load()
+	req.Versions = make(map[string]string, len(cfg.Services))
+	for i := range cfg.Services {
+		req.Versions[cfg.Services[i].Name] = routeService(&cfg.Services[i], req)
+	}
+}
+
+// Route 计算请求应路由到的版本，已由 Resolve 选定时直接返回选定的版本
+func (r *Router) Route(service string, req *Request) string {
+	if req != nil {
+		if v, ok := req.Versions[service]; ok {
+			return v
+		}
+	}
+	svc := r.service(service)
+	if svc == nil {
+		return ""
+	}
+	return routeService(svc, req)
+}
+
+// routeService 按规则和权重为请求选择服务的版本
+func routeService(svc *ServiceConfig, req *Request) string {
+	if req != nil {
+		for _, rule := range svc.Rules {
+			if matchRule(rule, req) {
+				return rule.Version
+			}
+		}
+	}
+	return pickWeighted(svc.Versions)
+}
+
+// Stable 返回服务的稳定版本（第一个版本）
+func (r *Router) Stable(service string) string {
+	svc := r.service(service)
+	if svc == nil {
+		return ""
+	}
+	return svc.Versions[0].Name
+}
+
+// Versions 返回服务当前配置的全部版本
+func (r *Router) Versions(service string) []Version {
+	svc := r.service(service)
+	if svc == nil {
+		return nil
+	}
+	return svc.Versions
+}
+
+// subscribe 注册配置变更回调，返回取消函数
+func (r *Router) subscribe(fn func()) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := r.nextID
+	r.listeners[id] = fn
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.listeners, id)
+	}
+}
+
+func (r *Router) service(name string) *ServiceConfig {
+	cfg := r.cfg.Load()
+	for i := range cfg.Services {
+		if cfg.Services[i].Name == name {
+			return &cfg.Services[i]
+		}
+	}
+	return nil
+}
+
+// matchRule 判断请求是否命中规则
+func matchRule(rule Rule, req *Request) bool {
+	switch rule.Type {
+	case RuleHeader:
+		values, ok := req.Header[http.CanonicalHeaderKey(rule.Name)]
+		return ok && matchValues(rule.Values, values)
+	case RuleCookie:
+		c, err := (&http.Request{Header: req.Header}).Cookie(rule.Name)
+		return err == nil && matchValues(rule.Values, []string{c.Value})
+	case RuleUser:
+		if req.UserID == "" {
+			return false
+		}
+		if len(rule.Values) > 0 {
+			return slices.Contains(rule.Values, req.UserID)
+		}
+		// 只按用户 ID 哈希（不加服务名），同一用户在各服务上落到同一批次，便于整条链路一起灰度
+		return int(crc32.ChecksumIEEE([]byte(req.UserID))%100) < rule.Percent
+	case RulePercent:
+		return rand.IntN(100) < rule.Percent
+	}
+	return false
+}
+
+// matchValues expected 为空时只要求存在
+func matchValues(expected, actual []string) bool {
+	if len(expected) == 0 {
+		return len(actual) > 0
+	}
+	for _, v := range actual {
+		if slices.Contains(expected, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// pickWeighted 按权重随机选择版本，权重全为 0 时返回稳定版本
+func pickWeighted(versions []Version) string {
+	total := 0
+	for _, v := range versions {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return versions[0].Name
+	}
+	n := rand.IntN(total)
+	for _, v := range versions {
+		if n < v.Weight {
+			return v.Name
+		}
+		n -= v.Weight
+	}
+	return versions[0].Name
+}
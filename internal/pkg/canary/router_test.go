@@ -0,0 +1,120 @@
+package canary
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func newTestRouter(t *testing.T) *Router {
+	t.Helper()
+	r, err := NewRouter(&Config{Services: []ServiceConfig{{
+		Name: "order-service",
+		Versions: []Version{
+			{Name: "v1", Target: "127.0.0.1:8082", Weight: 100},
+			{Name: "v2", Target: "127.0.0.1:18082"},
+		},
+		Rules: []Rule{
+			{Type: RuleHeader, Name: "X-Canary", Values: []string{"v2"}, Version: "v2"},
+			{Type: RuleCookie, Name: "canary", Version: "v2"},
+			{Type: RuleUser, Values: []string{"10001"}, Version: "v2"},
+		},
+	}}})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	return r
+}
+
+func TestRouterRoute(t *testing.T) {
+	r := newTestRouter(t)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		userID string
+		want   string
+	}{
+		{name: "default weight", header: http.Header{}, want: "v1"},
+		{name: "header match", header: http.Header{"X-Canary": {"v2"}}, want: "v2"},
+		{name: "header mismatch", header: http.Header{"X-Canary": {"v3"}}, want: "v1"},
+		{name: "cookie present", header: http.Header{"Cookie": {"canary=1"}}, want: "v2"},
+		{name: "user whitelist", header: http.Header{}, userID: "10001", want: "v2"},
+		{name: "other user", header: http.Header{}, userID: "10002", want: "v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Route("order-service", &Request{Header: tt.header, UserID: tt.userID})
+			if got != tt.want {
+				t.Errorf("Route() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := r.Route("unknown-service", nil); got != "" {
+		t.Errorf("Route() for unknown service = %q, want empty", got)
+	}
+}
+
+func TestRouterUpdateRejectsInvalidConfig(t *testing.T) {
+	r := newTestRouter(t)
+
+	err := r.Update(&Config{Services: []ServiceConfig{{
+		Name:     "order-service",
+		Versions: []Version{{Name: "v1", Target: "127.0.0.1:8082"}},
+		Rules:    []Rule{{Type: RuleHeader, Name: "X-Canary", Version: "v3"}},
+	}}})
+	if err == nil {
+		t.Fatal("Update() expected error for unknown version")
+	}
+	if got := r.Stable("order-service"); got != "v1" || len(r.Versions("order-service")) != 2 {
+		t.Errorf("previous config should be kept, got stable=%s versions=%d", got, len(r.Versions("order-service")))
+	}
+}
+
+func TestUserHashIsStable(t *testing.T) {
+	rule := Rule{Type: RuleUser, Percent: 50, Version: "v2"}
+	req := &Request{Header: http.Header{}, UserID: "42"}
+	first := matchRule(rule, req)
+	for i := 0; i < 10; i++ {
+		if matchRule(rule, req) != first {
+			t.Fatal("user hash rule should be deterministic")
+		}
+	}
+}
+
+func TestResolvePinsVersionsForRequest(t *testing.T) {
+	r, err := NewRouter(&Config{Services: []ServiceConfig{
+		{
+			Name:     "order-service",
+			Versions: []Version{{Name: "v1", Target: "127.0.0.1:8082", Weight: 50}, {Name: "v2", Target: "127.0.0.1:18082", Weight: 50}},
+		},
+		{
+			Name:     "product-service",
+			Versions: []Version{{Name: "v1", Target: "127.0.0.1:8081"}, {Name: "v2", Target: "127.0.0.1:18081"}},
+			Rules:    []Rule{{Type: RuleHeader, Name: "X-Canary", Version: "v2"}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	req := &Request{Header: http.Header{"X-Canary": {"1"}}}
+	r.Resolve(req)
+	// 按权重随机的服务在同一请求内始终落到选定的版本
+	picked := req.Versions["order-service"]
+	for i := 0; i < 20; i++ {
+		if got := r.Route("order-service", req); got != picked {
+			t.Fatalf("Route() = %s after Resolve picked %s", got, picked)
+		}
+	}
+
+	ctx := WithRequest(context.Background(), req)
+	want := "order-service=" + picked + ",product-service=v2"
+	if got := VersionKey(ctx); got != want {
+		t.Errorf("VersionKey() = %q, want %q", got, want)
+	}
+	if got := VersionKey(context.Background()); got != "" {
+		t.Errorf("VersionKey() without canary = %q, want empty", got)
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	userv1 "ecommerce-system/api/user/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ApiKeyClient 开放平台 API Key 客户端（网关验签、上报调用记录）
+type ApiKeyClient struct {
+	conn    *grpc.ClientConn
+	client  userv1.ApiKeyServiceClient
+	timeout RpcConf
+}
+
+// NewApiKeyClient 创建 API Key 客户端
+func NewApiKeyClient(conf RpcConf) (*ApiKeyClient, error) {
+	conn, err := newConn(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dial api key service %s: %w", conf.Endpoint, err)
+	}
+	return &ApiKeyClient{
+		conn:    conn,
+		client:  userv1.NewApiKeyServiceClient(conn),
+		timeout: conf,
+	}, nil
+}
+
+// Close 关闭连接
+func (c *ApiKeyClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetCredential 获取验签凭证，Key 不存在时返回 (nil, nil)
+func (c *ApiKeyClient) GetCredential(ctx context.Context, keyID string) (*userv1.ApiKeyCredential, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.GetApiKeyCredential(ctx, &userv1.GetApiKeyCredentialRequest{KeyId: keyID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get api key credential key=%s: %w", keyID, err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("get api key credential key=%s: %s", keyID, resp.Message)
+	}
+	return resp.Data, nil
+}
+
+// ReportCalls 批量上报调用记录
+func (c *ApiKeyClient) ReportCalls(ctx context.Context, calls []*userv1.ApiKeyCall) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.ReportApiKeyCalls(ctx, &userv1.ReportApiKeyCallsRequest{Calls: calls})
+	if err != nil {
+		return fmt.Errorf("report api key calls n=%d: %w", len(calls), err)
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("report api key calls n=%d: %s", len(calls), resp.Message)
+	}
+	return nil
+}
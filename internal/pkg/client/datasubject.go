@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	privacyv1 "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// serviceTokenExpire 调用个人数据接口的服务令牌有效期（秒）
+const serviceTokenExpire = 60
+
+// DataSubjectClient 业务服务个人数据导出/删除接口客户端，各服务地址不同、契约相同
+type DataSubjectClient struct {
+	conn      *grpc.ClientConn
+	client    privacyv1.DataSubjectServiceClient
+	timeout   RpcConf
+	jwtSecret string
+}
+
+// NewDataSubjectClient 创建个人数据接口客户端。
+// 各业务服务按 rbac.MethodPermissions 校验这两个接口，每次调用用 jwtSecret 签发带 privacy:process 权限的短期服务令牌。
+func NewDataSubjectClient(conf RpcConf, jwtSecret string) (*DataSubjectClient, error) {
+	conn, err := newConn(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dial data subject service %s: %w", conf.Endpoint, err)
+	}
+	return &DataSubjectClient{
+		conn:      conn,
+		client:    privacyv1.NewDataSubjectServiceClient(conn),
+		timeout:   conf,
+		jwtSecret: jwtSecret,
+	}, nil
+}
+
+// Close 关闭连接
+func (c *DataSubjectClient) Close() error {
+	return c.conn.Close()
+}
+
+// Export 导出用户在该服务中的个人数据
+func (c *DataSubjectClient) Export(ctx context.Context, userID uint64, requestNo string) ([]*privacyv1.DataSection, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+	ctx, err := c.withServiceToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.ExportUserData(ctx, &privacyv1.ExportUserDataRequest{
+		UserId:    int64(userID),
+		RequestNo: requestNo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("export user data user=%d: %w", userID, err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("export user data user=%d: %s", userID, resp.Message)
+	}
+	return resp.Sections, nil
+}
+
+// Erase 删除或匿名化用户在该服务中的个人数据
+func (c *DataSubjectClient) Erase(ctx context.Context, userID uint64, requestNo string) ([]*privacyv1.ErasureResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+	ctx, err := c.withServiceToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.EraseUserData(ctx, &privacyv1.EraseUserDataRequest{
+		UserId:    int64(userID),
+		RequestNo: requestNo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erase user data user=%d: %w", userID, err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("erase user data user=%d: %s", userID, resp.Message)
+	}
+	return resp.Results, nil
+}
+
+// withServiceToken 在请求中附带只有 privacy:process 权限的服务令牌
+func (c *DataSubjectClient) withServiceToken(ctx context.Context) (context.Context, error) {
+	token, _, err := utils.GenerateAccessToken(utils.TokenSubject{
+		Username:    "user-service",
+		Permissions: []string{rbac.PermPrivacyProcess},
+	}, c.jwtSecret, serviceTokenExpire)
+	if err != nil {
+		return nil, fmt.Errorf("sign service token: %w", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), nil
+}
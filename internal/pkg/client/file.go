@@ -0,0 +1,132 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	filev1 "ecommerce-system/api/file/v1"
+
+	"google.golang.org/grpc"
+)
+
+// fileChunkSize 流式上传每个分片的大小，低于 gRPC 默认 4MB 的消息上限
+const fileChunkSize = 1 << 20
+
+// FileClient 文件服务客户端
+type FileClient struct {
+	conn    *grpc.ClientConn
+	client  filev1.FileServiceClient
+	timeout RpcConf
+}
+
+// NewFileClient 创建文件服务客户端
+func NewFileClient(conf RpcConf) (*FileClient, error) {
+	conn, err := newConn(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dial file service %s: %w", conf.Endpoint, err)
+	}
+	return &FileClient{
+		conn:    conn,
+		client:  filev1.NewFileServiceClient(conn),
+		timeout: conf,
+	}, nil
+}
+
+// Close 关闭连接
+func (c *FileClient) Close() error {
+	return c.conn.Close()
+}
+
+// Upload 流式上传文件，返回文件ID
+func (c *FileClient) Upload(ctx context.Context, data []byte, fileName, fileType, category string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	stream, err := c.client.UploadFileStream(ctx)
+	if err != nil {
+		return "", fmt.Errorf("upload file %s: %w", fileName, err)
+	}
+	err = stream.Send(&filev1.UploadFileChunk{Meta: &filev1.UploadFileMeta{
+		FileName: fileName,
+		FileType: fileType,
+		Category: category,
+		Size:     int64(len(data)),
+	}})
+	for off := 0; err == nil && off < len(data); off += fileChunkSize {
+		end := min(off+fileChunkSize, len(data))
+		err = stream.Send(&filev1.UploadFileChunk{Data: data[off:end]})
+	}
+	if err != nil {
+		return "", fmt.Errorf("upload file %s: %w", fileName, err)
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return "", fmt.Errorf("upload file %s: %w", fileName, err)
+	}
+	if resp.Code != 0 || resp.Data == nil {
+		return "", fmt.Errorf("upload file %s: %s", fileName, resp.Message)
+	}
+	return resp.Data.FileId, nil
+}
+
+// Download 流式下载文件，返回文件内容和存储文件名（含扩展名）；maxSize > 0 时超过该大小直接报错
+func (c *FileClient) Download(ctx context.Context, fileID string, maxSize int64) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	stream, err := c.client.DownloadFileStream(ctx, &filev1.DownloadFileRequest{FileId: fileID})
+	if err != nil {
+		return nil, "", fmt.Errorf("download file %s: %w", fileID, err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, "", fmt.Errorf("download file %s: %w", fileID, err)
+	}
+	info := first.GetInfo()
+	if info == nil {
+		return nil, "", fmt.Errorf("download file %s: missing file info", fileID)
+	}
+	if maxSize > 0 && info.FileSize > maxSize {
+		return nil, "", fmt.Errorf("download file %s: size %d exceeds limit %d", fileID, info.FileSize, maxSize)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(int(info.FileSize))
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("download file %s: %w", fileID, err)
+		}
+		buf.Write(chunk.GetData())
+		if maxSize > 0 && int64(buf.Len()) > maxSize {
+			return nil, "", fmt.Errorf("download file %s: size exceeds limit %d", fileID, maxSize)
+		}
+	}
+	return buf.Bytes(), info.FileName, nil
+}
+
+// GetFileURL 获取文件访问地址，私有文件返回绑定 userID 的签名 URL 和过期时间（Unix 秒）。
+// 走内部接口 SignFileURL，不校验文件归属，调用方需要自行确认 userID 有权访问该文件。
+func (c *FileClient) GetFileURL(ctx context.Context, fileID string, expireSeconds int64, userID uint64) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.SignFileURL(ctx, &filev1.SignFileURLRequest{
+		FileId:        fileID,
+		ExpireSeconds: expireSeconds,
+		UserId:        userID,
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("get file url %s: %w", fileID, err)
+	}
+	if resp.Code != 0 {
+		return "", 0, fmt.Errorf("get file url %s: %s", fileID, resp.Message)
+	}
+	return resp.FileUrl, resp.ExpiresAt, nil
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	messagev1 "ecommerce-system/api/message/v1"
+
+	"google.golang.org/grpc"
+)
+
+// MessageTypeSystem 站内消息类型：系统通知
+const MessageTypeSystem int32 = 1
+
+// MessageClient 消息服务客户端
+type MessageClient struct {
+	conn    *grpc.ClientConn
+	client  messagev1.MessageServiceClient
+	timeout RpcConf
+}
+
+// NewMessageClient 创建消息服务客户端
+func NewMessageClient(conf RpcConf) (*MessageClient, error) {
+	conn, err := newConn(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dial message service %s: %w", conf.Endpoint, err)
+	}
+	return &MessageClient{
+		conn:    conn,
+		client:  messagev1.NewMessageServiceClient(conn),
+		timeout: conf,
+	}, nil
+}
+
+// Close 关闭连接
+func (c *MessageClient) Close() error {
+	return c.conn.Close()
+}
+
+// SendMessage 给用户发送站内消息
+func (c *MessageClient) SendMessage(ctx context.Context, userID uint64, msgType int32, title, content, link string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.SendMessage(ctx, &messagev1.SendMessageRequest{
+		UserId:  int64(userID),
+		Type:    msgType,
+		Title:   title,
+		Content: content,
+		Link:    link,
+	})
+	if err != nil {
+		return fmt.Errorf("send message user=%d: %w", userID, err)
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("send message user=%d: %s", userID, resp.Message)
+	}
+	return nil
+}
@@ -109,3 +109,31 @@ func (c *OrderClient) GetOrder(ctx context.Context, orderID int64, orderNo strin
 	}
 	return resp.Data, nil
 }
+
+// ListOrderIDs 获取用户全部订单ID（物流服务处理个人数据时按订单查找物流单）
+func (c *OrderClient) ListOrderIDs(ctx context.Context, userID uint64) ([]uint64, error) {
+	const pageSize = 100
+	var ids []uint64
+	for page := int32(1); ; page++ {
+		callCtx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+		resp, err := c.client.ListOrders(callCtx, &orderv1.ListOrdersRequest{
+			UserId:   int64(userID),
+			Status:   -1,
+			Page:     page,
+			PageSize: pageSize,
+		})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("list orders user=%d: %w", userID, err)
+		}
+		if resp.Code != 0 || resp.Data == nil {
+			return nil, fmt.Errorf("list orders user=%d: %s", userID, resp.Message)
+		}
+		for _, o := range resp.Data.List {
+			ids = append(ids, uint64(o.Id))
+		}
+		if len(resp.Data.List) < pageSize {
+			return ids, nil
+		}
+	}
+}
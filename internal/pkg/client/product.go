@@ -63,3 +63,23 @@ func (c *ProductClient) GetProduct(ctx context.Context, productID int64) (*produ
 	}
 	return resp.Data, nil
 }
+
+// ListSkus 获取商品下的 SKU 列表（只取上架 SKU）
+func (c *ProductClient) ListSkus(ctx context.Context, productID int64) ([]*productv1.Sku, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.ListSkus(ctx, &productv1.ListSkusRequest{
+		ProductId: productID,
+		Status:    1,
+		Page:      1,
+		PageSize:  100,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list skus product=%d: %w", productID, err)
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+	return resp.Data.List, nil
+}
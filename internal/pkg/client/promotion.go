@@ -77,3 +77,21 @@ func (c *PromotionClient) UseCoupon(ctx context.Context, userID, userCouponID, o
 	}
 	return nil
 }
+
+// GetPromotionList 获取商品可用的促销活动
+func (c *PromotionClient) GetPromotionList(ctx context.Context, productID, categoryID int64) ([]*promotionv1.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.GetPromotionList(ctx, &promotionv1.GetPromotionListRequest{
+		ProductId:  productID,
+		CategoryId: categoryID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get promotion list product=%d: %w", productID, err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("get promotion list product=%d: %s", productID, resp.Message)
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	recommendv1 "ecommerce-system/api/recommend/v1"
+
+	"google.golang.org/grpc"
+)
+
+// RecommendClient 推荐服务客户端
+type RecommendClient struct {
+	conn    *grpc.ClientConn
+	client  recommendv1.RecommendServiceClient
+	timeout RpcConf
+}
+
+// NewRecommendClient 创建推荐服务客户端
+func NewRecommendClient(conf RpcConf) (*RecommendClient, error) {
+	conn, err := newConn(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dial recommend service %s: %w", conf.Endpoint, err)
+	}
+	return &RecommendClient{
+		conn:    conn,
+		client:  recommendv1.NewRecommendServiceClient(conn),
+		timeout: conf,
+	}, nil
+}
+
+// Close 关闭连接
+func (c *RecommendClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetSimilarProducts 获取相似商品
+func (c *RecommendClient) GetSimilarProducts(ctx context.Context, productID int64, limit int32) ([]*recommendv1.RecommendProduct, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.GetSimilarProducts(ctx, &recommendv1.GetSimilarProductsRequest{
+		ProductId: productID,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get similar products product=%d: %w", productID, err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("get similar products product=%d: %s", productID, resp.Message)
+	}
+	return resp.Data, nil
+}
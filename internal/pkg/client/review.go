@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	reviewv1 "ecommerce-system/api/review/v1"
+
+	"google.golang.org/grpc"
+)
+
+// ReviewClient 评价服务客户端
+type ReviewClient struct {
+	conn    *grpc.ClientConn
+	client  reviewv1.ReviewServiceClient
+	timeout RpcConf
+}
+
+// NewReviewClient 创建评价服务客户端
+func NewReviewClient(conf RpcConf) (*ReviewClient, error) {
+	conn, err := newConn(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dial review service %s: %w", conf.Endpoint, err)
+	}
+	return &ReviewClient{
+		conn:    conn,
+		client:  reviewv1.NewReviewServiceClient(conn),
+		timeout: conf,
+	}, nil
+}
+
+// Close 关闭连接
+func (c *ReviewClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetReviewStats 获取商品评价统计
+func (c *ReviewClient) GetReviewStats(ctx context.Context, productID int64) (*reviewv1.ReviewStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.GetReviewStats(ctx, &reviewv1.GetReviewStatsRequest{ProductId: productID})
+	if err != nil {
+		return nil, fmt.Errorf("get review stats product=%d: %w", productID, err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("get review stats product=%d: %s", productID, resp.Message)
+	}
+	return resp.Data, nil
+}
+
+// GetProductReviews 分页获取商品评价，返回 (评价列表, 总数, error)
+func (c *ReviewClient) GetProductReviews(ctx context.Context, productID int64, page, pageSize int32) ([]*reviewv1.Review, int32, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.GetProductReviews(ctx, &reviewv1.GetProductReviewsRequest{
+		ProductId: productID,
+		Page:      page,
+		PageSize:  pageSize,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("get product reviews product=%d: %w", productID, err)
+	}
+	if resp.Code != 0 {
+		return nil, 0, fmt.Errorf("get product reviews product=%d: %s", productID, resp.Message)
+	}
+	return resp.Data, resp.Total, nil
+}
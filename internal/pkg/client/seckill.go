@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	seckillv1 "ecommerce-system/api/seckill/v1"
+
+	"google.golang.org/grpc"
+)
+
+// SeckillClient 秒杀服务客户端
+type SeckillClient struct {
+	conn    *grpc.ClientConn
+	client  seckillv1.SeckillServiceClient
+	timeout RpcConf
+}
+
+// NewSeckillClient 创建秒杀服务客户端
+func NewSeckillClient(conf RpcConf) (*SeckillClient, error) {
+	conn, err := newConn(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dial seckill service %s: %w", conf.Endpoint, err)
+	}
+	return &SeckillClient{
+		conn:    conn,
+		client:  seckillv1.NewSeckillServiceClient(conn),
+		timeout: conf,
+	}, nil
+}
+
+// Close 关闭连接
+func (c *SeckillClient) Close() error {
+	return c.conn.Close()
+}
+
+// ListActiveActivities 获取进行中的秒杀活动（最多 pageSize 条）
+func (c *SeckillClient) ListActiveActivities(ctx context.Context, pageSize int32) ([]*seckillv1.SeckillActivity, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.ListSeckillActivities(ctx, &seckillv1.ListSeckillActivitiesRequest{
+		Page:     1,
+		PageSize: pageSize,
+		Status:   1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list seckill activities: %w", err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("list seckill activities: %s", resp.Message)
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+	return resp.Data.List, nil
+}
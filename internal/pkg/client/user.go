@@ -68,3 +68,27 @@ func (c *UserClient) GetAddressByID(ctx context.Context, userID, addressID int64
 	// 没有默认地址，返回第一个
 	return resp.Data[0], nil
 }
+
+// GetMemberBenefits 获取用户会员等级和权益
+func (c *UserClient) GetMemberBenefits(ctx context.Context, userID int64) (*userv1.MemberBenefits, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.GetMemberBenefits(ctx, &userv1.GetMemberBenefitsRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("get member benefits: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// UseMemberFreeShipping 为订单占用一次会员包邮次数，返回是否包邮
+func (c *UserClient) UseMemberFreeShipping(ctx context.Context, userID int64, orderNo string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout.timeout())
+	defer cancel()
+
+	resp, err := c.client.UseMemberFreeShipping(ctx, &userv1.UseMemberFreeShippingRequest{UserId: userID, OrderNo: orderNo})
+	if err != nil {
+		return false, fmt.Errorf("use member free shipping: %w", err)
+	}
+	return resp.Granted, nil
+}
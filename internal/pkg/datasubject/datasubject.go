@@ -0,0 +1,122 @@
+// Package datasubject 个人数据请求（数据导出、注销删除）的服务端适配。
+// 各业务服务实现 Handler，用 NewServer 包装后注册为 privacy.v1.DataSubjectService，
+// 用户服务作为编排方逐个调用并汇总进度。
+package datasubject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "ecommerce-system/api/privacy/v1"
+)
+
+// ErasedName 匿名化后替换姓名、昵称等字段的占位文字
+const ErasedName = "已注销用户"
+
+// Section 一类个人数据，Records 为可 JSON 编码的记录切片
+type Section struct {
+	Name        string
+	Description string
+	Records     interface{}
+	Count       int
+}
+
+// Result 一类数据的删除结果
+type Result struct {
+	Name       string
+	Deleted    int64
+	Anonymized int64
+	Note       string // 保留原因等说明
+}
+
+// Handler 业务服务实现的个人数据导出和删除，Erase 必须可重复执行
+type Handler interface {
+	Export(ctx context.Context, userID uint64) ([]Section, error)
+	Erase(ctx context.Context, userID uint64) ([]Result, error)
+}
+
+// server DataSubjectService 的通用实现
+type server struct {
+	v1.UnimplementedDataSubjectServiceServer
+	service string
+	handler Handler
+}
+
+// NewServer 包装 Handler 为 gRPC 服务，service 为响应中的服务名
+func NewServer(service string, handler Handler) v1.DataSubjectServiceServer {
+	return &server{service: service, handler: handler}
+}
+
+// ExportUserData 导出个人数据
+func (s *server) ExportUserData(ctx context.Context, req *v1.ExportUserDataRequest) (*v1.ExportUserDataResponse, error) {
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id 不能为空")
+	}
+	sections, err := s.handler.Export(ctx, uint64(req.UserId))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s 导出个人数据失败: %v", s.service, err)
+	}
+	out, err := EncodeSections(sections)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.ExportUserDataResponse{
+		Code:     0,
+		Message:  "成功",
+		Service:  s.service,
+		Sections: out,
+	}, nil
+}
+
+// EraseUserData 删除个人数据
+func (s *server) EraseUserData(ctx context.Context, req *v1.EraseUserDataRequest) (*v1.EraseUserDataResponse, error) {
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id 不能为空")
+	}
+	results, err := s.handler.Erase(ctx, uint64(req.UserId))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s 删除个人数据失败: %v", s.service, err)
+	}
+	return &v1.EraseUserDataResponse{
+		Code:    0,
+		Message: "成功",
+		Service: s.service,
+		Results: EncodeResults(results),
+	}, nil
+}
+
+// EncodeSections 把记录编码为 JSON，供导出响应和本地导出共用
+func EncodeSections(sections []Section) ([]*v1.DataSection, error) {
+	out := make([]*v1.DataSection, 0, len(sections))
+	for _, sec := range sections {
+		data, err := json.MarshalIndent(sec.Records, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("编码 %s 失败: %w", sec.Name, err)
+		}
+		out = append(out, &v1.DataSection{
+			Name:        sec.Name,
+			Description: sec.Description,
+			Count:       int32(sec.Count),
+			Data:        data,
+		})
+	}
+	return out, nil
+}
+
+// EncodeResults 转换删除结果
+func EncodeResults(results []Result) []*v1.ErasureResult {
+	out := make([]*v1.ErasureResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, &v1.ErasureResult{
+			Name:       r.Name,
+			Deleted:    int32(r.Deleted),
+			Anonymized: int32(r.Anonymized),
+			Note:       r.Note,
+		})
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+package datasubject
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	v1 "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/middleware"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+type fakeHandler struct {
+	exported, erased uint64
+}
+
+func (h *fakeHandler) Export(ctx context.Context, userID uint64) ([]Section, error) {
+	h.exported = userID
+	return []Section{{Name: "orders", Description: "订单", Records: []map[string]int{{"id": 1}}, Count: 1}}, nil
+}
+
+func (h *fakeHandler) Erase(ctx context.Context, userID uint64) ([]Result, error) {
+	h.erased = userID
+	return []Result{{Name: "orders", Anonymized: 1, Note: "交易记录保留"}}, nil
+}
+
+func TestServerRejectsMissingUser(t *testing.T) {
+	h := &fakeHandler{}
+	s := NewServer("order-service", h)
+
+	// user_id 为 0 时业务查询可能不带用户条件，必须在进入 Handler 前拒绝
+	if _, err := s.ExportUserData(context.Background(), &v1.ExportUserDataRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("export without user: expected InvalidArgument, got %v", err)
+	}
+	if _, err := s.EraseUserData(context.Background(), &v1.EraseUserDataRequest{UserId: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("erase without user: expected InvalidArgument, got %v", err)
+	}
+	if h.exported != 0 || h.erased != 0 {
+		t.Fatalf("handler must not be called: %+v", h)
+	}
+}
+
+func TestServerEncodesSections(t *testing.T) {
+	h := &fakeHandler{}
+	s := NewServer("order-service", h)
+
+	resp, err := s.ExportUserData(context.Background(), &v1.ExportUserDataRequest{UserId: 7})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if h.exported != 7 || resp.Service != "order-service" || len(resp.Sections) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	var records []map[string]int
+	if err := json.Unmarshal(resp.Sections[0].Data, &records); err != nil || len(records) != 1 || records[0]["id"] != 1 {
+		t.Fatalf("unexpected section data %s: %v", resp.Sections[0].Data, err)
+	}
+
+	erased, err := s.EraseUserData(context.Background(), &v1.EraseUserDataRequest{UserId: 7})
+	if err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+	if h.erased != 7 || erased.Results[0].Anonymized != 1 || erased.Results[0].Note == "" {
+		t.Fatalf("unexpected erase response: %+v", erased)
+	}
+}
+
+func TestServerRequiresServiceToken(t *testing.T) {
+	const secret = "datasubject-test-secret"
+	h := &fakeHandler{}
+	// 与各业务服务的 main 一样，按 rbac.MethodPermissions 校验
+	srv := grpc.NewServer(grpc.UnaryInterceptor(middleware.RequirePermissionInterceptor(secret, nil, rbac.MethodPermissions)))
+	v1.RegisterDataSubjectServiceServer(srv, NewServer("order-service", h))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	raw := v1.NewDataSubjectServiceClient(conn)
+	ctx := context.Background()
+
+	// 未登录：导出和删除都被拒绝
+	if _, err := raw.ExportUserData(ctx, &v1.ExportUserDataRequest{UserId: 7}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("export without token: expected Unauthenticated, got %v", err)
+	}
+	if _, err := raw.EraseUserData(ctx, &v1.EraseUserDataRequest{UserId: 7}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("erase without token: expected Unauthenticated, got %v", err)
+	}
+	// 普通用户的令牌没有 privacy:process 权限，不能处理任何人的数据
+	userToken, err := utils.GenerateToken(7, "alice", secret, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+userToken)
+	if _, err := raw.EraseUserData(userCtx, &v1.EraseUserDataRequest{UserId: 8}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("erase with user token: expected PermissionDenied, got %v", err)
+	}
+	if h.exported != 0 || h.erased != 0 {
+		t.Fatalf("handler must not be called: %+v", h)
+	}
+
+	// user-service 的客户端签发服务令牌后可以调用；密钥不一致时被拒绝
+	other, err := client.NewDataSubjectClient(client.RpcConf{Endpoint: lis.Addr().String()}, "other-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = other.Close() })
+	if _, err := other.Erase(ctx, 7, "DR1"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("erase with foreign secret: expected Unauthenticated, got %v", err)
+	}
+	orchestrator, err := client.NewDataSubjectClient(client.RpcConf{Endpoint: lis.Addr().String()}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = orchestrator.Close() })
+	if _, err := orchestrator.Export(ctx, 7, "DR1"); err != nil {
+		t.Fatalf("export with service token: %v", err)
+	}
+	if _, err := orchestrator.Erase(ctx, 7, "DR1"); err != nil {
+		t.Fatalf("erase with service token: %v", err)
+	}
+	if h.exported != 7 || h.erased != 7 {
+		t.Fatalf("handler not called: %+v", h)
+	}
+}
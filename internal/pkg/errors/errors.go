@@ -31,6 +31,9 @@ const (
 	CodeTokenInvalid      = 2004
 	CodeUserDisabled      = 2005
 	CodeVerifyCodeError   = 2006
+	CodeCaptchaRequired   = 2007
+	CodeAccountLocked     = 2008
+	CodeMFACodeError      = 2009
 
 	// 商品服务错误码 3000-3999
 	CodeProductNotFound  = 3000
@@ -158,6 +161,9 @@ func defaultMessage(code int) string {
 		CodeTokenInvalid:      "Token无效",
 		CodeUserDisabled:      "用户已被禁用",
 		CodeVerifyCodeError:   "验证码错误",
+		CodeCaptchaRequired:   "请完成人机验证",
+		CodeAccountLocked:     "账号已被临时锁定",
+		CodeMFACodeError:      "两步验证码错误",
 
 		CodeProductNotFound:  "商品不存在",
 		CodeProductOffline:   "商品已下架",
@@ -233,7 +239,7 @@ func bizCodeToGRPC(code int) codes.Code {
 		CodeAddressNotFound:
 		return codes.NotFound
 
-	case CodeInvalidParam:
+	case CodeInvalidParam, CodePasswordError, CodeMFACodeError:
 		return codes.InvalidArgument
 
 	case CodeUnauthorized, CodeTokenExpired, CodeTokenInvalid:
@@ -249,7 +255,7 @@ func bizCodeToGRPC(code int) codes.Code {
 		CodeSeckillDuplicate:
 		return codes.AlreadyExists
 
-	case CodeTooManyRequests:
+	case CodeTooManyRequests, CodeAccountLocked:
 		return codes.ResourceExhausted
 
 	case CodeTimeout:
@@ -0,0 +1,97 @@
+// Package geoip 根据 IP 查询粗略地理位置（国家、省、市），数据来自本地 MaxMind 格式的数据库文件
+// （GeoLite2-City / GeoLite2-Country 或兼容格式），不调用外部接口。
+// 未配置数据库文件时 Reader 为 nil，查询返回空字符串，调用方不需要判断。
+package geoip
+
+import (
+	"net"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// 名称优先使用中文，没有中文时使用英文
+var languages = []string{"zh-CN", "en"}
+
+// Reader GeoIP 数据库，可并发使用
+type Reader struct {
+	db       *geoip2.Reader
+	cityData bool // City 库能查到省市，Country 库只有国家
+}
+
+// Open 打开数据库文件
+func Open(path string) (*Reader, error) {
+	db, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		db:       db,
+		cityData: strings.Contains(db.Metadata().DatabaseType, "City"),
+	}, nil
+}
+
+// Close 关闭数据库文件
+func (r *Reader) Close() error {
+	if r == nil {
+		return nil
+	}
+	return r.db.Close()
+}
+
+// Lookup 查询 IP 所在地，格式为“国家 省 市”，缺少的部分省略；
+// 内网和本机地址返回“局域网”，无法解析或查不到时返回空字符串
+func (r *Reader) Lookup(ip string) string {
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return ""
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() {
+		return "局域网"
+	}
+	if r == nil {
+		return ""
+	}
+
+	var parts []string
+	if r.cityData {
+		record, err := r.db.City(addr)
+		if err != nil {
+			return ""
+		}
+		parts = append(parts, localized(record.Country.Names))
+		if len(record.Subdivisions) > 0 {
+			parts = append(parts, localized(record.Subdivisions[0].Names))
+		}
+		parts = append(parts, localized(record.City.Names))
+	} else {
+		record, err := r.db.Country(addr)
+		if err != nil {
+			return ""
+		}
+		parts = append(parts, localized(record.Country.Names))
+	}
+	return joinPlace(parts)
+}
+
+// localized 按 languages 顺序取名称
+func localized(names map[string]string) string {
+	for _, lang := range languages {
+		if name := names[lang]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// joinPlace 拼接地名，跳过空值和与上一级相同的名称（直辖市的省、市同名）
+func joinPlace(parts []string) string {
+	var out []string
+	for _, p := range parts {
+		if p == "" || (len(out) > 0 && out[len(out)-1] == p) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,28 @@
+package geoip
+
+import "testing"
+
+func TestLookupWithoutDatabase(t *testing.T) {
+	var r *Reader
+	cases := map[string]string{
+		"127.0.0.1":   "局域网",
+		"192.168.1.8": "局域网",
+		"::1":         "局域网",
+		"8.8.8.8":     "", // 未配置数据库时查不到公网地址
+		"not-an-ip":   "",
+	}
+	for ip, want := range cases {
+		if got := r.Lookup(ip); got != want {
+			t.Errorf("Lookup(%q) = %q, want %q", ip, got, want)
+		}
+	}
+}
+
+func TestJoinPlace(t *testing.T) {
+	if got := joinPlace([]string{"中国", "北京市", "北京市"}); got != "中国 北京市" {
+		t.Fatalf("unexpected %q", got)
+	}
+	if got := joinPlace([]string{"美国", "", "芝加哥"}); got != "美国 芝加哥" {
+		t.Fatalf("unexpected %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+// Package httpcache 网关 GET 响应缓存：按路由配置 TTL，缓存 key 由路径、规范化后的查询参数、Vary 请求头和灰度版本组成，
+// 条目保存在 Redis 中供多个网关实例共享，商品数据变更（outbox 事件）时按路由整体清除。
+package httpcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/cache"
+)
+
+// Route 单条缓存路由配置
+type Route struct {
+	Name    string   // 路由名，用于 key 前缀和按路由清除
+	Path    string   // 路由路径，支持 :param 段，如 /api/v1/products/:id
+	TTL     int64    `json:",default=60"` // 网关缓存时长（秒），同时作为 Cache-Control max-age
+	SMaxAge int64    `json:",optional"`   // 给 CDN 的 s-maxage（秒），CDN 收不到清除事件，建议小于 TTL
+	Vary    []string `json:",optional"`   // 参与缓存 key 的请求头，并写入响应 Vary 头
+	PurgeOn []string `json:",optional"`   // 收到这些事件类型时清除该路由的全部条目
+}
+
+// Match 判断请求路径是否命中该路由
+func (r *Route) Match(path string) bool {
+	return MatchPath(r.Path, path)
+}
+
+// Entry 缓存条目
+type Entry struct {
+	StatusCode   int               `json:"status_code"`
+	Header       map[string]string `json:"header,omitempty"`
+	Body         []byte            `json:"body"`
+	ETag         string            `json:"etag"`
+	LastModified int64             `json:"last_modified"` // 写入缓存的时间（Unix 秒）
+}
+
+// Store 缓存存储
+type Store struct {
+	client *redis.Client
+	ops    *cache.CacheOperations
+}
+
+// NewStore 创建缓存存储
+func NewStore(client *redis.Client) *Store {
+	return &Store{client: client, ops: cache.NewCacheOperations(client)}
+}
+
+// BuildKey 构建缓存 key：前缀 + 路由名 + 请求摘要。
+// variant 为请求头以外影响响应内容的维度（如网关选定的灰度版本），为空表示没有
+func BuildKey(route string, r *http.Request, vary []string, variant string) string {
+	h := sha256.New()
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	// url.Values.Encode 按参数名排序，参数顺序不同的请求共用同一个条目
+	h.Write([]byte(r.URL.Query().Encode()))
+	for _, name := range vary {
+		h.Write([]byte{0})
+		h.Write([]byte(strings.ToLower(name) + "=" + r.Header.Get(name)))
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(variant))
+	return cache.BuildKey(cache.KeyPrefixHTTPCache, route, hex.EncodeToString(h.Sum(nil)[:16]))
+}
+
+// Get 读取缓存条目，未命中返回 (nil, nil)
+func (s *Store) Get(ctx context.Context, key string) (*Entry, error) {
+	var entry Entry
+	if err := s.ops.GetJSON(ctx, key, &entry); err != nil {
+		if cache.IsNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Set 写入缓存条目
+func (s *Store) Set(ctx context.Context, key string, entry *Entry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, data, ttl).Err()
+}
+
+// PurgeRoute 清除某个路由的全部缓存条目
+func (s *Store) PurgeRoute(ctx context.Context, route string) error {
+	return s.ops.DeletePattern(ctx, cache.BuildKey(cache.KeyPrefixHTTPCache, route, "*"))
+}
+
+// ETag 由响应体生成强 ETag
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// MatchETag 判断 If-None-Match 是否命中（支持多个值、弱校验前缀和 *）
+func MatchETag(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, v := range strings.Split(ifNoneMatch, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPath 路径匹配，pattern 中以 : 开头的段匹配任意单个段
+func MatchPath(pattern, path string) bool {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	xs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(ps) != len(xs) {
+		return false
+	}
+	return slices.EqualFunc(ps, xs, func(p, x string) bool {
+		if strings.HasPrefix(p, ":") {
+			return x != ""
+		}
+		return p == x
+	})
+}
@@ -0,0 +1,183 @@
+// Package idempotency 基于 Redis 的幂等键存储。
+//
+// 客户端为一次"业务意图"生成唯一的 Idempotency-Key，重试时复用同一个 key：
+//   - 首次请求：占位为处理中（processing），处理完成后写入最终响应
+//   - 重复请求：指纹一致则回放已保存的响应；指纹不一致说明 key 被挪用，直接拒绝
+//   - 首次请求仍在处理中：返回 ErrInProgress，由调用方转换为 409
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+// HeaderKey 幂等键请求头
+const HeaderKey = "Idempotency-Key"
+
+// MetadataKey 幂等键在 gRPC metadata 中的名称（metadata key 统一小写）
+const MetadataKey = "idempotency-key"
+
+// 记录状态
+const (
+	StatusProcessing = 0
+	StatusCompleted  = 1
+)
+
+var (
+	// ErrInProgress 同一个 key 的首个请求仍在处理中
+	ErrInProgress = errors.New("idempotency: request with this key is still in progress")
+	// ErrKeyReused 同一个 key 被用于不同的请求内容
+	ErrKeyReused = errors.New("idempotency: key reused with different request")
+)
+
+// Record 幂等记录（JSON 存储在 Redis）
+type Record struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status"`
+	StatusCode  int               `json:"status_code,omitempty"` // HTTP 状态码，gRPC 场景为 0
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+	CreatedAt   int64             `json:"created_at"`
+}
+
+// Store 幂等键存储
+type Store struct {
+	client  *redis.Client
+	ttl     time.Duration // 最终响应保存时长
+	lockTTL time.Duration // 处理中占位的最长时长，防止进程崩溃后 key 永久卡死
+}
+
+// NewStore 创建幂等键存储，ttl/lockTTL <= 0 时分别使用 24h / 30s
+func NewStore(client *redis.Client, ttl, lockTTL time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	if lockTTL <= 0 {
+		lockTTL = 30 * time.Second
+	}
+	return &Store{client: client, ttl: ttl, lockTTL: lockTTL}
+}
+
+// BuildKey 构建 Redis key（前缀 + scope + 客户端 key）
+// scope 用于隔离不同用户/接口，避免不同调用方的 key 互相碰撞。
+func BuildKey(scope, key string) string {
+	return cache.BuildKey(cache.KeyPrefixIdempotency, scope, key)
+}
+
+// Scope 按调用方身份计算作用域，jwtSecret 用于校验 Bearer 令牌：
+//   - 开放平台请求（网关以 Key 所属用户签发的内部令牌）按 API Key 隔离
+//   - 普通用户按令牌中的用户 ID 隔离，访问令牌刷新后重试仍落在同一作用域
+//   - 未登录请求共享 anon 作用域
+//
+// 令牌无法校验（过期、伪造或未配置 jwtSecret）时按原始令牌隔离，不与任何用户共享作用域。
+func Scope(authorization, jwtSecret string) string {
+	if authorization == "" {
+		return "anon"
+	}
+	if jwtSecret != "" {
+		token := strings.TrimPrefix(authorization, "Bearer ")
+		if claims, err := utils.ParseToken(token, jwtSecret); err == nil {
+			return ClaimsScope(claims)
+		}
+	}
+	sum := sha256.Sum256([]byte(authorization))
+	return "t:" + hex.EncodeToString(sum[:8])
+}
+
+// ClaimsScope 按已校验的令牌声明计算作用域，见 Scope
+func ClaimsScope(claims *utils.JWTClaims) string {
+	if keyID, ok := strings.CutPrefix(claims.Username, apikey.OwnerUsernamePrefix); ok && keyID != "" {
+		return "ak:" + keyID
+	}
+	return fmt.Sprintf("u:%d", claims.UserID)
+}
+
+// Fingerprint 计算请求指纹（各部分依次写入 sha256，部分之间用 0 字节分隔）
+func Fingerprint(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// beginScript 原子地"占位或读取"：
+// key 不存在时写入 processing 记录并返回 nil；否则返回已存在的记录
+var beginScript = redis.NewScript(`
+local v = redis.call("GET", KEYS[1])
+if v then
+	return v
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return false
+`)
+
+// Begin 开始处理一个幂等请求。
+//   - 返回 (nil, nil)：首次请求，已占位，调用方执行业务后必须调用 Complete 或 Release
+//   - 返回 (record, nil)：已有完成的记录，调用方直接回放
+//   - 返回 ErrInProgress / ErrKeyReused：调用方拒绝请求
+func (s *Store) Begin(ctx context.Context, key, fingerprint string) (*Record, error) {
+	placeholder, err := json.Marshal(&Record{
+		Fingerprint: fingerprint,
+		Status:      StatusProcessing,
+		CreatedAt:   time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := beginScript.Run(ctx, s.client, []string{key}, string(placeholder), s.lockTTL.Milliseconds()).Result()
+	if err != nil {
+		if cache.IsNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	raw, ok := res.(string)
+	if !ok {
+		return nil, fmt.Errorf("idempotency: unexpected script result %T", res)
+	}
+	var existing Record
+	if err := json.Unmarshal([]byte(raw), &existing); err != nil {
+		return nil, err
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrKeyReused
+	}
+	if existing.Status != StatusCompleted {
+		return nil, ErrInProgress
+	}
+	return &existing, nil
+}
+
+// Complete 保存最终响应，后续同 key 请求将直接回放
+func (s *Store) Complete(ctx context.Context, key string, rec *Record) error {
+	rec.Status = StatusCompleted
+	if rec.CreatedAt == 0 {
+		rec.CreatedAt = time.Now().Unix()
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, data, s.ttl).Err()
+}
+
+// Release 释放占位（业务失败且允许客户端重试时调用）
+func (s *Store) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
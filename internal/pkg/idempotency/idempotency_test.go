@@ -0,0 +1,57 @@
+package idempotency
+
+import (
+	"strings"
+	"testing"
+
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+const testSecret = "test-secret"
+
+func TestScopeStableAcrossTokenRefresh(t *testing.T) {
+	first, err := utils.GenerateToken(7, "alice", testSecret, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := utils.GenerateToken(7, "alice", testSecret, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == refreshed {
+		t.Fatal("expected distinct access tokens")
+	}
+
+	a := Scope("Bearer "+first, testSecret)
+	b := Scope("Bearer "+refreshed, testSecret)
+	if a != "u:7" || b != a {
+		t.Fatalf("scope should follow user id across refresh, got %q and %q", a, b)
+	}
+
+	other, _ := utils.GenerateToken(8, "bob", testSecret, 900)
+	if Scope("Bearer "+other, testSecret) == a {
+		t.Fatal("different users must not share a scope")
+	}
+}
+
+func TestScopeApiKeyAndUnverified(t *testing.T) {
+	token, _ := utils.GenerateToken(7, apikey.OwnerUsernamePrefix+"ak_123", testSecret, 600)
+	if got := Scope("Bearer "+token, testSecret); got != "ak:ak_123" {
+		t.Fatalf("api key scope = %q", got)
+	}
+
+	if got := Scope("", testSecret); got != "anon" {
+		t.Fatalf("anonymous scope = %q", got)
+	}
+
+	// 签名不对的令牌不能冒用用户作用域
+	forged, _ := utils.GenerateToken(7, "alice", "other-secret", 900)
+	got := Scope("Bearer "+forged, testSecret)
+	if !strings.HasPrefix(got, "t:") || got == Scope("Bearer "+token, testSecret) {
+		t.Fatalf("unverified token scope = %q", got)
+	}
+	if Scope("Bearer "+forged, "") != got {
+		t.Fatal("unverified token scope should not depend on secret configuration")
+	}
+}
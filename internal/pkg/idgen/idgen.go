@@ -43,6 +43,11 @@ func (g *Generator) LogisticsNo(ctx context.Context) string {
 	return g.generate(ctx, "idgen:logistics:", "LGS")
 }
 
+// DataRequestNo 生成个人数据请求编号，格式：DSR + yyyyMMdd + 8位序号
+func (g *Generator) DataRequestNo(ctx context.Context) string {
+	return g.generate(ctx, "idgen:datarequest:", "DSR")
+}
+
 // generate 通用生成逻辑
 func (g *Generator) generate(ctx context.Context, keyPrefix, bizPrefix string) string {
 	date := time.Now().Format("20060102")
@@ -0,0 +1,171 @@
+// Package loginguard 登录防暴力破解。按账号和 IP 统计窗口期内的失败次数：
+//
+//   - 账号失败达到 CaptchaAfter 次、或 IP 失败达到 IPCaptchaAfter 次后要求人机验证
+//   - 账号失败达到 DelayAfter 次后，每次失败后需要等待 2^(n-DelayAfter) 秒（不超过 MaxDelay）才能再试
+//   - 账号失败达到 LockAfter 次后锁定 LockDuration；IP 失败达到 IPLockAfter 次后在窗口期内拒绝该 IP
+//
+// 登录成功清零账号计数（IP 计数保留到窗口期结束）。
+package loginguard
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/cache"
+)
+
+// Config 防暴力破解配置
+type Config struct {
+	Window         int64 `json:",default=900"` // 失败计数窗口（秒）
+	CaptchaAfter   int   `json:",default=3"`   // 账号失败多少次后要求人机验证
+	DelayAfter     int   `json:",default=5"`   // 账号失败多少次后开始递增等待
+	MaxDelay       int64 `json:",default=60"`  // 最长等待（秒）
+	LockAfter      int   `json:",default=10"`  // 账号失败多少次后锁定
+	LockDuration   int64 `json:",default=900"` // 锁定时长（秒）
+	IPCaptchaAfter int   `json:",default=10"`  // IP 失败多少次后要求人机验证
+	IPLockAfter    int   `json:",default=50"`  // IP 失败多少次后拒绝该 IP
+}
+
+// Status 登录前检查结果
+type Status struct {
+	Locked          bool          // 账号或 IP 被锁定
+	RetryAfter      time.Duration // 锁定剩余时间或递增等待剩余时间，0 表示可以立即尝试
+	CaptchaRequired bool
+}
+
+// Guard 登录防暴力破解
+type Guard struct {
+	client *redis.Client
+	conf   Config
+}
+
+// NewGuard 创建登录防护，未配置的项使用默认值
+func NewGuard(client *redis.Client, c Config) *Guard {
+	def := func(v *int, d int) {
+		if *v <= 0 {
+			*v = d
+		}
+	}
+	def64 := func(v *int64, d int64) {
+		if *v <= 0 {
+			*v = d
+		}
+	}
+	def64(&c.Window, 900)
+	def(&c.CaptchaAfter, 3)
+	def(&c.DelayAfter, 5)
+	def64(&c.MaxDelay, 60)
+	def(&c.LockAfter, 10)
+	def64(&c.LockDuration, 900)
+	def(&c.IPCaptchaAfter, 10)
+	def(&c.IPLockAfter, 50)
+	return &Guard{client: client, conf: c}
+}
+
+// Check 登录前检查账号和 IP 的状态；ip 为空时只检查账号
+func (g *Guard) Check(ctx context.Context, account, ip string) (*Status, error) {
+	pipe := g.client.Pipeline()
+	lockTTL := pipe.TTL(ctx, lockKey(account))
+	fails := pipe.HGetAll(ctx, failKey(account))
+	var ipFails *redis.StringCmd
+	var ipTTL *redis.DurationCmd
+	if ip != "" {
+		ipFails = pipe.Get(ctx, ipKey(ip))
+		ipTTL = pipe.TTL(ctx, ipKey(ip))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	st := &Status{}
+	if ttl := lockTTL.Val(); ttl > 0 {
+		st.Locked, st.RetryAfter = true, ttl
+		return st, nil
+	}
+
+	ipCount := 0
+	if ipFails != nil {
+		ipCount, _ = strconv.Atoi(ipFails.Val())
+		if ipCount >= g.conf.IPLockAfter && ipTTL.Val() > 0 {
+			st.Locked, st.RetryAfter = true, ipTTL.Val()
+			return st, nil
+		}
+	}
+
+	count, _ := strconv.Atoi(fails.Val()["count"])
+	last, _ := strconv.ParseInt(fails.Val()["last"], 10, 64)
+	if count >= g.conf.DelayAfter {
+		if wait := time.Until(time.Unix(last, 0).Add(g.delay(count))); wait > 0 {
+			st.RetryAfter = wait
+		}
+	}
+	st.CaptchaRequired = count >= g.conf.CaptchaAfter || ipCount >= g.conf.IPCaptchaAfter
+	return st, nil
+}
+
+// Fail 记录一次失败，返回账号当前失败次数；达到 LockAfter 时锁定账号并返回 locked=true
+func (g *Guard) Fail(ctx context.Context, account, ip string) (count int, locked bool, err error) {
+	window := time.Duration(g.conf.Window) * time.Second
+	pipe := g.client.TxPipeline()
+	incr := pipe.HIncrBy(ctx, failKey(account), "count", 1)
+	pipe.HSet(ctx, failKey(account), "last", time.Now().Unix())
+	pipe.Expire(ctx, failKey(account), window)
+	if ip != "" {
+		pipe.Incr(ctx, ipKey(ip))
+		pipe.Expire(ctx, ipKey(ip), window)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, false, err
+	}
+
+	count = int(incr.Val())
+	if count < g.conf.LockAfter {
+		return count, false, nil
+	}
+	// 锁定后清零计数，解锁后重新开始累计
+	pipe = g.client.TxPipeline()
+	pipe.Set(ctx, lockKey(account), 1, time.Duration(g.conf.LockDuration)*time.Second)
+	pipe.Del(ctx, failKey(account))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return count, false, err
+	}
+	return count, true, nil
+}
+
+// Succeed 登录成功，清零账号失败计数
+func (g *Guard) Succeed(ctx context.Context, account string) error {
+	return g.client.Del(ctx, failKey(account)).Err()
+}
+
+// Unlock 解除账号锁定并清零失败计数
+func (g *Guard) Unlock(ctx context.Context, account string) error {
+	return g.client.Del(ctx, lockKey(account), failKey(account)).Err()
+}
+
+// delay 第 count 次失败后需要等待的时长
+func (g *Guard) delay(count int) time.Duration {
+	shift := count - g.conf.DelayAfter
+	if shift > 16 {
+		shift = 16
+	}
+	d := time.Duration(1<<shift) * time.Second
+	if max := time.Duration(g.conf.MaxDelay) * time.Second; d > max {
+		d = max
+	}
+	return d
+}
+
+func failKey(account string) string {
+	return cache.BuildKey(cache.KeyPrefixLoginFail, account)
+}
+
+func lockKey(account string) string {
+	return cache.BuildKey(cache.KeyPrefixLoginLock, account)
+}
+
+func ipKey(ip string) string {
+	return cache.BuildKey(cache.KeyPrefixLoginFail, "ip", ip)
+}
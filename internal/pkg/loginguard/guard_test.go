@@ -0,0 +1,36 @@
+package loginguard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuard_Delay(t *testing.T) {
+	g := NewGuard(nil, Config{DelayAfter: 5, MaxDelay: 60})
+
+	tests := []struct {
+		count int
+		want  time.Duration
+	}{
+		{5, time.Second},
+		{6, 2 * time.Second},
+		{9, 16 * time.Second},
+		{11, 60 * time.Second}, // 64s 封顶到 MaxDelay
+		{100, 60 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := g.delay(tt.count); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestNewGuard_Defaults(t *testing.T) {
+	g := NewGuard(nil, Config{LockAfter: 3})
+	if g.conf.LockAfter != 3 {
+		t.Errorf("LockAfter = %d, want configured 3", g.conf.LockAfter)
+	}
+	if g.conf.Window != 900 || g.conf.CaptchaAfter != 3 || g.conf.IPLockAfter != 50 {
+		t.Errorf("unexpected defaults: %+v", g.conf)
+	}
+}
@@ -0,0 +1,94 @@
+// Package membership 会员等级规则：按滚动窗口（默认 12 个月）内已完成订单的实付金额（GMV）和订单数评定等级，
+// 两个门槛同时满足才算达到该等级。每个等级带会员折扣、每月包邮次数和积分倍数等权益。
+package membership
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Tier 会员等级定义
+type Tier struct {
+	Level             int8
+	Name              string
+	MinGMV            float64 `json:",optional"`  // 窗口内实付金额门槛（元）
+	MinOrders         int     `json:",optional"`  // 窗口内订单数门槛
+	DiscountRate      float64 `json:",default=1"` // 会员折扣，0.95 表示 95 折，1 表示无折扣
+	FreeShippingQuota int     `json:",optional"`  // 每月包邮次数
+	PointsMultiplier  float64 `json:",default=1"` // 积分倍数
+}
+
+// DefaultTiers 未配置时使用的等级，与 constants.MemberLevel* 对应
+func DefaultTiers() []Tier {
+	return []Tier{
+		{Level: 0, Name: "普通会员", DiscountRate: 1, PointsMultiplier: 1},
+		{Level: 1, Name: "VIP1", MinGMV: 1000, MinOrders: 3, DiscountRate: 0.98, FreeShippingQuota: 1, PointsMultiplier: 1.2},
+		{Level: 2, Name: "VIP2", MinGMV: 5000, MinOrders: 10, DiscountRate: 0.95, FreeShippingQuota: 3, PointsMultiplier: 1.5},
+		{Level: 3, Name: "VIP3", MinGMV: 20000, MinOrders: 30, DiscountRate: 0.9, FreeShippingQuota: 10, PointsMultiplier: 2},
+	}
+}
+
+// Normalize 按等级排序并校验：必须有门槛为 0 的 0 级，等级越高门槛不能越低，折扣在 (0, 1] 之间
+func Normalize(tiers []Tier) ([]Tier, error) {
+	if len(tiers) == 0 {
+		return DefaultTiers(), nil
+	}
+	out := make([]Tier, len(tiers))
+	copy(out, tiers)
+	sort.Slice(out, func(i, j int) bool { return out[i].Level < out[j].Level })
+
+	if out[0].Level != 0 || out[0].MinGMV != 0 || out[0].MinOrders != 0 {
+		return nil, errors.New("membership: level 0 without thresholds is required")
+	}
+	for i, t := range out {
+		if t.DiscountRate <= 0 || t.DiscountRate > 1 {
+			return nil, fmt.Errorf("membership: level %d discount rate must be in (0, 1]", t.Level)
+		}
+		if t.PointsMultiplier <= 0 {
+			return nil, fmt.Errorf("membership: level %d points multiplier must be positive", t.Level)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := out[i-1]
+		if t.Level == prev.Level {
+			return nil, fmt.Errorf("membership: duplicate level %d", t.Level)
+		}
+		if t.MinGMV < prev.MinGMV || t.MinOrders < prev.MinOrders {
+			return nil, fmt.Errorf("membership: level %d thresholds lower than level %d", t.Level, prev.Level)
+		}
+	}
+	return out, nil
+}
+
+// Evaluate 返回 GMV 和订单数同时达到门槛的最高等级；tiers 须已 Normalize
+func Evaluate(tiers []Tier, gmv float64, orders int) Tier {
+	result := tiers[0]
+	for _, t := range tiers[1:] {
+		if gmv >= t.MinGMV && orders >= t.MinOrders {
+			result = t
+		}
+	}
+	return result
+}
+
+// Find 按等级查找，找不到时返回 0 级
+func Find(tiers []Tier, level int8) Tier {
+	for _, t := range tiers {
+		if t.Level == level {
+			return t
+		}
+	}
+	return tiers[0]
+}
+
+// Next 下一个等级，已是最高等级时返回 false
+func Next(tiers []Tier, level int8) (Tier, bool) {
+	for _, t := range tiers {
+		if t.Level > level {
+			return t, true
+		}
+	}
+	return Tier{}, false
+}
@@ -0,0 +1,48 @@
+package membership
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	tiers := DefaultTiers()
+	cases := []struct {
+		gmv    float64
+		orders int
+		want   int8
+	}{
+		{0, 0, 0},
+		{999, 10, 0},
+		{1000, 3, 1},
+		{30000, 9, 1}, // 金额够 VIP3，订单数只够 VIP1
+		{5000, 10, 2},
+		{20000, 30, 3},
+	}
+	for _, c := range cases {
+		if got := Evaluate(tiers, c.gmv, c.orders).Level; got != c.want {
+			t.Errorf("Evaluate(%v, %d) = %d, want %d", c.gmv, c.orders, got, c.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tiers, err := Normalize([]Tier{
+		{Level: 1, Name: "VIP", MinGMV: 100, DiscountRate: 0.9, PointsMultiplier: 1},
+		{Level: 0, Name: "普通", DiscountRate: 1, PointsMultiplier: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tiers[0].Level != 0 || tiers[1].Level != 1 {
+		t.Fatalf("tiers not sorted: %+v", tiers)
+	}
+
+	bad := [][]Tier{
+		{{Level: 1, DiscountRate: 1, PointsMultiplier: 1}},
+		{{Level: 0, DiscountRate: 1, PointsMultiplier: 1}, {Level: 1, MinGMV: 100, DiscountRate: 1.2, PointsMultiplier: 1}},
+		{{Level: 0, DiscountRate: 1, PointsMultiplier: 1}, {Level: 1, MinGMV: 100, DiscountRate: 1, PointsMultiplier: 1}, {Level: 2, MinGMV: 50, DiscountRate: 1, PointsMultiplier: 1}},
+	}
+	for i, b := range bad {
+		if _, err := Normalize(b); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"strings"
 
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
 	"ecommerce-system/internal/pkg/utils"
 
+	"github.com/zeromicro/go-zero/core/logx"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -15,16 +18,34 @@ import (
 // AuthInterceptor gRPC 一元拦截器：从 metadata 解析 JWT，将 user_id/username 注入 context。
 // 解析失败时不强制拦截——由具体 handler 决定是否要求登录。
 // 需要强制鉴权的接口，请使用 RequireAuthInterceptor。
-func AuthInterceptor(jwtSecret string) grpc.UnaryServerInterceptor {
+// denylist 不为 nil 时，已吊销的令牌按未登录处理。
+func AuthInterceptor(jwtSecret string, denylist *revocation.Denylist) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		ctx = injectUserFromMeta(ctx, jwtSecret)
+		ctx = injectUserFromMeta(ctx, jwtSecret, denylist)
 		return handler(ctx, req)
 	}
 }
 
+// AuthStreamInterceptor AuthInterceptor 的流式版本，用于上传等客户端流接口
+func AuthStreamInterceptor(jwtSecret string, denylist *revocation.Denylist) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: injectUserFromMeta(ss.Context(), jwtSecret, denylist)})
+	}
+}
+
+// authServerStream 替换 Context，使 handler 能从 stream.Context() 取到登录用户
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
 // RequireAuthInterceptor gRPC 一元拦截器：强制要求 JWT 有效，否则返回 Unauthenticated。
 // 白名单（skipMethods）中的方法名不做校验，格式如 "/user.v1.UserService/Login"。
-func RequireAuthInterceptor(jwtSecret string, skipMethods ...string) grpc.UnaryServerInterceptor {
+func RequireAuthInterceptor(jwtSecret string, denylist *revocation.Denylist, skipMethods ...string) grpc.UnaryServerInterceptor {
 	skip := make(map[string]struct{}, len(skipMethods))
 	for _, m := range skipMethods {
 		skip[m] = struct{}{}
@@ -35,7 +56,7 @@ func RequireAuthInterceptor(jwtSecret string, skipMethods ...string) grpc.UnaryS
 			return handler(ctx, req)
 		}
 
-		ctx = injectUserFromMeta(ctx, jwtSecret)
+		ctx = injectUserFromMeta(ctx, jwtSecret, denylist)
 
 		if _, ok := utils.GetUserID(ctx); !ok {
 			return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
@@ -44,8 +65,35 @@ func RequireAuthInterceptor(jwtSecret string, skipMethods ...string) grpc.UnaryS
 	}
 }
 
-// injectUserFromMeta 从 gRPC metadata 解析 Authorization header，写入 context
-func injectUserFromMeta(ctx context.Context, jwtSecret string) context.Context {
+// RequirePermissionInterceptor gRPC 一元拦截器：按 方法→权限 表校验调用方权限。
+// 表中没有的方法直接放行；表中的方法要求 JWT 有效（否则 Unauthenticated），
+// 且令牌声明中的权限包含所需权限（否则 PermissionDenied）。
+func RequirePermissionInterceptor(jwtSecret string, denylist *revocation.Denylist, permissions map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		perm, ok := permissions[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		// 前面的 AuthInterceptor 已经解析过令牌时直接复用
+		claims, ok := utils.GetClaims(ctx)
+		if !ok {
+			ctx = injectUserFromMeta(ctx, jwtSecret, denylist)
+			claims, ok = utils.GetClaims(ctx)
+		}
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+		}
+		if !rbac.HasPermission(claims.Permissions, perm) {
+			return nil, status.Errorf(codes.PermissionDenied, "没有权限: %s", perm)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// injectUserFromMeta 从 gRPC metadata 解析 Authorization header，写入 context。
+// 查询黑名单失败时放行（只记录日志），避免 Redis 故障导致所有登录用户不可用。
+func injectUserFromMeta(ctx context.Context, jwtSecret string, denylist *revocation.Denylist) context.Context {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return ctx
@@ -59,7 +107,16 @@ func injectUserFromMeta(ctx context.Context, jwtSecret string) context.Context {
 	if err != nil {
 		return ctx
 	}
+	if denylist != nil {
+		revoked, err := denylist.IsRevoked(ctx, claims)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("查询令牌黑名单失败: %v", err)
+		} else if revoked {
+			return ctx
+		}
+	}
 	ctx = utils.WithUserID(ctx, claims.UserID)
 	ctx = utils.WithUsername(ctx, claims.Username)
+	ctx = utils.WithClaims(ctx, claims)
 	return ctx
 }
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/utils"
+)
+
+const authTestSecret = "auth-test-secret"
+
+func accessToken(t *testing.T, perms ...string) (string, string) {
+	t.Helper()
+	token, jti, err := utils.GenerateAccessToken(utils.TokenSubject{UserID: 7, Username: "alice", Permissions: perms}, authTestSecret, 3600)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	return token, jti
+}
+
+func TestRequirePermissionInterceptor(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	denylist := revocation.NewDenylist(rdb)
+
+	revoked, jti := accessToken(t, rbac.PermAll)
+	if err := denylist.RevokeToken(context.Background(), jti, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	operator, _ := accessToken(t, rbac.PermProductWrite)
+	admin, _ := accessToken(t, rbac.PermAll)
+	otherSecret, _, _ := utils.GenerateAccessToken(utils.TokenSubject{UserID: 7, Permissions: []string{rbac.PermAll}}, "other-secret", 3600)
+
+	const (
+		protected = "/product.v1.ProductService/ApproveProductRevision"
+		unlisted  = "/product.v1.ProductService/GetProduct"
+	)
+	interceptor := RequirePermissionInterceptor(authTestSecret, denylist, map[string]string{protected: rbac.PermProductReview})
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   codes.Code
+	}{
+		{"missing token", protected, "", codes.Unauthenticated},
+		{"token signed with another secret", protected, otherSecret, codes.Unauthenticated},
+		{"revoked token", protected, revoked, codes.Unauthenticated},
+		{"missing permission", protected, operator, codes.PermissionDenied},
+		{"wildcard permission", protected, admin, codes.OK},
+		{"unlisted method without token", unlisted, "", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tt.token))
+			}
+			called := false
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %v, want %v (err %v)", got, tt.want, err)
+			}
+			if called != (tt.want == codes.OK) {
+				t.Fatalf("handler called = %v", called)
+			}
+		})
+	}
+}
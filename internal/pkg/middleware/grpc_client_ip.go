@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"ecommerce-system/internal/pkg/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIPInterceptor gRPC 一元拦截器：把客户端 IP 写入 context（utils.GetClientIP）。
+// 经网关转发的请求取 metadata 中 x-forwarded-for 的第一个地址，直连时取对端地址。
+func ClientIPInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if ip := clientIPFromContext(ctx); ip != "" {
+			ctx = utils.WithClientIP(ctx, ip)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// clientIPFromContext 解析客户端 IP
+func clientIPFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if xff := md.Get("x-forwarded-for"); len(xff) > 0 && xff[0] != "" {
+			ip, _, _ := strings.Cut(xff[0], ",")
+			return strings.TrimSpace(ip)
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			return p.Addr.String()
+		}
+		return host
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// 网关转发的客户端设备信息。user-agent 是 gRPC 保留头（会被 gRPC 客户端自身的 UA 覆盖），
+// 浏览器的 User-Agent 改用 x-client-user-agent 转发
+const (
+	MetadataUserAgent = "x-client-user-agent"
+	MetadataDeviceID  = "x-device-id"
+)
+
+// 超长的头截断，避免撑爆会话表
+const (
+	maxUserAgentLen = 512
+	maxDeviceIDLen  = 64
+)
+
+// DeviceInterceptor gRPC 一元拦截器：把客户端 User-Agent 和设备标识写入 context
+// （utils.GetUserAgent / utils.GetDeviceID），用于登录会话记录
+func DeviceInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := firstValue(md, MetadataUserAgent, maxUserAgentLen); v != "" {
+				ctx = utils.WithUserAgent(ctx, v)
+			}
+			if v := firstValue(md, MetadataDeviceID, maxDeviceIDLen); v != "" {
+				ctx = utils.WithDeviceID(ctx, v)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// firstValue 取 metadata 的第一个值并按长度截断
+func firstValue(md metadata.MD, key string, maxLen int) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	v := values[0]
+	if len(v) > maxLen {
+		v = v[:maxLen]
+	}
+	return v
+}
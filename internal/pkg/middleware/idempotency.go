@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/idempotency"
+	"ecommerce-system/internal/pkg/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// IdempotencyInterceptor gRPC 一元拦截器：对指定方法启用 Idempotency-Key 语义。
+// 只有 metadata 中带了 idempotency-key 的请求才会被处理，未带 key 的请求原样放行。
+//   - 重复请求（同 key、同请求体）：直接回放第一次的响应，不再执行 handler
+//   - 同 key、不同请求体：返回 InvalidArgument
+//   - 第一次请求仍在处理中：返回 Aborted（网关映射为 409）
+//
+// handler 返回 error 时释放 key，允许客户端用同一个 key 重试。
+// 幂等键按调用方隔离：前面的鉴权拦截器已解析出用户时直接使用，否则用 jwtSecret 校验 authorization，
+// 访问令牌刷新后重试仍命中同一个 key。
+// methods 格式如 "/order.v1.OrderService/CreateOrder"。
+func IdempotencyInterceptor(store *idempotency.Store, jwtSecret string, methods ...string) grpc.UnaryServerInterceptor {
+	enabled := make(map[string]struct{}, len(methods))
+	for _, m := range methods {
+		enabled[m] = struct{}{}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if store == nil {
+			return handler(ctx, req)
+		}
+		if _, ok := enabled[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		keys := md.Get(idempotency.MetadataKey)
+		if len(keys) == 0 || keys[0] == "" {
+			return handler(ctx, req)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "序列化请求失败: "+err.Error())
+		}
+
+		// 按调用方 + 方法隔离 key
+		key := idempotency.BuildKey(callerScope(ctx, md, jwtSecret)+":"+info.FullMethod, keys[0])
+		fingerprint := idempotency.Fingerprint([]byte(info.FullMethod), body)
+
+		rec, err := store.Begin(ctx, key, fingerprint)
+		switch err {
+		case nil:
+		case idempotency.ErrInProgress:
+			return nil, status.Error(codes.Aborted, "相同 Idempotency-Key 的请求正在处理中，请稍后重试")
+		case idempotency.ErrKeyReused:
+			return nil, status.Error(codes.InvalidArgument, "Idempotency-Key 已被用于不同的请求")
+		default:
+			// Redis 不可用时降级为不做幂等，避免影响主链路
+			logx.Errorf("idempotency begin failed, method=%s: %v", info.FullMethod, err)
+			return handler(ctx, req)
+		}
+
+		// 回放已保存的响应
+		if rec != nil {
+			var stored anypb.Any
+			if err := proto.Unmarshal(rec.Body, &stored); err != nil {
+				return nil, status.Error(codes.Internal, "回放幂等响应失败: "+err.Error())
+			}
+			resp, err := stored.UnmarshalNew()
+			if err != nil {
+				return nil, status.Error(codes.Internal, "回放幂等响应失败: "+err.Error())
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+			return resp, nil
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			_ = store.Release(ctx, key)
+			return resp, err
+		}
+
+		if respMsg, ok := resp.(proto.Message); ok {
+			stored, mErr := anypb.New(respMsg)
+			if mErr == nil {
+				data, mErr := proto.Marshal(stored)
+				if mErr == nil {
+					mErr = store.Complete(ctx, key, &idempotency.Record{Fingerprint: fingerprint, Body: data})
+				}
+			}
+			if mErr != nil {
+				logx.Errorf("idempotency complete failed, method=%s: %v", info.FullMethod, mErr)
+				_ = store.Release(ctx, key)
+			}
+		}
+		return resp, nil
+	}
+}
+
+// callerScope 计算调用方作用域，见 idempotency.Scope
+func callerScope(ctx context.Context, md metadata.MD, jwtSecret string) string {
+	if claims, ok := utils.GetClaims(ctx); ok {
+		return idempotency.ClaimsScope(claims)
+	}
+	var auth string
+	if values := md.Get("authorization"); len(values) > 0 {
+		auth = values[0]
+	}
+	return idempotency.Scope(auth, jwtSecret)
+}
@@ -109,6 +109,25 @@ var (
 		},
 		[]string{"service", "topic"},
 	)
+
+	// 网关灰度路由：按服务版本统计上游请求
+	CanaryRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_canary_requests_total",
+			Help: "网关按版本转发的上游请求总数",
+		},
+		[]string{"service", "version", "code"},
+	)
+
+	// 网关灰度路由：按服务版本统计上游延迟
+	CanaryRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "gateway_canary_request_duration_seconds",
+			Help:    "网关按版本转发的上游请求延迟（秒）",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"service", "version"},
+	)
 )
 
 // RecordHTTPRequest 记录HTTP请求指标
@@ -122,3 +141,9 @@ func RecordGRPCRequest(service, method string, status string, duration float64)
 	GRPCRequestsTotal.WithLabelValues(service, method, status).Inc()
 	GRPCRequestDuration.WithLabelValues(service, method).Observe(duration)
 }
+
+// RecordCanaryRequest 记录灰度路由的版本请求指标
+func RecordCanaryRequest(service, version, code string, duration float64) {
+	CanaryRequestsTotal.WithLabelValues(service, version, code).Inc()
+	CanaryRequestDuration.WithLabelValues(service, version).Observe(duration)
+}
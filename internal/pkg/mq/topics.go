@@ -41,5 +41,6 @@ const (
 
 	// 系统消息
 	TopicSystemNotification = "system.notification"
+	TopicMessageCreated     = "message.created" // 站内消息已创建（推送网关消费）
 	TopicDataSync           = "data.sync"
 )
@@ -0,0 +1,278 @@
+// Package oidc OAuth2 授权码 + OpenID Connect 第三方登录客户端。
+//
+// 端点通过 issuer 的 /.well-known/openid-configuration 发现（首次使用时加载，失败下次重试），
+// 授权码换令牌使用 PKCE(S256)，id_token 用 JWKS 公钥校验签名、iss、aud、exp 和 nonce。
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// ErrInvalidIDToken id_token 校验失败
+var ErrInvalidIDToken = errors.New("oidc: invalid id_token")
+
+// ProviderConfig 第三方登录提供方配置
+type ProviderConfig struct {
+	Name         string // 提供方标识，如 google、mock，出现在接口路径和凭证 key 中
+	DisplayName  string `json:",optional"`
+	Issuer       string // 如 https://accounts.google.com
+	ClientID     string
+	ClientSecret string   `json:",optional"`
+	RedirectURL  string   // 授权后回跳的前端页面，由前端把 code/state 提交给登录接口
+	Scopes       []string `json:",optional"` // 默认 openid profile email
+	// AutoRegister 第三方账号未绑定时自动注册新用户
+	AutoRegister bool `json:",default=true"`
+	// LinkByEmail 首次登录时按已验证的邮箱绑定已有账号，只对可信的提供方开启
+	LinkByEmail bool `json:",optional"`
+}
+
+// Identity id_token 中的用户身份
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// Provider 单个 OIDC 提供方
+type Provider struct {
+	conf   ProviderConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config // 发现完成后才有值
+	jwksURL  string
+	keys     map[string]any // kid -> 公钥
+	keysAt   time.Time
+	loadedAt time.Time
+}
+
+// discovery openid-configuration 中用到的字段
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider 创建提供方，不立即请求发现端点
+func NewProvider(c ProviderConfig) (*Provider, error) {
+	if c.Name == "" || c.Issuer == "" || c.ClientID == "" || c.RedirectURL == "" {
+		return nil, errors.New("oidc: provider requires Name, Issuer, ClientID and RedirectURL")
+	}
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"openid", "profile", "email"}
+	}
+	c.Issuer = strings.TrimSuffix(c.Issuer, "/")
+	return &Provider{
+		conf:   c,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// NewProviders 按配置创建全部提供方
+func NewProviders(cs []ProviderConfig) (map[string]*Provider, error) {
+	providers := make(map[string]*Provider, len(cs))
+	for _, c := range cs {
+		if _, ok := providers[c.Name]; ok {
+			return nil, fmt.Errorf("oidc: duplicate provider %q", c.Name)
+		}
+		p, err := NewProvider(c)
+		if err != nil {
+			return nil, err
+		}
+		providers[c.Name] = p
+	}
+	return providers, nil
+}
+
+// Config 提供方配置
+func (p *Provider) Config() ProviderConfig {
+	return p.conf
+}
+
+// AuthCodeURL 生成授权地址。verifier 为 PKCE code_verifier，可用 oauth2.GenerateVerifier 生成。
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oc, err := p.ensure(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oc.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.S256ChallengeOption(verifier),
+	), nil
+}
+
+// Exchange 用授权码换取令牌并校验 id_token，返回用户身份
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	oc, err := p.ensure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	token, err := oc.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: exchange code: %w", err)
+	}
+	raw, _ := token.Extra("id_token").(string)
+	if raw == "" {
+		return nil, fmt.Errorf("%w: missing in token response", ErrInvalidIDToken)
+	}
+	return p.VerifyIDToken(ctx, raw, nonce)
+}
+
+// idTokenClaims id_token 声明
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string `json:"nonce"`
+	AuthorizedBy  string `json:"azp"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // 部分提供方返回字符串 "true"
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// VerifyIDToken 校验 id_token
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*Identity, error) {
+	if _, err := p.ensure(ctx); err != nil {
+		return nil, err
+	}
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.conf.Issuer),
+		jwt.WithAudience(p.conf.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != p.conf.ClientID {
+		return nil, fmt.Errorf("%w: azp mismatch", ErrInvalidIDToken)
+	}
+
+	verified := false
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		verified = v
+	case string:
+		verified = v == "true"
+	}
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: verified,
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}, nil
+}
+
+// ensure 加载发现文档，失败后 10 秒内不重复请求
+func (p *Provider) ensure(ctx context.Context) (*oauth2.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth != nil {
+		return p.oauth, nil
+	}
+	if time.Since(p.loadedAt) < 10*time.Second {
+		return nil, fmt.Errorf("oidc: provider %s discovery unavailable", p.conf.Name)
+	}
+	p.loadedAt = time.Now()
+
+	var doc discovery
+	if err := p.getJSON(ctx, p.conf.Issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("oidc: discovery %s: %w", p.conf.Name, err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != p.conf.Issuer {
+		return nil, fmt.Errorf("oidc: discovery issuer %q does not match %q", doc.Issuer, p.conf.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: discovery %s: missing endpoints", p.conf.Name)
+	}
+
+	p.jwksURL = doc.JWKSURI
+	p.oauth = &oauth2.Config{
+		ClientID:     p.conf.ClientID,
+		ClientSecret: p.conf.ClientSecret,
+		RedirectURL:  p.conf.RedirectURL,
+		Scopes:       p.conf.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  doc.AuthorizationEndpoint,
+			TokenURL: doc.TokenEndpoint,
+		},
+	}
+	return p.oauth, nil
+}
+
+// key 按 kid 取公钥，找不到时刷新 JWKS（提供方轮换密钥），1 分钟内最多刷新一次
+func (p *Provider) key(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	if time.Since(p.keysAt) < time.Minute {
+		return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+	}
+	p.keysAt = time.Now()
+
+	var set jwkSet
+	if err := p.getJSON(ctx, p.jwksURL, &set); err != nil {
+		return nil, fmt.Errorf("oidc: fetch jwks: %w", err)
+	}
+	p.keys = set.publicKeys()
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+}
+
+// lookup kid 为空且只有一把密钥时直接使用
+func (p *Provider) lookup(kid string) (any, bool) {
+	if k, ok := p.keys[kid]; ok {
+		return k, true
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
@@ -0,0 +1,108 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"ecommerce-system/internal/pkg/oidc"
+	"ecommerce-system/internal/pkg/oidc/mockoidc"
+)
+
+func newMockProvider(t *testing.T) (*oidc.Provider, *mockoidc.Server) {
+	t.Helper()
+	mock, err := mockoidc.New("", "shop", "shop-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(mock)
+	t.Cleanup(ts.Close)
+	mock.Issuer = ts.URL
+
+	p, err := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "mock",
+		Issuer:       ts.URL,
+		ClientID:     "shop",
+		ClientSecret: "shop-secret",
+		RedirectURL:  "http://localhost:3000/oauth/callback",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, mock
+}
+
+// authorize 访问授权地址，返回回跳地址中的 code 和 state
+func authorize(t *testing.T, authURL, loginHint string) (string, string) {
+	t.Helper()
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	q.Set("login_hint", loginHint)
+	u.RawQuery = q.Encode()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d", resp.StatusCode)
+	}
+	loc, _ := url.Parse(resp.Header.Get("Location"))
+	return loc.Query().Get("code"), loc.Query().Get("state")
+}
+
+func TestProviderExchange(t *testing.T) {
+	ctx := context.Background()
+	p, mock := newMockProvider(t)
+	mock.AddUser(mockoidc.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := authorize(t, authURL, "alice")
+	if state != "state-1" {
+		t.Fatalf("state = %q", state)
+	}
+
+	id, err := p.Exchange(ctx, code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Subject != "alice" || id.Email != "alice@example.com" || !id.EmailVerified || id.Name != "Alice" {
+		t.Fatalf("identity = %+v", id)
+	}
+
+	// 授权码只能使用一次
+	if _, err := p.Exchange(ctx, code, verifier, "nonce-1"); err == nil {
+		t.Fatal("reused code should fail")
+	}
+}
+
+func TestProviderRejectsBadNonceAndVerifier(t *testing.T) {
+	ctx := context.Background()
+	p, _ := newMockProvider(t)
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := p.AuthCodeURL(ctx, "s", "nonce-1", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := authorize(t, authURL, "bob")
+	if _, err := p.Exchange(ctx, code, verifier, "other-nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+		t.Fatalf("nonce mismatch: err = %v", err)
+	}
+
+	code, _ = authorize(t, authURL, "bob")
+	if _, err := p.Exchange(ctx, code, oauth2.GenerateVerifier(), "nonce-1"); err == nil {
+		t.Fatal("wrong PKCE verifier should fail")
+	}
+}
@@ -0,0 +1,249 @@
+package openapi
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"ecommerce-system/internal/pkg/apikey"
+)
+
+// 安全方案名
+const (
+	securityBearer = "bearerAuth"
+	securityApiKey = "apiKeyAuth"
+)
+
+// Document OpenAPI 3.1 文档
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server 服务地址
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag 接口分组（每个 gRPC 服务一组）
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// SecurityRequirement 安全要求，key 为安全方案名
+type SecurityRequirement map[string][]string
+
+// PathItem 同一路径下各 HTTP 方法的接口
+type PathItem map[string]*Operation
+
+// Operation 单个接口
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	// Security 为空切片时表示免登录，nil 表示沿用文档级要求
+	Security *[]SecurityRequirement `json:"security,omitempty"`
+	// GRPCMethod 对应的 gRPC 方法，便于排查问题
+	GRPCMethod string `json:"x-grpc-method"`
+	// ApiKeyScopes 允许调用该接口的开放平台权限范围
+	ApiKeyScopes []string `json:"x-api-key-scopes,omitempty"`
+}
+
+// Parameter 路径或查询参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType 内容类型
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components 可复用组件
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme 安全方案
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// errorSchemaName 网关错误响应的 Schema 名
+const errorSchemaName = "ErrorResponse"
+
+// apiKeyDescription 开放平台签名说明
+const apiKeyDescription = "开放平台 API Key。除 " + apikey.HeaderKeyID + " 外还需携带 " +
+	apikey.HeaderTimestamp + "（Unix 秒）、" + apikey.HeaderNonce + "（8-64 位随机串，不可重复）和 " +
+	apikey.HeaderSignature + "。\n签名为 base64(HMAC-SHA256(secret, 签名串))，签名串按行拼接：" +
+	"METHOD、PATH、按 key 排序的 QUERY、TIMESTAMP、NONCE、hex(sha256(body))。\n" +
+	"只能访问 Key 权限范围覆盖的接口（见各接口的 x-api-key-scopes）。"
+
+// OpenAPI 生成 OpenAPI 3.1 文档
+func (a *API) OpenAPI() *Document {
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       a.opts.Title,
+			Version:     a.opts.Version,
+			Description: a.opts.Description,
+		},
+		Security: []SecurityRequirement{{securityBearer: {}}},
+		Paths:    make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema, len(a.types)+1),
+			SecuritySchemes: map[string]*SecurityScheme{
+				securityBearer: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "登录接口返回的 token，放在 Authorization: Bearer <token>",
+				},
+			},
+		},
+	}
+	for _, s := range a.opts.Servers {
+		doc.Servers = append(doc.Servers, Server{URL: s})
+	}
+	for _, sd := range a.services {
+		doc.Tags = append(doc.Tags, Tag{Name: string(sd.Name()), Description: comment(sd)})
+	}
+
+	doc.Components.Schemas[errorSchemaName] = &Schema{
+		Type:        "object",
+		Description: "错误响应：网关中间件返回 JSON；上游 gRPC 错误按状态码映射为 HTTP 状态码",
+		Properties: map[string]*Schema{
+			"code":    {Type: "integer", Format: "int32"},
+			"message": {Type: "string"},
+		},
+	}
+	for _, d := range a.types {
+		name := a.typeName(d)
+		switch d := d.(type) {
+		case protoreflect.MessageDescriptor:
+			doc.Components.Schemas[name] = a.messageSchema(d)
+		case protoreflect.EnumDescriptor:
+			doc.Components.Schemas[name] = a.enumSchema(d)
+		}
+	}
+
+	usesApiKey := false
+	for _, op := range a.operations {
+		item, ok := doc.Paths[op.path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[op.path] = item
+		}
+		item[op.httpMethod] = a.buildOperation(op)
+		if len(op.scopes) > 0 {
+			usesApiKey = true
+		}
+	}
+	if usesApiKey {
+		doc.Components.SecuritySchemes[securityApiKey] = &SecurityScheme{
+			Type:        "apiKey",
+			In:          "header",
+			Name:        apikey.HeaderKeyID,
+			Description: apiKeyDescription,
+		}
+	}
+	return doc
+}
+
+// buildOperation 生成单个接口
+func (a *API) buildOperation(op *operation) *Operation {
+	summary, description := summaryOf(op.method)
+	o := &Operation{
+		Tags:        []string{string(op.method.Parent().Name())},
+		Summary:     summary,
+		Description: description,
+		OperationID: op.id,
+		GRPCMethod:  op.rpcPath,
+		Responses: map[string]*Response{
+			"200": {
+				Description: "成功",
+				Content:     jsonContent(&Schema{Ref: schemaRef(a.typeName(op.method.Output()))}),
+			},
+			"default": {
+				Description: "失败",
+				Content:     jsonContent(&Schema{Ref: schemaRef(errorSchemaName)}),
+			},
+		},
+	}
+
+	for _, p := range op.pathParams {
+		o.Parameters = append(o.Parameters, a.parameter(p, "path"))
+	}
+	for _, p := range op.queryParams {
+		o.Parameters = append(o.Parameters, a.parameter(p, "query"))
+	}
+	if op.hasBody {
+		o.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(&Schema{Ref: schemaRef(a.typeName(op.method.Input()))}),
+		}
+	}
+
+	switch {
+	case op.public:
+		o.Security = &[]SecurityRequirement{}
+	case len(op.scopes) > 0:
+		o.Security = &[]SecurityRequirement{{securityBearer: {}}, {securityApiKey: {}}}
+		o.ApiKeyScopes = op.scopes
+	}
+	return o
+}
+
+// parameter 生成路径/查询参数，找不到对应请求字段时按字符串处理
+func (a *API) parameter(p param, in string) *Parameter {
+	param := &Parameter{Name: p.name, In: in, Required: in == "path"}
+	if p.field == nil {
+		param.Schema = &Schema{Type: "string"}
+		return param
+	}
+	s := a.fieldSchema(p.field)
+	param.Description, s.Description = s.Description, ""
+	param.Schema = s
+	return param
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
@@ -0,0 +1,365 @@
+// Package openapi 基于编译后的 proto 描述符和网关路由映射生成 OpenAPI 3.1 文档与 TypeScript 客户端。
+// 字段名、类型与网关实际的 JSON 编解码保持一致：字段使用 json_name（小驼峰），
+// 64 位整数按 protojson 规则以字符串返回，枚举以名称返回。
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"ecommerce-system/internal/pkg/apikey"
+)
+
+// Route 网关路由映射，对应 gateway.yaml 中 Upstreams[].Mappings 的一项
+type Route struct {
+	Method  string `yaml:"Method"`
+	Path    string `yaml:"Path"`
+	RpcPath string `yaml:"RpcPath"`
+}
+
+// Options 文档生成选项
+type Options struct {
+	Title       string   `yaml:"Title"`
+	Version     string   `yaml:"Version"`
+	Description string   `yaml:"Description"`
+	Servers     []string `yaml:"Servers"`
+	// Public 无需登录即可调用的 RPC，如 user.v1.UserService/Login，service/* 表示整个服务
+	Public []string `yaml:"Public"`
+	// ApiKeyScopes 开放平台权限范围，命中的接口额外标注可用 API Key 签名调用
+	ApiKeyScopes []apikey.ScopeRule `yaml:"-"`
+}
+
+// Compile 编译 importPath 下的全部 .proto 文件（保留注释信息，用于生成文档说明）
+func Compile(ctx context.Context, importPath string) ([]protoreflect.FileDescriptor, error) {
+	var names []string
+	err := filepath.WalkDir(importPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".proto") {
+			rel, err := filepath.Rel(importPath, path)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: []string{importPath},
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	files, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, fmt.Errorf("compile protos: %w", err)
+	}
+
+	result := make([]protoreflect.FileDescriptor, 0, len(files))
+	for _, f := range files {
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+// API 网关对外接口的中间模型，OpenAPI 文档和 TypeScript 客户端都从这里生成
+type API struct {
+	opts       Options
+	operations []*operation
+	types      []protoreflect.Descriptor // 接口用到的消息和枚举，按首次引用顺序
+	names      map[protoreflect.FullName]string
+	services   []protoreflect.ServiceDescriptor
+}
+
+// operation 一个 HTTP 接口
+type operation struct {
+	id          string
+	httpMethod  string // 小写
+	path        string // OpenAPI 格式，如 /api/v1/orders/{id}
+	rpcPath     string
+	method      protoreflect.MethodDescriptor
+	pathParams  []param
+	queryParams []param
+	hasBody     bool
+	public      bool
+	scopes      []string // 可访问该接口的 API Key 权限范围
+}
+
+// param 路径或查询参数，name 为 URL 中的参数名，field 为对应的请求字段（可能为空）
+type param struct {
+	name  string
+	field protoreflect.FieldDescriptor
+}
+
+// Build 根据描述符和网关路由构建接口模型；路由指向不存在的 RPC 时返回错误
+func Build(files []protoreflect.FileDescriptor, routes []Route, opts Options) (*API, error) {
+	methods := make(map[string]protoreflect.MethodDescriptor)
+	for _, f := range files {
+		services := f.Services()
+		for i := 0; i < services.Len(); i++ {
+			sd := services.Get(i)
+			for j := 0; j < sd.Methods().Len(); j++ {
+				md := sd.Methods().Get(j)
+				methods[string(sd.FullName())+"/"+string(md.Name())] = md
+			}
+		}
+	}
+
+	a := &API{opts: opts}
+	scopes := apikey.NewScopes(opts.ApiKeyScopes)
+	seenService := make(map[protoreflect.FullName]bool)
+	usedIDs := make(map[string]int)
+
+	for _, r := range routes {
+		httpMethod := strings.ToLower(r.Method)
+		if httpMethod == "options" {
+			continue
+		}
+		md, ok := methods[r.RpcPath]
+		if !ok {
+			return nil, fmt.Errorf("route %s %s: rpc %s not found in protos", r.Method, r.Path, r.RpcPath)
+		}
+		if md.IsStreamingClient() || md.IsStreamingServer() {
+			return nil, fmt.Errorf("route %s %s: streaming rpc %s cannot be mapped", r.Method, r.Path, r.RpcPath)
+		}
+
+		sd := md.Parent().(protoreflect.ServiceDescriptor)
+		if !seenService[sd.FullName()] {
+			seenService[sd.FullName()] = true
+			a.services = append(a.services, sd)
+		}
+
+		op := &operation{
+			httpMethod: httpMethod,
+			path:       templatePath(r.Path),
+			rpcPath:    r.RpcPath,
+			method:     md,
+			hasBody:    httpMethod == "post" || httpMethod == "put" || httpMethod == "patch",
+			public:     matchPublic(opts.Public, r.RpcPath),
+		}
+		op.id = a.operationID(md, usedIDs)
+
+		input := md.Input()
+		inPath := make(map[protoreflect.FullName]bool)
+		for _, name := range pathParamNames(r.Path) {
+			fd := findField(input, name)
+			if fd != nil {
+				inPath[fd.FullName()] = true
+			}
+			op.pathParams = append(op.pathParams, param{name: name, field: fd})
+		}
+		if !op.hasBody {
+			fields := input.Fields()
+			for i := 0; i < fields.Len(); i++ {
+				fd := fields.Get(i)
+				if inPath[fd.FullName()] || !queryable(fd) {
+					continue
+				}
+				op.queryParams = append(op.queryParams, param{name: string(fd.Name()), field: fd})
+			}
+		}
+
+		for name, rule := range scopes {
+			if rule.Match(r.Method, r.Path) {
+				op.scopes = append(op.scopes, name)
+			}
+		}
+		sort.Strings(op.scopes)
+
+		a.operations = append(a.operations, op)
+	}
+
+	a.collectTypes()
+	return a, nil
+}
+
+// operationID 生成唯一的 operationId：默认用 RPC 名，重名时加服务名前缀，仍重名时加序号
+func (a *API) operationID(md protoreflect.MethodDescriptor, used map[string]int) string {
+	id := lowerCamel(string(md.Name()))
+	if _, ok := used[id]; ok {
+		service := strings.TrimSuffix(string(md.Parent().Name()), "Service")
+		id = lowerCamel(service) + string(md.Name())
+	}
+	used[id]++
+	if n := used[id]; n > 1 {
+		id = fmt.Sprintf("%s%d", id, n)
+	}
+	if reservedWords[id] {
+		id += "Rpc"
+	}
+	return id
+}
+
+// collectTypes 从接口的请求/响应出发收集用到的消息和枚举，并分配不冲突的类型名
+func (a *API) collectTypes() {
+	seen := make(map[protoreflect.FullName]bool)
+	var visit func(d protoreflect.Descriptor)
+	visit = func(d protoreflect.Descriptor) {
+		if seen[d.FullName()] {
+			return
+		}
+		seen[d.FullName()] = true
+		md, ok := d.(protoreflect.MessageDescriptor)
+		if ok && (wellKnownSchema(md.FullName()) != nil || md.IsMapEntry()) {
+			return
+		}
+		a.types = append(a.types, d)
+		if !ok {
+			return
+		}
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if fd.IsMap() {
+				fd = fd.MapValue()
+			}
+			if fd.Enum() != nil {
+				visit(fd.Enum())
+			}
+			if fd.Message() != nil {
+				visit(fd.Message())
+			}
+		}
+	}
+	for _, op := range a.operations {
+		visit(op.method.Input())
+		visit(op.method.Output())
+	}
+
+	// 同名类型（不同 package 下的同名消息）加上 package 前缀
+	count := make(map[string]int)
+	for _, d := range a.types {
+		count[localName(d)]++
+	}
+	a.names = make(map[protoreflect.FullName]string, len(a.types))
+	for _, d := range a.types {
+		name := localName(d)
+		if count[name] > 1 {
+			name = pascal(string(d.ParentFile().Package())) + name
+		}
+		a.names[d.FullName()] = name
+	}
+}
+
+// typeName 消息或枚举在文档和 TypeScript 中的名字
+func (a *API) typeName(d protoreflect.Descriptor) string {
+	return a.names[d.FullName()]
+}
+
+// localName 去掉 package 的类型名，嵌套消息拼接外层名，如 Order.Item -> OrderItem
+func localName(d protoreflect.Descriptor) string {
+	name := strings.TrimPrefix(string(d.FullName()), string(d.ParentFile().Package())+".")
+	return strings.ReplaceAll(name, ".", "")
+}
+
+// templatePath 网关路径参数 :id 转为 OpenAPI 的 {id}
+func templatePath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathParamNames 提取网关路径中的参数名
+func pathParamNames(path string) []string {
+	var names []string
+	for _, p := range strings.Split(path, "/") {
+		if strings.HasPrefix(p, ":") {
+			names = append(names, p[1:])
+		}
+	}
+	return names
+}
+
+// findField 按 proto 字段名或 json_name 查找请求字段
+func findField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return md.Fields().ByJSONName(name)
+}
+
+// queryable 是否可以作为查询参数（标量、枚举及其数组）
+func queryable(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsMap() {
+		return false
+	}
+	return fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind
+}
+
+// matchPublic 判断 RPC 是否在免登录名单中
+func matchPublic(patterns []string, rpcPath string) bool {
+	for _, p := range patterns {
+		if p == rpcPath {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "/*"); ok && strings.HasPrefix(rpcPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// comment 描述符的前置注释（没有时取行尾注释）
+func comment(d protoreflect.Descriptor) string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	text := strings.TrimSpace(loc.LeadingComments)
+	if text == "" {
+		text = strings.TrimSpace(loc.TrailingComments)
+	}
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// summaryOf 注释首行作为摘要，其余作为详细说明
+func summaryOf(d protoreflect.Descriptor) (summary, description string) {
+	text := comment(d)
+	summary, description, _ = strings.Cut(text, "\n")
+	if summary == "" {
+		summary = string(d.Name())
+	}
+	return summary, strings.TrimSpace(description)
+}
+
+// lowerCamel GetUserInfo -> getUserInfo
+func lowerCamel(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// pascal user.v1 -> UserV1
+func pascal(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// reservedWords 不能作为函数名的 TypeScript 保留字
+var reservedWords = map[string]bool{
+	"delete": true, "new": true, "default": true, "import": true, "export": true,
+	"function": true, "return": true, "switch": true, "case": true, "class": true,
+	"const": true, "var": true, "let": true, "in": true, "typeof": true, "void": true,
+}
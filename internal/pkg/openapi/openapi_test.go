@@ -0,0 +1,241 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ecommerce-system/internal/pkg/apikey"
+)
+
+const testShopProto = `syntax = "proto3";
+
+package shop.v1;
+
+// 商店服务
+service ShopService {
+  // 获取订单
+  // 只返回当前用户的订单
+  rpc GetOrder (GetOrderRequest) returns (GetOrderResponse);
+  // 下单
+  rpc CreateOrder (CreateOrderRequest) returns (GetOrderResponse);
+  rpc Login (LoginRequest) returns (GetOrderResponse);
+}
+
+// 订单状态
+enum OrderStatus {
+  ORDER_STATUS_UNSPECIFIED = 0;
+  ORDER_STATUS_PAID = 1; // 已支付
+}
+
+message Address {
+  string city = 1;
+}
+
+message Order {
+  // 订单项
+  message Item {
+    int64 sku_id = 1;
+    int32 quantity = 2;
+  }
+  int64 id = 1;
+  string order_no = 2; // 订单号，示例：202401010001
+  OrderStatus status = 3;
+  repeated Item items = 4;
+  map<string, string> extra = 5;
+  Address address = 6;
+  oneof payer {
+    int64 user_id = 7;
+    string guest_token = 8;
+  }
+}
+
+message GetOrderRequest {
+  int64 id = 1;
+  string order_no = 2;
+  repeated int64 sku_ids = 3;
+  Address filter = 4;
+}
+
+message GetOrderResponse {
+  int32 code = 1;
+  string message = 2;
+  Order data = 3;
+}
+
+message CreateOrderRequest {
+  repeated Order.Item items = 1;
+  Address address = 2;
+}
+
+message LoginRequest {
+  string username = 1;
+}
+`
+
+// testUserProto 与 shop.v1 同名的 Address，生成时需要加 package 前缀区分
+const testUserProto = `syntax = "proto3";
+
+package user.v1;
+
+service AddressService {
+  rpc GetAddress (GetAddressRequest) returns (Address);
+}
+
+message GetAddressRequest {
+  int64 id = 1;
+}
+
+message Address {
+  string detail = 1;
+}
+`
+
+func buildTestAPI(t *testing.T) *API {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"shop/v1/shop.proto": testShopProto,
+		"user/v1/user.proto": testUserProto,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Compile(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	api, err := Build(files, []Route{
+		{Method: "options", Path: "/api/v1/orders/:id", RpcPath: "shop.v1.ShopService/GetOrder"},
+		{Method: "get", Path: "/api/v1/orders/:id", RpcPath: "shop.v1.ShopService/GetOrder"},
+		{Method: "post", Path: "/api/v1/orders", RpcPath: "shop.v1.ShopService/CreateOrder"},
+		{Method: "post", Path: "/api/v1/login", RpcPath: "shop.v1.ShopService/Login"},
+		{Method: "get", Path: "/api/v1/addresses/:id", RpcPath: "user.v1.AddressService/GetAddress"},
+	}, Options{
+		Title:        "test",
+		Version:      "1.0.0",
+		Public:       []string{"shop.v1.ShopService/Login"},
+		ApiKeyScopes: []apikey.ScopeRule{{Name: "orders:read", Methods: []string{"GET"}, Paths: []string{"/api/v1/orders/*"}}},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return api
+}
+
+func TestOpenAPI(t *testing.T) {
+	doc := buildTestAPI(t).OpenAPI()
+
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %s, want 3.1.0", doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/api/v1/orders/{id}"]["options"]; ok {
+		t.Error("options mapping should be skipped")
+	}
+
+	get := doc.Paths["/api/v1/orders/{id}"]["get"]
+	if get == nil {
+		t.Fatal("GET /api/v1/orders/{id} missing")
+	}
+	if get.Summary != "获取订单" || get.Description != "只返回当前用户的订单" {
+		t.Errorf("summary/description = %q/%q", get.Summary, get.Description)
+	}
+	var params []string
+	for _, p := range get.Parameters {
+		params = append(params, p.In+":"+p.Name)
+	}
+	// 消息类型字段不能作为查询参数
+	if got := strings.Join(params, ","); got != "path:id,query:order_no,query:sku_ids" {
+		t.Errorf("parameters = %s", got)
+	}
+	if get.Security == nil || len(*get.Security) != 2 || len(get.ApiKeyScopes) != 1 {
+		t.Errorf("GetOrder should accept bearer or api key, got %+v scopes=%v", get.Security, get.ApiKeyScopes)
+	}
+
+	login := doc.Paths["/api/v1/login"]["post"]
+	if login.Security == nil || len(*login.Security) != 0 {
+		t.Errorf("public operation should have empty security, got %+v", login.Security)
+	}
+	if login.Summary != "Login" {
+		t.Errorf("summary without comment should fall back to rpc name, got %q", login.Summary)
+	}
+
+	schemas := doc.Components.Schemas
+	for _, name := range []string{"Order", "OrderItem", "OrderStatus", "ShopV1Address", "UserV1Address", "ErrorResponse"} {
+		if schemas[name] == nil {
+			t.Errorf("schema %s missing", name)
+		}
+	}
+	if schemas["LoginRequest"] == nil {
+		t.Error("request schema of body operation missing")
+	}
+
+	order := schemas["Order"]
+	if order.Properties["orderNo"].Examples[0] != "202401010001" {
+		t.Errorf("orderNo example = %v", order.Properties["orderNo"].Examples)
+	}
+	if got := order.Properties["id"].Type; !equalJSON(got, []string{"string", "integer"}) {
+		t.Errorf("int64 type = %v", got)
+	}
+	if order.Properties["items"].Items.Ref != "#/components/schemas/OrderItem" {
+		t.Errorf("items ref = %s", order.Properties["items"].Items.Ref)
+	}
+	if order.Properties["extra"].AdditionalProperties.(*Schema).Type != "string" {
+		t.Error("map should use additionalProperties")
+	}
+	if len(order.AllOf) != 1 || len(order.AllOf[0].OneOf) != 3 {
+		t.Errorf("oneof payer should produce one oneOf group with 3 branches, got %+v", order.AllOf)
+	}
+	if status := schemas["OrderStatus"]; len(status.Enum) != 2 || !strings.Contains(status.Description, "已支付") {
+		t.Errorf("enum schema = %+v", status)
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	ts := string(buildTestAPI(t).TypeScript("@/api/client"))
+
+	for _, want := range []string{
+		`import { apiClient } from "@/api/client";`,
+		"export interface Order {\n",
+		"  id?: Int64;\n",
+		"  items?: OrderItem[];\n",
+		"  extra?: Record<string, string>;\n",
+		`export type OrderStatus = "ORDER_STATUS_UNSPECIFIED" | "ORDER_STATUS_PAID";`,
+		"export interface ShopV1Address {",
+		"export async function getOrder(req: GetOrderRequest, config?: AxiosRequestConfig): Promise<GetOrderResponse> {",
+		"apiClient.get<GetOrderResponse>(`/api/v1/orders/${pathParam(req.id)}`, {",
+		"      sku_ids: req.skuIds,\n",
+		"export async function createOrder(req: CreateOrderRequest = {}, config?: AxiosRequestConfig)",
+		`apiClient.post<GetOrderResponse>("/api/v1/orders", req, config);`,
+	} {
+		if !strings.Contains(ts, want) {
+			t.Errorf("generated TypeScript missing %q", want)
+		}
+	}
+}
+
+func TestBuildUnknownRPC(t *testing.T) {
+	_, err := Build(nil, []Route{{Method: "get", Path: "/x", RpcPath: "x.v1.X/Missing"}}, Options{})
+	if err == nil {
+		t.Fatal("Build() should fail for unknown rpc")
+	}
+}
+
+func equalJSON(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
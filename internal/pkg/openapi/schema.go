@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Schema OpenAPI 3.1 Schema（JSON Schema 2020-12 子集）
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // string 或 []string
+	Format               string             `json:"format,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"` // *Schema 或 bool
+	Required             []string           `json:"required,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+}
+
+// int64Schema protojson 中 64 位整数以字符串输出，请求中字符串和数字都接受
+func int64Schema(format string) *Schema {
+	return &Schema{Type: []string{"string", "integer"}, Format: format}
+}
+
+// wellKnownSchema google.protobuf 常用类型的 JSON 表示，非内置类型返回 nil
+func wellKnownSchema(name protoreflect.FullName) *Schema {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &Schema{Type: "string", Description: "时长，如 1.5s"}
+	case "google.protobuf.FieldMask":
+		return &Schema{Type: "string", Description: "逗号分隔的字段路径"}
+	case "google.protobuf.Empty":
+		return &Schema{Type: "object"}
+	case "google.protobuf.Struct":
+		return &Schema{Type: "object", AdditionalProperties: true}
+	case "google.protobuf.Value":
+		return &Schema{}
+	case "google.protobuf.ListValue":
+		return &Schema{Type: "array", Items: &Schema{}}
+	case "google.protobuf.Any":
+		return &Schema{Type: "object", Properties: map[string]*Schema{"@type": {Type: "string"}}, AdditionalProperties: true}
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return &Schema{Type: []string{"string", "null"}}
+	case "google.protobuf.BoolValue":
+		return &Schema{Type: []string{"boolean", "null"}}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return &Schema{Type: []string{"integer", "null"}, Format: "int32"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return &Schema{Type: []string{"string", "integer", "null"}, Format: "int64"}
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return &Schema{Type: []string{"number", "null"}}
+	}
+	return nil
+}
+
+// messageSchema 消息的 Schema；oneof 用 oneOf 约束"最多设置其中一个字段"
+func (a *API) messageSchema(md protoreflect.MessageDescriptor) *Schema {
+	s := &Schema{Type: "object", Title: localName(md), Description: comment(md)}
+	fields := md.Fields()
+	if fields.Len() > 0 {
+		s.Properties = make(map[string]*Schema, fields.Len())
+	}
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		s.Properties[fd.JSONName()] = a.fieldSchema(fd)
+	}
+
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if od.IsSynthetic() || od.Fields().Len() < 2 {
+			continue
+		}
+		var choices, each []*Schema
+		for j := 0; j < od.Fields().Len(); j++ {
+			req := &Schema{Required: []string{od.Fields().Get(j).JSONName()}}
+			choices = append(choices, req)
+			each = append(each, req)
+		}
+		// 恰好命中一个分支：只设置了某一个字段，或者一个都没设置
+		choices = append(choices, &Schema{Not: &Schema{AnyOf: each}})
+		s.AllOf = append(s.AllOf, &Schema{Title: string(od.Name()), OneOf: choices})
+	}
+	return s
+}
+
+// enumSchema 枚举的 Schema，取值说明拼进 description
+func (a *API) enumSchema(ed protoreflect.EnumDescriptor) *Schema {
+	s := &Schema{Type: "string", Title: localName(ed)}
+	var doc []string
+	if c := comment(ed); c != "" {
+		doc = append(doc, c)
+	}
+	values := ed.Values()
+	for i := 0; i < values.Len(); i++ {
+		v := values.Get(i)
+		s.Enum = append(s.Enum, string(v.Name()))
+		if c := comment(v); c != "" {
+			doc = append(doc, "- "+string(v.Name())+": "+strings.ReplaceAll(c, "\n", " "))
+		}
+	}
+	s.Description = strings.Join(doc, "\n")
+	return s
+}
+
+// fieldSchema 字段的 Schema（含 repeated / map）
+func (a *API) fieldSchema(fd protoreflect.FieldDescriptor) *Schema {
+	var s *Schema
+	switch {
+	case fd.IsMap():
+		s = &Schema{Type: "object", AdditionalProperties: a.valueSchema(fd.MapValue())}
+	case fd.IsList():
+		s = &Schema{Type: "array", Items: a.valueSchema(fd)}
+	default:
+		s = a.valueSchema(fd)
+	}
+
+	desc := comment(fd)
+	if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+		desc = strings.TrimSpace(desc + "\n（oneof " + string(od.Name()) + "，与同组字段互斥）")
+	}
+	if desc != "" {
+		s.Description = desc
+	}
+	if fieldDeprecated(fd) {
+		s.Deprecated = true
+	}
+	if !fd.IsMap() {
+		if ex := example(fd, desc); ex != nil {
+			if fd.IsList() {
+				ex = []any{ex}
+			}
+			s.Examples = []any{ex}
+		}
+	}
+	return s
+}
+
+// valueSchema 单个值的 Schema（忽略 repeated）
+func (a *API) valueSchema(fd protoreflect.FieldDescriptor) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return int64Schema("int64")
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return int64Schema("uint64")
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.StringKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		return &Schema{Ref: schemaRef(a.typeName(fd.Enum()))}
+	default:
+		if wk := wellKnownSchema(fd.Message().FullName()); wk != nil {
+			return wk
+		}
+		return &Schema{Ref: schemaRef(a.typeName(fd.Message()))}
+	}
+}
+
+func schemaRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// fieldDeprecated 字段是否标记了 [deprecated = true]
+func fieldDeprecated(fd protoreflect.FieldDescriptor) bool {
+	type deprecated interface{ GetDeprecated() bool }
+	d, ok := fd.Options().(deprecated)
+	return ok && d.GetDeprecated()
+}
+
+// exampleInComment 注释中显式给出的示例，如 "// 订单号，示例：202401010001"
+var exampleInComment = regexp.MustCompile(`(?:示例|例如|example)[:：]\s*([^\s，,；;）)]+)`)
+
+// example 字段示例值：优先取注释中的示例，否则按字段名推断常见字段
+func example(fd protoreflect.FieldDescriptor, desc string) any {
+	if m := exampleInComment.FindStringSubmatch(desc); m != nil {
+		return typedExample(fd, m[1])
+	}
+
+	name := string(fd.Name())
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		switch {
+		case strings.Contains(name, "phone"):
+			return "13800138000"
+		case strings.Contains(name, "email"):
+			return "user@example.com"
+		case name == "password" || strings.HasSuffix(name, "_password"):
+			return "Passw0rd!"
+		case name == "username":
+			return "zhangsan"
+		case strings.HasSuffix(name, "_at") || strings.HasSuffix(name, "_time") || name == "birthday":
+			return "2024-01-01 12:00:00"
+		case strings.Contains(name, "image") || strings.Contains(name, "avatar") || strings.HasSuffix(name, "_url") || name == "icon":
+			return "/uploads/image/example.jpg"
+		case strings.HasSuffix(name, "_no"):
+			return "202401010001"
+		case strings.Contains(name, "price") || strings.Contains(name, "amount"):
+			return "99.00"
+		}
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Uint32Kind, protoreflect.Uint64Kind,
+		protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		switch {
+		case name == "page":
+			return typedExample(fd, "1")
+		case name == "page_size" || name == "limit":
+			return typedExample(fd, "10")
+		case name == "id" || strings.HasSuffix(name, "_id"):
+			return typedExample(fd, "1")
+		case name == "quantity":
+			return typedExample(fd, "1")
+		}
+	}
+	return nil
+}
+
+// typedExample 按字段类型转换示例值（64 位整数按 protojson 输出为字符串）
+func typedExample(fd protoreflect.FieldDescriptor, raw string) any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return n
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return nil
+	}
+	return raw
+}
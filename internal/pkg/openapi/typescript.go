@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TypeScript 生成基于 axios 的类型化客户端；clientImport 为导出 apiClient 的模块，如 @/api/client
+func (a *API) TypeScript(clientImport string) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by cmd/generate-swagger. DO NOT EDIT.\n")
+	b.WriteString("// 由 api/**/*.proto 和 configs/dev/gateway.yaml 生成，修改后执行 make swagger 重新生成。\n\n")
+	b.WriteString("import type { AxiosRequestConfig } from \"axios\";\n")
+	fmt.Fprintf(&b, "import { apiClient } from %q;\n\n", clientImport)
+	b.WriteString("/** 64 位整数：响应中按 protojson 规则为字符串，请求中字符串和数字均可 */\n")
+	b.WriteString("export type Int64 = string | number;\n\n")
+	b.WriteString("function pathParam(value: unknown): string {\n")
+	b.WriteString("  return encodeURIComponent(String(value ?? \"\"));\n")
+	b.WriteString("}\n")
+
+	for _, d := range a.types {
+		b.WriteString("\n")
+		switch d := d.(type) {
+		case protoreflect.MessageDescriptor:
+			a.writeInterface(&b, d)
+		case protoreflect.EnumDescriptor:
+			writeDoc(&b, "", comment(d))
+			values := make([]string, 0, d.Values().Len())
+			for i := 0; i < d.Values().Len(); i++ {
+				values = append(values, fmt.Sprintf("%q", d.Values().Get(i).Name()))
+			}
+			fmt.Fprintf(&b, "export type %s = %s;\n", a.typeName(d), strings.Join(values, " | "))
+		}
+	}
+
+	for _, op := range a.operations {
+		b.WriteString("\n")
+		a.writeFunction(&b, op)
+	}
+	return b.Bytes()
+}
+
+// writeInterface 消息生成 interface，所有字段可选（proto3 字段都可以不传）
+func (a *API) writeInterface(b *bytes.Buffer, md protoreflect.MessageDescriptor) {
+	writeDoc(b, "", comment(md))
+	fields := md.Fields()
+	if fields.Len() == 0 {
+		fmt.Fprintf(b, "export type %s = Record<string, never>;\n", a.typeName(md))
+		return
+	}
+	fmt.Fprintf(b, "export interface %s {\n", a.typeName(md))
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		doc := comment(fd)
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+			doc = strings.TrimSpace(doc + "\n（oneof " + string(od.Name()) + "，与同组字段互斥）")
+		}
+		writeDoc(b, "  ", doc)
+		fmt.Fprintf(b, "  %s?: %s;\n", fd.JSONName(), a.tsFieldType(fd))
+	}
+	b.WriteString("}\n")
+}
+
+// writeFunction 接口生成请求函数：路径参数从请求对象中取，GET/DELETE 其余标量字段作为查询参数
+func (a *API) writeFunction(b *bytes.Buffer, op *operation) {
+	summary, description := summaryOf(op.method)
+	doc := summary
+	if description != "" {
+		doc += "\n\n" + description
+	}
+	doc += fmt.Sprintf("\n\n`%s %s` → %s", strings.ToUpper(op.httpMethod), op.path, op.rpcPath)
+	if op.public {
+		doc += "（免登录）"
+	}
+	writeDoc(b, "", doc)
+
+	reqType := a.typeName(op.method.Input())
+	respType := a.typeName(op.method.Output())
+	reqParam := "req: " + reqType
+	if len(op.pathParams) == 0 {
+		reqParam += " = {}"
+	}
+	fmt.Fprintf(b, "export async function %s(%s, config?: AxiosRequestConfig): Promise<%s> {\n", op.id, reqParam, respType)
+
+	url := fmt.Sprintf("%q", op.path)
+	if len(op.pathParams) > 0 {
+		url = op.path
+		for _, p := range op.pathParams {
+			value := "undefined"
+			if p.field != nil {
+				value = "req." + p.field.JSONName()
+			}
+			url = strings.Replace(url, "{"+p.name+"}", "${pathParam("+value+")}", 1)
+		}
+		url = "`" + url + "`"
+	}
+
+	switch {
+	case op.hasBody:
+		fmt.Fprintf(b, "  const { data } = await apiClient.%s<%s>(%s, req, config);\n", op.httpMethod, respType, url)
+	case len(op.queryParams) > 0:
+		fmt.Fprintf(b, "  const { data } = await apiClient.%s<%s>(%s, {\n", op.httpMethod, respType, url)
+		b.WriteString("    ...config,\n")
+		b.WriteString("    params: {\n")
+		for _, p := range op.queryParams {
+			fmt.Fprintf(b, "      %s: req.%s,\n", p.name, p.field.JSONName())
+		}
+		b.WriteString("    },\n")
+		b.WriteString("    paramsSerializer: { indexes: null },\n")
+		b.WriteString("  });\n")
+	default:
+		fmt.Fprintf(b, "  const { data } = await apiClient.%s<%s>(%s, config);\n", op.httpMethod, respType, url)
+	}
+	b.WriteString("  return data;\n")
+	b.WriteString("}\n")
+}
+
+// tsFieldType 字段的 TypeScript 类型
+func (a *API) tsFieldType(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.IsMap():
+		return fmt.Sprintf("Record<string, %s>", a.tsValueType(fd.MapValue()))
+	case fd.IsList():
+		t := a.tsValueType(fd)
+		if strings.Contains(t, " ") {
+			t = "(" + t + ")"
+		}
+		return t + "[]"
+	default:
+		return a.tsValueType(fd)
+	}
+}
+
+// tsValueType 单个值的 TypeScript 类型，与 Schema 中的 JSON 表示对应
+func (a *API) tsValueType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "Int64"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "number"
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return "string"
+	case protoreflect.EnumKind:
+		return a.typeName(fd.Enum())
+	default:
+		if wk := wellKnownTSType(fd.Message().FullName()); wk != "" {
+			return wk
+		}
+		return a.typeName(fd.Message())
+	}
+}
+
+// wellKnownTSType google.protobuf 常用类型对应的 TypeScript 类型
+func wellKnownTSType(name protoreflect.FullName) string {
+	switch name {
+	case "google.protobuf.Timestamp", "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return "string"
+	case "google.protobuf.Empty":
+		return "Record<string, never>"
+	case "google.protobuf.Struct", "google.protobuf.Any":
+		return "Record<string, unknown>"
+	case "google.protobuf.Value":
+		return "unknown"
+	case "google.protobuf.ListValue":
+		return "unknown[]"
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return "string | null"
+	case "google.protobuf.BoolValue":
+		return "boolean | null"
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return "Int64 | null"
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return "number | null"
+	}
+	return ""
+}
+
+// writeDoc 输出 JSDoc 注释
+func writeDoc(b *bytes.Buffer, indent, doc string) {
+	doc = strings.TrimSpace(strings.ReplaceAll(doc, "*/", "*\\/"))
+	if doc == "" {
+		return
+	}
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, l := range lines {
+		if l == "" {
+			fmt.Fprintf(b, "%s *\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s * %s\n", indent, l)
+		}
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
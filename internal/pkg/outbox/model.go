@@ -10,8 +10,9 @@ const (
 
 // 常用事件类型（供 search-service 消费）
 const (
-	EventProductUpserted = "product.upserted"
-	EventProductDeleted  = "product.deleted"
+	EventProductUpserted     = "product.upserted"
+	EventProductDeleted      = "product.deleted"
+	EventProductPriceChanged = "product.price.changed" // payload 带 sku_id（0 表示商品本身）和改价前后的价格
 )
 
 // Event Outbox 事件（同事务写入，异步投递到 Kafka）
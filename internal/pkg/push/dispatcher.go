@@ -0,0 +1,88 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/mq"
+)
+
+// Topics 推送网关订阅的 Kafka topic
+var Topics = []string{
+	mq.TopicOrderCreated,
+	mq.TopicOrderPaid,
+	mq.TopicOrderCancelled,
+	mq.TopicOrderCompleted,
+	mq.TopicPaymentSuccess,
+	mq.TopicPaymentFailed,
+	mq.TopicPaymentRefunded,
+	mq.TopicLogisticsUpdated,
+	mq.TopicLogisticsDelivered,
+	mq.TopicMessageCreated,
+}
+
+// Dispatcher 把 Kafka 业务事件转换为推送事件
+type Dispatcher struct {
+	broker *Broker
+}
+
+// NewDispatcher 创建事件分发器
+func NewDispatcher(broker *Broker) *Dispatcher {
+	return &Dispatcher{broker: broker}
+}
+
+// Register 在消费者上注册全部推送 topic
+func (d *Dispatcher) Register(consumer *mq.Consumer) {
+	for _, topic := range Topics {
+		consumer.RegisterHandler(topic, d.Handle)
+	}
+}
+
+// Handle 处理一条 Kafka 消息：取出 user_id 后发布给该用户；没有 user_id 的消息直接忽略
+func (d *Dispatcher) Handle(ctx context.Context, msg *mq.Message) error {
+	userID := userIDFromData(msg.Data)
+	if userID == 0 {
+		return nil
+	}
+	if err := d.broker.Publish(ctx, userID, msg.EventType, msg.Data); err != nil {
+		// 推送失败不重试：客户端可以通过查询接口拿到最终状态
+		logx.Errorf("push: publish %s to user %d failed: %v", msg.EventType, userID, err)
+	}
+	return nil
+}
+
+// userIDFromData 兼容 user_id 在顶层或 outbox 的 payload 中
+func userIDFromData(data map[string]interface{}) uint64 {
+	if id := toUint64(data["user_id"]); id > 0 {
+		return id
+	}
+	if payload, ok := data["payload"].(map[string]interface{}); ok {
+		return toUint64(payload["user_id"])
+	}
+	return 0
+}
+
+func toUint64(v interface{}) uint64 {
+	switch n := v.(type) {
+	case float64:
+		if n > 0 {
+			return uint64(n)
+		}
+	case json.Number:
+		id, _ := strconv.ParseUint(n.String(), 10, 64)
+		return id
+	case string:
+		id, _ := strconv.ParseUint(n, 10, 64)
+		return id
+	case int64:
+		if n > 0 {
+			return uint64(n)
+		}
+	case uint64:
+		return n
+	}
+	return 0
+}
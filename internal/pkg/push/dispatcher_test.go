@@ -0,0 +1,107 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/mq"
+)
+
+func newTestBroker(t *testing.T) *Broker {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewBroker(client, NewHub(), Config{})
+}
+
+// viaKafka 模拟消息经过 Kafka 序列化后被消费者解析
+func viaKafka(t *testing.T, msg *mq.Message) *mq.Message {
+	t.Helper()
+	raw, err := msg.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded mq.Message
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return &decoded
+}
+
+func TestDispatcherRoutesEachTopicToUser(t *testing.T) {
+	broker := newTestBroker(t)
+	d := NewDispatcher(broker)
+	ctx := context.Background()
+
+	// 与各服务实际发布的载荷保持一致：user_id 在顶层
+	payloads := map[string]map[string]interface{}{
+		mq.TopicOrderCreated:       {"order_no": "O1", "total_amount": 99.5},
+		mq.TopicOrderPaid:          {"order_no": "O1", "payment_no": "P1"},
+		mq.TopicOrderCancelled:     {"order_no": "O1", "reason": "支付失败"},
+		mq.TopicOrderCompleted:     {"order_no": "O1"},
+		mq.TopicPaymentSuccess:     {"payment_no": "P1", "pay_amount": 99.5},
+		mq.TopicPaymentFailed:      {"payment_no": "P1", "status": 2},
+		mq.TopicPaymentRefunded:    {"refund_no": "R1"},
+		mq.TopicLogisticsUpdated:   {"logistics_no": "L1", "status": 1},
+		mq.TopicLogisticsDelivered: {"logistics_no": "L1", "status": 3},
+		mq.TopicMessageCreated:     {"title": "hi"},
+	}
+	if len(payloads) != len(Topics) {
+		t.Fatalf("test covers %d topics, dispatcher subscribes %d", len(payloads), len(Topics))
+	}
+
+	users := make(map[uint64]string, len(Topics))
+	for i, topic := range Topics {
+		data, ok := payloads[topic]
+		if !ok {
+			t.Fatalf("no test payload for topic %s", topic)
+		}
+		userID := uint64(100 + i)
+		data["user_id"] = userID
+		users[userID] = topic
+		if err := d.Handle(ctx, viaKafka(t, mq.NewMessage(topic, data))); err != nil {
+			t.Fatalf("handle %s: %v", topic, err)
+		}
+	}
+
+	for userID, topic := range users {
+		events, err := broker.Replay(ctx, userID, "0-0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || events[0].Type != topic {
+			t.Fatalf("user %d should receive exactly %s, got %+v", userID, topic, events)
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal(events[0].Data, &data); err != nil || toUint64(data["user_id"]) != userID {
+			t.Fatalf("event data for %s not forwarded: %s", topic, events[0].Data)
+		}
+	}
+}
+
+func TestDispatcherUserIDLocations(t *testing.T) {
+	broker := newTestBroker(t)
+	d := NewDispatcher(broker)
+	ctx := context.Background()
+
+	// outbox 事件的 user_id 在 payload 中
+	outboxMsg := mq.NewMessage(mq.TopicOrderPaid, map[string]interface{}{
+		"aggregate_id": 1,
+		"payload":      map[string]interface{}{"user_id": "42"},
+	})
+	_ = d.Handle(ctx, viaKafka(t, outboxMsg))
+	if events, _ := broker.Replay(ctx, 42, "0-0"); len(events) != 1 {
+		t.Fatalf("nested user_id should be delivered, got %d events", len(events))
+	}
+
+	// 没有 user_id 的消息直接忽略
+	_ = d.Handle(ctx, viaKafka(t, mq.NewMessage(mq.TopicOrderCancelled, map[string]interface{}{"order_no": "O1"})))
+	if events, _ := broker.Replay(ctx, 0, "0-0"); len(events) != 0 {
+		t.Fatalf("message without user_id must be dropped, got %d events", len(events))
+	}
+}
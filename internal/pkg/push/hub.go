@@ -0,0 +1,83 @@
+package push
+
+import (
+	"sync"
+)
+
+// Hub 本副本上的在线连接，按用户分组（一个用户可以有多台设备同时在线）
+type Hub struct {
+	mu    sync.RWMutex
+	conns map[uint64]map[*Conn]struct{}
+}
+
+// NewHub 创建连接管理器
+func NewHub() *Hub {
+	return &Hub{conns: make(map[uint64]map[*Conn]struct{})}
+}
+
+// Conn 单个设备连接
+type Conn struct {
+	UserID uint64
+	Events chan *Event
+
+	mu     sync.Mutex
+	lastID string // 已发送的最大事件 ID，回放与实时推送重叠时去重
+}
+
+// Register 注册连接，buffer 为待发送事件的缓冲大小
+func (h *Hub) Register(userID uint64, buffer int) *Conn {
+	c := &Conn{UserID: userID, Events: make(chan *Event, buffer)}
+	h.mu.Lock()
+	if h.conns[userID] == nil {
+		h.conns[userID] = make(map[*Conn]struct{})
+	}
+	h.conns[userID][c] = struct{}{}
+	h.mu.Unlock()
+	return c
+}
+
+// Unregister 注销连接
+func (h *Hub) Unregister(c *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if set := h.conns[c.UserID]; set != nil {
+		delete(set, c)
+		if len(set) == 0 {
+			delete(h.conns, c.UserID)
+		}
+	}
+}
+
+// Deliver 投递给该用户在本副本上的所有连接；连接缓冲已满时丢弃，客户端可通过 Last-Event-ID 补齐
+func (h *Hub) Deliver(evt *Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.conns[evt.UserID] {
+		select {
+		case c.Events <- evt:
+		default:
+		}
+	}
+}
+
+// Online 本副本在线连接数
+func (h *Hub) Online() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	n := 0
+	for _, set := range h.conns {
+		n += len(set)
+	}
+	return n
+}
+
+// MarkSent 记录已发送的事件，返回 false 表示该事件已发送过（应跳过）
+func (c *Conn) MarkSent(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastID != "" && compareEventID(id, c.lastID) <= 0 {
+		return false
+	}
+	c.lastID = id
+	return true
+}
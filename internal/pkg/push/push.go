@@ -0,0 +1,168 @@
+// Package push 服务端推送：把订单、支付、物流、站内消息等事件实时推送给用户在线的设备。
+//
+// 多副本部署时，事件先写入用户的 Redis Stream（用于断线续传），再通过 Redis pub/sub 广播，
+// 每个副本只投递给连接在自己身上的设备；客户端重连时带上 Last-Event-ID 即可补齐错过的事件。
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/cache"
+)
+
+// channelEvents 所有副本订阅的广播频道
+const channelEvents = "push:events"
+
+// Event 推送事件
+type Event struct {
+	ID     string          `json:"id"` // Redis Stream ID，作为 SSE 的 id，客户端重连时原样带回
+	UserID uint64          `json:"user_id"`
+	Type   string          `json:"type"` // 事件类型，与 Kafka topic 一致，如 order.created
+	Data   json.RawMessage `json:"data"`
+	Time   int64           `json:"time"`
+}
+
+// Config 推送配置
+type Config struct {
+	StreamMaxLen int64         // 每个用户保留的最近事件数，0 表示默认 200
+	StreamTTL    time.Duration // 用户事件流过期时间，0 表示默认 24h
+}
+
+// Broker 推送事件的发布与订阅
+type Broker struct {
+	client *redis.Client
+	hub    *Hub
+	cfg    Config
+}
+
+// NewBroker 创建推送 Broker
+func NewBroker(client *redis.Client, hub *Hub, cfg Config) *Broker {
+	if cfg.StreamMaxLen <= 0 {
+		cfg.StreamMaxLen = 200
+	}
+	if cfg.StreamTTL <= 0 {
+		cfg.StreamTTL = 24 * time.Hour
+	}
+	return &Broker{client: client, hub: hub, cfg: cfg}
+}
+
+// Publish 发布事件：写入用户事件流后广播给所有副本
+func (b *Broker) Publish(ctx context.Context, userID uint64, eventType string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+
+	key := streamKey(userID)
+	id, err := b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: b.cfg.StreamMaxLen,
+		Approx: true,
+		Values: map[string]any{"type": eventType, "data": string(raw), "time": now},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("push: xadd %s: %w", key, err)
+	}
+	_ = b.client.Expire(ctx, key, b.cfg.StreamTTL).Err()
+
+	msg, err := json.Marshal(&Event{ID: id, UserID: userID, Type: eventType, Data: raw, Time: now})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, channelEvents, msg).Err()
+}
+
+// Run 订阅广播频道，把事件投递给本副本上的连接；ctx 取消时退出
+func (b *Broker) Run(ctx context.Context) {
+	sub := b.client.Subscribe(ctx, channelEvents)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-ch:
+			if !ok {
+				return
+			}
+			var evt Event
+			if err := json.Unmarshal([]byte(m.Payload), &evt); err != nil {
+				logx.Errorf("push: decode broadcast failed: %v", err)
+				continue
+			}
+			b.hub.Deliver(&evt)
+		}
+	}
+}
+
+// Replay 读取 lastID 之后的事件（断线续传），lastID 为空时不回放
+func (b *Broker) Replay(ctx context.Context, userID uint64, lastID string) ([]*Event, error) {
+	if lastID == "" {
+		return nil, nil
+	}
+	msgs, err := b.client.XRange(ctx, streamKey(userID), "("+lastID, "+").Result()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*Event, 0, len(msgs))
+	for _, m := range msgs {
+		evt := &Event{ID: m.ID, UserID: userID}
+		evt.Type, _ = m.Values["type"].(string)
+		if data, ok := m.Values["data"].(string); ok {
+			evt.Data = json.RawMessage(data)
+		}
+		if t, ok := m.Values["time"].(string); ok {
+			evt.Time, _ = strconv.ParseInt(t, 10, 64)
+		}
+		events = append(events, evt)
+	}
+	return events, nil
+}
+
+func streamKey(userID uint64) string {
+	return cache.BuildKey(cache.KeyPrefixPushStream, userID)
+}
+
+// ValidEventID 校验客户端带回的事件 ID 格式（毫秒时间戳-序号）
+func ValidEventID(id string) bool {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return false
+	}
+	_, err1 := strconv.ParseUint(ms, 10, 64)
+	_, err2 := strconv.ParseUint(seq, 10, 64)
+	return err1 == nil && err2 == nil
+}
+
+// compareEventID 比较两个 Stream ID，返回 -1 / 0 / 1
+func compareEventID(a, b string) int {
+	am, as, _ := strings.Cut(a, "-")
+	bm, bs, _ := strings.Cut(b, "-")
+	ams, _ := strconv.ParseUint(am, 10, 64)
+	bms, _ := strconv.ParseUint(bm, 10, 64)
+	if ams != bms {
+		if ams < bms {
+			return -1
+		}
+		return 1
+	}
+	aseq, _ := strconv.ParseUint(as, 10, 64)
+	bseq, _ := strconv.ParseUint(bs, 10, 64)
+	switch {
+	case aseq < bseq:
+		return -1
+	case aseq > bseq:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+package rbac
+
+// 权限点。角色和权限的对应关系保存在 user-service 的 role_permission 表中，
+// 签发访问令牌时写入 JWT，各服务按 MethodPermissions 校验，不需要回查 user-service。
+const (
+	PermAll             = "*" // 全部权限（超级管理员）
+	PermUserRead        = "user:read"
+	PermUserWrite       = "user:write"
+	PermRoleManage      = "role:manage"
+	PermProductWrite    = "product:write"
+	PermProductReview   = "product:review"
+	PermInventoryManage = "inventory:manage"
+	PermOrderShip       = "order:ship"
+	PermSeckillWrite    = "seckill:write"
+	PermPrivacyProcess  = "privacy:process" // 各服务的个人数据导出和删除，只签发给编排个人数据请求的 user-service
+)
+
+// 内置角色
+const (
+	RoleSuperAdmin = "super_admin"
+	RoleOperator   = "operator"
+)
+
+// Permission 权限点定义
+type Permission struct {
+	Code string
+	Name string
+}
+
+// Permissions 全部权限点，bootstrap-admin 启动时同步到 permission 表
+var Permissions = []Permission{
+	{Code: PermAll, Name: "全部权限"},
+	{Code: PermUserRead, Name: "查看用户"},
+	{Code: PermUserWrite, Name: "管理用户"},
+	{Code: PermRoleManage, Name: "分配角色"},
+	{Code: PermProductWrite, Name: "管理商品、SKU、类目及属性模板、品牌和 Banner"},
+	{Code: PermProductReview, Name: "审核商品"},
+	{Code: PermInventoryManage, Name: "入库"},
+	{Code: PermOrderShip, Name: "订单发货"},
+	{Code: PermSeckillWrite, Name: "管理秒杀活动"},
+	{Code: PermPrivacyProcess, Name: "导出和删除各服务中的个人数据（内部调用）"},
+}
+
+// Role 角色定义
+type Role struct {
+	Code        string
+	Name        string
+	Permissions []string
+}
+
+// BuiltinRoles 内置角色，bootstrap-admin 启动时同步（会覆盖内置角色的权限）
+var BuiltinRoles = []Role{
+	{Code: RoleSuperAdmin, Name: "超级管理员", Permissions: []string{PermAll}},
+	{Code: RoleOperator, Name: "运营", Permissions: []string{
+		PermUserRead, PermProductWrite, PermInventoryManage, PermOrderShip, PermSeckillWrite,
+	}},
+}
+
+// MethodPermissions gRPC 方法需要的权限，未列出的方法不做权限校验。
+// 各服务共用这一张表，RequirePermissionInterceptor 只会匹配到本服务的方法。
+var MethodPermissions = map[string]string{
+	"/user.v1.UserService/ListUsers":            PermUserRead,
+	"/user.v1.UserService/DeleteUser":           PermUserWrite,
+	"/user.v1.UserService/UnlockUser":           PermUserWrite,
+	"/user.v1.UserService/ListUserSessions":     PermUserRead,
+	"/user.v1.UserService/RevokeUserSession":    PermUserWrite,
+	"/user.v1.UserService/EvaluateMemberLevels": PermUserWrite,
+	"/user.v1.UserService/ListDataRequests":     PermUserRead,
+	"/user.v1.UserService/RetryDataRequest":     PermUserWrite,
+
+	"/user.v1.RoleService/ListRoles":      PermRoleManage,
+	"/user.v1.RoleService/GetUserRoles":   PermRoleManage,
+	"/user.v1.RoleService/AssignUserRole": PermRoleManage,
+	"/user.v1.RoleService/RemoveUserRole": PermRoleManage,
+
+	"/product.v1.ProductService/CreateProduct":  PermProductWrite,
+	"/product.v1.ProductService/UpdateProduct":  PermProductWrite,
+	"/product.v1.ProductService/DeleteProduct":  PermProductWrite,
+	"/product.v1.ProductService/CreateSku":      PermProductWrite,
+	"/product.v1.ProductService/UpdateSku":      PermProductWrite,
+	"/product.v1.ProductService/DeleteSku":      PermProductWrite,
+	"/product.v1.ProductService/CreateCategory": PermProductWrite,
+	"/product.v1.ProductService/UpdateCategory": PermProductWrite,
+	"/product.v1.ProductService/DeleteCategory": PermProductWrite,
+	"/product.v1.ProductService/CreateBanner":   PermProductWrite,
+	"/product.v1.ProductService/UpdateBanner":   PermProductWrite,
+	"/product.v1.ProductService/DeleteBanner":   PermProductWrite,
+	"/product.v1.ProductService/CreateBrand":    PermProductWrite,
+	"/product.v1.ProductService/UpdateBrand":    PermProductWrite,
+	"/product.v1.ProductService/DeleteBrand":    PermProductWrite,
+	"/product.v1.ProductService/CreateAttr":     PermProductWrite,
+	"/product.v1.ProductService/UpdateAttr":     PermProductWrite,
+	"/product.v1.ProductService/DeleteAttr":     PermProductWrite,
+	"/product.v1.ProductService/GenerateSkus":   PermProductWrite,
+
+	"/product.v1.ProductService/SubmitProductRevision":  PermProductWrite,
+	"/product.v1.ProductService/ListProductRevisions":   PermProductWrite,
+	"/product.v1.ProductService/ListProductAuditLogs":   PermProductWrite,
+	"/product.v1.ProductService/ApproveProductRevision": PermProductReview,
+	"/product.v1.ProductService/RejectProductRevision":  PermProductReview,
+
+	"/product.v1.ProductService/CreateProductSchedule": PermProductWrite,
+	"/product.v1.ProductService/ListProductSchedules":  PermProductWrite,
+	"/product.v1.ProductService/CancelProductSchedule": PermProductWrite,
+	"/product.v1.ProductService/ImportCatalog":         PermProductWrite,
+	"/product.v1.ProductService/ExportCatalog":         PermProductWrite,
+	"/product.v1.ProductService/GetCatalogJob":         PermProductWrite,
+	"/product.v1.ProductService/ListCatalogJobs":       PermProductWrite,
+
+	"/inventory.v1.InventoryService/StockIn": PermInventoryManage,
+
+	"/order.v1.OrderService/ShipOrder": PermOrderShip,
+
+	"/seckill.v1.SeckillService/CreateSeckillActivity": PermSeckillWrite,
+	"/seckill.v1.SeckillService/UpdateSeckillActivity": PermSeckillWrite,
+	"/seckill.v1.SeckillService/DeleteSeckillActivity": PermSeckillWrite,
+
+	"/privacy.v1.DataSubjectService/ExportUserData": PermPrivacyProcess,
+	"/privacy.v1.DataSubjectService/EraseUserData":  PermPrivacyProcess,
+}
+
+// HasPermission granted 中是否包含 perm（PermAll 包含所有权限）
+func HasPermission(granted []string, perm string) bool {
+	for _, p := range granted {
+		if p == perm || p == PermAll {
+			return true
+		}
+	}
+	return false
+}
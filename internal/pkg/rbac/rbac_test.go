@@ -0,0 +1,25 @@
+package rbac
+
+import "testing"
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted []string
+		perm    string
+		want    bool
+	}{
+		{"exact match", []string{PermUserRead, PermProductWrite}, PermProductWrite, true},
+		{"missing permission", []string{PermUserRead}, PermProductWrite, false},
+		{"no permissions", nil, PermUserRead, false},
+		{"wildcard grants everything", []string{PermAll}, PermRoleManage, true},
+		{"prefix is not a match", []string{"product"}, PermProductWrite, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasPermission(tt.granted, tt.perm); got != tt.want {
+				t.Errorf("HasPermission(%v, %q) = %v, want %v", tt.granted, tt.perm, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Package region 全国行政区划（省、地、县三级）数据和地址规范化。
+// 内置数据为 regions.csv（code,name 两列，县级以上行政区划代码），上下级关系由 6 位代码推导：
+// xx0000 为省级，xxyy00 为地级，其余为县级；直辖市的区县挂在 xx0100 下，xx90yy 为省直辖县级单位（按地级处理）。
+// 区划调整后可以用 LoadFile 加载新的 CSV，不需要重新发版。
+package region
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed regions.csv
+var embedded []byte
+
+const (
+	LevelProvince = 1 // 省级
+	LevelCity     = 2 // 地级
+	LevelDistrict = 3 // 县级
+)
+
+// municipalities 直辖市，区县统一挂在 xx0100 下（含重庆 5002xx 的县）
+var municipalities = map[string]bool{"11": true, "12": true, "31": true, "50": true}
+
+// noMainland 港澳台，不适用大陆的手机号和邮编规则
+var noMainland = map[string]bool{"71": true, "81": true, "82": true}
+
+// shortSuffixes 简称匹配时去掉的后缀，长的在前
+var shortSuffixes = []string{
+	"特别行政区", "维吾尔自治区", "壮族自治区", "回族自治区", "自治区", "自治州", "自治县", "自治旗",
+	"地区", "林区", "省", "市", "盟", "区", "县", "旗",
+}
+
+// Region 行政区划
+type Region struct {
+	Code        string
+	Name        string
+	Level       int
+	ParentCode  string
+	HasChildren bool
+}
+
+// Dataset 行政区划数据集，加载后只读，可并发使用
+type Dataset struct {
+	regions  map[string]*Region
+	children map[string][]*Region // key 为上级代码，"" 为省级列表
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultDataset *Dataset
+)
+
+// Default 返回内置数据集（首次调用时解析）
+func Default() *Dataset {
+	defaultOnce.Do(func() {
+		d, err := Parse(bytes.NewReader(embedded))
+		if err != nil {
+			panic("region: 内置行政区划数据无效: " + err.Error())
+		}
+		defaultDataset = d
+	})
+	return defaultDataset
+}
+
+// LoadFile 从 CSV 文件加载数据集，格式同内置的 regions.csv
+func LoadFile(path string) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse 解析 code,name 两列的 CSV，首行为 code,name 表头时跳过
+func Parse(r io.Reader) (*Dataset, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.Comment = '#'
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 && records[0][0] == "code" {
+		records = records[1:]
+	}
+
+	d := &Dataset{
+		regions:  make(map[string]*Region, len(records)),
+		children: make(map[string][]*Region),
+	}
+	for _, rec := range records {
+		code, name := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1])
+		if !isCode(code) || name == "" {
+			return nil, fmt.Errorf("无效的区划记录 %q", strings.Join(rec, ","))
+		}
+		if _, ok := d.regions[code]; ok {
+			return nil, fmt.Errorf("区划代码重复: %s", code)
+		}
+		parent, level := parentOf(code)
+		d.regions[code] = &Region{Code: code, Name: name, Level: level, ParentCode: parent}
+	}
+	for _, reg := range d.regions {
+		if reg.ParentCode != "" {
+			parent, ok := d.regions[reg.ParentCode]
+			if !ok {
+				return nil, fmt.Errorf("区划 %s 的上级 %s 不存在", reg.Code, reg.ParentCode)
+			}
+			parent.HasChildren = true
+		}
+		d.children[reg.ParentCode] = append(d.children[reg.ParentCode], reg)
+	}
+	if len(d.children[""]) == 0 {
+		return nil, errors.New("数据集中没有省级区划")
+	}
+	for _, list := range d.children {
+		sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	}
+	return d, nil
+}
+
+// parentOf 由代码推导上级代码和层级
+func parentOf(code string) (string, int) {
+	switch {
+	case code[2:] == "0000":
+		return "", LevelProvince
+	case code[4:] == "00", code[2:4] == "90":
+		return code[:2] + "0000", LevelCity
+	case municipalities[code[:2]]:
+		return code[:2] + "0100", LevelDistrict
+	default:
+		return code[:4] + "00", LevelDistrict
+	}
+}
+
+// Get 按代码查询
+func (d *Dataset) Get(code string) (Region, bool) {
+	reg, ok := d.regions[code]
+	if !ok {
+		return Region{}, false
+	}
+	return *reg, true
+}
+
+// Children 下级区划列表，parentCode 为空时返回省级；代码不存在时返回 false
+func (d *Dataset) Children(parentCode string) ([]Region, bool) {
+	if parentCode != "" {
+		if _, ok := d.regions[parentCode]; !ok {
+			return nil, false
+		}
+	}
+	list := d.children[parentCode]
+	out := make([]Region, len(list))
+	for i, reg := range list {
+		out[i] = *reg
+	}
+	return out, true
+}
+
+// Address 规范化后的省市区。数据集没有下级的地区（港澳台、东莞市等），下级 Code 为空，Name 保留用户填写的内容
+type Address struct {
+	Province Region
+	City     Region
+	District Region
+}
+
+// Mainland 是否大陆地址（港澳台以外）
+func (a *Address) Mainland() bool {
+	return IsMainland(a.Province.Code)
+}
+
+// IsMainland 区划代码是否属于大陆（港澳台以外）
+func IsMainland(code string) bool {
+	return len(code) < 2 || !noMainland[code[:2]]
+}
+
+// Resolve 校验省市区并规范化为标准名称和代码，每一级可以传名称、简称（如“广东”“深圳”）或 6 位代码。
+// 数据集覆盖到的层级必须逐级匹配，不能跨级或错挂上级
+func (d *Dataset) Resolve(province, city, district string) (*Address, error) {
+	province, city, district = strings.TrimSpace(province), strings.TrimSpace(city), strings.TrimSpace(district)
+	if province == "" {
+		return nil, errors.New("省份不能为空")
+	}
+	p, err := d.match("", province, "省份")
+	if err != nil {
+		return nil, err
+	}
+	addr := &Address{Province: *p}
+	if !p.HasChildren {
+		addr.City = Region{Name: city, Level: LevelCity}
+		addr.District = Region{Name: district, Level: LevelDistrict}
+		return addr, nil
+	}
+
+	if city == "" {
+		return nil, errors.New("城市不能为空")
+	}
+	c, err := d.match(p.Code, city, "城市")
+	if err != nil {
+		return nil, err
+	}
+	addr.City = *c
+	if !c.HasChildren {
+		addr.District = Region{Name: district, Level: LevelDistrict}
+		return addr, nil
+	}
+
+	if district == "" {
+		return nil, errors.New("区县不能为空")
+	}
+	dist, err := d.match(c.Code, district, "区县")
+	if err != nil {
+		return nil, err
+	}
+	addr.District = *dist
+	return addr, nil
+}
+
+// match 在同一上级下按代码、全称、简称依次匹配，简称有多个匹配时要求填写全称
+func (d *Dataset) match(parent, value, label string) (*Region, error) {
+	if isCode(value) {
+		if reg, ok := d.regions[value]; ok && reg.ParentCode == parent {
+			return reg, nil
+		}
+		return nil, fmt.Errorf("%s代码 %s 不存在或不属于所选上级地区", label, value)
+	}
+
+	siblings := d.children[parent]
+	for _, reg := range siblings {
+		if reg.Name == value {
+			return reg, nil
+		}
+	}
+	// 直辖市只有一个地级节点，兼容“市辖区”的写法
+	if value == "市辖区" && len(siblings) == 1 {
+		return siblings[0], nil
+	}
+
+	short := shortName(value)
+	var found *Region
+	for _, reg := range siblings {
+		if short != "" && shortName(reg.Name) == short {
+			if found != nil {
+				return nil, fmt.Errorf("%s“%s”匹配到多个地区，请填写全称", label, value)
+			}
+			found = reg
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%s“%s”不存在或不属于所选上级地区", label, value)
+	}
+	return found, nil
+}
+
+func shortName(name string) string {
+	for _, suffix := range shortSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return name
+}
+
+func isCode(s string) bool {
+	if len(s) != 6 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package region
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultDataset(t *testing.T) {
+	d := Default()
+
+	provinces, _ := d.Children("")
+	if len(provinces) != 34 {
+		t.Fatalf("expected 34 provinces, got %d", len(provinces))
+	}
+	// 直辖市的区县挂在 xx0100 下，省直辖县级单位按地级处理
+	if reg, ok := d.Get("500229"); !ok || reg.ParentCode != "500100" || reg.Level != LevelDistrict {
+		t.Fatalf("unexpected 城口县: %+v", reg)
+	}
+	if reg, ok := d.Get("429004"); !ok || reg.ParentCode != "420000" || reg.Level != LevelCity || reg.HasChildren {
+		t.Fatalf("unexpected 仙桃市: %+v", reg)
+	}
+	if _, ok := d.Children("999999"); ok {
+		t.Fatal("unknown parent should not be found")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	d := Default()
+	cases := []struct {
+		province, city, district string
+		want                     string // 期望的省/市/区代码，空表示期望报错
+	}{
+		{"广东省", "深圳市", "南山区", "440000/440300/440305"},
+		{"广东", "深圳", "南山", "440000/440300/440305"},
+		{"440000", "440300", "440305", "440000/440300/440305"},
+		{"北京市", "市辖区", "朝阳区", "110000/110100/110105"},
+		{"北京", "北京市", "朝阳区", "110000/110100/110105"},
+		{"重庆市", "重庆市", "城口县", "500000/500100/500229"},
+		{"广东省", "东莞市", "长安镇", "440000/441900/"},
+		{"香港特别行政区", "香港", "九龙城区", "810000//"},
+		{"广东省", "深圳市", "朝阳区", ""}, // 区县不属于该市
+		{"广东省", "深圳市", "", ""},
+		{"浙江省", "440300", "南山区", ""},
+		{"火星", "", "", ""},
+	}
+	for _, c := range cases {
+		addr, err := d.Resolve(c.province, c.city, c.district)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("%s/%s/%s: expected error, got %+v", c.province, c.city, c.district, addr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s/%s/%s: %v", c.province, c.city, c.district, err)
+			continue
+		}
+		got := strings.Join([]string{addr.Province.Code, addr.City.Code, addr.District.Code}, "/")
+		if got != c.want {
+			t.Errorf("%s/%s/%s: expected %s, got %s", c.province, c.city, c.district, c.want, got)
+		}
+	}
+
+	addr, _ := d.Resolve("广西", "南宁", "青秀")
+	if addr.Province.Name != "广西壮族自治区" || addr.City.Name != "南宁市" || addr.District.Name != "青秀区" || !addr.Mainland() {
+		t.Fatalf("names not normalized: %+v", addr)
+	}
+	if addr, _ := d.Resolve("台湾", "台北市", ""); addr == nil || addr.Mainland() || addr.City.Name != "台北市" {
+		t.Fatalf("unexpected 台湾 address: %+v", addr)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse(strings.NewReader("code,name\n440000,广东省\n440305,南山区\n")); err == nil {
+		t.Fatal("expected error for district without city")
+	}
+
+	d, err := Parse(strings.NewReader("130000,河北省\n130100,石家庄市\n130121,井陉县\n130122,井陉区\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	// 简称匹配到多个时要求填写全称
+	if _, err := d.Resolve("河北", "石家庄", "井陉"); err == nil {
+		t.Fatal("expected ambiguous short name to fail")
+	}
+	if addr, err := d.Resolve("河北", "石家庄", "井陉区"); err != nil || addr.District.Code != "130122" {
+		t.Fatalf("full name should match: %+v %v", addr, err)
+	}
+}
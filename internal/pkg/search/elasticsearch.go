@@ -13,9 +13,9 @@ import (
 
 // Config Elasticsearch配置
 type Config struct {
-	Addresses []string `json:"required"` // ES节点地址列表
-	Username  string   `json:"optional"`
-	Password  string   `json:"optional"`
+	Addresses []string // ES节点地址列表
+	Username  string   `json:",optional"`
+	Password  string   `json:",optional"`
 }
 
 // Client Elasticsearch客户端
@@ -109,9 +109,15 @@ func (c *Client) IndexDocument(ctx context.Context, indexName string, docID stri
 
 // Search 搜索文档
 func (c *Client) Search(ctx context.Context, indexName string, query map[string]interface{}) ([]map[string]interface{}, int64, error) {
+	documents, total, _, err := c.SearchWithAggs(ctx, indexName, query)
+	return documents, total, err
+}
+
+// SearchWithAggs 搜索文档，同时返回查询中 aggs 的聚合结果（原样返回 ES 的 aggregations 字段）
+func (c *Client) SearchWithAggs(ctx context.Context, indexName string, query map[string]interface{}) ([]map[string]interface{}, int64, map[string]interface{}, error) {
 	queryBody, err := json.Marshal(query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("序列化查询失败: %w", err)
+		return nil, 0, nil, fmt.Errorf("序列化查询失败: %w", err)
 	}
 
 	res, err := c.es.Search(
@@ -121,23 +127,24 @@ func (c *Client) Search(ctx context.Context, indexName string, query map[string]
 		c.es.Search.WithTrackTotalHits(true),
 	)
 	if err != nil {
-		return nil, 0, fmt.Errorf("搜索失败: %w", err)
+		return nil, 0, nil, fmt.Errorf("搜索失败: %w", err)
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return nil, 0, fmt.Errorf("搜索失败: %s", res.String())
+		return nil, 0, nil, fmt.Errorf("搜索失败: %s", res.String())
 	}
 
 	var result map[string]interface{}
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, 0, fmt.Errorf("解析搜索结果失败: %w", err)
+		return nil, 0, nil, fmt.Errorf("解析搜索结果失败: %w", err)
 	}
+	aggs, _ := result["aggregations"].(map[string]interface{})
 
 	// 解析结果
 	hits, ok := result["hits"].(map[string]interface{})
 	if !ok {
-		return []map[string]interface{}{}, 0, nil
+		return []map[string]interface{}{}, 0, aggs, nil
 	}
 
 	total, _ := hits["total"].(map[string]interface{})
@@ -157,7 +164,7 @@ func (c *Client) Search(ctx context.Context, indexName string, query map[string]
 		}
 	}
 
-	return documents, int64(totalValue), nil
+	return documents, int64(totalValue), aggs, nil
 }
 
 // ListAllDocumentIDs 返回索引中所有文档的 ID 列表（用于和 MySQL 做 diff）
@@ -0,0 +1,103 @@
+// Package sender 短信/邮件发送。业务只依赖 Sender 接口，具体通道按配置选择：
+// 本地开发用 LogSender 把消息写进文件（直接在文件里看验证码），邮件可以配置 SMTPSender。
+package sender
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 发送通道
+const (
+	ChannelSMS   = "sms"
+	ChannelEmail = "email"
+)
+
+// 发送器类型
+const (
+	TypeLog  = "log"
+	TypeSMTP = "smtp"
+)
+
+// Message 待发送的消息
+type Message struct {
+	Channel string // sms / email
+	To      string // 手机号或邮箱
+	Subject string // 邮件标题，短信忽略
+	Content string
+}
+
+// Sender 消息发送器
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Config 发送器配置
+type Config struct {
+	Type string     `json:",default=log,options=log|smtp"`
+	File string     `json:",default=logs/outbox.log"` // TypeLog：消息追加写入的文件
+	SMTP SMTPConfig `json:",optional"`                // TypeSMTP
+}
+
+// New 按配置创建发送器
+func New(c Config) (Sender, error) {
+	switch c.Type {
+	case "", TypeLog:
+		return NewLogSender(c.File), nil
+	case TypeSMTP:
+		return NewSMTPSender(c.SMTP)
+	default:
+		return nil, fmt.Errorf("sender: unknown type %q", c.Type)
+	}
+}
+
+// LogSender 开发环境发送器：不真正发送，把消息追加到文件并打日志
+type LogSender struct {
+	file string
+	mu   sync.Mutex
+}
+
+// NewLogSender 创建日志发送器，file 为空时只打日志
+func NewLogSender(file string) *LogSender {
+	return &LogSender{file: file}
+}
+
+// Send 记录消息
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	logx.WithContext(ctx).Infof("[sender] %s to=%s subject=%q content=%q", msg.Channel, msg.To, msg.Subject, msg.Content)
+	if s.file == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.file), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\t%s\t%s\n",
+		time.Now().Format(time.RFC3339), msg.Channel, msg.To, msg.Subject, msg.Content)
+	return err
+}
+
+// ChannelSender 按通道分发到不同的发送器
+type ChannelSender map[string]Sender
+
+// Send 发送到 msg.Channel 对应的发送器
+func (c ChannelSender) Send(ctx context.Context, msg *Message) error {
+	s, ok := c[msg.Channel]
+	if !ok || s == nil {
+		return fmt.Errorf("sender: no sender for channel %q", msg.Channel)
+	}
+	return s.Send(ctx, msg)
+}
@@ -0,0 +1,54 @@
+package sender
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogSender_AppendsToFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "logs", "outbox.log")
+	s := NewLogSender(file)
+
+	for _, to := range []string{"13800000000", "a@example.com"} {
+		if err := s.Send(context.Background(), &Message{Channel: ChannelSMS, To: to, Content: "code 123456"}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "a@example.com") || !strings.Contains(lines[0], "code 123456") {
+		t.Fatalf("unexpected outbox content:\n%s", data)
+	}
+}
+
+func TestChannelSender_UnknownChannel(t *testing.T) {
+	s := ChannelSender{ChannelSMS: NewLogSender("")}
+	if err := s.Send(context.Background(), &Message{Channel: ChannelSMS, To: "13800000000"}); err != nil {
+		t.Fatalf("sms should be routed, err = %v", err)
+	}
+	if err := s.Send(context.Background(), &Message{Channel: ChannelEmail, To: "a@example.com"}); err == nil {
+		t.Fatal("email has no sender, want error")
+	}
+}
+
+func TestBuildMail_EncodesSubject(t *testing.T) {
+	mail := string(buildMail("Go Ecom <noreply@example.com>", &Message{
+		Channel: ChannelEmail, To: "a@example.com", Subject: "验证码", Content: "line1\nline2",
+	}))
+	if !strings.Contains(mail, "Subject: =?UTF-8?b?") {
+		t.Errorf("subject should be B-encoded:\n%s", mail)
+	}
+	if !strings.HasSuffix(mail, "line1\r\nline2") {
+		t.Errorf("body should use CRLF:\n%q", mail)
+	}
+	if got := envelopeAddress("Go Ecom <noreply@example.com>"); got != "noreply@example.com" {
+		t.Errorf("envelopeAddress() = %q", got)
+	}
+}
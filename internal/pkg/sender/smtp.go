@@ -0,0 +1,147 @@
+package sender
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig SMTP 配置
+type SMTPConfig struct {
+	Host     string `json:",optional"`
+	Port     int    `json:",default=465"`
+	Username string `json:",optional"`
+	Password string `json:",optional"`
+	From     string `json:",optional"` // 发件人，如 "商城 <noreply@example.com>"，为空时使用 Username
+	// ImplicitTLS 465 端口直接 TLS；关闭时走明文连接 + STARTTLS（587/25 端口）
+	ImplicitTLS bool  `json:",default=true"`
+	Timeout     int64 `json:",default=10"` // 秒
+}
+
+// SMTPSender 通过 SMTP 发送邮件，只支持 email 通道
+type SMTPSender struct {
+	conf SMTPConfig
+	from string
+}
+
+// NewSMTPSender 创建 SMTP 发送器
+func NewSMTPSender(c SMTPConfig) (*SMTPSender, error) {
+	if c.Host == "" {
+		return nil, errors.New("sender: smtp host is required")
+	}
+	if c.Port == 0 {
+		c.Port = 465
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10
+	}
+	from := c.From
+	if from == "" {
+		from = c.Username
+	}
+	if from == "" {
+		return nil, errors.New("sender: smtp from is required")
+	}
+	return &SMTPSender{conf: c, from: from}, nil
+}
+
+// Send 发送邮件
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	if msg.Channel != ChannelEmail {
+		return fmt.Errorf("sender: smtp cannot send %s", msg.Channel)
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.conf.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(envelopeAddress(s.from)); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMail(s.from, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 建立连接，隐式 TLS 或 STARTTLS
+func (s *SMTPSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.conf.Host, strconv.Itoa(s.conf.Port))
+	dialer := &net.Dialer{Timeout: time.Duration(s.conf.Timeout) * time.Second}
+	tlsConf := &tls.Config{ServerName: s.conf.Host}
+
+	var conn net.Conn
+	var err error
+	if s.conf.ImplicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConf}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.conf.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !s.conf.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConf); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+// buildMail 组装 UTF-8 纯文本邮件
+func buildMail(from string, msg *Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Content, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// envelopeAddress 从 "名称 <addr>" 中取出地址
+func envelopeAddress(from string) string {
+	if i := strings.LastIndexByte(from, '<'); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
@@ -0,0 +1,60 @@
+package signedurl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func verifyURL(t *testing.T, s *Signer, signed string, now time.Time) (*Claims, error) {
+	t.Helper()
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse %q: %v", signed, err)
+	}
+	return s.Verify(u.Path, u.Query(), now)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	s := NewSigner("test-secret")
+	now := time.Unix(1700000000, 0)
+
+	signed := s.Sign("/uploads/invoice/abc.pdf", now.Add(time.Minute), 42)
+	claims, err := verifyURL(t, s, signed, now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.UserID != 42 || !claims.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := verifyURL(t, s, signed, now.Add(2*time.Minute)); err != ErrExpired {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+	if _, err := verifyURL(t, NewSigner("other"), signed, now); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+	if _, err := verifyURL(t, s, strings.Replace(signed, "uid=42", "uid=43", 1), now); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for tampered uid, got %v", err)
+	}
+	if _, err := verifyURL(t, s, strings.Replace(signed, "abc.pdf", "abd.pdf", 1), now); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for tampered path, got %v", err)
+	}
+	if _, err := s.Verify("/uploads/invoice/abc.pdf", url.Values{}, now); err != ErrMissingSignature {
+		t.Fatalf("expected ErrMissingSignature, got %v", err)
+	}
+}
+
+func TestConfigTTL(t *testing.T) {
+	c := Config{DefaultTTL: 900, MaxTTL: 3600}
+	if got := c.TTL(0); got != 900*time.Second {
+		t.Fatalf("default ttl = %v", got)
+	}
+	if got := c.TTL(7200); got != 3600*time.Second {
+		t.Fatalf("capped ttl = %v", got)
+	}
+	if got := c.TTL(60); got != time.Minute {
+		t.Fatalf("ttl = %v", got)
+	}
+}
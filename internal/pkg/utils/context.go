@@ -5,8 +5,12 @@ import "context"
 type contextKey string
 
 const (
-	userIDKey   contextKey = "user_id"
-	usernameKey contextKey = "username"
+	userIDKey    contextKey = "user_id"
+	usernameKey  contextKey = "username"
+	claimsKey    contextKey = "jwt_claims"
+	clientIPKey  contextKey = "client_ip"
+	userAgentKey contextKey = "user_agent"
+	deviceIDKey  contextKey = "device_id"
 )
 
 func WithUserID(ctx context.Context, userID uint64) context.Context {
@@ -26,3 +30,43 @@ func GetUsername(ctx context.Context) (string, bool) {
 	username, ok := ctx.Value(usernameKey).(string)
 	return username, ok
 }
+
+// WithClaims 保存当前请求的 JWT 声明（登出时需要 jti 和过期时间）
+func WithClaims(ctx context.Context, claims *JWTClaims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+func GetClaims(ctx context.Context) (*JWTClaims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*JWTClaims)
+	return claims, ok && claims != nil
+}
+
+// WithClientIP 保存客户端 IP（网关转发的 X-Forwarded-For）
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+func GetClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
+
+// WithUserAgent 保存客户端 User-Agent（网关以 x-client-user-agent 转发）
+func WithUserAgent(ctx context.Context, ua string) context.Context {
+	return context.WithValue(ctx, userAgentKey, ua)
+}
+
+func GetUserAgent(ctx context.Context) string {
+	ua, _ := ctx.Value(userAgentKey).(string)
+	return ua
+}
+
+// WithDeviceID 保存客户端生成的设备标识（X-Device-Id），用于识别新设备登录
+func WithDeviceID(ctx context.Context, deviceID string) context.Context {
+	return context.WithValue(ctx, deviceIDKey, deviceID)
+}
+
+func GetDeviceID(ctx context.Context) string {
+	id, _ := ctx.Value(deviceIDKey).(string)
+	return id
+}
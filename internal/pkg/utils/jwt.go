@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -14,25 +15,48 @@ var (
 
 // JWTClaims JWT声明
 type JWTClaims struct {
-	UserID   uint64 `json:"user_id"`
-	Username string `json:"username"`
+	UserID      uint64   `json:"user_id"`
+	Username    string   `json:"username"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"perms,omitempty"` // 角色对应的权限，各服务据此做权限校验
 	jwt.RegisteredClaims
 }
 
+// TokenSubject 访问令牌的主体信息
+type TokenSubject struct {
+	UserID      uint64
+	Username    string
+	Roles       []string
+	Permissions []string
+}
+
 // GenerateToken 生成JWT Token
 func GenerateToken(userID uint64, username, secret string, expire int64) (string, error) {
+	token, _, err := GenerateAccessToken(TokenSubject{UserID: userID, Username: username}, secret, expire)
+	return token, err
+}
+
+// GenerateAccessToken 生成带 jti 的访问令牌，返回 token 和 jti（吊销时按 jti 加入黑名单）
+func GenerateAccessToken(sub TokenSubject, secret string, expire int64) (string, string, error) {
+	jti := uuid.NewString()
 	claims := JWTClaims{
-		UserID:   userID,
-		Username: username,
+		UserID:      sub.UserID,
+		Username:    sub.Username,
+		Roles:       sub.Roles,
+		Permissions: sub.Permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expire) * time.Second)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", "", err
+	}
+	return token, jti, nil
 }
 
 // ParseToken 解析JWT Token
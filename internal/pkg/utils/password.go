@@ -1,12 +1,75 @@
 package utils
 
 import (
+	"bufio"
 	"crypto/md5"
+	_ "embed"
 	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// 密码长度限制（字节）。bcrypt 只使用前 72 字节，超出部分拒绝而不是静默截断
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 72
+)
+
+// 密码策略错误，文案可直接返回给用户
+var (
+	ErrPasswordTooShort = errors.New("密码长度至少8位")
+	ErrPasswordTooLong  = errors.New("密码长度不能超过72个字符")
+	ErrPasswordTooWeak  = errors.New("密码过于简单，请更换")
+	ErrPasswordUsername = errors.New("密码不能与用户名相同")
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var (
+	commonPasswordsOnce sync.Once
+	commonPasswords     map[string]struct{}
+)
+
+// ValidatePassword 校验密码策略：长度 8~72 字节、不是常见/已泄露密码、不是同一字符重复、不与用户名相同。
+// 设置密码的地方（注册、重置、修改、初始化管理员）都要先调用，再 HashPassword。
+func ValidatePassword(password, username string) error {
+	if len(password) < PasswordMinLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > PasswordMaxLength {
+		return ErrPasswordTooLong
+	}
+	lower := strings.ToLower(password)
+	if username != "" && lower == strings.ToLower(username) {
+		return ErrPasswordUsername
+	}
+	if strings.Count(lower, lower[:1]) == len(lower) || isCommonPassword(lower) {
+		return ErrPasswordTooWeak
+	}
+	return nil
+}
+
+// isCommonPassword 是否在常见密码列表中（不区分大小写）
+func isCommonPassword(lower string) bool {
+	commonPasswordsOnce.Do(func() {
+		commonPasswords = make(map[string]struct{})
+		sc := bufio.NewScanner(strings.NewReader(commonPasswordList))
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			commonPasswords[line] = struct{}{}
+		}
+	})
+	_, ok := commonPasswords[lower]
+	return ok
+}
+
 // HashPassword 使用bcrypt加密密码
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
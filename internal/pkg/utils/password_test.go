@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	cases := []struct {
+		password string
+		username string
+		want     error
+	}{
+		{"abc123", "", ErrPasswordTooShort},
+		{strings.Repeat("x1", 37), "", ErrPasswordTooLong},
+		{"Password123", "", ErrPasswordTooWeak},
+		{"88888888", "", ErrPasswordTooWeak},
+		{"zzzzzzzzzz", "", ErrPasswordTooWeak},
+		{"AliceWonder", "alicewonder", ErrPasswordUsername},
+		{"correct-horse-battery", "alice", nil},
+	}
+	for _, c := range cases {
+		if err := ValidatePassword(c.password, c.username); !errors.Is(err, c.want) {
+			t.Errorf("ValidatePassword(%q) = %v, want %v", c.password, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package verifycode 短信/邮件验证码的存储和限流，数据保存在 Redis：
+//
+//	verify:code::{target}:{scene}      验证码和已尝试次数（hash），有效期 TTL
+//	verify:interval::{target}          发送间隔锁
+//	verify:target::{target}            目标 24 小时内的发送次数
+//	verify:ip::{ip}                    IP 1 小时内的发送次数
+//
+// 同一目标、同一场景只保留最新的验证码；输错次数达到上限后验证码作废，需要重新发送。
+package verifycode
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/cache"
+)
+
+// 场景
+const (
+	SceneRegister      = 1 // 注册
+	SceneLogin         = 2 // 验证码登录
+	SceneResetPassword = 3 // 重置密码
+	SceneChangePhone   = 4 // 更换手机号
+)
+
+var (
+	// ErrTooFrequent 距上次发送不足发送间隔
+	ErrTooFrequent = errors.New("verifycode: sent too frequently")
+	// ErrTargetLimited 目标 24 小时内发送次数超限
+	ErrTargetLimited = errors.New("verifycode: target daily limit exceeded")
+	// ErrIPLimited IP 1 小时内发送次数超限
+	ErrIPLimited = errors.New("verifycode: ip hourly limit exceeded")
+	// ErrNotFound 验证码不存在或已过期
+	ErrNotFound = errors.New("verifycode: code not found or expired")
+	// ErrMismatch 验证码错误
+	ErrMismatch = errors.New("verifycode: code mismatch")
+	// ErrTooManyAttempts 输错次数过多，验证码已作废
+	ErrTooManyAttempts = errors.New("verifycode: too many attempts")
+)
+
+// Config 验证码配置
+type Config struct {
+	Length           int   `json:",default=6"`
+	TTL              int64 `json:",default=300"` // 有效期（秒）
+	MaxAttempts      int   `json:",default=5"`   // 同一个验证码最多尝试次数
+	SendInterval     int64 `json:",default=60"`  // 同一目标两次发送的最小间隔（秒）
+	TargetDailyLimit int   `json:",default=10"`  // 同一目标 24 小时内最多发送次数
+	IPHourlyLimit    int   `json:",default=20"`  // 同一 IP 1 小时内最多发送次数
+}
+
+// Store 验证码存储。target 由调用方统一规范化（如邮箱转小写）
+type Store struct {
+	client *redis.Client
+	conf   Config
+}
+
+// NewStore 创建验证码存储，未配置的项使用默认值
+func NewStore(client *redis.Client, c Config) *Store {
+	if c.Length <= 0 {
+		c.Length = 6
+	}
+	if c.TTL <= 0 {
+		c.TTL = 300
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.SendInterval <= 0 {
+		c.SendInterval = 60
+	}
+	if c.TargetDailyLimit <= 0 {
+		c.TargetDailyLimit = 10
+	}
+	if c.IPHourlyLimit <= 0 {
+		c.IPHourlyLimit = 20
+	}
+	return &Store{client: client, conf: c}
+}
+
+// TTL 验证码有效期
+func (s *Store) TTL() time.Duration {
+	return time.Duration(s.conf.TTL) * time.Second
+}
+
+// SendInterval 两次发送的最小间隔
+func (s *Store) SendInterval() time.Duration {
+	return time.Duration(s.conf.SendInterval) * time.Second
+}
+
+// allowScript 检查发送间隔、目标和 IP 的发送次数，全部通过后才计数。
+// KEYS: 间隔锁, 目标计数[, IP 计数]；ARGV: 间隔秒数, 目标上限, IP 上限
+// 返回 0 通过，1 间隔不足，2 目标超限，3 IP 超限
+var allowScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  return 1
+end
+if tonumber(redis.call('GET', KEYS[2]) or '0') >= tonumber(ARGV[2]) then
+  return 2
+end
+if #KEYS > 2 and tonumber(redis.call('GET', KEYS[3]) or '0') >= tonumber(ARGV[3]) then
+  return 3
+end
+redis.call('SET', KEYS[1], 1, 'EX', ARGV[1])
+if redis.call('INCR', KEYS[2]) == 1 then
+  redis.call('EXPIRE', KEYS[2], 86400)
+end
+if #KEYS > 2 and redis.call('INCR', KEYS[3]) == 1 then
+  redis.call('EXPIRE', KEYS[3], 3600)
+end
+return 0
+`)
+
+// verifyScript 校验验证码。KEYS: 验证码；ARGV: 输入的验证码, 最多尝试次数, 是否消费(1/0)
+// 返回 0 通过，-1 不存在，-2 尝试次数用尽，-3 不匹配
+var verifyScript = redis.NewScript(`
+local stored = redis.call('HGET', KEYS[1], 'code')
+if not stored then
+  return -1
+end
+local attempts = tonumber(redis.call('HGET', KEYS[1], 'attempts') or '0')
+if attempts >= tonumber(ARGV[2]) then
+  redis.call('DEL', KEYS[1])
+  return -2
+end
+if stored ~= ARGV[1] then
+  attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+  if attempts >= tonumber(ARGV[2]) then
+    redis.call('DEL', KEYS[1])
+    return -2
+  end
+  return -3
+end
+if ARGV[3] == '1' then
+  redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Issue 通过限流检查后生成并保存新的验证码；ip 为空时不做 IP 限流
+func (s *Store) Issue(ctx context.Context, scene int, target, ip string) (string, error) {
+	keys := []string{
+		cache.BuildKey(cache.KeyPrefixVerifyInterval, target),
+		cache.BuildKey(cache.KeyPrefixVerifyTarget, target),
+	}
+	if ip != "" {
+		keys = append(keys, cache.BuildKey(cache.KeyPrefixVerifyIP, ip))
+	}
+	res, err := allowScript.Run(ctx, s.client, keys, s.conf.SendInterval, s.conf.TargetDailyLimit, s.conf.IPHourlyLimit).Int()
+	if err != nil {
+		return "", err
+	}
+	switch res {
+	case 1:
+		return "", ErrTooFrequent
+	case 2:
+		return "", ErrTargetLimited
+	case 3:
+		return "", ErrIPLimited
+	}
+
+	code, err := generate(s.conf.Length)
+	if err != nil {
+		return "", err
+	}
+	key := codeKey(scene, target)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "code", code, "attempts", 0)
+		pipe.Expire(ctx, key, s.TTL())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// Discard 作废验证码（发送失败时调用）
+func (s *Store) Discard(ctx context.Context, scene int, target string) error {
+	return s.client.Del(ctx, codeKey(scene, target)).Err()
+}
+
+// Verify 校验验证码；consume 为 true 时校验通过后作废，验证码只能使用一次
+func (s *Store) Verify(ctx context.Context, scene int, target, code string, consume bool) error {
+	if code == "" {
+		return ErrMismatch
+	}
+	flag := "0"
+	if consume {
+		flag = "1"
+	}
+	res, err := verifyScript.Run(ctx, s.client, []string{codeKey(scene, target)}, code, s.conf.MaxAttempts, flag).Int()
+	if err != nil {
+		return err
+	}
+	switch res {
+	case -1:
+		return ErrNotFound
+	case -2:
+		return ErrTooManyAttempts
+	case -3:
+		return ErrMismatch
+	}
+	return nil
+}
+
+// codeKey 验证码键
+func codeKey(scene int, target string) string {
+	return cache.BuildKey(cache.KeyPrefixVerifyCode, target, scene)
+}
+
+// generate 生成 n 位数字验证码
+func generate(n int) (string, error) {
+	b := make([]byte, n)
+	for i := range b {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b[i] = byte('0' + d.Int64())
+	}
+	return string(b), nil
+}
@@ -0,0 +1,423 @@
+// Package xlsx 最小化的 Office Open XML 表格读写：只处理单个工作表的纯文本单元格，
+// 不支持样式、公式求值和合并单元格，用于商品目录等表格数据的批量导入导出。
+//
+// 读取时取工作簿中的第一个工作表，数字、布尔和公式缓存值都按原始文本返回；
+// 写入时所有单元格都写为内联字符串，避免编码、手机号等前导零被表格软件吞掉。
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrNoSheet 工作簿中没有工作表
+	ErrNoSheet = errors.New("xlsx: no worksheet")
+	// ErrTooLarge 解压后的内容超过限制（防止压缩炸弹）
+	ErrTooLarge = errors.New("xlsx: content too large")
+	// ErrTooManyRows 工作表的行数超过调用方的上限
+	ErrTooManyRows = errors.New("xlsx: too many rows")
+)
+
+// maxPartSize 单个 XML 部件解压后的大小上限
+const maxPartSize = 256 << 20
+
+// maxSheetRows 工作表的最大行号（与 Excel 一致）
+const maxSheetRows = 1 << 20
+
+// Read 读取第一个工作表的全部行；每行按列号补齐中间的空单元格，行尾空单元格被省略。
+// 行数（含补齐的空行）超过 maxRows 时返回 ErrTooManyRows，maxRows <= 0 表示不限制。
+func Read(data []byte, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: open zip: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrNoSheet
+	}
+	return readSheet(f, shared, maxRows)
+}
+
+// firstSheetPath 通过 workbook.xml 和其关系文件找到第一个工作表的路径
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var wb struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(files, "xl/workbook.xml", &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", ErrNoSheet
+	}
+	var rels struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, r := range rels.Items {
+		if r.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(r.Target, "/") {
+			return strings.TrimPrefix(r.Target, "/"), nil
+		}
+		return path.Join("xl", r.Target), nil
+	}
+	return "", ErrNoSheet
+}
+
+// decodePart 解析指定的 XML 部件
+func decodePart(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("xlsx: missing %s", name)
+	}
+	rc, err := openPart(f)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("xlsx: parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// openPart 打开部件，声明的解压大小超过上限时直接拒绝，实际读取时再按上限截断
+func openPart(f *zip.File) (io.ReadCloser, error) {
+	if f.UncompressedSize64 > maxPartSize {
+		return nil, ErrTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: open %s: %w", f.Name, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{&limitedReader{r: rc, remaining: maxPartSize}, rc}, nil
+}
+
+// limitedReader 超过上限时返回 ErrTooLarge（io.LimitReader 只会静默截断）
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// richText 共享字符串和内联字符串：纯文本在 <t> 中，富文本分段在 <r><t> 中
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r richText) String() string {
+	if len(r.Runs) == 0 {
+		return r.T
+	}
+	var b strings.Builder
+	b.WriteString(r.T)
+	for _, run := range r.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+// readSharedStrings 读取共享字符串表
+func readSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := openPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var out []string
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: parse shared strings: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "si" {
+			continue
+		}
+		var si richText
+		if err := dec.DecodeElement(&si, &se); err != nil {
+			return nil, fmt.Errorf("xlsx: parse shared strings: %w", err)
+		}
+		out = append(out, si.String())
+	}
+}
+
+// cell 工作表单元格
+type cell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline richText `xml:"is"`
+}
+
+// readSheet 流式读取工作表，逐行解码避免一次性构建整个 DOM
+func readSheet(f *zip.File, shared []string, maxRows int) ([][]string, error) {
+	rc, err := openPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var rows [][]string
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: parse sheet: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "row" {
+			continue
+		}
+		var row struct {
+			Num   int    `xml:"r,attr"`
+			Cells []cell `xml:"c"`
+		}
+		if err := dec.DecodeElement(&row, &se); err != nil {
+			return nil, fmt.Errorf("xlsx: parse sheet: %w", err)
+		}
+		// 行号缺失时按顺序排列；跳过的行号补空行，保证行下标与表格行号一致。
+		// 补齐前先检查行号，很小的文件也可以声明一个极大的行号
+		if row.Num > maxSheetRows {
+			return nil, fmt.Errorf("xlsx: row number %d out of range", row.Num)
+		}
+		if n := max(row.Num, len(rows)+1); maxRows > 0 && n > maxRows {
+			return nil, ErrTooManyRows
+		}
+		if row.Num > 0 {
+			for len(rows) < row.Num-1 {
+				rows = append(rows, nil)
+			}
+		}
+
+		var values []string
+		for i, c := range row.Cells {
+			col := i
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+			v, err := cellValue(c, shared)
+			if err != nil {
+				return nil, err
+			}
+			if col < len(values) {
+				values[col] = v
+			} else {
+				values = append(values, v)
+			}
+		}
+		for len(values) > 0 && values[len(values)-1] == "" {
+			values = values[:len(values)-1]
+		}
+		rows = append(rows, values)
+	}
+}
+
+// cellValue 按单元格类型取文本值
+func cellValue(c cell, shared []string) (string, error) {
+	switch c.Type {
+	case "s":
+		var idx int
+		if _, err := fmt.Sscanf(c.Value, "%d", &idx); err != nil || idx < 0 || idx >= len(shared) {
+			return "", fmt.Errorf("xlsx: invalid shared string index %q in %s", c.Value, c.Ref)
+		}
+		return shared[idx], nil
+	case "inlineStr":
+		return c.Inline.String(), nil
+	case "b":
+		if c.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	default: // n、str、e 以及缺省类型都直接取缓存值
+		return c.Value, nil
+	}
+}
+
+// columnIndex 将单元格引用（如 "AB12"）的列部分转换为从 0 开始的列号
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		n++
+	}
+	if n == 0 || n > 3 {
+		return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
+
+// columnName 将从 0 开始的列号转换为列名（0 -> A，26 -> AA）
+func columnName(idx int) string {
+	name := ""
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = string(rune('A'+(idx-1)%26)) + name
+	}
+	return name
+}
+
+// 写入时使用的固定部件
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+)
+
+// Write 将 rows 写为只有一个工作表的 xlsx 文件
+func Write(w io.Writer, sheetName string, rows [][]string) error {
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML(sheetName)},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, p := range parts {
+		pw, err := zw.Create(p.name)
+		if err != nil {
+			return fmt.Errorf("xlsx: create %s: %w", p.name, err)
+		}
+		if _, err := io.WriteString(pw, p.content); err != nil {
+			return fmt.Errorf("xlsx: write %s: %w", p.name, err)
+		}
+	}
+
+	sw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("xlsx: create sheet: %w", err)
+	}
+	if err := writeSheet(sw, rows); err != nil {
+		return fmt.Errorf("xlsx: write sheet: %w", err)
+	}
+	return zw.Close()
+}
+
+// workbookXML 生成只包含一个工作表的 workbook.xml
+func workbookXML(sheetName string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`)
+	_ = xml.EscapeText(&b, []byte(sheetName))
+	b.WriteString(`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+	return b.String()
+}
+
+// writeSheet 写入工作表，空单元格不输出
+func writeSheet(w io.Writer, rows [][]string) error {
+	bw := &errWriter{w: w}
+	bw.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(bw, `<row r="%d">`, i+1)
+		for j, v := range row {
+			if v == "" {
+				continue
+			}
+			fmt.Fprintf(bw, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(j), i+1)
+			_ = xml.EscapeText(bw, []byte(v))
+			bw.WriteString(`</t></is></c>`)
+		}
+		bw.WriteString(`</row>`)
+	}
+	bw.WriteString(`</sheetData></worksheet>`)
+	return bw.err
+}
+
+// errWriter 记录第一次写入错误，之后的写入直接跳过
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n, err := e.w.Write(p)
+	e.err = err
+	return n, err
+}
+
+func (e *errWriter) WriteString(s string) {
+	_, _ = e.Write([]byte(s))
+}
@@ -0,0 +1,141 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"spu_code", "name", "price"},
+		{"SPU001", "小米手机 <Pro> & 套装", "1999.00"},
+		{"00123", "", "  空格保留  "},
+		{},
+		{"", "", "", "D5"},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "商品", rows); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := Read(buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := [][]string{
+		rows[0],
+		rows[1],
+		rows[2],
+		nil,
+		rows[4],
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip mismatch:\n got %q\nwant %q", got, want)
+	}
+}
+
+// buildWorkbook 用给定的 sheetData 内容构造一个工作簿，工作表通过非默认的关系 ID 和路径引用
+func buildWorkbook(t *testing.T, sheetData string) []byte {
+	t.Helper()
+	parts := map[string]string{
+		"[Content_Types].xml": contentTypesXML,
+		"_rels/.rels":         rootRelsXML,
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="A" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId7" Type="worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>名称</t></si><si><r><t>富</t></r><r><t>文本</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			sheetData + `</sheetData></worksheet>`,
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadSharedStringsAndTypes(t *testing.T) {
+	data := buildWorkbook(t, `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>`+
+		`<row r="3"><c r="A3"><v>42.5</v></c><c r="B3" t="b"><v>1</v></c><c r="AA3" t="str"><v>x</v></c></row>`)
+
+	got, err := Read(data, 0)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 rows, got %d: %q", len(got), got)
+	}
+	if !reflect.DeepEqual(got[0], []string{"名称", "", "富文本"}) {
+		t.Fatalf("unexpected row 1: %q", got[0])
+	}
+	if got[1] != nil {
+		t.Fatalf("expected skipped row 2 to be empty, got %q", got[1])
+	}
+	if len(got[2]) != 27 || got[2][0] != "42.5" || got[2][1] != "TRUE" || got[2][26] != "x" {
+		t.Fatalf("unexpected row 3: %q", got[2])
+	}
+}
+
+func TestReadRejectsInvalid(t *testing.T) {
+	if _, err := Read([]byte("spu_code,name\n"), 0); err == nil {
+		t.Fatal("expected error for non-zip input")
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_ = zw.Close()
+	if _, err := Read(buf.Bytes(), 0); err == nil {
+		t.Fatal("expected error for empty archive")
+	}
+}
+
+func TestReadRowLimits(t *testing.T) {
+	// 很小的文件声明极大的行号：不能按行号补齐空行
+	data := buildWorkbook(t, `<row r="1"><c r="A1" t="str"><v>a</v></c></row><row r="2000000000"><c r="A2000000000" t="str"><v>b</v></c></row>`)
+	if _, err := Read(data, 0); err == nil {
+		t.Fatal("expected error for row number beyond the sheet limit")
+	}
+
+	// 补齐的空行也计入行数上限
+	data = buildWorkbook(t, `<row r="1"><c r="A1" t="str"><v>a</v></c></row><row r="5"><c r="A5" t="str"><v>b</v></c></row>`)
+	if _, err := Read(data, 4); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("expected ErrTooManyRows, got %v", err)
+	}
+	// 没有行号的行按顺序计数
+	data = buildWorkbook(t, `<row><c t="str"><v>a</v></c></row><row><c t="str"><v>b</v></c></row><row><c t="str"><v>c</v></c></row>`)
+	if _, err := Read(data, 2); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("expected ErrTooManyRows, got %v", err)
+	}
+	if got, err := Read(data, 3); err != nil || len(got) != 3 {
+		t.Fatalf("read within limit: rows=%d err=%v", len(got), err)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for idx, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(idx); got != want {
+			t.Fatalf("columnName(%d) = %s, want %s", idx, got, want)
+		}
+		got, err := columnIndex(want + "12")
+		if err != nil || got != idx {
+			t.Fatalf("columnIndex(%s12) = %d, %v; want %d", want, got, err, idx)
+		}
+	}
+	if _, err := columnIndex("12"); err == nil {
+		t.Fatal("expected error for reference without column")
+	}
+}
@@ -30,6 +30,8 @@ type CartRepository interface {
 	BatchSelect(ctx context.Context, userID uint64, skuIDs []uint64, isSelected int8) error
 	// SyncToDB 同步到数据库（持久化）
 	SyncToDB(ctx context.Context, userID uint64) error
+	// DeleteAllByUser 彻底删除用户购物车（Redis 和持久化备份），返回删除的持久化记录数
+	DeleteAllByUser(ctx context.Context, userID uint64) (int64, error)
 }
 
 type cartRepository struct {
@@ -232,3 +234,12 @@ func (r *cartRepository) SyncToDB(ctx context.Context, userID uint64) error {
 
 	return nil
 }
+
+// DeleteAllByUser 彻底删除用户购物车，持久化记录物理删除
+func (r *cartRepository) DeleteAllByUser(ctx context.Context, userID uint64) (int64, error) {
+	if err := r.ClearCart(ctx, userID); err != nil {
+		return 0, err
+	}
+	res := r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.Cart{})
+	return res.RowsAffected, res.Error
+}
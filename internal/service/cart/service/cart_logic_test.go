@@ -81,6 +81,10 @@ func (m *mockCartRepo) SyncToDB(ctx context.Context, userID uint64) error {
 	return m.syncToDBFn(ctx, userID)
 }
 
+func (m *mockCartRepo) DeleteAllByUser(ctx context.Context, userID uint64) (int64, error) {
+	return 0, nil
+}
+
 func TestCartLogicAddItemSuccess(t *testing.T) {
 	repo := &mockCartRepo{
 		addItemFn: func(_ context.Context, cart *model.Cart) error {
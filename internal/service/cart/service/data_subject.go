@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/cart/repository"
+)
+
+// DataSubjectHandler 购物车服务的个人数据导出和删除，注销时购物车全部删除
+type DataSubjectHandler struct {
+	cartRepo repository.CartRepository
+}
+
+// NewDataSubjectHandler 创建购物车个人数据处理器
+func NewDataSubjectHandler(cartRepo repository.CartRepository) *DataSubjectHandler {
+	return &DataSubjectHandler{cartRepo: cartRepo}
+}
+
+// Export 导出用户购物车
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	carts, err := h.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Section{{
+		Name:        "cart",
+		Description: "购物车商品",
+		Records:     carts,
+		Count:       len(carts),
+	}}, nil
+}
+
+// Erase 删除用户购物车
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	n, err := h.cartRepo.DeleteAllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Result{{Name: "cart", Deleted: n}}, nil
+}
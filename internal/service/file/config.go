@@ -1,10 +1,41 @@
 package file
 
-import "github.com/zeromicro/go-zero/zrpc"
+import (
+	"github.com/zeromicro/go-zero/zrpc"
+
+	"ecommerce-system/internal/pkg/signedurl"
+)
 
 type Config struct {
 	zrpc.RpcServerConf
 	Storage StorageConfig
+	Upload  UploadConfig `json:",optional"`
+	// Signing 私有分类文件的签名 URL 配置（与网关 Static.Signing 保持一致）
+	Signing signedurl.Config `json:",optional"`
+	// JWT 校验网关转发的登录令牌，用于记录上传者和签发绑定用户的 URL（与 user-service 保持一致）
+	JWT JWTConfig
+	// BizRedis 令牌黑名单所在的 Redis（与 user-service 保持一致），未配置时不检查吊销
+	BizRedis RedisConfig `json:",optional"`
+}
+
+// JWTConfig JWT配置
+type JWTConfig struct {
+	Secret string
+}
+
+// RedisConfig Redis配置
+type RedisConfig struct {
+	Host         string `json:",optional"`
+	Port         int    `json:",default=6379"`
+	Password     string `json:",optional"`
+	Database     int    `json:",optional"`
+	PoolSize     int    `json:",default=10"`
+	MinIdleConns int    `json:",default=2"`
+}
+
+// UploadConfig 上传限制
+type UploadConfig struct {
+	MaxSize int64 `json:",default=104857600"` // 流式上传单文件大小上限（字节）
 }
 
 type StorageConfig struct {
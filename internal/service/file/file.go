@@ -1,6 +1,9 @@
 package file
 
 import (
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/cache"
 	"ecommerce-system/internal/service/file/repository"
 )
 
@@ -8,6 +11,7 @@ import (
 type ServiceContext struct {
 	Config   Config
 	FileRepo repository.FileRepository
+	Redis    *redis.Client // 令牌黑名单，未配置 BizRedis 时为 nil
 }
 
 // NewServiceContext 创建服务上下文
@@ -17,6 +21,16 @@ func NewServiceContext(c Config) *ServiceContext {
 	}
 
 	ctx.FileRepo = repository.NewFileRepository(c.Storage)
+	if c.BizRedis.Host != "" {
+		ctx.Redis = cache.MustNewRedis(&cache.Config{
+			Host:         c.BizRedis.Host,
+			Port:         c.BizRedis.Port,
+			Password:     c.BizRedis.Password,
+			Database:     c.BizRedis.Database,
+			PoolSize:     c.BizRedis.PoolSize,
+			MinIdleConns: c.BizRedis.MinIdleConns,
+		})
+	}
 
 	return ctx
 }
@@ -2,12 +2,16 @@ package file
 
 import (
 	"context"
+	"io"
 	"time"
 
 	v1 "ecommerce-system/api/file/v1"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/utils"
 	"ecommerce-system/internal/service/file/model"
 	"ecommerce-system/internal/service/file/service"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -21,7 +25,7 @@ type FileService struct {
 
 // NewFileService 创建文件服务
 func NewFileService(svcCtx *ServiceContext) *FileService {
-	logic := service.NewFileLogic(svcCtx.FileRepo)
+	logic := service.NewFileLogic(svcCtx.FileRepo, svcCtx.Config.Signing, svcCtx.Config.Upload.MaxSize)
 
 	return &FileService{
 		svcCtx: svcCtx,
@@ -36,6 +40,7 @@ func (s *FileService) UploadFile(ctx context.Context, req *v1.UploadFileRequest)
 		FileName: req.FileName,
 		FileType: req.FileType,
 		Category: req.Category,
+		OwnerID:  callerID(ctx),
 	}
 
 	resp, err := s.logic.UploadFile(ctx, uploadReq)
@@ -50,12 +55,93 @@ func (s *FileService) UploadFile(ctx context.Context, req *v1.UploadFileRequest)
 	}, nil
 }
 
+// UploadFileStream 流式上传文件
+func (s *FileService) UploadFileStream(stream grpc.ClientStreamingServer[v1.UploadFileChunk, v1.UploadFileResponse]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "读取文件元信息失败")
+	}
+	meta := first.GetMeta()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "首条消息必须携带文件元信息")
+	}
+
+	resp, err := s.logic.UploadFileStream(stream.Context(), &service.UploadFileStreamRequest{
+		Reader:   &chunkReader{stream: stream, buf: first.GetData()},
+		FileName: meta.GetFileName(),
+		FileType: meta.GetFileType(),
+		Category: meta.GetCategory(),
+		Size:     meta.GetSize(),
+		OwnerID:  callerID(stream.Context()),
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return stream.SendAndClose(&v1.UploadFileResponse{
+		Code:    0,
+		Message: "上传成功",
+		Data:    convertFileInfoToProto(resp.FileInfo),
+	})
+}
+
+// chunkReader 将客户端上传流适配为 io.Reader，客户端 CloseSend 后返回 io.EOF
+type chunkReader struct {
+	stream grpc.ClientStreamingServer[v1.UploadFileChunk, v1.UploadFileResponse]
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// downloadChunkSize 流式下载每个分片的大小，低于 gRPC 默认 4MB 的消息上限
+const downloadChunkSize = 1 << 20
+
+// DownloadFileStream 流式下载文件：首条消息携带文件信息，之后按分片发送文件内容
+func (s *FileService) DownloadFileStream(req *v1.DownloadFileRequest, stream grpc.ServerStreamingServer[v1.DownloadFileChunk]) error {
+	rc, fileInfo, err := s.logic.OpenFile(stream.Context(), req.FileId)
+	if err != nil {
+		return convertError(err)
+	}
+	defer rc.Close()
+
+	if err := stream.Send(&v1.DownloadFileChunk{Info: convertFileInfoToProto(fileInfo)}); err != nil {
+		return err
+	}
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := rc.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&v1.DownloadFileChunk{Data: buf[:n]}); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, "读取文件失败")
+		}
+	}
+}
+
 // BatchUploadFile 批量上传文件
 func (s *FileService) BatchUploadFile(ctx context.Context, req *v1.BatchUploadFileRequest) (*v1.BatchUploadFileResponse, error) {
 	batchReq := &service.BatchUploadFileRequest{
 		FileDataList: req.FileData,
 		FileNames:    req.FileNames,
 		Category:     req.Category,
+		OwnerID:      callerID(ctx),
 	}
 
 	resp, err := s.logic.BatchUploadFile(ctx, batchReq)
@@ -96,6 +182,7 @@ func (s *FileService) DeleteFile(ctx context.Context, req *v1.DeleteFileRequest)
 func (s *FileService) GetFileURL(ctx context.Context, req *v1.GetFileURLRequest) (*v1.GetFileURLResponse, error) {
 	getReq := &service.GetFileURLRequest{
 		FileID: req.FileId,
+		UserID: callerID(ctx),
 	}
 
 	resp, err := s.logic.GetFileURL(ctx, getReq)
@@ -104,18 +191,43 @@ func (s *FileService) GetFileURL(ctx context.Context, req *v1.GetFileURLRequest)
 	}
 
 	return &v1.GetFileURLResponse{
-		Code:    0,
-		Message: "成功",
-		FileUrl: resp.FileURL,
+		Code:      0,
+		Message:   "成功",
+		FileUrl:   resp.FileURL,
+		ExpiresAt: resp.ExpiresAt,
+	}, nil
+}
+
+// SignFileURL 为指定用户签发文件URL（内部调用）
+func (s *FileService) SignFileURL(ctx context.Context, req *v1.SignFileURLRequest) (*v1.GetFileURLResponse, error) {
+	signReq := &service.SignFileURLRequest{
+		FileID:        req.FileId,
+		ExpireSeconds: req.ExpireSeconds,
+		UserID:        req.UserId,
+	}
+
+	resp, err := s.logic.SignFileURL(ctx, signReq)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.GetFileURLResponse{
+		Code:      0,
+		Message:   "成功",
+		FileUrl:   resp.FileURL,
+		ExpiresAt: resp.ExpiresAt,
 	}, nil
 }
 
+// callerID 鉴权拦截器校验过的调用方用户ID，未登录时为 0
+func callerID(ctx context.Context) uint64 {
+	userID, _ := utils.GetUserID(ctx)
+	return userID
+}
+
 // convertError 转换业务错误为 gRPC 错误
 func convertError(err error) error {
-	if err == nil {
-		return nil
-	}
-	return status.Error(codes.Internal, err.Error())
+	return apperrors.ConvertToGRPCError(err)
 }
 
 // convertFileInfoToProto 转换文件信息模型为 Protobuf 消息
@@ -8,5 +8,7 @@ type FileInfo struct {
 	FileURL   string
 	FileSize  int64
 	FileType  string
+	Category  string
+	OwnerID   uint64 // 上传者用户ID，0 表示匿名或内部服务上传
 	CreatedAt time.Time
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -14,14 +17,21 @@ import (
 
 // FileRepository 文件仓库接口
 type FileRepository interface {
-	// UploadFile 上传文件
-	UploadFile(ctx context.Context, fileData []byte, fileName, fileType, category string) (*model.FileInfo, error)
+	// UploadFile 上传文件，ownerID 为上传者（0 表示无归属）
+	UploadFile(ctx context.Context, fileData []byte, fileName, fileType, category string, ownerID uint64) (*model.FileInfo, error)
+	// UploadFileStream 流式上传文件：边读边写入磁盘，不在内存中保存整个文件
+	UploadFileStream(ctx context.Context, r io.Reader, fileName, fileType, category string, ownerID uint64) (*model.FileInfo, error)
 	// DeleteFile 删除文件
 	DeleteFile(ctx context.Context, fileID string) error
-	// GetFileURL 获取文件URL
-	GetFileURL(ctx context.Context, fileID string) (string, error)
+	// GetFile 根据文件ID查找文件（返回的 FileURL 为未签名的原始路径）
+	GetFile(ctx context.Context, fileID string) (*model.FileInfo, error)
+	// OpenFile 打开文件用于读取，调用方负责关闭
+	OpenFile(ctx context.Context, fileID string) (io.ReadCloser, *model.FileInfo, error)
 }
 
+// ErrFileNotFound 文件不存在
+var ErrFileNotFound = errors.New("文件不存在")
+
 type fileRepository struct {
 	storageType string
 	localPath   string
@@ -45,8 +55,44 @@ func (r *fileRepository) generateFileID(fileData []byte, fileName string) string
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// fileMeta 文件元信息，与文件同目录保存为 .{fileID}.meta（点开头，网关静态文件服务不对外提供）
+type fileMeta struct {
+	OwnerID uint64 `json:"owner_id"`
+}
+
+// metaPath 元信息文件路径
+func (r *fileRepository) metaPath(category, fileID string) string {
+	return filepath.Join(r.localPath, category, "."+fileID+".meta")
+}
+
+// writeMeta 保存文件元信息，无归属的文件不写
+func (r *fileRepository) writeMeta(category, fileID string, ownerID uint64) error {
+	if ownerID == 0 {
+		return nil
+	}
+	data, err := json.Marshal(fileMeta{OwnerID: ownerID})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.metaPath(category, fileID), data, 0644)
+}
+
+// readMeta 读取文件元信息，元信息不存在时返回零值
+func (r *fileRepository) readMeta(category, fileID string) (fileMeta, error) {
+	var meta fileMeta
+	data, err := os.ReadFile(r.metaPath(category, fileID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return meta, nil
+		}
+		return meta, err
+	}
+	err = json.Unmarshal(data, &meta)
+	return meta, err
+}
+
 // UploadFile 上传文件
-func (r *fileRepository) UploadFile(ctx context.Context, fileData []byte, fileName, fileType, category string) (*model.FileInfo, error) {
+func (r *fileRepository) UploadFile(ctx context.Context, fileData []byte, fileName, fileType, category string, ownerID uint64) (*model.FileInfo, error) {
 	fileID := r.generateFileID(fileData, fileName)
 
 	// 创建目录
@@ -66,6 +112,9 @@ func (r *fileRepository) UploadFile(ctx context.Context, fileData []byte, fileNa
 	if _, err := file.Write(fileData); err != nil {
 		return nil, fmt.Errorf("写入文件失败: %v", err)
 	}
+	if err := r.writeMeta(category, fileID, ownerID); err != nil {
+		return nil, fmt.Errorf("保存文件信息失败: %v", err)
+	}
 
 	fileInfo := &model.FileInfo{
 		FileID:    fileID,
@@ -73,12 +122,64 @@ func (r *fileRepository) UploadFile(ctx context.Context, fileData []byte, fileNa
 		FileURL:   "/uploads/" + category + "/" + fileID + filepath.Ext(fileName),
 		FileSize:  int64(len(fileData)),
 		FileType:  fileType,
+		Category:  category,
+		OwnerID:   ownerID,
 		CreatedAt: time.Now(),
 	}
 
 	return fileInfo, nil
 }
 
+// UploadFileStream 流式上传文件：先写入同目录下的临时文件并同时计算文件ID，完成后再重命名，
+// 读取失败（客户端取消、超过大小限制等）时删除临时文件，不会留下不完整的文件
+func (r *fileRepository) UploadFileStream(ctx context.Context, reader io.Reader, fileName, fileType, category string, ownerID uint64) (*model.FileInfo, error) {
+	dir := filepath.Join(r.localPath, category)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer os.Remove(tmp.Name()) // 重命名成功后为空操作
+
+	h := md5.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), reader)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("写入文件失败: %w", err)
+	}
+
+	// 与 generateFileID 保持一致：内容 + 文件名 + 时间
+	h.Write([]byte(fileName))
+	h.Write([]byte(time.Now().String()))
+	fileID := hex.EncodeToString(h.Sum(nil))
+
+	// 先写元信息再重命名：文件可见时归属已经确定
+	if err := r.writeMeta(category, fileID, ownerID); err != nil {
+		return nil, fmt.Errorf("保存文件信息失败: %v", err)
+	}
+	name := fileID + filepath.Ext(fileName)
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		_ = os.Remove(r.metaPath(category, fileID))
+		return nil, fmt.Errorf("保存文件失败: %v", err)
+	}
+
+	return &model.FileInfo{
+		FileID:    fileID,
+		FileName:  fileName,
+		FileURL:   "/uploads/" + category + "/" + name,
+		FileSize:  size,
+		FileType:  fileType,
+		Category:  category,
+		OwnerID:   ownerID,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
 // DeleteFile 删除文件
 func (r *fileRepository) DeleteFile(ctx context.Context, fileID string) error {
 	// 简化处理，实际应该根据fileID查找文件路径
@@ -86,8 +187,53 @@ func (r *fileRepository) DeleteFile(ctx context.Context, fileID string) error {
 	return nil
 }
 
-// GetFileURL 获取文件URL
-func (r *fileRepository) GetFileURL(ctx context.Context, fileID string) (string, error) {
-	// 简化处理，实际应该根据fileID查找文件URL
-	return "/uploads/" + fileID, nil
+// GetFile 根据文件ID查找文件：文件按 {localPath}/{category}/{fileID}{ext} 存放
+func (r *fileRepository) GetFile(ctx context.Context, fileID string) (*model.FileInfo, error) {
+	// fileID 为 md5 十六进制串，校验后再拼接 glob，避免路径穿越和通配符注入
+	if _, err := hex.DecodeString(fileID); err != nil || len(fileID) != md5.Size*2 {
+		return nil, ErrFileNotFound
+	}
+
+	matches, err := filepath.Glob(filepath.Join(r.localPath, "*", fileID+"*"))
+	if err != nil {
+		return nil, fmt.Errorf("查找文件失败: %v", err)
+	}
+	for _, filePath := range matches {
+		stat, err := os.Stat(filePath)
+		if err != nil || stat.IsDir() {
+			continue
+		}
+		category := filepath.Base(filepath.Dir(filePath))
+		name := filepath.Base(filePath)
+		meta, err := r.readMeta(category, fileID)
+		if err != nil {
+			return nil, fmt.Errorf("读取文件信息失败: %v", err)
+		}
+		return &model.FileInfo{
+			FileID:    fileID,
+			FileName:  name,
+			FileURL:   "/uploads/" + category + "/" + name,
+			FileSize:  stat.Size(),
+			Category:  category,
+			OwnerID:   meta.OwnerID,
+			CreatedAt: stat.ModTime(),
+		}, nil
+	}
+	return nil, ErrFileNotFound
+}
+
+// OpenFile 打开文件用于读取：先按 GetFile 定位，再从存储目录打开
+func (r *fileRepository) OpenFile(ctx context.Context, fileID string) (io.ReadCloser, *model.FileInfo, error) {
+	fi, err := r.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(filepath.Join(r.localPath, fi.Category, filepath.Base(fi.FileURL)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrFileNotFound
+		}
+		return nil, nil, fmt.Errorf("打开文件失败: %v", err)
+	}
+	return f, fi, nil
 }
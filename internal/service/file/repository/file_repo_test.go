@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func newTestFileRepo(t *testing.T) *fileRepository {
+	t.Helper()
+	return &fileRepository{storageType: "local", localPath: t.TempDir()}
+}
+
+func TestFileOwnerPersisted(t *testing.T) {
+	repo := newTestFileRepo(t)
+	ctx := context.Background()
+
+	uploaded, err := repo.UploadFile(ctx, []byte("%PDF-1.4"), "a.pdf", "application/pdf", "invoice", 42)
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	streamed, err := repo.UploadFileStream(ctx, bytes.NewReader([]byte("%PDF-1.4 stream")), "b.pdf", "application/pdf", "invoice", 43)
+	if err != nil {
+		t.Fatalf("UploadFileStream: %v", err)
+	}
+	anonymous, err := repo.UploadFile(ctx, []byte("png"), "c.png", "image/png", "avatar", 0)
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+
+	for fileID, want := range map[string]uint64{uploaded.FileID: 42, streamed.FileID: 43, anonymous.FileID: 0} {
+		fi, err := repo.GetFile(ctx, fileID)
+		if err != nil {
+			t.Fatalf("GetFile(%s): %v", fileID, err)
+		}
+		if fi.OwnerID != want {
+			t.Fatalf("GetFile(%s).OwnerID = %d, want %d", fileID, fi.OwnerID, want)
+		}
+	}
+}
+
+func TestUploadFileStreamRemovesTempFileOnError(t *testing.T) {
+	repo := newTestFileRepo(t)
+	reader := io.MultiReader(bytes.NewReader(make([]byte, 64<<10)), iotest.ErrReader(errors.New("client canceled")))
+
+	if _, err := repo.UploadFileStream(context.Background(), reader, "a.pdf", "application/pdf", "document", 42); err == nil {
+		t.Fatal("expected error from a failed stream")
+	}
+	entries, err := os.ReadDir(filepath.Join(repo.localPath, "document"))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, e := range entries {
+		t.Errorf("left behind %s after a failed upload", e.Name())
+	}
+}
+
+func TestUploadFileStreamKeepsOnlyFinalFile(t *testing.T) {
+	repo := newTestFileRepo(t)
+	content := bytes.Repeat([]byte("x"), 200<<10)
+
+	fi, err := repo.UploadFileStream(context.Background(), bytes.NewReader(content), "a.pdf", "application/pdf", "document", 0)
+	if err != nil {
+		t.Fatalf("UploadFileStream: %v", err)
+	}
+	if fi.FileSize != int64(len(content)) {
+		t.Fatalf("FileSize = %d, want %d", fi.FileSize, len(content))
+	}
+	entries, err := os.ReadDir(filepath.Join(repo.localPath, "document"))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != fi.FileID+".pdf" {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Fatalf("directory contains %v, want only %s.pdf", names, fi.FileID)
+	}
+
+	rc, _, err := repo.OpenFile(context.Background(), fi.FileID)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer rc.Close()
+	got, _ := io.ReadAll(rc)
+	if !bytes.Equal(got, content) {
+		t.Fatalf("stored content differs (%d bytes, want %d)", len(got), len(content))
+	}
+}
@@ -2,21 +2,52 @@ package service
 
 import (
 	"context"
+	"errors"
+	"io"
+	"regexp"
+	"time"
+
 	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/signedurl"
 	"ecommerce-system/internal/service/file/model"
 	"ecommerce-system/internal/service/file/repository"
 )
 
+// errFileTooLarge 流式上传超过大小限制
+var errFileTooLarge = errors.New("文件大小超过限制")
+
+// categoryPattern 分类名同时作为存储目录名，只允许字母、数字、下划线和中划线
+var categoryPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
 // FileLogic 文件业务逻辑
 type FileLogic struct {
-	fileRepo repository.FileRepository
+	fileRepo      repository.FileRepository
+	signing       signedurl.Config
+	signer        *signedurl.Signer
+	maxUploadSize int64
+}
+
+// NewFileLogic 创建文件业务逻辑，signing 未配置密钥时所有文件都返回公开 URL；
+// maxUploadSize 为流式上传的单文件大小上限（字节），<= 0 表示不限制
+func NewFileLogic(fileRepo repository.FileRepository, signing signedurl.Config, maxUploadSize int64) *FileLogic {
+	l := &FileLogic{
+		fileRepo:      fileRepo,
+		signing:       signing,
+		maxUploadSize: maxUploadSize,
+	}
+	if signing.Enabled() {
+		l.signer = signedurl.NewSigner(signing.Secret)
+	}
+	return l
 }
 
-// NewFileLogic 创建文件业务逻辑
-func NewFileLogic(fileRepo repository.FileRepository) *FileLogic {
-	return &FileLogic{
-		fileRepo: fileRepo,
+// signURL 私有分类的文件返回带签名的 URL，公开分类原样返回（便于 CDN/浏览器缓存）
+func (l *FileLogic) signURL(fi *model.FileInfo, expireSeconds int64, userID uint64) (string, int64) {
+	if l.signer == nil || !l.signing.IsPrivate(fi.Category) {
+		return fi.FileURL, 0
 	}
+	expiresAt := time.Now().Add(l.signing.TTL(expireSeconds))
+	return l.signer.Sign(fi.FileURL, expiresAt, userID), expiresAt.Unix()
 }
 
 // UploadFileRequest 上传文件请求
@@ -25,6 +56,7 @@ type UploadFileRequest struct {
 	FileName string
 	FileType string
 	Category string
+	OwnerID  uint64 // 上传者（取自登录令牌），0 表示匿名
 }
 
 // UploadFileResponse 上传文件响应
@@ -37,22 +69,97 @@ func (l *FileLogic) UploadFile(ctx context.Context, req *UploadFileRequest) (*Up
 	if len(req.FileData) == 0 {
 		return nil, apperrors.NewInvalidParamError("文件数据不能为空")
 	}
+	if !categoryPattern.MatchString(req.Category) {
+		return nil, apperrors.NewInvalidParamError("文件分类无效")
+	}
+
+	fileInfo, err := l.fileRepo.UploadFile(ctx, req.FileData, req.FileName, req.FileType, req.Category, req.OwnerID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("上传文件失败")
+	}
+	fileInfo.FileURL, _ = l.signURL(fileInfo, 0, fileInfo.OwnerID)
+
+	return &UploadFileResponse{
+		FileInfo: fileInfo,
+	}, nil
+}
+
+// UploadFileStreamRequest 流式上传文件请求
+type UploadFileStreamRequest struct {
+	Reader   io.Reader // 文件内容，读到 io.EOF 表示上传完成
+	FileName string
+	FileType string
+	Category string
+	Size     int64  // 客户端声明的文件大小，0 表示未知
+	OwnerID  uint64 // 上传者（取自登录令牌），0 表示匿名
+}
+
+// UploadFileStream 流式上传文件：边接收边落盘，超过大小限制时中止并删除已写入的部分
+func (l *FileLogic) UploadFileStream(ctx context.Context, req *UploadFileStreamRequest) (*UploadFileResponse, error) {
+	if req.FileName == "" {
+		return nil, apperrors.NewInvalidParamError("文件名不能为空")
+	}
+	if !categoryPattern.MatchString(req.Category) {
+		return nil, apperrors.NewInvalidParamError("文件分类无效")
+	}
+	if l.maxUploadSize > 0 && req.Size > l.maxUploadSize {
+		return nil, apperrors.NewInvalidParamError(errFileTooLarge.Error())
+	}
 
-	fileInfo, err := l.fileRepo.UploadFile(ctx, req.FileData, req.FileName, req.FileType, req.Category)
+	reader := req.Reader
+	if l.maxUploadSize > 0 {
+		reader = &maxSizeReader{r: reader, remaining: l.maxUploadSize}
+	}
+	fileInfo, err := l.fileRepo.UploadFileStream(ctx, reader, req.FileName, req.FileType, req.Category, req.OwnerID)
 	if err != nil {
+		if errors.Is(err, errFileTooLarge) {
+			return nil, apperrors.NewInvalidParamError(errFileTooLarge.Error())
+		}
 		return nil, apperrors.NewInternalError("上传文件失败")
 	}
+	fileInfo.FileURL, _ = l.signURL(fileInfo, 0, fileInfo.OwnerID)
 
 	return &UploadFileResponse{
 		FileInfo: fileInfo,
 	}, nil
 }
 
+// OpenFile 打开文件用于流式下载，调用方负责关闭返回的 io.ReadCloser
+func (l *FileLogic) OpenFile(ctx context.Context, fileID string) (io.ReadCloser, *model.FileInfo, error) {
+	if fileID == "" {
+		return nil, nil, apperrors.NewInvalidParamError("文件ID不能为空")
+	}
+	rc, fileInfo, err := l.fileRepo.OpenFile(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFileNotFound) {
+			return nil, nil, apperrors.NewNotFoundError("文件不存在")
+		}
+		return nil, nil, apperrors.NewInternalError("读取文件失败")
+	}
+	return rc, fileInfo, nil
+}
+
+// maxSizeReader 读取超过 remaining 字节时返回 errFileTooLarge（io.LimitReader 只会静默截断）
+type maxSizeReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return n, errFileTooLarge
+	}
+	return n, err
+}
+
 // BatchUploadFileRequest 批量上传文件请求
 type BatchUploadFileRequest struct {
 	FileDataList [][]byte
 	FileNames    []string
 	Category     string
+	OwnerID      uint64 // 上传者（取自登录令牌），0 表示匿名
 }
 
 // BatchUploadFileResponse 批量上传文件响应
@@ -62,12 +169,16 @@ type BatchUploadFileResponse struct {
 
 // BatchUploadFile 批量上传文件
 func (l *FileLogic) BatchUploadFile(ctx context.Context, req *BatchUploadFileRequest) (*BatchUploadFileResponse, error) {
+	if !categoryPattern.MatchString(req.Category) {
+		return nil, apperrors.NewInvalidParamError("文件分类无效")
+	}
 	fileInfos := make([]*model.FileInfo, 0, len(req.FileDataList))
 
 	for i, fileData := range req.FileDataList {
 		if i < len(req.FileNames) {
-			fileInfo, err := l.fileRepo.UploadFile(ctx, fileData, req.FileNames[i], "", req.Category)
+			fileInfo, err := l.fileRepo.UploadFile(ctx, fileData, req.FileNames[i], "", req.Category, req.OwnerID)
 			if err == nil {
+				fileInfo.FileURL, _ = l.signURL(fileInfo, 0, fileInfo.OwnerID)
 				fileInfos = append(fileInfos, fileInfo)
 			}
 		}
@@ -95,21 +206,67 @@ func (l *FileLogic) DeleteFile(ctx context.Context, req *DeleteFileRequest) erro
 // GetFileURLRequest 获取文件URL请求
 type GetFileURLRequest struct {
 	FileID string
+	UserID uint64 // 调用方用户ID（取自登录令牌）
 }
 
 // GetFileURLResponse 获取文件URL响应
 type GetFileURLResponse struct {
-	FileURL string
+	FileURL   string
+	ExpiresAt int64 // 签名过期时间（Unix 秒），公开文件为 0
 }
 
-// GetFileURL 获取文件URL
+// GetFileURL 获取文件URL（面向登录用户）：私有文件只签发给上传者本人，签名绑定调用方，有效期固定为默认值
 func (l *FileLogic) GetFileURL(ctx context.Context, req *GetFileURLRequest) (*GetFileURLResponse, error) {
-	fileURL, err := l.fileRepo.GetFileURL(ctx, req.FileID)
+	if req.UserID == 0 {
+		return nil, apperrors.NewUnauthorizedError("未授权，请先登录")
+	}
+	fileInfo, err := l.getFile(ctx, req.FileID)
 	if err != nil {
-		return nil, apperrors.NewInternalError("获取文件URL失败")
+		return nil, err
+	}
+	if l.signer != nil && l.signing.IsPrivate(fileInfo.Category) && fileInfo.OwnerID != req.UserID {
+		return nil, apperrors.NewForbiddenError("无权访问该文件")
 	}
 
+	fileURL, expiresAt := l.signURL(fileInfo, 0, req.UserID)
 	return &GetFileURLResponse{
-		FileURL: fileURL,
+		FileURL:   fileURL,
+		ExpiresAt: expiresAt,
 	}, nil
 }
+
+// SignFileURLRequest 内部签发文件URL请求
+type SignFileURLRequest struct {
+	FileID        string
+	ExpireSeconds int64  // 签名有效期（秒），0 使用默认值，超过 MaxTTL 时截断
+	UserID        uint64 // 绑定的用户ID，0 表示不绑定
+}
+
+// SignFileURL 为指定用户签发文件URL，供内部服务在完成自身的权限校验后调用（如个人数据导出包、商品导出文件）
+func (l *FileLogic) SignFileURL(ctx context.Context, req *SignFileURLRequest) (*GetFileURLResponse, error) {
+	fileInfo, err := l.getFile(ctx, req.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileURL, expiresAt := l.signURL(fileInfo, req.ExpireSeconds, req.UserID)
+	return &GetFileURLResponse{
+		FileURL:   fileURL,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// getFile 按文件ID查找文件
+func (l *FileLogic) getFile(ctx context.Context, fileID string) (*model.FileInfo, error) {
+	if fileID == "" {
+		return nil, apperrors.NewInvalidParamError("文件ID不能为空")
+	}
+	fileInfo, err := l.fileRepo.GetFile(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFileNotFound) {
+			return nil, apperrors.NewNotFoundError("文件不存在")
+		}
+		return nil, apperrors.NewInternalError("获取文件URL失败")
+	}
+	return fileInfo, nil
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/signedurl"
+	"ecommerce-system/internal/service/file/model"
+	"ecommerce-system/internal/service/file/repository"
+)
+
+const testSigningSecret = "file-logic-test-secret"
+
+// mockFileRepo 按文件ID保存文件信息，上传时记录上传者
+type mockFileRepo struct {
+	repository.FileRepository
+	files map[string]*model.FileInfo
+}
+
+func newMockFileRepo() *mockFileRepo {
+	return &mockFileRepo{files: make(map[string]*model.FileInfo)}
+}
+
+func (m *mockFileRepo) UploadFile(ctx context.Context, fileData []byte, fileName, fileType, category string, ownerID uint64) (*model.FileInfo, error) {
+	fileID := "f" + strconv.Itoa(len(m.files)+1)
+	fi := &model.FileInfo{
+		FileID:   fileID,
+		FileName: fileName,
+		FileURL:  "/uploads/" + category + "/" + fileID + ".pdf",
+		FileSize: int64(len(fileData)),
+		FileType: fileType,
+		Category: category,
+		OwnerID:  ownerID,
+	}
+	m.files[fileID] = fi
+	copied := *fi
+	return &copied, nil
+}
+
+func (m *mockFileRepo) GetFile(ctx context.Context, fileID string) (*model.FileInfo, error) {
+	fi, ok := m.files[fileID]
+	if !ok {
+		return nil, repository.ErrFileNotFound
+	}
+	copied := *fi
+	return &copied, nil
+}
+
+func newTestFileLogic(repo repository.FileRepository) *FileLogic {
+	return NewFileLogic(repo, signedurl.Config{
+		Secret:            testSigningSecret,
+		DefaultTTL:        900,
+		MaxTTL:            86400,
+		PrivateCategories: []string{"invoice"},
+	}, 0)
+}
+
+// verifySigned 校验签名 URL，返回绑定的用户和剩余有效期
+func verifySigned(t *testing.T, signed string) (uint64, time.Duration) {
+	t.Helper()
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse %q: %v", signed, err)
+	}
+	claims, err := signedurl.NewSigner(testSigningSecret).Verify(u.Path, u.Query(), time.Now())
+	if err != nil {
+		t.Fatalf("verify %q: %v", signed, err)
+	}
+	return claims.UserID, time.Until(claims.ExpiresAt)
+}
+
+func assertCode(t *testing.T, err error, code int) {
+	t.Helper()
+	bizErr, ok := err.(*apperrors.BusinessError)
+	if !ok {
+		t.Fatalf("expected BusinessError with code %d, got %v", code, err)
+	}
+	if bizErr.Code != code {
+		t.Fatalf("expected code %d, got %d (%s)", code, bizErr.Code, bizErr.Message)
+	}
+}
+
+func TestUploadFileSignsForUploader(t *testing.T) {
+	repo := newMockFileRepo()
+	logic := newTestFileLogic(repo)
+
+	resp, err := logic.UploadFile(context.Background(), &UploadFileRequest{
+		FileData: []byte("%PDF-1.4"),
+		FileName: "invoice.pdf",
+		Category: "invoice",
+		OwnerID:  42,
+	})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if got := repo.files[resp.FileInfo.FileID].OwnerID; got != 42 {
+		t.Fatalf("stored owner = %d, want 42", got)
+	}
+	if uid, _ := verifySigned(t, resp.FileInfo.FileURL); uid != 42 {
+		t.Fatalf("upload URL bound to uid %d, want 42", uid)
+	}
+
+	batch, err := logic.BatchUploadFile(context.Background(), &BatchUploadFileRequest{
+		FileDataList: [][]byte{[]byte("a"), []byte("b")},
+		FileNames:    []string{"a.pdf", "b.pdf"},
+		Category:     "invoice",
+		OwnerID:      42,
+	})
+	if err != nil {
+		t.Fatalf("BatchUploadFile: %v", err)
+	}
+	for _, fi := range batch.FileInfos {
+		if uid, _ := verifySigned(t, fi.FileURL); uid != 42 {
+			t.Fatalf("batch upload URL bound to uid %d, want 42", uid)
+		}
+	}
+}
+
+func TestGetFileURLOnlyForOwner(t *testing.T) {
+	repo := newMockFileRepo()
+	logic := newTestFileLogic(repo)
+	ctx := context.Background()
+
+	private, err := logic.UploadFile(ctx, &UploadFileRequest{FileData: []byte("x"), FileName: "a.pdf", Category: "invoice", OwnerID: 42})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	fileID := private.FileInfo.FileID
+
+	// 未登录
+	_, err = logic.GetFileURL(ctx, &GetFileURLRequest{FileID: fileID})
+	assertCode(t, err, apperrors.CodeUnauthorized)
+
+	// 其他用户不能为别人的私有文件签发链接
+	_, err = logic.GetFileURL(ctx, &GetFileURLRequest{FileID: fileID, UserID: 7})
+	assertCode(t, err, apperrors.CodeForbidden)
+
+	// 上传者本人：绑定本人，有效期为服务端默认值
+	resp, err := logic.GetFileURL(ctx, &GetFileURLRequest{FileID: fileID, UserID: 42})
+	if err != nil {
+		t.Fatalf("GetFileURL by owner: %v", err)
+	}
+	uid, ttl := verifySigned(t, resp.FileURL)
+	if uid != 42 || ttl > 900*time.Second {
+		t.Fatalf("owner URL uid=%d ttl=%v, want uid 42 and ttl <= 900s", uid, ttl)
+	}
+
+	// 匿名上传的私有文件没有归属，不对任何登录用户签发
+	anonymous, err := logic.UploadFile(ctx, &UploadFileRequest{FileData: []byte("y"), FileName: "b.pdf", Category: "invoice"})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	_, err = logic.GetFileURL(ctx, &GetFileURLRequest{FileID: anonymous.FileInfo.FileID, UserID: 7})
+	assertCode(t, err, apperrors.CodeForbidden)
+
+	// 公开分类直接返回原始 URL
+	public, err := logic.UploadFile(ctx, &UploadFileRequest{FileData: []byte("z"), FileName: "c.png", Category: "avatar", OwnerID: 42})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	resp, err = logic.GetFileURL(ctx, &GetFileURLRequest{FileID: public.FileInfo.FileID, UserID: 7})
+	if err != nil || resp.ExpiresAt != 0 || resp.FileURL != public.FileInfo.FileURL {
+		t.Fatalf("public file URL = %+v, %v", resp, err)
+	}
+}
+
+func TestSignFileURLCapsExpiry(t *testing.T) {
+	repo := newMockFileRepo()
+	logic := newTestFileLogic(repo)
+	ctx := context.Background()
+
+	uploaded, err := logic.UploadFile(ctx, &UploadFileRequest{FileData: []byte("x"), FileName: "a.pdf", Category: "invoice"})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	resp, err := logic.SignFileURL(ctx, &SignFileURLRequest{FileID: uploaded.FileInfo.FileID, ExpireSeconds: 365 * 86400, UserID: 9})
+	if err != nil {
+		t.Fatalf("SignFileURL: %v", err)
+	}
+	uid, ttl := verifySigned(t, resp.FileURL)
+	if uid != 9 || ttl > 86400*time.Second {
+		t.Fatalf("signed URL uid=%d ttl=%v, want uid 9 and ttl <= MaxTTL", uid, ttl)
+	}
+
+	_, err = logic.SignFileURL(ctx, &SignFileURLRequest{FileID: "missing"})
+	assertCode(t, err, apperrors.CodeNotFound)
+}
+
+// streamRepo 把流式上传的内容读完，读取出错时原样返回（与本地仓库一致）
+type streamRepo struct {
+	mockFileRepo
+	calls    int
+	received int64
+}
+
+func (m *streamRepo) UploadFileStream(ctx context.Context, r io.Reader, fileName, fileType, category string, ownerID uint64) (*model.FileInfo, error) {
+	m.calls++
+	n, err := io.Copy(io.Discard, r)
+	m.received = n
+	if err != nil {
+		return nil, fmt.Errorf("写入文件失败: %w", err)
+	}
+	return &model.FileInfo{FileID: "s1", FileName: fileName, FileURL: "/uploads/" + category + "/s1.pdf", FileSize: n, Category: category, OwnerID: ownerID}, nil
+}
+
+func TestUploadFileStreamSizeLimit(t *testing.T) {
+	const maxSize = 1024
+	repo := &streamRepo{mockFileRepo: *newMockFileRepo()}
+	logic := NewFileLogic(repo, signedurl.Config{}, maxSize)
+	ctx := context.Background()
+
+	// 声明的大小超限：不读取内容直接拒绝
+	_, err := logic.UploadFileStream(ctx, &UploadFileStreamRequest{
+		Reader: bytes.NewReader(make([]byte, 10)), FileName: "a.pdf", Category: "document", Size: maxSize + 1,
+	})
+	assertCode(t, err, apperrors.CodeInvalidParam)
+	if repo.calls != 0 {
+		t.Fatalf("repository called for a declared oversized file")
+	}
+
+	// 未声明大小：读到超限为止，不会读完整个流
+	_, err = logic.UploadFileStream(ctx, &UploadFileStreamRequest{
+		Reader: bytes.NewReader(make([]byte, maxSize*64)), FileName: "a.pdf", Category: "document",
+	})
+	assertCode(t, err, apperrors.CodeInvalidParam)
+	if repo.received >= maxSize*64 {
+		t.Fatalf("read %d bytes past the limit", repo.received)
+	}
+
+	// 恰好等于上限可以上传
+	resp, err := logic.UploadFileStream(ctx, &UploadFileStreamRequest{
+		Reader: bytes.NewReader(make([]byte, maxSize)), FileName: "a.pdf", Category: "document", OwnerID: 42,
+	})
+	if err != nil {
+		t.Fatalf("UploadFileStream at limit: %v", err)
+	}
+	if resp.FileInfo.FileSize != maxSize || resp.FileInfo.OwnerID != 42 {
+		t.Fatalf("unexpected file info: %+v", resp.FileInfo)
+	}
+}
+
+func TestUploadFileStreamValidatesRequest(t *testing.T) {
+	repo := &streamRepo{mockFileRepo: *newMockFileRepo()}
+	logic := NewFileLogic(repo, signedurl.Config{}, 0)
+
+	for _, req := range []*UploadFileStreamRequest{
+		{Reader: bytes.NewReader([]byte("x")), Category: "document"},
+		{Reader: bytes.NewReader([]byte("x")), FileName: "a.pdf", Category: "../etc"},
+		{Reader: bytes.NewReader([]byte("x")), FileName: "a.pdf", Category: ""},
+	} {
+		_, err := logic.UploadFileStream(context.Background(), req)
+		assertCode(t, err, apperrors.CodeInvalidParam)
+	}
+	if repo.calls != 0 {
+		t.Fatalf("repository called %d times for invalid requests", repo.calls)
+	}
+
+	// 客户端中途断开等读取错误按内部错误返回
+	_, err := logic.UploadFileStream(context.Background(), &UploadFileStreamRequest{
+		Reader: io.MultiReader(bytes.NewReader([]byte("partial")), iotest.ErrReader(errors.New("stream reset"))), FileName: "a.pdf", Category: "document",
+	})
+	assertCode(t, err, apperrors.CodeInternalError)
+}
@@ -10,6 +10,12 @@ type Config struct {
 	Database DatabaseConfig
 	BizRedis RedisConfig // 业务侧使用的 Redis 配置，避免与 zrpc.RpcServerConf 内置的 Redis 字段冲突
 	Kafka    *KafkaConfig
+	JWT      JWTConfig `json:",optional"`
+}
+
+// JWTConfig JWT配置（校验管理接口权限）
+type JWTConfig struct {
+	Secret string
 }
 
 // KafkaConfig Kafka配置
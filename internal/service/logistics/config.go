@@ -2,6 +2,8 @@ package logistics
 
 import (
 	"github.com/zeromicro/go-zero/zrpc"
+
+	"ecommerce-system/internal/pkg/client"
 )
 
 // Config 物流服务配置
@@ -9,6 +11,24 @@ type Config struct {
 	zrpc.RpcServerConf
 	Database DatabaseConfig
 	BizRedis RedisConfig // 业务侧 Redis，用于 idgen 物流单号生成
+	// UserRpc 用户服务地址，计算运费时查询会员包邮次数；不配置时不包邮
+	UserRpc client.RpcConf `json:",optional"`
+	// OrderRpc 订单服务地址，处理个人数据导出/删除时查找用户的订单；不配置时这两个接口不可用
+	OrderRpc client.RpcConf `json:",optional"`
+	// Kafka 物流状态变更后发布 logistics.updated / logistics.delivered（需要 OrderRpc 查询订单所属用户），不配置则不发布
+	Kafka KafkaConfig `json:",optional"`
+	JWT   JWTConfig   // 个人数据接口校验 user-service 签发的服务令牌，与 user-service 保持一致
+}
+
+// JWTConfig JWT配置
+type JWTConfig struct {
+	Secret string
+}
+
+// KafkaConfig Kafka配置
+type KafkaConfig struct {
+	Brokers []string `json:",optional"`
+	Version string   `json:",optional"`
 }
 
 // RedisConfig Redis配置
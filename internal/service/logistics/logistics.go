@@ -1,11 +1,16 @@
 package logistics
 
 import (
+	"log"
+
+	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
 
 	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/client"
 	"ecommerce-system/internal/pkg/database"
 	"ecommerce-system/internal/pkg/idgen"
+	"ecommerce-system/internal/pkg/mq"
 	"ecommerce-system/internal/service/logistics/repository"
 )
 
@@ -15,6 +20,9 @@ type ServiceContext struct {
 	DB            *gorm.DB
 	IDGen         *idgen.Generator
 	LogisticsRepo repository.LogisticsRepository
+	UserClient    *client.UserClient  // 为 nil 时不计算会员包邮
+	OrderClient   *client.OrderClient // 为 nil 时不能处理个人数据请求，也不发布物流事件
+	MQProducer    *mq.Producer        // 为 nil 时不发布物流事件
 }
 
 // NewServiceContext 创建服务上下文。DB 初始化失败直接 Fatal，不静默放行。
@@ -45,10 +53,42 @@ func NewServiceContext(c Config) *ServiceContext {
 		ig = idgen.New(rdb)
 	}
 
-	return &ServiceContext{
+	// 用户服务客户端（endpoint 为空则跳过，方便单独启动调试）
+	var userClient *client.UserClient
+	if c.UserRpc.Endpoint != "" {
+		uc, err := client.NewUserClient(c.UserRpc)
+		logx.Must(err)
+		userClient = uc
+	}
+	var orderClient *client.OrderClient
+	if c.OrderRpc.Endpoint != "" {
+		oc, err := client.NewOrderClient(c.OrderRpc)
+		logx.Must(err)
+		orderClient = oc
+	}
+
+	ctx := &ServiceContext{
 		Config:        c,
 		DB:            db,
 		IDGen:         ig,
 		LogisticsRepo: repository.NewLogisticsRepository(db),
+		UserClient:    userClient,
+		OrderClient:   orderClient,
 	}
+
+	// Kafka 生产者可选（不影响主链路）
+	if len(c.Kafka.Brokers) > 0 {
+		mqProducer, err := mq.NewProducer(&mq.Config{
+			Brokers:       c.Kafka.Brokers,
+			Version:       c.Kafka.Version,
+			ProducerAsync: true,
+		})
+		if err != nil {
+			log.Printf("警告：初始化Kafka生产者失败: %v", err)
+		} else {
+			ctx.MQProducer = mqProducer
+		}
+	}
+
+	return ctx
 }
@@ -22,7 +22,7 @@ type LogisticsService struct {
 
 // NewLogisticsService 创建物流服务
 func NewLogisticsService(svcCtx *ServiceContext) *LogisticsService {
-	logic := service.NewLogisticsLogic(svcCtx.LogisticsRepo, svcCtx.IDGen)
+	logic := service.NewLogisticsLogic(svcCtx.LogisticsRepo, svcCtx.IDGen, svcCtx.UserClient, svcCtx.OrderClient, svcCtx.MQProducer)
 
 	return &LogisticsService{
 		svcCtx: svcCtx,
@@ -127,6 +127,8 @@ func (s *LogisticsService) CalculateFreight(ctx context.Context, req *v1.Calcula
 		District: req.District,
 		Weight:   req.Weight,
 		Volume:   req.Volume,
+		UserID:   uint64(req.UserId),
+		OrderNo:  req.OrderNo,
 	}
 
 	resp, err := s.logic.CalculateFreight(ctx, calcReq)
@@ -135,9 +137,10 @@ func (s *LogisticsService) CalculateFreight(ctx context.Context, req *v1.Calcula
 	}
 
 	return &v1.CalculateFreightResponse{
-		Code:    0,
-		Message: "成功",
-		Freight: strconv.FormatFloat(resp.Freight, 'f', 2, 64),
+		Code:               0,
+		Message:            "成功",
+		Freight:            strconv.FormatFloat(resp.Freight, 'f', 2, 64),
+		MemberFreeShipping: resp.MemberFreeShipping,
 	}, nil
 }
 
@@ -17,6 +17,10 @@ type LogisticsRepository interface {
 	GetByLogisticsNo(ctx context.Context, logisticsNo string) (*model.Logistics, error)
 	// Update 更新物流信息
 	Update(ctx context.Context, logistics *model.Logistics) error
+	// ListByOrderIDs 根据订单ID批量获取
+	ListByOrderIDs(ctx context.Context, orderIDs []uint64) ([]*model.Logistics, error)
+	// AnonymizeByOrderIDs 去除物流单的收货人信息，物流单本身保留，返回处理的数量
+	AnonymizeByOrderIDs(ctx context.Context, orderIDs []uint64, receiverName string) (int64, error)
 }
 
 type logisticsRepository struct {
@@ -57,3 +61,28 @@ func (r *logisticsRepository) GetByLogisticsNo(ctx context.Context, logisticsNo
 func (r *logisticsRepository) Update(ctx context.Context, logistics *model.Logistics) error {
 	return r.db.WithContext(ctx).Save(logistics).Error
 }
+
+// ListByOrderIDs 根据订单ID批量获取
+func (r *logisticsRepository) ListByOrderIDs(ctx context.Context, orderIDs []uint64) ([]*model.Logistics, error) {
+	var list []*model.Logistics
+	if len(orderIDs) == 0 {
+		return list, nil
+	}
+	err := r.db.WithContext(ctx).Where("order_id IN ?", orderIDs).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+// AnonymizeByOrderIDs 匿名化物流单收货人信息
+func (r *logisticsRepository) AnonymizeByOrderIDs(ctx context.Context, orderIDs []uint64, receiverName string) (int64, error) {
+	if len(orderIDs) == 0 {
+		return 0, nil
+	}
+	res := r.db.WithContext(ctx).Model(&model.Logistics{}).
+		Where("order_id IN ?", orderIDs).
+		Updates(map[string]interface{}{
+			"receiver_name":    receiverName,
+			"receiver_phone":   "",
+			"receiver_address": "",
+		})
+	return res.RowsAffected, res.Error
+}
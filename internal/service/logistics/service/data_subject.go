@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/logistics/repository"
+)
+
+// DataSubjectHandler 物流服务的个人数据导出和删除。
+// 物流单不记录用户ID，通过订单服务查出用户的订单后按订单处理；物流单随订单保留，只去除收货人信息。
+type DataSubjectHandler struct {
+	logisticsRepo repository.LogisticsRepository
+	orderClient   *client.OrderClient
+}
+
+// NewDataSubjectHandler 创建物流个人数据处理器
+func NewDataSubjectHandler(logisticsRepo repository.LogisticsRepository, orderClient *client.OrderClient) *DataSubjectHandler {
+	return &DataSubjectHandler{logisticsRepo: logisticsRepo, orderClient: orderClient}
+}
+
+// Export 导出用户订单的物流单
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	orderIDs, err := h.orderIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := h.logisticsRepo.ListByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Section{{
+		Name:        "logistics",
+		Description: "物流信息",
+		Records:     list,
+		Count:       len(list),
+	}}, nil
+}
+
+// Erase 匿名化用户订单的物流单
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	orderIDs, err := h.orderIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	n, err := h.logisticsRepo.AnonymizeByOrderIDs(ctx, orderIDs, datasubject.ErasedName)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Result{{
+		Name:       "logistics",
+		Anonymized: n,
+		Note:       "物流单随订单保留，已去除收货人信息",
+	}}, nil
+}
+
+func (h *DataSubjectHandler) orderIDs(ctx context.Context, userID uint64) ([]uint64, error) {
+	if h.orderClient == nil {
+		return nil, errors.New("未配置订单服务地址，无法查找用户的物流单")
+	}
+	return h.orderClient.ListOrderIDs(ctx, userID)
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"time"
 
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/client"
 	apperrors "ecommerce-system/internal/pkg/errors"
 	"ecommerce-system/internal/pkg/idgen"
+	"ecommerce-system/internal/pkg/mq"
 	"ecommerce-system/internal/service/logistics/model"
 	"ecommerce-system/internal/service/logistics/repository"
 )
@@ -14,13 +18,19 @@ import (
 type LogisticsLogic struct {
 	logisticsRepo repository.LogisticsRepository
 	idGen         *idgen.Generator
+	userClient    *client.UserClient  // 查询会员包邮次数，为 nil 时不包邮
+	orderClient   *client.OrderClient // 查询订单所属用户，为 nil 时不发布物流事件
+	mqProducer    *mq.Producer
 }
 
 // NewLogisticsLogic 创建物流业务逻辑
-func NewLogisticsLogic(logisticsRepo repository.LogisticsRepository, idGen *idgen.Generator) *LogisticsLogic {
+func NewLogisticsLogic(logisticsRepo repository.LogisticsRepository, idGen *idgen.Generator, userClient *client.UserClient, orderClient *client.OrderClient, mqProducer *mq.Producer) *LogisticsLogic {
 	return &LogisticsLogic{
 		logisticsRepo: logisticsRepo,
 		idGen:         idGen,
+		userClient:    userClient,
+		orderClient:   orderClient,
+		mqProducer:    mqProducer,
 	}
 }
 
@@ -118,9 +128,39 @@ func (l *LogisticsLogic) UpdateLogisticsStatus(ctx context.Context, req *UpdateL
 		return apperrors.NewInternalError("更新物流状态失败")
 	}
 
+	l.publishStatusChanged(ctx, logistics, req.Remark, now)
 	return nil
 }
 
+// publishStatusChanged 发布物流状态变更事件，推送网关据此通知用户；
+// 物流单上没有用户ID，需要向订单服务查询，查询失败只记录日志
+func (l *LogisticsLogic) publishStatusChanged(ctx context.Context, logistics *model.Logistics, remark string, at time.Time) {
+	if l.mqProducer == nil || l.orderClient == nil {
+		return
+	}
+	order, err := l.orderClient.GetOrder(ctx, int64(logistics.OrderID), logistics.OrderNo)
+	if err != nil {
+		logx.Errorf("查询订单失败，未发布物流事件 logistics_no=%s: %v", logistics.LogisticsNo, err)
+		return
+	}
+
+	topic := mq.TopicLogisticsUpdated
+	if logistics.Status == 3 {
+		topic = mq.TopicLogisticsDelivered
+	}
+	msg := mq.NewMessage(topic, map[string]interface{}{
+		"order_id":     logistics.OrderID,
+		"order_no":     logistics.OrderNo,
+		"user_id":      order.UserId,
+		"logistics_no": logistics.LogisticsNo,
+		"carrier":      logistics.LogisticsCompany,
+		"status":       logistics.Status,
+		"remark":       remark,
+		"updated_at":   at.Format(time.RFC3339),
+	})
+	_ = l.mqProducer.PublishWithKey(ctx, topic, logistics.OrderNo, msg)
+}
+
 // QueryTrackingRequest 查询物流轨迹请求
 type QueryTrackingRequest struct {
 	LogisticsNo string
@@ -183,11 +223,14 @@ type CalculateFreightRequest struct {
 	District string
 	Weight   float64
 	Volume   float64
+	UserID   uint64 // 可选，会员有包邮次数时免运费
+	OrderNo  string // 可选，下单结算时传入，包邮时占用一次包邮次数
 }
 
 // CalculateFreightResponse 计算运费响应
 type CalculateFreightResponse struct {
-	Freight float64
+	Freight            float64
+	MemberFreeShipping bool // 是否使用了会员包邮
 }
 
 // CalculateFreight 计算运费（简化规则：基础费 10 元，重量每千克 2 元，体积每立方厘米 1.5 元）
@@ -201,5 +244,29 @@ func (l *LogisticsLogic) CalculateFreight(ctx context.Context, req *CalculateFre
 		freight = 10
 	}
 
+	if l.memberFreeShipping(ctx, req.UserID, req.OrderNo) {
+		return &CalculateFreightResponse{Freight: 0, MemberFreeShipping: true}, nil
+	}
 	return &CalculateFreightResponse{Freight: freight}, nil
 }
+
+// memberFreeShipping 会员本月是否还有包邮次数；带订单号时占用一次。用户服务不可用时按不包邮处理
+func (l *LogisticsLogic) memberFreeShipping(ctx context.Context, userID uint64, orderNo string) bool {
+	if userID == 0 || l.userClient == nil {
+		return false
+	}
+	if orderNo != "" {
+		granted, err := l.userClient.UseMemberFreeShipping(ctx, int64(userID), orderNo)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("占用会员包邮次数失败 user_id=%d order_no=%s: %v", userID, orderNo, err)
+			return false
+		}
+		return granted
+	}
+	benefits, err := l.userClient.GetMemberBenefits(ctx, int64(userID))
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询会员权益失败 user_id=%d: %v", userID, err)
+		return false
+	}
+	return benefits.GetFreeShippingRemaining() > 0
+}
@@ -8,8 +8,14 @@ import (
 type Config struct {
 	zrpc.RpcServerConf
 	Database DatabaseConfig
-	BizRedis RedisConfig // 业务侧使用的 Redis 配置，避免与 zrpc.RpcServerConf 内置的 Redis 字段冲突
+	BizRedis RedisConfig  // 业务侧使用的 Redis 配置，避免与 zrpc.RpcServerConf 内置的 Redis 字段冲突
 	Kafka    *KafkaConfig `json:",optional"` // Kafka 配置（可选，不配置则不启动消费者）
+	JWT      JWTConfig    // 个人数据接口校验 user-service 签发的服务令牌，与 user-service 保持一致
+}
+
+// JWTConfig JWT配置
+type JWTConfig struct {
+	Secret string
 }
 
 // KafkaConfig Kafka配置
@@ -21,6 +21,7 @@ type ServiceContext struct {
 	Redis       *redis.Client
 	Cache       *cache.CacheOperations
 	MessageRepo repository.MessageRepository
+	MQProducer  *mq.Producer // 可选：发布 message.created 事件供推送网关实时推送
 }
 
 // NewServiceContext 创建服务上下文。DB/Redis 初始化失败直接 Fatal，不静默放行。
@@ -57,6 +58,20 @@ func NewServiceContext(c Config) *ServiceContext {
 		MessageRepo: msgRepo,
 	}
 
+	// Kafka 生产者（可选）：站内消息创建后发布 message.created 事件
+	if c.Kafka != nil && len(c.Kafka.Brokers) > 0 {
+		producer, err := mq.NewProducer(&mq.Config{
+			Brokers:       c.Kafka.Brokers,
+			Version:       c.Kafka.Version,
+			ProducerAsync: true,
+		})
+		if err != nil {
+			log.Printf("警告：初始化Kafka生产者失败: %v", err)
+		} else {
+			svcCtx.MQProducer = producer
+		}
+	}
+
 	// Kafka 消费者（可选）：监听订单/支付事件并发送站内消息
 	if c.Kafka != nil && len(c.Kafka.Brokers) > 0 {
 		consumerGroup := c.Kafka.ConsumerGroup
@@ -71,7 +86,7 @@ func NewServiceContext(c Config) *ServiceContext {
 		if err != nil {
 			log.Printf("警告：初始化Kafka消费者失败: %v", err)
 		} else {
-			logic := service.NewMessageLogic(msgRepo)
+			logic := service.NewMessageLogic(msgRepo, svcCtx.MQProducer)
 			mc := service.NewMessageConsumer(logic)
 
 			consumer.RegisterHandler(mq.TopicOrderCreated, mc.HandleOrderCreated)
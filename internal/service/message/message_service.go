@@ -21,7 +21,7 @@ type MessageService struct {
 
 // NewMessageService 创建消息服务
 func NewMessageService(svcCtx *ServiceContext) *MessageService {
-	logic := service.NewMessageLogic(svcCtx.MessageRepo)
+	logic := service.NewMessageLogic(svcCtx.MessageRepo, svcCtx.MQProducer)
 
 	return &MessageService{
 		svcCtx: svcCtx,
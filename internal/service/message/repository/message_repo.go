@@ -21,6 +21,10 @@ type MessageRepository interface {
 	BatchMarkAsRead(ctx context.Context, userID uint64, messageIDs []uint64) error
 	// GetUnreadCount 获取未读数量
 	GetUnreadCount(ctx context.Context, userID uint64) (int64, error)
+	// ListAllByUser 获取用户全部消息，用于个人数据导出
+	ListAllByUser(ctx context.Context, userID uint64) ([]*model.Message, error)
+	// DeleteAllByUser 删除用户全部消息，返回删除数量
+	DeleteAllByUser(ctx context.Context, userID uint64) (int64, error)
 }
 
 type messageRepository struct {
@@ -86,3 +90,16 @@ func (r *messageRepository) GetUnreadCount(ctx context.Context, userID uint64) (
 		Count(&count).Error
 	return count, err
 }
+
+// ListAllByUser 获取用户全部消息
+func (r *messageRepository) ListAllByUser(ctx context.Context, userID uint64) ([]*model.Message, error) {
+	var messages []*model.Message
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&messages).Error
+	return messages, err
+}
+
+// DeleteAllByUser 删除用户全部消息
+func (r *messageRepository) DeleteAllByUser(ctx context.Context, userID uint64) (int64, error) {
+	res := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.Message{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/message/repository"
+)
+
+// DataSubjectHandler 消息服务的个人数据导出和删除，注销时站内信全部删除
+type DataSubjectHandler struct {
+	messageRepo repository.MessageRepository
+}
+
+// NewDataSubjectHandler 创建消息个人数据处理器
+func NewDataSubjectHandler(messageRepo repository.MessageRepository) *DataSubjectHandler {
+	return &DataSubjectHandler{messageRepo: messageRepo}
+}
+
+// Export 导出用户站内信
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	messages, err := h.messageRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Section{{
+		Name:        "messages",
+		Description: "站内信",
+		Records:     messages,
+		Count:       len(messages),
+	}}, nil
+}
+
+// Erase 删除用户站内信
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	n, err := h.messageRepo.DeleteAllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Result{{Name: "messages", Deleted: n}}, nil
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/mq"
 	"ecommerce-system/internal/service/message/model"
 	"ecommerce-system/internal/service/message/repository"
 )
@@ -12,12 +14,14 @@ import (
 // MessageLogic 消息业务逻辑
 type MessageLogic struct {
 	messageRepo repository.MessageRepository
+	mqProducer  *mq.Producer // 可选，为空时不发布 message.created
 }
 
 // NewMessageLogic 创建消息业务逻辑
-func NewMessageLogic(messageRepo repository.MessageRepository) *MessageLogic {
+func NewMessageLogic(messageRepo repository.MessageRepository, mqProducer *mq.Producer) *MessageLogic {
 	return &MessageLogic{
 		messageRepo: messageRepo,
+		mqProducer:  mqProducer,
 	}
 }
 
@@ -49,6 +53,18 @@ func (l *MessageLogic) SendMessage(ctx context.Context, req *SendMessageRequest)
 		return apperrors.NewInternalError("发送消息失败")
 	}
 
+	// 发布站内消息创建事件（推送网关据此实时通知在线设备），失败不影响主流程
+	if l.mqProducer != nil {
+		msg := mq.NewMessage(mq.TopicMessageCreated, map[string]interface{}{
+			"message_id": message.ID,
+			"user_id":    message.UserID,
+			"type":       message.Type,
+			"title":      message.Title,
+			"link":       req.Link,
+		})
+		_ = l.mqProducer.PublishWithKey(ctx, mq.TopicMessageCreated, strconv.FormatUint(message.UserID, 10), msg)
+	}
+
 	return nil
 }
 
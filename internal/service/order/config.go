@@ -33,14 +33,20 @@ type RedisConfig struct {
 // Config 订单服务配置
 type Config struct {
 	zrpc.RpcServerConf
-	Database      DatabaseConfig
-	BizRedis      RedisConfig    // 业务侧使用的 Redis 配置
-	Kafka         KafkaConfig
-	UserRpc       client.RpcConf // 用户服务地址
-	ProductRpc    client.RpcConf // 商品服务地址
-	InventoryRpc  client.RpcConf // 库存服务地址
-	LogisticsRpc  client.RpcConf // 物流服务地址（发货时建运单）
-	PromotionRpc  client.RpcConf // 营销服务地址（创建订单时计算优惠）
+	Database     DatabaseConfig
+	BizRedis     RedisConfig // 业务侧使用的 Redis 配置
+	Kafka        KafkaConfig
+	UserRpc      client.RpcConf // 用户服务地址
+	ProductRpc   client.RpcConf // 商品服务地址
+	InventoryRpc client.RpcConf // 库存服务地址
+	LogisticsRpc client.RpcConf // 物流服务地址（发货时建运单）
+	PromotionRpc client.RpcConf // 营销服务地址（创建订单时计算优惠）
+	JWT          JWTConfig      `json:",optional"`
+}
+
+// JWTConfig JWT配置（校验管理接口权限）
+type JWTConfig struct {
+	Secret string
 }
 
 // KafkaConfig Kafka配置
@@ -32,28 +32,31 @@ const (
 
 // Order 订单表
 type Order struct {
-	ID              uint64      `gorm:"primaryKey;column:id" json:"id"`
-	OrderNo         string      `gorm:"column:order_no;type:varchar(32);uniqueIndex;not null" json:"order_no"`
-	UserID          uint64      `gorm:"column:user_id;type:bigint unsigned;not null;index" json:"user_id"`
-	OrderType       int8        `gorm:"column:order_type;type:tinyint;default:1" json:"order_type"`
-	Status          int8        `gorm:"column:status;type:tinyint;not null;index" json:"status"`
-	TotalAmount     float64     `gorm:"column:total_amount;type:decimal(10,2);not null" json:"total_amount"`
-	PayAmount       float64     `gorm:"column:pay_amount;type:decimal(10,2);not null" json:"pay_amount"`
-	DiscountAmount  float64     `gorm:"column:discount_amount;type:decimal(10,2);default:0" json:"discount_amount"`
-	FreightAmount   float64     `gorm:"column:freight_amount;type:decimal(10,2);default:0" json:"freight_amount"`
-	ReceiverName    string      `gorm:"column:receiver_name;type:varchar(50);not null" json:"receiver_name"`
-	ReceiverPhone   string      `gorm:"column:receiver_phone;type:varchar(20);not null" json:"receiver_phone"`
-	ReceiverAddress string      `gorm:"column:receiver_address;type:varchar(500);not null" json:"receiver_address"`
-	PaymentMethod   *int8       `gorm:"column:payment_method;type:tinyint" json:"payment_method"`
-	PaymentTime     *time.Time  `gorm:"column:payment_time;type:datetime" json:"payment_time"`
-	DeliveryTime    *time.Time  `gorm:"column:delivery_time;type:datetime" json:"delivery_time"`
-	ReceiveTime     *time.Time  `gorm:"column:receive_time;type:datetime" json:"receive_time"`
-	CancelTime      *time.Time  `gorm:"column:cancel_time;type:datetime" json:"cancel_time"`
-	CancelReason    *string     `gorm:"column:cancel_reason;type:varchar(255)" json:"cancel_reason"`
-	Remark          *string     `gorm:"column:remark;type:varchar(500)" json:"remark"`
-	Items           []OrderItem `gorm:"foreignKey:OrderID;references:ID" json:"items"` // 关联订单项
-	CreatedAt       time.Time   `gorm:"column:created_at;type:datetime;not null;index" json:"created_at"`
-	UpdatedAt       time.Time   `gorm:"column:updated_at;type:datetime;not null" json:"updated_at"`
+	ID                   uint64      `gorm:"primaryKey;column:id" json:"id"`
+	OrderNo              string      `gorm:"column:order_no;type:varchar(32);uniqueIndex;not null" json:"order_no"`
+	UserID               uint64      `gorm:"column:user_id;type:bigint unsigned;not null;index" json:"user_id"`
+	OrderType            int8        `gorm:"column:order_type;type:tinyint;default:1" json:"order_type"`
+	Status               int8        `gorm:"column:status;type:tinyint;not null;index" json:"status"`
+	TotalAmount          float64     `gorm:"column:total_amount;type:decimal(10,2);not null" json:"total_amount"`
+	PayAmount            float64     `gorm:"column:pay_amount;type:decimal(10,2);not null" json:"pay_amount"`
+	DiscountAmount       float64     `gorm:"column:discount_amount;type:decimal(10,2);default:0" json:"discount_amount"`
+	FreightAmount        float64     `gorm:"column:freight_amount;type:decimal(10,2);default:0" json:"freight_amount"`
+	ReceiverName         string      `gorm:"column:receiver_name;type:varchar(50);not null" json:"receiver_name"`
+	ReceiverPhone        string      `gorm:"column:receiver_phone;type:varchar(20);not null" json:"receiver_phone"`
+	ReceiverAddress      string      `gorm:"column:receiver_address;type:varchar(500);not null" json:"receiver_address"`
+	ReceiverProvinceCode string      `gorm:"column:receiver_province_code;type:varchar(6)" json:"receiver_province_code"` // 收货地址行政区划代码，旧地址无法识别时为空
+	ReceiverCityCode     string      `gorm:"column:receiver_city_code;type:varchar(6)" json:"receiver_city_code"`
+	ReceiverDistrictCode string      `gorm:"column:receiver_district_code;type:varchar(6)" json:"receiver_district_code"`
+	PaymentMethod        *int8       `gorm:"column:payment_method;type:tinyint" json:"payment_method"`
+	PaymentTime          *time.Time  `gorm:"column:payment_time;type:datetime" json:"payment_time"`
+	DeliveryTime         *time.Time  `gorm:"column:delivery_time;type:datetime" json:"delivery_time"`
+	ReceiveTime          *time.Time  `gorm:"column:receive_time;type:datetime" json:"receive_time"`
+	CancelTime           *time.Time  `gorm:"column:cancel_time;type:datetime" json:"cancel_time"`
+	CancelReason         *string     `gorm:"column:cancel_reason;type:varchar(255)" json:"cancel_reason"`
+	Remark               *string     `gorm:"column:remark;type:varchar(500)" json:"remark"`
+	Items                []OrderItem `gorm:"foreignKey:OrderID;references:ID" json:"items"` // 关联订单项
+	CreatedAt            time.Time   `gorm:"column:created_at;type:datetime;not null;index" json:"created_at"`
+	UpdatedAt            time.Time   `gorm:"column:updated_at;type:datetime;not null" json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -218,21 +218,24 @@ func convertOrderToProto(o *model.Order) *v1.Order {
 	}
 
 	order := &v1.Order{
-		Id:              int64(o.ID),
-		OrderNo:         o.OrderNo,
-		UserId:          int64(o.UserID),
-		OrderType:       int32(o.OrderType),
-		Status:          int32(o.Status),
-		TotalAmount:     formatDecimal(o.TotalAmount),
-		PayAmount:       formatDecimal(o.PayAmount),
-		DiscountAmount:  formatDecimal(o.DiscountAmount),
-		FreightAmount:   formatDecimal(o.FreightAmount),
-		ReceiverName:    o.ReceiverName,
-		ReceiverPhone:   o.ReceiverPhone,
-		ReceiverAddress: o.ReceiverAddress,
-		Items:           items, // 添加订单项
-		CreatedAt:       formatTime(&o.CreatedAt),
-		UpdatedAt:       formatTime(&o.UpdatedAt),
+		Id:                   int64(o.ID),
+		OrderNo:              o.OrderNo,
+		UserId:               int64(o.UserID),
+		OrderType:            int32(o.OrderType),
+		Status:               int32(o.Status),
+		TotalAmount:          formatDecimal(o.TotalAmount),
+		PayAmount:            formatDecimal(o.PayAmount),
+		DiscountAmount:       formatDecimal(o.DiscountAmount),
+		FreightAmount:        formatDecimal(o.FreightAmount),
+		ReceiverName:         o.ReceiverName,
+		ReceiverPhone:        o.ReceiverPhone,
+		ReceiverAddress:      o.ReceiverAddress,
+		ReceiverProvinceCode: o.ReceiverProvinceCode,
+		ReceiverCityCode:     o.ReceiverCityCode,
+		ReceiverDistrictCode: o.ReceiverDistrictCode,
+		Items:                items, // 添加订单项
+		CreatedAt:            formatTime(&o.CreatedAt),
+		UpdatedAt:            formatTime(&o.UpdatedAt),
 	}
 
 	if o.PaymentMethod != nil {
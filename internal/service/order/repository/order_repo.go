@@ -18,6 +18,10 @@ type OrderRepository interface {
 	Update(ctx context.Context, order *model.Order) error
 	List(ctx context.Context, req *ListOrdersRequest) ([]*model.Order, int64, error)
 	UpdateStatus(ctx context.Context, id uint64, status int8, cancelReason *string) error
+	// ListAllByUser 获取用户全部订单（含订单项），用于个人数据导出
+	ListAllByUser(ctx context.Context, userID uint64) ([]*model.Order, error)
+	// AnonymizeByUser 去除用户订单中的收货人信息和备注，金额等交易数据保留，返回处理的订单数
+	AnonymizeByUser(ctx context.Context, userID uint64, receiverName string) (int64, error)
 }
 
 // ListOrdersRequest 订单列表查询请求
@@ -141,3 +145,28 @@ func (r *orderRepository) UpdateStatus(ctx context.Context, id uint64, status in
 		Where("id = ?", id).
 		Updates(updates).Error
 }
+
+// ListAllByUser 获取用户全部订单
+func (r *orderRepository) ListAllByUser(ctx context.Context, userID uint64) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Preload("Items").
+		Order("id ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// AnonymizeByUser 匿名化用户订单
+func (r *orderRepository) AnonymizeByUser(ctx context.Context, userID uint64, receiverName string) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"receiver_name":    receiverName,
+			"receiver_phone":   "",
+			"receiver_address": "",
+			"remark":           nil,
+			"updated_at":       time.Now(),
+		})
+	return res.RowsAffected, res.Error
+}
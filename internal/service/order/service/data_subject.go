@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/order/repository"
+)
+
+// DataSubjectHandler 订单服务的个人数据导出和删除。
+// 订单属于交易凭证需要保留，注销时只去除收货人姓名、电话、地址和备注。
+type DataSubjectHandler struct {
+	orderRepo repository.OrderRepository
+	cache     *cache.CacheOperations
+}
+
+// NewDataSubjectHandler 创建订单个人数据处理器
+func NewDataSubjectHandler(orderRepo repository.OrderRepository, cacheOps *cache.CacheOperations) *DataSubjectHandler {
+	return &DataSubjectHandler{orderRepo: orderRepo, cache: cacheOps}
+}
+
+// Export 导出用户订单（含订单项）
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	orders, err := h.orderRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Section{{
+		Name:        "orders",
+		Description: "订单及订单商品",
+		Records:     orders,
+		Count:       len(orders),
+	}}, nil
+}
+
+// Erase 匿名化用户订单
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	orders, err := h.orderRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	n, err := h.orderRepo.AnonymizeByUser(ctx, userID, datasubject.ErasedName)
+	if err != nil {
+		return nil, err
+	}
+	if h.cache != nil {
+		for _, o := range orders {
+			_ = h.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixOrderDetail, o.ID))
+		}
+		_ = h.cache.DeletePattern(ctx, fmt.Sprintf("%s%d:*", cache.KeyPrefixOrderList, userID))
+	}
+	return []datasubject.Result{{
+		Name:       "orders",
+		Anonymized: n,
+		Note:       "订单为交易凭证依法保留，已去除收货人信息和备注",
+	}}, nil
+}
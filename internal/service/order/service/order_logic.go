@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"time"
 
+	userv1 "ecommerce-system/api/user/v1"
 	"ecommerce-system/internal/pkg/cache"
 	"ecommerce-system/internal/pkg/client"
 	apperrors "ecommerce-system/internal/pkg/errors"
 	"ecommerce-system/internal/pkg/idgen"
 	"ecommerce-system/internal/pkg/mq"
+	"ecommerce-system/internal/pkg/region"
 	"ecommerce-system/internal/service/order/model"
 	"ecommerce-system/internal/service/order/repository"
 
@@ -106,6 +108,7 @@ func (l *OrderLogic) CreateOrder(ctx context.Context, req *CreateOrderRequest) (
 
 	// 2. 获取收货地址
 	var receiverName, receiverPhone, receiverAddress string
+	var provinceCode, cityCode, districtCode string
 	if l.userClient != nil {
 		addr, err := l.userClient.GetAddressByID(ctx, int64(req.UserID), int64(req.AddressID))
 		if err != nil {
@@ -114,6 +117,7 @@ func (l *OrderLogic) CreateOrder(ctx context.Context, req *CreateOrderRequest) (
 		receiverName = addr.ReceiverName
 		receiverPhone = addr.ReceiverPhone
 		receiverAddress = fmt.Sprintf("%s%s%s%s", addr.Province, addr.City, addr.District, addr.Detail)
+		provinceCode, cityCode, districtCode = receiverRegionCodes(addr)
 	}
 
 	// 3. 查询 SKU 信息，构建订单项，计算总金额
@@ -190,10 +194,10 @@ func (l *OrderLogic) CreateOrder(ctx context.Context, req *CreateOrderRequest) (
 	// 4. 生成订单号
 	orderNo := l.idGen.OrderNo(ctx)
 
-	// 4.5 计算优惠金额（promotion service 可选）
+	// 4.5 计算优惠金额（promotion service 可选）：优惠券和会员折扣
 	discountAmount := 0.0
 	payAmount := totalAmount
-	if req.CouponID > 0 && l.promotionClient != nil {
+	if l.promotionClient != nil {
 		productIDs := make([]int64, 0, len(items))
 		quantities := make([]int32, 0, len(items))
 		for _, item := range items {
@@ -223,6 +227,9 @@ func (l *OrderLogic) CreateOrder(ctx context.Context, req *CreateOrderRequest) (
 		ReceiverPhone:   receiverPhone,
 		ReceiverAddress: receiverAddress,
 	}
+	order.ReceiverProvinceCode = provinceCode
+	order.ReceiverCityCode = cityCode
+	order.ReceiverDistrictCode = districtCode
 	if req.Remark != "" {
 		order.Remark = &req.Remark
 	}
@@ -504,6 +511,7 @@ func (l *OrderLogic) CancelOrder(ctx context.Context, req *CancelOrderRequest) (
 		msg := mq.NewMessage(mq.TopicOrderCancelled, map[string]interface{}{
 			"order_id": order.ID,
 			"order_no": order.OrderNo,
+			"user_id":  order.UserID,
 			"reason":   reason,
 		})
 		_ = l.mqProducer.PublishWithKey(ctx, mq.TopicOrderCancelled, order.OrderNo, msg)
@@ -555,6 +563,18 @@ func (l *OrderLogic) ConfirmReceive(ctx context.Context, req *ConfirmReceiveRequ
 		AfterStatus:  &afterStatus,
 	})
 
+	// 订单完成事件：用户服务据此累计会员消费
+	if l.mqProducer != nil {
+		msg := mq.NewMessage(mq.TopicOrderCompleted, map[string]interface{}{
+			"order_id":     order.ID,
+			"order_no":     order.OrderNo,
+			"user_id":      order.UserID,
+			"pay_amount":   order.PayAmount,
+			"completed_at": time.Now().Format(time.RFC3339),
+		})
+		_ = l.mqProducer.PublishWithKey(ctx, mq.TopicOrderCompleted, order.OrderNo, msg)
+	}
+
 	return &ConfirmReceiveResponse{Success: true}, nil
 }
 
@@ -616,6 +636,18 @@ func (l *OrderLogic) PayOrder(ctx context.Context, req *PayOrderRequest) error {
 		Remark:       strPtr("支付单号: " + req.PaymentNo),
 	})
 
+	if l.mqProducer != nil {
+		msg := mq.NewMessage(mq.TopicOrderPaid, map[string]interface{}{
+			"order_id":   order.ID,
+			"order_no":   order.OrderNo,
+			"user_id":    order.UserID,
+			"pay_amount": order.PayAmount,
+			"payment_no": req.PaymentNo,
+			"paid_at":    now.Format(time.RFC3339),
+		})
+		_ = l.mqProducer.PublishWithKey(ctx, mq.TopicOrderPaid, order.OrderNo, msg)
+	}
+
 	return nil
 }
 
@@ -644,8 +676,9 @@ func (l *OrderLogic) ShipOrder(ctx context.Context, req *ShipOrderRequest) error
 	}
 
 	// 在物流服务中创建运单（可选，失败不阻断发货主流程）
+	var logisticsNo string
 	if l.logisticsClient != nil {
-		logisticsNo, logErr := l.logisticsClient.CreateLogistics(
+		no, logErr := l.logisticsClient.CreateLogistics(
 			ctx,
 			int64(order.ID),
 			order.OrderNo,
@@ -657,6 +690,7 @@ func (l *OrderLogic) ShipOrder(ctx context.Context, req *ShipOrderRequest) error
 		if logErr != nil {
 			logx.Errorf("创建物流运单失败 order_id=%d: %v，继续发货", order.ID, logErr)
 		} else {
+			logisticsNo = no
 			logx.Infof("物流运单已创建 order_id=%d logistics_no=%s", order.ID, logisticsNo)
 		}
 	}
@@ -682,6 +716,21 @@ func (l *OrderLogic) ShipOrder(ctx context.Context, req *ShipOrderRequest) error
 		Remark:       &remark,
 	})
 
+	// 发货事件：推送网关通知用户包裹已发出
+	if l.mqProducer != nil {
+		msg := mq.NewMessage(mq.TopicLogisticsUpdated, map[string]interface{}{
+			"order_id":     order.ID,
+			"order_no":     order.OrderNo,
+			"user_id":      order.UserID,
+			"logistics_no": logisticsNo,
+			"carrier":      req.Carrier,
+			"tracking_no":  req.TrackingNo,
+			"status":       1, // 已发货
+			"updated_at":   now.Format(time.RFC3339),
+		})
+		_ = l.mqProducer.PublishWithKey(ctx, mq.TopicLogisticsUpdated, order.OrderNo, msg)
+	}
+
 	return nil
 }
 
@@ -758,3 +807,15 @@ func (l *OrderLogic) invalidateOrderCache(ctx context.Context, order *model.Orde
 func strPtr(s string) *string {
 	return &s
 }
+
+// receiverRegionCodes 收货地址的行政区划代码。用户服务补齐代码之前的旧地址按名称现场识别，识别不了时留空，不阻断下单
+func receiverRegionCodes(addr *userv1.Address) (string, string, string) {
+	if addr.ProvinceCode != "" {
+		return addr.ProvinceCode, addr.CityCode, addr.DistrictCode
+	}
+	resolved, err := region.Default().Resolve(addr.Province, addr.City, addr.District)
+	if err != nil {
+		return "", "", ""
+	}
+	return resolved.Province.Code, resolved.City.Code, resolved.District.Code
+}
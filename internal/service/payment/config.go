@@ -14,6 +14,19 @@ type Config struct {
 	Payment      PaymentConfig
 	OrderRpc     client.RpcConf // 订单服务地址（支付成功后回调）
 	InventoryRpc client.RpcConf // 库存服务地址（退款时回退库存）
+	Kafka        KafkaConfig    `json:",optional"` // 退款成功后发布 payment.refunded，不配置则不发布
+	JWT          JWTConfig      // 幂等键按令牌中的用户隔离，个人数据接口校验 user-service 签发的服务令牌
+}
+
+// JWTConfig JWT配置
+type JWTConfig struct {
+	Secret string
+}
+
+// KafkaConfig Kafka配置
+type KafkaConfig struct {
+	Brokers []string `json:",optional"`
+	Version string   `json:",optional"`
 }
 
 // DatabaseConfig 数据库配置
@@ -7,6 +7,7 @@ import (
 	"ecommerce-system/internal/pkg/client"
 	"ecommerce-system/internal/pkg/database"
 	"ecommerce-system/internal/pkg/idgen"
+	"ecommerce-system/internal/pkg/mq"
 	"ecommerce-system/internal/service/payment/repository"
 
 	"github.com/redis/go-redis/v9"
@@ -24,6 +25,7 @@ type ServiceContext struct {
 	PaymentLogRepo repository.PaymentLogRepository
 	OrderClient    *client.OrderClient
 	InvClient      *client.InventoryClient
+	MQProducer     *mq.Producer
 }
 
 // NewServiceContext 创建服务上下文。DB/Redis 初始化失败直接 Fatal，不静默放行。
@@ -76,5 +78,19 @@ func NewServiceContext(c Config) *ServiceContext {
 		ctx.InvClient = ic
 	}
 
+	// Kafka 生产者可选（不影响主链路）
+	if len(c.Kafka.Brokers) > 0 {
+		mqProducer, err := mq.NewProducer(&mq.Config{
+			Brokers:       c.Kafka.Brokers,
+			Version:       c.Kafka.Version,
+			ProducerAsync: true,
+		})
+		if err != nil {
+			log.Printf("警告：初始化Kafka生产者失败: %v", err)
+		} else {
+			ctx.MQProducer = mqProducer
+		}
+	}
+
 	return ctx
 }
@@ -28,6 +28,7 @@ func NewPaymentService(svcCtx *ServiceContext) *PaymentService {
 			svcCtx.PaymentLogRepo,
 			svcCtx.OrderClient,
 			svcCtx.InvClient,
+			svcCtx.MQProducer,
 		),
 	}
 }
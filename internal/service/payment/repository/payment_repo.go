@@ -19,6 +19,10 @@ type PaymentRepository interface {
 	Update(ctx context.Context, payment *model.Payment) error
 	// UpdateStatus 更新支付状态
 	UpdateStatus(ctx context.Context, paymentNo string, status int8) error
+	// ListByUser 获取用户全部支付单
+	ListByUser(ctx context.Context, userID uint64) ([]*model.Payment, error)
+	// ScrubByUser 清除用户支付单的第三方响应和流水的请求/响应报文，支付单和流水本身保留
+	ScrubByUser(ctx context.Context, userID uint64) (payments int64, logs int64, err error)
 }
 
 type paymentRepository struct {
@@ -66,3 +70,30 @@ func (r *paymentRepository) UpdateStatus(ctx context.Context, paymentNo string,
 		Where("payment_no = ?", paymentNo).
 		Update("status", status).Error
 }
+
+// ListByUser 获取用户全部支付单
+func (r *paymentRepository) ListByUser(ctx context.Context, userID uint64) ([]*model.Payment, error) {
+	var payments []*model.Payment
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&payments).Error
+	return payments, err
+}
+
+// ScrubByUser 清除支付报文
+func (r *paymentRepository) ScrubByUser(ctx context.Context, userID uint64) (int64, int64, error) {
+	var payments, logs int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Payment{}).
+			Where("user_id = ?", userID).
+			Update("third_party_response", nil)
+		if res.Error != nil {
+			return res.Error
+		}
+		payments = res.RowsAffected
+		res = tx.Model(&model.PaymentLog{}).
+			Where("payment_id IN (?)", tx.Model(&model.Payment{}).Select("id").Where("user_id = ?", userID)).
+			Updates(map[string]interface{}{"request_data": nil, "response_data": nil})
+		logs = res.RowsAffected
+		return res.Error
+	})
+	return payments, logs, err
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/payment/repository"
+)
+
+// DataSubjectHandler 支付服务的个人数据导出和删除。
+// 支付单和流水属于财务记录需要保留，注销时只清除第三方支付报文（可能含付款账户信息）。
+type DataSubjectHandler struct {
+	paymentRepo repository.PaymentRepository
+}
+
+// NewDataSubjectHandler 创建支付个人数据处理器
+func NewDataSubjectHandler(paymentRepo repository.PaymentRepository) *DataSubjectHandler {
+	return &DataSubjectHandler{paymentRepo: paymentRepo}
+}
+
+// Export 导出用户支付单
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	payments, err := h.paymentRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Section{{
+		Name:        "payments",
+		Description: "支付记录",
+		Records:     payments,
+		Count:       len(payments),
+	}}, nil
+}
+
+// Erase 清除用户支付报文
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	payments, logs, err := h.paymentRepo.ScrubByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Result{
+		{Name: "payments", Anonymized: payments, Note: "支付单为财务记录依法保留，已清除第三方支付报文"},
+		{Name: "payment_logs", Anonymized: logs, Note: "支付流水依法保留，已清除请求和响应报文"},
+	}, nil
+}
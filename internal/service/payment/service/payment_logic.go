@@ -8,6 +8,7 @@ import (
 	"ecommerce-system/internal/pkg/client"
 	apperrors "ecommerce-system/internal/pkg/errors"
 	"ecommerce-system/internal/pkg/idgen"
+	"ecommerce-system/internal/pkg/mq"
 	"ecommerce-system/internal/service/payment/model"
 	"ecommerce-system/internal/service/payment/repository"
 
@@ -22,6 +23,7 @@ type PaymentLogic struct {
 	paymentLogRepo repository.PaymentLogRepository
 	orderClient    *client.OrderClient
 	invClient      *client.InventoryClient
+	mqProducer     *mq.Producer
 }
 
 // NewPaymentLogic 创建支付业务逻辑
@@ -31,6 +33,7 @@ func NewPaymentLogic(
 	paymentLogRepo repository.PaymentLogRepository,
 	orderClient *client.OrderClient,
 	invClient *client.InventoryClient,
+	mqProducer *mq.Producer,
 ) *PaymentLogic {
 	return &PaymentLogic{
 		idGen:          idGen,
@@ -38,6 +41,7 @@ func NewPaymentLogic(
 		paymentLogRepo: paymentLogRepo,
 		orderClient:    orderClient,
 		invClient:      invClient,
+		mqProducer:     mqProducer,
 	}
 }
 
@@ -203,6 +207,25 @@ func (l *PaymentLogic) PaymentCallback(ctx context.Context, req *PaymentCallback
 		AfterStatus:  &req.Status,
 	})
 
+	// 支付结果事件：站内信和推送网关据此通知用户
+	if l.mqProducer != nil {
+		topic := mq.TopicPaymentFailed
+		if req.Status == 1 {
+			topic = mq.TopicPaymentSuccess
+		}
+		msg := mq.NewMessage(topic, map[string]interface{}{
+			"payment_no":     payment.PaymentNo,
+			"order_id":       payment.OrderID,
+			"order_no":       payment.OrderNo,
+			"user_id":        payment.UserID,
+			"pay_amount":     payment.Amount,
+			"payment_method": payment.PaymentMethod,
+			"status":         req.Status,
+			"notified_at":    now.Format(time.RFC3339),
+		})
+		_ = l.mqProducer.PublishWithKey(ctx, topic, payment.OrderNo, msg)
+	}
+
 	// 回调下游订单服务
 	if l.orderClient != nil {
 		if req.Status == 1 {
@@ -280,6 +303,24 @@ func (l *PaymentLogic) Refund(ctx context.Context, req *RefundRequest) (*RefundR
 		}
 	}
 
+	// 退款事件：站内信通知用户，用户服务据此扣减会员消费
+	if l.mqProducer != nil {
+		refundAmount := req.RefundAmount
+		if refundAmount <= 0 {
+			refundAmount = payment.Amount
+		}
+		msg := mq.NewMessage(mq.TopicPaymentRefunded, map[string]interface{}{
+			"payment_no":    payment.PaymentNo,
+			"refund_no":     refundNo,
+			"order_id":      payment.OrderID,
+			"order_no":      payment.OrderNo,
+			"user_id":       payment.UserID,
+			"refund_amount": refundAmount,
+			"refunded_at":   time.Now().Format(time.RFC3339),
+		})
+		_ = l.mqProducer.PublishWithKey(ctx, mq.TopicPaymentRefunded, payment.OrderNo, msg)
+	}
+
 	return &RefundResponse{RefundNo: refundNo}, nil
 }
 
@@ -1,6 +1,8 @@
 package product
 
 import (
+	"ecommerce-system/internal/pkg/client"
+
 	"github.com/zeromicro/go-zero/zrpc"
 )
 
@@ -10,6 +12,32 @@ type Config struct {
 	Database DatabaseConfig
 	BizRedis RedisConfig // 业务侧使用的 Redis 配置，避免与 zrpc.RpcServerConf 内置的 Redis 字段冲突
 	Kafka    KafkaConfig
+	JWT      JWTConfig `json:",optional"`
+	// FileRpc 文件服务地址，用于按文件ID设置品牌 Logo；不配置时只能直接填写 Logo URL
+	FileRpc client.RpcConf `json:",optional"`
+	// Schedule 定时上下架和改价
+	Schedule ScheduleConfig `json:",optional"`
+	// Catalog 商品批量导入导出，需要配置 FileRpc
+	Catalog CatalogConfig `json:",optional"`
+}
+
+// CatalogConfig 商品批量导入导出配置
+type CatalogConfig struct {
+	FileCategory string `json:",default=catalog"`  // 导出文件和错误报告的分类，文件服务和网关需配置为私有分类
+	BatchSize    int    `json:",default=50"`       // 每个事务写入的商品数
+	MaxRows      int    `json:",default=50000"`    // 单个导入文件的最大数据行数
+	MaxFileSize  int64  `json:",default=20971520"` // 单个导入文件的最大字节数（20MB）
+	DownloadTTL  int64  `json:",default=3600"`     // 下载地址有效期（秒）
+}
+
+// ScheduleConfig 商品定时任务配置
+type ScheduleConfig struct {
+	PollInterval int64 `json:",default=10"` // 检查到期任务的间隔（秒），0 表示本实例不执行定时任务
+}
+
+// JWTConfig JWT配置（校验管理接口权限）
+type JWTConfig struct {
+	Secret string
 }
 
 // KafkaConfig Kafka配置
@@ -23,12 +23,17 @@ type Product struct {
 	OriginalPrice  *float64       `gorm:"column:original_price;type:decimal(10,2)" json:"original_price"`
 	Stock          int            `gorm:"column:stock;default:0" json:"stock"`
 	Sales          int            `gorm:"column:sales;default:0" json:"sales"`
-	Status         int8           `gorm:"column:status;default:1" json:"status"`       // 0-下架, 1-上架, 2-待审核
-	IsHot          int8           `gorm:"column:is_hot;default:0;index" json:"is_hot"` // 0-否, 1-是
+	Status         int8           `gorm:"column:status;default:1" json:"status"`              // 0-下架, 1-上架, 2-待审核（从未通过审核）
+	AuditStatus    int8           `gorm:"column:audit_status" json:"audit_status"`            // 最近一次修订的审核状态: 0-草稿, 1-待审核, 2-已通过, 3-已驳回。不设 gorm 默认值，否则创建草稿时零值会被库默认值覆盖
+	AuditComment   string         `gorm:"column:audit_comment;size:500" json:"audit_comment"` // 最近一次审核意见
+	IsHot          int8           `gorm:"column:is_hot;default:0;index" json:"is_hot"`        // 0-否, 1-是
 	Sort           int            `gorm:"column:sort;default:0" json:"sort"`
+	Attrs          string         `gorm:"column:attrs;type:json" json:"attrs"` // JSON 格式存储规格参数和基础属性（{"屏幕尺寸":"6.1英寸"}），多选值以逗号分隔
 	CreatedAt      time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt      time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
+
+	BrandName string `gorm:"-" json:"brand_name"` // 品牌名称，查询时由 brand 表补充，不落库
 }
 
 // TableName 指定表名
@@ -103,3 +108,205 @@ type Banner struct {
 func (Banner) TableName() string {
 	return "banner"
 }
+
+// Brand 品牌模型
+type Brand struct {
+	ID          uint64    `gorm:"primaryKey;column:id" json:"id"`
+	Name        string    `gorm:"column:name;uniqueIndex;not null;size:100" json:"name"`
+	Logo        string    `gorm:"column:logo;size:255" json:"logo"` // Logo URL（文件服务公开地址）
+	Description string    `gorm:"column:description;type:text" json:"description"`
+	Sort        int       `gorm:"column:sort;default:0" json:"sort"`           // 排序值
+	Status      int8      `gorm:"column:status;default:1;index" json:"status"` // 0-禁用, 1-启用
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Brand) TableName() string {
+	return "brand"
+}
+
+// 属性类型
+const (
+	AttrTypeSpec  int8 = 1 // 规格参数，填写在商品上，如屏幕尺寸
+	AttrTypeSales int8 = 2 // 销售属性，填写在 SKU 上，不同取值组合成不同 SKU，如颜色、尺码
+	AttrTypeBasic int8 = 3 // 基础属性，填写在商品上，如产地
+)
+
+// 属性输入类型
+const (
+	AttrInputSingle int8 = 1 // 单选
+	AttrInputMulti  int8 = 2 // 多选
+	AttrInputText   int8 = 3 // 文本输入
+)
+
+// Attr 类目属性模板，子类目继承上级类目的属性，同名属性以下级为准
+type Attr struct {
+	ID         uint64    `gorm:"primaryKey;column:id" json:"id"`
+	CategoryID uint64    `gorm:"column:category_id;not null;index" json:"category_id"`
+	Name       string    `gorm:"column:name;not null;size:50" json:"name"`
+	Type       int8      `gorm:"column:type;not null;index" json:"type"`          // 1-规格属性, 2-销售属性, 3-基础属性
+	InputType  int8      `gorm:"column:input_type;not null" json:"input_type"`    // 1-单选, 2-多选, 3-文本输入
+	Values     string    `gorm:"column:values;type:json" json:"values"`           // JSON 格式存储可选值列表
+	Sort       int       `gorm:"column:sort;default:0" json:"sort"`               // 排序值
+	IsRequired int8      `gorm:"column:is_required;default:0" json:"is_required"` // 0-否, 1-是
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Attr) TableName() string {
+	return "attr"
+}
+
+// 商品状态
+const (
+	ProductStatusOff     int8 = 0 // 下架
+	ProductStatusOn      int8 = 1 // 上架
+	ProductStatusPending int8 = 2 // 待审核，从未通过审核的新商品，不对外展示
+)
+
+// 修订状态，商品的 AuditStatus 取值相同
+const (
+	RevisionStatusDraft     int8 = 0 // 草稿
+	RevisionStatusSubmitted int8 = 1 // 待审核
+	RevisionStatusApproved  int8 = 2 // 已通过
+	RevisionStatusRejected  int8 = 3 // 已驳回
+)
+
+// ProductRevision 商品内容修订。商品的名称、图片、价格等内容修改先写入修订，
+// 审核通过后才应用到商品上；同一商品同时最多一个未通过的修订（草稿、待审核或已驳回）
+type ProductRevision struct {
+	ID            uint64     `gorm:"primaryKey;column:id" json:"id"`
+	ProductID     uint64     `gorm:"column:product_id;not null;index" json:"product_id"`
+	Content       string     `gorm:"column:content;type:json;not null" json:"content"` // JSON 格式存储修订后的完整商品内容
+	Status        int8       `gorm:"column:status;default:0;index" json:"status"`      // 0-草稿, 1-待审核, 2-已通过, 3-已驳回
+	CreatedBy     uint64     `gorm:"column:created_by" json:"created_by"`
+	ReviewedBy    uint64     `gorm:"column:reviewed_by" json:"reviewed_by"`
+	ReviewComment string     `gorm:"column:review_comment;size:500" json:"review_comment"`
+	SubmittedAt   *time.Time `gorm:"column:submitted_at" json:"submitted_at"`
+	ReviewedAt    *time.Time `gorm:"column:reviewed_at" json:"reviewed_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ProductRevision) TableName() string {
+	return "product_revision"
+}
+
+// 审核日志动作
+const (
+	AuditActionCreate  = "create"  // 创建商品
+	AuditActionEdit    = "edit"    // 修改草稿
+	AuditActionSubmit  = "submit"  // 提交审核
+	AuditActionApprove = "approve" // 审核通过
+	AuditActionReject  = "reject"  // 审核驳回
+)
+
+// ProductAuditLog 商品审核日志，只追加不修改
+type ProductAuditLog struct {
+	ID         uint64    `gorm:"primaryKey;column:id" json:"id"`
+	ProductID  uint64    `gorm:"column:product_id;not null;index" json:"product_id"`
+	RevisionID uint64    `gorm:"column:revision_id;not null" json:"revision_id"`
+	Action     string    `gorm:"column:action;not null;size:20" json:"action"`
+	OperatorID uint64    `gorm:"column:operator_id" json:"operator_id"`
+	Comment    string    `gorm:"column:comment;size:500" json:"comment"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ProductAuditLog) TableName() string {
+	return "product_audit_log"
+}
+
+// 定时任务目标类型
+const (
+	ScheduleTargetProduct = "product"
+	ScheduleTargetSku     = "sku"
+)
+
+// 定时任务动作
+const (
+	ScheduleActionList   = "list"   // 上架
+	ScheduleActionDelist = "delist" // 下架
+	ScheduleActionPrice  = "price"  // 改价
+)
+
+// 定时任务状态
+const (
+	ScheduleStatusPending  int8 = 0 // 待执行
+	ScheduleStatusDone     int8 = 1 // 已执行
+	ScheduleStatusCanceled int8 = 2 // 已取消
+	ScheduleStatusFailed   int8 = 3 // 执行失败
+)
+
+// ProductSchedule 商品定时任务：到 RunAt 后对商品或 SKU 执行上架、下架或改价，每个任务只执行一次
+type ProductSchedule struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	TargetType string     `gorm:"column:target_type;not null;size:20" json:"target_type"` // product / sku
+	TargetID   uint64     `gorm:"column:target_id;not null" json:"target_id"`
+	ProductID  uint64     `gorm:"column:product_id;not null;index" json:"product_id"` // 目标为 SKU 时是其所属商品，便于按商品查询
+	Action     string     `gorm:"column:action;not null;size:20" json:"action"`       // list / delist / price
+	Payload    string     `gorm:"column:payload;type:json" json:"payload"`            // 改价时为 {"price":99,"original_price":129}
+	RunAt      time.Time  `gorm:"column:run_at;not null;index:idx_status_run_at,priority:2" json:"run_at"`
+	Status     int8       `gorm:"column:status;default:0;index:idx_status_run_at,priority:1" json:"status"` // 0-待执行, 1-已执行, 2-已取消, 3-执行失败
+	Error      string     `gorm:"column:error;size:500" json:"error"`                                       // 执行失败原因
+	CreatedBy  uint64     `gorm:"column:created_by" json:"created_by"`
+	ExecutedAt *time.Time `gorm:"column:executed_at" json:"executed_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ProductSchedule) TableName() string {
+	return "product_schedule"
+}
+
+// 批量任务类型
+const (
+	CatalogJobImport = "import" // 导入
+	CatalogJobExport = "export" // 导出
+)
+
+// 批量任务文件格式
+const (
+	CatalogFormatCSV  = "csv"
+	CatalogFormatXLSX = "xlsx"
+)
+
+// 批量任务状态
+const (
+	CatalogJobPending   int8 = 0 // 待处理
+	CatalogJobRunning   int8 = 1 // 处理中
+	CatalogJobSucceeded int8 = 2 // 已完成（导入时允许部分行失败）
+	CatalogJobFailed    int8 = 3 // 处理失败（文件无法读取等整体失败）
+)
+
+// CatalogJob 商品批量导入导出任务，后台异步处理，前端轮询进度
+type CatalogJob struct {
+	ID            uint64     `gorm:"primaryKey;column:id" json:"id"`
+	Kind          string     `gorm:"column:kind;not null;size:20;index" json:"kind"` // import / export
+	Format        string     `gorm:"column:format;not null;size:10" json:"format"`   // csv / xlsx
+	FileID        string     `gorm:"column:file_id;size:64" json:"file_id"`          // 导入的源文件
+	DryRun        bool       `gorm:"column:dry_run" json:"dry_run"`                  // 只校验不写入
+	Params        string     `gorm:"column:params;type:json" json:"params"`          // JSON 格式存储任务参数（导入是否提交审核、导出筛选条件）
+	Status        int8       `gorm:"column:status;default:0;index" json:"status"`    // 0-待处理, 1-处理中, 2-已完成, 3-处理失败
+	TotalRows     int        `gorm:"column:total_rows" json:"total_rows"`
+	ProcessedRows int        `gorm:"column:processed_rows" json:"processed_rows"`
+	SuccessRows   int        `gorm:"column:success_rows" json:"success_rows"`
+	FailedRows    int        `gorm:"column:failed_rows" json:"failed_rows"`
+	Errors        string     `gorm:"column:errors;type:json" json:"errors"`               // JSON 格式存储逐行错误，最多保留前若干条
+	ResultFileID  string     `gorm:"column:result_file_id;size:64" json:"result_file_id"` // 导出文件，或导入失败行的错误报告
+	Message       string     `gorm:"column:message;size:500" json:"message"`              // 整体失败原因
+	CreatedBy     uint64     `gorm:"column:created_by" json:"created_by"`
+	StartedAt     *time.Time `gorm:"column:started_at" json:"started_at"`
+	FinishedAt    *time.Time `gorm:"column:finished_at" json:"finished_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (CatalogJob) TableName() string {
+	return "catalog_job"
+}
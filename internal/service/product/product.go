@@ -3,9 +3,11 @@ package product
 import (
 	"context"
 	"log"
+	"time"
 
 	v1 "ecommerce-system/api/product/v1"
 	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/client"
 	"ecommerce-system/internal/pkg/database"
 	"ecommerce-system/internal/pkg/mq"
 	"ecommerce-system/internal/pkg/outbox"
@@ -13,6 +15,7 @@ import (
 	"ecommerce-system/internal/service/product/service"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
 )
 
@@ -28,6 +31,12 @@ type ServiceContext struct {
 	CategoryRepo repository.CategoryRepository
 	SkuRepo      repository.SkuRepository
 	BannerRepo   repository.BannerRepository
+	BrandRepo    repository.BrandRepository
+	AttrRepo     repository.AttrRepository
+	RevisionRepo repository.RevisionRepository
+	ScheduleRepo repository.ScheduleRepository
+	CatalogRepo  repository.CatalogJobRepository
+	FileClient   *client.FileClient // 为 nil 时不能按文件ID设置品牌 Logo，也不能批量导入导出
 }
 
 // NewServiceContext 创建服务上下文。DB/Redis 初始化失败直接 Fatal，不静默放行。
@@ -63,9 +72,20 @@ func NewServiceContext(c Config) *ServiceContext {
 		CategoryRepo: repository.NewCategoryRepository(db),
 		SkuRepo:      repository.NewSkuRepository(db),
 		BannerRepo:   repository.NewBannerRepository(db),
+		BrandRepo:    repository.NewBrandRepository(db),
+		AttrRepo:     repository.NewAttrRepository(db),
+		RevisionRepo: repository.NewRevisionRepository(db),
+		ScheduleRepo: repository.NewScheduleRepository(db),
+		CatalogRepo:  repository.NewCatalogJobRepository(db),
 		OutboxRepo:   outbox.NewRepo(db),
 	}
 
+	if c.FileRpc.Endpoint != "" {
+		fileClient, err := client.NewFileClient(c.FileRpc)
+		logx.Must(err)
+		ctx.FileClient = fileClient
+	}
+
 	// Kafka 生产者可选（不影响主链路，仅用于 outbox relay）
 	if len(c.Kafka.Brokers) > 0 {
 		mqProducer, err := mq.NewProducer(&mq.Config{
@@ -98,17 +118,46 @@ type ProductService struct {
 
 // NewProductService 创建商品服务
 func NewProductService(svcCtx *ServiceContext) *ProductService {
+	var logoStore service.LogoStore
+	var catalogStore service.CatalogStore
+	if svcCtx.FileClient != nil {
+		logoStore = svcCtx.FileClient
+		catalogStore = svcCtx.FileClient
+	}
+	catalogConf := svcCtx.Config.Catalog
+	logic := service.NewProductLogic(
+		svcCtx.DB,
+		svcCtx.OutboxRepo,
+		svcCtx.ProductRepo,
+		svcCtx.CategoryRepo,
+		svcCtx.SkuRepo,
+		svcCtx.BannerRepo,
+		svcCtx.BrandRepo,
+		svcCtx.AttrRepo,
+		svcCtx.RevisionRepo,
+		svcCtx.ScheduleRepo,
+		svcCtx.CatalogRepo,
+		logoStore,
+		catalogStore,
+		service.CatalogPolicy{
+			FileCategory: catalogConf.FileCategory,
+			BatchSize:    catalogConf.BatchSize,
+			MaxRows:      catalogConf.MaxRows,
+			MaxFileSize:  catalogConf.MaxFileSize,
+			DownloadTTL:  time.Duration(catalogConf.DownloadTTL) * time.Second,
+		},
+		svcCtx.Cache,
+		svcCtx.MQProducer,
+	)
+	// 定时上下架和改价：多实例部署时由 Redis 锁选出的 leader 执行
+	if svcCtx.Config.Schedule.PollInterval > 0 {
+		go logic.RunScheduler(context.Background(), time.Duration(svcCtx.Config.Schedule.PollInterval)*time.Second)
+	}
+	// 服务重启前未处理完的批量导入导出任务继续处理
+	go logic.ResumeCatalogJobs(context.Background())
+
 	return &ProductService{
 		svcCtx: svcCtx,
-		logic: service.NewProductLogic(
-			svcCtx.DB,
-			svcCtx.OutboxRepo,
-			svcCtx.ProductRepo,
-			svcCtx.CategoryRepo,
-			svcCtx.SkuRepo,
-			svcCtx.BannerRepo,
-			svcCtx.Cache,
-			svcCtx.MQProducer,
-		),
+		logic:  logic,
 	}
 }
@@ -367,6 +367,8 @@ func (s *ProductService) CreateProduct(ctx context.Context, req *v1.CreateProduc
 		Stock:          int(req.Stock),
 		Status:         int8(req.Status),
 		IsHot:          int8(req.IsHot),
+		Attrs:          req.Attrs,
+		Submit:         req.Submit,
 	}
 	if req.BrandId > 0 {
 		brandID := uint64(req.BrandId)
@@ -381,9 +383,10 @@ func (s *ProductService) CreateProduct(ctx context.Context, req *v1.CreateProduc
 
 	// 转换响应
 	return &v1.CreateProductResponse{
-		Code:    0,
-		Message: "创建成功",
-		Data:    convertProductToProto(resp.Product),
+		Code:     0,
+		Message:  "创建成功",
+		Data:     convertProductToProto(resp.Product),
+		Revision: convertRevisionToProto(resp.Revision),
 	}, nil
 }
 
@@ -415,11 +418,16 @@ func (s *ProductService) UpdateProduct(ctx context.Context, req *v1.UpdateProduc
 		// 如果前端传递了有效值（>= 0），则使用该值
 		Status: int8(req.Status),
 		IsHot:  int8(req.IsHot),
+		Submit: req.Submit,
 	}
 	if req.BrandId > 0 {
 		brandID := uint64(req.BrandId)
 		updateReq.BrandID = &brandID
 	}
+	// proto map 无法区分未传和空，空表示不更新
+	if len(req.Attrs) > 0 {
+		updateReq.Attrs = req.Attrs
+	}
 
 	// 验证分类ID（如果提供了分类ID，则验证；如果只更新其他字段如is_hot，则不验证）
 	// 注意：如果 CategoryID 为 0 且其他必填字段也为空，说明可能是部分更新，从数据库获取现有值
@@ -442,9 +450,10 @@ func (s *ProductService) UpdateProduct(ctx context.Context, req *v1.UpdateProduc
 
 	// 转换响应
 	return &v1.UpdateProductResponse{
-		Code:    0,
-		Message: "更新成功",
-		Data:    convertProductToProto(resp.Product),
+		Code:     0,
+		Message:  "更新成功",
+		Data:     convertProductToProto(resp.Product),
+		Revision: convertRevisionToProto(resp.Revision),
 	}, nil
 }
 
@@ -606,6 +615,7 @@ func convertProductToProto(p *model.Product) *v1.Product {
 	// 解析图片列表
 	images, _ := parseJSONArray(p.Images)
 	localImages, _ := parseJSONArray(p.LocalImages)
+	attrs, _ := parseJSONMap(p.Attrs)
 
 	product := &v1.Product{
 		Id:             int64(p.ID),
@@ -623,12 +633,16 @@ func convertProductToProto(p *model.Product) *v1.Product {
 		Sales:          int32(p.Sales),
 		Status:         int32(p.Status),
 		IsHot:          int32(p.IsHot),
+		Attrs:          attrs,
+		AuditStatus:    int32(p.AuditStatus),
+		AuditComment:   p.AuditComment,
 		CreatedAt:      formatTime(&p.CreatedAt),
 		UpdatedAt:      formatTime(&p.UpdatedAt),
 	}
 
 	if p.BrandID != nil {
 		product.BrandId = int64(*p.BrandID)
+		product.BrandName = p.BrandName
 	}
 	if p.OriginalPrice != nil {
 		product.OriginalPrice = *p.OriginalPrice
@@ -915,3 +929,572 @@ func convertBannerToProto(banner *model.Banner) *v1.Banner {
 		UpdatedAt:   banner.UpdatedAt.Format(time.RFC3339),
 	}
 }
+
+// ==================== 品牌相关方法 ====================
+
+// ListBrands 获取品牌列表
+func (s *ProductService) ListBrands(ctx context.Context, req *v1.ListBrandsRequest) (*v1.ListBrandsResponse, error) {
+	resp, err := s.logic.ListBrands(ctx, &service.ListBrandsRequest{
+		Status:   int8(req.Status),
+		Keyword:  req.Keyword,
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	brands := make([]*v1.Brand, 0, len(resp.Brands))
+	for _, b := range resp.Brands {
+		brands = append(brands, convertBrandToProto(b))
+	}
+
+	return &v1.ListBrandsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    brands,
+		Total:   resp.Total,
+	}, nil
+}
+
+// GetBrand 获取品牌详情
+func (s *ProductService) GetBrand(ctx context.Context, req *v1.GetBrandRequest) (*v1.GetBrandResponse, error) {
+	brand, err := s.logic.GetBrand(ctx, uint64(req.Id))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.GetBrandResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertBrandToProto(brand),
+	}, nil
+}
+
+// CreateBrand 创建品牌（管理后台）
+func (s *ProductService) CreateBrand(ctx context.Context, req *v1.CreateBrandRequest) (*v1.CreateBrandResponse, error) {
+	brand, err := s.logic.CreateBrand(ctx, &service.CreateBrandRequest{
+		Name:        req.Name,
+		Logo:        req.Logo,
+		LogoFileID:  req.LogoFileId,
+		Description: req.Description,
+		Sort:        int(req.Sort),
+		Status:      int8(req.Status),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.CreateBrandResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertBrandToProto(brand),
+	}, nil
+}
+
+// UpdateBrand 更新品牌（管理后台）
+func (s *ProductService) UpdateBrand(ctx context.Context, req *v1.UpdateBrandRequest) (*v1.UpdateBrandResponse, error) {
+	brand, err := s.logic.UpdateBrand(ctx, &service.UpdateBrandRequest{
+		ID:          uint64(req.Id),
+		Name:        req.Name,
+		Logo:        req.Logo,
+		LogoFileID:  req.LogoFileId,
+		Description: req.Description,
+		Sort:        int(req.Sort),
+		Status:      int8(req.Status),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.UpdateBrandResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertBrandToProto(brand),
+	}, nil
+}
+
+// DeleteBrand 删除品牌（管理后台）
+func (s *ProductService) DeleteBrand(ctx context.Context, req *v1.DeleteBrandRequest) (*v1.DeleteBrandResponse, error) {
+	if err := s.logic.DeleteBrand(ctx, uint64(req.Id)); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.DeleteBrandResponse{
+		Code:    0,
+		Message: "成功",
+	}, nil
+}
+
+// ListCategoryAttrs 获取类目属性模板
+func (s *ProductService) ListCategoryAttrs(ctx context.Context, req *v1.ListCategoryAttrsRequest) (*v1.ListCategoryAttrsResponse, error) {
+	attrs, err := s.logic.ListCategoryAttrs(ctx, &service.ListCategoryAttrsRequest{
+		CategoryID:       uint64(req.CategoryId),
+		Type:             int8(req.Type),
+		IncludeInherited: req.IncludeInherited,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		data = append(data, convertAttrToProto(a))
+	}
+	return &v1.ListCategoryAttrsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
+// CreateAttr 创建类目属性（管理后台）
+func (s *ProductService) CreateAttr(ctx context.Context, req *v1.CreateAttrRequest) (*v1.CreateAttrResponse, error) {
+	attr, err := s.logic.CreateAttr(ctx, &service.CreateAttrRequest{
+		CategoryID: uint64(req.CategoryId),
+		Name:       req.Name,
+		Type:       int8(req.Type),
+		InputType:  int8(req.InputType),
+		Values:     req.Values,
+		Sort:       int(req.Sort),
+		IsRequired: int8(req.IsRequired),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.CreateAttrResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertAttrToProto(attr),
+	}, nil
+}
+
+// UpdateAttr 更新类目属性（管理后台）
+func (s *ProductService) UpdateAttr(ctx context.Context, req *v1.UpdateAttrRequest) (*v1.UpdateAttrResponse, error) {
+	attr, err := s.logic.UpdateAttr(ctx, &service.UpdateAttrRequest{
+		ID:         uint64(req.Id),
+		Name:       req.Name,
+		InputType:  int8(req.InputType),
+		Values:     req.Values,
+		Sort:       int(req.Sort),
+		IsRequired: int8(req.IsRequired),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.UpdateAttrResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertAttrToProto(attr),
+	}, nil
+}
+
+// DeleteAttr 删除类目属性（管理后台）
+func (s *ProductService) DeleteAttr(ctx context.Context, req *v1.DeleteAttrRequest) (*v1.DeleteAttrResponse, error) {
+	if err := s.logic.DeleteAttr(ctx, uint64(req.Id)); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.DeleteAttrResponse{
+		Code:    0,
+		Message: "成功",
+	}, nil
+}
+
+// GenerateSkus 按销售属性批量生成SKU（管理后台）
+func (s *ProductService) GenerateSkus(ctx context.Context, req *v1.GenerateSkusRequest) (*v1.GenerateSkusResponse, error) {
+	attrs := make(map[string][]string, len(req.Attrs))
+	for _, a := range req.Attrs {
+		if a != nil && a.Name != "" {
+			attrs[a.Name] = a.Values
+		}
+	}
+	resp, err := s.logic.GenerateSkus(ctx, &service.GenerateSkusRequest{
+		ProductID: uint64(req.ProductId),
+		Attrs:     attrs,
+		Price:     req.Price,
+		Stock:     int(req.Stock),
+		Status:    int8(req.Status),
+		DryRun:    req.DryRun,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	skus := make([]*v1.Sku, 0, len(resp.Skus))
+	for _, sku := range resp.Skus {
+		skus = append(skus, convertSkuToProto(sku))
+	}
+	return &v1.GenerateSkusResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    skus,
+		Skipped: int32(resp.Skipped),
+	}, nil
+}
+
+// SubmitProductRevision 提交商品修订等待审核（管理后台）
+func (s *ProductService) SubmitProductRevision(ctx context.Context, req *v1.SubmitProductRevisionRequest) (*v1.ProductRevisionResponse, error) {
+	rev, err := s.logic.SubmitProductRevision(ctx, uint64(req.ProductId))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.ProductRevisionResponse{
+		Code:    0,
+		Message: "已提交审核",
+		Data:    convertRevisionToProto(rev),
+	}, nil
+}
+
+// ApproveProductRevision 审核通过（管理后台）
+func (s *ProductService) ApproveProductRevision(ctx context.Context, req *v1.ReviewProductRevisionRequest) (*v1.ProductRevisionResponse, error) {
+	rev, err := s.logic.ApproveProductRevision(ctx, &service.ReviewProductRevisionRequest{
+		ID:      uint64(req.Id),
+		Comment: req.Comment,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.ProductRevisionResponse{
+		Code:    0,
+		Message: "审核通过",
+		Data:    convertRevisionToProto(rev),
+	}, nil
+}
+
+// RejectProductRevision 审核驳回（管理后台）
+func (s *ProductService) RejectProductRevision(ctx context.Context, req *v1.ReviewProductRevisionRequest) (*v1.ProductRevisionResponse, error) {
+	rev, err := s.logic.RejectProductRevision(ctx, &service.ReviewProductRevisionRequest{
+		ID:      uint64(req.Id),
+		Comment: req.Comment,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.ProductRevisionResponse{
+		Code:    0,
+		Message: "已驳回",
+		Data:    convertRevisionToProto(rev),
+	}, nil
+}
+
+// ListProductRevisions 获取商品修订列表（管理后台）
+func (s *ProductService) ListProductRevisions(ctx context.Context, req *v1.ListProductRevisionsRequest) (*v1.ListProductRevisionsResponse, error) {
+	resp, err := s.logic.ListProductRevisions(ctx, &service.ListProductRevisionsRequest{
+		ProductID: uint64(req.ProductId),
+		Status:    int8(req.Status),
+		Page:      int(req.Page),
+		PageSize:  int(req.PageSize),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	revisions := make([]*v1.ProductRevision, 0, len(resp.Revisions))
+	for _, rev := range resp.Revisions {
+		revisions = append(revisions, convertRevisionToProto(rev))
+	}
+	return &v1.ListProductRevisionsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    revisions,
+		Total:   resp.Total,
+	}, nil
+}
+
+// ListProductAuditLogs 获取商品审核日志（管理后台）
+func (s *ProductService) ListProductAuditLogs(ctx context.Context, req *v1.ListProductAuditLogsRequest) (*v1.ListProductAuditLogsResponse, error) {
+	resp, err := s.logic.ListProductAuditLogs(ctx, &service.ListProductAuditLogsRequest{
+		ProductID: uint64(req.ProductId),
+		Page:      int(req.Page),
+		PageSize:  int(req.PageSize),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	logs := make([]*v1.ProductAuditLog, 0, len(resp.Logs))
+	for _, log := range resp.Logs {
+		logs = append(logs, &v1.ProductAuditLog{
+			Id:         int64(log.ID),
+			ProductId:  int64(log.ProductID),
+			RevisionId: int64(log.RevisionID),
+			Action:     log.Action,
+			OperatorId: int64(log.OperatorID),
+			Comment:    log.Comment,
+			CreatedAt:  log.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return &v1.ListProductAuditLogsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    logs,
+		Total:   resp.Total,
+	}, nil
+}
+
+// CreateProductSchedule 创建定时上下架或改价任务（管理后台）
+func (s *ProductService) CreateProductSchedule(ctx context.Context, req *v1.CreateProductScheduleRequest) (*v1.ProductScheduleResponse, error) {
+	runAt, err := parseTime(req.RunAt)
+	if err != nil {
+		return nil, convertError(apperrors.NewInvalidParamError("执行时间格式错误，应为 RFC3339"))
+	}
+	createReq := &service.CreateProductScheduleRequest{
+		TargetType:    req.TargetType,
+		TargetID:      uint64(req.TargetId),
+		Action:        req.Action,
+		Price:         req.Price,
+		OriginalPrice: req.OriginalPrice,
+	}
+	if runAt != nil {
+		createReq.RunAt = *runAt
+	}
+	schedule, err := s.logic.CreateProductSchedule(ctx, createReq)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.ProductScheduleResponse{
+		Code:    0,
+		Message: "创建成功",
+		Data:    convertScheduleToProto(schedule),
+	}, nil
+}
+
+// ListProductSchedules 获取定时任务列表（管理后台）
+func (s *ProductService) ListProductSchedules(ctx context.Context, req *v1.ListProductSchedulesRequest) (*v1.ListProductSchedulesResponse, error) {
+	resp, err := s.logic.ListProductSchedules(ctx, &service.ListProductSchedulesRequest{
+		ProductID: uint64(req.ProductId),
+		Status:    int8(req.Status),
+		Page:      int(req.Page),
+		PageSize:  int(req.PageSize),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	schedules := make([]*v1.ProductSchedule, 0, len(resp.Schedules))
+	for _, schedule := range resp.Schedules {
+		schedules = append(schedules, convertScheduleToProto(schedule))
+	}
+	return &v1.ListProductSchedulesResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    schedules,
+		Total:   resp.Total,
+	}, nil
+}
+
+// CancelProductSchedule 取消待执行的定时任务（管理后台）
+func (s *ProductService) CancelProductSchedule(ctx context.Context, req *v1.CancelProductScheduleRequest) (*v1.ProductScheduleResponse, error) {
+	schedule, err := s.logic.CancelProductSchedule(ctx, uint64(req.Id))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.ProductScheduleResponse{
+		Code:    0,
+		Message: "已取消",
+		Data:    convertScheduleToProto(schedule),
+	}, nil
+}
+
+// convertScheduleToProto 转换商品定时任务为Proto
+func convertScheduleToProto(schedule *model.ProductSchedule) *v1.ProductSchedule {
+	if schedule == nil {
+		return nil
+	}
+
+	pb := &v1.ProductSchedule{
+		Id:         int64(schedule.ID),
+		TargetType: schedule.TargetType,
+		TargetId:   int64(schedule.TargetID),
+		ProductId:  int64(schedule.ProductID),
+		Action:     schedule.Action,
+		RunAt:      schedule.RunAt.Format(time.RFC3339),
+		Status:     int32(schedule.Status),
+		Error:      schedule.Error,
+		CreatedBy:  int64(schedule.CreatedBy),
+		ExecutedAt: formatTime(schedule.ExecutedAt),
+		CreatedAt:  schedule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  schedule.UpdatedAt.Format(time.RFC3339),
+	}
+	if schedule.Action == model.ScheduleActionPrice {
+		var payload service.SchedulePricePayload
+		if err := json.Unmarshal([]byte(schedule.Payload), &payload); err == nil {
+			pb.Price = payload.Price
+			if payload.OriginalPrice != nil {
+				pb.OriginalPrice = *payload.OriginalPrice
+			}
+		}
+	}
+	return pb
+}
+
+// convertRevisionToProto 转换商品修订为Proto
+func convertRevisionToProto(rev *service.ProductRevisionDetail) *v1.ProductRevision {
+	if rev == nil || rev.ProductRevision == nil {
+		return nil
+	}
+
+	return &v1.ProductRevision{
+		Id:            int64(rev.ID),
+		ProductId:     int64(rev.ProductID),
+		Content:       convertProductToProto(rev.Product),
+		Status:        int32(rev.Status),
+		CreatedBy:     int64(rev.CreatedBy),
+		ReviewedBy:    int64(rev.ReviewedBy),
+		ReviewComment: rev.ReviewComment,
+		SubmittedAt:   formatTime(rev.SubmittedAt),
+		ReviewedAt:    formatTime(rev.ReviewedAt),
+		CreatedAt:     rev.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     rev.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// convertAttrToProto 转换类目属性为Proto
+func convertAttrToProto(a *service.TemplateAttr) *v1.Attr {
+	if a == nil || a.Attr == nil {
+		return nil
+	}
+
+	return &v1.Attr{
+		Id:         int64(a.ID),
+		CategoryId: int64(a.CategoryID),
+		Name:       a.Name,
+		Type:       int32(a.Type),
+		InputType:  int32(a.InputType),
+		Values:     a.Values,
+		Sort:       int32(a.Sort),
+		IsRequired: int32(a.IsRequired),
+		Inherited:  a.Inherited,
+		CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  a.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// convertBrandToProto 转换品牌模型为Proto
+func convertBrandToProto(brand *model.Brand) *v1.Brand {
+	if brand == nil {
+		return nil
+	}
+
+	return &v1.Brand{
+		Id:          int64(brand.ID),
+		Name:        brand.Name,
+		Logo:        brand.Logo,
+		Description: brand.Description,
+		Sort:        int32(brand.Sort),
+		Status:      int32(brand.Status),
+		CreatedAt:   brand.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   brand.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ImportCatalog 批量导入商品（管理后台）
+func (s *ProductService) ImportCatalog(ctx context.Context, req *v1.ImportCatalogRequest) (*v1.CatalogJobResponse, error) {
+	detail, err := s.logic.ImportCatalog(ctx, &service.ImportCatalogRequest{
+		FileID: req.FileId,
+		Format: req.Format,
+		DryRun: req.DryRun,
+		Submit: req.Submit,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.CatalogJobResponse{
+		Code:    0,
+		Message: "已创建导入任务",
+		Data:    convertCatalogJobToProto(detail.CatalogJob, detail),
+	}, nil
+}
+
+// ExportCatalog 批量导出商品（管理后台）
+func (s *ProductService) ExportCatalog(ctx context.Context, req *v1.ExportCatalogRequest) (*v1.CatalogJobResponse, error) {
+	detail, err := s.logic.ExportCatalog(ctx, &service.ExportCatalogRequest{
+		Format:     req.Format,
+		CategoryID: uint64(req.CategoryId),
+		BrandID:    uint64(req.BrandId),
+		Status:     int8(req.Status),
+		Keyword:    req.Keyword,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.CatalogJobResponse{
+		Code:    0,
+		Message: "已创建导出任务",
+		Data:    convertCatalogJobToProto(detail.CatalogJob, detail),
+	}, nil
+}
+
+// GetCatalogJob 获取批量任务进度和结果（管理后台）
+func (s *ProductService) GetCatalogJob(ctx context.Context, req *v1.GetCatalogJobRequest) (*v1.CatalogJobResponse, error) {
+	detail, err := s.logic.GetCatalogJob(ctx, uint64(req.Id))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.CatalogJobResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertCatalogJobToProto(detail.CatalogJob, detail),
+	}, nil
+}
+
+// ListCatalogJobs 获取批量任务列表（管理后台）
+func (s *ProductService) ListCatalogJobs(ctx context.Context, req *v1.ListCatalogJobsRequest) (*v1.ListCatalogJobsResponse, error) {
+	jobs, total, err := s.logic.ListCatalogJobs(ctx, req.Kind, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.CatalogJob, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, convertCatalogJobToProto(job, nil))
+	}
+	return &v1.ListCatalogJobsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+		Total:   total,
+	}, nil
+}
+
+// convertCatalogJobToProto 转换商品批量任务为Proto，detail 为 nil 时不返回逐行错误和下载地址（列表）
+func convertCatalogJobToProto(job *model.CatalogJob, detail *service.CatalogJobDetail) *v1.CatalogJob {
+	if job == nil {
+		return nil
+	}
+
+	pb := &v1.CatalogJob{
+		Id:            int64(job.ID),
+		Kind:          job.Kind,
+		Format:        job.Format,
+		FileId:        job.FileID,
+		DryRun:        job.DryRun,
+		Status:        int32(job.Status),
+		TotalRows:     int32(job.TotalRows),
+		ProcessedRows: int32(job.ProcessedRows),
+		SuccessRows:   int32(job.SuccessRows),
+		FailedRows:    int32(job.FailedRows),
+		Message:       job.Message,
+		CreatedBy:     int64(job.CreatedBy),
+		StartedAt:     formatTime(job.StartedAt),
+		FinishedAt:    formatTime(job.FinishedAt),
+		CreatedAt:     job.CreatedAt.Format(time.RFC3339),
+	}
+	if detail != nil {
+		pb.ResultUrl = detail.ResultURL
+		pb.ResultUrlExpiresAt = detail.ExpiresAt
+		pb.Errors = make([]*v1.CatalogRowError, 0, len(detail.RowErrors))
+		for _, e := range detail.RowErrors {
+			pb.Errors = append(pb.Errors, &v1.CatalogRowError{
+				Row:     int32(e.Row),
+				SpuCode: e.SpuCode,
+				SkuCode: e.SkuCode,
+				Message: e.Message,
+			})
+		}
+	}
+	return pb
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/product/model"
+)
+
+// AttrRepository 类目属性模板数据访问接口
+type AttrRepository interface {
+	Create(ctx context.Context, attr *model.Attr) error
+	GetByID(ctx context.Context, id uint64) (*model.Attr, error)
+	// ListByCategoryIDs 获取多个类目的属性，按排序值降序、ID升序
+	ListByCategoryIDs(ctx context.Context, categoryIDs []uint64) ([]*model.Attr, error)
+	Update(ctx context.Context, attr *model.Attr) error
+	Delete(ctx context.Context, id uint64) error
+	DeleteByCategoryID(ctx context.Context, categoryID uint64) error
+}
+
+// attrRepository 类目属性模板数据访问实现
+type attrRepository struct {
+	db *gorm.DB
+}
+
+// NewAttrRepository 创建类目属性模板数据访问实例
+func NewAttrRepository(db *gorm.DB) AttrRepository {
+	return &attrRepository{db: db}
+}
+
+// Create 创建属性
+func (r *attrRepository) Create(ctx context.Context, attr *model.Attr) error {
+	return r.db.WithContext(ctx).Create(attr).Error
+}
+
+// GetByID 根据ID获取属性
+func (r *attrRepository) GetByID(ctx context.Context, id uint64) (*model.Attr, error) {
+	var attr model.Attr
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&attr).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attr, nil
+}
+
+// ListByCategoryIDs 获取类目属性
+func (r *attrRepository) ListByCategoryIDs(ctx context.Context, categoryIDs []uint64) ([]*model.Attr, error) {
+	var attrs []*model.Attr
+	if len(categoryIDs) == 0 {
+		return attrs, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("category_id IN ?", categoryIDs).
+		Order("sort DESC, id ASC").
+		Find(&attrs).Error
+	return attrs, err
+}
+
+// Update 更新属性
+func (r *attrRepository) Update(ctx context.Context, attr *model.Attr) error {
+	return r.db.WithContext(ctx).Save(attr).Error
+}
+
+// Delete 删除属性
+func (r *attrRepository) Delete(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Delete(&model.Attr{}, id).Error
+}
+
+// DeleteByCategoryID 删除类目下的全部属性
+func (r *attrRepository) DeleteByCategoryID(ctx context.Context, categoryID uint64) error {
+	return r.db.WithContext(ctx).Where("category_id = ?", categoryID).Delete(&model.Attr{}).Error
+}
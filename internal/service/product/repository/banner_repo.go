@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -12,6 +13,7 @@ import (
 type BannerRepository interface {
 	Create(ctx context.Context, banner *model.Banner) error
 	GetByID(ctx context.Context, id uint64) (*model.Banner, error)
+	// GetAll 获取Banner列表，status 为 -1 表示全部状态；查询启用的 Banner 时只返回在投放时间内的
 	GetAll(ctx context.Context, status int8, limit int, keyword string) ([]*model.Banner, error)
 	Update(ctx context.Context, banner *model.Banner) error
 	Delete(ctx context.Context, id uint64) error
@@ -50,6 +52,11 @@ func (r *bannerRepository) GetAll(ctx context.Context, status int8, limit int, k
 	if status >= 0 {
 		query = query.Where("status = ?", status)
 	}
+	// 启用的 Banner 还要在投放时间内，未设置开始/结束时间表示不限
+	if status == 1 {
+		now := time.Now()
+		query = query.Where("(start_time IS NULL OR start_time <= ?) AND (end_time IS NULL OR end_time > ?)", now, now)
+	}
 	if keyword != "" {
 		like := "%" + keyword + "%"
 		query = query.Where("title LIKE ? OR description LIKE ? OR link LIKE ?", like, like, like)
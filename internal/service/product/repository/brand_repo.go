@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/product/model"
+)
+
+// BrandRepository 品牌数据访问接口
+type BrandRepository interface {
+	Create(ctx context.Context, brand *model.Brand) error
+	GetByID(ctx context.Context, id uint64) (*model.Brand, error)
+	GetByName(ctx context.Context, name string) (*model.Brand, error)
+	// GetByIDs 批量获取品牌，用于商品列表补充品牌名称
+	GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.Brand, error)
+	List(ctx context.Context, status int8, keyword string, page, pageSize int) ([]*model.Brand, int64, error)
+	Update(ctx context.Context, brand *model.Brand) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+// brandRepository 品牌数据访问实现
+type brandRepository struct {
+	db *gorm.DB
+}
+
+// NewBrandRepository 创建品牌数据访问实例
+func NewBrandRepository(db *gorm.DB) BrandRepository {
+	return &brandRepository{db: db}
+}
+
+// Create 创建品牌
+func (r *brandRepository) Create(ctx context.Context, brand *model.Brand) error {
+	return r.db.WithContext(ctx).Create(brand).Error
+}
+
+// GetByID 根据ID获取品牌
+func (r *brandRepository) GetByID(ctx context.Context, id uint64) (*model.Brand, error) {
+	var brand model.Brand
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&brand).Error
+	if err != nil {
+		return nil, err
+	}
+	return &brand, nil
+}
+
+// GetByName 根据名称获取品牌
+func (r *brandRepository) GetByName(ctx context.Context, name string) (*model.Brand, error) {
+	var brand model.Brand
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&brand).Error
+	if err != nil {
+		return nil, err
+	}
+	return &brand, nil
+}
+
+// GetByIDs 批量获取品牌
+func (r *brandRepository) GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.Brand, error) {
+	result := make(map[uint64]*model.Brand, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var brands []*model.Brand
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&brands).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range brands {
+		result[b.ID] = b
+	}
+	return result, nil
+}
+
+// List 分页获取品牌列表
+func (r *brandRepository) List(ctx context.Context, status int8, keyword string, page, pageSize int) ([]*model.Brand, int64, error) {
+	var brands []*model.Brand
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Brand{})
+	if status >= 0 {
+		query = query.Where("status = ?", status)
+	}
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("name LIKE ? OR description LIKE ?", like, like)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 按排序值降序，ID升序
+	offset := (page - 1) * pageSize
+	if err := query.Order("sort DESC, id ASC").Offset(offset).Limit(pageSize).Find(&brands).Error; err != nil {
+		return nil, 0, err
+	}
+	return brands, total, nil
+}
+
+// Update 更新品牌
+func (r *brandRepository) Update(ctx context.Context, brand *model.Brand) error {
+	return r.db.WithContext(ctx).Save(brand).Error
+}
+
+// Delete 删除品牌
+func (r *brandRepository) Delete(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Delete(&model.Brand{}, id).Error
+}
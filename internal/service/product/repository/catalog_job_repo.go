@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/product/model"
+)
+
+// CatalogJobRepository 商品批量导入导出任务数据访问接口
+type CatalogJobRepository interface {
+	Create(ctx context.Context, job *model.CatalogJob) error
+	GetByID(ctx context.Context, id uint64) (*model.CatalogJob, error)
+	// Update 保存任务进度和结果
+	Update(ctx context.Context, job *model.CatalogJob) error
+	// List 分页获取任务，kind 为空表示全部类型；按创建时间倒序
+	List(ctx context.Context, kind string, page, pageSize int) ([]*model.CatalogJob, int64, error)
+	// ListUnfinished 获取待处理和处理中的任务，用于服务重启后继续处理
+	ListUnfinished(ctx context.Context, limit int) ([]*model.CatalogJob, error)
+}
+
+// catalogJobRepository 商品批量任务数据访问实现
+type catalogJobRepository struct {
+	db *gorm.DB
+}
+
+// NewCatalogJobRepository 创建商品批量任务数据访问实例
+func NewCatalogJobRepository(db *gorm.DB) CatalogJobRepository {
+	return &catalogJobRepository{db: db}
+}
+
+// Create 创建任务
+func (r *catalogJobRepository) Create(ctx context.Context, job *model.CatalogJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetByID 根据ID获取任务
+func (r *catalogJobRepository) GetByID(ctx context.Context, id uint64) (*model.CatalogJob, error) {
+	var job model.CatalogJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Update 更新任务
+func (r *catalogJobRepository) Update(ctx context.Context, job *model.CatalogJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// List 分页获取任务列表
+func (r *catalogJobRepository) List(ctx context.Context, kind string, page, pageSize int) ([]*model.CatalogJob, int64, error) {
+	var jobs []*model.CatalogJob
+	var total int64
+
+	// 列表不返回逐行错误，详情接口再取
+	query := r.db.WithContext(ctx).Model(&model.CatalogJob{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Omit("errors").Order("id DESC").Offset(offset).Limit(pageSize).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// ListUnfinished 获取未完成的任务
+func (r *catalogJobRepository) ListUnfinished(ctx context.Context, limit int) ([]*model.CatalogJob, error) {
+	var jobs []*model.CatalogJob
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []int8{model.CatalogJobPending, model.CatalogJobRunning}).
+		Order("id ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
@@ -14,6 +14,10 @@ type ProductRepository interface {
 	Update(ctx context.Context, product *model.Product) error
 	Delete(ctx context.Context, id uint64) error
 	List(ctx context.Context, req *ListProductsRequest) ([]*model.Product, int64, error)
+	// ListIDsByBrand 获取品牌下所有未删除商品的 ID
+	ListIDsByBrand(ctx context.Context, brandID uint64) ([]uint64, error)
+	// GetByIDs 批量获取商品，用于审核列表展示当前线上内容
+	GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.Product, error)
 }
 
 // ListProductsRequest 商品列表查询请求
@@ -28,7 +32,7 @@ type ListProductsRequest struct {
 	IsHot       int8 // -1-全部, 0-否, 1-是
 	Page        int
 	PageSize    int
-	Sort        string // price_asc, price_desc, sales_desc, created_desc
+	Sort        string // price_asc, price_desc, sales_desc, created_desc, id_asc（批量导出时稳定分页）
 }
 
 // 依赖注入
@@ -41,6 +45,7 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 }
 
 func (r *productRepository) Create(ctx context.Context, product *model.Product) error {
+	ensureProductAttrs(product)
 	return r.db.WithContext(ctx).Create(product).Error
 }
 
@@ -63,13 +68,44 @@ func (r *productRepository) GetBySpuCode(ctx context.Context, spuCode string) (*
 }
 
 func (r *productRepository) Update(ctx context.Context, product *model.Product) error {
+	ensureProductAttrs(product)
 	return r.db.WithContext(ctx).Save(product).Error
 }
 
+// ensureProductAttrs attrs 是 JSON 列，不能写入空字符串（老数据读出来为空）
+func ensureProductAttrs(product *model.Product) {
+	if product.Attrs == "" {
+		product.Attrs = "{}"
+	}
+}
+
 func (r *productRepository) Delete(ctx context.Context, id uint64) error {
 	return r.db.WithContext(ctx).Delete(&model.Product{}, id).Error
 }
 
+// ListIDsByBrand 获取品牌下的商品ID
+func (r *productRepository) ListIDsByBrand(ctx context.Context, brandID uint64) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.WithContext(ctx).Model(&model.Product{}).Where("brand_id = ?", brandID).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetByIDs 批量获取商品
+func (r *productRepository) GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.Product, error) {
+	result := make(map[uint64]*model.Product, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var products []*model.Product
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		result[p.ID] = p
+	}
+	return result, nil
+}
+
 // List 获取商品列表
 func (r *productRepository) List(ctx context.Context, req *ListProductsRequest) ([]*model.Product, int64, error) {
 	var products []*model.Product
@@ -113,6 +149,8 @@ func (r *productRepository) List(ctx context.Context, req *ListProductsRequest)
 		query = query.Order("sales DESC")
 	case "created_desc":
 		query = query.Order("created_at DESC")
+	case "id_asc":
+		query = query.Order("id ASC")
 	default:
 		query = query.Order("sort DESC, created_at DESC")
 	}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/product/model"
+)
+
+// RevisionRepository 商品修订和审核日志数据访问接口
+type RevisionRepository interface {
+	Create(ctx context.Context, rev *model.ProductRevision) error
+	GetByID(ctx context.Context, id uint64) (*model.ProductRevision, error)
+	// GetOpenByProductID 获取商品未通过的修订（草稿、待审核或已驳回），没有时返回 nil, nil
+	GetOpenByProductID(ctx context.Context, productID uint64) (*model.ProductRevision, error)
+	// List 分页获取修订，productID 为 0 表示全部商品，status 为 -1 表示全部状态；按 ID 降序
+	List(ctx context.Context, productID uint64, status int8, page, pageSize int) ([]*model.ProductRevision, int64, error)
+	// UpdateFromStatus 仅当修订仍处于 fromStatus 时保存，返回是否更新成功（用于并发审核）
+	UpdateFromStatus(ctx context.Context, rev *model.ProductRevision, fromStatus int8) (bool, error)
+	CreateLog(ctx context.Context, log *model.ProductAuditLog) error
+	// ListLogs 分页获取商品的审核日志，按 ID 降序
+	ListLogs(ctx context.Context, productID uint64, page, pageSize int) ([]*model.ProductAuditLog, int64, error)
+}
+
+// revisionRepository 商品修订数据访问实现
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+// NewRevisionRepository 创建商品修订数据访问实例
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// Create 创建修订
+func (r *revisionRepository) Create(ctx context.Context, rev *model.ProductRevision) error {
+	return r.db.WithContext(ctx).Create(rev).Error
+}
+
+// GetByID 根据ID获取修订
+func (r *revisionRepository) GetByID(ctx context.Context, id uint64) (*model.ProductRevision, error) {
+	var rev model.ProductRevision
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// GetOpenByProductID 获取商品未通过的修订
+func (r *revisionRepository) GetOpenByProductID(ctx context.Context, productID uint64) (*model.ProductRevision, error) {
+	var rev model.ProductRevision
+	err := r.db.WithContext(ctx).
+		Where("product_id = ? AND status IN ?", productID, []int8{
+			model.RevisionStatusDraft, model.RevisionStatusSubmitted, model.RevisionStatusRejected,
+		}).
+		Order("id DESC").
+		First(&rev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// List 分页获取修订列表
+func (r *revisionRepository) List(ctx context.Context, productID uint64, status int8, page, pageSize int) ([]*model.ProductRevision, int64, error) {
+	var revs []*model.ProductRevision
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.ProductRevision{})
+	if productID > 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+	if status >= 0 {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&revs).Error; err != nil {
+		return nil, 0, err
+	}
+	return revs, total, nil
+}
+
+// UpdateFromStatus 条件更新修订
+func (r *revisionRepository) UpdateFromStatus(ctx context.Context, rev *model.ProductRevision, fromStatus int8) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.ProductRevision{}).
+		Where("id = ? AND status = ?", rev.ID, fromStatus).
+		Updates(map[string]any{
+			"content":        rev.Content,
+			"status":         rev.Status,
+			"reviewed_by":    rev.ReviewedBy,
+			"review_comment": rev.ReviewComment,
+			"submitted_at":   rev.SubmittedAt,
+			"reviewed_at":    rev.ReviewedAt,
+			"updated_at":     rev.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreateLog 写入审核日志
+func (r *revisionRepository) CreateLog(ctx context.Context, log *model.ProductAuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// ListLogs 分页获取审核日志
+func (r *revisionRepository) ListLogs(ctx context.Context, productID uint64, page, pageSize int) ([]*model.ProductAuditLog, int64, error) {
+	var logs []*model.ProductAuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.ProductAuditLog{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
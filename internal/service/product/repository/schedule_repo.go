@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/product/model"
+)
+
+// ScheduleRepository 商品定时任务数据访问接口
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *model.ProductSchedule) error
+	GetByID(ctx context.Context, id uint64) (*model.ProductSchedule, error)
+	// List 分页获取定时任务，productID 为 0 表示全部商品，status 为 -1 表示全部状态；按执行时间升序
+	List(ctx context.Context, productID uint64, status int8, page, pageSize int) ([]*model.ProductSchedule, int64, error)
+	// ListDue 获取已到执行时间的待执行任务，按执行时间升序
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*model.ProductSchedule, error)
+	// UpdateStatus 仅当任务仍处于 fromStatus 时修改状态，返回是否更新成功（用于保证只执行或取消一次）
+	UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int8, errMsg string) (bool, error)
+}
+
+// scheduleRepository 商品定时任务数据访问实现
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository 创建商品定时任务数据访问实例
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+// Create 创建定时任务
+func (r *scheduleRepository) Create(ctx context.Context, schedule *model.ProductSchedule) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+// GetByID 根据ID获取定时任务
+func (r *scheduleRepository) GetByID(ctx context.Context, id uint64) (*model.ProductSchedule, error) {
+	var schedule model.ProductSchedule
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// List 分页获取定时任务列表
+func (r *scheduleRepository) List(ctx context.Context, productID uint64, status int8, page, pageSize int) ([]*model.ProductSchedule, int64, error) {
+	var schedules []*model.ProductSchedule
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.ProductSchedule{})
+	if productID > 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+	if status >= 0 {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("run_at ASC, id ASC").Offset(offset).Limit(pageSize).Find(&schedules).Error; err != nil {
+		return nil, 0, err
+	}
+	return schedules, total, nil
+}
+
+// ListDue 获取到期的待执行任务
+func (r *scheduleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*model.ProductSchedule, error) {
+	var schedules []*model.ProductSchedule
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND run_at <= ?", model.ScheduleStatusPending, now).
+		Order("run_at ASC, id ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// UpdateStatus 条件更新任务状态，离开待执行状态时记录执行时间
+func (r *scheduleRepository) UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int8, errMsg string) (bool, error) {
+	now := time.Now()
+	updates := map[string]any{
+		"status":     toStatus,
+		"error":      errMsg,
+		"updated_at": now,
+	}
+	if toStatus == model.ScheduleStatusDone || toStatus == model.ScheduleStatusFailed {
+		updates["executed_at"] = now
+	}
+	result := r.db.WithContext(ctx).Model(&model.ProductSchedule{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transactor 事务执行接口：fn 返回错误时回滚 fn 内的全部写入。
+// 在 TxRepos 上再调用 Transaction 即为当前事务内的保存点
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(r *TxRepos) error) error
+}
+
+// TxRepos 同一事务内使用的数据访问对象
+type TxRepos struct {
+	Transactor
+	// DB 当前事务，用于在同一事务内写 outbox 事件；不是数据库事务时为 nil
+	DB       *gorm.DB
+	Product  ProductRepository
+	Sku      SkuRepository
+	Revision RevisionRepository
+	Schedule ScheduleRepository
+}
+
+// gormTransactor 基于 gorm 的事务实现
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor 创建事务执行实例
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+// Transaction 开启事务（已在事务内时为保存点）执行 fn
+func (t *gormTransactor) Transaction(ctx context.Context, fn func(r *TxRepos) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewTxRepos(tx))
+	})
+}
+
+// NewTxRepos 创建绑定到事务 tx 的数据访问对象
+func NewTxRepos(tx *gorm.DB) *TxRepos {
+	return &TxRepos{
+		Transactor: &gormTransactor{db: tx},
+		DB:         tx,
+		Product:    NewProductRepository(tx),
+		Sku:        NewSkuRepository(tx),
+		Revision:   NewRevisionRepository(tx),
+		Schedule:   NewScheduleRepository(tx),
+	}
+}
@@ -0,0 +1,739 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ecommerce-system/internal/pkg/cache"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/outbox"
+	"ecommerce-system/internal/service/product/model"
+	"ecommerce-system/internal/service/product/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxAttrTextLen 文本类型属性值的最大长度
+	maxAttrTextLen = 255
+	// maxGeneratedSkus 单次最多生成的 SKU 组合数
+	maxGeneratedSkus = 200
+	// maxCategoryDepth 向上查找父类目的最大层数，防止脏数据成环
+	maxCategoryDepth = 10
+)
+
+// TemplateAttr 类目属性模板中的一项，Inherited 表示来自上级类目
+type TemplateAttr struct {
+	*model.Attr
+	Values    []string // 解析后的可选值，覆盖 model.Attr 中的 JSON 字符串
+	Inherited bool
+}
+
+// ListCategoryAttrsRequest 获取类目属性模板请求
+type ListCategoryAttrsRequest struct {
+	CategoryID       uint64
+	Type             int8 // 0-全部, 1-规格参数, 2-销售属性, 3-基础属性
+	IncludeInherited bool
+}
+
+// ListCategoryAttrs 获取类目属性模板。包含继承时按根类目到当前类目的顺序合并，
+// 下级类目的同名属性覆盖上级类目
+func (l *ProductLogic) ListCategoryAttrs(ctx context.Context, req *ListCategoryAttrsRequest) ([]*TemplateAttr, error) {
+	if l.attrRepo == nil || l.categoryRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.CategoryID == 0 {
+		return nil, apperrors.NewInvalidParamError("类目ID不能为空")
+	}
+
+	var attrs []*TemplateAttr
+	var err error
+	if req.IncludeInherited {
+		attrs, err = l.resolveAttrTemplate(ctx, req.CategoryID)
+	} else {
+		if _, err = l.getCategory(ctx, req.CategoryID); err != nil {
+			return nil, err
+		}
+		var list []*model.Attr
+		list, err = l.attrRepo.ListByCategoryIDs(ctx, []uint64{req.CategoryID})
+		if err != nil {
+			return nil, apperrors.NewInternalError("查询类目属性失败: " + err.Error())
+		}
+		for _, a := range list {
+			attrs = append(attrs, newTemplateAttr(a, false))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type <= 0 {
+		return attrs, nil
+	}
+	filtered := make([]*TemplateAttr, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Type == req.Type {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered, nil
+}
+
+// CreateAttrRequest 创建类目属性请求
+type CreateAttrRequest struct {
+	CategoryID uint64
+	Name       string
+	Type       int8
+	InputType  int8
+	Values     []string
+	Sort       int
+	IsRequired int8
+}
+
+// CreateAttr 创建类目属性（管理后台）
+func (l *ProductLogic) CreateAttr(ctx context.Context, req *CreateAttrRequest) (*TemplateAttr, error) {
+	if l.attrRepo == nil || l.categoryRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.CategoryID == 0 {
+		return nil, apperrors.NewInvalidParamError("类目ID不能为空")
+	}
+	if _, err := l.getCategory(ctx, req.CategoryID); err != nil {
+		return nil, err
+	}
+	if req.Type != model.AttrTypeSpec && req.Type != model.AttrTypeSales && req.Type != model.AttrTypeBasic {
+		return nil, apperrors.NewInvalidParamError("属性类型错误")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := l.checkAttrName(ctx, req.CategoryID, 0, name); err != nil {
+		return nil, err
+	}
+	values, err := normalizeAttrValues(req.Type, req.InputType, req.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	attr := &model.Attr{
+		CategoryID: req.CategoryID,
+		Name:       name,
+		Type:       req.Type,
+		InputType:  req.InputType,
+		Values:     marshalAttrValues(values),
+		Sort:       req.Sort,
+		IsRequired: boolToInt8(req.IsRequired == 1),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := l.attrRepo.Create(ctx, attr); err != nil {
+		return nil, apperrors.NewInternalError("创建类目属性失败: " + err.Error())
+	}
+	return newTemplateAttr(attr, false), nil
+}
+
+// UpdateAttrRequest 更新类目属性请求
+type UpdateAttrRequest struct {
+	ID         uint64
+	Name       string
+	InputType  int8     // 0 表示不更新
+	Values     []string // 为空表示不更新
+	Sort       int
+	IsRequired int8 // -1 表示不更新
+}
+
+// UpdateAttr 更新类目属性（管理后台）。属性类型不允许修改；
+// 已保存的商品属性值不会随之迁移，收紧可选值后编辑老商品时会重新校验
+func (l *ProductLogic) UpdateAttr(ctx context.Context, req *UpdateAttrRequest) (*TemplateAttr, error) {
+	attr, err := l.getAttr(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" && name != attr.Name {
+		if err := l.checkAttrName(ctx, attr.CategoryID, attr.ID, name); err != nil {
+			return nil, err
+		}
+		attr.Name = name
+	}
+	inputType := attr.InputType
+	if req.InputType > 0 {
+		inputType = req.InputType
+	}
+	values := parseAttrValues(attr.Values)
+	if len(req.Values) > 0 {
+		values = req.Values
+	}
+	if values, err = normalizeAttrValues(attr.Type, inputType, values); err != nil {
+		return nil, err
+	}
+	attr.InputType = inputType
+	attr.Values = marshalAttrValues(values)
+	if req.Sort > 0 {
+		attr.Sort = req.Sort
+	}
+	if req.IsRequired >= 0 {
+		attr.IsRequired = boolToInt8(req.IsRequired == 1)
+	}
+	attr.UpdatedAt = time.Now()
+
+	if err := l.attrRepo.Update(ctx, attr); err != nil {
+		return nil, apperrors.NewInternalError("更新类目属性失败: " + err.Error())
+	}
+	return newTemplateAttr(attr, false), nil
+}
+
+// DeleteAttr 删除类目属性（管理后台），已保存的商品属性值保留，不再参与校验
+func (l *ProductLogic) DeleteAttr(ctx context.Context, id uint64) error {
+	attr, err := l.getAttr(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := l.attrRepo.Delete(ctx, attr.ID); err != nil {
+		return apperrors.NewInternalError("删除类目属性失败: " + err.Error())
+	}
+	return nil
+}
+
+// GenerateSkusRequest 批量生成SKU请求
+type GenerateSkusRequest struct {
+	ProductID uint64
+	Attrs     map[string][]string // 参与组合的销售属性取值，未传的属性使用模板全部可选值
+	Price     float64             // 为 0 时使用商品价格
+	Stock     int
+	Status    int8
+	DryRun    bool
+}
+
+// GenerateSkusResponse 批量生成SKU响应
+type GenerateSkusResponse struct {
+	Skus    []*model.Sku
+	Skipped int
+}
+
+// GenerateSkus 按商品类目的销售属性做笛卡尔积生成SKU，已存在的规格组合跳过。
+// SKU 编码为 SPU 编码加规格组合的短哈希，同一组合重复生成得到相同编码
+func (l *ProductLogic) GenerateSkus(ctx context.Context, req *GenerateSkusRequest) (*GenerateSkusResponse, error) {
+	if l.productRepo == nil || l.skuRepo == nil || l.attrRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.ProductID == 0 {
+		return nil, apperrors.NewInvalidParamError("商品ID不能为空")
+	}
+	product, err := l.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewError(apperrors.CodeProductNotFound, "商品不存在")
+		}
+		return nil, apperrors.NewInternalError("查询商品失败: " + err.Error())
+	}
+
+	template, err := l.resolveAttrTemplate(ctx, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	var dims []*TemplateAttr
+	for _, a := range template {
+		if a.Type == model.AttrTypeSales {
+			dims = append(dims, a)
+		}
+	}
+	if len(dims) == 0 {
+		return nil, apperrors.NewInvalidParamError("商品类目未配置销售属性")
+	}
+
+	// 确定每个销售属性参与组合的取值
+	dimValues := make([][]string, len(dims))
+	for name := range req.Attrs {
+		if findTemplateAttr(dims, name) == nil {
+			return nil, apperrors.NewInvalidParamError(fmt.Sprintf("销售属性「%s」不存在", name))
+		}
+	}
+	total := 1
+	for i, a := range dims {
+		values := a.Values
+		if picked, ok := req.Attrs[a.Name]; ok {
+			values = dedupStrings(picked)
+			for _, v := range values {
+				if !containsString(a.Values, v) {
+					return nil, apperrors.NewInvalidParamError(fmt.Sprintf("销售属性「%s」不支持取值「%s」", a.Name, v))
+				}
+			}
+		}
+		if len(values) == 0 {
+			return nil, apperrors.NewInvalidParamError(fmt.Sprintf("销售属性「%s」没有可选值", a.Name))
+		}
+		dimValues[i] = values
+		total *= len(values)
+		if total > maxGeneratedSkus {
+			return nil, apperrors.NewInvalidParamError(fmt.Sprintf("规格组合超过 %d 个，请减少取值", maxGeneratedSkus))
+		}
+	}
+
+	existing, err := l.skuRepo.GetByProductID(ctx, product.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询SKU失败: " + err.Error())
+	}
+	existingKeys := make(map[string]struct{}, len(existing))
+	for _, s := range existing {
+		if specs, err := parseJSONMap(s.Specs); err == nil {
+			existingKeys[specsKey(specs)] = struct{}{}
+		}
+	}
+
+	price := req.Price
+	if price <= 0 {
+		price = product.Price
+	}
+	now := time.Now()
+	resp := &GenerateSkusResponse{}
+	for _, combo := range cartesian(dimValues) {
+		specs := make(map[string]string, len(dims))
+		for i, a := range dims {
+			specs[a.Name] = combo[i]
+		}
+		key := specsKey(specs)
+		if _, ok := existingKeys[key]; ok {
+			resp.Skipped++
+			continue
+		}
+		specsJSON, _ := json.Marshal(specs)
+		sum := sha1.Sum([]byte(key))
+		resp.Skus = append(resp.Skus, &model.Sku{
+			ProductID: product.ID,
+			SkuCode:   product.SpuCode + "-" + strings.ToUpper(hex.EncodeToString(sum[:4])),
+			Name:      product.Name + " " + strings.Join(combo, " "),
+			Specs:     string(specsJSON),
+			Price:     price,
+			Stock:     req.Stock,
+			Status:    req.Status,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+	if req.DryRun || len(resp.Skus) == 0 {
+		return resp, nil
+	}
+
+	write := func(skuRepo repository.SkuRepository, tx *gorm.DB) error {
+		for i, sku := range resp.Skus {
+			existingAny, err := skuRepo.GetBySkuCodeUnscoped(ctx, sku.SkuCode)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.NewInternalError("查询SKU失败: " + err.Error())
+			}
+			if existingAny == nil {
+				if err := skuRepo.Create(ctx, sku); err != nil {
+					if isDuplicateSkuCodeErr(err) {
+						return apperrors.NewError(apperrors.CodeAlreadyExists, "SKU编码已存在: "+sku.SkuCode)
+					}
+					return apperrors.NewInternalError("创建SKU失败: " + err.Error())
+				}
+				continue
+			}
+			if !existingAny.DeletedAt.Valid {
+				return apperrors.NewError(apperrors.CodeAlreadyExists, "SKU编码已存在: "+sku.SkuCode)
+			}
+			// 之前删除过的同一组合：恢复并覆盖字段
+			updates := map[string]any{
+				"product_id": sku.ProductID,
+				"name":       sku.Name,
+				"specs":      sku.Specs,
+				"price":      sku.Price,
+				"stock":      sku.Stock,
+				"status":     sku.Status,
+				"updated_at": now,
+			}
+			if err := skuRepo.RestoreAndUpdateByID(ctx, existingAny.ID, updates); err != nil {
+				return apperrors.NewInternalError("恢复SKU失败: " + err.Error())
+			}
+			if restored, _ := skuRepo.GetByID(ctx, existingAny.ID); restored != nil {
+				resp.Skus[i] = restored
+			}
+		}
+		if tx == nil || l.outboxRepo == nil {
+			return nil
+		}
+		payloadBytes, _ := json.Marshal(map[string]any{"product_id": product.ID})
+		payload := string(payloadBytes)
+		evt := &outbox.Event{
+			AggregateType: "product",
+			AggregateID:   fmt.Sprintf("%d", product.ID),
+			EventType:     outbox.EventProductUpserted,
+			Payload:       &payload,
+			Status:        outbox.StatusPending,
+		}
+		return l.outboxRepo.CreateInTx(ctx, tx, evt)
+	}
+
+	if l.db != nil {
+		err = l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return write(repository.NewSkuRepository(tx), tx)
+		})
+	} else {
+		err = write(l.skuRepo, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if l.cache != nil {
+		for _, sku := range resp.Skus {
+			_ = l.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixSkuInfo, sku.ID))
+		}
+	}
+	l.clearProductCaches(ctx, []uint64{product.ID})
+	return resp, nil
+}
+
+// resolveAttrTemplate 解析类目的有效属性模板：沿父类目向上查找，
+// 按根类目到当前类目的顺序合并，下级类目的同名属性覆盖上级类目
+func (l *ProductLogic) resolveAttrTemplate(ctx context.Context, categoryID uint64) ([]*TemplateAttr, error) {
+	if l.attrRepo == nil || l.categoryRepo == nil {
+		return nil, nil
+	}
+
+	// chain 为当前类目到根类目
+	var chain []uint64
+	visited := make(map[uint64]bool)
+	for id := categoryID; id != 0 && !visited[id] && len(chain) < maxCategoryDepth; {
+		category, err := l.getCategory(ctx, id)
+		if err != nil {
+			if len(chain) == 0 {
+				return nil, err
+			}
+			// 上级类目已被删除，按现有链路继续
+			break
+		}
+		visited[id] = true
+		chain = append(chain, id)
+		id = category.ParentID
+	}
+
+	list, err := l.attrRepo.ListByCategoryIDs(ctx, chain)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询类目属性失败: " + err.Error())
+	}
+	byCategory := make(map[uint64][]*model.Attr, len(chain))
+	for _, a := range list {
+		byCategory[a.CategoryID] = append(byCategory[a.CategoryID], a)
+	}
+
+	var result []*TemplateAttr
+	index := make(map[string]int)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, a := range byCategory[chain[i]] {
+			item := newTemplateAttr(a, chain[i] != categoryID)
+			if pos, ok := index[a.Name]; ok {
+				result[pos] = item
+				continue
+			}
+			index[a.Name] = len(result)
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// normalizeProductAttrs 按类目属性模板校验商品的规格参数和基础属性，返回落库的 JSON。
+// 类目没有配置模板时不校验，保持原来的自由填写
+func (l *ProductLogic) normalizeProductAttrs(ctx context.Context, categoryID uint64, attrs map[string]string) (string, error) {
+	cleaned := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if k != "" && v != "" {
+			cleaned[k] = v
+		}
+	}
+
+	template, err := l.resolveAttrTemplate(ctx, categoryID)
+	if err != nil {
+		return "", err
+	}
+	if len(template) > 0 {
+		for name := range cleaned {
+			a := findTemplateAttr(template, name)
+			if a == nil {
+				return "", apperrors.NewInvalidParamError(fmt.Sprintf("类目没有属性「%s」", name))
+			}
+			if a.Type == model.AttrTypeSales {
+				return "", apperrors.NewInvalidParamError(fmt.Sprintf("「%s」是销售属性，应在SKU规格中设置", name))
+			}
+		}
+		for _, a := range template {
+			if a.Type == model.AttrTypeSales {
+				continue
+			}
+			value, ok := cleaned[a.Name]
+			if !ok {
+				if a.IsRequired == 1 {
+					return "", apperrors.NewInvalidParamError(fmt.Sprintf("属性「%s」不能为空", a.Name))
+				}
+				continue
+			}
+			normalized, err := checkAttrValue(a, value)
+			if err != nil {
+				return "", err
+			}
+			cleaned[a.Name] = normalized
+		}
+	}
+
+	data, err := json.Marshal(cleaned)
+	if err != nil {
+		return "", apperrors.NewInternalError("商品属性格式错误: " + err.Error())
+	}
+	return string(data), nil
+}
+
+// checkSkuSpecs 按类目销售属性校验SKU规格，并检查同一商品下没有重复的规格组合。
+// 类目没有配置销售属性时不校验
+func (l *ProductLogic) checkSkuSpecs(ctx context.Context, skuRepo repository.SkuRepository, product *model.Product, specs map[string]string, selfID uint64) error {
+	template, err := l.resolveAttrTemplate(ctx, product.CategoryID)
+	if err != nil {
+		return err
+	}
+	var sales []*TemplateAttr
+	for _, a := range template {
+		if a.Type == model.AttrTypeSales {
+			sales = append(sales, a)
+		}
+	}
+	if len(sales) == 0 {
+		return nil
+	}
+
+	for name := range specs {
+		if findTemplateAttr(sales, name) == nil {
+			return apperrors.NewInvalidParamError(fmt.Sprintf("类目没有销售属性「%s」", name))
+		}
+	}
+	for _, a := range sales {
+		value, ok := specs[a.Name]
+		if !ok || value == "" {
+			if a.IsRequired == 1 {
+				return apperrors.NewInvalidParamError(fmt.Sprintf("规格「%s」不能为空", a.Name))
+			}
+			continue
+		}
+		if _, err := checkAttrValue(a, value); err != nil {
+			return err
+		}
+	}
+
+	siblings, err := skuRepo.GetByProductID(ctx, product.ID)
+	if err != nil {
+		return apperrors.NewInternalError("查询SKU失败: " + err.Error())
+	}
+	key := specsKey(specs)
+	for _, s := range siblings {
+		if s.ID == selfID {
+			continue
+		}
+		if other, err := parseJSONMap(s.Specs); err == nil && specsKey(other) == key {
+			return apperrors.NewError(apperrors.CodeAlreadyExists, "该规格组合的SKU已存在: "+s.SkuCode)
+		}
+	}
+	return nil
+}
+
+// checkAttrName 校验属性名称非空且在类目内不重复
+func (l *ProductLogic) checkAttrName(ctx context.Context, categoryID, selfID uint64, name string) error {
+	if name == "" {
+		return apperrors.NewInvalidParamError("属性名称不能为空")
+	}
+	if len([]rune(name)) > 50 {
+		return apperrors.NewInvalidParamError("属性名称不能超过50个字符")
+	}
+	list, err := l.attrRepo.ListByCategoryIDs(ctx, []uint64{categoryID})
+	if err != nil {
+		return apperrors.NewInternalError("查询类目属性失败: " + err.Error())
+	}
+	for _, a := range list {
+		if a.Name == name && a.ID != selfID {
+			return apperrors.NewInvalidParamError("类目下已存在同名属性")
+		}
+	}
+	return nil
+}
+
+// getAttr 获取类目属性
+func (l *ProductLogic) getAttr(ctx context.Context, id uint64) (*model.Attr, error) {
+	if l.attrRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if id == 0 {
+		return nil, apperrors.NewInvalidParamError("属性ID不能为空")
+	}
+	attr, err := l.attrRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewError(apperrors.CodeNotFound, "类目属性不存在")
+		}
+		return nil, apperrors.NewInternalError("查询类目属性失败: " + err.Error())
+	}
+	return attr, nil
+}
+
+// getCategory 获取类目
+func (l *ProductLogic) getCategory(ctx context.Context, id uint64) (*model.Category, error) {
+	category, err := l.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewError(apperrors.CodeCategoryNotFound, "类目不存在")
+		}
+		return nil, apperrors.NewInternalError("查询类目失败: " + err.Error())
+	}
+	return category, nil
+}
+
+// checkAttrValue 校验属性值，返回规范化后的值（多选值去重后以逗号拼接）
+func checkAttrValue(a *TemplateAttr, value string) (string, error) {
+	switch a.InputType {
+	case model.AttrInputSingle:
+		if !containsString(a.Values, value) {
+			return "", apperrors.NewInvalidParamError(fmt.Sprintf("属性「%s」不支持取值「%s」", a.Name, value))
+		}
+		return value, nil
+	case model.AttrInputMulti:
+		var picked []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				picked = append(picked, v)
+			}
+		}
+		picked = dedupStrings(picked)
+		for _, v := range picked {
+			if !containsString(a.Values, v) {
+				return "", apperrors.NewInvalidParamError(fmt.Sprintf("属性「%s」不支持取值「%s」", a.Name, v))
+			}
+		}
+		return strings.Join(picked, ","), nil
+	default:
+		if len([]rune(value)) > maxAttrTextLen {
+			return "", apperrors.NewInvalidParamError(fmt.Sprintf("属性「%s」不能超过%d个字符", a.Name, maxAttrTextLen))
+		}
+		return value, nil
+	}
+}
+
+// normalizeAttrValues 校验属性的输入方式和可选值
+func normalizeAttrValues(attrType, inputType int8, values []string) ([]string, error) {
+	if inputType != model.AttrInputSingle && inputType != model.AttrInputMulti && inputType != model.AttrInputText {
+		return nil, apperrors.NewInvalidParamError("属性输入方式错误")
+	}
+	// 销售属性要参与SKU组合，只能是单选
+	if attrType == model.AttrTypeSales && inputType != model.AttrInputSingle {
+		return nil, apperrors.NewInvalidParamError("销售属性只能是单选")
+	}
+	if inputType == model.AttrInputText {
+		return []string{}, nil
+	}
+	cleaned := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if strings.Contains(v, ",") {
+			return nil, apperrors.NewInvalidParamError("可选值不能包含逗号")
+		}
+		cleaned = append(cleaned, v)
+	}
+	cleaned = dedupStrings(cleaned)
+	if len(cleaned) == 0 {
+		return nil, apperrors.NewInvalidParamError("可选值不能为空")
+	}
+	return cleaned, nil
+}
+
+func newTemplateAttr(a *model.Attr, inherited bool) *TemplateAttr {
+	return &TemplateAttr{Attr: a, Values: parseAttrValues(a.Values), Inherited: inherited}
+}
+
+func findTemplateAttr(attrs []*TemplateAttr, name string) *TemplateAttr {
+	for _, a := range attrs {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+func parseAttrValues(s string) []string {
+	values, err := parseJSONArray(s)
+	if err != nil {
+		return []string{}
+	}
+	return values
+}
+
+func marshalAttrValues(values []string) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// specsKey 规格组合的规范化表示，与键的顺序无关
+func specsKey(specs map[string]string) string {
+	keys := make([]string, 0, len(specs))
+	for k := range specs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+specs[k])
+	}
+	return strings.Join(parts, ";")
+}
+
+// cartesian 计算多组取值的笛卡尔积
+func cartesian(dims [][]string) [][]string {
+	result := [][]string{{}}
+	for _, values := range dims {
+		next := make([][]string, 0, len(result)*len(values))
+		for _, prefix := range result {
+			for _, v := range values {
+				combo := make([]string, len(prefix), len(prefix)+1)
+				copy(combo, prefix)
+				next = append(next, append(combo, v))
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+func dedupStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func boolToInt8(b bool) int8 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/service/product/model"
+)
+
+func newAttr(id, categoryID uint64, name string, attrType, inputType int8, required bool, values ...string) *model.Attr {
+	return &model.Attr{
+		ID:         id,
+		CategoryID: categoryID,
+		Name:       name,
+		Type:       attrType,
+		InputType:  inputType,
+		Values:     marshalAttrValues(values),
+		IsRequired: boolToInt8(required),
+	}
+}
+
+// newAttrTestEnv 类目 1 > 2 > 3：根类目定义产地、材质和颜色，
+// 类目 2 把材质覆盖为单选并新增尺码，类目 3 没有自己的属性
+func newAttrTestEnv() *productTestEnv {
+	env := newProductTestEnv()
+	env.categories.seed(
+		&model.Category{ID: 1, Name: "服装", Level: 1},
+		&model.Category{ID: 2, ParentID: 1, Name: "上衣", Level: 2},
+		&model.Category{ID: 3, ParentID: 2, Name: "T恤", Level: 3},
+	)
+	env.attrs.seed(
+		newAttr(1, 1, "产地", model.AttrTypeBasic, model.AttrInputText, true),
+		newAttr(2, 1, "材质", model.AttrTypeSpec, model.AttrInputMulti, false, "棉", "麻", "涤纶"),
+		newAttr(3, 1, "颜色", model.AttrTypeSales, model.AttrInputSingle, true, "红", "蓝"),
+		newAttr(4, 2, "材质", model.AttrTypeSpec, model.AttrInputSingle, true, "纯棉", "莫代尔"),
+		newAttr(5, 2, "尺码", model.AttrTypeSales, model.AttrInputSingle, true, "M", "L"),
+	)
+	return env
+}
+
+func TestListCategoryAttrsInheritance(t *testing.T) {
+	logic := newAttrTestEnv().logic
+	ctx := context.Background()
+
+	attrs, err := logic.ListCategoryAttrs(ctx, &ListCategoryAttrsRequest{CategoryID: 2, IncludeInherited: true})
+	if err != nil {
+		t.Fatalf("ListCategoryAttrs: %v", err)
+	}
+	got := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		got = append(got, a.Name)
+	}
+	if strings.Join(got, ",") != "产地,材质,颜色,尺码" {
+		t.Fatalf("template order = %v", got)
+	}
+	// 材质被下级类目覆盖，保留上级的位置
+	material := findTemplateAttr(attrs, "材质")
+	if material.ID != 4 || material.Inherited || material.InputType != model.AttrInputSingle || strings.Join(material.Values, ",") != "纯棉,莫代尔" {
+		t.Fatalf("overridden attr = %+v (values %v)", material.Attr, material.Values)
+	}
+	if origin := findTemplateAttr(attrs, "产地"); !origin.Inherited {
+		t.Fatalf("attr from parent category not marked inherited: %+v", origin.Attr)
+	}
+
+	// 孙类目没有自己的属性，全部继承
+	attrs, err = logic.ListCategoryAttrs(ctx, &ListCategoryAttrsRequest{CategoryID: 3, IncludeInherited: true, Type: model.AttrTypeSales})
+	if err != nil {
+		t.Fatalf("ListCategoryAttrs: %v", err)
+	}
+	if len(attrs) != 2 || !attrs[0].Inherited || !attrs[1].Inherited {
+		t.Fatalf("unexpected inherited sales attrs: %+v", attrs)
+	}
+
+	// 不含继承时只返回本类目的属性
+	attrs, err = logic.ListCategoryAttrs(ctx, &ListCategoryAttrsRequest{CategoryID: 3})
+	if err != nil || len(attrs) != 0 {
+		t.Fatalf("own attrs of category 3 = %+v, %v", attrs, err)
+	}
+
+	_, err = logic.ListCategoryAttrs(ctx, &ListCategoryAttrsRequest{CategoryID: 99, IncludeInherited: true})
+	assertBizCode(t, err, apperrors.CodeCategoryNotFound)
+}
+
+func TestNormalizeProductAttrs(t *testing.T) {
+	env := newAttrTestEnv()
+	logic := env.logic
+	ctx := context.Background()
+
+	data, err := logic.normalizeProductAttrs(ctx, 1, map[string]string{" 产地 ": " 杭州 ", "材质": "棉, 麻,棉", "空": ""})
+	if err != nil {
+		t.Fatalf("normalizeProductAttrs: %v", err)
+	}
+	var saved map[string]string
+	if err := json.Unmarshal([]byte(data), &saved); err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	if len(saved) != 2 || saved["产地"] != "杭州" || saved["材质"] != "棉,麻" {
+		t.Fatalf("normalized attrs = %v", saved)
+	}
+
+	// 下级类目按覆盖后的定义校验：材质变为必填单选
+	if _, err := logic.normalizeProductAttrs(ctx, 3, map[string]string{"产地": "杭州", "材质": "纯棉"}); err != nil {
+		t.Fatalf("normalizeProductAttrs with overridden attr: %v", err)
+	}
+
+	cases := map[string]struct {
+		categoryID uint64
+		attrs      map[string]string
+	}{
+		"missing required":         {1, map[string]string{"材质": "棉"}},
+		"missing overridden":       {3, map[string]string{"产地": "杭州"}},
+		"single value not in enum": {3, map[string]string{"产地": "杭州", "材质": "棉"}},
+		"multi value not in enum":  {1, map[string]string{"产地": "杭州", "材质": "棉,丝"}},
+		"multi value for single":   {3, map[string]string{"产地": "杭州", "材质": "纯棉,莫代尔"}},
+		"text too long":            {1, map[string]string{"产地": strings.Repeat("长", maxAttrTextLen+1)}},
+		"unknown attr":             {1, map[string]string{"产地": "杭州", "重量": "1kg"}},
+		"sales attr on product":    {1, map[string]string{"产地": "杭州", "颜色": "红"}},
+	}
+	for name, tc := range cases {
+		_, err := logic.normalizeProductAttrs(ctx, tc.categoryID, tc.attrs)
+		if bizErr, ok := err.(*apperrors.BusinessError); !ok || bizErr.Code != apperrors.CodeInvalidParam {
+			t.Errorf("%s: expected invalid param, got %v", name, err)
+		}
+	}
+
+	// 类目没有模板时不校验
+	clear(env.attrs.rows)
+	if _, err := logic.normalizeProductAttrs(ctx, 1, map[string]string{"任意": "值"}); err != nil {
+		t.Fatalf("category without template: %v", err)
+	}
+}
+
+func TestCreateAttrRejectsInvalidDefinition(t *testing.T) {
+	env := newAttrTestEnv()
+	logic, attrs := env.logic, env.attrs
+	ctx := context.Background()
+	before := len(attrs.rows)
+
+	cases := map[string]*CreateAttrRequest{
+		"bad type":           {CategoryID: 2, Name: "重量", Type: 9, InputType: model.AttrInputText},
+		"bad input type":     {CategoryID: 2, Name: "重量", Type: model.AttrTypeSpec, InputType: 9},
+		"multi sales attr":   {CategoryID: 2, Name: "版型", Type: model.AttrTypeSales, InputType: model.AttrInputMulti, Values: []string{"修身"}},
+		"empty values":       {CategoryID: 2, Name: "版型", Type: model.AttrTypeSales, InputType: model.AttrInputSingle, Values: []string{" "}},
+		"comma in value":     {CategoryID: 2, Name: "版型", Type: model.AttrTypeSales, InputType: model.AttrInputSingle, Values: []string{"修身,宽松"}},
+		"empty name":         {CategoryID: 2, Name: " ", Type: model.AttrTypeSpec, InputType: model.AttrInputText},
+		"duplicate in scope": {CategoryID: 2, Name: "尺码", Type: model.AttrTypeSales, InputType: model.AttrInputSingle, Values: []string{"S"}},
+	}
+	for name, req := range cases {
+		_, err := logic.CreateAttr(ctx, req)
+		if bizErr, ok := err.(*apperrors.BusinessError); !ok || bizErr.Code != apperrors.CodeInvalidParam {
+			t.Errorf("%s: expected invalid param, got %v", name, err)
+		}
+	}
+	if len(attrs.rows) != before {
+		t.Fatalf("rejected definitions were saved: %d attrs, want %d", len(attrs.rows), before)
+	}
+
+	// 与上级类目同名的属性可以在下级类目创建，用来覆盖
+	created, err := logic.CreateAttr(ctx, &CreateAttrRequest{CategoryID: 3, Name: "颜色", Type: model.AttrTypeSales, InputType: model.AttrInputSingle, Values: []string{"黑", "白", "黑"}, IsRequired: 1})
+	if err != nil {
+		t.Fatalf("CreateAttr: %v", err)
+	}
+	if strings.Join(created.Values, ",") != "黑,白" || created.IsRequired != 1 {
+		t.Fatalf("created attr = %+v (values %v)", created.Attr, created.Values)
+	}
+}
+
+func TestSkuSpecsRejectDuplicateCombination(t *testing.T) {
+	env := newAttrTestEnv()
+	env.products.seed(&model.Product{ID: 10, SpuCode: "SPU10", Name: "T恤", CategoryID: 3, Price: 99})
+	env.skus.seed(&model.Sku{ID: 1, ProductID: 10, SkuCode: "SPU10-A", Specs: `{"尺码":"M","颜色":"红"}`, Status: 1})
+	logic, products, skus := env.logic, env.products, env.skus
+	ctx := context.Background()
+
+	// 同一组合换一个键顺序也视为重复
+	_, err := logic.CreateSku(ctx, &CreateSkuRequest{ProductID: 10, SkuCode: "SPU10-B", Name: "红M", Specs: map[string]string{"颜色": "红", "尺码": "M"}, Price: 99})
+	assertBizCode(t, err, apperrors.CodeAlreadyExists)
+
+	// 规格不合法
+	for name, specs := range map[string]map[string]string{
+		"missing required":  {"颜色": "红"},
+		"value not in enum": {"颜色": "绿", "尺码": "M"},
+		"unknown spec":      {"颜色": "红", "尺码": "M", "袖长": "短"},
+	} {
+		_, err := logic.CreateSku(ctx, &CreateSkuRequest{ProductID: 10, SkuCode: "SPU10-" + name, Name: name, Specs: specs, Price: 99})
+		if bizErr, ok := err.(*apperrors.BusinessError); !ok || bizErr.Code != apperrors.CodeInvalidParam {
+			t.Errorf("%s: expected invalid param, got %v", name, err)
+		}
+	}
+	if len(skus.rows) != 1 {
+		t.Fatalf("rejected SKUs were saved: %d", len(skus.rows))
+	}
+
+	resp, err := logic.CreateSku(ctx, &CreateSkuRequest{ProductID: 10, SkuCode: "SPU10-B", Name: "蓝M", Specs: map[string]string{"颜色": "蓝", "尺码": "M"}, Price: 99})
+	if err != nil {
+		t.Fatalf("CreateSku: %v", err)
+	}
+	// 更新 SKU 时不和自己比较
+	product, _ := products.GetByID(ctx, 10)
+	if err := logic.checkSkuSpecs(ctx, skus, product, map[string]string{"颜色": "蓝", "尺码": "M"}, resp.Sku.ID); err != nil {
+		t.Fatalf("checkSkuSpecs against itself: %v", err)
+	}
+	err = logic.checkSkuSpecs(ctx, skus, product, map[string]string{"颜色": "红", "尺码": "M"}, resp.Sku.ID)
+	assertBizCode(t, err, apperrors.CodeAlreadyExists)
+}
+
+func TestGenerateSkusSkipsExistingCombinations(t *testing.T) {
+	env := newAttrTestEnv()
+	env.products.seed(&model.Product{ID: 10, SpuCode: "SPU10", Name: "T恤", CategoryID: 3, Price: 99})
+	env.skus.seed(&model.Sku{ID: 1, ProductID: 10, SkuCode: "SPU10-A", Specs: `{"尺码":"M","颜色":"红"}`, Status: 1})
+	logic, skus := env.logic, env.skus
+	ctx := context.Background()
+
+	preview, err := logic.GenerateSkus(ctx, &GenerateSkusRequest{ProductID: 10, DryRun: true})
+	if err != nil {
+		t.Fatalf("GenerateSkus dry run: %v", err)
+	}
+	if len(preview.Skus) != 3 || preview.Skipped != 1 || len(skus.rows) != 1 {
+		t.Fatalf("dry run: %d skus, %d skipped, %d stored", len(preview.Skus), preview.Skipped, len(skus.rows))
+	}
+
+	resp, err := logic.GenerateSkus(ctx, &GenerateSkusRequest{ProductID: 10, Status: 1})
+	if err != nil {
+		t.Fatalf("GenerateSkus: %v", err)
+	}
+	if len(resp.Skus) != 3 || len(skus.rows) != 4 {
+		t.Fatalf("generated %d skus, %d stored", len(resp.Skus), len(skus.rows))
+	}
+	seen := map[string]bool{}
+	for _, s := range resp.Skus {
+		if s.Price != 99 || seen[s.SkuCode] {
+			t.Fatalf("unexpected generated sku: %+v", s)
+		}
+		seen[s.SkuCode] = true
+	}
+
+	// 再次生成时所有组合都已存在
+	again, err := logic.GenerateSkus(ctx, &GenerateSkusRequest{ProductID: 10})
+	if err != nil {
+		t.Fatalf("GenerateSkus again: %v", err)
+	}
+	if len(again.Skus) != 0 || again.Skipped != 4 || len(skus.rows) != 4 {
+		t.Fatalf("second run: %d skus, %d skipped, %d stored", len(again.Skus), again.Skipped, len(skus.rows))
+	}
+
+	_, err = logic.GenerateSkus(ctx, &GenerateSkusRequest{ProductID: 10, Attrs: map[string][]string{"颜色": {"绿"}}})
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ecommerce-system/internal/pkg/cache"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/outbox"
+	"ecommerce-system/internal/service/product/model"
+	"ecommerce-system/internal/service/product/repository"
+
+	"gorm.io/gorm"
+)
+
+// LogoStore 品牌 Logo 所在的文件服务，由文件服务客户端实现
+type LogoStore interface {
+	GetFileURL(ctx context.Context, fileID string, expireSeconds int64, userID uint64) (string, int64, error)
+}
+
+// ListBrandsRequest 获取品牌列表请求
+type ListBrandsRequest struct {
+	Status   int8 // -1-全部, 0-禁用, 1-启用
+	Keyword  string
+	Page     int
+	PageSize int
+}
+
+// ListBrandsResponse 获取品牌列表响应
+type ListBrandsResponse struct {
+	Brands []*model.Brand
+	Total  int64
+}
+
+// ListBrands 获取品牌列表
+func (l *ProductLogic) ListBrands(ctx context.Context, req *ListBrandsRequest) (*ListBrandsResponse, error) {
+	if l.brandRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	brands, total, err := l.brandRepo.List(ctx, req.Status, strings.TrimSpace(req.Keyword), req.Page, req.PageSize)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询品牌列表失败: " + err.Error())
+	}
+	return &ListBrandsResponse{
+		Brands: brands,
+		Total:  total,
+	}, nil
+}
+
+// GetBrand 获取品牌详情
+func (l *ProductLogic) GetBrand(ctx context.Context, id uint64) (*model.Brand, error) {
+	if l.brandRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if id == 0 {
+		return nil, apperrors.NewInvalidParamError("品牌ID不能为空")
+	}
+
+	brand, err := l.brandRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewError(apperrors.CodeNotFound, "品牌不存在")
+		}
+		return nil, apperrors.NewInternalError("查询品牌失败: " + err.Error())
+	}
+	return brand, nil
+}
+
+// CreateBrandRequest 创建品牌请求
+type CreateBrandRequest struct {
+	Name        string
+	Logo        string // Logo URL
+	LogoFileID  string // 文件服务的文件ID，优先于 Logo
+	Description string
+	Sort        int
+	Status      int8
+}
+
+// CreateBrand 创建品牌（管理后台）
+func (l *ProductLogic) CreateBrand(ctx context.Context, req *CreateBrandRequest) (*model.Brand, error) {
+	if l.brandRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := l.checkBrandName(ctx, 0, name); err != nil {
+		return nil, err
+	}
+	logo, err := l.resolveBrandLogo(ctx, req.Logo, req.LogoFileID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	brand := &model.Brand{
+		Name:        name,
+		Logo:        logo,
+		Description: req.Description,
+		Sort:        req.Sort,
+		Status:      req.Status,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := l.brandRepo.Create(ctx, brand); err != nil {
+		return nil, apperrors.NewInternalError("创建品牌失败: " + err.Error())
+	}
+	return brand, nil
+}
+
+// UpdateBrandRequest 更新品牌请求
+type UpdateBrandRequest struct {
+	ID          uint64
+	Name        string
+	Logo        string
+	LogoFileID  string
+	Description string
+	Sort        int
+	Status      int8 // -1 表示不更新
+}
+
+// UpdateBrand 更新品牌（管理后台）。品牌名称写在商品的搜索文档里，
+// 改名时为品牌下的商品写 product.upserted 事件，由搜索服务重建文档
+func (l *ProductLogic) UpdateBrand(ctx context.Context, req *UpdateBrandRequest) (*model.Brand, error) {
+	brand, err := l.GetBrand(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	if name := strings.TrimSpace(req.Name); name != "" && name != brand.Name {
+		if err := l.checkBrandName(ctx, brand.ID, name); err != nil {
+			return nil, err
+		}
+		brand.Name = name
+		renamed = true
+	}
+	if req.Logo != "" || req.LogoFileID != "" {
+		logo, err := l.resolveBrandLogo(ctx, req.Logo, req.LogoFileID)
+		if err != nil {
+			return nil, err
+		}
+		brand.Logo = logo
+	}
+	if req.Description != "" {
+		brand.Description = req.Description
+	}
+	if req.Sort > 0 {
+		brand.Sort = req.Sort
+	}
+	if req.Status >= 0 {
+		brand.Status = req.Status
+	}
+	brand.UpdatedAt = time.Now()
+
+	if !renamed || l.db == nil || l.outboxRepo == nil {
+		if err := l.brandRepo.Update(ctx, brand); err != nil {
+			return nil, apperrors.NewInternalError("更新品牌失败: " + err.Error())
+		}
+		if renamed && l.productRepo != nil {
+			productIDs, _ := l.productRepo.ListIDsByBrand(ctx, brand.ID)
+			l.clearProductCaches(ctx, productIDs)
+		}
+		return brand, nil
+	}
+
+	var productIDs []uint64
+	if err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewBrandRepository(tx).Update(ctx, brand); err != nil {
+			return apperrors.NewInternalError("更新品牌失败: " + err.Error())
+		}
+		productIDs, err = repository.NewProductRepository(tx).ListIDsByBrand(ctx, brand.ID)
+		if err != nil {
+			return apperrors.NewInternalError("查询品牌商品失败: " + err.Error())
+		}
+		for _, id := range productIDs {
+			payloadBytes, _ := json.Marshal(map[string]any{"product_id": id})
+			payload := string(payloadBytes)
+			evt := &outbox.Event{
+				AggregateType: "product",
+				AggregateID:   fmt.Sprintf("%d", id),
+				EventType:     outbox.EventProductUpserted,
+				Payload:       &payload,
+				Status:        outbox.StatusPending,
+			}
+			if err := l.outboxRepo.CreateInTx(ctx, tx, evt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	l.clearProductCaches(ctx, productIDs)
+	return brand, nil
+}
+
+// DeleteBrand 删除品牌（管理后台），品牌下还有商品时不允许删除
+func (l *ProductLogic) DeleteBrand(ctx context.Context, id uint64) error {
+	brand, err := l.GetBrand(ctx, id)
+	if err != nil {
+		return err
+	}
+	if l.productRepo != nil {
+		productIDs, err := l.productRepo.ListIDsByBrand(ctx, brand.ID)
+		if err != nil {
+			return apperrors.NewInternalError("查询品牌商品失败: " + err.Error())
+		}
+		if len(productIDs) > 0 {
+			return apperrors.NewInvalidParamError(fmt.Sprintf("品牌下还有 %d 个商品，请先调整商品品牌", len(productIDs)))
+		}
+	}
+	if err := l.brandRepo.Delete(ctx, brand.ID); err != nil {
+		return apperrors.NewInternalError("删除品牌失败: " + err.Error())
+	}
+	return nil
+}
+
+// checkBrandName 校验品牌名称非空且不与其他品牌重复
+func (l *ProductLogic) checkBrandName(ctx context.Context, selfID uint64, name string) error {
+	if name == "" {
+		return apperrors.NewInvalidParamError("品牌名称不能为空")
+	}
+	if len([]rune(name)) > 100 {
+		return apperrors.NewInvalidParamError("品牌名称不能超过100个字符")
+	}
+	existing, err := l.brandRepo.GetByName(ctx, name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.NewInternalError("查询品牌失败: " + err.Error())
+	}
+	if existing != nil && existing.ID != selfID {
+		return apperrors.NewInvalidParamError("品牌名称已存在")
+	}
+	return nil
+}
+
+// resolveBrandLogo 得到 Logo URL：传了文件ID时向文件服务查询，只接受公开文件（私有文件的签名 URL 会过期）
+func (l *ProductLogic) resolveBrandLogo(ctx context.Context, logo, fileID string) (string, error) {
+	if fileID == "" {
+		return strings.TrimSpace(logo), nil
+	}
+	if l.logoStore == nil {
+		return "", apperrors.NewInvalidParamError("未配置文件服务，请直接填写Logo地址")
+	}
+	url, expiresAt, err := l.logoStore.GetFileURL(ctx, fileID, 0, 0)
+	if err != nil {
+		return "", apperrors.NewInvalidParamError("Logo文件不存在或不可用")
+	}
+	if expiresAt != 0 {
+		return "", apperrors.NewInvalidParamError("Logo必须上传到公开分类")
+	}
+	return url, nil
+}
+
+// checkProductBrand 校验商品引用的品牌存在且已启用
+func (l *ProductLogic) checkProductBrand(ctx context.Context, brandID *uint64) error {
+	if brandID == nil || *brandID == 0 || l.brandRepo == nil {
+		return nil
+	}
+	brand, err := l.brandRepo.GetByID(ctx, *brandID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewInvalidParamError("品牌不存在")
+		}
+		return apperrors.NewInternalError("查询品牌失败: " + err.Error())
+	}
+	if brand.Status != 1 {
+		return apperrors.NewInvalidParamError("品牌已禁用")
+	}
+	return nil
+}
+
+// fillBrandNames 批量补充商品的品牌名称，查询失败时保持为空
+func (l *ProductLogic) fillBrandNames(ctx context.Context, products ...*model.Product) {
+	if l.brandRepo == nil {
+		return
+	}
+	ids := make([]uint64, 0, len(products))
+	for _, p := range products {
+		if p != nil && p.BrandID != nil && *p.BrandID > 0 {
+			ids = append(ids, *p.BrandID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	brands, err := l.brandRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return
+	}
+	for _, p := range products {
+		if p == nil || p.BrandID == nil {
+			continue
+		}
+		if b, ok := brands[*p.BrandID]; ok {
+			p.BrandName = b.Name
+		}
+	}
+}
+
+// clearProductCaches 清除商品详情和列表缓存
+func (l *ProductLogic) clearProductCaches(ctx context.Context, productIDs []uint64) {
+	if l.cache == nil {
+		return
+	}
+	for _, id := range productIDs {
+		_ = l.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixProductDetail, id))
+	}
+	_ = l.cache.DeletePattern(ctx, cache.KeyPrefixProductList+"*")
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/service/product/model"
+)
+
+// fakeLogoStore 按文件ID返回 URL，私有文件带过期时间
+type fakeLogoStore struct {
+	urls    map[string]string
+	private map[string]bool
+}
+
+func (s *fakeLogoStore) GetFileURL(ctx context.Context, fileID string, expireSeconds int64, userID uint64) (string, int64, error) {
+	url, ok := s.urls[fileID]
+	if !ok {
+		return "", 0, errors.New("file not found")
+	}
+	if s.private[fileID] {
+		return url + "?sig=x", 1700000000, nil
+	}
+	return url, 0, nil
+}
+
+func TestBrandCRUD(t *testing.T) {
+	env := newProductTestEnv()
+	logic, brands := env.logic, env.brands
+	logic.logoStore = &fakeLogoStore{
+		urls:    map[string]string{"pub": "/uploads/brand/pub.png", "priv": "/uploads/invoice/priv.png"},
+		private: map[string]bool{"priv": true},
+	}
+	ctx := context.Background()
+
+	created, err := logic.CreateBrand(ctx, &CreateBrandRequest{Name: "  Acme  ", LogoFileID: "pub", Status: 1})
+	if err != nil {
+		t.Fatalf("CreateBrand: %v", err)
+	}
+	if created.ID == 0 || created.Name != "Acme" || created.Logo != "/uploads/brand/pub.png" {
+		t.Fatalf("unexpected brand: %+v", created)
+	}
+
+	// 名称为空、重名、Logo 为私有文件都拒绝
+	for _, req := range []*CreateBrandRequest{
+		{Name: "   "},
+		{Name: "Acme"},
+		{Name: "Other", LogoFileID: "priv"},
+		{Name: "Other", LogoFileID: "missing"},
+	} {
+		_, err := logic.CreateBrand(ctx, req)
+		assertBizCode(t, err, apperrors.CodeInvalidParam)
+	}
+	if len(brands.rows) != 1 {
+		t.Fatalf("rejected requests created brands: %d", len(brands.rows))
+	}
+
+	got, err := logic.GetBrand(ctx, created.ID)
+	if err != nil || got.Name != "Acme" {
+		t.Fatalf("GetBrand = %+v, %v", got, err)
+	}
+	_, err = logic.GetBrand(ctx, 999)
+	assertBizCode(t, err, apperrors.CodeNotFound)
+	_, err = logic.GetBrand(ctx, 0)
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+
+	// 更新：Status -1 表示不修改，改成另一个品牌的名称被拒绝
+	other, err := logic.CreateBrand(ctx, &CreateBrandRequest{Name: "Globex", Status: 1})
+	if err != nil {
+		t.Fatalf("CreateBrand: %v", err)
+	}
+	updated, err := logic.UpdateBrand(ctx, &UpdateBrandRequest{ID: created.ID, Name: "Acme Corp", Description: "desc", Status: -1})
+	if err != nil {
+		t.Fatalf("UpdateBrand: %v", err)
+	}
+	if updated.Name != "Acme Corp" || updated.Description != "desc" || updated.Status != 1 {
+		t.Fatalf("unexpected updated brand: %+v", updated)
+	}
+	_, err = logic.UpdateBrand(ctx, &UpdateBrandRequest{ID: other.ID, Name: "Acme Corp", Status: -1})
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+	if brands.rows[other.ID].Name != "Globex" {
+		t.Fatalf("rejected rename was saved: %+v", brands.rows[other.ID])
+	}
+
+	if _, err := logic.UpdateBrand(ctx, &UpdateBrandRequest{ID: other.ID, Status: 0}); err != nil {
+		t.Fatalf("UpdateBrand: %v", err)
+	}
+	all, err := logic.ListBrands(ctx, &ListBrandsRequest{Status: -1})
+	if err != nil || all.Total != 2 {
+		t.Fatalf("ListBrands(all) = %+v, %v", all, err)
+	}
+	enabled, err := logic.ListBrands(ctx, &ListBrandsRequest{Status: 1})
+	if err != nil || enabled.Total != 1 || enabled.Brands[0].Name != "Acme Corp" {
+		t.Fatalf("ListBrands(enabled) = %+v, %v", enabled, err)
+	}
+}
+
+func TestDeleteBrandWithProducts(t *testing.T) {
+	env := newProductTestEnv()
+	env.brands.seed(&model.Brand{ID: 1, Name: "Acme", Status: 1}, &model.Brand{ID: 2, Name: "Globex", Status: 1})
+	env.products.seed(
+		&model.Product{ID: 10, Name: "Rocket", BrandID: uint64Ptr(1)},
+		&model.Product{ID: 11, Name: "Anvil", BrandID: uint64Ptr(1)},
+	)
+	logic, brands, products := env.logic, env.brands, env.products
+	ctx := context.Background()
+
+	err := logic.DeleteBrand(ctx, 1)
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+	if _, ok := brands.rows[1]; !ok {
+		t.Fatal("brand with products was deleted")
+	}
+
+	// 商品改到其他品牌后可以删除
+	for _, p := range products.rows {
+		p.BrandID = uint64Ptr(2)
+	}
+	if err := logic.DeleteBrand(ctx, 1); err != nil {
+		t.Fatalf("DeleteBrand: %v", err)
+	}
+	if _, ok := brands.rows[1]; ok {
+		t.Fatal("brand still present after delete")
+	}
+	assertBizCode(t, logic.DeleteBrand(ctx, 1), apperrors.CodeNotFound)
+}
+
+func TestCheckProductBrand(t *testing.T) {
+	env := newProductTestEnv()
+	env.brands.seed(&model.Brand{ID: 1, Name: "Acme", Status: 1}, &model.Brand{ID: 2, Name: "Old", Status: 0})
+	logic := env.logic
+	ctx := context.Background()
+
+	for _, brandID := range []*uint64{nil, uint64Ptr(0), uint64Ptr(1)} {
+		if err := logic.checkProductBrand(ctx, brandID); err != nil {
+			t.Fatalf("checkProductBrand(%v): %v", brandID, err)
+		}
+	}
+	assertBizCode(t, logic.checkProductBrand(ctx, uint64Ptr(2)), apperrors.CodeInvalidParam)
+	assertBizCode(t, logic.checkProductBrand(ctx, uint64Ptr(3)), apperrors.CodeInvalidParam)
+}
+
+func TestListProductsBrandFilter(t *testing.T) {
+	env := newProductTestEnv()
+	env.brands.seed(&model.Brand{ID: 1, Name: "Acme", Status: 1}, &model.Brand{ID: 2, Name: "Globex", Status: 1})
+	env.products.seed(
+		&model.Product{ID: 10, Name: "Rocket", BrandID: uint64Ptr(1)},
+		&model.Product{ID: 11, Name: "Laser", BrandID: uint64Ptr(2)},
+		&model.Product{ID: 12, Name: "Rope"},
+	)
+	logic, products := env.logic, env.products
+	ctx := context.Background()
+
+	resp, err := logic.ListProducts(ctx, &ListProductsRequest{BrandID: 2})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if products.lastList.BrandID != 2 {
+		t.Fatalf("brand filter not passed to repository: %+v", products.lastList)
+	}
+	if resp.Total != 1 || len(resp.Products) != 1 || resp.Products[0].ID != 11 || resp.Products[0].BrandName != "Globex" {
+		t.Fatalf("unexpected brand-filtered list: %+v", resp.Products)
+	}
+
+	// 不筛选时返回全部，有品牌的商品补充品牌名称
+	resp, err = logic.ListProducts(ctx, &ListProductsRequest{})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	names := map[uint64]string{}
+	for _, p := range resp.Products {
+		names[p.ID] = p.BrandName
+	}
+	if len(names) != 3 || names[10] != "Acme" || names[11] != "Globex" || names[12] != "" {
+		t.Fatalf("unexpected brand names: %v", names)
+	}
+
+	// 品牌改名后列表里的名称随之变化
+	if _, err := logic.UpdateBrand(ctx, &UpdateBrandRequest{ID: 1, Name: "Acme Corp", Status: -1}); err != nil {
+		t.Fatalf("UpdateBrand: %v", err)
+	}
+	resp, err = logic.ListProducts(ctx, &ListProductsRequest{BrandID: 1})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if len(resp.Products) != 1 || resp.Products[0].BrandName != "Acme Corp" {
+		t.Fatalf("unexpected list after rename: %+v", resp.Products)
+	}
+}
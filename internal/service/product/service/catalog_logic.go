@@ -0,0 +1,1210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ecommerce-system/internal/pkg/cache"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/pkg/xlsx"
+	"ecommerce-system/internal/service/product/model"
+	"ecommerce-system/internal/service/product/repository"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// catalogColumns 批量导入导出文件的列，每行一个 SKU；同一商品的多个 SKU 用相同的 spu_code 关联，
+// 商品字段只需在其中一行填写。导出的文件修改后可以直接重新导入
+var catalogColumns = []string{
+	"spu_code", "name", "subtitle", "category_path", "brand", "main_image", "images", "detail", "attrs",
+	"sku_code", "sku_name", "specs", "price", "original_price", "stock", "sku_image", "sku_status",
+}
+
+// catalogProductColumns 属于商品（SPU）的列，同一商品的各行填写时必须一致
+var catalogProductColumns = catalogColumns[1:9]
+
+// catalogErrorColumn 错误报告在原有列之后追加的错误原因列，重新导入时忽略
+const catalogErrorColumn = "error"
+
+const (
+	// catalogJobLockTTL 单个任务处理锁的有效期，覆盖最慢的一次完整处理
+	catalogJobLockTTL = time.Hour
+	// catalogMaxErrors 任务详情中保留的逐行错误条数，完整的失败行见错误报告文件
+	catalogMaxErrors = 1000
+	// catalogExportPageSize 导出时每次查询的商品数
+	catalogExportPageSize = 200
+)
+
+// errCatalogDryRun 试运行时回滚批次事务
+var errCatalogDryRun = errors.New("catalog dry run")
+
+// CatalogStore 批量导入导出文件的存储，由文件服务客户端实现
+type CatalogStore interface {
+	Download(ctx context.Context, fileID string, maxSize int64) ([]byte, string, error)
+	Upload(ctx context.Context, data []byte, fileName, fileType, category string) (string, error)
+	GetFileURL(ctx context.Context, fileID string, expireSeconds int64, userID uint64) (string, int64, error)
+}
+
+// CatalogPolicy 批量导入导出策略
+type CatalogPolicy struct {
+	FileCategory string        // 导出文件和错误报告的文件分类，文件服务和网关需配置为私有分类
+	BatchSize    int           // 每个事务处理的商品数
+	MaxRows      int           // 单个导入文件的最大数据行数
+	MaxFileSize  int64         // 单个导入文件的最大字节数
+	DownloadTTL  time.Duration // 下载地址有效期
+}
+
+// CatalogJobParams 任务参数
+type CatalogJobParams struct {
+	Submit     bool   `json:"submit,omitempty"`      // 导入：新建或修改的商品内容直接提交审核
+	CategoryID uint64 `json:"category_id,omitempty"` // 导出：类目，包含子类目
+	BrandID    uint64 `json:"brand_id,omitempty"`    // 导出：品牌
+	Status     int8   `json:"status"`                // 导出：商品状态，-1 表示全部
+	Keyword    string `json:"keyword,omitempty"`     // 导出：名称关键词
+}
+
+// CatalogRowError 导入的逐行错误，Row 为文件中的行号（表头为第 1 行）
+type CatalogRowError struct {
+	Row     int    `json:"row"`
+	SpuCode string `json:"spu_code"`
+	SkuCode string `json:"sku_code"`
+	Message string `json:"message"`
+}
+
+// CatalogJobDetail 任务详情
+type CatalogJobDetail struct {
+	*model.CatalogJob
+	RowErrors []*CatalogRowError
+	ResultURL string // 导出文件或错误报告的下载地址，没有时为空
+	ExpiresAt int64  // 下载地址过期时间（Unix 秒）
+}
+
+// ImportCatalogRequest 导入商品请求
+type ImportCatalogRequest struct {
+	FileID string // 已上传到文件服务的 CSV/XLSX 文件
+	Format string // csv / xlsx，为空时按文件扩展名判断
+	DryRun bool   // 只校验不写入
+	Submit bool   // 新建或修改的商品内容直接提交审核
+}
+
+// ImportCatalog 创建商品导入任务（管理后台），后台异步处理，通过 GetCatalogJob 查询进度和逐行错误
+func (l *ProductLogic) ImportCatalog(ctx context.Context, req *ImportCatalogRequest) (*CatalogJobDetail, error) {
+	if l.catalogJobRepo == nil || l.tx == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if l.catalogStore == nil {
+		return nil, apperrors.NewInvalidParamError("未配置文件服务，不能导入")
+	}
+	if req.FileID == "" {
+		return nil, apperrors.NewInvalidParamError("文件ID不能为空")
+	}
+	if req.Format != "" && req.Format != model.CatalogFormatCSV && req.Format != model.CatalogFormatXLSX {
+		return nil, apperrors.NewInvalidParamError("文件格式只能是 csv 或 xlsx")
+	}
+	return l.createCatalogJob(ctx, &model.CatalogJob{
+		Kind:   model.CatalogJobImport,
+		Format: req.Format,
+		FileID: req.FileID,
+		DryRun: req.DryRun,
+	}, &CatalogJobParams{Submit: req.Submit, Status: -1})
+}
+
+// ExportCatalogRequest 导出商品请求
+type ExportCatalogRequest struct {
+	Format     string // csv / xlsx，为空时为 csv
+	CategoryID uint64 // 0 表示全部类目
+	BrandID    uint64 // 0 表示全部品牌
+	Status     int8   // -1 表示全部状态
+	Keyword    string
+}
+
+// ExportCatalog 创建商品导出任务（管理后台），导出文件与导入格式相同
+func (l *ProductLogic) ExportCatalog(ctx context.Context, req *ExportCatalogRequest) (*CatalogJobDetail, error) {
+	if l.catalogJobRepo == nil || l.productRepo == nil || l.skuRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if l.catalogStore == nil {
+		return nil, apperrors.NewInvalidParamError("未配置文件服务，不能导出")
+	}
+	format := req.Format
+	if format == "" {
+		format = model.CatalogFormatCSV
+	}
+	if format != model.CatalogFormatCSV && format != model.CatalogFormatXLSX {
+		return nil, apperrors.NewInvalidParamError("文件格式只能是 csv 或 xlsx")
+	}
+	return l.createCatalogJob(ctx, &model.CatalogJob{
+		Kind:   model.CatalogJobExport,
+		Format: format,
+	}, &CatalogJobParams{
+		CategoryID: req.CategoryID,
+		BrandID:    req.BrandID,
+		Status:     req.Status,
+		Keyword:    strings.TrimSpace(req.Keyword),
+	})
+}
+
+// createCatalogJob 保存任务并在后台开始处理
+func (l *ProductLogic) createCatalogJob(ctx context.Context, job *model.CatalogJob, params *CatalogJobParams) (*CatalogJobDetail, error) {
+	b, _ := json.Marshal(params)
+	operatorID, _ := utils.GetUserID(ctx)
+	now := time.Now()
+	job.Params = string(b)
+	job.Errors = "[]"
+	job.Status = model.CatalogJobPending
+	job.CreatedBy = operatorID
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	if err := l.catalogJobRepo.Create(ctx, job); err != nil {
+		return nil, apperrors.NewInternalError("创建任务失败: " + err.Error())
+	}
+	// 在后台处理，不受发起请求的 ctx 取消影响
+	go l.processCatalogJob(context.Background(), job.ID)
+	return &CatalogJobDetail{CatalogJob: job, RowErrors: []*CatalogRowError{}}, nil
+}
+
+// GetCatalogJob 获取任务进度和结果，导出文件和错误报告返回绑定当前用户的下载地址
+func (l *ProductLogic) GetCatalogJob(ctx context.Context, id uint64) (*CatalogJobDetail, error) {
+	if l.catalogJobRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if id == 0 {
+		return nil, apperrors.NewInvalidParamError("任务ID不能为空")
+	}
+	job, err := l.catalogJobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("任务不存在")
+		}
+		return nil, apperrors.NewInternalError("查询任务失败: " + err.Error())
+	}
+
+	detail := &CatalogJobDetail{CatalogJob: job, RowErrors: []*CatalogRowError{}}
+	if job.Errors != "" {
+		if err := json.Unmarshal([]byte(job.Errors), &detail.RowErrors); err != nil {
+			return nil, apperrors.NewInternalError("任务错误格式错误: " + err.Error())
+		}
+	}
+	if job.ResultFileID != "" && l.catalogStore != nil {
+		operatorID, _ := utils.GetUserID(ctx)
+		detail.ResultURL, detail.ExpiresAt, err = l.catalogStore.GetFileURL(ctx, job.ResultFileID,
+			int64(l.catalogPolicy.DownloadTTL/time.Second), operatorID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("获取下载地址失败: " + err.Error())
+		}
+	}
+	return detail, nil
+}
+
+// ListCatalogJobs 分页获取任务列表，kind 为空表示全部类型
+func (l *ProductLogic) ListCatalogJobs(ctx context.Context, kind string, page, pageSize int) ([]*model.CatalogJob, int64, error) {
+	if l.catalogJobRepo == nil {
+		return nil, 0, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if kind != "" && kind != model.CatalogJobImport && kind != model.CatalogJobExport {
+		return nil, 0, apperrors.NewInvalidParamError("任务类型只能是 import 或 export")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	jobs, total, err := l.catalogJobRepo.List(ctx, kind, page, pageSize)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("查询任务列表失败: " + err.Error())
+	}
+	return jobs, total, nil
+}
+
+// ResumeCatalogJobs 服务启动时继续处理重启前未完成的任务。导入按编码覆盖写入，从头重新处理是安全的
+func (l *ProductLogic) ResumeCatalogJobs(ctx context.Context) {
+	if l.catalogJobRepo == nil || l.catalogStore == nil {
+		return
+	}
+	jobs, err := l.catalogJobRepo.ListUnfinished(ctx, 100)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询未完成的商品批量任务失败: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		l.processCatalogJob(ctx, job.ID)
+	}
+}
+
+// processCatalogJob 处理任务：同一任务同时只有一个处理者，处理过程中按批次更新进度
+func (l *ProductLogic) processCatalogJob(ctx context.Context, id uint64) {
+	if l.cache != nil {
+		// 释放时校验持有者：处理超时后锁可能已被其他实例取得，不能删除别人的锁
+		lock := cache.NewDistributedLock(l.cache.GetClient(), cache.BuildKey(cache.KeyPrefixLock, "catalog", id), catalogJobLockTTL)
+		ok, err := lock.Lock(ctx)
+		if err != nil || !ok {
+			return
+		}
+		defer lock.Unlock(context.Background())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, catalogJobLockTTL)
+	defer cancel()
+	log := logx.WithContext(ctx)
+
+	job, err := l.catalogJobRepo.GetByID(ctx, id)
+	if err != nil {
+		log.Errorf("查询商品批量任务失败: id=%d err=%v", id, err)
+		return
+	}
+	if job.Status != model.CatalogJobPending && job.Status != model.CatalogJobRunning {
+		return
+	}
+	var params CatalogJobParams
+	if job.Params != "" {
+		_ = json.Unmarshal([]byte(job.Params), &params)
+	}
+	// 修订和审核日志记录发起任务的管理员
+	ctx = utils.WithUserID(ctx, job.CreatedBy)
+
+	now := time.Now()
+	job.Status = model.CatalogJobRunning
+	job.StartedAt = &now
+	job.FinishedAt = nil
+	job.TotalRows, job.ProcessedRows, job.SuccessRows, job.FailedRows = 0, 0, 0, 0
+	job.Errors = "[]"
+	job.ResultFileID = ""
+	job.Message = ""
+	l.saveCatalogJob(ctx, job)
+
+	if job.Kind == model.CatalogJobImport {
+		err = l.runCatalogImport(ctx, job, &params)
+	} else {
+		err = l.runCatalogExport(ctx, job, &params)
+	}
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.Status = model.CatalogJobSucceeded
+	if err != nil {
+		log.Errorf("商品批量任务失败: id=%d kind=%s err=%v", job.ID, job.Kind, err)
+		job.Status = model.CatalogJobFailed
+		job.Message = truncateRunes(catalogErrorMessage(err), 500)
+	}
+	l.saveCatalogJob(ctx, job)
+}
+
+// saveCatalogJob 保存任务进度，失败只记录日志，不中断处理
+func (l *ProductLogic) saveCatalogJob(ctx context.Context, job *model.CatalogJob) {
+	job.UpdatedAt = time.Now()
+	if err := l.catalogJobRepo.Update(ctx, job); err != nil {
+		logx.WithContext(ctx).Errorf("保存商品批量任务失败: id=%d err=%v", job.ID, err)
+	}
+}
+
+// catalogRow 导入文件中的一行
+type catalogRow struct {
+	Line          int      // 文件中的行号
+	Cells         []string // 按 catalogColumns 排列的原始值，用于生成错误报告
+	SpuCode       string
+	SkuCode       string
+	SkuName       string
+	Specs         map[string]string
+	Price         float64
+	OriginalPrice *float64
+	Stock         int
+	SkuImage      string
+	SkuStatus     int8
+	Err           string // 行本身的格式错误
+}
+
+// cell 取指定列的值
+func (r *catalogRow) cell(column string) string {
+	for i, c := range catalogColumns {
+		if c == column {
+			return r.Cells[i]
+		}
+	}
+	return ""
+}
+
+// catalogGroup 同一 spu_code 的行，作为一个整体写入：任一行失败时整组都不写入
+type catalogGroup struct {
+	SpuCode string
+	Rows    []*catalogRow
+	Product *catalogRow // 提供商品字段的行
+	ErrLine int         // 出错的行号，0 表示整组的错误
+	Err     string
+}
+
+// fail 记录整组失败的原因，只保留第一个
+func (g *catalogGroup) fail(line int, msg string) {
+	if g.Err == "" {
+		g.ErrLine, g.Err = line, msg
+	}
+}
+
+// catalogRefs 导入时按名称解析的类目和品牌，整个任务共用
+type catalogRefs struct {
+	categories map[string][]*model.Category // 类目路径 -> 类目（同名路径可能有多个）
+	brands     map[string]*model.Brand
+}
+
+// runCatalogImport 下载并解析导入文件，按商品分组后分批写入，最后为失败的行生成错误报告
+func (l *ProductLogic) runCatalogImport(ctx context.Context, job *model.CatalogJob, params *CatalogJobParams) error {
+	data, fileName, err := l.catalogStore.Download(ctx, job.FileID, l.catalogPolicy.MaxFileSize)
+	if err != nil {
+		return fmt.Errorf("下载导入文件失败: %w", err)
+	}
+	if job.Format == "" {
+		job.Format = detectCatalogFormat(fileName, data)
+	}
+	records, err := readCatalogRecords(job.Format, data, l.catalogPolicy.MaxRows)
+	if err != nil {
+		return err
+	}
+	rows, err := parseCatalogRows(records)
+	if err != nil {
+		return err
+	}
+	if l.catalogPolicy.MaxRows > 0 && len(rows) > l.catalogPolicy.MaxRows {
+		return apperrors.NewInvalidParamError(fmt.Sprintf("文件数据行数 %d 超过上限 %d", len(rows), l.catalogPolicy.MaxRows))
+	}
+	job.TotalRows = len(rows)
+	l.saveCatalogJob(ctx, job)
+
+	refs, err := l.loadCatalogRefs(ctx)
+	if err != nil {
+		return err
+	}
+	groups := groupCatalogRows(rows)
+
+	batchSize := l.catalogPolicy.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	var rowErrors []*CatalogRowError
+	var failedRows []*catalogRow
+	for start := 0; start < len(groups); start += batchSize {
+		batch := groups[start:min(start+batchSize, len(groups))]
+		l.importCatalogBatch(ctx, batch, refs, job.DryRun, params.Submit)
+
+		for _, g := range batch {
+			job.ProcessedRows += len(g.Rows)
+			if g.Err == "" {
+				job.SuccessRows += len(g.Rows)
+				continue
+			}
+			job.FailedRows += len(g.Rows)
+			for _, r := range g.Rows {
+				e := &CatalogRowError{Row: r.Line, SpuCode: r.SpuCode, SkuCode: r.SkuCode, Message: g.Err}
+				if g.ErrLine != 0 && r.Line != g.ErrLine {
+					e.Message = fmt.Sprintf("同一商品的第 %d 行有错误，本行未导入", g.ErrLine)
+				}
+				if len(rowErrors) < catalogMaxErrors {
+					rowErrors = append(rowErrors, e)
+				}
+				failedRows = append(failedRows, r)
+				r.Err = e.Message
+			}
+		}
+		b, _ := json.Marshal(rowErrors)
+		job.Errors = string(b)
+		l.saveCatalogJob(ctx, job)
+	}
+
+	if len(failedRows) > 0 {
+		report := make([][]string, 0, len(failedRows)+1)
+		report = append(report, append(append([]string{}, catalogColumns...), catalogErrorColumn))
+		for _, r := range failedRows {
+			report = append(report, append(append([]string{}, r.Cells...), r.Err))
+		}
+		name := fmt.Sprintf("catalog-import-%d-errors", job.ID)
+		if job.ResultFileID, err = l.uploadCatalogFile(ctx, job.Format, name, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importCatalogBatch 在一个事务内写入一批商品，每个商品使用独立的保存点，失败的商品回滚后不影响同批其他商品。
+// 试运行时执行相同的写入再整体回滚，能发现 SKU 编码冲突、规格重复等只有写库时才能发现的问题
+func (l *ProductLogic) importCatalogBatch(ctx context.Context, batch []*catalogGroup, refs *catalogRefs, dryRun, submit bool) {
+	var productIDs, skuIDs []uint64
+	err := l.tx.Transaction(ctx, func(r *repository.TxRepos) error {
+		for _, g := range batch {
+			if g.Err != "" {
+				continue
+			}
+			var ids []uint64
+			err := r.Transaction(ctx, func(sp *repository.TxRepos) error {
+				productID, written, err := l.importCatalogGroup(ctx, sp, g, refs, submit)
+				if err != nil {
+					return err
+				}
+				productIDs = append(productIDs, productID)
+				ids = written
+				return nil
+			})
+			if err != nil {
+				var rowErr *catalogRowErr
+				if errors.As(err, &rowErr) {
+					g.fail(rowErr.line, catalogErrorMessage(rowErr.err))
+				} else {
+					g.fail(0, catalogErrorMessage(err))
+				}
+				continue
+			}
+			skuIDs = append(skuIDs, ids...)
+		}
+		if dryRun {
+			return errCatalogDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errCatalogDryRun) {
+		// 提交失败时整批都没有写入
+		for _, g := range batch {
+			g.fail(0, "写入失败: "+err.Error())
+		}
+		return
+	}
+	if dryRun {
+		return
+	}
+
+	if l.cache != nil {
+		for _, id := range skuIDs {
+			_ = l.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixSkuInfo, id))
+		}
+	}
+	l.clearProductCaches(ctx, productIDs)
+}
+
+// catalogRowErr 定位到具体行的错误
+type catalogRowErr struct {
+	line int
+	err  error
+}
+
+func (e *catalogRowErr) Error() string { return e.err.Error() }
+
+// importCatalogGroup 写入一个商品及其 SKU（在事务内调用），返回商品ID和写入的 SKU ID。
+// 新商品与 CreateProduct 一样为待审核状态；已有商品的内容修改写入修订，审核通过后生效；
+// SKU 和库存属于运营数据，直接写入
+func (l *ProductLogic) importCatalogGroup(ctx context.Context, r *repository.TxRepos, g *catalogGroup, refs *catalogRefs, submit bool) (uint64, []uint64, error) {
+	p := g.Product
+	rowErr := func(err error) error { return &catalogRowErr{line: p.Line, err: err} }
+
+	var categoryID uint64
+	if path := p.cell("category_path"); path != "" {
+		c, err := refs.category(path)
+		if err != nil {
+			return 0, nil, rowErr(err)
+		}
+		categoryID = c.ID
+	}
+	var brandID *uint64
+	if name := p.cell("brand"); name != "" {
+		b, err := l.catalogBrand(ctx, refs, name)
+		if err != nil {
+			return 0, nil, rowErr(err)
+		}
+		brandID = &b.ID
+	}
+	attrs, err := parseCatalogKV(p.cell("attrs"))
+	if err != nil {
+		return 0, nil, rowErr(err)
+	}
+	var images []string
+	for _, s := range strings.Split(p.cell("images"), "|") {
+		if s = strings.TrimSpace(s); s != "" {
+			images = append(images, s)
+		}
+	}
+
+	// 商品价格取最低的 SKU 价格，库存为 SKU 库存之和；没有 SKU 的商品取商品行自身的价格和库存，未填写时保持原值
+	var skus []*catalogRow
+	price, originalPrice, stock := p.Price, p.OriginalPrice, p.Stock
+	stockSet := p.cell("stock") != ""
+	for _, row := range g.Rows {
+		if row.SkuCode == "" {
+			continue
+		}
+		if len(skus) == 0 || row.Price < price {
+			price, originalPrice = row.Price, row.OriginalPrice
+		}
+		if len(skus) == 0 {
+			stock = 0
+		}
+		stock += row.Stock
+		skus = append(skus, row)
+	}
+
+	product, err := r.Product.GetBySpuCode(ctx, g.SpuCode)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil, apperrors.NewInternalError("查询商品失败: " + err.Error())
+	}
+	if product == nil {
+		if p.cell("name") == "" {
+			return 0, nil, rowErr(apperrors.NewInvalidParamError("新商品的名称不能为空"))
+		}
+		if categoryID == 0 {
+			return 0, nil, rowErr(apperrors.NewInvalidParamError("新商品的类目不能为空"))
+		}
+		if price <= 0 {
+			return 0, nil, rowErr(apperrors.NewInvalidParamError("价格必须大于0"))
+		}
+		if err := l.checkProductBrand(ctx, brandID); err != nil {
+			return 0, nil, rowErr(err)
+		}
+		attrsJSON, err := l.normalizeProductAttrs(ctx, categoryID, attrs)
+		if err != nil {
+			return 0, nil, rowErr(err)
+		}
+		imagesJSON := "[]"
+		if len(images) > 0 {
+			b, _ := json.Marshal(images)
+			imagesJSON = string(b)
+		}
+		now := time.Now()
+		product = &model.Product{
+			SpuCode:       g.SpuCode,
+			Name:          p.cell("name"),
+			Subtitle:      p.cell("subtitle"),
+			CategoryID:    categoryID,
+			BrandID:       brandID,
+			MainImage:     p.cell("main_image"),
+			Images:        imagesJSON,
+			LocalImages:   "[]",
+			Detail:        p.cell("detail"),
+			Price:         price,
+			OriginalPrice: originalPrice,
+			Stock:         stock,
+			Status:        model.ProductStatusPending,
+			AuditStatus:   model.RevisionStatusDraft,
+			Attrs:         attrsJSON,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if submit {
+			product.AuditStatus = model.RevisionStatusSubmitted
+		}
+		if err := r.Product.Create(ctx, product); err != nil {
+			if strings.Contains(err.Error(), "Duplicate entry") {
+				return 0, nil, rowErr(apperrors.NewAlreadyExistsError("SPU编码已被已删除的商品占用"))
+			}
+			return 0, nil, apperrors.NewInternalError("创建商品失败: " + err.Error())
+		}
+		if _, err := l.createInitialRevision(ctx, r, product, submit); err != nil {
+			return 0, nil, err
+		}
+	} else {
+		update := &UpdateProductRequest{
+			ID:         product.ID,
+			Name:       p.cell("name"),
+			Subtitle:   p.cell("subtitle"),
+			CategoryID: categoryID,
+			BrandID:    brandID,
+			MainImage:  p.cell("main_image"),
+			Images:     images,
+			Detail:     p.cell("detail"),
+			Price:      price,
+			Submit:     submit,
+		}
+		if originalPrice != nil {
+			update.OriginalPrice = *originalPrice
+		}
+		if len(attrs) > 0 {
+			update.Attrs = attrs
+		}
+		if _, err := l.stageProductRevision(ctx, r, product, update); err != nil {
+			return 0, nil, rowErr(err)
+		}
+		if len(skus) > 0 || stockSet {
+			product.Stock = stock
+		}
+		product.UpdatedAt = time.Now()
+		if err := r.Product.Update(ctx, product); err != nil {
+			return 0, nil, apperrors.NewInternalError("更新商品失败: " + err.Error())
+		}
+	}
+
+	skuIDs := make([]uint64, 0, len(skus))
+	for _, row := range skus {
+		id, err := l.upsertCatalogSku(ctx, r.Sku, product, row)
+		if err != nil {
+			return 0, nil, &catalogRowErr{line: row.Line, err: err}
+		}
+		skuIDs = append(skuIDs, id)
+	}
+
+	// 未通过审核的商品不写 outbox 事件，审核通过时再通知搜索服务建索引
+	if product.Status != model.ProductStatusPending {
+		if err := l.writeOutboxEvents(ctx, r, productUpsertedEvent(product.ID)); err != nil {
+			return 0, nil, apperrors.NewInternalError("写入事件失败: " + err.Error())
+		}
+	}
+	return product.ID, skuIDs, nil
+}
+
+// upsertCatalogSku 按 SKU 编码新建、恢复（已删除）或更新 SKU（在事务内调用），重量和体积不在导入格式中，保持原值
+func (l *ProductLogic) upsertCatalogSku(ctx context.Context, skuRepo repository.SkuRepository, product *model.Product, r *catalogRow) (uint64, error) {
+	existing, err := skuRepo.GetBySkuCodeUnscoped(ctx, r.SkuCode)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, apperrors.NewInternalError("查询SKU失败: " + err.Error())
+	}
+	if existing != nil && !existing.DeletedAt.Valid && existing.ProductID != product.ID {
+		return 0, apperrors.NewAlreadyExistsError("SKU编码已被其他商品使用")
+	}
+	var selfID uint64
+	if existing != nil && !existing.DeletedAt.Valid {
+		selfID = existing.ID
+	}
+	if err := l.checkSkuSpecs(ctx, skuRepo, product, r.Specs, selfID); err != nil {
+		return 0, err
+	}
+
+	specsJSON, _ := json.Marshal(r.Specs)
+	name := r.SkuName
+	if name == "" {
+		name = product.Name
+	}
+	now := time.Now()
+	switch {
+	case existing == nil:
+		sku := &model.Sku{
+			ProductID:     product.ID,
+			SkuCode:       r.SkuCode,
+			Name:          name,
+			Specs:         string(specsJSON),
+			Price:         r.Price,
+			OriginalPrice: r.OriginalPrice,
+			Stock:         r.Stock,
+			Image:         r.SkuImage,
+			Status:        r.SkuStatus,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := skuRepo.Create(ctx, sku); err != nil {
+			return 0, apperrors.NewInternalError("创建SKU失败: " + err.Error())
+		}
+		return sku.ID, nil
+	case existing.DeletedAt.Valid:
+		updates := map[string]any{
+			"product_id":     product.ID,
+			"name":           name,
+			"specs":          string(specsJSON),
+			"price":          r.Price,
+			"original_price": r.OriginalPrice,
+			"stock":          r.Stock,
+			"image":          r.SkuImage,
+			"status":         r.SkuStatus,
+			"updated_at":     now,
+		}
+		if err := skuRepo.RestoreAndUpdateByID(ctx, existing.ID, updates); err != nil {
+			return 0, apperrors.NewInternalError("恢复SKU失败: " + err.Error())
+		}
+		return existing.ID, nil
+	default:
+		existing.Name = name
+		existing.Specs = string(specsJSON)
+		existing.Price = r.Price
+		existing.OriginalPrice = r.OriginalPrice
+		existing.Stock = r.Stock
+		existing.Image = r.SkuImage
+		existing.Status = r.SkuStatus
+		existing.UpdatedAt = now
+		if err := skuRepo.Update(ctx, existing); err != nil {
+			return 0, apperrors.NewInternalError("更新SKU失败: " + err.Error())
+		}
+		return existing.ID, nil
+	}
+}
+
+// loadCatalogRefs 加载全部类目，按“一级/二级/三级”的名称路径建立索引
+func (l *ProductLogic) loadCatalogRefs(ctx context.Context) (*catalogRefs, error) {
+	refs := &catalogRefs{
+		categories: make(map[string][]*model.Category),
+		brands:     make(map[string]*model.Brand),
+	}
+	if l.categoryRepo == nil {
+		return refs, nil
+	}
+	list, err := l.categoryRepo.GetAll(ctx, -1, "")
+	if err != nil {
+		return nil, fmt.Errorf("查询类目失败: %w", err)
+	}
+	paths := categoryPaths(list)
+	for _, c := range list {
+		refs.categories[paths[c.ID]] = append(refs.categories[paths[c.ID]], c)
+	}
+	return refs, nil
+}
+
+// category 按名称路径查找启用的类目
+func (r *catalogRefs) category(path string) (*model.Category, error) {
+	parts := strings.Split(path, "/")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	list := r.categories[strings.Join(parts, "/")]
+	switch {
+	case len(list) == 0:
+		return nil, apperrors.NewInvalidParamError("类目不存在: " + path)
+	case len(list) > 1:
+		return nil, apperrors.NewInvalidParamError("类目路径不唯一: " + path)
+	case list[0].Status != 1:
+		return nil, apperrors.NewInvalidParamError("类目已禁用: " + path)
+	}
+	return list[0], nil
+}
+
+// catalogBrand 按名称查找品牌，结果在任务内缓存
+func (l *ProductLogic) catalogBrand(ctx context.Context, refs *catalogRefs, name string) (*model.Brand, error) {
+	if b, ok := refs.brands[name]; ok {
+		if b == nil {
+			return nil, apperrors.NewInvalidParamError("品牌不存在: " + name)
+		}
+		return b, nil
+	}
+	if l.brandRepo == nil {
+		return nil, apperrors.NewInvalidParamError("品牌不存在: " + name)
+	}
+	b, err := l.brandRepo.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			refs.brands[name] = nil
+			return nil, apperrors.NewInvalidParamError("品牌不存在: " + name)
+		}
+		return nil, apperrors.NewInternalError("查询品牌失败: " + err.Error())
+	}
+	refs.brands[name] = b
+	return b, nil
+}
+
+// categoryPaths 计算每个类目的名称路径（类目ID -> “一级/二级/三级”）
+func categoryPaths(list []*model.Category) map[uint64]string {
+	byID := make(map[uint64]*model.Category, len(list))
+	for _, c := range list {
+		byID[c.ID] = c
+	}
+	paths := make(map[uint64]string, len(list))
+	for _, c := range list {
+		names := []string{c.Name}
+		seen := map[uint64]bool{c.ID: true}
+		for parent := byID[c.ParentID]; parent != nil && !seen[parent.ID]; parent = byID[parent.ParentID] {
+			seen[parent.ID] = true
+			names = append([]string{parent.Name}, names...)
+		}
+		paths[c.ID] = strings.Join(names, "/")
+	}
+	return paths
+}
+
+// detectCatalogFormat 按文件扩展名判断格式，没有扩展名时按内容判断（xlsx 是 zip 包）
+func detectCatalogFormat(fileName string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx":
+		return model.CatalogFormatXLSX
+	case ".csv":
+		return model.CatalogFormatCSV
+	}
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return model.CatalogFormatXLSX
+	}
+	return model.CatalogFormatCSV
+}
+
+// readCatalogRecords 读取文件的全部行；maxRows 为数据行数上限（不含表头），<= 0 表示不限制。
+// 读取过程中就按上限截止，避免先把超大文件全部解析到内存
+func readCatalogRecords(format string, data []byte, maxRows int) ([][]string, error) {
+	limit := 0
+	if maxRows > 0 {
+		limit = maxRows + 1
+	}
+	tooMany := apperrors.NewInvalidParamError(fmt.Sprintf("文件数据行数超过上限 %d", maxRows))
+	if format == model.CatalogFormatXLSX {
+		records, err := xlsx.Read(data, limit)
+		if errors.Is(err, xlsx.ErrTooManyRows) {
+			return nil, tooMany
+		}
+		if err != nil {
+			return nil, apperrors.NewInvalidParamError("XLSX 文件格式错误: " + err.Error())
+		}
+		return records, nil
+	}
+	if !utf8.Valid(data) {
+		return nil, apperrors.NewInvalidParamError("CSV 文件必须是 UTF-8 编码")
+	}
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, apperrors.NewInvalidParamError("CSV 文件格式错误: " + err.Error())
+		}
+		if limit > 0 && len(records) >= limit {
+			return nil, tooMany
+		}
+		records = append(records, record)
+	}
+}
+
+// parseCatalogRows 按表头解析数据行，跳过空行；不认识的列（如错误报告的 error 列）忽略
+func parseCatalogRows(records [][]string) ([]*catalogRow, error) {
+	if len(records) == 0 {
+		return nil, apperrors.NewInvalidParamError("文件为空")
+	}
+	index := make(map[string]int, len(records[0]))
+	for i, h := range records[0] {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{"spu_code", "sku_code", "price"} {
+		if _, ok := index[required]; !ok {
+			return nil, apperrors.NewInvalidParamError("缺少必需的列: " + required)
+		}
+	}
+
+	var rows []*catalogRow
+	for i, record := range records[1:] {
+		cells := make([]string, len(catalogColumns))
+		blank := true
+		for j, c := range catalogColumns {
+			if k, ok := index[c]; ok && k < len(record) {
+				cells[j] = strings.TrimSpace(record[k])
+				blank = blank && cells[j] == ""
+			}
+		}
+		if blank {
+			continue
+		}
+		row := &catalogRow{Line: i + 2, Cells: cells}
+		if err := row.parse(); err != nil {
+			row.Err = err.Error()
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parse 解析并校验行内字段的格式
+func (r *catalogRow) parse() error {
+	r.SpuCode = r.cell("spu_code")
+	r.SkuCode = r.cell("sku_code")
+	r.SkuName = r.cell("sku_name")
+	r.SkuImage = r.cell("sku_image")
+	switch {
+	case r.SpuCode == "":
+		return errors.New("spu_code 不能为空")
+	case len(r.SpuCode) > 50:
+		return errors.New("spu_code 不能超过50个字符")
+	case len(r.SkuCode) > 50:
+		return errors.New("sku_code 不能超过50个字符")
+	case utf8.RuneCountInString(r.cell("name")) > 200, utf8.RuneCountInString(r.cell("subtitle")) > 200,
+		utf8.RuneCountInString(r.SkuName) > 200:
+		return errors.New("名称和副标题不能超过200个字符")
+	case len(r.cell("main_image")) > 255 || len(r.SkuImage) > 255:
+		return errors.New("图片地址不能超过255个字符")
+	}
+
+	if s := r.cell("price"); s != "" {
+		price, err := strconv.ParseFloat(s, 64)
+		if err != nil || price <= 0 {
+			return fmt.Errorf("价格格式错误: %s", s)
+		}
+		r.Price = price
+	}
+	if s := r.cell("original_price"); s != "" {
+		originalPrice, err := strconv.ParseFloat(s, 64)
+		if err != nil || originalPrice < 0 {
+			return fmt.Errorf("原价格式错误: %s", s)
+		}
+		if originalPrice > 0 {
+			r.OriginalPrice = &originalPrice
+		}
+	}
+	if s := r.cell("stock"); s != "" {
+		stock, err := strconv.Atoi(s)
+		if err != nil || stock < 0 {
+			return fmt.Errorf("库存格式错误: %s", s)
+		}
+		r.Stock = stock
+	}
+
+	r.SkuStatus = 1
+	if s := r.cell("sku_status"); s != "" {
+		if s != "0" && s != "1" {
+			return fmt.Errorf("sku_status 只能是 0 或 1: %s", s)
+		}
+		if s == "0" {
+			r.SkuStatus = 0
+		}
+	}
+	if _, err := parseCatalogKV(r.cell("attrs")); err != nil {
+		return err
+	}
+	specs, err := parseCatalogKV(r.cell("specs"))
+	if err != nil {
+		return err
+	}
+	r.Specs = specs
+
+	if r.SkuCode != "" {
+		if r.Price <= 0 {
+			return errors.New("SKU 价格不能为空")
+		}
+		if len(r.Specs) == 0 {
+			return errors.New("SKU 规格不能为空")
+		}
+	} else if r.cell("sku_name") != "" || r.cell("specs") != "" || r.SkuImage != "" {
+		return errors.New("填写了 SKU 字段但 sku_code 为空")
+	}
+	return nil
+}
+
+// groupCatalogRows 按 spu_code 分组（保持首次出现的顺序），并校验组内商品字段一致、SKU 编码不重复
+func groupCatalogRows(rows []*catalogRow) []*catalogGroup {
+	var groups []*catalogGroup
+	bySpu := make(map[string]*catalogGroup)
+	skuLines := make(map[string]int)
+	for _, r := range rows {
+		g := bySpu[r.SpuCode]
+		if g == nil {
+			g = &catalogGroup{SpuCode: r.SpuCode}
+			bySpu[r.SpuCode] = g
+			groups = append(groups, g)
+		}
+		g.Rows = append(g.Rows, r)
+		if r.Err != "" {
+			g.fail(r.Line, r.Err)
+			continue
+		}
+		if r.SkuCode != "" {
+			if line, dup := skuLines[r.SkuCode]; dup {
+				g.fail(r.Line, fmt.Sprintf("sku_code 与第 %d 行重复", line))
+				continue
+			}
+			skuLines[r.SkuCode] = r.Line
+		}
+	}
+
+	for _, g := range groups {
+		productOnly := 0
+		for _, r := range g.Rows {
+			if r.SkuCode == "" {
+				productOnly++
+			}
+			if !hasCatalogProductFields(r) {
+				continue
+			}
+			if g.Product == nil {
+				g.Product = r
+				continue
+			}
+			for _, c := range catalogProductColumns {
+				if v := r.cell(c); v != "" && v != g.Product.cell(c) {
+					g.fail(r.Line, fmt.Sprintf("%s 与第 %d 行不一致", c, g.Product.Line))
+					break
+				}
+			}
+		}
+		if g.Product == nil {
+			g.Product = g.Rows[0]
+		}
+		if productOnly > 0 && (productOnly > 1 || len(g.Rows) > 1) {
+			g.fail(0, "同一商品有 SKU 时每行都必须填写 sku_code")
+		}
+	}
+	return groups
+}
+
+// hasCatalogProductFields 行中是否填写了商品字段
+func hasCatalogProductFields(r *catalogRow) bool {
+	for _, c := range catalogProductColumns {
+		if r.cell(c) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCatalogKV 解析“名称:取值;名称:取值”格式的属性或规格，多选值以逗号分隔
+func parseCatalogKV(s string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, item := range strings.Split(s, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(strings.Replace(item, "：", ":", 1), ":")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("属性格式错误，应为“名称:取值;名称:取值”: %s", item)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// formatCatalogKV 将属性或规格格式化为“名称:取值;名称:取值”，按名称排序
+func formatCatalogKV(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+":"+m[k])
+	}
+	return strings.Join(parts, ";")
+}
+
+// runCatalogExport 按筛选条件分页导出商品和 SKU，写成与导入相同格式的文件并上传
+func (l *ProductLogic) runCatalogExport(ctx context.Context, job *model.CatalogJob, params *CatalogJobParams) error {
+	listReq := &repository.ListProductsRequest{
+		BrandID:  params.BrandID,
+		Keyword:  params.Keyword,
+		Status:   params.Status,
+		IsHot:    -1,
+		PageSize: catalogExportPageSize,
+		Sort:     "id_asc",
+	}
+	if params.CategoryID > 0 {
+		ids, err := l.collectDescendantCategoryIDs(ctx, params.CategoryID)
+		if err != nil {
+			return err
+		}
+		listReq.CategoryIDs = ids
+		if len(ids) == 0 {
+			listReq.CategoryID = params.CategoryID
+		}
+	}
+
+	var categories []*model.Category
+	if l.categoryRepo != nil {
+		var err error
+		if categories, err = l.categoryRepo.GetAll(ctx, -1, ""); err != nil {
+			return fmt.Errorf("查询类目失败: %w", err)
+		}
+	}
+	categoryPath := categoryPaths(categories)
+
+	records := [][]string{catalogColumns}
+	for page := 1; ; page++ {
+		listReq.Page = page
+		products, total, err := l.productRepo.List(ctx, listReq)
+		if err != nil {
+			return fmt.Errorf("查询商品失败: %w", err)
+		}
+		job.TotalRows = int(total)
+		l.fillBrandNames(ctx, products...)
+		for _, p := range products {
+			skus, err := l.skuRepo.GetByProductID(ctx, p.ID)
+			if err != nil {
+				return fmt.Errorf("查询SKU失败: %w", err)
+			}
+			records = append(records, catalogExportRows(p, categoryPath[p.CategoryID], skus)...)
+		}
+		job.ProcessedRows += len(products)
+		job.SuccessRows = len(records) - 1
+		l.saveCatalogJob(ctx, job)
+		if len(products) < catalogExportPageSize {
+			break
+		}
+	}
+
+	name := fmt.Sprintf("catalog-export-%d", job.ID)
+	fileID, err := l.uploadCatalogFile(ctx, job.Format, name, records)
+	if err != nil {
+		return err
+	}
+	job.ResultFileID = fileID
+	return nil
+}
+
+// catalogExportRows 商品的导出行：每个 SKU 一行，商品字段每行都填写；没有 SKU 的商品导出一行商品行
+func catalogExportRows(p *model.Product, categoryPath string, skus []*model.Sku) [][]string {
+	images, _ := parseJSONArray(p.Images)
+	attrs, _ := parseJSONMap(p.Attrs)
+	product := []string{
+		p.SpuCode, p.Name, p.Subtitle, categoryPath, p.BrandName, p.MainImage,
+		strings.Join(images, "|"), p.Detail, formatCatalogKV(attrs),
+	}
+	if len(skus) == 0 {
+		row := append(append([]string{}, product...), "", "", "", formatCatalogPrice(&p.Price),
+			formatCatalogPrice(p.OriginalPrice), strconv.Itoa(p.Stock), "", "")
+		return [][]string{row}
+	}
+	rows := make([][]string, 0, len(skus))
+	for _, s := range skus {
+		specs, _ := parseJSONMap(s.Specs)
+		row := append(append([]string{}, product...), s.SkuCode, s.Name, formatCatalogKV(specs),
+			formatCatalogPrice(&s.Price), formatCatalogPrice(s.OriginalPrice), strconv.Itoa(s.Stock),
+			s.Image, strconv.Itoa(int(s.Status)))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// formatCatalogPrice 价格保留两位小数，nil 为空
+func formatCatalogPrice(p *float64) string {
+	if p == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*p, 'f', 2, 64)
+}
+
+// uploadCatalogFile 将行写成 CSV（带 BOM，便于 Excel 直接打开）或 XLSX 并上传到文件服务私有分类
+func (l *ProductLogic) uploadCatalogFile(ctx context.Context, format, name string, records [][]string) (string, error) {
+	var buf bytes.Buffer
+	fileType := "text/csv"
+	if format == model.CatalogFormatXLSX {
+		fileType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		if err := xlsx.Write(&buf, "catalog", records); err != nil {
+			return "", fmt.Errorf("生成文件失败: %w", err)
+		}
+	} else {
+		buf.WriteString("\ufeff")
+		w := csv.NewWriter(&buf)
+		if err := w.WriteAll(records); err != nil {
+			return "", fmt.Errorf("生成文件失败: %w", err)
+		}
+	}
+	fileID, err := l.catalogStore.Upload(ctx, buf.Bytes(), name+"."+format, fileType, l.catalogPolicy.FileCategory)
+	if err != nil {
+		return "", fmt.Errorf("上传文件失败: %w", err)
+	}
+	return fileID, nil
+}
+
+// catalogErrorMessage 取业务错误的提示信息，其他错误原样返回
+func catalogErrorMessage(err error) string {
+	var bizErr *apperrors.BusinessError
+	if errors.As(err, &bizErr) {
+		return bizErr.Message
+	}
+	return err.Error()
+}
+
+// truncateRunes 按字符截断，避免截断多字节字符
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/xlsx"
+	"ecommerce-system/internal/service/product/model"
+)
+
+// fakeCatalogStore 内存中的文件服务，上传的文件按顺序编号
+type fakeCatalogStore struct {
+	files map[string][]byte
+}
+
+func (s *fakeCatalogStore) Download(ctx context.Context, fileID string, maxSize int64) ([]byte, string, error) {
+	data, ok := s.files[fileID]
+	if !ok {
+		return nil, "", errors.New("file not found")
+	}
+	return data, fileID, nil
+}
+
+func (s *fakeCatalogStore) Upload(ctx context.Context, data []byte, fileName, fileType, category string) (string, error) {
+	fileID := fmt.Sprintf("upload-%d-%s", len(s.files)+1, fileName)
+	s.files[fileID] = data
+	return fileID, nil
+}
+
+func (s *fakeCatalogStore) GetFileURL(ctx context.Context, fileID string, expireSeconds int64, userID uint64) (string, int64, error) {
+	return "https://files.example.com/" + fileID, 1700000000, nil
+}
+
+// catalogTestFile 第 2、3 行修改已有商品 SPU1 并新增一个 SKU，第 4 行新建商品 SPU2；
+// SPU3 的第 5 行规格取值不存在，写入时才发现，同组的第 7 行一起失败；第 6 行价格格式错误
+const catalogTestFile = `spu_code,name,category_path,sku_code,specs,price,stock
+SPU1,新上衣,服装/上衣,SKU1-R,颜色:红,12,5
+SPU1,,,SKU1-B,颜色:蓝,11,3
+SPU2,新裤子,服装/上衣,SKU2-R,颜色:红,20,1
+SPU3,坏商品,服装/上衣,SKU3-R,颜色:绿,30,1
+SPU4,价格错误,服装/上衣,SKU4-R,颜色:红,abc,1
+SPU3,,,SKU3-B,颜色:蓝,30,1
+`
+
+type catalogTestEnv struct {
+	*productTestEnv
+	store *fakeCatalogStore
+}
+
+// newCatalogTestEnv 类目“服装/上衣”有必填的销售属性颜色（红、蓝），已上架的商品 SPU1 有一个红色 SKU
+func newCatalogTestEnv() *catalogTestEnv {
+	env := &catalogTestEnv{
+		productTestEnv: newProductTestEnv(),
+		store:          &fakeCatalogStore{files: map[string][]byte{"catalog.csv": []byte(catalogTestFile)}},
+	}
+	env.categories.seed(
+		&model.Category{ID: 1, Name: "服装", Level: 1, Status: 1},
+		&model.Category{ID: 2, ParentID: 1, Name: "上衣", Level: 2, Status: 1},
+	)
+	env.attrs.seed(newAttr(1, 2, "颜色", model.AttrTypeSales, model.AttrInputSingle, true, "红", "蓝"))
+	env.products.seed(&model.Product{
+		ID: 1, SpuCode: "SPU1", CategoryID: 2, Name: "上衣", Price: 10, Stock: 2,
+		Status: model.ProductStatusOn, AuditStatus: model.RevisionStatusApproved,
+	})
+	env.skus.seed(&model.Sku{
+		ID: 1, ProductID: 1, SkuCode: "SKU1-R", Name: "上衣", Specs: `{"颜色":"红"}`, Price: 10, Stock: 2, Status: 1,
+	})
+	env.logic.catalogStore = env.store
+	env.logic.catalogPolicy = CatalogPolicy{BatchSize: 2, MaxRows: 100}
+	return env
+}
+
+// runImport 创建导入任务并同步处理，返回处理后的任务
+func (env *catalogTestEnv) runImport(t *testing.T, fileID string, dryRun bool) *model.CatalogJob {
+	t.Helper()
+	ctx := context.Background()
+	job := &model.CatalogJob{
+		Kind:      model.CatalogJobImport,
+		FileID:    fileID,
+		DryRun:    dryRun,
+		Params:    `{"status":-1}`,
+		Errors:    "[]",
+		Status:    model.CatalogJobPending,
+		CreatedBy: 7,
+	}
+	if err := env.jobs.Create(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	env.logic.processCatalogJob(ctx, job.ID)
+	job, err := env.jobs.GetByID(ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+// assertErrorReport 检查错误报告按商品分组列出失败的行，并在末尾追加了错误原因
+func (env *catalogTestEnv) assertErrorReport(t *testing.T, job *model.CatalogJob, spuCodes ...string) {
+	t.Helper()
+	data, ok := env.store.files[job.ResultFileID]
+	if !ok {
+		t.Fatalf("error report %q not uploaded", job.ResultFileID)
+	}
+	records, err := readCatalogRecords(model.CatalogFormatCSV, data, 0)
+	if err != nil {
+		t.Fatalf("read error report: %v", err)
+	}
+	if len(records) != len(spuCodes)+1 || records[0][len(records[0])-1] != catalogErrorColumn {
+		t.Fatalf("unexpected error report: %v", records)
+	}
+	for i, spuCode := range spuCodes {
+		row := records[i+1]
+		if row[0] != spuCode || row[len(row)-1] == "" {
+			t.Fatalf("error report row %d = %v, want %s with a reason", i+1, row, spuCode)
+		}
+	}
+}
+
+func TestImportCatalogPartialFailure(t *testing.T) {
+	env := newCatalogTestEnv()
+	job := env.runImport(t, "catalog.csv", false)
+
+	if job.Status != model.CatalogJobSucceeded || job.Message != "" {
+		t.Fatalf("job status = %d message %q, want succeeded", job.Status, job.Message)
+	}
+	if job.TotalRows != 6 || job.ProcessedRows != 6 || job.SuccessRows != 3 || job.FailedRows != 3 {
+		t.Fatalf("rows total=%d processed=%d success=%d failed=%d",
+			job.TotalRows, job.ProcessedRows, job.SuccessRows, job.FailedRows)
+	}
+
+	// 已有商品：线上内容不变，修改进入修订；SKU 和库存直接写入
+	p1 := env.products.rows[1]
+	if p1.Name != "上衣" || p1.Stock != 8 {
+		t.Fatalf("existing product name %q stock %d", p1.Name, p1.Stock)
+	}
+	rev, _ := env.revisions.GetOpenByProductID(context.Background(), 1)
+	if rev == nil || rev.CreatedBy != 7 {
+		t.Fatalf("expected an open revision created by the job owner, got %+v", rev)
+	}
+	if content, _ := parseRevisionContent(rev.Content); content.Name != "新上衣" || content.Price != 11 {
+		t.Fatalf("unexpected revision content: %+v", content)
+	}
+	if sku := env.skus.rows[1]; sku.Price != 12 || sku.Stock != 5 {
+		t.Fatalf("existing sku price %v stock %d", sku.Price, sku.Stock)
+	}
+	if _, err := env.skus.GetBySkuCodeUnscoped(context.Background(), "SKU1-B"); err != nil {
+		t.Fatalf("new sku of SPU1 not created: %v", err)
+	}
+
+	// 新商品待审核；写入失败的商品整组回滚
+	p2, err := env.products.GetBySpuCode(context.Background(), "SPU2")
+	if err != nil || p2.Status != model.ProductStatusPending {
+		t.Fatalf("new product SPU2: %+v err=%v", p2, err)
+	}
+	for _, spuCode := range []string{"SPU3", "SPU4"} {
+		if _, err := env.products.GetBySpuCode(context.Background(), spuCode); err == nil {
+			t.Fatalf("failed product %s was written", spuCode)
+		}
+	}
+	for _, skuCode := range []string{"SKU3-R", "SKU3-B", "SKU4-R"} {
+		if _, err := env.skus.GetBySkuCodeUnscoped(context.Background(), skuCode); err == nil {
+			t.Fatalf("sku %s of a failed product was written", skuCode)
+		}
+	}
+
+	detail, err := env.logic.GetCatalogJob(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("GetCatalogJob: %v", err)
+	}
+	wantRows := map[int]string{5: "颜色", 6: "价格格式错误", 7: "第 5 行"}
+	if len(detail.RowErrors) != len(wantRows) {
+		t.Fatalf("row errors = %+v", detail.RowErrors)
+	}
+	for _, e := range detail.RowErrors {
+		if want, ok := wantRows[e.Row]; !ok || !strings.Contains(e.Message, want) {
+			t.Fatalf("row %d error %q, want it to mention %q", e.Row, e.Message, want)
+		}
+	}
+	if detail.ResultURL == "" {
+		t.Fatal("missing error report URL")
+	}
+	env.assertErrorReport(t, job, "SPU3", "SPU3", "SPU4")
+}
+
+func TestImportCatalogDryRun(t *testing.T) {
+	env := newCatalogTestEnv()
+	product, sku := *env.products.rows[1], *env.skus.rows[1]
+
+	job := env.runImport(t, "catalog.csv", true)
+
+	// 报告与正式导入一致
+	if job.Status != model.CatalogJobSucceeded || job.SuccessRows != 3 || job.FailedRows != 3 {
+		t.Fatalf("dry run job status=%d success=%d failed=%d", job.Status, job.SuccessRows, job.FailedRows)
+	}
+	env.assertErrorReport(t, job, "SPU3", "SPU3", "SPU4")
+
+	// 但没有写入任何数据
+	if len(env.products.rows) != 1 || *env.products.rows[1] != product {
+		t.Fatalf("dry run changed products: %d products, %+v", len(env.products.rows), env.products.rows[1])
+	}
+	if len(env.skus.rows) != 1 || *env.skus.rows[1] != sku {
+		t.Fatalf("dry run changed skus: %d skus, %+v", len(env.skus.rows), env.skus.rows[1])
+	}
+	if len(env.revisions.rows) != 0 || len(env.revisions.logs) != 0 {
+		t.Fatalf("dry run wrote %d revisions and %d audit logs", len(env.revisions.rows), len(env.revisions.logs))
+	}
+}
+
+func TestCatalogJobStatusTransitions(t *testing.T) {
+	env := newCatalogTestEnv()
+
+	job := env.runImport(t, "catalog.csv", false)
+	statuses := env.jobs.statuses
+	if len(statuses) < 2 || statuses[len(statuses)-1] != model.CatalogJobSucceeded {
+		t.Fatalf("statuses = %v, want running then succeeded", statuses)
+	}
+	for _, s := range statuses[:len(statuses)-1] {
+		if s != model.CatalogJobRunning {
+			t.Fatalf("statuses = %v, want running then succeeded", statuses)
+		}
+	}
+	if job.StartedAt == nil || job.FinishedAt == nil || job.FinishedAt.Before(*job.StartedAt) {
+		t.Fatalf("started %v finished %v", job.StartedAt, job.FinishedAt)
+	}
+
+	// 已结束的任务不会被再次处理
+	updates := len(env.jobs.statuses)
+	env.logic.processCatalogJob(context.Background(), job.ID)
+	if len(env.jobs.statuses) != updates {
+		t.Fatalf("finished job processed again: %v", env.jobs.statuses[updates:])
+	}
+
+	// 文件无法读取时整体失败，记录原因
+	env.jobs.statuses = nil
+	job = env.runImport(t, "missing.csv", false)
+	if job.Status != model.CatalogJobFailed || !strings.Contains(job.Message, "下载导入文件失败") {
+		t.Fatalf("job status = %d message %q, want failed", job.Status, job.Message)
+	}
+	if got := env.jobs.statuses; len(got) != 2 || got[0] != model.CatalogJobRunning || got[1] != model.CatalogJobFailed {
+		t.Fatalf("statuses = %v, want [running failed]", got)
+	}
+	if job.SuccessRows != 0 || job.ResultFileID != "" || job.FinishedAt == nil {
+		t.Fatalf("unexpected failed job: %+v", job)
+	}
+}
+
+func TestReadCatalogRecordsRowLimit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := xlsx.Write(&buf, "Sheet1", [][]string{{"spu_code"}, {"A"}, {"B"}, {"C"}}); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		model.CatalogFormatCSV:  []byte("spu_code\nA\nB\nC\n"),
+		model.CatalogFormatXLSX: buf.Bytes(),
+	}
+	for format, data := range files {
+		// 上限不含表头
+		if records, err := readCatalogRecords(format, data, 3); err != nil || len(records) != 4 {
+			t.Fatalf("%s within limit: rows=%d err=%v", format, len(records), err)
+		}
+		_, err := readCatalogRecords(format, data, 2)
+		assertBizCode(t, err, apperrors.CodeInvalidParam)
+	}
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/service/product/model"
+	"ecommerce-system/internal/service/product/repository"
+)
+
+// memRows 内存表：按 ID 保存整行，读写都复制一份，未找到时与 gorm 一样返回 ErrRecordNotFound
+type memRows[T any] struct {
+	rows   map[uint64]*T
+	nextID uint64
+	idOf   func(*T) *uint64
+}
+
+func newMemRows[T any](idOf func(*T) *uint64) memRows[T] {
+	return memRows[T]{rows: make(map[uint64]*T), idOf: idOf}
+}
+
+// seed 写入测试数据，自增 ID 从已有的最大 ID 之后开始
+func (m *memRows[T]) seed(rows ...*T) {
+	for _, row := range rows {
+		m.put(row)
+	}
+}
+
+func (m *memRows[T]) get(id uint64) (*T, error) {
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *row
+	return &copied, nil
+}
+
+// insert 分配自增 ID 后保存
+func (m *memRows[T]) insert(row *T) {
+	m.nextID++
+	*m.idOf(row) = m.nextID
+	m.put(row)
+}
+
+func (m *memRows[T]) put(row *T) {
+	id := *m.idOf(row)
+	m.nextID = max(m.nextID, id)
+	copied := *row
+	m.rows[id] = &copied
+}
+
+// find 按 ID 升序返回满足条件的行
+func (m *memRows[T]) find(match func(*T) bool) []*T {
+	ids := make([]uint64, 0, len(m.rows))
+	for id, row := range m.rows {
+		if match(row) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	result := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		copied := *m.rows[id]
+		result = append(result, &copied)
+	}
+	return result
+}
+
+// first 返回 ID 最小的满足条件的行
+func (m *memRows[T]) first(match func(*T) bool) (*T, error) {
+	rows := m.find(match)
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return rows[0], nil
+}
+
+// snapshot 记录当前数据，返回恢复函数（行只整体替换不原地修改，浅拷贝即可）
+func (m *memRows[T]) snapshot() func() {
+	saved, nextID := maps.Clone(m.rows), m.nextID
+	return func() { m.rows, m.nextID = saved, nextID }
+}
+
+// memTable 可在内存事务回滚时恢复的内存表
+type memTable interface {
+	snapshot() (restore func())
+}
+
+// memTx 内存事务：fn 返回错误时把 repos 中的内存表恢复到事务开始前，嵌套调用即为保存点
+type memTx struct {
+	repos repository.TxRepos
+}
+
+func newMemTx(repos repository.TxRepos) *memTx {
+	return &memTx{repos: repos}
+}
+
+func (m *memTx) Transaction(ctx context.Context, fn func(r *repository.TxRepos) error) error {
+	r := m.repos
+	r.Transactor = m
+	var restores []func()
+	for _, repo := range []any{r.Product, r.Sku, r.Revision, r.Schedule} {
+		if t, ok := repo.(memTable); ok {
+			restores = append(restores, t.snapshot())
+		}
+	}
+	if err := fn(&r); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+	return nil
+}
+
+// memProductRepo 内存中的商品表，List 只实现品牌和类目筛选，updates 记录 Update 调用次数
+type memProductRepo struct {
+	repository.ProductRepository
+	memRows[model.Product]
+	lastList *repository.ListProductsRequest
+	updates  int
+}
+
+func (m *memProductRepo) GetByID(ctx context.Context, id uint64) (*model.Product, error) {
+	return m.get(id)
+}
+
+func (m *memProductRepo) Create(ctx context.Context, product *model.Product) error {
+	m.insert(product)
+	return nil
+}
+
+func (m *memProductRepo) GetBySpuCode(ctx context.Context, spuCode string) (*model.Product, error) {
+	return m.first(func(p *model.Product) bool { return p.SpuCode == spuCode })
+}
+
+func (m *memProductRepo) Update(ctx context.Context, product *model.Product) error {
+	m.updates++
+	m.put(product)
+	return nil
+}
+
+func (m *memProductRepo) List(ctx context.Context, req *repository.ListProductsRequest) ([]*model.Product, int64, error) {
+	m.lastList = req
+	result := m.find(func(p *model.Product) bool {
+		if req.BrandID > 0 && (p.BrandID == nil || *p.BrandID != req.BrandID) {
+			return false
+		}
+		return req.CategoryID == 0 || p.CategoryID == req.CategoryID
+	})
+	return result, int64(len(result)), nil
+}
+
+func (m *memProductRepo) ListIDsByBrand(ctx context.Context, brandID uint64) ([]uint64, error) {
+	ids := make([]uint64, 0)
+	for _, p := range m.find(func(p *model.Product) bool { return p.BrandID != nil && *p.BrandID == brandID }) {
+		ids = append(ids, p.ID)
+	}
+	return ids, nil
+}
+
+// memSkuRepo 内存中的 SKU 表
+type memSkuRepo struct {
+	repository.SkuRepository
+	memRows[model.Sku]
+}
+
+func (m *memSkuRepo) Create(ctx context.Context, sku *model.Sku) error {
+	m.insert(sku)
+	return nil
+}
+
+func (m *memSkuRepo) GetByID(ctx context.Context, id uint64) (*model.Sku, error) {
+	return m.get(id)
+}
+
+func (m *memSkuRepo) GetBySkuCodeUnscoped(ctx context.Context, skuCode string) (*model.Sku, error) {
+	return m.first(func(s *model.Sku) bool { return s.SkuCode == skuCode })
+}
+
+func (m *memSkuRepo) GetByProductID(ctx context.Context, productID uint64) ([]*model.Sku, error) {
+	return m.find(func(s *model.Sku) bool { return s.ProductID == productID && !s.DeletedAt.Valid }), nil
+}
+
+// GetAggByProductIDs 只聚合最低价和总库存
+func (m *memSkuRepo) GetAggByProductIDs(ctx context.Context, productIDs []uint64, status int8) (map[uint64]repository.SkuAgg, error) {
+	result := make(map[uint64]repository.SkuAgg)
+	skus := m.find(func(s *model.Sku) bool {
+		return slices.Contains(productIDs, s.ProductID) && !s.DeletedAt.Valid && (status < 0 || s.Status == status)
+	})
+	for _, s := range skus {
+		agg, ok := result[s.ProductID]
+		if !ok || s.Price < agg.MinPrice {
+			agg.MinPrice = s.Price
+		}
+		agg.TotalStock += int64(s.Stock)
+		result[s.ProductID] = agg
+	}
+	return result, nil
+}
+
+func (m *memSkuRepo) Update(ctx context.Context, sku *model.Sku) error {
+	m.put(sku)
+	return nil
+}
+
+// memBrandRepo 内存中的品牌表
+type memBrandRepo struct {
+	repository.BrandRepository
+	memRows[model.Brand]
+}
+
+func (m *memBrandRepo) Create(ctx context.Context, brand *model.Brand) error {
+	m.insert(brand)
+	return nil
+}
+
+func (m *memBrandRepo) GetByID(ctx context.Context, id uint64) (*model.Brand, error) {
+	return m.get(id)
+}
+
+func (m *memBrandRepo) GetByName(ctx context.Context, name string) (*model.Brand, error) {
+	return m.first(func(b *model.Brand) bool { return b.Name == name })
+}
+
+func (m *memBrandRepo) GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.Brand, error) {
+	result := make(map[uint64]*model.Brand, len(ids))
+	for _, b := range m.find(func(b *model.Brand) bool { return slices.Contains(ids, b.ID) }) {
+		result[b.ID] = b
+	}
+	return result, nil
+}
+
+func (m *memBrandRepo) List(ctx context.Context, status int8, keyword string, page, pageSize int) ([]*model.Brand, int64, error) {
+	result := m.find(func(b *model.Brand) bool {
+		return (status < 0 || b.Status == status) && strings.Contains(b.Name, keyword)
+	})
+	return result, int64(len(result)), nil
+}
+
+func (m *memBrandRepo) Update(ctx context.Context, brand *model.Brand) error {
+	m.put(brand)
+	return nil
+}
+
+func (m *memBrandRepo) Delete(ctx context.Context, id uint64) error {
+	delete(m.rows, id)
+	return nil
+}
+
+// memCategoryRepo 内存中的类目表
+type memCategoryRepo struct {
+	repository.CategoryRepository
+	memRows[model.Category]
+}
+
+func (m *memCategoryRepo) GetByID(ctx context.Context, id uint64) (*model.Category, error) {
+	return m.get(id)
+}
+
+func (m *memCategoryRepo) GetAll(ctx context.Context, status int8, keyword string) ([]*model.Category, error) {
+	return m.find(func(c *model.Category) bool {
+		return (status < 0 || c.Status == status) && strings.Contains(c.Name, keyword)
+	}), nil
+}
+
+// memAttrRepo 内存中的类目属性表，ListByCategoryIDs 与实现一致按排序值降序、ID升序
+type memAttrRepo struct {
+	repository.AttrRepository
+	memRows[model.Attr]
+}
+
+func (m *memAttrRepo) Create(ctx context.Context, attr *model.Attr) error {
+	m.insert(attr)
+	return nil
+}
+
+func (m *memAttrRepo) GetByID(ctx context.Context, id uint64) (*model.Attr, error) {
+	return m.get(id)
+}
+
+func (m *memAttrRepo) ListByCategoryIDs(ctx context.Context, categoryIDs []uint64) ([]*model.Attr, error) {
+	result := m.find(func(a *model.Attr) bool { return slices.Contains(categoryIDs, a.CategoryID) })
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Sort > result[j].Sort })
+	return result, nil
+}
+
+func (m *memAttrRepo) Update(ctx context.Context, attr *model.Attr) error {
+	m.put(attr)
+	return nil
+}
+
+// memRevisionRepo 内存中的修订表和审核日志
+type memRevisionRepo struct {
+	repository.RevisionRepository
+	memRows[model.ProductRevision]
+	logs []*model.ProductAuditLog
+}
+
+func (m *memRevisionRepo) Create(ctx context.Context, rev *model.ProductRevision) error {
+	m.insert(rev)
+	return nil
+}
+
+func (m *memRevisionRepo) GetByID(ctx context.Context, id uint64) (*model.ProductRevision, error) {
+	return m.get(id)
+}
+
+func (m *memRevisionRepo) GetOpenByProductID(ctx context.Context, productID uint64) (*model.ProductRevision, error) {
+	open := m.find(func(rev *model.ProductRevision) bool {
+		return rev.ProductID == productID && rev.Status != model.RevisionStatusApproved
+	})
+	if len(open) == 0 {
+		return nil, nil
+	}
+	return open[len(open)-1], nil
+}
+
+func (m *memRevisionRepo) UpdateFromStatus(ctx context.Context, rev *model.ProductRevision, fromStatus int8) (bool, error) {
+	current, ok := m.rows[rev.ID]
+	if !ok || current.Status != fromStatus {
+		return false, nil
+	}
+	m.put(rev)
+	return true, nil
+}
+
+func (m *memRevisionRepo) CreateLog(ctx context.Context, log *model.ProductAuditLog) error {
+	copied := *log
+	copied.ID = uint64(len(m.logs) + 1)
+	m.logs = append(m.logs, &copied)
+	return nil
+}
+
+func (m *memRevisionRepo) ListLogs(ctx context.Context, productID uint64, page, pageSize int) ([]*model.ProductAuditLog, int64, error) {
+	result := make([]*model.ProductAuditLog, 0)
+	for i := len(m.logs) - 1; i >= 0; i-- {
+		if log := m.logs[i]; log.ProductID == productID {
+			copied := *log
+			result = append(result, &copied)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+// snapshot 审核日志只追加，回滚时截断到事务开始前的长度
+func (m *memRevisionRepo) snapshot() func() {
+	restoreRows, logCount := m.memRows.snapshot(), len(m.logs)
+	return func() {
+		restoreRows()
+		m.logs = m.logs[:logCount]
+	}
+}
+
+// memScheduleRepo 内存中的定时任务表，afterListDue 在下一次 ListDue 取到任务后调用一次，模拟执行期间的并发操作
+type memScheduleRepo struct {
+	repository.ScheduleRepository
+	memRows[model.ProductSchedule]
+	afterListDue func()
+}
+
+func (m *memScheduleRepo) GetByID(ctx context.Context, id uint64) (*model.ProductSchedule, error) {
+	return m.get(id)
+}
+
+func (m *memScheduleRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]*model.ProductSchedule, error) {
+	result := m.find(func(s *model.ProductSchedule) bool {
+		return s.Status == model.ScheduleStatusPending && !s.RunAt.After(now)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	if hook := m.afterListDue; hook != nil {
+		m.afterListDue = nil
+		hook()
+	}
+	return result, nil
+}
+
+func (m *memScheduleRepo) UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int8, errMsg string) (bool, error) {
+	s, ok := m.rows[id]
+	if !ok || s.Status != fromStatus {
+		return false, nil
+	}
+	copied := *s
+	copied.Status = toStatus
+	copied.Error = errMsg
+	m.rows[id] = &copied
+	return true, nil
+}
+
+// memCatalogJobRepo 内存中的批量任务表，statuses 记录每次 Update 时的任务状态
+type memCatalogJobRepo struct {
+	repository.CatalogJobRepository
+	memRows[model.CatalogJob]
+	statuses []int8
+}
+
+func (m *memCatalogJobRepo) Create(ctx context.Context, job *model.CatalogJob) error {
+	m.insert(job)
+	return nil
+}
+
+func (m *memCatalogJobRepo) GetByID(ctx context.Context, id uint64) (*model.CatalogJob, error) {
+	return m.get(id)
+}
+
+func (m *memCatalogJobRepo) Update(ctx context.Context, job *model.CatalogJob) error {
+	m.statuses = append(m.statuses, job.Status)
+	m.put(job)
+	return nil
+}
+
+// productTestEnv 商品服务的测试环境：logic 的仓储和事务都指向同一组内存表
+type productTestEnv struct {
+	logic      *ProductLogic
+	products   *memProductRepo
+	skus       *memSkuRepo
+	brands     *memBrandRepo
+	categories *memCategoryRepo
+	attrs      *memAttrRepo
+	revisions  *memRevisionRepo
+	schedules  *memScheduleRepo
+	jobs       *memCatalogJobRepo
+}
+
+// newProductTestEnv 创建空的内存表，测试用 seed 写入各自需要的数据
+func newProductTestEnv() *productTestEnv {
+	env := &productTestEnv{
+		products:   &memProductRepo{memRows: newMemRows(func(p *model.Product) *uint64 { return &p.ID })},
+		skus:       &memSkuRepo{memRows: newMemRows(func(s *model.Sku) *uint64 { return &s.ID })},
+		brands:     &memBrandRepo{memRows: newMemRows(func(b *model.Brand) *uint64 { return &b.ID })},
+		categories: &memCategoryRepo{memRows: newMemRows(func(c *model.Category) *uint64 { return &c.ID })},
+		attrs:      &memAttrRepo{memRows: newMemRows(func(a *model.Attr) *uint64 { return &a.ID })},
+		revisions:  &memRevisionRepo{memRows: newMemRows(func(r *model.ProductRevision) *uint64 { return &r.ID })},
+		schedules:  &memScheduleRepo{memRows: newMemRows(func(s *model.ProductSchedule) *uint64 { return &s.ID })},
+		jobs:       &memCatalogJobRepo{memRows: newMemRows(func(j *model.CatalogJob) *uint64 { return &j.ID })},
+	}
+	env.logic = &ProductLogic{
+		productRepo:    env.products,
+		skuRepo:        env.skus,
+		brandRepo:      env.brands,
+		categoryRepo:   env.categories,
+		attrRepo:       env.attrs,
+		revisionRepo:   env.revisions,
+		scheduleRepo:   env.schedules,
+		catalogJobRepo: env.jobs,
+		tx: newMemTx(repository.TxRepos{
+			Product:  env.products,
+			Sku:      env.skus,
+			Revision: env.revisions,
+			Schedule: env.schedules,
+		}),
+	}
+	return env
+}
+
+func uint64Ptr(v uint64) *uint64 { return &v }
+
+func assertBizCode(t *testing.T, err error, code int) {
+	t.Helper()
+	bizErr, ok := err.(*apperrors.BusinessError)
+	if !ok {
+		t.Fatalf("expected BusinessError with code %d, got %v", code, err)
+	}
+	if bizErr.Code != code {
+		t.Fatalf("expected code %d, got %d (%s)", code, bizErr.Code, bizErr.Message)
+	}
+}
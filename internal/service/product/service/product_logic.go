@@ -31,13 +31,23 @@ func isDuplicateSkuCodeErr(err error) bool {
 // ProductLogic 商品业务逻辑
 type ProductLogic struct {
 	db           *gorm.DB
+	tx           repository.Transactor // 审核、定时任务和批量导入的事务
 	outboxRepo   *outbox.Repo
 	productRepo  repository.ProductRepository
 	categoryRepo repository.CategoryRepository
 	skuRepo      repository.SkuRepository
 	bannerRepo   repository.BannerRepository
-	cache        *cache.CacheOperations
-	mqProducer   *mq.Producer
+	brandRepo    repository.BrandRepository
+	attrRepo     repository.AttrRepository
+	revisionRepo repository.RevisionRepository
+	scheduleRepo repository.ScheduleRepository
+	// catalogJobRepo 商品批量导入导出任务
+	catalogJobRepo repository.CatalogJobRepository
+	logoStore      LogoStore    // 为 nil 时只能直接填写 Logo URL
+	catalogStore   CatalogStore // 为 nil 时不能批量导入导出
+	catalogPolicy  CatalogPolicy
+	cache          *cache.CacheOperations
+	mqProducer     *mq.Producer
 }
 
 // NewProductLogic 创建商品业务逻辑
@@ -48,18 +58,40 @@ func NewProductLogic(
 	categoryRepo repository.CategoryRepository,
 	skuRepo repository.SkuRepository,
 	bannerRepo repository.BannerRepository,
+	brandRepo repository.BrandRepository,
+	attrRepo repository.AttrRepository,
+	revisionRepo repository.RevisionRepository,
+	scheduleRepo repository.ScheduleRepository,
+	catalogJobRepo repository.CatalogJobRepository,
+	logoStore LogoStore,
+	catalogStore CatalogStore,
+	catalogPolicy CatalogPolicy,
 	cache *cache.CacheOperations,
 	mqProducer *mq.Producer,
 ) *ProductLogic {
+	var tx repository.Transactor
+	if db != nil {
+		tx = repository.NewTransactor(db)
+	}
 	return &ProductLogic{
 		db:           db,
+		tx:           tx,
 		outboxRepo:   outboxRepo,
 		productRepo:  productRepo,
 		categoryRepo: categoryRepo,
 		skuRepo:      skuRepo,
 		bannerRepo:   bannerRepo,
-		cache:        cache,
-		mqProducer:   mqProducer,
+		brandRepo:    brandRepo,
+		attrRepo:     attrRepo,
+		revisionRepo: revisionRepo,
+		scheduleRepo: scheduleRepo,
+
+		catalogJobRepo: catalogJobRepo,
+		logoStore:      logoStore,
+		catalogStore:   catalogStore,
+		catalogPolicy:  catalogPolicy,
+		cache:          cache,
+		mqProducer:     mqProducer,
 	}
 }
 
@@ -156,6 +188,8 @@ func (l *ProductLogic) GetProduct(ctx context.Context, req *GetProductRequest) (
 		}
 	}
 
+	l.fillBrandNames(ctx, product)
+
 	resp := &GetProductResponse{
 		Product: product,
 		Skus:    skus,
@@ -296,6 +330,8 @@ func (l *ProductLogic) ListProducts(ctx context.Context, req *ListProductsReques
 		}
 	}
 
+	l.fillBrandNames(ctx, products...)
+
 	// 计算总页数
 	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
 
@@ -592,6 +628,9 @@ func (l *ProductLogic) CreateSku(ctx context.Context, req *CreateSkuRequest) (*C
 			if product == nil {
 				return apperrors.NewError(apperrors.CodeProductNotFound, "商品不存在")
 			}
+			if err := l.checkSkuSpecs(ctx, skuRepoTx, product, req.Specs, 0); err != nil {
+				return err
+			}
 
 			// sku_code 唯一：如果存在软删除记录，则“恢复并覆盖字段”
 			existingAny, err := skuRepoTx.GetBySkuCodeUnscoped(ctx, req.SkuCode)
@@ -733,6 +772,9 @@ func (l *ProductLogic) CreateSku(ctx context.Context, req *CreateSkuRequest) (*C
 	if product == nil {
 		return nil, apperrors.NewError(apperrors.CodeProductNotFound, "商品不存在")
 	}
+	if err := l.checkSkuSpecs(ctx, l.skuRepo, product, req.Specs, 0); err != nil {
+		return nil, err
+	}
 
 	// sku_code 唯一：如果存在软删除记录，则“恢复并覆盖字段”（用户期望：删了能用同一个 sku_code 重新添加）
 	// 如果存在未删除记录，则报已存在。
@@ -893,6 +935,13 @@ func (l *ProductLogic) UpdateSku(ctx context.Context, req *UpdateSkuRequest) (*U
 				sku.Name = req.Name
 			}
 			if len(req.Specs) > 0 {
+				product, err := repository.NewProductRepository(tx).GetByID(ctx, sku.ProductID)
+				if err != nil {
+					return apperrors.NewInternalError("查询商品失败: " + err.Error())
+				}
+				if err := l.checkSkuSpecs(ctx, skuRepoTx, product, req.Specs, sku.ID); err != nil {
+					return err
+				}
 				specsJSON, err := json.Marshal(req.Specs)
 				if err != nil {
 					return apperrors.NewInternalError("规格属性格式错误: " + err.Error())
@@ -994,6 +1043,13 @@ func (l *ProductLogic) UpdateSku(ctx context.Context, req *UpdateSkuRequest) (*U
 		sku.Name = req.Name
 	}
 	if len(req.Specs) > 0 {
+		product, err := l.productRepo.GetByID(ctx, sku.ProductID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("查询商品失败: " + err.Error())
+		}
+		if err := l.checkSkuSpecs(ctx, l.skuRepo, product, req.Specs, sku.ID); err != nil {
+			return nil, err
+		}
 		specsJSON, err := json.Marshal(req.Specs)
 		if err != nil {
 			return nil, apperrors.NewInternalError("规格属性格式错误: " + err.Error())
@@ -1312,14 +1368,17 @@ type CreateProductRequest struct {
 	Stock          int
 	Status         int8
 	IsHot          int8
+	Attrs          map[string]string // 规格参数和基础属性
+	Submit         bool              // 创建后直接提交审核
 }
 
 // CreateProductResponse 创建商品响应
 type CreateProductResponse struct {
-	Product *model.Product
+	Product  *model.Product
+	Revision *ProductRevisionDetail
 }
 
-// CreateProduct 创建商品（管理后台）
+// CreateProduct 创建商品（管理后台）。新商品为待审核状态，同时创建第一个修订，审核通过后才上架并进入搜索
 func (l *ProductLogic) CreateProduct(ctx context.Context, req *CreateProductRequest) (*CreateProductResponse, error) {
 	if l.productRepo == nil {
 		return nil, apperrors.NewInternalError("数据库连接未初始化")
@@ -1336,6 +1395,13 @@ func (l *ProductLogic) CreateProduct(ctx context.Context, req *CreateProductRequ
 		if req.Price <= 0 {
 			return nil, apperrors.NewInvalidParamError("价格必须大于0")
 		}
+		if err := l.checkProductBrand(ctx, req.BrandID); err != nil {
+			return nil, err
+		}
+		attrsJSON, err := l.normalizeProductAttrs(ctx, req.CategoryID, req.Attrs)
+		if err != nil {
+			return nil, err
+		}
 
 		// 转换图片列表为JSON
 		imagesJSON := "[]"
@@ -1372,31 +1438,30 @@ func (l *ProductLogic) CreateProduct(ctx context.Context, req *CreateProductRequ
 			Detail:         req.Detail,
 			Price:          req.Price,
 			Stock:          req.Stock,
-			Status:         req.Status,
+			Status:         model.ProductStatusPending,
+			AuditStatus:    model.RevisionStatusDraft,
 			IsHot:          req.IsHot,
+			Attrs:          attrsJSON,
 			CreatedAt:      now,
 			UpdatedAt:      now,
 		}
 		if req.OriginalPrice > 0 {
 			product.OriginalPrice = &req.OriginalPrice
 		}
+		if req.Submit {
+			product.AuditStatus = model.RevisionStatusSubmitted
+		}
 
+		// 未通过审核的商品不写 outbox 事件，审核通过时再通知搜索服务建索引
+		var revision *model.ProductRevision
 		if err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 			productRepoTx := repository.NewProductRepository(tx)
 			if err := productRepoTx.Create(ctx, product); err != nil {
 				return apperrors.NewInternalError("创建商品失败: " + err.Error())
 			}
-
-			payloadBytes, _ := json.Marshal(map[string]any{"product_id": product.ID})
-			payload := string(payloadBytes)
-			evt := &outbox.Event{
-				AggregateType: "product",
-				AggregateID:   fmt.Sprintf("%d", product.ID),
-				EventType:     outbox.EventProductUpserted,
-				Payload:       &payload,
-				Status:        outbox.StatusPending,
-			}
-			return l.outboxRepo.CreateInTx(ctx, tx, evt)
+			var err error
+			revision, err = l.createInitialRevision(ctx, repository.NewTxRepos(tx), product, req.Submit)
+			return err
 		}); err != nil {
 			return nil, err
 		}
@@ -1408,7 +1473,12 @@ func (l *ProductLogic) CreateProduct(ctx context.Context, req *CreateProductRequ
 			_ = l.cache.Delete(ctx, cacheKey)
 		}
 
-		return &CreateProductResponse{Product: product}, nil
+		l.fillBrandNames(ctx, product)
+		detail, err := l.revisionDetail(ctx, revision, product)
+		if err != nil {
+			return nil, err
+		}
+		return &CreateProductResponse{Product: product, Revision: detail}, nil
 	}
 
 	// 参数验证
@@ -1421,6 +1491,13 @@ func (l *ProductLogic) CreateProduct(ctx context.Context, req *CreateProductRequ
 	if req.Price <= 0 {
 		return nil, apperrors.NewInvalidParamError("价格必须大于0")
 	}
+	if err := l.checkProductBrand(ctx, req.BrandID); err != nil {
+		return nil, err
+	}
+	attrsJSON, err := l.normalizeProductAttrs(ctx, req.CategoryID, req.Attrs)
+	if err != nil {
+		return nil, err
+	}
 
 	// 转换图片列表为JSON
 	imagesJSON := "[]"
@@ -1461,7 +1538,9 @@ func (l *ProductLogic) CreateProduct(ctx context.Context, req *CreateProductRequ
 		Price:          req.Price,
 		Stock:          req.Stock,
 		Status:         req.Status,
+		AuditStatus:    model.RevisionStatusApproved, // 未启用 outbox 时不走审核，直接发布
 		IsHot:          req.IsHot,
+		Attrs:          attrsJSON,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
@@ -1483,6 +1562,7 @@ func (l *ProductLogic) CreateProduct(ctx context.Context, req *CreateProductRequ
 		_ = l.cache.Delete(ctx, cacheKey)
 	}
 
+	l.fillBrandNames(ctx, product)
 	return &CreateProductResponse{
 		Product: product,
 	}, nil
@@ -1505,14 +1585,18 @@ type UpdateProductRequest struct {
 	Stock          int
 	Status         int8
 	IsHot          int8
+	Attrs          map[string]string // 为 nil 表示不更新
+	Submit         bool              // 保存后直接提交审核
 }
 
 // UpdateProductResponse 更新商品响应
 type UpdateProductResponse struct {
-	Product *model.Product
+	Product  *model.Product
+	Revision *ProductRevisionDetail // 商品未通过审核的修订，没有时为 nil
 }
 
-// UpdateProduct 更新商品（管理后台）
+// UpdateProduct 更新商品（管理后台）。名称、图片、价格等内容的修改写入修订，审核通过后才生效；
+// 库存、热门和上下架直接生效
 func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequest) (*UpdateProductResponse, error) {
 	if l.productRepo == nil {
 		return nil, apperrors.NewInternalError("数据库连接未初始化")
@@ -1523,6 +1607,7 @@ func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequ
 			return nil, apperrors.NewInvalidParamError("商品ID不能为空")
 		}
 		var updated *model.Product
+		var revision *model.ProductRevision
 		if err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 			productRepoTx := repository.NewProductRepository(tx)
 			product, err := productRepoTx.GetByID(ctx, req.ID)
@@ -1536,37 +1621,9 @@ func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequ
 				return apperrors.NewError(apperrors.CodeProductNotFound, "商品不存在")
 			}
 
-			if req.Name != "" {
-				product.Name = req.Name
-			}
-			if req.Subtitle != "" {
-				product.Subtitle = req.Subtitle
-			}
-			if req.CategoryID > 0 {
-				product.CategoryID = req.CategoryID
-			}
-			if req.BrandID != nil {
-				product.BrandID = req.BrandID
-			}
-			if req.MainImage != "" {
-				product.MainImage = req.MainImage
-			}
-			if req.LocalMainImage != "" {
-				product.LocalMainImage = req.LocalMainImage
-			}
-			if req.Images != nil {
-				imagesBytes, err := json.Marshal(req.Images)
-				if err != nil {
-					return apperrors.NewInternalError("图片列表格式错误: " + err.Error())
-				}
-				product.Images = string(imagesBytes)
-			}
-			if req.LocalImages != nil {
-				localImagesBytes, err := json.Marshal(req.LocalImages)
-				if err != nil {
-					return apperrors.NewInternalError("本地图片列表格式错误: " + err.Error())
-				}
-				product.LocalImages = string(localImagesBytes)
+			revision, err = l.stageProductRevision(ctx, repository.NewTxRepos(tx), product, req)
+			if err != nil {
+				return err
 			}
 			if product.LocalImages == "" {
 				product.LocalImages = "[]"
@@ -1574,16 +1631,12 @@ func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequ
 			if product.Images == "" {
 				product.Images = "[]"
 			}
-			if req.Detail != "" {
-				product.Detail = req.Detail
-			}
-			if req.Price > 0 {
-				product.Price = req.Price
-			}
-			if req.OriginalPrice > 0 {
-				product.OriginalPrice = &req.OriginalPrice
-			}
-			if req.Status >= 0 && req.Status != -1 {
+
+			// 未通过审核的新商品在审核通过时自动上架，这之前忽略上下架设置
+			if req.Status >= 0 && req.Status != -1 && product.Status != model.ProductStatusPending {
+				if req.Status != model.ProductStatusOff && req.Status != model.ProductStatusOn {
+					return apperrors.NewInvalidParamError("商品状态只能是上架或下架")
+				}
 				product.Status = req.Status
 			}
 			if req.IsHot >= 0 && req.IsHot != -1 {
@@ -1596,6 +1649,9 @@ func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequ
 				return apperrors.NewInternalError("更新商品失败: " + err.Error())
 			}
 			updated = product
+			if product.Status == model.ProductStatusPending {
+				return nil
+			}
 
 			payloadBytes, _ := json.Marshal(map[string]any{"product_id": product.ID})
 			payload := string(payloadBytes)
@@ -1618,7 +1674,16 @@ func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequ
 			_ = l.cache.DeletePattern(ctx, cache.KeyPrefixProductList+"*")
 		}
 
-		return &UpdateProductResponse{Product: updated}, nil
+		l.fillBrandNames(ctx, updated)
+		resp := &UpdateProductResponse{Product: updated}
+		if revision != nil {
+			detail, err := l.revisionDetail(ctx, revision, updated)
+			if err != nil {
+				return nil, err
+			}
+			resp.Revision = detail
+		}
+		return resp, nil
 	}
 
 	if req.ID == 0 {
@@ -1647,10 +1712,27 @@ func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequ
 		product.Subtitle = req.Subtitle
 	}
 	// 分类ID：如果提供了且 > 0 则更新
+	categoryChanged := req.CategoryID > 0 && req.CategoryID != product.CategoryID
 	if req.CategoryID > 0 {
 		product.CategoryID = req.CategoryID
 	}
+	if req.Attrs != nil || categoryChanged {
+		attrs := req.Attrs
+		if attrs == nil {
+			attrs, _ = parseJSONMap(product.Attrs)
+		}
+		attrsJSON, err := l.normalizeProductAttrs(ctx, product.CategoryID, attrs)
+		if err != nil {
+			return nil, err
+		}
+		product.Attrs = attrsJSON
+	}
 	if req.BrandID != nil {
+		if product.BrandID == nil || *product.BrandID != *req.BrandID {
+			if err := l.checkProductBrand(ctx, req.BrandID); err != nil {
+				return nil, err
+			}
+		}
 		product.BrandID = req.BrandID
 	}
 
@@ -1723,6 +1805,7 @@ func (l *ProductLogic) UpdateProduct(ctx context.Context, req *UpdateProductRequ
 		_ = l.cache.DeletePattern(ctx, cache.KeyPrefixProductList+"*")
 	}
 
+	l.fillBrandNames(ctx, product)
 	return &UpdateProductResponse{
 		Product: product,
 	}, nil
@@ -2086,6 +2169,10 @@ func (l *ProductLogic) DeleteCategory(ctx context.Context, req *DeleteCategoryRe
 	if err := l.categoryRepo.Delete(ctx, req.ID); err != nil {
 		return nil, apperrors.NewInternalError("删除类目失败: " + err.Error())
 	}
+	// 类目的属性模板随类目一起删除
+	if l.attrRepo != nil {
+		_ = l.attrRepo.DeleteByCategoryID(ctx, req.ID)
+	}
 
 	// 清除类目树缓存（清除所有 status 的缓存）
 	l.clearCategoryTreeCache(ctx)
@@ -0,0 +1,609 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/outbox"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/product/model"
+	"ecommerce-system/internal/service/product/repository"
+
+	"gorm.io/gorm"
+)
+
+// RevisionContent 修订中保存的商品内容，审核通过后整体覆盖到商品上。
+// 库存、热门和上下架属于运营字段，直接修改商品，不走审核
+type RevisionContent struct {
+	Name           string            `json:"name"`
+	Subtitle       string            `json:"subtitle"`
+	CategoryID     uint64            `json:"category_id"`
+	BrandID        *uint64           `json:"brand_id"`
+	MainImage      string            `json:"main_image"`
+	LocalMainImage string            `json:"local_main_image"`
+	Images         []string          `json:"images"`
+	LocalImages    []string          `json:"local_images"`
+	Detail         string            `json:"detail"`
+	Price          float64           `json:"price"`
+	OriginalPrice  *float64          `json:"original_price"`
+	Attrs          map[string]string `json:"attrs"`
+}
+
+// contentFromProduct 取商品当前的内容
+func contentFromProduct(p *model.Product) *RevisionContent {
+	images, _ := parseJSONArray(p.Images)
+	localImages, _ := parseJSONArray(p.LocalImages)
+	attrs, _ := parseJSONMap(p.Attrs)
+	return &RevisionContent{
+		Name:           p.Name,
+		Subtitle:       p.Subtitle,
+		CategoryID:     p.CategoryID,
+		BrandID:        p.BrandID,
+		MainImage:      p.MainImage,
+		LocalMainImage: p.LocalMainImage,
+		Images:         images,
+		LocalImages:    localImages,
+		Detail:         p.Detail,
+		Price:          p.Price,
+		OriginalPrice:  p.OriginalPrice,
+		Attrs:          attrs,
+	}
+}
+
+// parseRevisionContent 解析修订内容
+func parseRevisionContent(s string) (*RevisionContent, error) {
+	var c RevisionContent
+	if err := json.Unmarshal([]byte(s), &c); err != nil {
+		return nil, apperrors.NewInternalError("修订内容格式错误: " + err.Error())
+	}
+	return &c, nil
+}
+
+// marshal 序列化修订内容，nil 切片和 map 统一写成空值，便于比较是否有改动
+func (c *RevisionContent) marshal() string {
+	if c.Images == nil {
+		c.Images = []string{}
+	}
+	if c.LocalImages == nil {
+		c.LocalImages = []string{}
+	}
+	if c.Attrs == nil {
+		c.Attrs = map[string]string{}
+	}
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+// applyTo 把修订内容写到商品上
+func (c *RevisionContent) applyTo(p *model.Product) {
+	p.Name = c.Name
+	p.Subtitle = c.Subtitle
+	p.CategoryID = c.CategoryID
+	p.BrandID = c.BrandID
+	p.MainImage = c.MainImage
+	p.LocalMainImage = c.LocalMainImage
+	p.Detail = c.Detail
+	p.Price = c.Price
+	p.OriginalPrice = c.OriginalPrice
+	p.Images = "[]"
+	if len(c.Images) > 0 {
+		b, _ := json.Marshal(c.Images)
+		p.Images = string(b)
+	}
+	p.LocalImages = "[]"
+	if len(c.LocalImages) > 0 {
+		b, _ := json.Marshal(c.LocalImages)
+		p.LocalImages = string(b)
+	}
+	p.Attrs = "{}"
+	if len(c.Attrs) > 0 {
+		b, _ := json.Marshal(c.Attrs)
+		p.Attrs = string(b)
+	}
+}
+
+// mergeUpdate 按更新请求修改内容，未提供的字段保持原值（与 UpdateProduct 的部分更新规则一致）
+func (c RevisionContent) mergeUpdate(req *UpdateProductRequest) *RevisionContent {
+	if req.Name != "" {
+		c.Name = req.Name
+	}
+	if req.Subtitle != "" {
+		c.Subtitle = req.Subtitle
+	}
+	if req.CategoryID > 0 {
+		c.CategoryID = req.CategoryID
+	}
+	if req.BrandID != nil {
+		c.BrandID = req.BrandID
+	}
+	if req.MainImage != "" {
+		c.MainImage = req.MainImage
+	}
+	if req.LocalMainImage != "" {
+		c.LocalMainImage = req.LocalMainImage
+	}
+	if req.Images != nil {
+		c.Images = req.Images
+	}
+	if req.LocalImages != nil {
+		c.LocalImages = req.LocalImages
+	}
+	if req.Detail != "" {
+		c.Detail = req.Detail
+	}
+	if req.Price > 0 {
+		c.Price = req.Price
+	}
+	if req.OriginalPrice > 0 {
+		originalPrice := req.OriginalPrice
+		c.OriginalPrice = &originalPrice
+	}
+	if req.Attrs != nil {
+		c.Attrs = req.Attrs
+	}
+	return &c
+}
+
+// stageProductRevision 把商品内容的修改写入修订（在事务内调用）。
+// 已有未通过的修订时在其基础上修改，否则以商品当前内容为基础新建；修改后修订回到草稿，submit 为 true 时直接提交审核。
+// 从未通过审核的新商品不对外展示，内容同时写到商品上，便于后台列表查看。返回商品当前未通过的修订（可能为 nil）
+func (l *ProductLogic) stageProductRevision(ctx context.Context, r *repository.TxRepos, product *model.Product, req *UpdateProductRequest) (*model.ProductRevision, error) {
+	rev, err := r.Revision.GetOpenByProductID(ctx, product.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询商品修订失败: " + err.Error())
+	}
+	base := contentFromProduct(product)
+	if rev != nil {
+		if base, err = parseRevisionContent(rev.Content); err != nil {
+			return nil, err
+		}
+	}
+	next := base.mergeUpdate(req)
+
+	// 修改属性或更换类目时按新类目的属性模板重新校验
+	if req.Attrs != nil || next.CategoryID != base.CategoryID {
+		attrsJSON, err := l.normalizeProductAttrs(ctx, next.CategoryID, next.Attrs)
+		if err != nil {
+			return nil, err
+		}
+		next.Attrs, _ = parseJSONMap(attrsJSON)
+	}
+	// 只在更换品牌时校验，已停用品牌下的老商品仍可编辑
+	if next.BrandID != nil && (base.BrandID == nil || *base.BrandID != *next.BrandID) {
+		if err := l.checkProductBrand(ctx, next.BrandID); err != nil {
+			return nil, err
+		}
+	}
+
+	changed := next.marshal() != base.marshal()
+	submit := req.Submit && (changed || rev == nil || rev.Status != model.RevisionStatusSubmitted)
+	if !changed && (!submit || rev == nil) {
+		return rev, nil
+	}
+
+	operatorID, _ := utils.GetUserID(ctx)
+	now := time.Now()
+	if rev == nil {
+		rev = &model.ProductRevision{
+			ProductID: product.ID,
+			CreatedBy: operatorID,
+			CreatedAt: now,
+		}
+	}
+	fromStatus := rev.Status
+	rev.Content = next.marshal()
+	rev.Status = model.RevisionStatusDraft
+	rev.SubmittedAt = nil
+	if submit {
+		rev.Status = model.RevisionStatusSubmitted
+		rev.SubmittedAt = &now
+	}
+	rev.UpdatedAt = now
+	if rev.ID == 0 {
+		if err := r.Revision.Create(ctx, rev); err != nil {
+			return nil, apperrors.NewInternalError("创建商品修订失败: " + err.Error())
+		}
+	} else {
+		ok, err := r.Revision.UpdateFromStatus(ctx, rev, fromStatus)
+		if err != nil {
+			return nil, apperrors.NewInternalError("更新商品修订失败: " + err.Error())
+		}
+		if !ok {
+			return nil, apperrors.NewInvalidParamError("修订已被审核，请刷新后重试")
+		}
+	}
+
+	if changed {
+		if err := l.writeAuditLog(ctx, r, rev, model.AuditActionEdit, operatorID, ""); err != nil {
+			return nil, err
+		}
+	}
+	if submit {
+		if err := l.writeAuditLog(ctx, r, rev, model.AuditActionSubmit, operatorID, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	product.AuditStatus = rev.Status
+	if product.Status == model.ProductStatusPending {
+		next.applyTo(product)
+	}
+	return rev, nil
+}
+
+// createInitialRevision 为新建的商品创建第一个修订（在事务内调用）
+func (l *ProductLogic) createInitialRevision(ctx context.Context, r *repository.TxRepos, product *model.Product, submit bool) (*model.ProductRevision, error) {
+	operatorID, _ := utils.GetUserID(ctx)
+	rev := &model.ProductRevision{
+		ProductID: product.ID,
+		Content:   contentFromProduct(product).marshal(),
+		Status:    model.RevisionStatusDraft,
+		CreatedBy: operatorID,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.CreatedAt,
+	}
+	if submit {
+		rev.Status = model.RevisionStatusSubmitted
+		rev.SubmittedAt = &product.CreatedAt
+	}
+	if err := r.Revision.Create(ctx, rev); err != nil {
+		return nil, apperrors.NewInternalError("创建商品修订失败: " + err.Error())
+	}
+	if err := l.writeAuditLog(ctx, r, rev, model.AuditActionCreate, operatorID, ""); err != nil {
+		return nil, err
+	}
+	if submit {
+		if err := l.writeAuditLog(ctx, r, rev, model.AuditActionSubmit, operatorID, ""); err != nil {
+			return nil, err
+		}
+	}
+	return rev, nil
+}
+
+// writeAuditLog 写入审核日志（在事务内调用）
+func (l *ProductLogic) writeAuditLog(ctx context.Context, r *repository.TxRepos, rev *model.ProductRevision, action string, operatorID uint64, comment string) error {
+	log := &model.ProductAuditLog{
+		ProductID:  rev.ProductID,
+		RevisionID: rev.ID,
+		Action:     action,
+		OperatorID: operatorID,
+		Comment:    comment,
+		CreatedAt:  time.Now(),
+	}
+	if err := r.Revision.CreateLog(ctx, log); err != nil {
+		return apperrors.NewInternalError("写入审核日志失败: " + err.Error())
+	}
+	return nil
+}
+
+// writeOutboxEvents 写 outbox 事件（在事务内调用），未配置 outbox 或不在数据库事务中时跳过
+func (l *ProductLogic) writeOutboxEvents(ctx context.Context, r *repository.TxRepos, events ...*outbox.Event) error {
+	if l.outboxRepo == nil || r.DB == nil {
+		return nil
+	}
+	for _, evt := range events {
+		if err := l.outboxRepo.CreateInTx(ctx, r.DB, evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProductRevisionDetail 修订详情，Product 为应用修订内容后的商品（库存、状态等运营字段取当前值）
+type ProductRevisionDetail struct {
+	*model.ProductRevision
+	Product *model.Product
+}
+
+// SubmitProductRevision 提交商品未通过的修订（草稿或已驳回）等待审核
+func (l *ProductLogic) SubmitProductRevision(ctx context.Context, productID uint64) (*ProductRevisionDetail, error) {
+	if l.tx == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if productID == 0 {
+		return nil, apperrors.NewInvalidParamError("商品ID不能为空")
+	}
+
+	var rev *model.ProductRevision
+	var product *model.Product
+	if err := l.tx.Transaction(ctx, func(r *repository.TxRepos) error {
+		var err error
+		product, err = l.getProductInTx(ctx, r, productID)
+		if err != nil {
+			return err
+		}
+		rev, err = r.Revision.GetOpenByProductID(ctx, productID)
+		if err != nil {
+			return apperrors.NewInternalError("查询商品修订失败: " + err.Error())
+		}
+		if rev == nil {
+			return apperrors.NewInvalidParamError("商品没有待提交的修改")
+		}
+		if rev.Status == model.RevisionStatusSubmitted {
+			return apperrors.NewInvalidParamError("修订已提交，请等待审核")
+		}
+		// 提交前按当前属性模板和品牌状态校验，避免审核时才发现内容不合法
+		content, err := parseRevisionContent(rev.Content)
+		if err != nil {
+			return err
+		}
+		if _, err := l.normalizeProductAttrs(ctx, content.CategoryID, content.Attrs); err != nil {
+			return err
+		}
+		if content.BrandID != nil && (product.BrandID == nil || *product.BrandID != *content.BrandID) {
+			if err := l.checkProductBrand(ctx, content.BrandID); err != nil {
+				return err
+			}
+		}
+		return l.transitRevision(ctx, r, product, rev, model.RevisionStatusSubmitted, model.AuditActionSubmit, "")
+	}); err != nil {
+		return nil, err
+	}
+
+	l.clearProductCaches(ctx, []uint64{productID})
+	return l.revisionDetail(ctx, rev, product)
+}
+
+// ReviewProductRevisionRequest 审核商品修订请求
+type ReviewProductRevisionRequest struct {
+	ID      uint64
+	Comment string
+}
+
+// ApproveProductRevision 审核通过：把修订内容应用到商品上，新商品同时上架，
+// 并写 product.upserted 事件，由搜索服务按审核通过的内容重建文档
+func (l *ProductLogic) ApproveProductRevision(ctx context.Context, req *ReviewProductRevisionRequest) (*ProductRevisionDetail, error) {
+	if l.tx == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if len([]rune(comment)) > 500 {
+		return nil, apperrors.NewInvalidParamError("审核意见不能超过500个字符")
+	}
+
+	var rev *model.ProductRevision
+	var product *model.Product
+	if err := l.tx.Transaction(ctx, func(r *repository.TxRepos) error {
+		var err error
+		rev, product, err = l.getSubmittedRevision(ctx, r, req.ID)
+		if err != nil {
+			return err
+		}
+		content, err := parseRevisionContent(rev.Content)
+		if err != nil {
+			return err
+		}
+		// 属性模板或品牌可能在提交后有变化，应用前再校验一次
+		attrsJSON, err := l.normalizeProductAttrs(ctx, content.CategoryID, content.Attrs)
+		if err != nil {
+			return err
+		}
+		content.Attrs, _ = parseJSONMap(attrsJSON)
+		if content.BrandID != nil && (product.BrandID == nil || *product.BrandID != *content.BrandID) {
+			if err := l.checkProductBrand(ctx, content.BrandID); err != nil {
+				return err
+			}
+		}
+
+		content.applyTo(product)
+		if product.Status == model.ProductStatusPending {
+			product.Status = model.ProductStatusOn
+		}
+		if err := l.transitRevision(ctx, r, product, rev, model.RevisionStatusApproved, model.AuditActionApprove, comment); err != nil {
+			return err
+		}
+		return l.writeOutboxEvents(ctx, r, productUpsertedEvent(product.ID))
+	}); err != nil {
+		return nil, err
+	}
+
+	l.clearProductCaches(ctx, []uint64{product.ID})
+	return l.revisionDetail(ctx, rev, product)
+}
+
+// RejectProductRevision 审核驳回，必须填写审核意见；商品线上内容不变
+func (l *ProductLogic) RejectProductRevision(ctx context.Context, req *ReviewProductRevisionRequest) (*ProductRevisionDetail, error) {
+	if l.tx == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if comment == "" {
+		return nil, apperrors.NewInvalidParamError("驳回时必须填写审核意见")
+	}
+	if len([]rune(comment)) > 500 {
+		return nil, apperrors.NewInvalidParamError("审核意见不能超过500个字符")
+	}
+
+	var rev *model.ProductRevision
+	var product *model.Product
+	if err := l.tx.Transaction(ctx, func(r *repository.TxRepos) error {
+		var err error
+		rev, product, err = l.getSubmittedRevision(ctx, r, req.ID)
+		if err != nil {
+			return err
+		}
+		return l.transitRevision(ctx, r, product, rev, model.RevisionStatusRejected, model.AuditActionReject, comment)
+	}); err != nil {
+		return nil, err
+	}
+
+	l.clearProductCaches(ctx, []uint64{product.ID})
+	return l.revisionDetail(ctx, rev, product)
+}
+
+// getSubmittedRevision 获取待审核的修订及其商品（在事务内调用）
+func (l *ProductLogic) getSubmittedRevision(ctx context.Context, r *repository.TxRepos, id uint64) (*model.ProductRevision, *model.Product, error) {
+	if id == 0 {
+		return nil, nil, apperrors.NewInvalidParamError("修订ID不能为空")
+	}
+	rev, err := r.Revision.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperrors.NewError(apperrors.CodeNotFound, "修订不存在")
+		}
+		return nil, nil, apperrors.NewInternalError("查询商品修订失败: " + err.Error())
+	}
+	if rev.Status != model.RevisionStatusSubmitted {
+		return nil, nil, apperrors.NewInvalidParamError("修订不是待审核状态")
+	}
+	product, err := l.getProductInTx(ctx, r, rev.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rev, product, nil
+}
+
+// getProductInTx 在事务内获取商品
+func (l *ProductLogic) getProductInTx(ctx context.Context, r *repository.TxRepos, id uint64) (*model.Product, error) {
+	product, err := r.Product.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewError(apperrors.CodeProductNotFound, "商品不存在")
+		}
+		return nil, apperrors.NewInternalError("查询商品失败: " + err.Error())
+	}
+	return product, nil
+}
+
+// transitRevision 修改修订状态，同步商品的审核状态并写审核日志（在事务内调用）。
+// 修订状态按条件更新，并发审核同一修订时只有一个成功
+func (l *ProductLogic) transitRevision(ctx context.Context, r *repository.TxRepos, product *model.Product, rev *model.ProductRevision, status int8, action, comment string) error {
+	operatorID, _ := utils.GetUserID(ctx)
+	now := time.Now()
+	fromStatus := rev.Status
+	rev.Status = status
+	rev.UpdatedAt = now
+	if status == model.RevisionStatusSubmitted {
+		rev.SubmittedAt = &now
+	} else {
+		rev.ReviewedBy = operatorID
+		rev.ReviewComment = comment
+		rev.ReviewedAt = &now
+		product.AuditComment = comment
+	}
+	ok, err := r.Revision.UpdateFromStatus(ctx, rev, fromStatus)
+	if err != nil {
+		return apperrors.NewInternalError("更新商品修订失败: " + err.Error())
+	}
+	if !ok {
+		return apperrors.NewInvalidParamError("修订状态已变化，请刷新后重试")
+	}
+
+	product.AuditStatus = status
+	product.UpdatedAt = now
+	if err := r.Product.Update(ctx, product); err != nil {
+		return apperrors.NewInternalError("更新商品失败: " + err.Error())
+	}
+	return l.writeAuditLog(ctx, r, rev, action, operatorID, comment)
+}
+
+// revisionDetail 组装修订详情
+func (l *ProductLogic) revisionDetail(ctx context.Context, rev *model.ProductRevision, product *model.Product) (*ProductRevisionDetail, error) {
+	content, err := parseRevisionContent(rev.Content)
+	if err != nil {
+		return nil, err
+	}
+	p := &model.Product{ID: rev.ProductID}
+	if product != nil {
+		copied := *product
+		p = &copied
+	}
+	content.applyTo(p)
+	l.fillBrandNames(ctx, p)
+	return &ProductRevisionDetail{ProductRevision: rev, Product: p}, nil
+}
+
+// ListProductRevisionsRequest 获取商品修订列表请求
+type ListProductRevisionsRequest struct {
+	ProductID uint64 // 0 表示全部商品
+	Status    int8   // -1-全部, 0-草稿, 1-待审核, 2-已通过, 3-已驳回
+	Page      int
+	PageSize  int
+}
+
+// ListProductRevisionsResponse 获取商品修订列表响应
+type ListProductRevisionsResponse struct {
+	Revisions []*ProductRevisionDetail
+	Total     int64
+}
+
+// ListProductRevisions 获取商品修订列表（审核队列按 status=1 查询）
+func (l *ProductLogic) ListProductRevisions(ctx context.Context, req *ListProductRevisionsRequest) (*ListProductRevisionsResponse, error) {
+	if l.revisionRepo == nil || l.productRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	revs, total, err := l.revisionRepo.List(ctx, req.ProductID, req.Status, req.Page, req.PageSize)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询商品修订失败: " + err.Error())
+	}
+	ids := make([]uint64, 0, len(revs))
+	for _, rev := range revs {
+		ids = append(ids, rev.ProductID)
+	}
+	products, err := l.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询商品失败: " + err.Error())
+	}
+
+	details := make([]*ProductRevisionDetail, 0, len(revs))
+	for _, rev := range revs {
+		detail, err := l.revisionDetail(ctx, rev, products[rev.ProductID])
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+	return &ListProductRevisionsResponse{
+		Revisions: details,
+		Total:     total,
+	}, nil
+}
+
+// ListProductAuditLogsRequest 获取商品审核日志请求
+type ListProductAuditLogsRequest struct {
+	ProductID uint64
+	Page      int
+	PageSize  int
+}
+
+// ListProductAuditLogsResponse 获取商品审核日志响应
+type ListProductAuditLogsResponse struct {
+	Logs  []*model.ProductAuditLog
+	Total int64
+}
+
+// ListProductAuditLogs 获取商品审核日志
+func (l *ProductLogic) ListProductAuditLogs(ctx context.Context, req *ListProductAuditLogsRequest) (*ListProductAuditLogsResponse, error) {
+	if l.revisionRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.ProductID == 0 {
+		return nil, apperrors.NewInvalidParamError("商品ID不能为空")
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	logs, total, err := l.revisionRepo.ListLogs(ctx, req.ProductID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询审核日志失败: " + err.Error())
+	}
+	return &ListProductAuditLogsResponse{
+		Logs:  logs,
+		Total: total,
+	}, nil
+}
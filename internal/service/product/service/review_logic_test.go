@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/product/model"
+)
+
+// newRevision 创建修订，content 为修订后的商品
+func newRevision(id uint64, content *model.Product, status int8) *model.ProductRevision {
+	return &model.ProductRevision{
+		ID:        id,
+		ProductID: content.ID,
+		Content:   contentFromProduct(content).marshal(),
+		Status:    status,
+	}
+}
+
+// newReviewTestLogic 商品 1 已上架，修订 1 待审核（改名并调价）；商品 2 是从未通过审核的新商品，修订 2 待审核
+func newReviewTestLogic() (*ProductLogic, *memProductRepo, *memRevisionRepo) {
+	env := newProductTestEnv()
+	env.categories.seed(&model.Category{ID: 1, Name: "服装", Level: 1})
+	env.products.seed(
+		&model.Product{ID: 1, CategoryID: 1, Name: "旧名称", Price: 10, Status: model.ProductStatusOn, AuditStatus: model.RevisionStatusSubmitted},
+		&model.Product{ID: 2, CategoryID: 1, Name: "新商品", Price: 20, Status: model.ProductStatusPending, AuditStatus: model.RevisionStatusSubmitted},
+	)
+	env.revisions.seed(
+		newRevision(1, &model.Product{ID: 1, CategoryID: 1, Name: "新名称", Price: 12}, model.RevisionStatusSubmitted),
+		newRevision(2, &model.Product{ID: 2, CategoryID: 1, Name: "新商品", Price: 20}, model.RevisionStatusSubmitted),
+	)
+	return env.logic, env.products, env.revisions
+}
+
+// auditActions 按写入顺序列出商品的审核日志动作
+func auditActions(t *testing.T, logic *ProductLogic, productID uint64) []string {
+	t.Helper()
+	resp, err := logic.ListProductAuditLogs(context.Background(), &ListProductAuditLogsRequest{ProductID: productID})
+	if err != nil {
+		t.Fatalf("ListProductAuditLogs: %v", err)
+	}
+	actions := make([]string, 0, len(resp.Logs))
+	for i := len(resp.Logs) - 1; i >= 0; i-- {
+		actions = append(actions, resp.Logs[i].Action)
+	}
+	return actions
+}
+
+func TestApproveProductRevision(t *testing.T) {
+	logic, products, revisions := newReviewTestLogic()
+	ctx := utils.WithUserID(context.Background(), 7)
+
+	detail, err := logic.ApproveProductRevision(ctx, &ReviewProductRevisionRequest{ID: 1, Comment: " 同意 "})
+	if err != nil {
+		t.Fatalf("ApproveProductRevision: %v", err)
+	}
+	if detail.Status != model.RevisionStatusApproved || detail.Product.Name != "新名称" {
+		t.Fatalf("unexpected detail: %+v / %+v", detail.ProductRevision, detail.Product)
+	}
+
+	// 修订内容应用到线上商品，上下架状态不变
+	p := products.rows[1]
+	if p.Name != "新名称" || p.Price != 12 || p.Status != model.ProductStatusOn {
+		t.Fatalf("revision not applied: %+v", p)
+	}
+	if p.AuditStatus != model.RevisionStatusApproved || p.AuditComment != "同意" {
+		t.Fatalf("audit status = %d comment %q", p.AuditStatus, p.AuditComment)
+	}
+	rev := revisions.rows[1]
+	if rev.Status != model.RevisionStatusApproved || rev.ReviewedBy != 7 || rev.ReviewComment != "同意" || rev.ReviewedAt == nil {
+		t.Fatalf("unexpected revision after approve: %+v", rev)
+	}
+
+	// 新商品第一次通过审核时上架
+	if _, err := logic.ApproveProductRevision(ctx, &ReviewProductRevisionRequest{ID: 2}); err != nil {
+		t.Fatalf("ApproveProductRevision new product: %v", err)
+	}
+	if p := products.rows[2]; p.Status != model.ProductStatusOn {
+		t.Fatalf("new product status = %d, want on", p.Status)
+	}
+
+	_, err = logic.ApproveProductRevision(ctx, &ReviewProductRevisionRequest{ID: 99})
+	assertBizCode(t, err, apperrors.CodeNotFound)
+}
+
+func TestRejectProductRevisionKeepsLiveProduct(t *testing.T) {
+	logic, products, revisions := newReviewTestLogic()
+	ctx := utils.WithUserID(context.Background(), 7)
+	before := *products.rows[1]
+
+	_, err := logic.RejectProductRevision(ctx, &ReviewProductRevisionRequest{ID: 1, Comment: "  "})
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+
+	detail, err := logic.RejectProductRevision(ctx, &ReviewProductRevisionRequest{ID: 1, Comment: "图片不清晰"})
+	if err != nil {
+		t.Fatalf("RejectProductRevision: %v", err)
+	}
+	// 详情展示的是修订内容，线上商品保持原样
+	if detail.Status != model.RevisionStatusRejected || detail.Product.Name != "新名称" {
+		t.Fatalf("unexpected detail: %+v / %+v", detail.ProductRevision, detail.Product)
+	}
+	p := products.rows[1]
+	if p.Name != before.Name || p.Price != before.Price || p.Status != before.Status {
+		t.Fatalf("live product changed by reject: %+v", p)
+	}
+	if p.AuditStatus != model.RevisionStatusRejected || p.AuditComment != "图片不清晰" {
+		t.Fatalf("audit status = %d comment %q", p.AuditStatus, p.AuditComment)
+	}
+	if rev := revisions.rows[1]; rev.Status != model.RevisionStatusRejected || rev.ReviewComment != "图片不清晰" {
+		t.Fatalf("unexpected revision after reject: %+v", rev)
+	}
+
+	// 驳回的新商品仍不上架
+	if _, err := logic.RejectProductRevision(ctx, &ReviewProductRevisionRequest{ID: 2, Comment: "信息不全"}); err != nil {
+		t.Fatalf("RejectProductRevision new product: %v", err)
+	}
+	if p := products.rows[2]; p.Status != model.ProductStatusPending {
+		t.Fatalf("rejected new product status = %d, want pending", p.Status)
+	}
+}
+
+func TestReviewDecidedRevisionFails(t *testing.T) {
+	logic, products, revisions := newReviewTestLogic()
+	ctx := utils.WithUserID(context.Background(), 7)
+
+	if _, err := logic.ApproveProductRevision(ctx, &ReviewProductRevisionRequest{ID: 1}); err != nil {
+		t.Fatalf("ApproveProductRevision: %v", err)
+	}
+	if _, err := logic.RejectProductRevision(ctx, &ReviewProductRevisionRequest{ID: 2, Comment: "信息不全"}); err != nil {
+		t.Fatalf("RejectProductRevision: %v", err)
+	}
+	approved, rejected := *products.rows[1], *products.rows[2]
+	logCount := len(revisions.logs)
+
+	for _, id := range []uint64{1, 2} {
+		_, err := logic.ApproveProductRevision(ctx, &ReviewProductRevisionRequest{ID: id})
+		assertBizCode(t, err, apperrors.CodeInvalidParam)
+		_, err = logic.RejectProductRevision(ctx, &ReviewProductRevisionRequest{ID: id, Comment: "重复审核"})
+		assertBizCode(t, err, apperrors.CodeInvalidParam)
+	}
+	if *products.rows[1] != approved || *products.rows[2] != rejected {
+		t.Fatal("products changed by reviewing a decided revision")
+	}
+	if len(revisions.logs) != logCount {
+		t.Fatalf("failed reviews wrote %d audit logs", len(revisions.logs)-logCount)
+	}
+}
+
+func TestReviewActionsWriteAuditLog(t *testing.T) {
+	logic, _, revisions := newReviewTestLogic()
+	ctx := utils.WithUserID(context.Background(), 7)
+
+	if _, err := logic.RejectProductRevision(ctx, &ReviewProductRevisionRequest{ID: 1, Comment: "价格有误"}); err != nil {
+		t.Fatalf("RejectProductRevision: %v", err)
+	}
+	// 已驳回的修订重新提交
+	if _, err := logic.SubmitProductRevision(ctx, 1); err != nil {
+		t.Fatalf("SubmitProductRevision: %v", err)
+	}
+	_, err := logic.SubmitProductRevision(ctx, 1)
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+	if _, err := logic.ApproveProductRevision(ctx, &ReviewProductRevisionRequest{ID: 1, Comment: "通过"}); err != nil {
+		t.Fatalf("ApproveProductRevision: %v", err)
+	}
+	// 没有未通过的修订时无法提交
+	_, err = logic.SubmitProductRevision(ctx, 1)
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+
+	got := auditActions(t, logic, 1)
+	want := []string{model.AuditActionReject, model.AuditActionSubmit, model.AuditActionApprove}
+	if len(got) != len(want) {
+		t.Fatalf("audit actions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("audit actions = %v, want %v", got, want)
+		}
+	}
+	comments := []string{"价格有误", "", "通过"}
+	for i, log := range revisions.logs {
+		if log.RevisionID != 1 || log.OperatorID != 7 || log.Comment != comments[i] {
+			t.Fatalf("unexpected audit log %d: %+v", i, log)
+		}
+	}
+	if actions := auditActions(t, logic, 2); len(actions) != 0 {
+		t.Fatalf("unexpected audit logs for product 2: %v", actions)
+	}
+}
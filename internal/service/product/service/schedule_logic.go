@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce-system/internal/pkg/cache"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/outbox"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/product/model"
+	"ecommerce-system/internal/service/product/repository"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// scheduleExecuteBatch 每轮最多执行的到期任务数
+const scheduleExecuteBatch = 100
+
+// errScheduleTaken 任务已被其他执行者处理或已取消
+var errScheduleTaken = errors.New("schedule taken")
+
+// SchedulePricePayload 改价任务的参数
+type SchedulePricePayload struct {
+	Price         float64  `json:"price"`
+	OriginalPrice *float64 `json:"original_price,omitempty"` // 为 nil 表示不修改原价
+}
+
+// CreateProductScheduleRequest 创建商品定时任务请求
+type CreateProductScheduleRequest struct {
+	TargetType    string // product / sku
+	TargetID      uint64
+	Action        string // list / delist / price
+	Price         float64
+	OriginalPrice float64 // 0 表示不修改原价
+	RunAt         time.Time
+}
+
+// CreateProductSchedule 创建商品定时任务（管理后台），到执行时间后由调度器执行
+func (l *ProductLogic) CreateProductSchedule(ctx context.Context, req *CreateProductScheduleRequest) (*model.ProductSchedule, error) {
+	if l.scheduleRepo == nil || l.productRepo == nil || l.skuRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.TargetID == 0 {
+		return nil, apperrors.NewInvalidParamError("目标ID不能为空")
+	}
+	if req.RunAt.IsZero() {
+		return nil, apperrors.NewInvalidParamError("执行时间不能为空")
+	}
+	if !req.RunAt.After(time.Now()) {
+		return nil, apperrors.NewInvalidParamError("执行时间必须晚于当前时间")
+	}
+
+	payload := "{}"
+	switch req.Action {
+	case model.ScheduleActionList, model.ScheduleActionDelist:
+	case model.ScheduleActionPrice:
+		if req.Price <= 0 {
+			return nil, apperrors.NewInvalidParamError("价格必须大于0")
+		}
+		if req.OriginalPrice < 0 {
+			return nil, apperrors.NewInvalidParamError("原价不能小于0")
+		}
+		p := SchedulePricePayload{Price: req.Price}
+		if req.OriginalPrice > 0 {
+			originalPrice := req.OriginalPrice
+			p.OriginalPrice = &originalPrice
+		}
+		b, _ := json.Marshal(p)
+		payload = string(b)
+	default:
+		return nil, apperrors.NewInvalidParamError("不支持的任务动作: " + req.Action)
+	}
+
+	var productID uint64
+	switch req.TargetType {
+	case model.ScheduleTargetProduct:
+		product, err := l.productRepo.GetByID(ctx, req.TargetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.NewError(apperrors.CodeProductNotFound, "商品不存在")
+			}
+			return nil, apperrors.NewInternalError("查询商品失败: " + err.Error())
+		}
+		productID = product.ID
+	case model.ScheduleTargetSku:
+		sku, err := l.skuRepo.GetByID(ctx, req.TargetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.NewError(apperrors.CodeSkuNotFound, "SKU不存在")
+			}
+			return nil, apperrors.NewInternalError("查询SKU失败: " + err.Error())
+		}
+		productID = sku.ProductID
+	default:
+		return nil, apperrors.NewInvalidParamError("不支持的目标类型: " + req.TargetType)
+	}
+
+	operatorID, _ := utils.GetUserID(ctx)
+	now := time.Now()
+	schedule := &model.ProductSchedule{
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		ProductID:  productID,
+		Action:     req.Action,
+		Payload:    payload,
+		RunAt:      req.RunAt,
+		Status:     model.ScheduleStatusPending,
+		CreatedBy:  operatorID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := l.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, apperrors.NewInternalError("创建定时任务失败: " + err.Error())
+	}
+	return schedule, nil
+}
+
+// ListProductSchedulesRequest 获取商品定时任务列表请求
+type ListProductSchedulesRequest struct {
+	ProductID uint64 // 0 表示全部商品
+	Status    int8   // -1-全部, 0-待执行, 1-已执行, 2-已取消, 3-执行失败
+	Page      int
+	PageSize  int
+}
+
+// ListProductSchedulesResponse 获取商品定时任务列表响应
+type ListProductSchedulesResponse struct {
+	Schedules []*model.ProductSchedule
+	Total     int64
+}
+
+// ListProductSchedules 获取商品定时任务列表（管理后台），按执行时间升序
+func (l *ProductLogic) ListProductSchedules(ctx context.Context, req *ListProductSchedulesRequest) (*ListProductSchedulesResponse, error) {
+	if l.scheduleRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	schedules, total, err := l.scheduleRepo.List(ctx, req.ProductID, req.Status, req.Page, req.PageSize)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询定时任务失败: " + err.Error())
+	}
+	return &ListProductSchedulesResponse{
+		Schedules: schedules,
+		Total:     total,
+	}, nil
+}
+
+// CancelProductSchedule 取消待执行的定时任务（管理后台）。与执行并发时只有一方成功
+func (l *ProductLogic) CancelProductSchedule(ctx context.Context, id uint64) (*model.ProductSchedule, error) {
+	if l.scheduleRepo == nil {
+		return nil, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	if id == 0 {
+		return nil, apperrors.NewInvalidParamError("任务ID不能为空")
+	}
+
+	schedule, err := l.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewError(apperrors.CodeNotFound, "定时任务不存在")
+		}
+		return nil, apperrors.NewInternalError("查询定时任务失败: " + err.Error())
+	}
+	ok, err := l.scheduleRepo.UpdateStatus(ctx, id, model.ScheduleStatusPending, model.ScheduleStatusCanceled, "")
+	if err != nil {
+		return nil, apperrors.NewInternalError("取消定时任务失败: " + err.Error())
+	}
+	if !ok {
+		return nil, apperrors.NewInvalidParamError("只能取消待执行的任务")
+	}
+	schedule.Status = model.ScheduleStatusCanceled
+	schedule.UpdatedAt = time.Now()
+	return schedule, nil
+}
+
+// ExecuteDueSchedules 执行已到期的定时任务，返回执行成功的任务数。单个任务失败只记录日志
+func (l *ProductLogic) ExecuteDueSchedules(ctx context.Context) (int, error) {
+	if l.scheduleRepo == nil || l.tx == nil {
+		return 0, apperrors.NewInternalError("数据库连接未初始化")
+	}
+	schedules, err := l.scheduleRepo.ListDue(ctx, time.Now(), scheduleExecuteBatch)
+	if err != nil {
+		return 0, apperrors.NewInternalError("查询到期定时任务失败: " + err.Error())
+	}
+
+	executed := 0
+	for _, s := range schedules {
+		err := l.executeSchedule(ctx, s)
+		if err == nil {
+			executed++
+			continue
+		}
+		if errors.Is(err, errScheduleTaken) {
+			continue
+		}
+		// 业务错误（目标已删除、待审核商品不能上架等）重试也不会成功，标记为失败；其他错误留到下一轮重试
+		var bizErr *apperrors.BusinessError
+		if errors.As(err, &bizErr) && bizErr.Code != apperrors.CodeInternalError {
+			if _, uerr := l.scheduleRepo.UpdateStatus(ctx, s.ID, model.ScheduleStatusPending, model.ScheduleStatusFailed, bizErr.Message); uerr != nil {
+				logx.WithContext(ctx).Errorf("标记定时任务失败出错: id=%d err=%v", s.ID, uerr)
+			}
+			logx.WithContext(ctx).Infof("定时任务执行失败: id=%d err=%s", s.ID, bizErr.Message)
+			continue
+		}
+		logx.WithContext(ctx).Errorf("定时任务执行出错，稍后重试: id=%d err=%v", s.ID, err)
+	}
+	return executed, nil
+}
+
+// executeSchedule 执行单个定时任务。任务状态从待执行改为已执行与商品修改、outbox 事件在同一事务中，
+// 多个执行者或与取消并发时只有一方能改到状态，保证每个任务只生效一次
+func (l *ProductLogic) executeSchedule(ctx context.Context, s *model.ProductSchedule) error {
+	var skuID uint64
+	if err := l.tx.Transaction(ctx, func(r *repository.TxRepos) error {
+		ok, err := r.Schedule.UpdateStatus(ctx, s.ID, model.ScheduleStatusPending, model.ScheduleStatusDone, "")
+		if err != nil {
+			return apperrors.NewInternalError("更新定时任务失败: " + err.Error())
+		}
+		if !ok {
+			return errScheduleTaken
+		}
+
+		var events []*outbox.Event
+		switch s.TargetType {
+		case model.ScheduleTargetProduct:
+			events, err = l.applyProductSchedule(ctx, r, s)
+		case model.ScheduleTargetSku:
+			skuID = s.TargetID
+			events, err = l.applySkuSchedule(ctx, r, s)
+		default:
+			err = apperrors.NewInvalidParamError("不支持的目标类型: " + s.TargetType)
+		}
+		if err != nil {
+			return err
+		}
+		return l.writeOutboxEvents(ctx, r, events...)
+	}); err != nil {
+		return err
+	}
+
+	if skuID > 0 && l.cache != nil {
+		_ = l.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixSkuInfo, skuID))
+	}
+	l.clearProductCaches(ctx, []uint64{s.ProductID})
+	return nil
+}
+
+// applyProductSchedule 对商品执行定时任务（在事务内调用），返回需要写入的 outbox 事件
+func (l *ProductLogic) applyProductSchedule(ctx context.Context, r *repository.TxRepos, s *model.ProductSchedule) ([]*outbox.Event, error) {
+	product, err := l.getProductInTx(ctx, r, s.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*outbox.Event
+	switch s.Action {
+	case model.ScheduleActionList, model.ScheduleActionDelist:
+		if product.Status == model.ProductStatusPending {
+			return nil, apperrors.NewInvalidParamError("商品尚未通过审核，不能上下架")
+		}
+		product.Status = model.ProductStatusOff
+		if s.Action == model.ScheduleActionList {
+			product.Status = model.ProductStatusOn
+		}
+	case model.ScheduleActionPrice:
+		p, err := parseSchedulePricePayload(s.Payload)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, priceChangedEvent(s, product.ID, 0, product.Price, p.Price, product.OriginalPrice, p.OriginalPrice))
+		if err := l.carryPriceToOpenRevision(ctx, r, product, p); err != nil {
+			return nil, err
+		}
+		product.Price = p.Price
+		if p.OriginalPrice != nil {
+			product.OriginalPrice = p.OriginalPrice
+		}
+	default:
+		return nil, apperrors.NewInvalidParamError("不支持的任务动作: " + s.Action)
+	}
+
+	product.UpdatedAt = time.Now()
+	if err := r.Product.Update(ctx, product); err != nil {
+		return nil, apperrors.NewInternalError("更新商品失败: " + err.Error())
+	}
+	if product.Status == model.ProductStatusPending {
+		return events, nil
+	}
+	return append(events, productUpsertedEvent(product.ID)), nil
+}
+
+// carryPriceToOpenRevision 商品有未通过的修订且修订没有改过价格时，把定时改价同步到修订，
+// 避免之后审核通过时用修订里的旧价格覆盖掉定时改价（在事务内调用）
+func (l *ProductLogic) carryPriceToOpenRevision(ctx context.Context, r *repository.TxRepos, product *model.Product, p *SchedulePricePayload) error {
+	rev, err := r.Revision.GetOpenByProductID(ctx, product.ID)
+	if err != nil {
+		return apperrors.NewInternalError("查询商品修订失败: " + err.Error())
+	}
+	if rev == nil {
+		return nil
+	}
+	content, err := parseRevisionContent(rev.Content)
+	if err != nil {
+		return err
+	}
+	if content.Price != product.Price {
+		return nil
+	}
+	content.Price = p.Price
+	if p.OriginalPrice != nil {
+		content.OriginalPrice = p.OriginalPrice
+	}
+	rev.Content = content.marshal()
+	rev.UpdatedAt = time.Now()
+	if _, err := r.Revision.UpdateFromStatus(ctx, rev, rev.Status); err != nil {
+		return apperrors.NewInternalError("更新商品修订失败: " + err.Error())
+	}
+	return nil
+}
+
+// applySkuSchedule 对 SKU 执行定时任务（在事务内调用），返回需要写入的 outbox 事件
+func (l *ProductLogic) applySkuSchedule(ctx context.Context, r *repository.TxRepos, s *model.ProductSchedule) ([]*outbox.Event, error) {
+	sku, err := r.Sku.GetByID(ctx, s.TargetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewError(apperrors.CodeSkuNotFound, "SKU不存在")
+		}
+		return nil, apperrors.NewInternalError("查询SKU失败: " + err.Error())
+	}
+
+	var events []*outbox.Event
+	switch s.Action {
+	case model.ScheduleActionList:
+		sku.Status = 1 // 上架
+	case model.ScheduleActionDelist:
+		sku.Status = 0 // 下架
+	case model.ScheduleActionPrice:
+		p, err := parseSchedulePricePayload(s.Payload)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, priceChangedEvent(s, sku.ProductID, sku.ID, sku.Price, p.Price, sku.OriginalPrice, p.OriginalPrice))
+		sku.Price = p.Price
+		if p.OriginalPrice != nil {
+			sku.OriginalPrice = p.OriginalPrice
+		}
+	default:
+		return nil, apperrors.NewInvalidParamError("不支持的任务动作: " + s.Action)
+	}
+
+	sku.UpdatedAt = time.Now()
+	if err := r.Sku.Update(ctx, sku); err != nil {
+		return nil, apperrors.NewInternalError("更新SKU失败: " + err.Error())
+	}
+	return append(events, productUpsertedEvent(sku.ProductID)), nil
+}
+
+// parseSchedulePricePayload 解析改价任务参数
+func parseSchedulePricePayload(s string) (*SchedulePricePayload, error) {
+	var p SchedulePricePayload
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		return nil, apperrors.NewInvalidParamError("改价参数格式错误: " + err.Error())
+	}
+	if p.Price <= 0 {
+		return nil, apperrors.NewInvalidParamError("价格必须大于0")
+	}
+	return &p, nil
+}
+
+// productUpsertedEvent 构造 product.upserted 事件
+func productUpsertedEvent(productID uint64) *outbox.Event {
+	payloadBytes, _ := json.Marshal(map[string]any{"product_id": productID})
+	payload := string(payloadBytes)
+	return &outbox.Event{
+		AggregateType: "product",
+		AggregateID:   fmt.Sprintf("%d", productID),
+		EventType:     outbox.EventProductUpserted,
+		Payload:       &payload,
+		Status:        outbox.StatusPending,
+	}
+}
+
+// priceChangedEvent 构造 product.price.changed 事件，skuID 为 0 表示商品本身的价格
+func priceChangedEvent(s *model.ProductSchedule, productID, skuID uint64, oldPrice, newPrice float64, oldOriginalPrice, newOriginalPrice *float64) *outbox.Event {
+	if newOriginalPrice == nil {
+		newOriginalPrice = oldOriginalPrice
+	}
+	payloadBytes, _ := json.Marshal(map[string]any{
+		"product_id":         productID,
+		"sku_id":             skuID,
+		"old_price":          oldPrice,
+		"new_price":          newPrice,
+		"old_original_price": oldOriginalPrice,
+		"new_original_price": newOriginalPrice,
+		"schedule_id":        s.ID,
+		"changed_at":         time.Now().Format(time.RFC3339),
+	})
+	payload := string(payloadBytes)
+	return &outbox.Event{
+		AggregateType: "product",
+		AggregateID:   fmt.Sprintf("%d", productID),
+		EventType:     outbox.EventProductPriceChanged,
+		Payload:       &payload,
+		Status:        outbox.StatusPending,
+	}
+}
+
+// RunScheduler 按间隔执行到期的定时任务直到 ctx 取消。多实例部署时用 Redis 锁选出一个 leader 执行，
+// leader 每轮续期，宕机后锁过期由其他实例接替；任务状态的条件更新保证即使短暂出现两个 leader 也只执行一次
+func (l *ProductLogic) RunScheduler(ctx context.Context, interval time.Duration) {
+	if l.cache == nil {
+		logx.Error("商品定时任务调度器未启动: Redis 未初始化")
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lock := cache.NewDistributedLock(l.cache.GetClient(), cache.BuildKey(cache.KeyPrefixLock, "product", "schedule"), 3*interval)
+	leader := false
+	for {
+		select {
+		case <-ctx.Done():
+			if leader {
+				_ = lock.Unlock(context.Background())
+			}
+			return
+		case <-ticker.C:
+		}
+
+		var ok bool
+		var err error
+		if leader {
+			ok, err = lock.Renew(ctx)
+		} else {
+			ok, err = lock.Lock(ctx)
+		}
+		if err != nil {
+			logx.Errorf("商品定时任务调度器选主失败: %v", err)
+		}
+		if ok != leader {
+			logx.Infof("商品定时任务调度器 leader 状态变化: leader=%v", ok)
+		}
+		leader = err == nil && ok
+		if !leader {
+			continue
+		}
+
+		executed, err := l.ExecuteDueSchedules(ctx)
+		if err != nil {
+			logx.Errorf("执行商品定时任务失败: %v", err)
+			continue
+		}
+		if executed > 0 {
+			logx.Infof("商品定时任务执行完成: executed=%d", executed)
+		}
+	}
+}
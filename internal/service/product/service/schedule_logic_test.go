@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/service/product/model"
+)
+
+func newSchedule(id, productID uint64, action, payload string) *model.ProductSchedule {
+	return &model.ProductSchedule{
+		ID:         id,
+		TargetType: model.ScheduleTargetProduct,
+		TargetID:   productID,
+		ProductID:  productID,
+		Action:     action,
+		Payload:    payload,
+		RunAt:      time.Now().Add(-time.Minute),
+		Status:     model.ScheduleStatusPending,
+	}
+}
+
+// newScheduleTestLogic 商品 1 已上架，价格 10；商品 2 尚未通过审核
+func newScheduleTestLogic(schedules ...*model.ProductSchedule) (*ProductLogic, *memProductRepo, *memRevisionRepo, *memScheduleRepo) {
+	env := newProductTestEnv()
+	env.categories.seed(&model.Category{ID: 1, Name: "服装", Level: 1})
+	env.products.seed(
+		&model.Product{ID: 1, CategoryID: 1, Name: "上衣", Price: 10, Status: model.ProductStatusOn, AuditStatus: model.RevisionStatusApproved},
+		&model.Product{ID: 2, CategoryID: 1, Name: "新商品", Price: 20, Status: model.ProductStatusPending},
+	)
+	env.schedules.seed(schedules...)
+	return env.logic, env.products, env.revisions, env.schedules
+}
+
+func TestExecuteScheduleOnlyOnce(t *testing.T) {
+	logic, products, _, schedules := newScheduleTestLogic(newSchedule(1, 1, model.ScheduleActionPrice, `{"price":15}`))
+	ctx := context.Background()
+
+	// 两个执行者取到同一批到期任务：第二个执行者在第一个取到任务后抢先执行
+	other := *logic
+	var otherExecuted int
+	schedules.afterListDue = func() {
+		var err error
+		if otherExecuted, err = other.ExecuteDueSchedules(ctx); err != nil {
+			t.Errorf("other ExecuteDueSchedules: %v", err)
+		}
+	}
+	executed, err := logic.ExecuteDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("ExecuteDueSchedules: %v", err)
+	}
+	if executed != 0 || otherExecuted != 1 {
+		t.Fatalf("executed %d and %d times, want once by the other executor", executed, otherExecuted)
+	}
+	if products.updates != 1 || products.rows[1].Price != 15 {
+		t.Fatalf("product updated %d times, price %v", products.updates, products.rows[1].Price)
+	}
+	if s := schedules.rows[1]; s.Status != model.ScheduleStatusDone {
+		t.Fatalf("schedule status = %d, want done", s.Status)
+	}
+
+	// 已执行的任务不会再被执行，也不能取消
+	if executed, _ := logic.ExecuteDueSchedules(ctx); executed != 0 {
+		t.Fatalf("executed %d done schedules again", executed)
+	}
+	_, err = logic.CancelProductSchedule(ctx, 1)
+	assertBizCode(t, err, apperrors.CodeInvalidParam)
+}
+
+func TestExecuteScheduleCanceledMidRun(t *testing.T) {
+	logic, products, _, schedules := newScheduleTestLogic(
+		newSchedule(1, 1, model.ScheduleActionPrice, `{"price":15}`),
+		newSchedule(2, 1, model.ScheduleActionDelist, "{}"),
+	)
+	ctx := context.Background()
+
+	// 调度器取到两个任务后，管理员取消了下架任务
+	schedules.afterListDue = func() {
+		if _, err := logic.CancelProductSchedule(ctx, 2); err != nil {
+			t.Errorf("CancelProductSchedule: %v", err)
+		}
+	}
+	executed, err := logic.ExecuteDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("ExecuteDueSchedules: %v", err)
+	}
+	if executed != 1 {
+		t.Fatalf("executed = %d, want 1", executed)
+	}
+	p := products.rows[1]
+	if p.Price != 15 || p.Status != model.ProductStatusOn {
+		t.Fatalf("canceled delist applied: price %v status %d", p.Price, p.Status)
+	}
+	if s := schedules.rows[2]; s.Status != model.ScheduleStatusCanceled {
+		t.Fatalf("canceled schedule status = %d", s.Status)
+	}
+}
+
+func TestExecuteScheduleBusinessErrorMarksFailed(t *testing.T) {
+	logic, products, _, schedules := newScheduleTestLogic(newSchedule(1, 2, model.ScheduleActionList, "{}"))
+	ctx := context.Background()
+
+	executed, err := logic.ExecuteDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("ExecuteDueSchedules: %v", err)
+	}
+	if executed != 0 {
+		t.Fatalf("executed = %d, want 0", executed)
+	}
+	// 待审核商品不能上架：事务回滚后任务标记为失败
+	if p := products.rows[2]; p.Status != model.ProductStatusPending {
+		t.Fatalf("pending product status changed to %d", p.Status)
+	}
+	if s := schedules.rows[1]; s.Status != model.ScheduleStatusFailed || s.Error == "" {
+		t.Fatalf("schedule status = %d error %q, want failed", s.Status, s.Error)
+	}
+}
+
+func TestExecuteScheduleCarriesPriceToOpenRevision(t *testing.T) {
+	logic, products, revisions, schedules := newScheduleTestLogic(
+		newSchedule(1, 1, model.ScheduleActionPrice, `{"price":15,"original_price":20}`),
+	)
+	ctx := context.Background()
+	// 待审核的修订只改了名称，价格与商品相同
+	revisions.seed(newRevision(1, &model.Product{ID: 1, CategoryID: 1, Name: "新上衣", Price: 10}, model.RevisionStatusSubmitted))
+
+	if executed, err := logic.ExecuteDueSchedules(ctx); err != nil || executed != 1 {
+		t.Fatalf("ExecuteDueSchedules: executed=%d err=%v", executed, err)
+	}
+	content, err := parseRevisionContent(revisions.rows[1].Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.Name != "新上衣" || content.Price != 15 || content.OriginalPrice == nil || *content.OriginalPrice != 20 {
+		t.Fatalf("price not carried into revision: %+v", content)
+	}
+	if revisions.rows[1].Status != model.RevisionStatusSubmitted {
+		t.Fatalf("revision status changed to %d", revisions.rows[1].Status)
+	}
+
+	// 审核通过后不会用修订里的旧价格覆盖定时改价
+	if _, err := logic.ApproveProductRevision(ctx, &ReviewProductRevisionRequest{ID: 1}); err != nil {
+		t.Fatalf("ApproveProductRevision: %v", err)
+	}
+	if p := products.rows[1]; p.Name != "新上衣" || p.Price != 15 {
+		t.Fatalf("approved product: name %q price %v", p.Name, p.Price)
+	}
+
+	// 修订自己改了价格时保留修订的价格
+	revisions.rows[2] = newRevision(2, &model.Product{ID: 1, CategoryID: 1, Name: "新上衣", Price: 12}, model.RevisionStatusDraft)
+	schedules.rows[2] = newSchedule(2, 1, model.ScheduleActionPrice, `{"price":18}`)
+	if executed, err := logic.ExecuteDueSchedules(ctx); err != nil || executed != 1 {
+		t.Fatalf("ExecuteDueSchedules: executed=%d err=%v", executed, err)
+	}
+	if content, _ := parseRevisionContent(revisions.rows[2].Content); content.Price != 12 {
+		t.Fatalf("revision price overwritten to %v", content.Price)
+	}
+	if p := products.rows[1]; p.Price != 18 {
+		t.Fatalf("product price = %v, want 18", p.Price)
+	}
+}
@@ -2,6 +2,8 @@ package promotion
 
 import (
 	"github.com/zeromicro/go-zero/zrpc"
+
+	"ecommerce-system/internal/pkg/client"
 )
 
 // Config 营销服务配置
@@ -9,6 +11,14 @@ type Config struct {
 	zrpc.RpcServerConf
 	Database DatabaseConfig
 	BizRedis RedisConfig // 业务侧使用的 Redis 配置，避免与 zrpc.RpcServerConf 内置的 Redis 字段冲突
+	// UserRpc 用户服务地址，计算优惠时查询会员折扣；不配置时不计算会员折扣
+	UserRpc client.RpcConf `json:",optional"`
+	JWT     JWTConfig      // 个人数据接口校验 user-service 签发的服务令牌，与 user-service 保持一致
+}
+
+// JWTConfig JWT配置
+type JWTConfig struct {
+	Secret string
 }
 
 // DatabaseConfig 数据库配置
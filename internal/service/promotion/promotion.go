@@ -2,9 +2,11 @@ package promotion
 
 import (
 	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
 
 	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/client"
 	"ecommerce-system/internal/pkg/database"
 	"ecommerce-system/internal/service/promotion/repository"
 )
@@ -19,6 +21,7 @@ type ServiceContext struct {
 	UserCouponRepo repository.UserCouponRepository
 	PromotionRepo  repository.PromotionRepository
 	PointsRepo     repository.PointsRepository
+	UserClient     *client.UserClient // 为 nil 时不计算会员折扣
 }
 
 // NewServiceContext 创建服务上下文。DB/Redis 初始化失败直接 Fatal，不静默放行。
@@ -45,6 +48,14 @@ func NewServiceContext(c Config) *ServiceContext {
 		MinIdleConns: c.BizRedis.MinIdleConns,
 	})
 
+	// 用户服务客户端（endpoint 为空则跳过，方便单独启动调试）
+	var userClient *client.UserClient
+	if c.UserRpc.Endpoint != "" {
+		uc, err := client.NewUserClient(c.UserRpc)
+		logx.Must(err)
+		userClient = uc
+	}
+
 	return &ServiceContext{
 		Config:         c,
 		DB:             db,
@@ -54,5 +65,6 @@ func NewServiceContext(c Config) *ServiceContext {
 		UserCouponRepo: repository.NewUserCouponRepository(db),
 		PromotionRepo:  repository.NewPromotionRepository(db),
 		PointsRepo:     repository.NewPointsRepository(db),
+		UserClient:     userClient,
 	}
 }
@@ -28,6 +28,7 @@ func NewPromotionService(svcCtx *ServiceContext) *PromotionService {
 		svcCtx.UserCouponRepo,
 		svcCtx.PromotionRepo,
 		svcCtx.PointsRepo,
+		svcCtx.UserClient,
 	)
 
 	return &PromotionService{
@@ -174,10 +175,11 @@ func (s *PromotionService) CalculateDiscount(ctx context.Context, req *v1.Calcul
 	}
 
 	return &v1.CalculateDiscountResponse{
-		Code:           0,
-		Message:        "成功",
-		DiscountAmount: strconv.FormatFloat(resp.DiscountAmount, 'f', 2, 64),
-		FinalAmount:    strconv.FormatFloat(resp.FinalAmount, 'f', 2, 64),
+		Code:                 0,
+		Message:              "成功",
+		DiscountAmount:       strconv.FormatFloat(resp.DiscountAmount, 'f', 2, 64),
+		FinalAmount:          strconv.FormatFloat(resp.FinalAmount, 'f', 2, 64),
+		MemberDiscountAmount: strconv.FormatFloat(resp.MemberDiscountAmount, 'f', 2, 64),
 	}, nil
 }
 
@@ -19,6 +19,10 @@ type PointsRepository interface {
 	AddPoints(ctx context.Context, userID uint64, points int64) error
 	// DeductPoints 扣减积分
 	DeductPoints(ctx context.Context, userID uint64, points int64) error
+	// FindByUserID 获取用户积分，不存在时返回 nil（不自动创建）
+	FindByUserID(ctx context.Context, userID uint64) (*model.Points, error)
+	// DeleteByUser 删除用户积分账户，返回删除数量
+	DeleteByUser(ctx context.Context, userID uint64) (int64, error)
 }
 
 type pointsRepository struct {
@@ -83,3 +87,22 @@ func (r *pointsRepository) DeductPoints(ctx context.Context, userID uint64, poin
 			"available": gorm.Expr("available - ?", points),
 		}).Error
 }
+
+// FindByUserID 获取用户积分，不存在时返回 nil
+func (r *pointsRepository) FindByUserID(ctx context.Context, userID uint64) (*model.Points, error) {
+	var points model.Points
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&points).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &points, nil
+}
+
+// DeleteByUser 删除用户积分账户
+func (r *pointsRepository) DeleteByUser(ctx context.Context, userID uint64) (int64, error) {
+	res := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.Points{})
+	return res.RowsAffected, res.Error
+}
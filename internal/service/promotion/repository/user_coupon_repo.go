@@ -17,6 +17,8 @@ type UserCouponRepository interface {
 	Update(ctx context.Context, userCoupon *model.UserCoupon) error
 	// CountByUserAndCoupon 统计用户已领取的优惠券数量
 	CountByUserAndCoupon(ctx context.Context, userID, couponID uint64) (int64, error)
+	// DeleteUnusedByUser 删除用户未使用和已过期的优惠券（已使用的关联订单，保留），返回删除数量
+	DeleteUnusedByUser(ctx context.Context, userID uint64) (int64, error)
 }
 
 type userCouponRepository struct {
@@ -59,3 +61,11 @@ func (r *userCouponRepository) CountByUserAndCoupon(ctx context.Context, userID,
 		Count(&count).Error
 	return count, err
 }
+
+// DeleteUnusedByUser 删除用户未使用和已过期的优惠券
+func (r *userCouponRepository) DeleteUnusedByUser(ctx context.Context, userID uint64) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, []int8{0, 2}).
+		Delete(&model.UserCoupon{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/promotion/model"
+	"ecommerce-system/internal/service/promotion/repository"
+)
+
+// DataSubjectHandler 营销服务的个人数据导出和删除。
+// 注销时未使用、已过期的优惠券和积分账户删除；已使用的优惠券关联订单优惠金额，随订单保留。
+type DataSubjectHandler struct {
+	userCouponRepo repository.UserCouponRepository
+	pointsRepo     repository.PointsRepository
+}
+
+// NewDataSubjectHandler 创建营销个人数据处理器
+func NewDataSubjectHandler(userCouponRepo repository.UserCouponRepository, pointsRepo repository.PointsRepository) *DataSubjectHandler {
+	return &DataSubjectHandler{userCouponRepo: userCouponRepo, pointsRepo: pointsRepo}
+}
+
+// Export 导出用户优惠券和积分
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	coupons, err := h.userCouponRepo.GetByUserID(ctx, userID, -1)
+	if err != nil {
+		return nil, err
+	}
+	points, err := h.pointsRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	pointsRecords := []*model.Points{}
+	if points != nil {
+		pointsRecords = append(pointsRecords, points)
+	}
+	return []datasubject.Section{
+		{Name: "coupons", Description: "领取的优惠券", Records: coupons, Count: len(coupons)},
+		{Name: "points", Description: "积分账户", Records: pointsRecords, Count: len(pointsRecords)},
+	}, nil
+}
+
+// Erase 删除用户优惠券和积分
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	coupons, err := h.userCouponRepo.DeleteUnusedByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	points, err := h.pointsRepo.DeleteByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Result{
+		{Name: "coupons", Deleted: coupons, Note: "已使用的优惠券关联订单优惠金额，随订单保留"},
+		{Name: "points", Deleted: points},
+	}, nil
+}
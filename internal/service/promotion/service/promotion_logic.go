@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"math"
 	"time"
 
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/client"
 	apperrors "ecommerce-system/internal/pkg/errors"
 	"ecommerce-system/internal/service/promotion/model"
 	"ecommerce-system/internal/service/promotion/repository"
@@ -15,6 +19,7 @@ type PromotionLogic struct {
 	userCouponRepo repository.UserCouponRepository
 	promotionRepo  repository.PromotionRepository
 	pointsRepo     repository.PointsRepository
+	userClient     *client.UserClient // 查询会员折扣，为 nil 时不计算
 }
 
 // NewPromotionLogic 创建营销业务逻辑
@@ -23,12 +28,14 @@ func NewPromotionLogic(
 	userCouponRepo repository.UserCouponRepository,
 	promotionRepo repository.PromotionRepository,
 	pointsRepo repository.PointsRepository,
+	userClient *client.UserClient,
 ) *PromotionLogic {
 	return &PromotionLogic{
 		couponRepo:     couponRepo,
 		userCouponRepo: userCouponRepo,
 		promotionRepo:  promotionRepo,
 		pointsRepo:     pointsRepo,
+		userClient:     userClient,
 	}
 }
 
@@ -230,11 +237,12 @@ type CalculateDiscountRequest struct {
 
 // CalculateDiscountResponse 计算优惠金额响应
 type CalculateDiscountResponse struct {
-	DiscountAmount float64
-	FinalAmount    float64
+	DiscountAmount       float64 // 优惠总额（含会员折扣）
+	FinalAmount          float64
+	MemberDiscountAmount float64 // 其中会员折扣金额
 }
 
-// CalculateDiscount 计算优惠金额
+// CalculateDiscount 计算优惠金额：先扣优惠券，再对剩余金额按会员等级打折
 func (l *PromotionLogic) CalculateDiscount(ctx context.Context, req *CalculateDiscountRequest) (*CalculateDiscountResponse, error) {
 	discountAmount := 0.0
 
@@ -264,9 +272,23 @@ func (l *PromotionLogic) CalculateDiscount(ctx context.Context, req *CalculateDi
 		finalAmount = 0
 	}
 
+	// 会员折扣：用户服务不可用时不打折，不影响下单
+	memberDiscount := 0.0
+	if req.UserID > 0 && l.userClient != nil && finalAmount > 0 {
+		benefits, err := l.userClient.GetMemberBenefits(ctx, int64(req.UserID))
+		if err != nil {
+			logx.WithContext(ctx).Errorf("查询会员权益失败 user_id=%d: %v，不计算会员折扣", req.UserID, err)
+		} else if rate := benefits.GetTier().GetDiscountRate(); rate > 0 && rate < 1 {
+			memberDiscount = math.Round(finalAmount*(1-rate)*100) / 100
+			finalAmount -= memberDiscount
+			discountAmount += memberDiscount
+		}
+	}
+
 	return &CalculateDiscountResponse{
-		DiscountAmount: discountAmount,
-		FinalAmount:    finalAmount,
+		DiscountAmount:       discountAmount,
+		FinalAmount:          finalAmount,
+		MemberDiscountAmount: memberDiscount,
 	}, nil
 }
 
@@ -8,6 +8,12 @@ import (
 type Config struct {
 	zrpc.RpcServerConf
 	BizRedis RedisConfig // 业务侧使用的 Redis 配置，避免与 zrpc.RpcServerConf 内置的 Redis 字段冲突
+	JWT      JWTConfig   // 个人数据接口校验 user-service 签发的服务令牌，与 user-service 保持一致
+}
+
+// JWTConfig JWT配置
+type JWTConfig struct {
+	Secret string
 }
 
 // RedisConfig Redis配置
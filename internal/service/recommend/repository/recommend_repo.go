@@ -28,6 +28,8 @@ type RecommendRepository interface {
 	GetHotProducts(ctx context.Context, categoryID uint64, limit int) ([]*RecommendItem, error)
 	// GetRealtimeRecommend 获取实时推荐
 	GetRealtimeRecommend(ctx context.Context, userID uint64, limit int) ([]*RecommendItem, error)
+	// DeleteUserRecommend 删除用户的个性化和实时推荐结果，返回删除的 key 数量
+	DeleteUserRecommend(ctx context.Context, userID uint64) (int64, error)
 }
 
 type recommendRepository struct {
@@ -100,3 +102,8 @@ func (r *recommendRepository) GetHotProducts(ctx context.Context, categoryID uin
 func (r *recommendRepository) GetRealtimeRecommend(ctx context.Context, userID uint64, limit int) ([]*RecommendItem, error) {
 	return r.fetchFromRedis(ctx, realtimeKey(userID), limit)
 }
+
+// DeleteUserRecommend 删除用户推荐结果
+func (r *recommendRepository) DeleteUserRecommend(ctx context.Context, userID uint64) (int64, error) {
+	return r.redis.Del(ctx, personalizedKey(userID), realtimeKey(userID)).Result()
+}
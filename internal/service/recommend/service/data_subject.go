@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/recommend/repository"
+)
+
+// userRecommendExportLimit 导出的每类推荐结果条数上限
+const userRecommendExportLimit = 1000
+
+// DataSubjectHandler 推荐服务的个人数据导出和删除。
+// 推荐服务只保存按用户计算的推荐结果，注销时直接删除。
+type DataSubjectHandler struct {
+	recommendRepo repository.RecommendRepository
+}
+
+// NewDataSubjectHandler 创建推荐个人数据处理器
+func NewDataSubjectHandler(recommendRepo repository.RecommendRepository) *DataSubjectHandler {
+	return &DataSubjectHandler{recommendRepo: recommendRepo}
+}
+
+// Export 导出用户的个性化和实时推荐结果
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	personalized, err := h.recommendRepo.GetPersonalizedRecommend(ctx, userID, userRecommendExportLimit)
+	if err != nil {
+		return nil, err
+	}
+	realtime, err := h.recommendRepo.GetRealtimeRecommend(ctx, userID, userRecommendExportLimit)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Section{
+		{Name: "recommend_personalized", Description: "个性化推荐结果", Records: personalized, Count: len(personalized)},
+		{Name: "recommend_realtime", Description: "实时推荐结果", Records: realtime, Count: len(realtime)},
+	}, nil
+}
+
+// Erase 删除用户推荐结果
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	n, err := h.recommendRepo.DeleteUserRecommend(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Result{{Name: "recommend", Deleted: n}}, nil
+}
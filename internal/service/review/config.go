@@ -12,6 +12,12 @@ type Config struct {
 	Database DatabaseConfig
 	MongoDB  *MongoDBConfig
 	OrderRpc client.RpcConf // 订单服务地址，用于校验订单状态
+	JWT      JWTConfig      // 个人数据接口校验 user-service 签发的服务令牌，与 user-service 保持一致
+}
+
+// JWTConfig JWT配置
+type JWTConfig struct {
+	Secret string
 }
 
 // MongoDBConfig MongoDB配置
@@ -13,6 +13,10 @@ type ReviewReplyRepository interface {
 	Create(ctx context.Context, reply *model.ReviewReply) error
 	// GetByReviewID 根据评价ID获取回复列表
 	GetByReviewID(ctx context.Context, reviewID uint64) ([]*model.ReviewReply, error)
+	// ListByUser 获取用户发表的全部回复
+	ListByUser(ctx context.Context, userID uint64) ([]*model.ReviewReply, error)
+	// DeleteByUser 删除用户发表的全部回复，返回删除数量
+	DeleteByUser(ctx context.Context, userID uint64) (int64, error)
 }
 
 type reviewReplyRepository struct {
@@ -36,3 +40,16 @@ func (r *reviewReplyRepository) GetByReviewID(ctx context.Context, reviewID uint
 		Order("created_at ASC").Find(&replies).Error
 	return replies, err
 }
+
+// ListByUser 获取用户发表的全部回复
+func (r *reviewReplyRepository) ListByUser(ctx context.Context, userID uint64) ([]*model.ReviewReply, error) {
+	var replies []*model.ReviewReply
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&replies).Error
+	return replies, err
+}
+
+// DeleteByUser 删除用户发表的全部回复
+func (r *reviewReplyRepository) DeleteByUser(ctx context.Context, userID uint64) (int64, error) {
+	res := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.ReviewReply{})
+	return res.RowsAffected, res.Error
+}
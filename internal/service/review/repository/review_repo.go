@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"ecommerce-system/internal/pkg/mongodb"
 	"ecommerce-system/internal/service/review/model"
@@ -23,6 +24,11 @@ type ReviewRepository interface {
 	GetStats(ctx context.Context, productID uint64) (*model.ReviewStats, error)
 	// GetReviewDetail 从MongoDB获取评价详情（包含图片、视频）
 	GetReviewDetail(ctx context.Context, reviewID uint64) (map[string]interface{}, error)
+	// ListByUser 获取用户全部评价，用于个人数据导出
+	ListByUser(ctx context.Context, userID uint64) ([]*model.Review, error)
+	// AnonymizeByUser 清空用户评价的文字、图片和视频（评分保留以免影响商品统计），
+	// 同时删除 MongoDB 中的评价详情，返回处理的评价数
+	AnonymizeByUser(ctx context.Context, userID uint64) (int64, error)
 }
 
 type reviewRepository struct {
@@ -143,3 +149,31 @@ func (r *reviewRepository) GetStats(ctx context.Context, productID uint64) (*mod
 
 	return &stats, nil
 }
+
+// ListByUser 获取用户全部评价
+func (r *reviewRepository) ListByUser(ctx context.Context, userID uint64) ([]*model.Review, error) {
+	var reviews []*model.Review
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&reviews).Error
+	return reviews, err
+}
+
+// AnonymizeByUser 匿名化用户评价
+func (r *reviewRepository) AnonymizeByUser(ctx context.Context, userID uint64) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&model.Review{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"content":    "",
+			"images":     nil,
+			"videos":     nil,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if r.mongoDB != nil {
+		if _, err := r.mongoDB.Collection("reviews").DeleteMany(ctx, map[string]interface{}{"user_id": userID}); err != nil {
+			return res.RowsAffected, err
+		}
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"ecommerce-system/internal/pkg/datasubject"
+	"ecommerce-system/internal/service/review/repository"
+)
+
+// DataSubjectHandler 评价服务的个人数据导出和删除。
+// 注销时评价保留评分（商品评分统计依赖），清空文字和图片视频；用户发表的回复直接删除。
+type DataSubjectHandler struct {
+	reviewRepo repository.ReviewRepository
+	replyRepo  repository.ReviewReplyRepository
+}
+
+// NewDataSubjectHandler 创建评价个人数据处理器
+func NewDataSubjectHandler(reviewRepo repository.ReviewRepository, replyRepo repository.ReviewReplyRepository) *DataSubjectHandler {
+	return &DataSubjectHandler{reviewRepo: reviewRepo, replyRepo: replyRepo}
+}
+
+// Export 导出用户评价和回复
+func (h *DataSubjectHandler) Export(ctx context.Context, userID uint64) ([]datasubject.Section, error) {
+	reviews, err := h.reviewRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := h.replyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Section{
+		{Name: "reviews", Description: "商品评价", Records: reviews, Count: len(reviews)},
+		{Name: "review_replies", Description: "评价回复", Records: replies, Count: len(replies)},
+	}, nil
+}
+
+// Erase 匿名化用户评价并删除回复
+func (h *DataSubjectHandler) Erase(ctx context.Context, userID uint64) ([]datasubject.Result, error) {
+	reviews, err := h.reviewRepo.AnonymizeByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := h.replyRepo.DeleteByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return []datasubject.Result{
+		{Name: "reviews", Anonymized: reviews, Note: "保留评分，已清空评价内容和图片视频"},
+		{Name: "review_replies", Deleted: replies},
+	}, nil
+}
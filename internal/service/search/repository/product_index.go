@@ -1,12 +1,14 @@
 package repository
 
-// ProductIndexName ES 商品索引名
-const ProductIndexName = "products_v1"
+// ProductIndexName ES 商品索引名。mapping 有不兼容变更时升级版本号，
+// 新索引在启动时创建，由全量重建（BuildProductIndex 不传 ID）填充
+const ProductIndexName = "products_v2"
 
 // ProductIndexMapping ES 索引 mapping（尽量使用内置 analyzer，避免依赖额外插件）
 // 说明：
 // - name/subtitle/detail 用 text 以支持全文检索
 // - 保留 keyword 字段用于过滤/聚合
+// - attrs 为 nested 的属性名/属性值对（商品属性 + 上架 SKU 规格），用于属性筛选和分面统计
 const ProductIndexMapping = `{
   "settings": {
     "number_of_shards": 1,
@@ -20,6 +22,7 @@ const ProductIndexMapping = `{
       "detail": { "type": "text" },
       "category_id": { "type": "long" },
       "brand_id": { "type": "long" },
+      "brand_name": { "type": "text", "fields": { "keyword": { "type": "keyword" } } },
       "status": { "type": "integer" },
       "is_hot": { "type": "integer" },
       "sales": { "type": "integer" },
@@ -39,6 +42,13 @@ const ProductIndexMapping = `{
           "specs": { "type": "object", "enabled": true }
         }
       },
+      "attrs": {
+        "type": "nested",
+        "properties": {
+          "name": { "type": "keyword" },
+          "value": { "type": "keyword" }
+        }
+      },
       "updated_at": { "type": "date" }
     }
   }
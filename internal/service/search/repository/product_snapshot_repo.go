@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -38,6 +40,7 @@ type productRow struct {
 	Sales          int        `gorm:"column:sales"`
 	Status         int8       `gorm:"column:status"`
 	IsHot          int8       `gorm:"column:is_hot"`
+	Attrs          *string    `gorm:"column:attrs"`
 	UpdatedAt      time.Time  `gorm:"column:updated_at"`
 	DeletedAt      *time.Time `gorm:"column:deleted_at"`
 }
@@ -83,6 +86,31 @@ func (r *productSnapshotRepo) BuildProductDocument(ctx context.Context, productI
 		Order("id ASC").
 		Find(&skus).Error
 
+	// 属性名/值对：商品的规格参数、基础属性（多选值以逗号分隔）和上架 SKU 的规格，去重
+	attrs := make([]map[string]interface{}, 0)
+	seenAttrs := make(map[string]struct{})
+	addAttr := func(name, value string) {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name == "" || value == "" {
+			return
+		}
+		key := name + "\x00" + value
+		if _, ok := seenAttrs[key]; ok {
+			return
+		}
+		seenAttrs[key] = struct{}{}
+		attrs = append(attrs, map[string]interface{}{"name": name, "value": value})
+	}
+	if p.Attrs != nil && *p.Attrs != "" {
+		productAttrs := map[string]string{}
+		_ = json.Unmarshal([]byte(*p.Attrs), &productAttrs)
+		for name, value := range productAttrs {
+			for _, v := range strings.Split(value, ",") {
+				addAttr(name, v)
+			}
+		}
+	}
+
 	priceMin := math.MaxFloat64
 	priceMax := 0.0
 	esSkus := make([]map[string]interface{}, 0, len(skus))
@@ -101,6 +129,11 @@ func (r *productSnapshotRepo) BuildProductDocument(ctx context.Context, productI
 		if s.Specs != "" {
 			_ = json.Unmarshal([]byte(s.Specs), &specsObj)
 		}
+		for name, value := range specsObj {
+			if v, ok := value.(string); ok {
+				addAttr(name, v)
+			}
+		}
 		esSkus = append(esSkus, map[string]interface{}{
 			"sku_id":   s.ID,
 			"sku_name": s.Name,
@@ -118,15 +151,26 @@ func (r *productSnapshotRepo) BuildProductDocument(ctx context.Context, productI
 		priceMax = p.Price
 	}
 
+	// 固定顺序，避免同样的数据每次生成不同的文档
+	sort.Slice(attrs, func(i, j int) bool {
+		ni, nj := attrs[i]["name"].(string), attrs[j]["name"].(string)
+		if ni != nj {
+			return ni < nj
+		}
+		return attrs[i]["value"].(string) < attrs[j]["value"].(string)
+	})
+
 	// 选择主图：优先本地，其次远程
 	mainImage := p.LocalMainImage
 	if mainImage == "" {
 		mainImage = p.MainImage
 	}
 
-	brandID := uint64(0)
-	if p.BrandID != nil {
+	// 品牌名称冗余到文档里，用于关键词检索和结果展示；品牌改名时商品服务会重新投递 upsert 事件
+	brandID, brandName := uint64(0), ""
+	if p.BrandID != nil && *p.BrandID > 0 {
 		brandID = *p.BrandID
+		_ = r.db.WithContext(ctx).Table("brand").Where("id = ?", brandID).Limit(1).Pluck("name", &brandName).Error
 	}
 
 	doc := map[string]interface{}{
@@ -136,6 +180,7 @@ func (r *productSnapshotRepo) BuildProductDocument(ctx context.Context, productI
 		"detail":      p.Detail,
 		"category_id": p.CategoryID,
 		"brand_id":    brandID,
+		"brand_name":  brandName,
 		"status":      int(p.Status),
 		"is_hot":      int(p.IsHot),
 		"sales":       p.Sales,
@@ -144,6 +189,7 @@ func (r *productSnapshotRepo) BuildProductDocument(ctx context.Context, productI
 		"price_min":   priceMin,
 		"price_max":   priceMax,
 		"skus":        esSkus,
+		"attrs":       attrs,
 		"updated_at":  p.UpdatedAt.Format(time.RFC3339),
 	}
 	return doc, nil
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/redis/go-redis/v9"
 
@@ -11,8 +12,8 @@ import (
 
 // SearchRepository 搜索仓库接口
 type SearchRepository interface {
-	// SearchProducts 搜索商品
-	SearchProducts(ctx context.Context, keyword string, categoryID uint64, page, pageSize int, sortBy string) ([]map[string]interface{}, int64, error)
+	// SearchProducts 搜索商品，attrFilters 为属性名到可选值（同一属性内为“或”，不同属性间为“且”），同时返回属性分面统计
+	SearchProducts(ctx context.Context, keyword string, categoryID, brandID uint64, attrFilters map[string][]string, page, pageSize int, sortBy string) ([]map[string]interface{}, int64, []AttrFacet, error)
 	// GetSearchSuggestions 获取搜索建议
 	GetSearchSuggestions(ctx context.Context, keyword string, limit int) ([]string, error)
 	// GetHotKeywords 获取搜索热词
@@ -21,6 +22,25 @@ type SearchRepository interface {
 	BuildProductIndex(ctx context.Context, productIDs []uint64) error
 }
 
+// AttrFacet 属性分面：属性名及各取值命中的商品数
+type AttrFacet struct {
+	Name   string
+	Values []FacetValue
+}
+
+// FacetValue 分面取值
+type FacetValue struct {
+	Value string
+	Count int64
+}
+
+const (
+	// maxFacetAttrs 最多返回的分面属性数
+	maxFacetAttrs = 20
+	// maxFacetValues 每个属性最多返回的取值数
+	maxFacetValues = 30
+)
+
 type searchRepository struct {
 	redis        *redis.Client
 	esClient     *search.Client
@@ -37,10 +57,10 @@ func NewSearchRepository(redis *redis.Client, esClient *search.Client, snapshotR
 }
 
 // SearchProducts 搜索商品（使用Elasticsearch）
-func (r *searchRepository) SearchProducts(ctx context.Context, keyword string, categoryID uint64, page, pageSize int, sortBy string) ([]map[string]interface{}, int64, error) {
+func (r *searchRepository) SearchProducts(ctx context.Context, keyword string, categoryID, brandID uint64, attrFilters map[string][]string, page, pageSize int, sortBy string) ([]map[string]interface{}, int64, []AttrFacet, error) {
 	// 如果Elasticsearch不可用，返回空结果
 	if r.esClient == nil {
-		return []map[string]interface{}{}, 0, nil
+		return []map[string]interface{}{}, 0, nil, nil
 	}
 
 	// 分页默认值
@@ -66,7 +86,7 @@ func (r *searchRepository) SearchProducts(ctx context.Context, keyword string, c
 		mustClauses = append(mustClauses, map[string]interface{}{
 			"multi_match": map[string]interface{}{
 				"query":  keyword,
-				"fields": []string{"name^3", "subtitle^2", "detail"},
+				"fields": []string{"name^3", "brand_name^2", "subtitle^2", "detail"},
 				"type":   "best_fields",
 			},
 		})
@@ -81,6 +101,39 @@ func (r *searchRepository) SearchProducts(ctx context.Context, keyword string, c
 		})
 	}
 
+	// 品牌筛选
+	if brandID > 0 {
+		mustClauses = append(mustClauses, map[string]interface{}{
+			"term": map[string]interface{}{
+				"brand_id": brandID,
+			},
+		})
+	}
+
+	// 属性筛选：每个属性一个 nested 查询，按属性名排序保证查询稳定
+	attrNames := make([]string, 0, len(attrFilters))
+	for name, values := range attrFilters {
+		if name != "" && len(values) > 0 {
+			attrNames = append(attrNames, name)
+		}
+	}
+	sort.Strings(attrNames)
+	for _, name := range attrNames {
+		mustClauses = append(mustClauses, map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path": "attrs",
+				"query": map[string]interface{}{
+					"bool": map[string]interface{}{
+						"filter": []map[string]interface{}{
+							{"term": map[string]interface{}{"attrs.name": name}},
+							{"terms": map[string]interface{}{"attrs.value": attrFilters[name]}},
+						},
+					},
+				},
+			},
+		})
+	}
+
 	// 状态筛选（只搜索上架商品）
 	mustClauses = append(mustClauses, map[string]interface{}{
 		"term": map[string]interface{}{
@@ -116,13 +169,67 @@ func (r *searchRepository) SearchProducts(ctx context.Context, keyword string, c
 	}
 	query["sort"] = sort
 
+	// 属性分面：按属性名分桶，再按属性值分桶，reverse_nested 统计商品数而不是属性条数
+	query["aggs"] = map[string]interface{}{
+		"attrs": map[string]interface{}{
+			"nested": map[string]interface{}{"path": "attrs"},
+			"aggs": map[string]interface{}{
+				"names": map[string]interface{}{
+					"terms": map[string]interface{}{"field": "attrs.name", "size": maxFacetAttrs},
+					"aggs": map[string]interface{}{
+						"values": map[string]interface{}{
+							"terms": map[string]interface{}{"field": "attrs.value", "size": maxFacetValues},
+							"aggs": map[string]interface{}{
+								"products": map[string]interface{}{"reverse_nested": map[string]interface{}{}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
 	// 执行搜索
-	results, total, err := r.esClient.Search(ctx, ProductIndexName, query)
+	results, total, aggs, err := r.esClient.SearchWithAggs(ctx, ProductIndexName, query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("搜索失败: %w", err)
+		return nil, 0, nil, fmt.Errorf("搜索失败: %w", err)
 	}
 
-	return results, total, nil
+	return results, total, parseAttrFacets(aggs), nil
+}
+
+// parseAttrFacets 解析属性分面聚合结果
+func parseAttrFacets(aggs map[string]interface{}) []AttrFacet {
+	attrsAgg, _ := aggs["attrs"].(map[string]interface{})
+	namesAgg, _ := attrsAgg["names"].(map[string]interface{})
+	nameBuckets, _ := namesAgg["buckets"].([]interface{})
+
+	facets := make([]AttrFacet, 0, len(nameBuckets))
+	for _, nb := range nameBuckets {
+		nameBucket, ok := nb.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := nameBucket["key"].(string)
+		valuesAgg, _ := nameBucket["values"].(map[string]interface{})
+		valueBuckets, _ := valuesAgg["buckets"].([]interface{})
+
+		facet := AttrFacet{Name: name, Values: make([]FacetValue, 0, len(valueBuckets))}
+		for _, vb := range valueBuckets {
+			valueBucket, ok := vb.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			value, _ := valueBucket["key"].(string)
+			products, _ := valueBucket["products"].(map[string]interface{})
+			count, _ := products["doc_count"].(float64)
+			facet.Values = append(facet.Values, FacetValue{Value: value, Count: int64(count)})
+		}
+		if name != "" && len(facet.Values) > 0 {
+			facets = append(facets, facet)
+		}
+	}
+	return facets
 }
 
 // GetSearchSuggestions 获取搜索建议
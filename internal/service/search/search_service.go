@@ -35,6 +35,8 @@ func (s *SearchService) SearchProducts(ctx context.Context, req *v1.SearchProduc
 		Page:       int(req.Page),
 		PageSize:   int(req.PageSize),
 		CategoryID: uint64(req.CategoryId),
+		BrandID:    uint64(req.BrandId),
+		AttrFilter: req.AttrFilter,
 		SortBy:     req.SortBy,
 	}
 
@@ -52,14 +54,26 @@ func (s *SearchService) SearchProducts(ctx context.Context, req *v1.SearchProduc
 			Price:     strconv.FormatFloat(r.Price, 'f', 2, 64),
 			Sales:     int32(r.Sales),
 			Score:     r.Score,
+			BrandId:   r.BrandID,
+			BrandName: r.BrandName,
 		})
 	}
 
+	facets := make([]*v1.AttrFacet, 0, len(resp.Facets))
+	for _, f := range resp.Facets {
+		values := make([]*v1.FacetValue, 0, len(f.Values))
+		for _, v := range f.Values {
+			values = append(values, &v1.FacetValue{Value: v.Value, Count: v.Count})
+		}
+		facets = append(facets, &v1.AttrFacet{Name: f.Name, Values: values})
+	}
+
 	return &v1.SearchProductsResponse{
 		Code:    0,
 		Message: "成功",
 		Data:    results,
 		Total:   int32(resp.Total),
+		Facets:  facets,
 	}, nil
 }
 
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"strings"
+
 	apperrors "ecommerce-system/internal/pkg/errors"
 	"ecommerce-system/internal/service/search/repository"
 )
@@ -24,6 +26,8 @@ type SearchProductsRequest struct {
 	Page       int
 	PageSize   int
 	CategoryID uint64
+	BrandID    uint64
+	AttrFilter string // 格式 "颜色:红色,蓝色;尺码:L"
 	SortBy     string
 }
 
@@ -35,12 +39,33 @@ type ProductSearchResult struct {
 	Price     float64
 	Sales     int
 	Score     float64
+	BrandID   int64
+	BrandName string
 }
 
 // SearchProductsResponse 搜索商品响应
 type SearchProductsResponse struct {
 	Results []*ProductSearchResult
 	Total   int64
+	Facets  []repository.AttrFacet
+}
+
+// parseAttrFilter 解析属性筛选串，格式错误的片段忽略
+func parseAttrFilter(filter string) map[string][]string {
+	result := make(map[string][]string)
+	for _, part := range strings.Split(filter, ";") {
+		name, values, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		for _, v := range strings.Split(values, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result[name] = append(result[name], v)
+			}
+		}
+	}
+	return result
 }
 
 func toInt64Value(value interface{}) int64 {
@@ -114,7 +139,7 @@ func toFloat64Value(value interface{}) float64 {
 
 // SearchProducts 搜索商品
 func (l *SearchLogic) SearchProducts(ctx context.Context, req *SearchProductsRequest) (*SearchProductsResponse, error) {
-	results, total, err := l.searchRepo.SearchProducts(ctx, req.Keyword, req.CategoryID, req.Page, req.PageSize, req.SortBy)
+	results, total, facets, err := l.searchRepo.SearchProducts(ctx, req.Keyword, req.CategoryID, req.BrandID, parseAttrFilter(req.AttrFilter), req.Page, req.PageSize, req.SortBy)
 	if err != nil {
 		return nil, apperrors.NewInternalError("搜索商品失败")
 	}
@@ -130,6 +155,7 @@ func (l *SearchLogic) SearchProducts(ctx context.Context, req *SearchProductsReq
 		price := toFloat64Value(r["price"])
 		sales := toIntValue(r["sales"])
 		score := toFloat64Value(r["score"])
+		brandName, _ := r["brand_name"].(string)
 
 		products = append(products, &ProductSearchResult{
 			ProductID: productID,
@@ -138,12 +164,15 @@ func (l *SearchLogic) SearchProducts(ctx context.Context, req *SearchProductsReq
 			Price:     price,
 			Sales:     sales,
 			Score:     score,
+			BrandID:   toInt64Value(r["brand_id"]),
+			BrandName: brandName,
 		})
 	}
 
 	return &SearchProductsResponse{
 		Results: products,
 		Total:   total,
+		Facets:  facets,
 	}, nil
 }
 
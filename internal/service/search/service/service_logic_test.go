@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ecommerce-system/internal/pkg/search"
+	"ecommerce-system/internal/service/search/repository"
+)
+
+// fakeES 模拟 Elasticsearch 的 _search 接口：记录查询，按 term 条件过滤内存中的文档
+type fakeES struct {
+	docs    []map[string]interface{}
+	queries []map[string]interface{}
+}
+
+func (f *fakeES) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	w.Header().Set("Content-Type", "application/json")
+	if !strings.HasSuffix(r.URL.Path, "/_search") {
+		_, _ = w.Write([]byte(`{"version":{"number":"8.19.0"}}`))
+		return
+	}
+
+	var query map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.queries = append(f.queries, query)
+
+	hits := make([]map[string]interface{}, 0)
+	for _, doc := range f.docs {
+		if matchTerms(doc, termClauses(query)) {
+			hits = append(hits, map[string]interface{}{"_source": doc})
+		}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"hits": map[string]interface{}{
+			"total": map[string]interface{}{"value": len(hits)},
+			"hits":  hits,
+		},
+	})
+}
+
+// termClauses 取出 bool.must 中的 term 条件
+func termClauses(query map[string]interface{}) map[string]interface{} {
+	terms := map[string]interface{}{}
+	q, _ := query["query"].(map[string]interface{})
+	b, _ := q["bool"].(map[string]interface{})
+	must, _ := b["must"].([]interface{})
+	for _, c := range must {
+		clause, _ := c.(map[string]interface{})
+		if term, ok := clause["term"].(map[string]interface{}); ok {
+			for k, v := range term {
+				terms[k] = v
+			}
+		}
+	}
+	return terms
+}
+
+func matchTerms(doc, terms map[string]interface{}) bool {
+	for field, want := range terms {
+		if doc[field] != want {
+			return false
+		}
+	}
+	return true
+}
+
+func newTestSearchLogic(t *testing.T, docs ...map[string]interface{}) (*SearchLogic, *fakeES) {
+	t.Helper()
+	es := &fakeES{docs: docs}
+	srv := httptest.NewServer(es)
+	t.Cleanup(srv.Close)
+
+	client, err := search.NewElasticsearchClient(&search.Config{Addresses: []string{srv.URL}})
+	if err != nil {
+		t.Fatalf("NewElasticsearchClient: %v", err)
+	}
+	return NewSearchLogic(repository.NewSearchRepository(nil, client, nil)), es
+}
+
+func TestSearchProductsBrandFilter(t *testing.T) {
+	// JSON 解码后数字为 float64，与查询里的 term 值一致
+	logic, es := newTestSearchLogic(t,
+		map[string]interface{}{"product_id": float64(10), "name": "Rocket", "brand_id": float64(1), "brand_name": "Acme", "status": float64(1)},
+		map[string]interface{}{"product_id": float64(11), "name": "Laser", "brand_id": float64(2), "brand_name": "Globex", "status": float64(1)},
+		map[string]interface{}{"product_id": float64(12), "name": "Rope", "status": float64(1)},
+		map[string]interface{}{"product_id": float64(13), "name": "Magnet", "brand_id": float64(2), "brand_name": "Globex", "status": float64(0)},
+	)
+	ctx := context.Background()
+
+	resp, err := logic.SearchProducts(ctx, &SearchProductsRequest{BrandID: 2})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if got := termClauses(es.queries[0])["brand_id"]; got != float64(2) {
+		t.Fatalf("brand_id term = %v, want 2", got)
+	}
+	if resp.Total != 1 || len(resp.Results) != 1 {
+		t.Fatalf("unexpected brand-filtered results: %+v", resp.Results)
+	}
+	if r := resp.Results[0]; r.ProductID != 11 || r.BrandID != 2 || r.BrandName != "Globex" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	// 不传品牌时不加品牌条件
+	resp, err = logic.SearchProducts(ctx, &SearchProductsRequest{})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if _, ok := termClauses(es.queries[1])["brand_id"]; ok {
+		t.Fatalf("unexpected brand_id term without a brand filter: %v", es.queries[1])
+	}
+	if resp.Total != 3 {
+		t.Fatalf("unfiltered total = %d, want 3", resp.Total)
+	}
+}
@@ -40,4 +40,10 @@ type Config struct {
 	Database DatabaseConfig
 	BizRedis RedisConfig
 	Kafka    KafkaConfig
+	JWT      JWTConfig `json:",optional"`
+}
+
+// JWTConfig JWT配置（校验管理接口权限）
+type JWTConfig struct {
+	Secret string
 }
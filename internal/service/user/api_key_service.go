@@ -0,0 +1,239 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	v1 "ecommerce-system/api/user/v1"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+	userservice "ecommerce-system/internal/service/user/service"
+)
+
+// ApiKeyService 开放平台 API Key 服务
+type ApiKeyService struct {
+	v1.UnimplementedApiKeyServiceServer
+	svcCtx *ServiceContext
+	logic  *userservice.ApiKeyLogic
+}
+
+// NewApiKeyService 创建 API Key 服务
+func NewApiKeyService(svcCtx *ServiceContext) *ApiKeyService {
+	return &ApiKeyService{
+		svcCtx: svcCtx,
+		logic: userservice.NewApiKeyLogic(svcCtx.ApiKeyRepo, svcCtx.ApiKeyCipher,
+			svcCtx.Config.ApiKey.Scopes, svcCtx.Config.ApiKey.MaxKeysPerUser),
+	}
+}
+
+// CreateApiKey 签发 API Key
+func (s *ApiKeyService) CreateApiKey(ctx context.Context, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyResponse, error) {
+	userID, _ := utils.GetUserID(ctx)
+	key, secret, err := s.logic.CreateApiKey(ctx, &userservice.CreateApiKeyRequest{
+		OwnerUserID: userID,
+		Name:        req.Name,
+		Scopes:      req.Scopes,
+		RateLimit:   int(req.RateLimit),
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.CreateApiKeyResponse{
+		Code:    0,
+		Message: "创建成功，secret 只显示一次，请妥善保存",
+		Data:    convertApiKeyToProto(key),
+		Secret:  secret,
+	}, nil
+}
+
+// ListApiKeys 获取 API Key 列表
+func (s *ApiKeyService) ListApiKeys(ctx context.Context, req *v1.ListApiKeysRequest) (*v1.ListApiKeysResponse, error) {
+	userID, _ := utils.GetUserID(ctx)
+	keys, total, err := s.logic.ListApiKeys(ctx, userID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.ApiKey, 0, len(keys))
+	for _, k := range keys {
+		data = append(data, convertApiKeyToProto(k))
+	}
+	return &v1.ListApiKeysResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+		Total:   int32(total),
+	}, nil
+}
+
+// UpdateApiKey 更新 API Key
+func (s *ApiKeyService) UpdateApiKey(ctx context.Context, req *v1.UpdateApiKeyRequest) (*v1.UpdateApiKeyResponse, error) {
+	userID, _ := utils.GetUserID(ctx)
+	key, err := s.logic.UpdateApiKey(ctx, &userservice.UpdateApiKeyRequest{
+		OwnerUserID: userID,
+		KeyID:       req.KeyId,
+		Name:        req.Name,
+		Scopes:      req.Scopes,
+		RateLimit:   int(req.RateLimit),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.UpdateApiKeyResponse{
+		Code:    0,
+		Message: "更新成功",
+		Data:    convertApiKeyToProto(key),
+	}, nil
+}
+
+// RotateApiKey 轮换 secret
+func (s *ApiKeyService) RotateApiKey(ctx context.Context, req *v1.RotateApiKeyRequest) (*v1.RotateApiKeyResponse, error) {
+	userID, _ := utils.GetUserID(ctx)
+	key, secret, err := s.logic.RotateApiKey(ctx, userID, req.KeyId, time.Duration(req.GraceSeconds)*time.Second)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RotateApiKeyResponse{
+		Code:    0,
+		Message: "轮换成功，新 secret 只显示一次，请妥善保存",
+		Data:    convertApiKeyToProto(key),
+		Secret:  secret,
+	}, nil
+}
+
+// RevokeApiKey 吊销 API Key
+func (s *ApiKeyService) RevokeApiKey(ctx context.Context, req *v1.RevokeApiKeyRequest) (*v1.RevokeApiKeyResponse, error) {
+	userID, _ := utils.GetUserID(ctx)
+	if err := s.logic.RevokeApiKey(ctx, userID, req.KeyId); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RevokeApiKeyResponse{
+		Code:    0,
+		Message: "吊销成功",
+	}, nil
+}
+
+// GetApiKeyUsage 查询按天调用量
+func (s *ApiKeyService) GetApiKeyUsage(ctx context.Context, req *v1.GetApiKeyUsageRequest) (*v1.GetApiKeyUsageResponse, error) {
+	userID, _ := utils.GetUserID(ctx)
+	usage, err := s.logic.GetUsage(ctx, userID, req.KeyId, int(req.Days))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.ApiKeyUsage, 0, len(usage))
+	for _, u := range usage {
+		data = append(data, &v1.ApiKeyUsage{Day: u.Day, Calls: u.Calls, Errors: u.Errors})
+	}
+	return &v1.GetApiKeyUsageResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
+// ListApiKeyAuditLogs 查询调用审计日志
+func (s *ApiKeyService) ListApiKeyAuditLogs(ctx context.Context, req *v1.ListApiKeyAuditLogsRequest) (*v1.ListApiKeyAuditLogsResponse, error) {
+	userID, _ := utils.GetUserID(ctx)
+	logs, total, err := s.logic.ListAuditLogs(ctx, userID, req.KeyId, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.ApiKeyCall, 0, len(logs))
+	for _, l := range logs {
+		data = append(data, &v1.ApiKeyCall{
+			KeyId:      l.KeyID,
+			Method:     l.Method,
+			Path:       l.Path,
+			StatusCode: int32(l.StatusCode),
+			Result:     l.Result,
+			ClientIp:   l.ClientIP,
+			LatencyMs:  l.LatencyMs,
+			CreatedAt:  l.CreatedAt.UnixMilli(),
+		})
+	}
+	return &v1.ListApiKeyAuditLogsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+		Total:   int32(total),
+	}, nil
+}
+
+// GetApiKeyCredential 获取验签凭证（网关内部调用）
+func (s *ApiKeyService) GetApiKeyCredential(ctx context.Context, req *v1.GetApiKeyCredentialRequest) (*v1.GetApiKeyCredentialResponse, error) {
+	cred, err := s.logic.GetCredential(ctx, req.KeyId)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.GetApiKeyCredentialResponse{
+		Code:    0,
+		Message: "成功",
+		Data: &v1.ApiKeyCredential{
+			KeyId:       cred.KeyID,
+			OwnerUserId: cred.OwnerUserID,
+			Secrets:     cred.Secrets,
+			Scopes:      cred.Scopes,
+			RateLimit:   int32(cred.RateLimit),
+			Active:      cred.Active,
+		},
+	}, nil
+}
+
+// ReportApiKeyCalls 批量上报调用记录（网关内部调用）
+func (s *ApiKeyService) ReportApiKeyCalls(ctx context.Context, req *v1.ReportApiKeyCallsRequest) (*v1.ReportApiKeyCallsResponse, error) {
+	calls := make([]*model.ApiKeyAuditLog, 0, len(req.Calls))
+	for _, c := range req.Calls {
+		calls = append(calls, &model.ApiKeyAuditLog{
+			KeyID:      c.KeyId,
+			Method:     c.Method,
+			Path:       c.Path,
+			StatusCode: int(c.StatusCode),
+			Result:     c.Result,
+			ClientIP:   c.ClientIp,
+			LatencyMs:  c.LatencyMs,
+			CreatedAt:  time.UnixMilli(c.CreatedAt),
+		})
+	}
+	if err := s.logic.ReportCalls(ctx, calls); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.ReportApiKeyCallsResponse{
+		Code:    0,
+		Message: "成功",
+	}, nil
+}
+
+// convertApiKeyToProto 转换 API Key 模型为 Protobuf 消息
+func convertApiKeyToProto(k *model.ApiKey) *v1.ApiKey {
+	if k == nil {
+		return nil
+	}
+	return &v1.ApiKey{
+		KeyId:      k.KeyID,
+		Name:       k.Name,
+		Scopes:     k.ScopeList(),
+		RateLimit:  int32(k.RateLimit),
+		Status:     int32(k.Status),
+		ExpiresAt:  unixOrZero(k.ExpiresAt),
+		LastUsedAt: unixOrZero(k.LastUsedAt),
+		CreatedAt:  k.CreatedAt.Unix(),
+		RotatedAt:  unixOrZero(k.RotatedAt),
+	}
+}
+
+// unixOrZero 可空时间转 Unix 秒，nil 返回 0
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
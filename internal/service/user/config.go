@@ -1,7 +1,17 @@
 package user
 
 import (
+	"time"
+
 	"github.com/zeromicro/go-zero/zrpc"
+
+	"ecommerce-system/internal/pkg/captcha"
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/loginguard"
+	"ecommerce-system/internal/pkg/membership"
+	"ecommerce-system/internal/pkg/oidc"
+	"ecommerce-system/internal/pkg/sender"
+	"ecommerce-system/internal/pkg/verifycode"
 )
 
 // Config 用户服务配置
@@ -11,6 +21,39 @@ type Config struct {
 	// BizRedis 业务侧使用的 Redis 配置，避免与 zrpc.RpcServerConf 内置的 Redis 字段冲突
 	BizRedis RedisConfig
 	JWT      JWTConfig
+	// ApiKey 开放平台 API Key
+	ApiKey ApiKeyConfig `json:",optional"`
+	// VerifyCode 短信/邮件验证码
+	VerifyCode VerifyCodeConfig `json:",optional"`
+	// LoginGuard 登录防暴力破解：失败计数、递增等待和临时锁定
+	LoginGuard loginguard.Config `json:",optional"`
+	// Captcha 人机验证，登录失败次数过多后要求
+	Captcha captcha.Config `json:",optional"`
+	// OAuth 第三方（OIDC）登录
+	OAuth OAuthConfig `json:",optional"`
+	// MFA TOTP 两步验证
+	MFA MFAConfig `json:",optional"`
+	// PasswordReset 找回密码
+	PasswordReset PasswordResetConfig `json:",optional"`
+	// MessageRpc 消息服务地址，用于发送站内信；不配置时只发短信/邮件
+	MessageRpc client.RpcConf `json:",optional"`
+	// Membership 会员等级规则
+	Membership MembershipConfig `json:",optional"`
+	// DataSubject 个人数据导出和账号注销
+	DataSubject DataSubjectConfig `json:",optional"`
+	// RegionDataFile 行政区划数据文件（code,name 两列的 CSV），为空时使用内置数据
+	RegionDataFile string `json:",optional"`
+	// GeoIPFile IP 地址库（MaxMind GeoLite2-City/Country 格式），用于登录会话显示所在地；为空时只记录 IP
+	GeoIPFile string `json:",optional"`
+	// Kafka 配置（可选，不配置则不消费订单/退款事件，会员等级不会自动更新）
+	Kafka *KafkaConfig `json:",optional"`
+}
+
+// KafkaConfig Kafka配置
+type KafkaConfig struct {
+	Brokers       []string
+	Version       string `json:",optional"`
+	ConsumerGroup string `json:",optional"` // 默认 user-service
 }
 
 // DatabaseConfig 数据库配置
@@ -40,6 +83,72 @@ type RedisConfig struct {
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	Secret string
-	Expire int64 // 秒
+	Secret        string
+	Expire        int64 // 访问令牌有效期（秒）
+	RefreshExpire int64 `json:",default=2592000"` // 刷新令牌有效期（秒），默认 30 天
+}
+
+// ApiKeyConfig 开放平台 API Key 配置
+type ApiKeyConfig struct {
+	// EncryptionKey 加密存储 secret 的主密钥，未配置时不能签发 API Key
+	EncryptionKey  string   `json:",optional"`
+	MaxKeysPerUser int      `json:",default=10"`
+	Scopes         []string `json:",optional"` // 允许签发的权限范围，应与网关 OpenAPI.Scopes 一致
+}
+
+// VerifyCodeConfig 验证码配置：有效期、限流和发送通道
+type VerifyCodeConfig struct {
+	verifycode.Config
+	// RequireOnRegister 注册时填写了手机号/邮箱必须带验证码
+	RequireOnRegister bool          `json:",optional"`
+	SMS               sender.Config `json:",optional"` // 短信通道，目前只有 log 发送器
+	Email             sender.Config `json:",optional"` // 邮件通道，log 或 smtp
+}
+
+// OAuthConfig 第三方登录配置
+type OAuthConfig struct {
+	StateTTL  int64                 `json:",default=600"` // 授权 state 有效期（秒）
+	Providers []oidc.ProviderConfig `json:",optional"`
+}
+
+// MFAConfig 两步验证配置
+type MFAConfig struct {
+	// EncryptionKey 加密存储 TOTP 密钥的主密钥，未配置时不能开启两步验证
+	EncryptionKey string   `json:",optional"`
+	Issuer        string   `json:",optional"`    // 验证器应用中显示的名称，默认 Go Ecom
+	RequiredRoles []string `json:",optional"`    // 必须开启两步验证的角色，如 super_admin
+	ChallengeTTL  int64    `json:",default=300"` // 登录第二步有效期（秒）
+	MaxAttempts   int      `json:",default=5"`   // 登录第二步最多尝试次数
+}
+
+// PasswordResetConfig 找回密码配置
+type PasswordResetConfig struct {
+	TokenTTL       int64  `json:",default=1800"` // 重置链接有效期（秒）
+	ResendInterval int64  `json:",default=60"`   // 同一手机号/邮箱两次申请的最小间隔（秒）
+	ResetURL       string `json:",optional"`     // 前端重置密码页面，默认 http://localhost:5173/reset-password
+}
+
+// MembershipConfig 会员等级配置
+type MembershipConfig struct {
+	WindowDays       int               `json:",default=365"`  // 等级按最近多少天的消费评定
+	EvaluateInterval int64             `json:",default=3600"` // 周期降级评估间隔（秒），0 表示不评估
+	Tiers            []membership.Tier `json:",optional"`     // 等级定义，不配置时使用 membership.DefaultTiers
+}
+
+// DataSubjectConfig 个人数据导出和账号注销配置
+type DataSubjectConfig struct {
+	// Services 持有用户个人数据、实现了 DataSubjectService 的业务服务
+	Services []DataSubjectServiceConfig `json:",optional"`
+	// FileRpc 文件服务地址，存放导出包；不配置时不能申请导出
+	FileRpc        client.RpcConf `json:",optional"`
+	FileCategory   string         `json:",default=privacy"` // 导出包分类，文件服务和网关需配置为私有分类
+	DownloadTTL    int64          `json:",default=86400"`   // 下载地址有效期（秒）
+	ExportInterval int64          `json:",default=86400"`   // 两次导出申请的最小间隔（秒）
+}
+
+// DataSubjectServiceConfig 参与个人数据请求的业务服务
+type DataSubjectServiceConfig struct {
+	Name     string
+	Endpoint string
+	Timeout  time.Duration `json:",default=10s"`
 }
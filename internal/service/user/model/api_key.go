@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// API Key 状态
+const (
+	ApiKeyStatusActive  int8 = 1
+	ApiKeyStatusRevoked int8 = 2
+)
+
+// ApiKey 开放平台 API Key
+type ApiKey struct {
+	ID          uint64 `gorm:"primaryKey;column:id" json:"id"`
+	KeyID       string `gorm:"column:key_id;uniqueIndex;not null;size:40" json:"key_id"`
+	OwnerUserID uint64 `gorm:"column:owner_user_id;not null;index" json:"owner_user_id"`
+	Name        string `gorm:"column:name;not null;size:100" json:"name"`
+	// SecretCipher 当前 secret（AES-GCM 加密），PrevSecretCipher 为轮换前的 secret，宽限期内仍可验签
+	SecretCipher        string     `gorm:"column:secret_cipher;not null;size:255" json:"-"`
+	PrevSecretCipher    string     `gorm:"column:prev_secret_cipher;size:255" json:"-"`
+	PrevSecretExpiresAt *time.Time `gorm:"column:prev_secret_expires_at" json:"-"`
+	Scopes              string     `gorm:"column:scopes;size:500" json:"scopes"` // 逗号分隔
+	RateLimit           int        `gorm:"column:rate_limit;default:0" json:"rate_limit"`
+	Status              int8       `gorm:"column:status;default:1" json:"status"`
+	ExpiresAt           *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	LastUsedAt          *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	RotatedAt           *time.Time `gorm:"column:rotated_at" json:"rotated_at"`
+	RevokedAt           *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt           time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ApiKey) TableName() string {
+	return "api_key"
+}
+
+// ScopeList 权限范围列表
+func (k *ApiKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// Active 未吊销且未过期
+func (k *ApiKey) Active(now time.Time) bool {
+	if k.Status != ApiKeyStatusActive {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// ApiKeyAuditLog API Key 调用审计日志
+type ApiKeyAuditLog struct {
+	ID          uint64    `gorm:"primaryKey;column:id" json:"id"`
+	KeyID       string    `gorm:"column:key_id;not null;size:40;index:idx_key_created,priority:1" json:"key_id"`
+	OwnerUserID uint64    `gorm:"column:owner_user_id;not null" json:"owner_user_id"`
+	Method      string    `gorm:"column:method;size:10" json:"method"`
+	Path        string    `gorm:"column:path;size:255" json:"path"`
+	StatusCode  int       `gorm:"column:status_code" json:"status_code"`
+	Result      string    `gorm:"column:result;size:32" json:"result"`
+	ClientIP    string    `gorm:"column:client_ip;size:64" json:"client_ip"`
+	LatencyMs   int64     `gorm:"column:latency_ms" json:"latency_ms"`
+	CreatedAt   time.Time `gorm:"column:created_at;index:idx_key_created,priority:2" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ApiKeyAuditLog) TableName() string {
+	return "api_key_audit_log"
+}
+
+// ApiKeyUsage API Key 按天统计的调用量
+type ApiKeyUsage struct {
+	ID     uint64 `gorm:"primaryKey;column:id" json:"id"`
+	KeyID  string `gorm:"column:key_id;not null;size:40;uniqueIndex:uk_key_day,priority:1" json:"key_id"`
+	Day    string `gorm:"column:day;not null;type:char(10);uniqueIndex:uk_key_day,priority:2" json:"day"`
+	Calls  int64  `gorm:"column:calls;default:0" json:"calls"`
+	Errors int64  `gorm:"column:errors;default:0" json:"errors"`
+}
+
+// TableName 指定表名
+func (ApiKeyUsage) TableName() string {
+	return "api_key_usage"
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// 个人数据请求类型
+const (
+	DataRequestTypeExport = "export" // 数据导出
+	DataRequestTypeErase  = "erase"  // 注销删除
+)
+
+// 个人数据请求及各服务任务的状态
+const (
+	DataRequestStatusPending    int8 = 0
+	DataRequestStatusProcessing int8 = 1
+	DataRequestStatusCompleted  int8 = 2
+	DataRequestStatusFailed     int8 = 3
+)
+
+// DataRequestLocalService 用户服务本地数据对应的任务服务名
+const DataRequestLocalService = "user-service"
+
+// DataRequest 个人数据请求（导出或注销），记录保留作为审计依据
+type DataRequest struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	RequestNo  string     `gorm:"column:request_no;not null;size:32;uniqueIndex" json:"request_no"`
+	UserID     uint64     `gorm:"column:user_id;not null;index" json:"user_id"`
+	Type       string     `gorm:"column:type;not null;size:16" json:"type"`
+	Status     int8       `gorm:"column:status;not null;default:0" json:"status"`
+	Progress   int        `gorm:"column:progress;not null;default:0" json:"progress"` // 已完成任务占比（百分比）
+	FileID     string     `gorm:"column:file_id;size:64" json:"file_id"`              // 导出包在文件服务中的ID
+	Error      string     `gorm:"column:error;size:500" json:"error"`
+	OperatorID uint64     `gorm:"column:operator_id;not null;default:0" json:"operator_id"` // 最近一次发起处理的人，重试时为管理员
+	StartedAt  *time.Time `gorm:"column:started_at" json:"started_at"`
+	FinishedAt *time.Time `gorm:"column:finished_at" json:"finished_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (DataRequest) TableName() string {
+	return "user_data_request"
+}
+
+// Active 待处理或处理中
+func (r *DataRequest) Active() bool {
+	return r.Status == DataRequestStatusPending || r.Status == DataRequestStatusProcessing
+}
+
+// DataRequestTask 个人数据请求在单个服务中的处理情况
+type DataRequestTask struct {
+	ID         uint64     `gorm:"primaryKey;column:id" json:"id"`
+	RequestID  uint64     `gorm:"column:request_id;not null;uniqueIndex:uk_request_service,priority:1" json:"request_id"`
+	Service    string     `gorm:"column:service;not null;size:64;uniqueIndex:uk_request_service,priority:2" json:"service"`
+	Status     int8       `gorm:"column:status;not null;default:0" json:"status"`
+	Summary    string     `gorm:"column:summary;size:1000" json:"summary"`
+	Error      string     `gorm:"column:error;size:500" json:"error"`
+	FinishedAt *time.Time `gorm:"column:finished_at" json:"finished_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (DataRequestTask) TableName() string {
+	return "user_data_request_task"
+}
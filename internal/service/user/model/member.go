@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// 会员等级变更原因
+const (
+	MemberChangeOrderCompleted = "order_completed" // 订单完成后升级
+	MemberChangeRefunded       = "refunded"        // 退款后重新评定
+	MemberChangeExpired        = "expired"         // 周期评估：消费移出统计窗口后降级
+)
+
+// MemberSpend 会员消费记录：订单完成时写入，退款时记录退款金额，等级按窗口内记录统计
+type MemberSpend struct {
+	ID           uint64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID       uint64     `gorm:"column:user_id;not null" json:"user_id"`
+	OrderNo      string     `gorm:"column:order_no;not null;size:64;uniqueIndex" json:"order_no"`
+	Amount       float64    `gorm:"column:amount;type:decimal(10,2);not null" json:"amount"`
+	RefundAmount float64    `gorm:"column:refund_amount;type:decimal(10,2);not null;default:0" json:"refund_amount"`
+	CompletedAt  time.Time  `gorm:"column:completed_at;not null" json:"completed_at"`
+	RefundedAt   *time.Time `gorm:"column:refunded_at" json:"refunded_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (MemberSpend) TableName() string {
+	return "member_spend"
+}
+
+// MemberLevelLog 会员等级变更历史
+type MemberLevelLog struct {
+	ID         uint64    `gorm:"primaryKey;column:id" json:"id"`
+	UserID     uint64    `gorm:"column:user_id;not null" json:"user_id"`
+	FromLevel  int8      `gorm:"column:from_level;not null" json:"from_level"`
+	ToLevel    int8      `gorm:"column:to_level;not null" json:"to_level"`
+	Reason     string    `gorm:"column:reason;not null;size:32" json:"reason"`
+	GMV        float64   `gorm:"column:gmv;type:decimal(12,2);not null" json:"gmv"` // 评定时窗口内实付金额
+	OrderCount int       `gorm:"column:order_count;not null" json:"order_count"`    // 评定时窗口内订单数
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName 指定表名
+func (MemberLevelLog) TableName() string {
+	return "member_level_log"
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// RefreshToken 刷新令牌（只保存 SHA-256 哈希）
+type RefreshToken struct {
+	ID     uint64 `gorm:"primaryKey;column:id" json:"id"`
+	UserID uint64 `gorm:"column:user_id;not null;index" json:"user_id"`
+	// FamilyID 同一次登录轮换出的令牌共用，任一旧令牌被重放时整族吊销
+	FamilyID        string     `gorm:"column:family_id;not null;size:36;index" json:"family_id"`
+	TokenHash       string     `gorm:"column:token_hash;uniqueIndex;not null;size:64" json:"-"`
+	AccessJTI       string     `gorm:"column:access_jti;not null;size:36" json:"access_jti"`
+	AccessExpiresAt time.Time  `gorm:"column:access_expires_at" json:"access_expires_at"`
+	ExpiresAt       time.Time  `gorm:"column:expires_at" json:"expires_at"`
+	UsedAt          *time.Time `gorm:"column:used_at" json:"used_at"`
+	RevokedAt       *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt       time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Role 角色
+type Role struct {
+	ID          uint64    `gorm:"primaryKey;column:id" json:"id"`
+	Code        string    `gorm:"column:code;uniqueIndex;not null;size:50" json:"code"`
+	Name        string    `gorm:"column:name;not null;size:50" json:"name"`
+	Description string    `gorm:"column:description;size:255" json:"description"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+
+	Permissions []string `gorm:"-" json:"permissions"` // 来自 role_permission
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "role"
+}
+
+// Permission 权限点
+type Permission struct {
+	ID        uint64    `gorm:"primaryKey;column:id" json:"id"`
+	Code      string    `gorm:"column:code;uniqueIndex;not null;size:50" json:"code"`
+	Name      string    `gorm:"column:name;not null;size:100" json:"name"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Permission) TableName() string {
+	return "permission"
+}
+
+// RolePermission 角色权限关联
+type RolePermission struct {
+	ID         uint64 `gorm:"primaryKey;column:id" json:"id"`
+	RoleID     uint64 `gorm:"column:role_id;not null" json:"role_id"`
+	Permission string `gorm:"column:permission;not null;size:50" json:"permission"`
+}
+
+// TableName 指定表名
+func (RolePermission) TableName() string {
+	return "role_permission"
+}
+
+// UserRole 用户角色关联
+type UserRole struct {
+	ID        uint64    `gorm:"primaryKey;column:id" json:"id"`
+	UserID    uint64    `gorm:"column:user_id;not null" json:"user_id"`
+	RoleID    uint64    `gorm:"column:role_id;not null;index" json:"role_id"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName 指定表名
+func (UserRole) TableName() string {
+	return "user_role"
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// 账号安全事件
+const (
+	SecurityEventAccountLocked   = "account_locked"
+	SecurityEventAccountUnlocked = "account_unlocked"
+	SecurityEventPasswordReset   = "password_reset"
+	SecurityEventPasswordChanged = "password_changed"
+	SecurityEventDataExport      = "data_export"      // 申请导出个人数据
+	SecurityEventErasureRequest  = "erasure_request"  // 申请注销，账号已停用
+	SecurityEventAccountErased   = "account_erased"   // 个人数据已删除或匿名化
+	SecurityEventNewDeviceLogin  = "new_device_login" // 在没登录过的设备上登录
+	SecurityEventSessionRevoked  = "session_revoked"  // 下线登录设备
+)
+
+// SecurityLog 账号安全审计日志
+type SecurityLog struct {
+	ID         uint64    `gorm:"primaryKey;column:id" json:"id"`
+	UserID     uint64    `gorm:"column:user_id;not null" json:"user_id"`
+	Event      string    `gorm:"column:event;not null;size:32" json:"event"`
+	OperatorID uint64    `gorm:"column:operator_id;not null;default:0" json:"operator_id"` // 0 表示系统
+	ClientIP   string    `gorm:"column:client_ip;size:64" json:"client_ip"`
+	Detail     string    `gorm:"column:detail;size:255" json:"detail"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName 指定表名
+func (SecurityLog) TableName() string {
+	return "user_security_log"
+}
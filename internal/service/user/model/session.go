@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// UserSession 登录会话，一次登录（一个刷新令牌族）一条记录，刷新令牌时更新最近活跃时间
+type UserSession struct {
+	ID       uint64 `gorm:"primaryKey;column:id" json:"id"`
+	UserID   uint64 `gorm:"column:user_id;not null;index" json:"user_id"`
+	FamilyID string `gorm:"column:family_id;uniqueIndex;not null;size:36" json:"-"`
+	// DeviceFingerprint 设备指纹：客户端设备标识（X-Device-Id）或 User-Agent 的哈希，用于识别新设备
+	DeviceFingerprint string     `gorm:"column:device_fingerprint;not null;size:64" json:"device_fingerprint"`
+	DeviceName        string     `gorm:"column:device_name;size:64" json:"device_name"` // 由 User-Agent 解析，如 Chrome / Windows
+	UserAgent         string     `gorm:"column:user_agent;size:512" json:"user_agent"`
+	IP                string     `gorm:"column:ip;size:64" json:"ip"`              // 最近一次使用的 IP
+	Location          string     `gorm:"column:location;size:128" json:"location"` // IP 所在地（国家 省 市）
+	LoginIP           string     `gorm:"column:login_ip;size:64" json:"login_ip"`
+	AccessJTI         string     `gorm:"column:access_jti;size:36" json:"-"` // 最近签发的访问令牌 jti，用于标记当前会话
+	LastSeenAt        time.Time  `gorm:"column:last_seen_at" json:"last_seen_at"`
+	ExpiresAt         time.Time  `gorm:"column:expires_at" json:"expires_at"` // 刷新令牌过期时间，过期后会话失效
+	RevokedAt         *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt         time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_session"
+}
+
+// Active 会话是否仍有效（未吊销且刷新令牌未过期）
+func (s *UserSession) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -40,6 +40,9 @@ type Address struct {
 	District      string         `gorm:"column:district;not null;size:50" json:"district"`
 	Detail        string         `gorm:"column:detail;not null;size:200" json:"detail"`
 	PostalCode    string         `gorm:"column:postal_code;size:10" json:"postal_code"`
+	ProvinceCode  string         `gorm:"column:province_code;size:6" json:"province_code"` // 行政区划代码，旧地址在下次修改时补齐
+	CityCode      string         `gorm:"column:city_code;size:6" json:"city_code"`
+	DistrictCode  string         `gorm:"column:district_code;size:6" json:"district_code"`
 	IsDefault     int8           `gorm:"column:is_default;default:0" json:"is_default"`
 	CreatedAt     time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt     time.Time      `gorm:"column:updated_at" json:"updated_at"`
@@ -51,6 +54,13 @@ func (Address) TableName() string {
 	return "address"
 }
 
+// 凭证类型
+const (
+	CredentialTypePassword int8 = 1
+	CredentialTypeOIDC     int8 = 5 // 第三方 OIDC 登录，credential_key 为 "{提供方}:{sub}"
+	CredentialTypeTOTP     int8 = 6 // 两步验证，credential_value 为加密后的 TOTP 密钥，extra 为 model.TOTPExtra
+)
+
 // Credential 用户凭证模型
 type Credential struct {
 	ID              uint64    `gorm:"primaryKey;column:id" json:"id"`
@@ -63,6 +73,14 @@ type Credential struct {
 	UpdatedAt       time.Time `gorm:"column:updated_at" json:"updated_at"`
 }
 
+// TOTPExtra 两步验证凭证的 extra
+type TOTPExtra struct {
+	Confirmed     bool       `json:"confirmed"` // 用户已用验证码确认绑定
+	ConfirmedAt   *time.Time `json:"confirmed_at,omitempty"`
+	LastStep      int64      `json:"last_step"`                // 最近一次通过的时间步，防止验证码重放
+	RecoveryCodes []string   `json:"recovery_codes,omitempty"` // 未使用的恢复码哈希
+}
+
 // TableName 指定表名
 func (Credential) TableName() string {
 	return "credential"
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// ApiKeyRepository API Key 仓储接口
+type ApiKeyRepository interface {
+	Create(ctx context.Context, key *model.ApiKey) error
+	GetByKeyID(ctx context.Context, keyID string) (*model.ApiKey, error)
+	ListByOwner(ctx context.Context, ownerUserID uint64, page, pageSize int) ([]*model.ApiKey, int64, error)
+	CountActiveByOwner(ctx context.Context, ownerUserID uint64) (int64, error)
+	Update(ctx context.Context, key *model.ApiKey) error
+	// SaveCalls 批量写入审计日志、累加按天用量并更新最近使用时间（同一事务）
+	SaveCalls(ctx context.Context, logs []*model.ApiKeyAuditLog) error
+	ListAuditLogs(ctx context.Context, keyID string, page, pageSize int) ([]*model.ApiKeyAuditLog, int64, error)
+	ListUsage(ctx context.Context, keyID string, since string) ([]*model.ApiKeyUsage, error)
+}
+
+// apiKeyRepository API Key 仓储实现
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewApiKeyRepository 创建 API Key 仓储
+func NewApiKeyRepository(db *gorm.DB) ApiKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+// Create 创建 API Key
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.ApiKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetByKeyID 根据 Key ID 获取
+func (r *apiKeyRepository) GetByKeyID(ctx context.Context, keyID string) (*model.ApiKey, error) {
+	var key model.ApiKey
+	err := r.db.WithContext(ctx).Where("key_id = ?", keyID).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByOwner 分页获取用户名下的 API Key
+func (r *apiKeyRepository) ListByOwner(ctx context.Context, ownerUserID uint64, page, pageSize int) ([]*model.ApiKey, int64, error) {
+	var keys []*model.ApiKey
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.ApiKey{}).Where("owner_user_id = ?", ownerUserID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&keys).Error; err != nil {
+		return nil, 0, err
+	}
+	return keys, total, nil
+}
+
+// CountActiveByOwner 统计用户名下未吊销的 API Key 数量
+func (r *apiKeyRepository) CountActiveByOwner(ctx context.Context, ownerUserID uint64) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&model.ApiKey{}).
+		Where("owner_user_id = ? AND status = ?", ownerUserID, model.ApiKeyStatusActive).
+		Count(&total).Error
+	return total, err
+}
+
+// Update 更新 API Key
+func (r *apiKeyRepository) Update(ctx context.Context, key *model.ApiKey) error {
+	return r.db.WithContext(ctx).Save(key).Error
+}
+
+// SaveCalls 批量写入调用记录
+func (r *apiKeyRepository) SaveCalls(ctx context.Context, logs []*model.ApiKeyAuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	// 按 Key + 天聚合用量，减少 upsert 次数
+	type usageKey struct{ keyID, day string }
+	usage := make(map[usageKey]*model.ApiKeyUsage)
+	lastUsed := make(map[string]time.Time)
+	for _, l := range logs {
+		k := usageKey{l.KeyID, l.CreatedAt.Format("2006-01-02")}
+		u, ok := usage[k]
+		if !ok {
+			u = &model.ApiKeyUsage{KeyID: k.keyID, Day: k.day}
+			usage[k] = u
+		}
+		u.Calls++
+		if l.StatusCode >= 400 {
+			u.Errors++
+		}
+		if l.CreatedAt.After(lastUsed[l.KeyID]) {
+			lastUsed[l.KeyID] = l.CreatedAt
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(logs, 200).Error; err != nil {
+			return err
+		}
+		for _, u := range usage {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "key_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"calls":  gorm.Expr("calls + ?", u.Calls),
+					"errors": gorm.Expr("errors + ?", u.Errors),
+				}),
+			}).Create(u).Error
+			if err != nil {
+				return err
+			}
+		}
+		for keyID, t := range lastUsed {
+			if err := tx.Model(&model.ApiKey{}).Where("key_id = ?", keyID).
+				UpdateColumn("last_used_at", t).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListAuditLogs 分页获取审计日志（按时间倒序）
+func (r *apiKeyRepository) ListAuditLogs(ctx context.Context, keyID string, page, pageSize int) ([]*model.ApiKeyAuditLog, int64, error) {
+	var logs []*model.ApiKeyAuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.ApiKeyAuditLog{}).Where("key_id = ?", keyID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// ListUsage 获取 since（yyyy-MM-dd，含）之后的按天用量
+func (r *apiKeyRepository) ListUsage(ctx context.Context, keyID string, since string) ([]*model.ApiKeyUsage, error) {
+	var usage []*model.ApiKeyUsage
+	err := r.db.WithContext(ctx).
+		Where("key_id = ? AND day >= ?", keyID, since).
+		Order("day ASC").
+		Find(&usage).Error
+	return usage, err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -14,7 +15,9 @@ type CredentialRepository interface {
 	Create(ctx context.Context, credential *model.Credential) error
 	GetByUserIDAndType(ctx context.Context, userID uint64, credentialType int8) (*model.Credential, error)
 	GetByKeyAndType(ctx context.Context, key string, credentialType int8) (*model.Credential, error)
+	ListByUserID(ctx context.Context, userID uint64) ([]*model.Credential, error)
 	Update(ctx context.Context, credential *model.Credential) error
+	SwapExtra(ctx context.Context, id uint64, oldExtra, newExtra string) (bool, error)
 	Delete(ctx context.Context, id uint64) error
 }
 
@@ -65,11 +68,30 @@ func (r *credentialRepository) GetByKeyAndType(ctx context.Context, key string,
 	return &credential, nil
 }
 
+// ListByUserID 获取用户的全部凭证
+func (r *credentialRepository) ListByUserID(ctx context.Context, userID uint64) ([]*model.Credential, error) {
+	var credentials []*model.Credential
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&credentials).Error
+	return credentials, err
+}
+
 // Update 更新凭证
 func (r *credentialRepository) Update(ctx context.Context, credential *model.Credential) error {
 	return r.db.WithContext(ctx).Save(credential).Error
 }
 
+// SwapExtra 凭证的 extra 仍为 oldExtra 时才更新为 newExtra，返回是否更新成功。
+// extra 是 JSON 列，按 JSON 值比较，不受键顺序和空白的影响。
+func (r *credentialRepository) SwapExtra(ctx context.Context, id uint64, oldExtra, newExtra string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Credential{}).
+		Where("id = ? AND extra = CAST(? AS JSON)", id, oldExtra).
+		Updates(map[string]interface{}{"extra": newExtra, "updated_at": time.Now()})
+	return res.RowsAffected > 0, res.Error
+}
+
 // Delete 删除凭证
 func (r *credentialRepository) Delete(ctx context.Context, id uint64) error {
 	return r.db.WithContext(ctx).Delete(&model.Credential{}, id).Error
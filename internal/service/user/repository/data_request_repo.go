@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// DataRequestFilter 个人数据请求列表筛选条件
+type DataRequestFilter struct {
+	UserID uint64 // 0 表示全部
+	Type   string // 空表示全部
+	Status int8   // -1 表示全部
+}
+
+// DataRequestRepository 个人数据请求仓储接口
+type DataRequestRepository interface {
+	// Create 创建请求和各服务任务
+	Create(ctx context.Context, req *model.DataRequest, tasks []*model.DataRequestTask) error
+	// GetByRequestNo 根据请求编号获取，不存在时返回 nil
+	GetByRequestNo(ctx context.Context, requestNo string) (*model.DataRequest, error)
+	// LatestByUser 用户最近一次指定类型的请求，没有时返回 nil
+	LatestByUser(ctx context.Context, userID uint64, reqType string) (*model.DataRequest, error)
+	// List 分页获取请求（按ID倒序）
+	List(ctx context.Context, filter DataRequestFilter, page, pageSize int) ([]*model.DataRequest, int64, error)
+	// ListUnfinished 按ID升序获取待处理和处理中的请求，用于服务重启后继续处理
+	ListUnfinished(ctx context.Context, limit int) ([]*model.DataRequest, error)
+	// Update 更新请求
+	Update(ctx context.Context, req *model.DataRequest) error
+	// ListTasks 获取请求的全部任务
+	ListTasks(ctx context.Context, requestID uint64) ([]*model.DataRequestTask, error)
+	// UpdateTask 更新任务
+	UpdateTask(ctx context.Context, task *model.DataRequestTask) error
+}
+
+// dataRequestRepository 个人数据请求仓储实现
+type dataRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewDataRequestRepository 创建个人数据请求仓储
+func NewDataRequestRepository(db *gorm.DB) DataRequestRepository {
+	return &dataRequestRepository{
+		db: db,
+	}
+}
+
+// Create 创建请求和任务
+func (r *dataRequestRepository) Create(ctx context.Context, req *model.DataRequest, tasks []*model.DataRequestTask) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(req).Error; err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			t.RequestID = req.ID
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		return tx.Create(&tasks).Error
+	})
+}
+
+// GetByRequestNo 根据请求编号获取
+func (r *dataRequestRepository) GetByRequestNo(ctx context.Context, requestNo string) (*model.DataRequest, error) {
+	var req model.DataRequest
+	err := r.db.WithContext(ctx).Where("request_no = ?", requestNo).First(&req).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// LatestByUser 用户最近一次请求
+func (r *dataRequestRepository) LatestByUser(ctx context.Context, userID uint64, reqType string) (*model.DataRequest, error) {
+	var req model.DataRequest
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ?", userID, reqType).
+		Order("id DESC").First(&req).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// List 分页获取请求
+func (r *dataRequestRepository) List(ctx context.Context, filter DataRequestFilter, page, pageSize int) ([]*model.DataRequest, int64, error) {
+	var reqs []*model.DataRequest
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.DataRequest{})
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status >= 0 {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&reqs).Error; err != nil {
+		return nil, 0, err
+	}
+	return reqs, total, nil
+}
+
+// ListUnfinished 获取未完成的请求
+func (r *dataRequestRepository) ListUnfinished(ctx context.Context, limit int) ([]*model.DataRequest, error) {
+	var reqs []*model.DataRequest
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []int8{model.DataRequestStatusPending, model.DataRequestStatusProcessing}).
+		Order("id ASC").Limit(limit).Find(&reqs).Error
+	return reqs, err
+}
+
+// Update 更新请求
+func (r *dataRequestRepository) Update(ctx context.Context, req *model.DataRequest) error {
+	return r.db.WithContext(ctx).Save(req).Error
+}
+
+// ListTasks 获取请求的全部任务
+func (r *dataRequestRepository) ListTasks(ctx context.Context, requestID uint64) ([]*model.DataRequestTask, error) {
+	var tasks []*model.DataRequestTask
+	err := r.db.WithContext(ctx).Where("request_id = ?", requestID).Order("id ASC").Find(&tasks).Error
+	return tasks, err
+}
+
+// UpdateTask 更新任务
+func (r *dataRequestRepository) UpdateTask(ctx context.Context, task *model.DataRequestTask) error {
+	return r.db.WithContext(ctx).Save(task).Error
+}
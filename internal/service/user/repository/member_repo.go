@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// MemberRepository 会员消费记录和等级变更历史仓储接口
+type MemberRepository interface {
+	// RecordSpend 写入消费记录，同一订单重复写入时忽略，返回是否新写入
+	RecordSpend(ctx context.Context, spend *model.MemberSpend) (bool, error)
+	// MarkRefunded 记录订单退款金额，返回对应的消费记录（订单未完成过时为 nil）
+	MarkRefunded(ctx context.Context, orderNo string, refundAmount float64, at time.Time) (*model.MemberSpend, error)
+	// Stats 统计 since 之后完成的订单：扣除退款后的实付金额和未全额退款的订单数
+	Stats(ctx context.Context, userID uint64, since time.Time) (float64, int, error)
+	// ChangeLevel 等级仍为 log.FromLevel 时更新为 log.ToLevel 并写入历史，返回是否更新
+	ChangeLevel(ctx context.Context, log *model.MemberLevelLog) (bool, error)
+	// ListLevelLogs 分页获取等级变更历史（按时间倒序）
+	ListLevelLogs(ctx context.Context, userID uint64, page, pageSize int) ([]*model.MemberLevelLog, int64, error)
+	// ListLeveledUsers 按 ID 升序获取 ID 大于 afterID 的非普通会员，用于周期评估
+	ListLeveledUsers(ctx context.Context, afterID uint64, limit int) ([]*model.User, error)
+}
+
+// memberRepository 会员仓储实现
+type memberRepository struct {
+	db *gorm.DB
+}
+
+// NewMemberRepository 创建会员仓储
+func NewMemberRepository(db *gorm.DB) MemberRepository {
+	return &memberRepository{
+		db: db,
+	}
+}
+
+// RecordSpend 写入消费记录
+func (r *memberRepository) RecordSpend(ctx context.Context, spend *model.MemberSpend) (bool, error) {
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(spend)
+	return res.RowsAffected > 0, res.Error
+}
+
+// MarkRefunded 记录退款金额（退款金额不超过实付金额，重复消息按最后一次为准）
+func (r *memberRepository) MarkRefunded(ctx context.Context, orderNo string, refundAmount float64, at time.Time) (*model.MemberSpend, error) {
+	var spend model.MemberSpend
+	err := r.db.WithContext(ctx).Where("order_no = ?", orderNo).First(&spend).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if refundAmount <= 0 || refundAmount > spend.Amount {
+		refundAmount = spend.Amount
+	}
+	spend.RefundAmount = refundAmount
+	spend.RefundedAt = &at
+	err = r.db.WithContext(ctx).Model(&spend).Updates(map[string]interface{}{
+		"refund_amount": refundAmount,
+		"refunded_at":   at,
+		"updated_at":    at,
+	}).Error
+	return &spend, err
+}
+
+// Stats 统计窗口内的实付金额和订单数
+func (r *memberRepository) Stats(ctx context.Context, userID uint64, since time.Time) (float64, int, error) {
+	var row struct {
+		GMV    float64
+		Orders int
+	}
+	err := r.db.WithContext(ctx).Model(&model.MemberSpend{}).
+		Select("COALESCE(SUM(amount - refund_amount), 0) AS gmv, COUNT(CASE WHEN refund_amount < amount THEN 1 END) AS orders").
+		Where("user_id = ? AND completed_at >= ?", userID, since).
+		Scan(&row).Error
+	return row.GMV, row.Orders, err
+}
+
+// ChangeLevel 按原等级条件更新，避免并发评定互相覆盖
+func (r *memberRepository) ChangeLevel(ctx context.Context, log *model.MemberLevelLog) (bool, error) {
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.User{}).
+			Where("id = ? AND member_level = ?", log.UserID, log.FromLevel).
+			Updates(map[string]interface{}{"member_level": log.ToLevel, "updated_at": log.CreatedAt})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		changed = true
+		return tx.Create(log).Error
+	})
+	return changed, err
+}
+
+// ListLevelLogs 分页获取等级变更历史
+func (r *memberRepository) ListLevelLogs(ctx context.Context, userID uint64, page, pageSize int) ([]*model.MemberLevelLog, int64, error) {
+	var logs []*model.MemberLevelLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MemberLevelLog{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// ListLeveledUsers 获取非普通会员
+func (r *memberRepository) ListLeveledUsers(ctx context.Context, afterID uint64, limit int) ([]*model.User, error) {
+	var users []*model.User
+	err := r.db.WithContext(ctx).
+		Where("id > ? AND member_level > 0", afterID).
+		Order("id ASC").Limit(limit).Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// PersonalData 用户服务中保存的个人数据
+type PersonalData struct {
+	User            *model.User
+	Addresses       []*model.Address
+	Credentials     []*model.Credential
+	RefreshTokens   []*model.RefreshToken
+	Sessions        []*model.UserSession
+	ApiKeys         []*model.ApiKey
+	MemberSpends    []*model.MemberSpend
+	MemberLevelLogs []*model.MemberLevelLog
+	SecurityLogs    []*model.SecurityLog
+}
+
+// ErasedCounts 用户服务本地数据的删除结果，key 为数据类别
+type ErasedCounts struct {
+	Deleted    map[string]int64
+	Anonymized map[string]int64
+}
+
+// PersonalDataRepository 用户服务本地个人数据的导出和删除
+type PersonalDataRepository interface {
+	// Export 读取用户的全部个人数据（含已软删除的地址）
+	Export(ctx context.Context, userID uint64) (*PersonalData, error)
+	// Erase 在一个事务中删除地址、凭证、登录令牌和会话、API Key、角色和会员记录，
+	// 清除安全日志中的 IP，并匿名化、软删除用户记录。重复执行结果相同
+	Erase(ctx context.Context, userID uint64, nickname string) (*ErasedCounts, error)
+}
+
+// personalDataRepository 本地个人数据仓储实现
+type personalDataRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalDataRepository 创建本地个人数据仓储
+func NewPersonalDataRepository(db *gorm.DB) PersonalDataRepository {
+	return &personalDataRepository{
+		db: db,
+	}
+}
+
+// Export 读取个人数据
+func (r *personalDataRepository) Export(ctx context.Context, userID uint64) (*PersonalData, error) {
+	db := r.db.WithContext(ctx)
+	data := &PersonalData{User: &model.User{}}
+	if err := db.Unscoped().Where("id = ?", userID).First(data.User).Error; err != nil {
+		return nil, fmt.Errorf("user: %w", err)
+	}
+	queries := []struct {
+		name string
+		dest interface{}
+		db   *gorm.DB
+	}{
+		{"address", &data.Addresses, db.Unscoped().Where("user_id = ?", userID)},
+		{"credential", &data.Credentials, db.Where("user_id = ?", userID)},
+		{"refresh_token", &data.RefreshTokens, db.Where("user_id = ?", userID)},
+		{"user_session", &data.Sessions, db.Where("user_id = ?", userID)},
+		{"api_key", &data.ApiKeys, db.Where("owner_user_id = ?", userID)},
+		{"member_spend", &data.MemberSpends, db.Where("user_id = ?", userID)},
+		{"member_level_log", &data.MemberLevelLogs, db.Where("user_id = ?", userID)},
+		{"user_security_log", &data.SecurityLogs, db.Where("user_id = ?", userID)},
+	}
+	for _, q := range queries {
+		if err := q.db.Order("id ASC").Find(q.dest).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", q.name, err)
+		}
+	}
+	return data, nil
+}
+
+// Erase 删除和匿名化本地个人数据
+func (r *personalDataRepository) Erase(ctx context.Context, userID uint64, nickname string) (*ErasedCounts, error) {
+	counts := &ErasedCounts{Deleted: map[string]int64{}, Anonymized: map[string]int64{}}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keyIDs := tx.Model(&model.ApiKey{}).Select("key_id").Where("owner_user_id = ?", userID)
+		deletes := []struct {
+			name  string
+			model interface{}
+			query *gorm.DB
+		}{
+			{"api_key_audit_log", &model.ApiKeyAuditLog{}, tx.Where("owner_user_id = ?", userID)},
+			{"api_key_usage", &model.ApiKeyUsage{}, tx.Where("key_id IN (?)", keyIDs)},
+			{"api_key", &model.ApiKey{}, tx.Where("owner_user_id = ?", userID)},
+			{"address", &model.Address{}, tx.Unscoped().Where("user_id = ?", userID)},
+			{"credential", &model.Credential{}, tx.Where("user_id = ?", userID)},
+			{"refresh_token", &model.RefreshToken{}, tx.Where("user_id = ?", userID)},
+			{"user_session", &model.UserSession{}, tx.Where("user_id = ?", userID)},
+			{"user_role", &model.UserRole{}, tx.Where("user_id = ?", userID)},
+			{"member_spend", &model.MemberSpend{}, tx.Where("user_id = ?", userID)},
+			{"member_level_log", &model.MemberLevelLog{}, tx.Where("user_id = ?", userID)},
+		}
+		for _, d := range deletes {
+			res := d.query.Delete(d.model)
+			if res.Error != nil {
+				return fmt.Errorf("%s: %w", d.name, res.Error)
+			}
+			counts.Deleted[d.name] = res.RowsAffected
+		}
+
+		// 安全日志作为审计记录保留，只清除 IP
+		res := tx.Model(&model.SecurityLog{}).
+			Where("user_id = ? AND client_ip IS NOT NULL AND client_ip <> ''", userID).
+			Update("client_ip", nil)
+		if res.Error != nil {
+			return fmt.Errorf("user_security_log: %w", res.Error)
+		}
+		counts.Anonymized["user_security_log"] = res.RowsAffected
+
+		// 用户记录保留ID供订单等关联，去除全部个人信息后软删除；用户名唯一，按ID生成
+		now := time.Now()
+		res = tx.Unscoped().Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"username":     fmt.Sprintf("deleted_%d", userID),
+			"nickname":     nickname,
+			"phone":        nil,
+			"email":        nil,
+			"avatar":       nil,
+			"gender":       0,
+			"birthday":     nil,
+			"status":       0,
+			"member_level": 0,
+			"points":       0,
+			"updated_at":   now,
+			"deleted_at":   gorm.Expr("COALESCE(deleted_at, ?)", now),
+		})
+		if res.Error != nil {
+			return fmt.Errorf("user: %w", res.Error)
+		}
+		counts.Anonymized["user"] = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
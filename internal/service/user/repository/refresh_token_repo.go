@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// RefreshTokenRepository 刷新令牌仓储接口
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	// MarkUsed 标记令牌已轮换，令牌已被使用或已吊销时返回 false（并发刷新只有一个能成功）
+	MarkUsed(ctx context.Context, id uint64, at time.Time) (bool, error)
+	// RevokeFamily 吊销整族令牌，返回吊销前仍有效的记录（用于把对应访问令牌加入黑名单）
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) ([]*model.RefreshToken, error)
+	// RevokeByUser 吊销用户的全部令牌，返回吊销前仍有效的记录
+	RevokeByUser(ctx context.Context, userID uint64, at time.Time) ([]*model.RefreshToken, error)
+}
+
+// refreshTokenRepository 刷新令牌仓储实现
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository 创建刷新令牌仓储
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+// Create 创建刷新令牌
+func (r *refreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// GetByTokenHash 根据令牌哈希获取
+func (r *refreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed 标记令牌已轮换
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id uint64, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"used_at": at, "updated_at": at})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeFamily 吊销整族令牌
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) ([]*model.RefreshToken, error) {
+	return r.revoke(ctx, "family_id = ?", familyID, at)
+}
+
+// RevokeByUser 吊销用户的全部令牌
+func (r *refreshTokenRepository) RevokeByUser(ctx context.Context, userID uint64, at time.Time) ([]*model.RefreshToken, error) {
+	return r.revoke(ctx, "user_id = ?", userID, at)
+}
+
+// revoke 在事务中锁定未吊销的记录并设置吊销时间
+func (r *refreshTokenRepository) revoke(ctx context.Context, query string, arg interface{}, at time.Time) ([]*model.RefreshToken, error) {
+	var tokens []*model.RefreshToken
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(query, arg).Where("revoked_at IS NULL").
+			Find(&tokens).Error; err != nil {
+			return err
+		}
+		if len(tokens) == 0 {
+			return nil
+		}
+		ids := make([]uint64, 0, len(tokens))
+		for _, t := range tokens {
+			ids = append(ids, t.ID)
+		}
+		return tx.Model(&model.RefreshToken{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"revoked_at": at, "updated_at": at}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// RoleRepository 角色仓储接口
+type RoleRepository interface {
+	ListRoles(ctx context.Context) ([]*model.Role, error)
+	GetRoleByCode(ctx context.Context, code string) (*model.Role, error)
+	// GetUserRoles 获取用户的角色（含权限）
+	GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error)
+	AssignRole(ctx context.Context, userID, roleID uint64) error
+	RemoveRole(ctx context.Context, userID, roleID uint64) error
+	CountRoleUsers(ctx context.Context, roleID uint64) (int64, error)
+	// SaveRole 按编码创建或更新角色，并用 permissions 替换角色的权限
+	SaveRole(ctx context.Context, role *model.Role, permissions []string) error
+	SavePermissions(ctx context.Context, permissions []*model.Permission) error
+}
+
+// roleRepository 角色仓储实现
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository 创建角色仓储
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+// ListRoles 获取全部角色
+func (r *roleRepository) ListRoles(ctx context.Context) ([]*model.Role, error) {
+	var roles []*model.Role
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, r.fillPermissions(ctx, roles)
+}
+
+// GetRoleByCode 根据编码获取角色
+func (r *roleRepository) GetRoleByCode(ctx context.Context, code string) (*model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetUserRoles 获取用户的角色
+func (r *roleRepository) GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := r.db.WithContext(ctx).
+		Joins("JOIN user_role ON user_role.role_id = role.id").
+		Where("user_role.user_id = ?", userID).
+		Order("role.id ASC").
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, r.fillPermissions(ctx, roles)
+}
+
+// AssignRole 分配角色（已分配时忽略）
+func (r *roleRepository) AssignRole(ctx context.Context, userID, roleID uint64) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserRole{UserID: userID, RoleID: roleID, CreatedAt: time.Now()}).Error
+}
+
+// RemoveRole 移除用户角色
+func (r *roleRepository) RemoveRole(ctx context.Context, userID, roleID uint64) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&model.UserRole{}).Error
+}
+
+// CountRoleUsers 统计拥有某角色的用户数
+func (r *roleRepository) CountRoleUsers(ctx context.Context, roleID uint64) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&model.UserRole{}).Where("role_id = ?", roleID).Count(&total).Error
+	return total, err
+}
+
+// SaveRole 创建或更新角色及其权限
+func (r *roleRepository) SaveRole(ctx context.Context, role *model.Role, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		role.CreatedAt, role.UpdatedAt = now, now
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+		}).Create(role).Error; err != nil {
+			return err
+		}
+		// ON DUPLICATE KEY UPDATE 时拿不到已有记录的 ID，重新查一次
+		if err := tx.Where("code = ?", role.Code).First(role).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("role_id = ?", role.ID).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+		rows := make([]*model.RolePermission, 0, len(permissions))
+		for _, p := range permissions {
+			rows = append(rows, &model.RolePermission{RoleID: role.ID, Permission: p})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// SavePermissions 同步权限点定义
+func (r *roleRepository) SavePermissions(ctx context.Context, permissions []*model.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(&permissions).Error
+}
+
+// fillPermissions 填充角色的权限列表
+func (r *roleRepository) fillPermissions(ctx context.Context, roles []*model.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	ids := make([]uint64, 0, len(roles))
+	byID := make(map[uint64]*model.Role, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+		byID[role.ID] = role
+	}
+
+	var rows []*model.RolePermission
+	if err := r.db.WithContext(ctx).Where("role_id IN ?", ids).Order("id ASC").Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		byID[row.RoleID].Permissions = append(byID[row.RoleID].Permissions, row.Permission)
+	}
+	return nil
+}
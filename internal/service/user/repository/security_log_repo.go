@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// SecurityLogRepository 账号安全审计日志仓储接口
+type SecurityLogRepository interface {
+	Create(ctx context.Context, log *model.SecurityLog) error
+}
+
+// securityLogRepository 账号安全审计日志仓储实现
+type securityLogRepository struct {
+	db *gorm.DB
+}
+
+// NewSecurityLogRepository 创建账号安全审计日志仓储
+func NewSecurityLogRepository(db *gorm.DB) SecurityLogRepository {
+	return &securityLogRepository{
+		db: db,
+	}
+}
+
+// Create 写入审计日志
+func (r *securityLogRepository) Create(ctx context.Context, log *model.SecurityLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ecommerce-system/internal/service/user/model"
+)
+
+// SessionActivity 刷新令牌时更新的会话信息
+type SessionActivity struct {
+	IP        string
+	Location  string
+	UserAgent string
+	AccessJTI string
+	ExpiresAt time.Time
+	At        time.Time
+}
+
+// SessionRepository 登录会话仓储接口
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.UserSession) error
+	GetByID(ctx context.Context, id uint64) (*model.UserSession, error)
+	// Touch 刷新令牌时更新最近活跃时间、IP 和过期时间
+	Touch(ctx context.Context, familyID string, activity *SessionActivity) error
+	// ListByUser 分页获取用户的会话（按最近活跃倒序），activeOnly 时只返回未吊销且未过期的
+	ListByUser(ctx context.Context, userID uint64, activeOnly bool, page, pageSize int) ([]*model.UserSession, int64, error)
+	// DeviceSeen 用户是否在该设备上登录过，firstLogin 表示用户还没有任何会话记录
+	DeviceSeen(ctx context.Context, userID uint64, fingerprint string) (seen, firstLogin bool, err error)
+	// RevokeFamily 标记令牌族对应的会话已吊销
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	// RevokeByUser 标记用户的全部会话已吊销
+	RevokeByUser(ctx context.Context, userID uint64, at time.Time) error
+}
+
+// sessionRepository 登录会话仓储实现
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository 创建登录会话仓储
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+// Create 创建会话
+func (r *sessionRepository) Create(ctx context.Context, session *model.UserSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetByID 根据ID获取会话
+func (r *sessionRepository) GetByID(ctx context.Context, id uint64) (*model.UserSession, error) {
+	var session model.UserSession
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Touch 更新会话活跃信息
+func (r *sessionRepository) Touch(ctx context.Context, familyID string, activity *SessionActivity) error {
+	updates := map[string]interface{}{
+		"access_jti":   activity.AccessJTI,
+		"expires_at":   activity.ExpiresAt,
+		"last_seen_at": activity.At,
+		"updated_at":   activity.At,
+	}
+	// 拿不到客户端信息（如内部调用）时保留原值
+	if activity.IP != "" {
+		updates["ip"] = activity.IP
+		updates["location"] = activity.Location
+	}
+	if activity.UserAgent != "" {
+		updates["user_agent"] = activity.UserAgent
+	}
+	return r.db.WithContext(ctx).Model(&model.UserSession{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(updates).Error
+}
+
+// ListByUser 分页获取用户的会话
+func (r *sessionRepository) ListByUser(ctx context.Context, userID uint64, activeOnly bool, page, pageSize int) ([]*model.UserSession, int64, error) {
+	var sessions []*model.UserSession
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.UserSession{}).Where("user_id = ?", userID)
+	if activeOnly {
+		query = query.Where("revoked_at IS NULL AND expires_at > ?", time.Now())
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("last_seen_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&sessions).Error; err != nil {
+		return nil, 0, err
+	}
+	return sessions, total, nil
+}
+
+// DeviceSeen 查询设备是否登录过
+func (r *sessionRepository) DeviceSeen(ctx context.Context, userID uint64, fingerprint string) (bool, bool, error) {
+	var seen, total int64
+	if err := r.db.WithContext(ctx).Model(&model.UserSession{}).
+		Where("user_id = ? AND device_fingerprint = ?", userID, fingerprint).Count(&seen).Error; err != nil {
+		return false, false, err
+	}
+	if seen > 0 {
+		return true, false, nil
+	}
+	if err := r.db.WithContext(ctx).Model(&model.UserSession{}).
+		Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return false, false, err
+	}
+	return false, total == 0, nil
+}
+
+// RevokeFamily 吊销令牌族对应的会话
+func (r *sessionRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	return r.revoke(ctx, "family_id = ?", familyID, at)
+}
+
+// RevokeByUser 吊销用户的全部会话
+func (r *sessionRepository) RevokeByUser(ctx context.Context, userID uint64, at time.Time) error {
+	return r.revoke(ctx, "user_id = ?", userID, at)
+}
+
+// revoke 设置未吊销会话的吊销时间
+func (r *sessionRepository) revoke(ctx context.Context, query string, arg interface{}, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.UserSession{}).
+		Where(query, arg).Where("revoked_at IS NULL").
+		Updates(map[string]interface{}{"revoked_at": at, "updated_at": at}).Error
+}
@@ -18,6 +18,8 @@ type UserRepository interface {
 	GetByPhone(ctx context.Context, phone string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	Update(ctx context.Context, user *model.User) error
+	// UpdateStatus 只更新用户状态，避免整行保存时把空手机号/邮箱写成空字符串
+	UpdateStatus(ctx context.Context, id uint64, status int8) error
 	Delete(ctx context.Context, id uint64) error
 	// 列表查询（管理后台）
 	List(ctx context.Context, page, pageSize int, keyword string, status *int8) ([]*model.User, int64, error)
@@ -112,6 +114,11 @@ func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// UpdateStatus 更新用户状态
+func (r *userRepository) UpdateStatus(ctx context.Context, id uint64, status int8) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("status", status).Error
+}
+
 // Delete 删除用户（软删除）
 func (r *userRepository) Delete(ctx context.Context, id uint64) error {
 	return r.db.WithContext(ctx).Delete(&model.User{}, id).Error
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"context"
+
+	v1 "ecommerce-system/api/user/v1"
+	"ecommerce-system/internal/service/user/model"
+	userservice "ecommerce-system/internal/service/user/service"
+)
+
+// RoleService 角色服务
+type RoleService struct {
+	v1.UnimplementedRoleServiceServer
+	svcCtx *ServiceContext
+	logic  *userservice.RoleLogic
+}
+
+// NewRoleService 创建角色服务
+func NewRoleService(svcCtx *ServiceContext) *RoleService {
+	return &RoleService{
+		svcCtx: svcCtx,
+		logic:  userservice.NewRoleLogic(svcCtx.RoleRepo, svcCtx.UserRepo, svcCtx.Denylist, accessExpire(svcCtx.Config)),
+	}
+}
+
+// ListRoles 获取全部角色
+func (s *RoleService) ListRoles(ctx context.Context, req *v1.ListRolesRequest) (*v1.ListRolesResponse, error) {
+	roles, err := s.logic.ListRoles(ctx)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.ListRolesResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertRolesToProto(roles),
+	}, nil
+}
+
+// GetUserRoles 获取用户的角色
+func (s *RoleService) GetUserRoles(ctx context.Context, req *v1.GetUserRolesRequest) (*v1.GetUserRolesResponse, error) {
+	roles, err := s.logic.GetUserRoles(ctx, uint64(req.UserId))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.GetUserRolesResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertRolesToProto(roles),
+	}, nil
+}
+
+// AssignUserRole 给用户分配角色
+func (s *RoleService) AssignUserRole(ctx context.Context, req *v1.AssignUserRoleRequest) (*v1.AssignUserRoleResponse, error) {
+	roles, err := s.logic.AssignRole(ctx, uint64(req.UserId), req.RoleCode)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.AssignUserRoleResponse{
+		Code:    0,
+		Message: "分配成功",
+		Data:    convertRolesToProto(roles),
+	}, nil
+}
+
+// RemoveUserRole 移除用户角色
+func (s *RoleService) RemoveUserRole(ctx context.Context, req *v1.RemoveUserRoleRequest) (*v1.RemoveUserRoleResponse, error) {
+	roles, err := s.logic.RemoveRole(ctx, uint64(req.UserId), req.RoleCode)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.RemoveUserRoleResponse{
+		Code:    0,
+		Message: "移除成功",
+		Data:    convertRolesToProto(roles),
+	}, nil
+}
+
+// convertRolesToProto 转换角色列表为 Protobuf 消息
+func convertRolesToProto(roles []*model.Role) []*v1.Role {
+	data := make([]*v1.Role, 0, len(roles))
+	for _, r := range roles {
+		data = append(data, &v1.Role{
+			Id:          int64(r.ID),
+			Code:        r.Code,
+			Name:        r.Name,
+			Description: r.Description,
+			Permissions: r.Permissions,
+		})
+	}
+	return data
+}
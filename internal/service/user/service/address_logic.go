@@ -2,28 +2,55 @@ package service
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	"ecommerce-system/internal/pkg/cache"
 	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/region"
 	"ecommerce-system/internal/service/user/model"
 	"ecommerce-system/internal/service/user/repository"
 )
 
+var (
+	// 大陆收货电话：手机号或带区号的固话（可带分机号）
+	mobilePattern   = regexp.MustCompile(`^1[3-9]\d{9}$`)
+	landlinePattern = regexp.MustCompile(`^0\d{2,3}-?\d{7,8}(-\d{1,6})?$`)
+	// 港澳台收货电话：可带国际区号
+	intlPhonePattern  = regexp.MustCompile(`^(\+?\d{1,4}-?)?\d{6,12}$`)
+	postalCodePattern = regexp.MustCompile(`^\d{6}$`)
+	intlPostalPattern = regexp.MustCompile(`^[0-9A-Za-z-]{3,10}$`)
+)
+
 // AddressLogic 地址业务逻辑
 type AddressLogic struct {
 	addressRepo repository.AddressRepository
 	cache       *cache.CacheOperations
+	regions     *region.Dataset
 }
 
-// NewAddressLogic 创建地址业务逻辑
-func NewAddressLogic(addressRepo repository.AddressRepository, cache *cache.CacheOperations) *AddressLogic {
+// NewAddressLogic 创建地址业务逻辑，regions 为 nil 时使用内置的行政区划数据
+func NewAddressLogic(addressRepo repository.AddressRepository, cache *cache.CacheOperations, regions *region.Dataset) *AddressLogic {
+	if regions == nil {
+		regions = region.Default()
+	}
 	return &AddressLogic{
 		addressRepo: addressRepo,
 		cache:       cache,
+		regions:     regions,
 	}
 }
 
+// GetRegions 获取下级行政区划，parentCode 为空时返回省级列表
+func (l *AddressLogic) GetRegions(ctx context.Context, parentCode string) ([]region.Region, error) {
+	regions, ok := l.regions.Children(strings.TrimSpace(parentCode))
+	if !ok {
+		return nil, apperrors.NewError(apperrors.CodeNotFound, "行政区划不存在")
+	}
+	return regions, nil
+}
+
 // GetAddressListRequest 获取地址列表请求
 type GetAddressListRequest struct {
 	UserID uint64
@@ -77,6 +104,9 @@ type AddAddressRequest struct {
 	Detail        string
 	PostalCode    string
 	IsDefault     int8
+	ProvinceCode  string // 区划代码，填写时优先于名称
+	CityCode      string
+	DistrictCode  string
 }
 
 // AddAddressResponse 添加地址响应
@@ -96,12 +126,21 @@ func (l *AddressLogic) AddAddress(ctx context.Context, req *AddAddressRequest) (
 	if req.ReceiverPhone == "" {
 		return nil, apperrors.NewInvalidParamError("收货人电话不能为空")
 	}
-	if req.Province == "" || req.City == "" || req.District == "" {
-		return nil, apperrors.NewInvalidParamError("地址信息不完整")
-	}
 	if req.Detail == "" {
 		return nil, apperrors.NewInvalidParamError("详细地址不能为空")
 	}
+	resolved, err := l.regions.Resolve(
+		firstNonEmpty(req.ProvinceCode, req.Province),
+		firstNonEmpty(req.CityCode, req.City),
+		firstNonEmpty(req.DistrictCode, req.District),
+	)
+	if err != nil {
+		return nil, apperrors.NewInvalidParamError(err.Error())
+	}
+	phone, postalCode, err := normalizeContact(resolved.Mainland(), req.ReceiverPhone, req.PostalCode)
+	if err != nil {
+		return nil, err
+	}
 
 	// 如果设置为默认地址，先取消其他默认地址
 	if req.IsDefault == 1 {
@@ -114,14 +153,12 @@ func (l *AddressLogic) AddAddress(ctx context.Context, req *AddAddressRequest) (
 	address := &model.Address{
 		UserID:        req.UserID,
 		ReceiverName:  req.ReceiverName,
-		ReceiverPhone: req.ReceiverPhone,
-		Province:      req.Province,
-		City:          req.City,
-		District:      req.District,
+		ReceiverPhone: phone,
 		Detail:        req.Detail,
-		PostalCode:    req.PostalCode,
+		PostalCode:    postalCode,
 		IsDefault:     req.IsDefault,
 	}
+	applyRegion(address, resolved)
 
 	if err := l.addressRepo.Create(ctx, address); err != nil {
 		return nil, apperrors.NewInternalError("创建地址失败: " + err.Error())
@@ -150,6 +187,9 @@ type UpdateAddressRequest struct {
 	Detail        string
 	PostalCode    string
 	IsDefault     int8
+	ProvinceCode  string // 区划代码，填写时优先于名称
+	CityCode      string
+	DistrictCode  string
 }
 
 // UpdateAddressResponse 更新地址响应
@@ -185,23 +225,41 @@ func (l *AddressLogic) UpdateAddress(ctx context.Context, req *UpdateAddressRequ
 	if req.ReceiverName != "" {
 		address.ReceiverName = req.ReceiverName
 	}
-	if req.ReceiverPhone != "" {
-		address.ReceiverPhone = req.ReceiverPhone
-	}
-	if req.Province != "" {
-		address.Province = req.Province
-	}
-	if req.City != "" {
-		address.City = req.City
-	}
-	if req.District != "" {
-		address.District = req.District
-	}
 	if req.Detail != "" {
 		address.Detail = req.Detail
 	}
-	if req.PostalCode != "" {
-		address.PostalCode = req.PostalCode
+
+	// 修改了任意一级省市区时，未填写的级别沿用原地址，整体重新校验；
+	// 没有修改但缺少区划代码的旧地址顺带补齐，补不齐也不影响修改其他字段
+	province := firstNonEmpty(req.ProvinceCode, req.Province)
+	city := firstNonEmpty(req.CityCode, req.City)
+	district := firstNonEmpty(req.DistrictCode, req.District)
+	if province != "" || city != "" || district != "" {
+		resolved, err := l.regions.Resolve(
+			firstNonEmpty(province, address.Province),
+			firstNonEmpty(city, address.City),
+			firstNonEmpty(district, address.District),
+		)
+		if err != nil {
+			return nil, apperrors.NewInvalidParamError(err.Error())
+		}
+		applyRegion(address, resolved)
+	} else if address.ProvinceCode == "" {
+		if resolved, err := l.regions.Resolve(address.Province, address.City, address.District); err == nil {
+			applyRegion(address, resolved)
+		}
+	}
+
+	if req.ReceiverPhone != "" || req.PostalCode != "" {
+		mainland := address.ProvinceCode == "" || region.IsMainland(address.ProvinceCode)
+		phone, postalCode, err := normalizeContact(mainland, firstNonEmpty(req.ReceiverPhone, address.ReceiverPhone), req.PostalCode)
+		if err != nil {
+			return nil, err
+		}
+		address.ReceiverPhone = phone
+		if req.PostalCode != "" {
+			address.PostalCode = postalCode
+		}
 	}
 
 	// 如果设置为默认地址
@@ -274,3 +332,45 @@ func (l *AddressLogic) DeleteAddress(ctx context.Context, req *DeleteAddressRequ
 
 	return &DeleteAddressResponse{}, nil
 }
+
+// applyRegion 写入规范化后的省市区名称和区划代码
+func applyRegion(address *model.Address, resolved *region.Address) {
+	address.Province = resolved.Province.Name
+	address.City = resolved.City.Name
+	address.District = resolved.District.Name
+	address.ProvinceCode = resolved.Province.Code
+	address.CityCode = resolved.City.Code
+	address.DistrictCode = resolved.District.Code
+}
+
+// normalizeContact 校验收货电话和邮编格式，去掉电话中的空格和大陆号码的 +86 前缀；邮编可以不填
+func normalizeContact(mainland bool, phone, postalCode string) (string, string, error) {
+	phone = strings.ReplaceAll(strings.TrimSpace(phone), " ", "")
+	postalCode = strings.TrimSpace(postalCode)
+	if mainland {
+		phone = strings.TrimPrefix(strings.TrimPrefix(phone, "+86"), "-")
+		if !mobilePattern.MatchString(phone) && !landlinePattern.MatchString(phone) {
+			return "", "", apperrors.NewInvalidParamError("收货人电话格式不正确，请填写手机号或带区号的固定电话")
+		}
+		if postalCode != "" && !postalCodePattern.MatchString(postalCode) {
+			return "", "", apperrors.NewInvalidParamError("邮政编码应为 6 位数字")
+		}
+		return phone, postalCode, nil
+	}
+	if !intlPhonePattern.MatchString(phone) {
+		return "", "", apperrors.NewInvalidParamError("收货人电话格式不正确")
+	}
+	if postalCode != "" && !intlPostalPattern.MatchString(postalCode) {
+		return "", "", apperrors.NewInvalidParamError("邮政编码格式不正确")
+	}
+	return phone, postalCode, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package service
+
+import "testing"
+
+func TestNormalizeContact(t *testing.T) {
+	cases := []struct {
+		mainland          bool
+		phone, postalCode string
+		wantPhone         string
+		wantErr           bool
+	}{
+		{true, "13800138000", "518000", "13800138000", false},
+		{true, "+86 138 0013 8000", "", "13800138000", false},
+		{true, "0755-86013388", "", "0755-86013388", false},
+		{true, "010-12345678-801", "", "010-12345678-801", false},
+		{true, "12800138000", "", "", true}, // 号段不对
+		{true, "13800138000", "51800", "", true},
+		{false, "+852 9123 4567", "", "+85291234567", false},
+		{false, "91234567", "999077", "91234567", false},
+		{false, "abc", "", "", true},
+	}
+	for _, c := range cases {
+		phone, _, err := normalizeContact(c.mainland, c.phone, c.postalCode)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%q/%q: expected error", c.phone, c.postalCode)
+			}
+			continue
+		}
+		if err != nil || phone != c.wantPhone {
+			t.Errorf("%q/%q: expected %q, got %q (%v)", c.phone, c.postalCode, c.wantPhone, phone, err)
+		}
+	}
+}
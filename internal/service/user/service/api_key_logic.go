@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"ecommerce-system/internal/pkg/apikey"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// scopePattern 权限范围格式：资源:操作，如 orders:read
+var scopePattern = regexp.MustCompile(`^[a-z_]+:[a-z_]+$`)
+
+// ApiKeyLogic 开放平台 API Key 业务逻辑
+type ApiKeyLogic struct {
+	repo           repository.ApiKeyRepository
+	cipher         *apikey.Cipher
+	allowedScopes  map[string]struct{}
+	maxKeysPerUser int
+}
+
+// NewApiKeyLogic 创建 API Key 业务逻辑。cipher 为 nil 时（未配置加密密钥）不允许签发和轮换；
+// allowedScopes 为空时只校验格式，不校验权限范围名
+func NewApiKeyLogic(repo repository.ApiKeyRepository, cipher *apikey.Cipher, allowedScopes []string, maxKeysPerUser int) *ApiKeyLogic {
+	allowed := make(map[string]struct{}, len(allowedScopes))
+	for _, s := range allowedScopes {
+		allowed[s] = struct{}{}
+	}
+	return &ApiKeyLogic{
+		repo:           repo,
+		cipher:         cipher,
+		allowedScopes:  allowed,
+		maxKeysPerUser: maxKeysPerUser,
+	}
+}
+
+// CreateApiKeyRequest 签发 API Key 请求
+type CreateApiKeyRequest struct {
+	OwnerUserID uint64
+	Name        string
+	Scopes      []string
+	RateLimit   int
+	ExpiresAt   int64 // Unix 秒，0 表示永不过期
+}
+
+// CreateApiKey 签发 API Key，返回的 secret 只在这里出现一次
+func (l *ApiKeyLogic) CreateApiKey(ctx context.Context, req *CreateApiKeyRequest) (*model.ApiKey, string, error) {
+	if req.OwnerUserID == 0 {
+		return nil, "", apperrors.NewUnauthorizedError("未授权，请先登录")
+	}
+	if l.cipher == nil {
+		return nil, "", apperrors.NewInternalError("API Key 加密密钥未配置")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, "", apperrors.NewInvalidParamError("名称不能为空且不能超过100个字符")
+	}
+	scopes, err := l.normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(scopes) == 0 {
+		return nil, "", apperrors.NewInvalidParamError("至少需要一个权限范围")
+	}
+	if req.RateLimit < 0 {
+		return nil, "", apperrors.NewInvalidParamError("限流值不能为负数")
+	}
+	var expiresAt *time.Time
+	if req.ExpiresAt > 0 {
+		t := time.Unix(req.ExpiresAt, 0)
+		if !t.After(time.Now()) {
+			return nil, "", apperrors.NewInvalidParamError("过期时间必须晚于当前时间")
+		}
+		expiresAt = &t
+	}
+
+	if l.maxKeysPerUser > 0 {
+		count, err := l.repo.CountActiveByOwner(ctx, req.OwnerUserID)
+		if err != nil {
+			return nil, "", apperrors.NewInternalError("查询 API Key 失败: " + err.Error())
+		}
+		if count >= int64(l.maxKeysPerUser) {
+			return nil, "", apperrors.NewInvalidParamError("API Key 数量已达上限")
+		}
+	}
+
+	keyID, secret, err := apikey.GenerateKey()
+	if err != nil {
+		return nil, "", apperrors.NewInternalError("生成 API Key 失败")
+	}
+	secretCipher, err := l.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, "", apperrors.NewInternalError("加密 secret 失败")
+	}
+
+	key := &model.ApiKey{
+		KeyID:        keyID,
+		OwnerUserID:  req.OwnerUserID,
+		Name:         name,
+		SecretCipher: secretCipher,
+		Scopes:       strings.Join(scopes, ","),
+		RateLimit:    req.RateLimit,
+		Status:       model.ApiKeyStatusActive,
+		ExpiresAt:    expiresAt,
+	}
+	if err := l.repo.Create(ctx, key); err != nil {
+		return nil, "", apperrors.NewInternalError("创建 API Key 失败: " + err.Error())
+	}
+	return key, secret, nil
+}
+
+// ListApiKeys 分页获取用户名下的 API Key
+func (l *ApiKeyLogic) ListApiKeys(ctx context.Context, ownerUserID uint64, page, pageSize int) ([]*model.ApiKey, int64, error) {
+	if ownerUserID == 0 {
+		return nil, 0, apperrors.NewUnauthorizedError("未授权，请先登录")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	keys, total, err := l.repo.ListByOwner(ctx, ownerUserID, page, pageSize)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("查询 API Key 失败: " + err.Error())
+	}
+	return keys, total, nil
+}
+
+// UpdateApiKeyRequest 更新 API Key 请求
+type UpdateApiKeyRequest struct {
+	OwnerUserID uint64
+	KeyID       string
+	Name        string   // 为空表示不修改
+	Scopes      []string // 为空表示不修改
+	RateLimit   int      // 小于 0 表示不修改
+}
+
+// UpdateApiKey 更新名称、权限范围和限流（网关凭证缓存过期后生效）
+func (l *ApiKeyLogic) UpdateApiKey(ctx context.Context, req *UpdateApiKeyRequest) (*model.ApiKey, error) {
+	key, err := l.getOwnedKey(ctx, req.OwnerUserID, req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.Status != model.ApiKeyStatusActive {
+		return nil, apperrors.NewInvalidParamError("API Key 已吊销")
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		if len(name) > 100 {
+			return nil, apperrors.NewInvalidParamError("名称不能超过100个字符")
+		}
+		key.Name = name
+	}
+	if len(req.Scopes) > 0 {
+		scopes, err := l.normalizeScopes(req.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		key.Scopes = strings.Join(scopes, ",")
+	}
+	if req.RateLimit >= 0 {
+		key.RateLimit = req.RateLimit
+	}
+
+	if err := l.repo.Update(ctx, key); err != nil {
+		return nil, apperrors.NewInternalError("更新 API Key 失败: " + err.Error())
+	}
+	return key, nil
+}
+
+// RotateApiKey 轮换 secret：旧 secret 在 grace 内仍可验签，便于合作方无停机切换
+func (l *ApiKeyLogic) RotateApiKey(ctx context.Context, ownerUserID uint64, keyID string, grace time.Duration) (*model.ApiKey, string, error) {
+	if l.cipher == nil {
+		return nil, "", apperrors.NewInternalError("API Key 加密密钥未配置")
+	}
+	key, err := l.getOwnedKey(ctx, ownerUserID, keyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.Status != model.ApiKeyStatusActive {
+		return nil, "", apperrors.NewInvalidParamError("API Key 已吊销")
+	}
+	if grace < 0 || grace > 7*24*time.Hour {
+		return nil, "", apperrors.NewInvalidParamError("宽限期必须在 0 到 7 天之间")
+	}
+
+	secret, err := apikey.GenerateSecret()
+	if err != nil {
+		return nil, "", apperrors.NewInternalError("生成 secret 失败")
+	}
+	secretCipher, err := l.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, "", apperrors.NewInternalError("加密 secret 失败")
+	}
+
+	now := time.Now()
+	if grace > 0 {
+		prevExpiresAt := now.Add(grace)
+		key.PrevSecretCipher = key.SecretCipher
+		key.PrevSecretExpiresAt = &prevExpiresAt
+	} else {
+		key.PrevSecretCipher = ""
+		key.PrevSecretExpiresAt = nil
+	}
+	key.SecretCipher = secretCipher
+	key.RotatedAt = &now
+
+	if err := l.repo.Update(ctx, key); err != nil {
+		return nil, "", apperrors.NewInternalError("轮换 secret 失败: " + err.Error())
+	}
+	return key, secret, nil
+}
+
+// RevokeApiKey 吊销 API Key（不可恢复）
+func (l *ApiKeyLogic) RevokeApiKey(ctx context.Context, ownerUserID uint64, keyID string) error {
+	key, err := l.getOwnedKey(ctx, ownerUserID, keyID)
+	if err != nil {
+		return err
+	}
+	if key.Status == model.ApiKeyStatusRevoked {
+		return nil
+	}
+	now := time.Now()
+	key.Status = model.ApiKeyStatusRevoked
+	key.RevokedAt = &now
+	key.PrevSecretCipher = ""
+	key.PrevSecretExpiresAt = nil
+	if err := l.repo.Update(ctx, key); err != nil {
+		return apperrors.NewInternalError("吊销 API Key 失败: " + err.Error())
+	}
+	return nil
+}
+
+// GetUsage 获取最近 days 天的按天用量
+func (l *ApiKeyLogic) GetUsage(ctx context.Context, ownerUserID uint64, keyID string, days int) ([]*model.ApiKeyUsage, error) {
+	if _, err := l.getOwnedKey(ctx, ownerUserID, keyID); err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		days = 7
+	}
+	if days > 90 {
+		days = 90
+	}
+	since := time.Now().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	usage, err := l.repo.ListUsage(ctx, keyID, since)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用量失败: " + err.Error())
+	}
+	return usage, nil
+}
+
+// ListAuditLogs 分页获取审计日志
+func (l *ApiKeyLogic) ListAuditLogs(ctx context.Context, ownerUserID uint64, keyID string, page, pageSize int) ([]*model.ApiKeyAuditLog, int64, error) {
+	if _, err := l.getOwnedKey(ctx, ownerUserID, keyID); err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	logs, total, err := l.repo.ListAuditLogs(ctx, keyID, page, pageSize)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("查询审计日志失败: " + err.Error())
+	}
+	return logs, total, nil
+}
+
+// ApiKeyCredential 网关验签所需的凭证
+type ApiKeyCredential struct {
+	KeyID       string
+	OwnerUserID uint64
+	Secrets     []string // 当前 secret 在前，宽限期内的旧 secret 在后
+	Scopes      []string
+	RateLimit   int
+	Active      bool
+}
+
+// GetCredential 获取验签凭证（仅供网关调用）
+func (l *ApiKeyLogic) GetCredential(ctx context.Context, keyID string) (*ApiKeyCredential, error) {
+	if l.cipher == nil {
+		return nil, apperrors.NewInternalError("API Key 加密密钥未配置")
+	}
+	key, err := l.repo.GetByKeyID(ctx, keyID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询 API Key 失败: " + err.Error())
+	}
+	if key == nil {
+		return nil, apperrors.NewNotFoundError("API Key 不存在")
+	}
+
+	now := time.Now()
+	cred := &ApiKeyCredential{
+		KeyID:       key.KeyID,
+		OwnerUserID: key.OwnerUserID,
+		Scopes:      key.ScopeList(),
+		RateLimit:   key.RateLimit,
+		Active:      key.Active(now),
+	}
+	if !cred.Active {
+		return cred, nil
+	}
+
+	secret, err := l.cipher.Decrypt(key.SecretCipher)
+	if err != nil {
+		return nil, apperrors.NewInternalError("解密 secret 失败")
+	}
+	cred.Secrets = append(cred.Secrets, secret)
+	if key.PrevSecretCipher != "" && key.PrevSecretExpiresAt != nil && now.Before(*key.PrevSecretExpiresAt) {
+		if prev, err := l.cipher.Decrypt(key.PrevSecretCipher); err == nil {
+			cred.Secrets = append(cred.Secrets, prev)
+		}
+	}
+	return cred, nil
+}
+
+// ReportCalls 保存网关上报的调用记录（审计日志 + 按天用量），未知的 Key 直接丢弃
+func (l *ApiKeyLogic) ReportCalls(ctx context.Context, calls []*model.ApiKeyAuditLog) error {
+	owners := make(map[string]uint64)
+	valid := make([]*model.ApiKeyAuditLog, 0, len(calls))
+	for _, c := range calls {
+		owner, ok := owners[c.KeyID]
+		if !ok {
+			key, err := l.repo.GetByKeyID(ctx, c.KeyID)
+			if err != nil {
+				return apperrors.NewInternalError("查询 API Key 失败: " + err.Error())
+			}
+			if key != nil {
+				owner = key.OwnerUserID
+			}
+			owners[c.KeyID] = owner
+		}
+		if owner == 0 {
+			continue
+		}
+		c.OwnerUserID = owner
+		valid = append(valid, c)
+	}
+	if err := l.repo.SaveCalls(ctx, valid); err != nil {
+		return apperrors.NewInternalError("保存调用记录失败: " + err.Error())
+	}
+	return nil
+}
+
+// getOwnedKey 获取当前用户名下的 Key，不属于该用户时按不存在处理
+func (l *ApiKeyLogic) getOwnedKey(ctx context.Context, ownerUserID uint64, keyID string) (*model.ApiKey, error) {
+	if ownerUserID == 0 {
+		return nil, apperrors.NewUnauthorizedError("未授权，请先登录")
+	}
+	if keyID == "" {
+		return nil, apperrors.NewInvalidParamError("Key ID 不能为空")
+	}
+	key, err := l.repo.GetByKeyID(ctx, keyID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询 API Key 失败: " + err.Error())
+	}
+	if key == nil || key.OwnerUserID != ownerUserID {
+		return nil, apperrors.NewNotFoundError("API Key 不存在")
+	}
+	return key, nil
+}
+
+// normalizeScopes 去重并校验权限范围
+func (l *ApiKeyLogic) normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(scopes))
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !scopePattern.MatchString(s) {
+			return nil, apperrors.NewInvalidParamError("权限范围格式无效: " + s)
+		}
+		if len(l.allowedScopes) > 0 {
+			if _, ok := l.allowedScopes[s]; !ok {
+				return nil, apperrors.NewInvalidParamError("未知的权限范围: " + s)
+			}
+		}
+		if _, dup := seen[s]; dup {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	return out, nil
+}
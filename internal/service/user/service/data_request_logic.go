@@ -0,0 +1,702 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+
+	privacyv1 "ecommerce-system/api/privacy/v1"
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/constants"
+	"ecommerce-system/internal/pkg/datasubject"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/idgen"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// dataRequestLockTTL 单个请求处理锁的有效期，覆盖最慢的一次完整处理
+const dataRequestLockTTL = 30 * time.Minute
+
+// DataParticipant 持有个人数据的业务服务，由 client.DataSubjectClient 实现
+type DataParticipant interface {
+	Export(ctx context.Context, userID uint64, requestNo string) ([]*privacyv1.DataSection, error)
+	Erase(ctx context.Context, userID uint64, requestNo string) ([]*privacyv1.ErasureResult, error)
+}
+
+// ArchiveStore 导出包存储，由文件服务客户端实现
+type ArchiveStore interface {
+	Upload(ctx context.Context, data []byte, fileName, fileType, category string) (string, error)
+	GetFileURL(ctx context.Context, fileID string, expireSeconds int64, userID uint64) (string, int64, error)
+}
+
+// DataRequestPolicy 个人数据请求策略
+type DataRequestPolicy struct {
+	Category       string        // 导出包在文件服务中的分类，需配置为私有分类
+	DownloadTTL    time.Duration // 下载地址有效期
+	ExportInterval time.Duration // 两次导出申请的最小间隔
+}
+
+// DataRequestDetail 请求详情
+type DataRequestDetail struct {
+	Request           *model.DataRequest
+	Tasks             []*model.DataRequestTask
+	DownloadURL       string
+	DownloadExpiresAt int64
+}
+
+// DataRequestLogic 个人数据导出和账号注销。
+// 请求落库后异步处理：逐个调用各业务服务的 DataSubjectService，每个服务的结果记录为一个任务，
+// 失败的请求可以重试，已完成的任务不会重复执行；服务重启后继续处理未完成的请求。
+// 导出包按 {服务}/{数据类别}.json 打包上传到文件服务私有分类，只能由本人通过签名地址下载。
+// 注销时账号立即停用并退出所有登录，其他服务处理完成后最后匿名化本地用户记录。
+type DataRequestLogic struct {
+	repo           repository.DataRequestRepository
+	personalRepo   repository.PersonalDataRepository
+	userRepo       repository.UserRepository
+	credentialRepo repository.CredentialRepository
+	cache          *cache.CacheOperations
+	rdb            *redis.Client
+	ids            *idgen.Generator
+	participants   map[string]DataParticipant
+	archive        ArchiveStore // 为 nil 时不能导出
+	notifier       *Notifier
+	tokens         *TokenLogic
+	guard          *LoginGuardLogic
+	mfa            *MFALogic
+	policy         DataRequestPolicy
+}
+
+// NewDataRequestLogic 创建个人数据请求业务逻辑，participants 的 key 为服务名
+func NewDataRequestLogic(
+	repo repository.DataRequestRepository,
+	personalRepo repository.PersonalDataRepository,
+	userRepo repository.UserRepository,
+	credentialRepo repository.CredentialRepository,
+	cacheOps *cache.CacheOperations,
+	rdb *redis.Client,
+	ids *idgen.Generator,
+	participants map[string]DataParticipant,
+	archive ArchiveStore,
+	notifier *Notifier,
+	tokens *TokenLogic,
+	guard *LoginGuardLogic,
+	mfa *MFALogic,
+	policy DataRequestPolicy,
+) *DataRequestLogic {
+	if policy.Category == "" {
+		policy.Category = "privacy"
+	}
+	if policy.DownloadTTL <= 0 {
+		policy.DownloadTTL = 24 * time.Hour
+	}
+	return &DataRequestLogic{
+		repo:           repo,
+		personalRepo:   personalRepo,
+		userRepo:       userRepo,
+		credentialRepo: credentialRepo,
+		cache:          cacheOps,
+		rdb:            rdb,
+		ids:            ids,
+		participants:   participants,
+		archive:        archive,
+		notifier:       notifier,
+		tokens:         tokens,
+		guard:          guard,
+		mfa:            mfa,
+		policy:         policy,
+	}
+}
+
+// RequestExport 申请导出个人数据。同一用户同时只能有一个导出请求，完成后需间隔 ExportInterval 才能再次申请
+func (l *DataRequestLogic) RequestExport(ctx context.Context, userID uint64, ip string) (*DataRequestDetail, error) {
+	if l.archive == nil {
+		return nil, apperrors.NewInternalError("数据导出未配置文件存储")
+	}
+	user, err := l.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	last, err := l.repo.LatestByUser(ctx, userID, model.DataRequestTypeExport)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询导出请求失败: " + err.Error())
+	}
+	if last != nil {
+		if last.Active() {
+			return nil, apperrors.NewError(apperrors.CodeAlreadyExists, "已有正在处理的导出请求: "+last.RequestNo)
+		}
+		if last.Status == model.DataRequestStatusCompleted && time.Since(last.CreatedAt) < l.policy.ExportInterval {
+			return nil, apperrors.NewError(apperrors.CodeTooManyRequests,
+				fmt.Sprintf("导出过于频繁，请于 %s 后再试", last.CreatedAt.Add(l.policy.ExportInterval).Format("2006-01-02 15:04")))
+		}
+	}
+
+	detail, err := l.create(ctx, user.ID, user.ID, model.DataRequestTypeExport)
+	if err != nil {
+		return nil, err
+	}
+	l.guard.audit(ctx, &model.SecurityLog{
+		UserID:     user.ID,
+		Event:      model.SecurityEventDataExport,
+		OperatorID: user.ID,
+		ClientIP:   ip,
+		Detail:     detail.Request.RequestNo,
+	})
+	l.start(detail.Request)
+	return detail, nil
+}
+
+// RequestErasure 注销账号：校验密码和两步验证后立即停用账号、退出所有登录，再异步删除各服务中的个人数据。
+// 没有设置密码（第三方登录注册）的账号不校验密码
+func (l *DataRequestLogic) RequestErasure(ctx context.Context, userID uint64, password, mfaCode, ip string) (*DataRequestDetail, error) {
+	user, err := l.erasableUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := l.credentialRepo.GetByUserIDAndType(ctx, user.ID, model.CredentialTypePassword)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	if credential != nil && !utils.CheckPassword(password, credential.CredentialValue) {
+		return nil, apperrors.NewError(apperrors.CodePasswordError, "密码错误")
+	}
+	if err := l.mfa.CheckCode(ctx, user.ID, mfaCode); err != nil {
+		return nil, err
+	}
+
+	return l.startErasure(ctx, user.ID, user.ID, ip)
+}
+
+// RequestErasureByAdmin 管理员删除用户，按注销流程删除或匿名化各服务中的个人数据
+func (l *DataRequestLogic) RequestErasureByAdmin(ctx context.Context, userID, operatorID uint64, ip string) (*DataRequestDetail, error) {
+	if userID == 0 {
+		return nil, apperrors.NewInvalidParamError("用户ID不能为空")
+	}
+	if _, err := l.erasableUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return l.startErasure(ctx, userID, operatorID, ip)
+}
+
+// startErasure 创建注销请求，立即停用账号并退出所有登录后在后台处理
+func (l *DataRequestLogic) startErasure(ctx context.Context, userID, operatorID uint64, ip string) (*DataRequestDetail, error) {
+	detail, err := l.create(ctx, userID, operatorID, model.DataRequestTypeErase)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.userRepo.UpdateStatus(ctx, userID, constants.UserStatusDisabled); err != nil {
+		return nil, apperrors.NewInternalError("停用账号失败: " + err.Error())
+	}
+	if _, err := l.tokens.RevokeAllSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+	l.clearUserCache(ctx, userID)
+	l.guard.audit(ctx, &model.SecurityLog{
+		UserID:     userID,
+		Event:      model.SecurityEventErasureRequest,
+		OperatorID: operatorID,
+		ClientIP:   ip,
+		Detail:     detail.Request.RequestNo,
+	})
+	l.start(detail.Request)
+	return detail, nil
+}
+
+// Get 获取本人的请求详情，已完成的导出附带下载地址
+func (l *DataRequestLogic) Get(ctx context.Context, userID uint64, requestNo string) (*DataRequestDetail, error) {
+	req, err := l.repo.GetByRequestNo(ctx, requestNo)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询请求失败: " + err.Error())
+	}
+	if req == nil || req.UserID != userID {
+		return nil, apperrors.NewNotFoundError("请求不存在")
+	}
+	detail, err := l.detail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Type == model.DataRequestTypeExport && req.Status == model.DataRequestStatusCompleted &&
+		req.FileID != "" && l.archive != nil {
+		detail.DownloadURL, detail.DownloadExpiresAt, err = l.archive.GetFileURL(ctx, req.FileID,
+			int64(l.policy.DownloadTTL.Seconds()), userID)
+		if err != nil {
+			return nil, apperrors.NewError(apperrors.CodeExternalAPIError, "获取下载地址失败: "+err.Error())
+		}
+	}
+	return detail, nil
+}
+
+// List 分页获取请求，不含任务明细
+func (l *DataRequestLogic) List(ctx context.Context, filter repository.DataRequestFilter, page, pageSize int) ([]*model.DataRequest, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	reqs, total, err := l.repo.List(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("查询请求列表失败: " + err.Error())
+	}
+	return reqs, total, nil
+}
+
+// Retry 重新执行失败的请求（管理后台）。注销请求跳过已完成的任务，导出请求重新收集全部数据
+func (l *DataRequestLogic) Retry(ctx context.Context, requestNo string, operatorID uint64) (*DataRequestDetail, error) {
+	req, err := l.repo.GetByRequestNo(ctx, requestNo)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询请求失败: " + err.Error())
+	}
+	if req == nil {
+		return nil, apperrors.NewNotFoundError("请求不存在")
+	}
+	if req.Status != model.DataRequestStatusFailed {
+		return nil, apperrors.NewError(apperrors.CodeForbidden, "只能重试失败的请求")
+	}
+	req.Status = model.DataRequestStatusPending
+	req.Error = ""
+	req.OperatorID = operatorID
+	if err := l.repo.Update(ctx, req); err != nil {
+		return nil, apperrors.NewInternalError("更新请求失败: " + err.Error())
+	}
+	detail, err := l.detail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	l.start(req)
+	return detail, nil
+}
+
+// Resume 继续处理服务重启前未完成的请求，启动时在后台调用
+func (l *DataRequestLogic) Resume(ctx context.Context) {
+	reqs, err := l.repo.ListUnfinished(ctx, 100)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询未完成的个人数据请求失败: %v", err)
+		return
+	}
+	for _, req := range reqs {
+		l.process(ctx, req)
+	}
+}
+
+// create 创建请求，每个业务服务一个任务，用户服务本地任务排在最后
+func (l *DataRequestLogic) create(ctx context.Context, userID, operatorID uint64, reqType string) (*DataRequestDetail, error) {
+	now := time.Now()
+	req := &model.DataRequest{
+		RequestNo:  l.ids.DataRequestNo(ctx),
+		UserID:     userID,
+		Type:       reqType,
+		Status:     model.DataRequestStatusPending,
+		OperatorID: operatorID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	tasks := make([]*model.DataRequestTask, 0, len(l.participants)+1)
+	for _, name := range l.serviceNames() {
+		tasks = append(tasks, &model.DataRequestTask{Service: name, CreatedAt: now, UpdatedAt: now})
+	}
+	tasks = append(tasks, &model.DataRequestTask{Service: model.DataRequestLocalService, CreatedAt: now, UpdatedAt: now})
+	if err := l.repo.Create(ctx, req, tasks); err != nil {
+		return nil, apperrors.NewInternalError("创建请求失败: " + err.Error())
+	}
+	return &DataRequestDetail{Request: req, Tasks: tasks}, nil
+}
+
+// start 在后台处理请求，不受发起请求的 ctx 取消影响
+func (l *DataRequestLogic) start(req *model.DataRequest) {
+	go l.process(context.Background(), req)
+}
+
+// process 处理请求：同一请求同时只有一个处理者，各任务依次执行并更新进度
+func (l *DataRequestLogic) process(ctx context.Context, req *model.DataRequest) {
+	lockKey := cache.BuildKey(cache.KeyPrefixLock, "datarequest", req.RequestNo)
+	ok, err := l.rdb.SetNX(ctx, lockKey, 1, dataRequestLockTTL).Result()
+	if err != nil || !ok {
+		return
+	}
+	defer l.rdb.Del(context.Background(), lockKey)
+
+	ctx, cancel := context.WithTimeout(ctx, dataRequestLockTTL)
+	defer cancel()
+	log := logx.WithContext(ctx)
+
+	tasks, err := l.repo.ListTasks(ctx, req.ID)
+	if err != nil {
+		log.Errorf("查询个人数据请求任务失败: request_no=%s err=%v", req.RequestNo, err)
+		return
+	}
+	// 注销后本地用户信息被匿名化，先取出通知所需的联系方式
+	user, err := l.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		log.Errorf("查询用户失败: request_no=%s err=%v", req.RequestNo, err)
+		return
+	}
+
+	now := time.Now()
+	req.Status = model.DataRequestStatusProcessing
+	req.StartedAt = &now
+	req.FinishedAt = nil
+	l.save(ctx, req)
+
+	var failed []string
+	if req.Type == model.DataRequestTypeExport {
+		failed = l.runExport(ctx, req, tasks)
+	} else {
+		failed = l.runErasure(ctx, req, tasks)
+	}
+
+	finished := time.Now()
+	req.FinishedAt = &finished
+	if len(failed) > 0 {
+		req.Status = model.DataRequestStatusFailed
+		req.Error = truncate("处理失败: "+strings.Join(failed, "; "), 500)
+	} else {
+		req.Status = model.DataRequestStatusCompleted
+		req.Error = ""
+	}
+	l.save(ctx, req)
+	log.Infof("个人数据请求处理结束: request_no=%s type=%s status=%d", req.RequestNo, req.Type, req.Status)
+
+	if user != nil && req.Status == model.DataRequestStatusCompleted {
+		l.notifyCompleted(ctx, user, req)
+	}
+}
+
+// runExport 收集各服务导出的数据打包上传，返回失败原因
+func (l *DataRequestLogic) runExport(ctx context.Context, req *model.DataRequest, tasks []*model.DataRequestTask) []string {
+	exported := make(map[string][]*privacyv1.DataSection, len(tasks))
+	var failed []string
+	for _, task := range tasks {
+		sections, err := l.exportService(ctx, req, task.Service)
+		if err != nil {
+			failed = append(failed, task.Service+": "+err.Error())
+			l.finishTask(ctx, req, tasks, task, "", err)
+			continue
+		}
+		exported[task.Service] = sections
+		parts := make([]string, 0, len(sections))
+		for _, sec := range sections {
+			parts = append(parts, fmt.Sprintf("%s %d 条", sec.Name, sec.Count))
+		}
+		l.finishTask(ctx, req, tasks, task, strings.Join(parts, ", "), nil)
+	}
+	if len(failed) > 0 {
+		return failed
+	}
+
+	archive, err := buildArchive(req, tasks, exported)
+	if err != nil {
+		return []string{"打包失败: " + err.Error()}
+	}
+	fileID, err := l.archive.Upload(ctx, archive, fmt.Sprintf("personal-data-%s.zip", req.RequestNo),
+		"application/zip", l.policy.Category)
+	if err != nil {
+		return []string{"上传导出包失败: " + err.Error()}
+	}
+	req.FileID = fileID
+	return nil
+}
+
+// runErasure 依次删除各服务中的个人数据，全部成功后最后处理本地数据，返回失败原因
+func (l *DataRequestLogic) runErasure(ctx context.Context, req *model.DataRequest, tasks []*model.DataRequestTask) []string {
+	var failed []string
+	var local *model.DataRequestTask
+	for _, task := range tasks {
+		if task.Service == model.DataRequestLocalService {
+			local = task
+			continue
+		}
+		if task.Status == model.DataRequestStatusCompleted {
+			continue
+		}
+		p, ok := l.participants[task.Service]
+		if !ok {
+			err := fmt.Errorf("服务未配置")
+			failed = append(failed, task.Service+": "+err.Error())
+			l.finishTask(ctx, req, tasks, task, "", err)
+			continue
+		}
+		results, err := p.Erase(ctx, req.UserID, req.RequestNo)
+		if err != nil {
+			failed = append(failed, task.Service+": "+err.Error())
+			l.finishTask(ctx, req, tasks, task, "", err)
+			continue
+		}
+		l.finishTask(ctx, req, tasks, task, summarizeErasure(results), nil)
+	}
+	// 其他服务未全部完成时保留本地账号记录，重试时仍能按用户ID处理
+	if len(failed) > 0 || local == nil || local.Status == model.DataRequestStatusCompleted {
+		return failed
+	}
+
+	counts, err := l.personalRepo.Erase(ctx, req.UserID, datasubject.ErasedName)
+	if err != nil {
+		l.finishTask(ctx, req, tasks, local, "", err)
+		return []string{local.Service + ": " + err.Error()}
+	}
+	results := make([]*privacyv1.ErasureResult, 0, len(counts.Deleted)+len(counts.Anonymized))
+	for name, n := range counts.Deleted {
+		results = append(results, &privacyv1.ErasureResult{Name: name, Deleted: int32(n)})
+	}
+	for name, n := range counts.Anonymized {
+		results = append(results, &privacyv1.ErasureResult{Name: name, Anonymized: int32(n)})
+	}
+	l.finishTask(ctx, req, tasks, local, summarizeErasure(results), nil)
+	l.clearUserCache(ctx, req.UserID)
+	l.guard.audit(ctx, &model.SecurityLog{
+		UserID:     req.UserID,
+		Event:      model.SecurityEventAccountErased,
+		OperatorID: req.OperatorID,
+		Detail:     req.RequestNo,
+	})
+	return nil
+}
+
+// exportService 导出单个服务的数据，用户服务读取本地数据
+func (l *DataRequestLogic) exportService(ctx context.Context, req *model.DataRequest, service string) ([]*privacyv1.DataSection, error) {
+	if service == model.DataRequestLocalService {
+		data, err := l.personalRepo.Export(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return datasubject.EncodeSections(localSections(data))
+	}
+	p, ok := l.participants[service]
+	if !ok {
+		return nil, fmt.Errorf("服务未配置")
+	}
+	return p.Export(ctx, req.UserID, req.RequestNo)
+}
+
+// finishTask 记录任务结果并更新请求进度
+func (l *DataRequestLogic) finishTask(ctx context.Context, req *model.DataRequest, tasks []*model.DataRequestTask,
+	task *model.DataRequestTask, summary string, err error) {
+	now := time.Now()
+	task.UpdatedAt = now
+	if err != nil {
+		task.Status = model.DataRequestStatusFailed
+		task.Error = truncate(err.Error(), 500)
+		logx.WithContext(ctx).Errorf("个人数据请求任务失败: request_no=%s service=%s err=%v", req.RequestNo, task.Service, err)
+	} else {
+		task.Status = model.DataRequestStatusCompleted
+		task.Summary = truncate(summary, 1000)
+		task.Error = ""
+		task.FinishedAt = &now
+	}
+	if err := l.repo.UpdateTask(ctx, task); err != nil {
+		logx.WithContext(ctx).Errorf("更新个人数据请求任务失败: request_no=%s service=%s err=%v", req.RequestNo, task.Service, err)
+	}
+
+	done := 0
+	for _, t := range tasks {
+		if t.Status == model.DataRequestStatusCompleted {
+			done++
+		}
+	}
+	req.Progress = done * 100 / len(tasks)
+	l.save(ctx, req)
+}
+
+// notifyCompleted 通知用户处理完成
+func (l *DataRequestLogic) notifyCompleted(ctx context.Context, user *model.User, req *model.DataRequest) {
+	if req.Type == model.DataRequestTypeExport {
+		l.notifier.Notify(ctx, user, "个人数据导出已完成",
+			fmt.Sprintf("您于 %s 申请的个人数据导出（%s）已完成，请登录后在账号设置中下载，下载链接有效期 %d 小时。",
+				req.CreatedAt.Format("2006-01-02 15:04"), req.RequestNo, int(l.policy.DownloadTTL.Hours())), "")
+		return
+	}
+	l.notifier.Notify(ctx, user, "账号已注销",
+		fmt.Sprintf("您的账号已于 %s 完成注销（%s），个人数据已删除或匿名化，依法需保留的交易记录不再与您的身份关联。",
+			time.Now().Format("2006-01-02 15:04"), req.RequestNo), "")
+}
+
+// detail 请求和任务明细
+func (l *DataRequestLogic) detail(ctx context.Context, req *model.DataRequest) (*DataRequestDetail, error) {
+	tasks, err := l.repo.ListTasks(ctx, req.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询请求任务失败: " + err.Error())
+	}
+	return &DataRequestDetail{Request: req, Tasks: tasks}, nil
+}
+
+// erasableUser 获取可以注销的用户，已有处理中的注销请求时返回错误
+func (l *DataRequestLogic) erasableUser(ctx context.Context, userID uint64) (*model.User, error) {
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	last, err := l.repo.LatestByUser(ctx, userID, model.DataRequestTypeErase)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询注销请求失败: " + err.Error())
+	}
+	if last != nil && last.Active() {
+		return nil, apperrors.NewError(apperrors.CodeAlreadyExists, "注销申请正在处理中: "+last.RequestNo)
+	}
+	return user, nil
+}
+
+// activeUser 获取状态正常的用户
+func (l *DataRequestLogic) activeUser(ctx context.Context, userID uint64) (*model.User, error) {
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	if user.Status != constants.UserStatusNormal {
+		return nil, apperrors.NewError(apperrors.CodeUserDisabled, "用户已被禁用")
+	}
+	return user, nil
+}
+
+// save 保存请求，失败只记录日志，下次处理时会重新写入
+func (l *DataRequestLogic) save(ctx context.Context, req *model.DataRequest) {
+	req.UpdatedAt = time.Now()
+	if err := l.repo.Update(ctx, req); err != nil {
+		logx.WithContext(ctx).Errorf("更新个人数据请求失败: request_no=%s err=%v", req.RequestNo, err)
+	}
+}
+
+func (l *DataRequestLogic) clearUserCache(ctx context.Context, userID uint64) {
+	if l.cache != nil {
+		_ = l.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixUserInfo, userID))
+	}
+}
+
+// serviceNames 已配置的业务服务名（按名称排序，保证任务顺序稳定）
+func (l *DataRequestLogic) serviceNames() []string {
+	names := make([]string, 0, len(l.participants))
+	for name := range l.participants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// localSections 用户服务本地数据的导出内容，凭证只导出类型和账号标识
+func localSections(data *repository.PersonalData) []datasubject.Section {
+	type credentialView struct {
+		Type      int8      `json:"type"`
+		Key       string    `json:"key"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	creds := make([]credentialView, 0, len(data.Credentials))
+	for _, c := range data.Credentials {
+		creds = append(creds, credentialView{Type: c.CredentialType, Key: c.CredentialKey, CreatedAt: c.CreatedAt})
+	}
+	return []datasubject.Section{
+		{Name: "profile", Description: "账号资料", Records: data.User, Count: 1},
+		{Name: "addresses", Description: "收货地址（含已删除）", Records: data.Addresses, Count: len(data.Addresses)},
+		{Name: "credentials", Description: "登录方式（不含密码和密钥）", Records: creds, Count: len(creds)},
+		{Name: "sessions", Description: "登录设备（设备、IP、所在地和活跃时间）", Records: data.Sessions, Count: len(data.Sessions)},
+		{Name: "refresh_tokens", Description: "刷新令牌记录（不含令牌）", Records: data.RefreshTokens, Count: len(data.RefreshTokens)},
+		{Name: "api_keys", Description: "开放平台 API Key（不含 secret）", Records: data.ApiKeys, Count: len(data.ApiKeys)},
+		{Name: "member_spends", Description: "会员消费记录", Records: data.MemberSpends, Count: len(data.MemberSpends)},
+		{Name: "member_level_logs", Description: "会员等级变更记录", Records: data.MemberLevelLogs, Count: len(data.MemberLevelLogs)},
+		{Name: "security_logs", Description: "账号安全日志", Records: data.SecurityLogs, Count: len(data.SecurityLogs)},
+	}
+}
+
+// archiveManifest 导出包说明文件
+type archiveManifest struct {
+	RequestNo   string                   `json:"request_no"`
+	UserID      uint64                   `json:"user_id"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Services    map[string][]sectionInfo `json:"services"`
+}
+
+type sectionInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Count       int32  `json:"count"`
+	File        string `json:"file"`
+}
+
+// buildArchive 生成 zip 导出包：manifest.json 加 {服务}/{数据类别}.json
+func buildArchive(req *model.DataRequest, tasks []*model.DataRequestTask, exported map[string][]*privacyv1.DataSection) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	manifest := archiveManifest{
+		RequestNo:   req.RequestNo,
+		UserID:      req.UserID,
+		GeneratedAt: time.Now(),
+		Services:    make(map[string][]sectionInfo, len(exported)),
+	}
+	for _, task := range tasks {
+		infos := make([]sectionInfo, 0, len(exported[task.Service]))
+		for _, sec := range exported[task.Service] {
+			file := task.Service + "/" + sec.Name + ".json"
+			w, err := zw.Create(file)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := w.Write(sec.Data); err != nil {
+				return nil, err
+			}
+			infos = append(infos, sectionInfo{Name: sec.Name, Description: sec.Description, Count: sec.Count, File: file})
+		}
+		manifest.Services[task.Service] = infos
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	w, err := zw.Create("manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// summarizeErasure 删除结果摘要，如 orders: 匿名化 3; cart: 删除 5
+func summarizeErasure(results []*privacyv1.ErasureResult) string {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		var s []string
+		if r.Deleted > 0 {
+			s = append(s, fmt.Sprintf("删除 %d", r.Deleted))
+		}
+		if r.Anonymized > 0 {
+			s = append(s, fmt.Sprintf("匿名化 %d", r.Anonymized))
+		}
+		if len(s) == 0 {
+			s = append(s, "无数据")
+		}
+		part := r.Name + ": " + strings.Join(s, " ")
+		if r.Note != "" {
+			part += "（" + r.Note + "）"
+		}
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "; ")
+}
+
+// truncate 按字符截断，避免超过列长度
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
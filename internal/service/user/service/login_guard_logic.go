@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/captcha"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/loginguard"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// LoginGuardLogic 登录防暴力破解：失败计数、递增等待、临时锁定和人机验证，锁定/解锁写审计日志。
+// Redis 不可用时放行（只记录日志），不影响正常登录。
+type LoginGuardLogic struct {
+	guard    *loginguard.Guard
+	captcha  captcha.Verifier
+	userRepo repository.UserRepository
+	logRepo  repository.SecurityLogRepository
+}
+
+// NewLoginGuardLogic 创建登录防护业务逻辑
+func NewLoginGuardLogic(
+	guard *loginguard.Guard,
+	verifier captcha.Verifier,
+	userRepo repository.UserRepository,
+	logRepo repository.SecurityLogRepository,
+) *LoginGuardLogic {
+	return &LoginGuardLogic{
+		guard:    guard,
+		captcha:  verifier,
+		userRepo: userRepo,
+		logRepo:  logRepo,
+	}
+}
+
+// loginAccount 失败计数的账号维度：已注册用户按用户 ID（各种登录方式共用），
+// 未注册的输入按输入值计数，保证不存在的账号和存在的账号表现一致
+func loginAccount(user *model.User, username string) string {
+	if user != nil {
+		return fmt.Sprintf("uid:%d", user.ID)
+	}
+	return "name:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// Check 登录前检查锁定、等待和人机验证
+func (l *LoginGuardLogic) Check(ctx context.Context, account, ip, captchaToken string) error {
+	if l == nil {
+		return nil
+	}
+	st, err := l.checkLocked(ctx, account, ip)
+	if err != nil || st == nil || !st.CaptchaRequired {
+		return err
+	}
+
+	if captchaToken == "" {
+		return apperrors.NewError(apperrors.CodeCaptchaRequired, "请完成人机验证")
+	}
+	ok, err := l.captcha.Verify(ctx, captchaToken, ip)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("人机验证失败: %v", err)
+		return apperrors.NewError(apperrors.CodeCaptchaRequired, "人机验证失败，请重试")
+	}
+	if !ok {
+		return apperrors.NewError(apperrors.CodeCaptchaRequired, "人机验证未通过，请重试")
+	}
+	return nil
+}
+
+// CheckLocked 只检查锁定和递增等待，不要求人机验证（两步验证等登录的后续步骤使用）
+func (l *LoginGuardLogic) CheckLocked(ctx context.Context, account, ip string) error {
+	if l == nil {
+		return nil
+	}
+	_, err := l.checkLocked(ctx, account, ip)
+	return err
+}
+
+// checkLocked 查询状态，锁定或需要等待时返回错误；Redis 不可用时返回 nil 状态放行
+func (l *LoginGuardLogic) checkLocked(ctx context.Context, account, ip string) (*loginguard.Status, error) {
+	st, err := l.guard.Check(ctx, account, ip)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询登录失败次数失败: %v", err)
+		return nil, nil
+	}
+	if st.Locked {
+		return nil, apperrors.NewError(apperrors.CodeAccountLocked,
+			fmt.Sprintf("登录失败次数过多，请 %d 分钟后再试", int(math.Ceil(st.RetryAfter.Minutes()))))
+	}
+	if st.RetryAfter > 0 {
+		return nil, apperrors.NewError(apperrors.CodeTooManyRequests,
+			fmt.Sprintf("登录失败次数过多，请 %d 秒后再试", int(math.Ceil(st.RetryAfter.Seconds()))))
+	}
+	return st, nil
+}
+
+// Fail 记录一次登录失败，触发锁定时写审计日志
+func (l *LoginGuardLogic) Fail(ctx context.Context, user *model.User, account, ip string) {
+	if l == nil {
+		return
+	}
+	count, locked, err := l.guard.Fail(ctx, account, ip)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("记录登录失败次数失败: %v", err)
+		return
+	}
+	if !locked {
+		return
+	}
+	logx.WithContext(ctx).Infof("账号连续登录失败被锁定: account=%s ip=%s", account, ip)
+	if user != nil {
+		l.audit(ctx, &model.SecurityLog{
+			UserID:   user.ID,
+			Event:    model.SecurityEventAccountLocked,
+			ClientIP: ip,
+			Detail:   fmt.Sprintf("连续登录失败 %d 次", count),
+		})
+	}
+}
+
+// Succeed 登录成功，清零失败计数
+func (l *LoginGuardLogic) Succeed(ctx context.Context, account string) {
+	if l == nil {
+		return
+	}
+	if err := l.guard.Succeed(ctx, account); err != nil {
+		logx.WithContext(ctx).Errorf("清除登录失败次数失败: %v", err)
+	}
+}
+
+// Unlock 管理员解除账号锁定
+func (l *LoginGuardLogic) Unlock(ctx context.Context, userID, operatorID uint64, ip string) error {
+	if userID == 0 {
+		return apperrors.NewInvalidParamError("用户ID不能为空")
+	}
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	if err := l.guard.Unlock(ctx, loginAccount(user, "")); err != nil {
+		return apperrors.NewInternalError("解除锁定失败: " + err.Error())
+	}
+	l.audit(ctx, &model.SecurityLog{
+		UserID:     user.ID,
+		Event:      model.SecurityEventAccountUnlocked,
+		OperatorID: operatorID,
+		ClientIP:   ip,
+		Detail:     "管理员解除锁定",
+	})
+	return nil
+}
+
+// Clear 通过找回密码证明了账号归属，解除锁定并清零失败计数
+func (l *LoginGuardLogic) Clear(ctx context.Context, user *model.User) {
+	if l == nil {
+		return
+	}
+	if err := l.guard.Unlock(ctx, loginAccount(user, "")); err != nil {
+		logx.WithContext(ctx).Errorf("解除登录锁定失败: user_id=%d err=%v", user.ID, err)
+	}
+}
+
+// audit 写审计日志，失败只记录日志
+func (l *LoginGuardLogic) audit(ctx context.Context, log *model.SecurityLog) {
+	log.CreatedAt = time.Now()
+	if err := l.logRepo.Create(ctx, log); err != nil {
+		logx.WithContext(ctx).Errorf("写入账号安全日志失败: event=%s user_id=%d err=%v", log.Event, log.UserID, err)
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/mq"
+)
+
+// MemberConsumer 会员等级 Kafka 消费者
+// 监听订单完成、退款事件，记录会员消费并重新评定等级。
+type MemberConsumer struct {
+	logic *MemberLogic
+}
+
+// NewMemberConsumer 创建会员消费者
+func NewMemberConsumer(logic *MemberLogic) *MemberConsumer {
+	return &MemberConsumer{logic: logic}
+}
+
+// HandleOrderCompleted 处理订单完成事件 → 累计消费并评定等级
+func (c *MemberConsumer) HandleOrderCompleted(ctx context.Context, msg *mq.Message) error {
+	type payload struct {
+		OrderNo     string  `json:"order_no"`
+		UserID      uint64  `json:"user_id"`
+		PayAmount   float64 `json:"pay_amount"`
+		CompletedAt string  `json:"completed_at"`
+	}
+	var p payload
+	if err := decodePayload(msg.Data, &p); err != nil {
+		logx.Errorf("解析订单完成消息失败: %v", err)
+		return nil // 不返回 error，避免无限重试
+	}
+	if p.UserID == 0 || p.OrderNo == "" || p.PayAmount <= 0 {
+		return nil
+	}
+	// 数据库等临时故障返回 error，消息不确认，等待重新投递
+	return c.logic.OnOrderCompleted(ctx, p.UserID, p.OrderNo, p.PayAmount, parseEventTime(p.CompletedAt))
+}
+
+// HandlePaymentRefunded 处理退款成功事件 → 扣减消费并重新评定等级
+func (c *MemberConsumer) HandlePaymentRefunded(ctx context.Context, msg *mq.Message) error {
+	type payload struct {
+		OrderNo      string  `json:"order_no"`
+		RefundAmount float64 `json:"refund_amount"`
+		RefundedAt   string  `json:"refunded_at"`
+	}
+	var p payload
+	if err := decodePayload(msg.Data, &p); err != nil {
+		logx.Errorf("解析退款消息失败: %v", err)
+		return nil
+	}
+	if p.OrderNo == "" {
+		return nil
+	}
+	return c.logic.OnRefunded(ctx, p.OrderNo, p.RefundAmount, parseEventTime(p.RefundedAt))
+}
+
+// decodePayload 把 map[string]interface{} 转换为目标结构体（通过 JSON 中转）
+func decodePayload(data map[string]interface{}, dst interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// parseEventTime 解析事件中的 RFC3339 时间，缺失或格式错误时返回零值（由调用方取当前时间）
+func parseEventTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/cache"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/membership"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// memberEvaluateBatch 周期评估每批处理的用户数
+const memberEvaluateBatch = 200
+
+// MemberPolicy 会员等级策略
+type MemberPolicy struct {
+	Window time.Duration     // 统计窗口，默认 365 天
+	Tiers  []membership.Tier // 已 Normalize 的等级定义，为空时使用默认等级
+}
+
+// MemberBenefits 用户当前会员等级、统计数据和权益使用情况
+type MemberBenefits struct {
+	Tier             membership.Tier
+	GMV              float64 // 窗口内扣除退款后的实付金额
+	OrderCount       int     // 窗口内未全额退款的订单数
+	Next             *membership.Tier
+	FreeShippingUsed int // 本月已使用的包邮次数
+	FreeShippingLeft int // 本月剩余包邮次数
+}
+
+// MemberLogic 会员等级引擎。
+// 订单完成、退款事件写入消费记录后按滚动窗口重新评定等级；消费移出窗口后的降级由周期评估完成。
+// 等级变更记录历史并通知用户，促销、物流服务在结算时查询会员权益。
+type MemberLogic struct {
+	memberRepo repository.MemberRepository
+	userRepo   repository.UserRepository
+	cache      *cache.CacheOperations
+	rdb        *redis.Client
+	notifier   *Notifier
+	policy     MemberPolicy
+}
+
+// NewMemberLogic 创建会员业务逻辑
+func NewMemberLogic(
+	memberRepo repository.MemberRepository,
+	userRepo repository.UserRepository,
+	cacheOps *cache.CacheOperations,
+	rdb *redis.Client,
+	notifier *Notifier,
+	policy MemberPolicy,
+) *MemberLogic {
+	if policy.Window <= 0 {
+		policy.Window = 365 * 24 * time.Hour
+	}
+	if len(policy.Tiers) == 0 {
+		policy.Tiers = membership.DefaultTiers()
+	}
+	return &MemberLogic{
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+		cache:      cacheOps,
+		rdb:        rdb,
+		notifier:   notifier,
+		policy:     policy,
+	}
+}
+
+// Tiers 全部会员等级定义（按等级升序）
+func (l *MemberLogic) Tiers() []membership.Tier {
+	return l.policy.Tiers
+}
+
+// OnOrderCompleted 订单完成：记录消费并重新评定等级，同一订单重复消息只处理一次
+func (l *MemberLogic) OnOrderCompleted(ctx context.Context, userID uint64, orderNo string, amount float64, completedAt time.Time) error {
+	if completedAt.IsZero() {
+		completedAt = time.Now()
+	}
+	created, err := l.memberRepo.RecordSpend(ctx, &model.MemberSpend{
+		UserID:      userID,
+		OrderNo:     orderNo,
+		Amount:      amount,
+		CompletedAt: completedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("记录会员消费失败: %w", err)
+	}
+	if !created {
+		return nil
+	}
+	_, err = l.Recompute(ctx, userID, model.MemberChangeOrderCompleted)
+	return err
+}
+
+// OnRefunded 订单退款：扣减消费金额并重新评定等级。未完成的订单退款不影响等级，直接忽略
+func (l *MemberLogic) OnRefunded(ctx context.Context, orderNo string, refundAmount float64, refundedAt time.Time) error {
+	if refundedAt.IsZero() {
+		refundedAt = time.Now()
+	}
+	spend, err := l.memberRepo.MarkRefunded(ctx, orderNo, refundAmount, refundedAt)
+	if err != nil {
+		return fmt.Errorf("记录会员退款失败: %w", err)
+	}
+	if spend == nil {
+		return nil
+	}
+	_, err = l.Recompute(ctx, spend.UserID, model.MemberChangeRefunded)
+	return err
+}
+
+// Recompute 按窗口内消费重新评定用户等级，等级变化时记录历史并通知用户，返回是否变化
+func (l *MemberLogic) Recompute(ctx context.Context, userID uint64, reason string) (bool, error) {
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("查询用户失败: %w", err)
+	}
+	if user == nil {
+		return false, nil
+	}
+	gmv, orders, err := l.memberRepo.Stats(ctx, userID, time.Now().Add(-l.policy.Window))
+	if err != nil {
+		return false, fmt.Errorf("统计会员消费失败: %w", err)
+	}
+	tier := membership.Evaluate(l.policy.Tiers, gmv, orders)
+	if tier.Level == user.MemberLevel {
+		return false, nil
+	}
+
+	changed, err := l.memberRepo.ChangeLevel(ctx, &model.MemberLevelLog{
+		UserID:     userID,
+		FromLevel:  user.MemberLevel,
+		ToLevel:    tier.Level,
+		Reason:     reason,
+		GMV:        gmv,
+		OrderCount: orders,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("更新会员等级失败: %w", err)
+	}
+	if !changed {
+		// 并发评定已先一步更新，以那次的结果为准
+		return false, nil
+	}
+	if l.cache != nil {
+		_ = l.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixUserInfo, userID))
+	}
+	logx.WithContext(ctx).Infof("会员等级变更: user_id=%d %d -> %d reason=%s gmv=%.2f orders=%d",
+		userID, user.MemberLevel, tier.Level, reason, gmv, orders)
+
+	if tier.Level > user.MemberLevel {
+		l.notifier.Notify(ctx, user, "会员等级提升",
+			fmt.Sprintf("恭喜您升级为%s，可享受%s。", tier.Name, describeBenefits(tier)), "/member")
+	} else {
+		l.notifier.Notify(ctx, user, "会员等级调整",
+			fmt.Sprintf("近 %d 天累计消费 %.2f 元、%d 笔订单，您的会员等级调整为%s。", int(l.policy.Window.Hours()/24), gmv, orders, tier.Name),
+			"/member")
+	}
+	return true, nil
+}
+
+// EvaluateAll 重新评定全部非普通会员，返回等级变化的人数。单个用户失败只记录日志
+func (l *MemberLogic) EvaluateAll(ctx context.Context) (int, error) {
+	changed := 0
+	var afterID uint64
+	for {
+		users, err := l.memberRepo.ListLeveledUsers(ctx, afterID, memberEvaluateBatch)
+		if err != nil {
+			return changed, apperrors.NewInternalError("查询会员失败: " + err.Error())
+		}
+		for _, u := range users {
+			ok, err := l.Recompute(ctx, u.ID, model.MemberChangeExpired)
+			if err != nil {
+				logx.WithContext(ctx).Errorf("会员等级评估失败: user_id=%d err=%v", u.ID, err)
+				continue
+			}
+			if ok {
+				changed++
+			}
+		}
+		if len(users) < memberEvaluateBatch {
+			return changed, nil
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// RunEvaluator 按间隔执行周期评估直到 ctx 取消；多实例部署时用 Redis 锁保证每个周期只有一个实例执行
+func (l *MemberLogic) RunEvaluator(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lockKey := cache.BuildKey(cache.KeyPrefixLock, "member", "evaluate")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ok, err := l.rdb.SetNX(ctx, lockKey, 1, interval/2).Result()
+		if err != nil || !ok {
+			continue
+		}
+		changed, err := l.EvaluateAll(ctx)
+		if err != nil {
+			logx.Errorf("会员等级周期评估失败: %v", err)
+			continue
+		}
+		logx.Infof("会员等级周期评估完成: changed=%d", changed)
+	}
+}
+
+// Benefits 查询用户会员等级和权益
+func (l *MemberLogic) Benefits(ctx context.Context, userID uint64) (*MemberBenefits, error) {
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	gmv, orders, err := l.memberRepo.Stats(ctx, userID, time.Now().Add(-l.policy.Window))
+	if err != nil {
+		return nil, apperrors.NewInternalError("统计会员消费失败: " + err.Error())
+	}
+	used, err := l.rdb.SCard(ctx, freeShippingKey(userID, time.Now())).Result()
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询包邮次数失败: " + err.Error())
+	}
+
+	tier := membership.Find(l.policy.Tiers, user.MemberLevel)
+	result := &MemberBenefits{
+		Tier:             tier,
+		GMV:              gmv,
+		OrderCount:       orders,
+		FreeShippingUsed: int(used),
+		FreeShippingLeft: max(tier.FreeShippingQuota-int(used), 0),
+	}
+	if next, ok := membership.Next(l.policy.Tiers, tier.Level); ok {
+		result.Next = &next
+	}
+	return result, nil
+}
+
+// UseFreeShipping 为订单占用一次本月包邮次数，返回是否包邮和剩余次数。
+// 同一订单重复调用只占用一次；先占用再检查，超出额度时撤回，并发时不会超发。
+func (l *MemberLogic) UseFreeShipping(ctx context.Context, userID uint64, orderNo string) (bool, int, error) {
+	if orderNo == "" {
+		return false, 0, apperrors.NewInvalidParamError("订单号不能为空")
+	}
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, 0, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return false, 0, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	quota := membership.Find(l.policy.Tiers, user.MemberLevel).FreeShippingQuota
+	if quota <= 0 {
+		return false, 0, nil
+	}
+
+	now := time.Now()
+	key := freeShippingKey(userID, now)
+	added, err := l.rdb.SAdd(ctx, key, orderNo).Result()
+	if err != nil {
+		return false, 0, apperrors.NewInternalError("占用包邮次数失败: " + err.Error())
+	}
+	// 保留到下月初之后，跨月的订单不会误删本月记录
+	l.rdb.ExpireAt(ctx, key, time.Date(now.Year(), now.Month()+1, 2, 0, 0, 0, 0, now.Location()))
+	used, err := l.rdb.SCard(ctx, key).Result()
+	if err != nil {
+		return false, 0, apperrors.NewInternalError("查询包邮次数失败: " + err.Error())
+	}
+	if int(used) > quota {
+		if added > 0 {
+			l.rdb.SRem(ctx, key, orderNo)
+		}
+		return false, 0, nil
+	}
+	return true, quota - int(used), nil
+}
+
+// ListLevelLogs 分页获取用户的等级变更历史
+func (l *MemberLogic) ListLevelLogs(ctx context.Context, userID uint64, page, pageSize int) ([]*model.MemberLevelLog, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	logs, total, err := l.memberRepo.ListLevelLogs(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("查询等级变更记录失败: " + err.Error())
+	}
+	return logs, total, nil
+}
+
+// describeBenefits 等级权益描述，用于升级通知
+func describeBenefits(t membership.Tier) string {
+	desc := fmt.Sprintf("%.1f 倍积分", t.PointsMultiplier)
+	if t.DiscountRate < 1 {
+		desc = fmt.Sprintf("%g 折优惠、", t.DiscountRate*10) + desc
+	}
+	if t.FreeShippingQuota > 0 {
+		desc += fmt.Sprintf("、每月 %d 次包邮", t.FreeShippingQuota)
+	}
+	return desc
+}
+
+// freeShippingKey 用户当月已包邮的订单号集合
+func freeShippingKey(userID uint64, t time.Time) string {
+	return cache.BuildKey(cache.KeyPrefixMemberFreeShip, userID, t.Format("200601"))
+}
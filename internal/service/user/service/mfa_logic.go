@@ -0,0 +1,538 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/constants"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/totp"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// recoveryCodeCount 每次生成的恢复码数量
+const recoveryCodeCount = 10
+
+// MFAPolicy 两步验证策略
+type MFAPolicy struct {
+	Issuer        string        // 验证器应用中显示的名称
+	RequiredRoles []string      // 拥有这些角色的用户必须开启两步验证
+	ChallengeTTL  time.Duration // 登录第二步的有效期
+	MaxAttempts   int           // 同一个登录挑战最多尝试次数
+}
+
+// MFALogic TOTP 两步验证。
+// 密码（或验证码、第三方）校验通过后，已开启两步验证或角色要求开启的用户不直接签发令牌，
+// 而是返回短期有效的挑战 token，由 VerifyMFA 校验 TOTP 验证码或恢复码后完成登录；
+// 角色要求开启但尚未绑定的用户先用挑战 token 绑定验证器，首次验证同时完成绑定。
+type MFALogic struct {
+	credentialRepo repository.CredentialRepository
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	cipher         *apikey.Cipher // 为 nil 时不能开启两步验证
+	rdb            *redis.Client
+	tokens         *TokenLogic
+	guard          *LoginGuardLogic
+	policy         MFAPolicy
+	requiredRoles  map[string]bool
+}
+
+// NewMFALogic 创建两步验证业务逻辑
+func NewMFALogic(
+	credentialRepo repository.CredentialRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	cipher *apikey.Cipher,
+	rdb *redis.Client,
+	tokens *TokenLogic,
+	guard *LoginGuardLogic,
+	policy MFAPolicy,
+) *MFALogic {
+	if policy.Issuer == "" {
+		policy.Issuer = "Go Ecom"
+	}
+	if policy.ChallengeTTL <= 0 {
+		policy.ChallengeTTL = 5 * time.Minute
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 5
+	}
+	required := make(map[string]bool, len(policy.RequiredRoles))
+	for _, r := range policy.RequiredRoles {
+		required[r] = true
+	}
+	return &MFALogic{
+		credentialRepo: credentialRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		cipher:         cipher,
+		rdb:            rdb,
+		tokens:         tokens,
+		guard:          guard,
+		policy:         policy,
+		requiredRoles:  required,
+	}
+}
+
+// MFAChallenge 登录第二步的挑战
+type MFAChallenge struct {
+	Token          string
+	EnrollRequired bool // 角色要求开启但尚未绑定，需先调用 SetupTOTP
+	ExpireAt       time.Time
+}
+
+// TOTPSetup 绑定验证器所需信息
+type TOTPSetup struct {
+	Secret          string // 无法扫码时手动输入
+	ProvisioningURI string // otpauth:// 地址，前端渲染成二维码
+}
+
+// MFAStatus 两步验证状态
+type MFAStatus struct {
+	Enabled           bool
+	Required          bool // 当前角色要求开启
+	RecoveryCodesLeft int
+}
+
+// MFAVerifyResult 登录第二步结果
+type MFAVerifyResult struct {
+	User          *model.User
+	Tokens        *TokenPair
+	RecoveryCodes []string // 本次完成绑定时生成的恢复码，只返回这一次
+}
+
+// Challenge 第一步校验通过后调用：需要两步验证时返回挑战，否则返回 nil
+func (l *MFALogic) Challenge(ctx context.Context, user *model.User) (*MFAChallenge, error) {
+	if l == nil {
+		return nil, nil
+	}
+	_, extra, err := l.credential(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := extra != nil && extra.Confirmed
+	required, err := l.required(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled && !required {
+		return nil, nil
+	}
+	if !enabled && l.cipher == nil {
+		return nil, apperrors.NewInternalError("两步验证未配置加密密钥")
+	}
+
+	token := randomToken()
+	key := challengeKey(token)
+	pipe := l.rdb.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", user.ID, "attempts", 0)
+	pipe.Expire(ctx, key, l.policy.ChallengeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, apperrors.NewInternalError("保存两步验证状态失败: " + err.Error())
+	}
+	return &MFAChallenge{
+		Token:          token,
+		EnrollRequired: !enabled,
+		ExpireAt:       time.Now().Add(l.policy.ChallengeTTL),
+	}, nil
+}
+
+// Setup 生成新的 TOTP 密钥（未确认前可重复调用，每次生成新密钥）
+func (l *MFALogic) Setup(ctx context.Context, userID uint64) (*TOTPSetup, error) {
+	if l.cipher == nil {
+		return nil, apperrors.NewError(apperrors.CodeForbidden, "两步验证未开放")
+	}
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	cred, extra, err := l.credential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if extra != nil && extra.Confirmed {
+		return nil, apperrors.NewError(apperrors.CodeAlreadyExists, "已开启两步验证")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apperrors.NewInternalError("生成密钥失败: " + err.Error())
+	}
+	encrypted, err := l.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, apperrors.NewInternalError("加密密钥失败: " + err.Error())
+	}
+	now := time.Now()
+	if cred == nil {
+		cred = &model.Credential{
+			UserID:         userID,
+			CredentialType: model.CredentialTypeTOTP,
+			CredentialKey:  "totp",
+			CreatedAt:      now,
+		}
+	}
+	cred.CredentialValue = encrypted
+	if err := l.save(ctx, cred, &model.TOTPExtra{}); err != nil {
+		return nil, err
+	}
+
+	return &TOTPSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(l.policy.Issuer, user.Username, secret),
+	}, nil
+}
+
+// SetupWithChallenge 登录过程中（角色要求开启但尚未绑定）用挑战 token 生成密钥
+func (l *MFALogic) SetupWithChallenge(ctx context.Context, token string) (*TOTPSetup, error) {
+	userID, err := l.challengeUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return l.Setup(ctx, userID)
+}
+
+// Confirm 已登录用户用验证码确认绑定，返回恢复码
+func (l *MFALogic) Confirm(ctx context.Context, userID uint64, code string) ([]string, error) {
+	cred, extra, err := l.credential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		return nil, apperrors.NewInvalidParamError("请先获取两步验证密钥")
+	}
+	if extra.Confirmed {
+		return nil, apperrors.NewError(apperrors.CodeAlreadyExists, "已开启两步验证")
+	}
+	return l.confirm(ctx, cred, extra, code)
+}
+
+// Verify 登录第二步：校验 TOTP 验证码或恢复码，通过后签发令牌。
+// 失败计入登录防护的失败次数，挑战超过最大尝试次数后作废，需要重新登录。
+func (l *MFALogic) Verify(ctx context.Context, token, code, ip string) (*MFAVerifyResult, error) {
+	if token == "" || code == "" {
+		return nil, apperrors.NewInvalidParamError("验证码不能为空")
+	}
+	userID, err := l.challengeUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := attemptScript.Run(ctx, l.rdb, []string{challengeKey(token)}).Int64()
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询两步验证状态失败: " + err.Error())
+	}
+	if attempts < 0 {
+		return nil, apperrors.NewError(apperrors.CodeUnauthorized, "两步验证已过期，请重新登录")
+	}
+	if attempts > int64(l.policy.MaxAttempts) {
+		_ = l.rdb.Del(ctx, challengeKey(token)).Err()
+		return nil, apperrors.NewError(apperrors.CodeUnauthorized, "两步验证失败次数过多，请重新登录")
+	}
+
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, apperrors.NewError(apperrors.CodeUnauthorized, "两步验证已过期，请重新登录")
+	}
+	account := loginAccount(user, "")
+	if err := l.guard.CheckLocked(ctx, account, ip); err != nil {
+		return nil, err
+	}
+
+	cred, extra, err := l.credential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		return nil, apperrors.NewInvalidParamError("请先设置两步验证")
+	}
+
+	result := &MFAVerifyResult{User: user}
+	if !extra.Confirmed {
+		// 首次验证同时完成绑定
+		result.RecoveryCodes, err = l.confirm(ctx, cred, extra, code)
+	} else {
+		err = l.check(ctx, cred, extra, code)
+	}
+	if err != nil {
+		var bizErr *apperrors.BusinessError
+		if errors.As(err, &bizErr) && bizErr.Code == apperrors.CodeMFACodeError {
+			l.guard.Fail(ctx, user, account, ip)
+		}
+		return nil, err
+	}
+
+	// 签发令牌前消费挑战：同一个挑战并发提交不同的验证码（如验证码和恢复码）时只有一个请求能完成登录
+	consumed, err := l.rdb.Del(ctx, challengeKey(token)).Result()
+	if err != nil {
+		return nil, apperrors.NewInternalError("保存两步验证状态失败: " + err.Error())
+	}
+	if consumed == 0 {
+		return nil, apperrors.NewError(apperrors.CodeUnauthorized, "两步验证已过期，请重新登录")
+	}
+	l.guard.Succeed(ctx, account)
+	if user.Status != constants.UserStatusNormal {
+		return nil, apperrors.NewError(apperrors.CodeForbidden, "用户已被禁用")
+	}
+	if result.Tokens, err = l.tokens.IssueTokens(ctx, user, ""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Disable 关闭两步验证，角色要求开启时不允许关闭
+func (l *MFALogic) Disable(ctx context.Context, userID uint64, code string) error {
+	required, err := l.required(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return apperrors.NewError(apperrors.CodeForbidden, "当前角色要求开启两步验证，不能关闭")
+	}
+	cred, extra, err := l.credential(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if cred == nil {
+		return apperrors.NewError(apperrors.CodeNotFound, "未开启两步验证")
+	}
+	if extra.Confirmed {
+		if err := l.check(ctx, cred, extra, code); err != nil {
+			return err
+		}
+	}
+	if err := l.credentialRepo.Delete(ctx, cred.ID); err != nil {
+		return apperrors.NewInternalError("关闭两步验证失败: " + err.Error())
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，旧恢复码作废
+func (l *MFALogic) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error) {
+	cred, extra, err := l.credential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil || !extra.Confirmed {
+		return nil, apperrors.NewError(apperrors.CodeNotFound, "未开启两步验证")
+	}
+	if err := l.check(ctx, cred, extra, code); err != nil {
+		return nil, err
+	}
+	codes, err := l.newRecoveryCodes(extra)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.save(ctx, cred, extra); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CheckCode 敏感操作的二次确认：已开启两步验证时校验验证码或恢复码，未开启时直接通过
+func (l *MFALogic) CheckCode(ctx context.Context, userID uint64, code string) error {
+	cred, extra, err := l.credential(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if cred == nil || !extra.Confirmed {
+		return nil
+	}
+	if code == "" {
+		return apperrors.NewError(apperrors.CodeMFACodeError, "请输入两步验证码")
+	}
+	return l.check(ctx, cred, extra, code)
+}
+
+// Status 两步验证状态
+func (l *MFALogic) Status(ctx context.Context, userID uint64) (*MFAStatus, error) {
+	_, extra, err := l.credential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := l.required(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	st := &MFAStatus{Required: required}
+	if extra != nil && extra.Confirmed {
+		st.Enabled = true
+		st.RecoveryCodesLeft = len(extra.RecoveryCodes)
+	}
+	return st, nil
+}
+
+// confirm 校验验证码并标记绑定完成，生成恢复码
+func (l *MFALogic) confirm(ctx context.Context, cred *model.Credential, extra *model.TOTPExtra, code string) ([]string, error) {
+	step, err := l.validateTOTP(cred, code)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	extra.Confirmed = true
+	extra.ConfirmedAt = &now
+	extra.LastStep = step
+	codes, err := l.newRecoveryCodes(extra)
+	if err != nil {
+		return nil, err
+	}
+	// 并发确认时只保留一组恢复码，避免返回给用户的恢复码被另一个请求覆盖
+	if err := l.consume(ctx, cred, extra); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// check 校验 TOTP 验证码（拒绝重放）或恢复码（使用后作废）
+func (l *MFALogic) check(ctx context.Context, cred *model.Credential, extra *model.TOTPExtra, code string) error {
+	if len(code) == totp.Digits {
+		step, err := l.validateTOTP(cred, code)
+		if err != nil {
+			return err
+		}
+		if step <= extra.LastStep {
+			return apperrors.NewError(apperrors.CodeMFACodeError, "验证码已使用，请等待下一个验证码")
+		}
+		extra.LastStep = step
+		return l.consume(ctx, cred, extra)
+	}
+
+	hash := totp.HashRecoveryCode(code)
+	for i, h := range extra.RecoveryCodes {
+		if h == hash {
+			extra.RecoveryCodes = append(extra.RecoveryCodes[:i], extra.RecoveryCodes[i+1:]...)
+			return l.consume(ctx, cred, extra)
+		}
+	}
+	return apperrors.NewError(apperrors.CodeMFACodeError, "两步验证码错误")
+}
+
+func (l *MFALogic) validateTOTP(cred *model.Credential, code string) (int64, error) {
+	if l.cipher == nil {
+		return 0, apperrors.NewInternalError("两步验证未配置加密密钥")
+	}
+	secret, err := l.cipher.Decrypt(cred.CredentialValue)
+	if err != nil {
+		return 0, apperrors.NewInternalError("解密两步验证密钥失败: " + err.Error())
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), 1)
+	if !ok {
+		return 0, apperrors.NewError(apperrors.CodeMFACodeError, "两步验证码错误")
+	}
+	return step, nil
+}
+
+func (l *MFALogic) newRecoveryCodes(extra *model.TOTPExtra) ([]string, error) {
+	codes, err := totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, apperrors.NewInternalError("生成恢复码失败: " + err.Error())
+	}
+	extra.RecoveryCodes = make([]string, len(codes))
+	for i, c := range codes {
+		extra.RecoveryCodes[i] = totp.HashRecoveryCode(c)
+	}
+	return codes, nil
+}
+
+// credential 用户的 TOTP 凭证；没有时都返回 nil
+func (l *MFALogic) credential(ctx context.Context, userID uint64) (*model.Credential, *model.TOTPExtra, error) {
+	cred, err := l.credentialRepo.GetByUserIDAndType(ctx, userID, model.CredentialTypeTOTP)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	if cred == nil {
+		return nil, nil, nil
+	}
+	extra := &model.TOTPExtra{}
+	_ = json.Unmarshal([]byte(cred.Extra), extra)
+	return cred, extra, nil
+}
+
+// save 保存凭证（新建或更新）
+func (l *MFALogic) save(ctx context.Context, cred *model.Credential, extra *model.TOTPExtra) error {
+	data, _ := json.Marshal(extra)
+	cred.Extra = string(data)
+	cred.UpdatedAt = time.Now()
+	var err error
+	if cred.ID == 0 {
+		err = l.credentialRepo.Create(ctx, cred)
+	} else {
+		err = l.credentialRepo.Update(ctx, cred)
+	}
+	if err != nil {
+		return apperrors.NewInternalError("保存两步验证凭证失败: " + err.Error())
+	}
+	return nil
+}
+
+// consume 只有凭证仍是读取时的内容才写入新的 LastStep 或恢复码列表，
+// 并发提交同一个验证码或恢复码时只有一个请求通过，其余按已使用处理
+func (l *MFALogic) consume(ctx context.Context, cred *model.Credential, extra *model.TOTPExtra) error {
+	data, _ := json.Marshal(extra)
+	ok, err := l.credentialRepo.SwapExtra(ctx, cred.ID, cred.Extra, string(data))
+	if err != nil {
+		return apperrors.NewInternalError("保存两步验证凭证失败: " + err.Error())
+	}
+	if !ok {
+		return apperrors.NewError(apperrors.CodeMFACodeError, "验证码已使用，请等待下一个验证码")
+	}
+	cred.Extra = string(data)
+	return nil
+}
+
+// required 用户的角色是否要求开启两步验证
+func (l *MFALogic) required(ctx context.Context, userID uint64) (bool, error) {
+	if len(l.requiredRoles) == 0 {
+		return false, nil
+	}
+	roles, err := l.roleRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return false, apperrors.NewInternalError("查询用户角色失败: " + err.Error())
+	}
+	for _, r := range roles {
+		if l.requiredRoles[r.Code] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// challengeUser 挑战对应的用户，不消耗挑战
+func (l *MFALogic) challengeUser(ctx context.Context, token string) (uint64, error) {
+	if token == "" {
+		return 0, apperrors.NewInvalidParamError("mfa_token 不能为空")
+	}
+	val, err := l.rdb.HGet(ctx, challengeKey(token), "user_id").Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, apperrors.NewError(apperrors.CodeUnauthorized, "两步验证已过期，请重新登录")
+	}
+	if err != nil {
+		return 0, apperrors.NewInternalError("查询两步验证状态失败: " + err.Error())
+	}
+	userID, _ := strconv.ParseUint(val, 10, 64)
+	return userID, nil
+}
+
+// attemptScript 挑战存在时递增尝试次数并返回，已过期或已被消费时返回 -1（不会重新创建没有过期时间的挑战）
+var attemptScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return -1
+end
+return redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+`)
+
+func challengeKey(token string) string {
+	return cache.BuildKey(cache.KeyPrefixMFAChallenge, token)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"ecommerce-system/internal/pkg/apikey"
+	"ecommerce-system/internal/pkg/constants"
+	"ecommerce-system/internal/pkg/totp"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// memCredentialRepo 内存版凭证仓储，可以并发访问
+type memCredentialRepo struct {
+	repository.CredentialRepository
+	mu    sync.Mutex
+	creds []*model.Credential
+}
+
+func (m *memCredentialRepo) GetByUserIDAndType(ctx context.Context, userID uint64, credentialType int8) (*model.Credential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.creds {
+		if c.UserID == userID && c.CredentialType == credentialType {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memCredentialRepo) Create(ctx context.Context, credential *model.Credential) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	credential.ID = uint64(len(m.creds) + 1)
+	copied := *credential
+	m.creds = append(m.creds, &copied)
+	return nil
+}
+
+func (m *memCredentialRepo) Update(ctx context.Context, credential *model.Credential) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, c := range m.creds {
+		if c.ID == credential.ID {
+			copied := *credential
+			m.creds[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (m *memCredentialRepo) SwapExtra(ctx context.Context, id uint64, oldExtra, newExtra string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.creds {
+		if c.ID == id && c.Extra == oldExtra {
+			c.Extra = newExtra
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newTestMFALogic 用户 7 已开启两步验证，返回 TOTP 密钥和恢复码
+func newTestMFALogic(t *testing.T) (*MFALogic, *memCredentialRepo, *model.User, string, []string) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	cipher, err := apikey.NewCipher("mfa-test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := cipher.Encrypt(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recoveryCodes, err := totp.GenerateRecoveryCodes(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra := model.TOTPExtra{Confirmed: true}
+	for _, c := range recoveryCodes {
+		extra.RecoveryCodes = append(extra.RecoveryCodes, totp.HashRecoveryCode(c))
+	}
+	data, _ := json.Marshal(extra)
+
+	user := &model.User{ID: 7, Username: "alice", Status: constants.UserStatusNormal}
+	users := &mockUserRepo{users: map[uint64]*model.User{user.ID: user}}
+	creds := &memCredentialRepo{creds: []*model.Credential{{
+		ID: 1, UserID: user.ID, CredentialType: model.CredentialTypeTOTP, CredentialValue: encrypted, Extra: string(data),
+	}}}
+	tokens := NewTokenLogic(users, &memTokenRepo{}, nil, nil, nil, "test-secret", 900, 3600)
+	logic := NewMFALogic(creds, users, nil, cipher, rdb, tokens, nil, MFAPolicy{MaxAttempts: 10})
+	return logic, creds, user, secret, recoveryCodes
+}
+
+// verifyConcurrently 并发提交 codes[i] 到 tokens[i]，返回成功的次数
+func verifyConcurrently(logic *MFALogic, tokens, codes []string) int {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		success int
+	)
+	for i := range codes {
+		wg.Add(1)
+		go func(token, code string) {
+			defer wg.Done()
+			if result, err := logic.Verify(context.Background(), token, code, "127.0.0.1"); err == nil && result.Tokens != nil {
+				mu.Lock()
+				success++
+				mu.Unlock()
+			}
+		}(tokens[i], codes[i])
+	}
+	wg.Wait()
+	return success
+}
+
+func TestMFAVerifyConcurrentSameCode(t *testing.T) {
+	logic, _, user, secret, recoveryCodes := newTestMFALogic(t)
+	ctx := context.Background()
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一个 TOTP 验证码和同一个恢复码分别在多个登录挑战上并发提交，各自只能用一次
+	for _, c := range []string{code, recoveryCodes[0]} {
+		tokens := make([]string, 8)
+		codes := make([]string, 8)
+		for i := range tokens {
+			challenge, err := logic.Challenge(ctx, user)
+			if err != nil || challenge == nil {
+				t.Fatalf("Challenge: %v %v", challenge, err)
+			}
+			tokens[i], codes[i] = challenge.Token, c
+		}
+		if success := verifyConcurrently(logic, tokens, codes); success != 1 {
+			t.Fatalf("code %q accepted %d times, want once", c, success)
+		}
+	}
+
+	_, extra, err := logic.credential(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extra.LastStep == 0 || len(extra.RecoveryCodes) != len(recoveryCodes)-1 {
+		t.Fatalf("credential after use: last_step=%d recovery codes left=%d", extra.LastStep, len(extra.RecoveryCodes))
+	}
+}
+
+func TestMFAVerifyConsumesChallengeOnce(t *testing.T) {
+	logic, _, user, _, recoveryCodes := newTestMFALogic(t)
+	ctx := context.Background()
+	challenge, err := logic.Challenge(ctx, user)
+	if err != nil {
+		t.Fatalf("Challenge: %v", err)
+	}
+
+	// 同一个挑战并发提交不同的有效恢复码，只能完成一次登录
+	tokens := make([]string, len(recoveryCodes))
+	for i := range tokens {
+		tokens[i] = challenge.Token
+	}
+	if success := verifyConcurrently(logic, tokens, recoveryCodes); success != 1 {
+		t.Fatalf("challenge completed %d times, want once", success)
+	}
+	if _, err := logic.Verify(ctx, challenge.Token, recoveryCodes[0], "127.0.0.1"); err == nil {
+		t.Fatal("consumed challenge accepted again")
+	}
+	// 已过期的挑战不会因为计数被重新创建
+	if n, _ := logic.rdb.Exists(ctx, challengeKey(challenge.Token)).Result(); n != 0 {
+		t.Fatal("consumed challenge was recreated")
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/client"
+	"ecommerce-system/internal/pkg/sender"
+	"ecommerce-system/internal/service/user/model"
+)
+
+// Inbox 站内消息发送，由消息服务客户端实现
+type Inbox interface {
+	SendMessage(ctx context.Context, userID uint64, msgType int32, title, content, link string) error
+}
+
+// Notifier 账号通知：优先发邮件，没有邮箱时发短信，同时发站内信。发送失败只记录日志
+type Notifier struct {
+	sender sender.Sender
+	inbox  Inbox // 为 nil 时不发站内信
+}
+
+// NewNotifier 创建账号通知
+func NewNotifier(s sender.Sender, inbox Inbox) *Notifier {
+	return &Notifier{
+		sender: s,
+		inbox:  inbox,
+	}
+}
+
+// Notify 发送通知，link 为站内信跳转地址，可为空
+func (n *Notifier) Notify(ctx context.Context, user *model.User, title, content, link string) {
+	var msg *sender.Message
+	switch {
+	case user.Email != "":
+		msg = &sender.Message{Channel: sender.ChannelEmail, To: user.Email, Subject: title, Content: content}
+	case user.Phone != "":
+		msg = &sender.Message{Channel: sender.ChannelSMS, To: user.Phone, Content: content}
+	}
+	if msg != nil {
+		if err := n.sender.Send(ctx, msg); err != nil {
+			logx.WithContext(ctx).Errorf("发送通知失败: user_id=%d title=%s err=%v", user.ID, title, err)
+		}
+	}
+	if n.inbox != nil {
+		if err := n.inbox.SendMessage(ctx, user.ID, client.MessageTypeSystem, title, content, link); err != nil {
+			logx.WithContext(ctx).Errorf("发送站内信失败: user_id=%d title=%s err=%v", user.ID, title, err)
+		}
+	}
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+	"golang.org/x/oauth2"
+
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/constants"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/oidc"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// OAuthLogic 第三方（OIDC）登录、绑定和解绑。
+// 第三方身份保存为 credential_type=5 的凭证，credential_key 为 "{提供方}:{sub}"，extra 保存第三方资料。
+// 授权 state 存 Redis，一次性使用，同时保存 nonce 和 PKCE verifier。
+type OAuthLogic struct {
+	providers      map[string]*oidc.Provider
+	rdb            *redis.Client
+	stateTTL       time.Duration
+	userRepo       repository.UserRepository
+	credentialRepo repository.CredentialRepository
+	tokens         *TokenLogic
+	mfa            *MFALogic
+}
+
+// NewOAuthLogic 创建第三方登录业务逻辑；stateTTL 为授权 state 有效期（秒）
+func NewOAuthLogic(
+	providers map[string]*oidc.Provider,
+	rdb *redis.Client,
+	stateTTL int64,
+	userRepo repository.UserRepository,
+	credentialRepo repository.CredentialRepository,
+	tokens *TokenLogic,
+	mfa *MFALogic,
+) *OAuthLogic {
+	if stateTTL <= 0 {
+		stateTTL = 600
+	}
+	return &OAuthLogic{
+		providers:      providers,
+		rdb:            rdb,
+		stateTTL:       time.Duration(stateTTL) * time.Second,
+		userRepo:       userRepo,
+		credentialRepo: credentialRepo,
+		tokens:         tokens,
+		mfa:            mfa,
+	}
+}
+
+// OAuthProvider 可用的第三方登录方式
+type OAuthProvider struct {
+	Name        string
+	DisplayName string
+}
+
+// OAuthIdentity 已绑定的第三方身份，同时是凭证 extra 的内容
+type OAuthIdentity struct {
+	Provider string    `json:"provider"`
+	Subject  string    `json:"subject"`
+	Email    string    `json:"email,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Picture  string    `json:"picture,omitempty"`
+	LinkedAt time.Time `json:"-"`
+}
+
+// oauthState 授权 state 对应的服务端数据
+type oauthState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	UserID   uint64 `json:"user_id,omitempty"` // 绑定流程中发起绑定的用户，登录流程为 0
+}
+
+// OAuthLoginResponse 第三方登录响应
+type OAuthLoginResponse struct {
+	User      *model.User
+	Tokens    *TokenPair
+	MFA       *MFAChallenge // 需要两步验证时不签发令牌，返回挑战
+	IsNewUser bool          // 本次登录自动注册了新用户
+}
+
+// ListProviders 已配置的第三方登录方式，按名称排序
+func (l *OAuthLogic) ListProviders() []*OAuthProvider {
+	list := make([]*OAuthProvider, 0, len(l.providers))
+	for name, p := range l.providers {
+		display := p.Config().DisplayName
+		if display == "" {
+			display = name
+		}
+		list = append(list, &OAuthProvider{Name: name, DisplayName: display})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// AuthURL 生成授权地址；userID 不为 0 表示已登录用户发起绑定
+func (l *OAuthLogic) AuthURL(ctx context.Context, providerName string, userID uint64) (string, string, error) {
+	p, err := l.provider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state := randomToken()
+	st := &oauthState{
+		Provider: providerName,
+		Nonce:    randomToken(),
+		Verifier: oauth2.GenerateVerifier(),
+		UserID:   userID,
+	}
+	authURL, err := p.AuthCodeURL(ctx, state, st.Nonce, st.Verifier)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("生成第三方授权地址失败: provider=%s err=%v", providerName, err)
+		return "", "", apperrors.NewInternalError("第三方登录暂不可用，请稍后再试")
+	}
+	data, _ := json.Marshal(st)
+	if err := l.rdb.Set(ctx, stateKey(state), data, l.stateTTL).Err(); err != nil {
+		return "", "", apperrors.NewInternalError("保存授权状态失败: " + err.Error())
+	}
+	return authURL, state, nil
+}
+
+// Login 第三方登录：已绑定直接登录；未绑定时按配置用已验证邮箱绑定已有账号，或自动注册
+func (l *OAuthLogic) Login(ctx context.Context, providerName, code, state string) (*OAuthLoginResponse, error) {
+	p, identity, err := l.exchange(ctx, providerName, code, state, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := l.boundUser(ctx, providerName, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	isNew := false
+	if user == nil {
+		conf := p.Config()
+		// identity.Email 只在第三方已验证时才有值
+		if conf.LinkByEmail && identity.Email != "" {
+			if user, err = l.userRepo.GetByEmail(ctx, identity.Email); err != nil {
+				return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+			}
+			if user != nil {
+				existing, err := l.findIdentity(ctx, user.ID, providerName)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil {
+					return nil, apperrors.NewError(apperrors.CodeAlreadyExists, "该邮箱对应的账号已绑定该平台的其他账号")
+				}
+			}
+		}
+		if user == nil {
+			if !conf.AutoRegister {
+				return nil, apperrors.NewError(apperrors.CodeNotFound, "该第三方账号未绑定，请登录后在账号设置中绑定")
+			}
+			if user, err = l.register(ctx, providerName, identity); err != nil {
+				return nil, err
+			}
+			isNew = true
+		}
+		if err := l.bind(ctx, user.ID, providerName, identity); err != nil {
+			if isNew {
+				_ = l.userRepo.Delete(ctx, user.ID)
+			}
+			return nil, err
+		}
+	}
+
+	if user.Status != constants.UserStatusNormal {
+		return nil, apperrors.NewError(apperrors.CodeForbidden, "用户已被禁用")
+	}
+	challenge, err := l.mfa.Challenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &OAuthLoginResponse{User: user, MFA: challenge, IsNewUser: isNew}, nil
+	}
+	tokens, err := l.tokens.IssueTokens(ctx, user, "")
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthLoginResponse{User: user, Tokens: tokens, IsNewUser: isNew}, nil
+}
+
+// Link 已登录用户绑定第三方账号
+func (l *OAuthLogic) Link(ctx context.Context, userID uint64, providerName, code, state string) (*OAuthIdentity, error) {
+	if userID == 0 {
+		return nil, apperrors.NewError(apperrors.CodeUnauthorized, "未授权，请先登录")
+	}
+	_, identity, err := l.exchange(ctx, providerName, code, state, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := l.credentialRepo.GetByKeyAndType(ctx, credentialKey(providerName, identity.Subject), model.CredentialTypeOIDC)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	if cred != nil {
+		if cred.UserID != userID {
+			return nil, apperrors.NewError(apperrors.CodeAlreadyExists, "该第三方账号已绑定其他用户")
+		}
+		return identityFromCredential(cred), nil
+	}
+
+	existing, err := l.findIdentity(ctx, userID, providerName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperrors.NewError(apperrors.CodeAlreadyExists, "已绑定该平台的其他账号，请先解绑")
+	}
+	if err := l.bind(ctx, userID, providerName, identity); err != nil {
+		return nil, err
+	}
+	identity.Provider = providerName
+	identity.LinkedAt = time.Now()
+	return identity, nil
+}
+
+// ListIdentities 用户已绑定的第三方账号
+func (l *OAuthLogic) ListIdentities(ctx context.Context, userID uint64) ([]*OAuthIdentity, error) {
+	creds, err := l.credentialRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	list := make([]*OAuthIdentity, 0, len(creds))
+	for _, c := range creds {
+		if c.CredentialType == model.CredentialTypeOIDC {
+			list = append(list, identityFromCredential(c))
+		}
+	}
+	return list, nil
+}
+
+// Unlink 解绑第三方账号；解绑后没有其他登录方式（密码、手机号、其他第三方账号）时拒绝
+func (l *OAuthLogic) Unlink(ctx context.Context, userID uint64, providerName string) error {
+	creds, err := l.credentialRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	var target *model.Credential
+	otherMethods := 0
+	for _, c := range creds {
+		switch {
+		case c.CredentialType == model.CredentialTypeOIDC && identityFromCredential(c).Provider == providerName:
+			target = c
+		case c.CredentialType == model.CredentialTypePassword || c.CredentialType == model.CredentialTypeOIDC:
+			otherMethods++
+		}
+	}
+	if target == nil {
+		return apperrors.NewError(apperrors.CodeNotFound, "未绑定该第三方账号")
+	}
+	if otherMethods == 0 {
+		user, err := l.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return apperrors.NewInternalError("查询用户失败: " + err.Error())
+		}
+		if user == nil || user.Phone == "" {
+			return apperrors.NewInvalidParamError("解绑后将无法登录，请先设置密码或绑定手机号")
+		}
+	}
+	if err := l.credentialRepo.Delete(ctx, target.ID); err != nil {
+		return apperrors.NewInternalError("解绑失败: " + err.Error())
+	}
+	return nil
+}
+
+// exchange 校验 state 并用授权码换取第三方身份；state 一次性使用
+func (l *OAuthLogic) exchange(ctx context.Context, providerName, code, state string, userID uint64) (*oidc.Provider, *OAuthIdentity, error) {
+	p, err := l.provider(providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if code == "" || state == "" {
+		return nil, nil, apperrors.NewInvalidParamError("授权码和 state 不能为空")
+	}
+
+	data, err := l.rdb.GetDel(ctx, stateKey(state)).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, nil, apperrors.NewInternalError("查询授权状态失败: " + err.Error())
+	}
+	var st oauthState
+	if len(data) == 0 || json.Unmarshal(data, &st) != nil || st.Provider != providerName || st.UserID != userID {
+		return nil, nil, apperrors.NewInvalidParamError("授权已失效，请重新发起")
+	}
+
+	id, err := p.Exchange(ctx, code, st.Verifier, st.Nonce)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("第三方授权码换取身份失败: provider=%s err=%v", providerName, err)
+		return nil, nil, apperrors.NewError(apperrors.CodeUnauthorized, "第三方授权失败，请重试")
+	}
+	return p, &OAuthIdentity{
+		Provider: providerName,
+		Subject:  id.Subject,
+		Email:    verifiedEmail(id),
+		Name:     id.Name,
+		Picture:  id.Picture,
+	}, nil
+}
+
+// boundUser 第三方身份已绑定的用户；用户已被删除时清理残留凭证并按未绑定处理
+func (l *OAuthLogic) boundUser(ctx context.Context, providerName, subject string) (*model.User, error) {
+	cred, err := l.credentialRepo.GetByKeyAndType(ctx, credentialKey(providerName, subject), model.CredentialTypeOIDC)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	if cred == nil {
+		return nil, nil
+	}
+	user, err := l.userRepo.GetByID(ctx, cred.UserID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		_ = l.credentialRepo.Delete(ctx, cred.ID)
+	}
+	return user, nil
+}
+
+// register 用第三方资料自动注册用户，没有密码凭证
+func (l *OAuthLogic) register(ctx context.Context, providerName string, identity *OAuthIdentity) (*model.User, error) {
+	now := time.Now()
+	user := &model.User{
+		Nickname:    truncateRunes(identity.Name, 50),
+		Status:      constants.UserStatusNormal,
+		MemberLevel: constants.MemberLevelNormal,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if len(identity.Picture) <= 255 {
+		user.Avatar = identity.Picture
+	}
+	// 已验证的邮箱未被占用时作为用户邮箱
+	if identity.Email != "" {
+		existing, err := l.userRepo.GetByEmail(ctx, identity.Email)
+		if err != nil {
+			return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+		}
+		if existing == nil {
+			user.Email = identity.Email
+		}
+	}
+
+	// 用户名 {提供方}_{随机串}，冲突时重试
+	for i := 0; i < 3; i++ {
+		username := truncateRunes(providerName, 40) + "_" + randomToken()[:8]
+		existing, err := l.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+		}
+		if existing == nil {
+			user.Username = username
+			break
+		}
+	}
+	if user.Username == "" {
+		return nil, apperrors.NewInternalError("生成用户名失败")
+	}
+	if err := l.userRepo.CreateWithOmit(ctx, user); err != nil {
+		return nil, apperrors.NewInternalError("创建用户失败: " + err.Error())
+	}
+	return user, nil
+}
+
+// bind 保存第三方身份凭证
+func (l *OAuthLogic) bind(ctx context.Context, userID uint64, providerName string, identity *OAuthIdentity) error {
+	extra, _ := json.Marshal(identity)
+	now := time.Now()
+	cred := &model.Credential{
+		UserID:         userID,
+		CredentialType: model.CredentialTypeOIDC,
+		CredentialKey:  credentialKey(providerName, identity.Subject),
+		Extra:          string(extra),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := l.credentialRepo.Create(ctx, cred); err != nil {
+		return apperrors.NewInternalError("绑定第三方账号失败: " + err.Error())
+	}
+	return nil
+}
+
+// findIdentity 用户在某个提供方下已绑定的身份
+func (l *OAuthLogic) findIdentity(ctx context.Context, userID uint64, providerName string) (*OAuthIdentity, error) {
+	list, err := l.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range list {
+		if id.Provider == providerName {
+			return id, nil
+		}
+	}
+	return nil, nil
+}
+
+func (l *OAuthLogic) provider(name string) (*oidc.Provider, error) {
+	p, ok := l.providers[name]
+	if !ok {
+		return nil, apperrors.NewInvalidParamError("不支持的第三方登录方式")
+	}
+	return p, nil
+}
+
+// credentialKey 第三方身份的凭证 key；sub 过长时取哈希，保证不超过字段长度
+func credentialKey(providerName, subject string) string {
+	key := providerName + ":" + subject
+	if len(key) > 100 {
+		sum := sha256.Sum256([]byte(subject))
+		key = providerName + ":sha256:" + hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+// identityFromCredential 从凭证 extra 还原第三方身份
+func identityFromCredential(c *model.Credential) *OAuthIdentity {
+	id := &OAuthIdentity{}
+	_ = json.Unmarshal([]byte(c.Extra), id)
+	if id.Provider == "" {
+		id.Provider, id.Subject, _ = strings.Cut(c.CredentialKey, ":")
+	}
+	id.LinkedAt = c.CreatedAt
+	return id
+}
+
+// verifiedEmail 只采用第三方已验证的邮箱
+func verifiedEmail(id *oidc.Identity) string {
+	if !id.EmailVerified {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(id.Email))
+}
+
+func stateKey(state string) string {
+	return cache.BuildKey(cache.KeyPrefixOAuthState, state)
+}
+
+// randomToken 32 位十六进制随机串
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/constants"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/sender"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// PasswordResetPolicy 找回密码策略
+type PasswordResetPolicy struct {
+	TokenTTL       time.Duration // 重置链接有效期
+	ResendInterval time.Duration // 同一手机号/邮箱两次申请的最小间隔
+	ResetURL       string        // 前端重置密码页面，token 以查询参数拼接
+}
+
+// errResetTokenInvalid 重置 token 不存在、已使用或已过期
+var errResetTokenInvalid = apperrors.NewInvalidParamError("重置链接已失效，请重新申请")
+
+// PasswordLogic 找回密码和修改密码。
+// 重置 token 只在 Redis 中保存 SHA-256，一次性使用，每个用户只有最新申请的一个有效；
+// 重置或修改密码后吊销所有登录，并通过短信/邮件和站内信通知用户。
+type PasswordLogic struct {
+	userRepo       repository.UserRepository
+	credentialRepo repository.CredentialRepository
+	rdb            *redis.Client
+	sender         sender.Sender
+	notifier       *Notifier
+	tokens         *TokenLogic
+	guard          *LoginGuardLogic
+	mfa            *MFALogic
+	policy         PasswordResetPolicy
+}
+
+// NewPasswordLogic 创建密码业务逻辑
+func NewPasswordLogic(
+	userRepo repository.UserRepository,
+	credentialRepo repository.CredentialRepository,
+	rdb *redis.Client,
+	s sender.Sender,
+	notifier *Notifier,
+	tokens *TokenLogic,
+	guard *LoginGuardLogic,
+	mfa *MFALogic,
+	policy PasswordResetPolicy,
+) *PasswordLogic {
+	if policy.TokenTTL <= 0 {
+		policy.TokenTTL = 30 * time.Minute
+	}
+	if policy.ResendInterval <= 0 {
+		policy.ResendInterval = time.Minute
+	}
+	if policy.ResetURL == "" {
+		policy.ResetURL = "http://localhost:5173/reset-password"
+	}
+	return &PasswordLogic{
+		userRepo:       userRepo,
+		credentialRepo: credentialRepo,
+		rdb:            rdb,
+		sender:         s,
+		notifier:       notifier,
+		tokens:         tokens,
+		guard:          guard,
+		mfa:            mfa,
+		policy:         policy,
+	}
+}
+
+// RequestReset 申请重置密码，返回距下次可申请的间隔（秒）。
+// 目标未注册或账号已禁用时不发送但同样返回成功，避免探测账号是否存在。
+func (l *PasswordLogic) RequestReset(ctx context.Context, target string) (int64, error) {
+	target, channel, err := normalizeTarget(target)
+	if err != nil {
+		return 0, err
+	}
+	interval := int64(l.policy.ResendInterval.Seconds())
+
+	ok, err := l.rdb.SetNX(ctx, resetIntervalKey(target), 1, l.policy.ResendInterval).Result()
+	if err != nil {
+		return 0, apperrors.NewInternalError("申请重置密码失败: " + err.Error())
+	}
+	if !ok {
+		return 0, apperrors.NewError(apperrors.CodeTooManyRequests, "申请过于频繁，请稍后再试")
+	}
+
+	var user *model.User
+	if channel == sender.ChannelSMS {
+		user, err = l.userRepo.GetByPhone(ctx, target)
+	} else {
+		user, err = l.userRepo.GetByEmail(ctx, target)
+	}
+	if err != nil {
+		return 0, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil || user.Status != constants.UserStatusNormal {
+		logx.WithContext(ctx).Infof("重置密码目标未注册或已禁用，跳过发送: target=%s", target)
+		return interval, nil
+	}
+
+	token := randomToken()
+	hash := hashResetToken(token)
+	// 新申请使之前的重置链接失效
+	old, err := l.rdb.Get(ctx, resetUserKey(user.ID)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, apperrors.NewInternalError("申请重置密码失败: " + err.Error())
+	}
+	pipe := l.rdb.TxPipeline()
+	if old != "" {
+		pipe.Del(ctx, resetTokenKey(old))
+	}
+	pipe.Set(ctx, resetTokenKey(hash), user.ID, l.policy.TokenTTL)
+	pipe.Set(ctx, resetUserKey(user.ID), hash, l.policy.TokenTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, apperrors.NewInternalError("保存重置 token 失败: " + err.Error())
+	}
+
+	msg := &sender.Message{
+		Channel: channel,
+		To:      target,
+		Subject: "重置密码",
+		Content: fmt.Sprintf("您正在重置账号 %s 的密码，请在 %d 分钟内打开链接设置新密码：%s 。链接只能使用一次，如非本人操作，请忽略。",
+			user.Username, int(l.policy.TokenTTL.Minutes()), l.resetLink(token)),
+	}
+	if err := l.sender.Send(ctx, msg); err != nil {
+		_ = l.rdb.Del(ctx, resetTokenKey(hash), resetUserKey(user.ID), resetIntervalKey(target)).Err()
+		return 0, apperrors.NewInternalError("发送重置链接失败: " + err.Error())
+	}
+	return interval, nil
+}
+
+// Reset 用重置 token 设置新密码，吊销所有登录并解除登录锁定
+func (l *PasswordLogic) Reset(ctx context.Context, token, newPassword, ip string) error {
+	if token == "" || newPassword == "" {
+		return apperrors.NewInvalidParamError("重置 token 和新密码不能为空")
+	}
+	hash := hashResetToken(token)
+	userID, err := l.rdb.Get(ctx, resetTokenKey(hash)).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return errResetTokenInvalid
+	}
+	if err != nil {
+		return apperrors.NewInternalError("查询重置 token 失败: " + err.Error())
+	}
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return errResetTokenInvalid
+	}
+	if user.Status != constants.UserStatusNormal {
+		return apperrors.NewError(apperrors.CodeUserDisabled, "用户已被禁用")
+	}
+	// 先校验密码策略再作废 token，新密码不合格时可以换一个重试
+	if err := utils.ValidatePassword(newPassword, user.Username); err != nil {
+		return apperrors.NewInvalidParamError(err.Error())
+	}
+	n, err := l.rdb.Del(ctx, resetTokenKey(hash)).Result()
+	if err != nil {
+		return apperrors.NewInternalError("作废重置 token 失败: " + err.Error())
+	}
+	if n == 0 { // 并发请求已经用掉了这个 token
+		return errResetTokenInvalid
+	}
+	_ = l.rdb.Del(ctx, resetUserKey(user.ID)).Err()
+
+	if err := l.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+	if _, err := l.tokens.RevokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+	l.guard.Clear(ctx, user)
+	l.guard.audit(ctx, &model.SecurityLog{
+		UserID:     user.ID,
+		Event:      model.SecurityEventPasswordReset,
+		OperatorID: user.ID,
+		ClientIP:   ip,
+		Detail:     "通过找回密码重置",
+	})
+	l.notifier.Notify(ctx, user, "密码已重置",
+		fmt.Sprintf("您的账号密码已于 %s 重置，所有设备已退出登录。如非本人操作，请立即联系客服。", time.Now().Format("2006-01-02 15:04")), "")
+	return nil
+}
+
+// Change 已登录用户修改密码：校验原密码，开启两步验证时还要校验验证码，原密码错误计入登录防护的失败次数。
+// 没有密码凭证（第三方登录注册）的账号只凭登录状态不能设置密码，需要通过找回密码设置。
+// 所有登录被吊销，返回当前设备的新令牌。
+func (l *PasswordLogic) Change(ctx context.Context, userID uint64, oldPassword, newPassword, mfaCode, ip string) (*model.User, *TokenPair, error) {
+	if newPassword == "" {
+		return nil, nil, apperrors.NewInvalidParamError("新密码不能为空")
+	}
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	credential, err := l.credentialRepo.GetByUserIDAndType(ctx, user.ID, model.CredentialTypePassword)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	if credential == nil {
+		return nil, nil, apperrors.NewError(apperrors.CodeForbidden, "账号未设置密码，请通过找回密码设置")
+	}
+	// 被盗用的登录态不能借修改密码无限次猜测原密码
+	account := loginAccount(user, "")
+	if err := l.guard.CheckLocked(ctx, account, ip); err != nil {
+		return nil, nil, err
+	}
+	if !utils.CheckPassword(oldPassword, credential.CredentialValue) {
+		l.guard.Fail(ctx, user, account, ip)
+		return nil, nil, apperrors.NewError(apperrors.CodePasswordError, "原密码错误")
+	}
+	if err := l.mfa.CheckCode(ctx, user.ID, mfaCode); err != nil {
+		var bizErr *apperrors.BusinessError
+		if errors.As(err, &bizErr) && bizErr.Code == apperrors.CodeMFACodeError {
+			l.guard.Fail(ctx, user, account, ip)
+		}
+		return nil, nil, err
+	}
+	if newPassword == oldPassword {
+		return nil, nil, apperrors.NewInvalidParamError("新密码不能与原密码相同")
+	}
+	if err := utils.ValidatePassword(newPassword, user.Username); err != nil {
+		return nil, nil, apperrors.NewInvalidParamError(err.Error())
+	}
+
+	if err := l.setPassword(ctx, user, newPassword); err != nil {
+		return nil, nil, err
+	}
+	if _, err := l.tokens.RevokeAllSessions(ctx, user.ID); err != nil {
+		return nil, nil, err
+	}
+	tokens, err := l.tokens.IssueTokens(ctx, user, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	// 未使用的重置链接随之失效
+	if hash, _ := l.rdb.GetDel(ctx, resetUserKey(user.ID)).Result(); hash != "" {
+		_ = l.rdb.Del(ctx, resetTokenKey(hash)).Err()
+	}
+	l.guard.audit(ctx, &model.SecurityLog{
+		UserID:     user.ID,
+		Event:      model.SecurityEventPasswordChanged,
+		OperatorID: user.ID,
+		ClientIP:   ip,
+	})
+	l.notifier.Notify(ctx, user, "密码已修改",
+		fmt.Sprintf("您的账号密码已于 %s 修改，其他设备已退出登录。如非本人操作，请立即通过找回密码重置。", time.Now().Format("2006-01-02 15:04")), "")
+	return user, tokens, nil
+}
+
+// setPassword 更新密码凭证，没有时创建
+func (l *PasswordLogic) setPassword(ctx context.Context, user *model.User, password string) error {
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return apperrors.NewInternalError("密码加密失败: " + err.Error())
+	}
+	credential, err := l.credentialRepo.GetByUserIDAndType(ctx, user.ID, model.CredentialTypePassword)
+	if err != nil {
+		return apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	now := time.Now()
+	if credential == nil {
+		err = l.credentialRepo.Create(ctx, &model.Credential{
+			UserID:          user.ID,
+			CredentialType:  model.CredentialTypePassword,
+			CredentialKey:   user.Username,
+			CredentialValue: hashed,
+			Extra:           "{}",
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		})
+	} else {
+		credential.CredentialValue = hashed
+		credential.UpdatedAt = now
+		err = l.credentialRepo.Update(ctx, credential)
+	}
+	if err != nil {
+		return apperrors.NewInternalError("保存密码失败: " + err.Error())
+	}
+	return nil
+}
+
+// resetLink 重置密码页面地址
+func (l *PasswordLogic) resetLink(token string) string {
+	u, err := url.Parse(l.policy.ResetURL)
+	if err != nil {
+		return l.policy.ResetURL + "?token=" + url.QueryEscape(token)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// hashResetToken 重置 token 的 SHA-256，Redis 中只保存哈希
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func resetTokenKey(hash string) string {
+	return cache.BuildKey(cache.KeyPrefixPasswordReset, hash)
+}
+
+func resetUserKey(userID uint64) string {
+	return cache.BuildKey(cache.KeyPrefixPasswordReset, "user", userID)
+}
+
+func resetIntervalKey(target string) string {
+	return cache.BuildKey(cache.KeyPrefixPasswordReset, "interval", target)
+}
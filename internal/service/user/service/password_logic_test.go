@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/loginguard"
+	"ecommerce-system/internal/pkg/totp"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+)
+
+const testOldPassword = "OldPass123"
+
+// newTestPasswordLogic 用户 7 已开启两步验证，withPassword 为 false 时模拟第三方登录注册、没有密码凭证的账号。
+// 原密码连续错误 3 次锁定账号。
+func newTestPasswordLogic(t *testing.T, withPassword bool) (*PasswordLogic, *memCredentialRepo, string) {
+	t.Helper()
+	mfa, creds, user, secret, _ := newTestMFALogic(t)
+	if withPassword {
+		hashed, err := utils.HashPassword(testOldPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = creds.Create(context.Background(), &model.Credential{
+			UserID: user.ID, CredentialType: model.CredentialTypePassword, CredentialKey: user.Username, CredentialValue: hashed,
+		})
+	}
+	guard := NewLoginGuardLogic(loginguard.NewGuard(mfa.rdb, loginguard.Config{LockAfter: 3}), nil, mfa.userRepo, &memSecurityLogRepo{})
+	logic := NewPasswordLogic(mfa.userRepo, creds, mfa.rdb, nil, NewNotifier(nil, nil), mfa.tokens, guard, mfa, PasswordResetPolicy{})
+	return logic, creds, secret
+}
+
+func assertBizCode(t *testing.T, err error, code int) {
+	t.Helper()
+	bizErr, ok := err.(*apperrors.BusinessError)
+	if !ok || bizErr.Code != code {
+		t.Fatalf("error = %v, want code %d", err, code)
+	}
+}
+
+func TestChangePasswordRequiresMFACode(t *testing.T) {
+	logic, creds, secret := newTestPasswordLogic(t, true)
+	ctx := context.Background()
+
+	_, _, err := logic.Change(ctx, 7, testOldPassword, "NewPass456", "", "10.0.0.1")
+	assertBizCode(t, err, apperrors.CodeMFACodeError)
+	_, _, err = logic.Change(ctx, 7, testOldPassword, "NewPass456", "000000", "10.0.0.1")
+	assertBizCode(t, err, apperrors.CodeMFACodeError)
+
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	if _, tokens, err := logic.Change(ctx, 7, testOldPassword, "NewPass456", code, "10.0.0.1"); err != nil || tokens == nil {
+		t.Fatalf("Change with TOTP code: %v", err)
+	}
+	cred, _ := creds.GetByUserIDAndType(ctx, 7, model.CredentialTypePassword)
+	if !utils.CheckPassword("NewPass456", cred.CredentialValue) {
+		t.Fatal("password not changed")
+	}
+}
+
+func TestChangePasswordWrongOldPasswordLocksAccount(t *testing.T) {
+	logic, _, secret := newTestPasswordLogic(t, true)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, _, err := logic.Change(ctx, 7, "guess-"+string(rune('a'+i)), "NewPass456", "", "10.0.0.1")
+		assertBizCode(t, err, apperrors.CodePasswordError)
+	}
+	// 原密码错误计入登录防护：锁定后即使原密码和验证码都正确也不能修改
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	_, _, err := logic.Change(ctx, 7, testOldPassword, "NewPass456", code, "10.0.0.1")
+	assertBizCode(t, err, apperrors.CodeAccountLocked)
+}
+
+func TestChangePasswordWithoutCredentialRequiresReset(t *testing.T) {
+	logic, creds, secret := newTestPasswordLogic(t, false)
+	ctx := context.Background()
+
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	_, _, err := logic.Change(ctx, 7, "", "NewPass456", code, "10.0.0.1")
+	assertBizCode(t, err, apperrors.CodeForbidden)
+	if cred, _ := creds.GetByUserIDAndType(ctx, 7, model.CredentialTypePassword); cred != nil {
+		t.Fatal("password set for an account without one")
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/rbac"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// RoleLogic 角色分配。
+// 角色和权限在签发访问令牌时写入 JWT，分配或移除角色后吊销该用户已签发的访问令牌，
+// 客户端用刷新令牌换新后即获得新的权限。
+type RoleLogic struct {
+	roleRepo  repository.RoleRepository
+	userRepo  repository.UserRepository
+	denylist  *revocation.Denylist
+	accessTTL time.Duration
+}
+
+// NewRoleLogic 创建角色业务逻辑
+func NewRoleLogic(
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	denylist *revocation.Denylist,
+	accessExpire int64,
+) *RoleLogic {
+	return &RoleLogic{
+		roleRepo:  roleRepo,
+		userRepo:  userRepo,
+		denylist:  denylist,
+		accessTTL: time.Duration(accessExpire) * time.Second,
+	}
+}
+
+// ListRoles 获取全部角色
+func (l *RoleLogic) ListRoles(ctx context.Context) ([]*model.Role, error) {
+	roles, err := l.roleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询角色失败: " + err.Error())
+	}
+	return roles, nil
+}
+
+// GetUserRoles 获取用户的角色
+func (l *RoleLogic) GetUserRoles(ctx context.Context, userID uint64) ([]*model.Role, error) {
+	if userID == 0 {
+		return nil, apperrors.NewInvalidParamError("用户ID不能为空")
+	}
+	roles, err := l.roleRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户角色失败: " + err.Error())
+	}
+	return roles, nil
+}
+
+// AssignRole 给用户分配角色
+func (l *RoleLogic) AssignRole(ctx context.Context, userID uint64, roleCode string) ([]*model.Role, error) {
+	role, err := l.checkUserAndRole(ctx, userID, roleCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.roleRepo.AssignRole(ctx, userID, role.ID); err != nil {
+		return nil, apperrors.NewInternalError("分配角色失败: " + err.Error())
+	}
+	if err := l.revokeAccessTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	return l.GetUserRoles(ctx, userID)
+}
+
+// RemoveRole 移除用户角色；不允许移除最后一个超级管理员
+func (l *RoleLogic) RemoveRole(ctx context.Context, userID uint64, roleCode string) ([]*model.Role, error) {
+	role, err := l.checkUserAndRole(ctx, userID, roleCode)
+	if err != nil {
+		return nil, err
+	}
+	current, err := l.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRole(current, role.Code) {
+		return current, nil
+	}
+	if role.Code == rbac.RoleSuperAdmin {
+		n, err := l.roleRepo.CountRoleUsers(ctx, role.ID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("查询角色失败: " + err.Error())
+		}
+		if n <= 1 {
+			return nil, apperrors.NewForbiddenError("至少保留一个超级管理员")
+		}
+	}
+
+	if err := l.roleRepo.RemoveRole(ctx, userID, role.ID); err != nil {
+		return nil, apperrors.NewInternalError("移除角色失败: " + err.Error())
+	}
+	if err := l.revokeAccessTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	return l.GetUserRoles(ctx, userID)
+}
+
+// SyncBuiltin 同步 rbac 包中定义的权限点和内置角色
+func (l *RoleLogic) SyncBuiltin(ctx context.Context) error {
+	perms := make([]*model.Permission, 0, len(rbac.Permissions))
+	now := time.Now()
+	for _, p := range rbac.Permissions {
+		perms = append(perms, &model.Permission{Code: p.Code, Name: p.Name, CreatedAt: now, UpdatedAt: now})
+	}
+	if err := l.roleRepo.SavePermissions(ctx, perms); err != nil {
+		return err
+	}
+	for _, r := range rbac.BuiltinRoles {
+		if err := l.roleRepo.SaveRole(ctx, &model.Role{Code: r.Code, Name: r.Name}, r.Permissions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUserAndRole 校验用户和角色都存在
+func (l *RoleLogic) checkUserAndRole(ctx context.Context, userID uint64, roleCode string) (*model.Role, error) {
+	if userID == 0 || roleCode == "" {
+		return nil, apperrors.NewInvalidParamError("用户ID和角色编码不能为空")
+	}
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	role, err := l.roleRepo.GetRoleByCode(ctx, roleCode)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询角色失败: " + err.Error())
+	}
+	if role == nil {
+		return nil, apperrors.NewNotFoundError("角色不存在: " + roleCode)
+	}
+	return role, nil
+}
+
+// revokeAccessTokens 让用户已签发的访问令牌失效，刷新令牌保留（刷新后带上新的角色）
+func (l *RoleLogic) revokeAccessTokens(ctx context.Context, userID uint64) error {
+	if l.denylist == nil {
+		return nil
+	}
+	if err := l.denylist.RevokeUser(ctx, userID, time.Now(), l.accessTTL); err != nil {
+		return apperrors.NewInternalError("吊销访问令牌失败: " + err.Error())
+	}
+	return nil
+}
+
+// hasRole 角色列表中是否包含 code
+func hasRole(roles []*model.Role, code string) bool {
+	for _, r := range roles {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// grantsOf 汇总角色编码和权限（权限去重排序），写入访问令牌
+func grantsOf(roles []*model.Role) ([]string, []string) {
+	codes := make([]string, 0, len(roles))
+	set := make(map[string]struct{})
+	for _, r := range roles {
+		codes = append(codes, r.Code)
+		for _, p := range r.Permissions {
+			set[p] = struct{}{}
+		}
+	}
+	perms := make([]string, 0, len(set))
+	for p := range set {
+		perms = append(perms, p)
+	}
+	sort.Strings(perms)
+	return codes, perms
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/geoip"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// SessionLogic 登录会话：记录每次登录的设备、IP 和所在地，刷新令牌时更新最近活跃时间，
+// 在没登录过的设备上登录时发送提醒。会话与刷新令牌族一一对应，吊销会话即吊销令牌族（TokenLogic.RevokeSession）。
+// 为 nil 时不记录会话，方便单元测试
+type SessionLogic struct {
+	repo     repository.SessionRepository
+	geo      *geoip.Reader // 为 nil 时不解析所在地
+	notifier *Notifier
+	guard    *LoginGuardLogic
+}
+
+// NewSessionLogic 创建登录会话业务逻辑
+func NewSessionLogic(repo repository.SessionRepository, geo *geoip.Reader, notifier *Notifier, guard *LoginGuardLogic) *SessionLogic {
+	return &SessionLogic{
+		repo:     repo,
+		geo:      geo,
+		notifier: notifier,
+		guard:    guard,
+	}
+}
+
+// Start 新登录时创建会话；用户以前有过会话而这台设备没登录过时发送新设备登录提醒
+func (l *SessionLogic) Start(ctx context.Context, user *model.User, familyID, accessJTI string, expiresAt time.Time) error {
+	if l == nil {
+		return nil
+	}
+	ua, deviceID, ip := utils.GetUserAgent(ctx), utils.GetDeviceID(ctx), utils.GetClientIP(ctx)
+	fingerprint := deviceFingerprint(deviceID, ua)
+
+	newDevice := false
+	if fingerprint != "" {
+		seen, firstLogin, err := l.repo.DeviceSeen(ctx, user.ID, fingerprint)
+		if err != nil {
+			// 查询失败按已知设备处理，宁可漏发也不误报
+			logx.WithContext(ctx).Errorf("查询登录设备失败: user_id=%d err=%v", user.ID, err)
+		} else {
+			newDevice = !seen && !firstLogin
+		}
+	}
+
+	now := time.Now()
+	session := &model.UserSession{
+		UserID:            user.ID,
+		FamilyID:          familyID,
+		DeviceFingerprint: fingerprint,
+		DeviceName:        describeUserAgent(ua),
+		UserAgent:         ua,
+		IP:                ip,
+		Location:          l.geo.Lookup(ip),
+		LoginIP:           ip,
+		AccessJTI:         accessJTI,
+		LastSeenAt:        now,
+		ExpiresAt:         expiresAt,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := l.repo.Create(ctx, session); err != nil {
+		return apperrors.NewInternalError("保存登录会话失败: " + err.Error())
+	}
+
+	if newDevice {
+		l.guard.audit(ctx, &model.SecurityLog{
+			UserID:     user.ID,
+			Event:      model.SecurityEventNewDeviceLogin,
+			OperatorID: user.ID,
+			ClientIP:   ip,
+			Detail:     sessionSummary(session),
+		})
+		// 邮件/短信发送较慢，不阻塞登录
+		go l.notifyNewDevice(context.WithoutCancel(ctx), user, session)
+	}
+	return nil
+}
+
+// Touch 刷新令牌时更新会话的最近活跃时间、IP 和过期时间，失败只记录日志
+func (l *SessionLogic) Touch(ctx context.Context, familyID, accessJTI string, expiresAt time.Time) {
+	if l == nil {
+		return
+	}
+	ip := utils.GetClientIP(ctx)
+	activity := &repository.SessionActivity{
+		IP:        ip,
+		Location:  l.geo.Lookup(ip),
+		UserAgent: utils.GetUserAgent(ctx),
+		AccessJTI: accessJTI,
+		ExpiresAt: expiresAt,
+		At:        time.Now(),
+	}
+	if err := l.repo.Touch(ctx, familyID, activity); err != nil {
+		logx.WithContext(ctx).Errorf("更新登录会话失败: family_id=%s err=%v", familyID, err)
+	}
+}
+
+// EndFamily 令牌族已吊销，标记对应会话结束。失败只记录日志，令牌吊销已生效
+func (l *SessionLogic) EndFamily(ctx context.Context, familyID string) {
+	if l == nil {
+		return
+	}
+	if err := l.repo.RevokeFamily(ctx, familyID, time.Now()); err != nil {
+		logx.WithContext(ctx).Errorf("标记登录会话结束失败: family_id=%s err=%v", familyID, err)
+	}
+}
+
+// EndAll 用户的全部令牌已吊销，标记全部会话结束
+func (l *SessionLogic) EndAll(ctx context.Context, userID uint64) {
+	if l == nil {
+		return
+	}
+	if err := l.repo.RevokeByUser(ctx, userID, time.Now()); err != nil {
+		logx.WithContext(ctx).Errorf("标记登录会话结束失败: user_id=%d err=%v", userID, err)
+	}
+}
+
+// List 分页获取用户的会话，activeOnly 时只返回仍有效的
+func (l *SessionLogic) List(ctx context.Context, userID uint64, activeOnly bool, page, pageSize int) ([]*model.UserSession, int64, error) {
+	if userID == 0 {
+		return nil, 0, apperrors.NewInvalidParamError("用户ID不能为空")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	sessions, total, err := l.repo.ListByUser(ctx, userID, activeOnly, page, pageSize)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("查询登录会话失败: " + err.Error())
+	}
+	return sessions, total, nil
+}
+
+// get 获取用户的会话，不存在或不属于该用户时返回 NotFound
+func (l *SessionLogic) get(ctx context.Context, userID, sessionID uint64) (*model.UserSession, error) {
+	if sessionID == 0 {
+		return nil, apperrors.NewInvalidParamError("会话ID不能为空")
+	}
+	session, err := l.repo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询登录会话失败: " + err.Error())
+	}
+	if session == nil || session.UserID != userID {
+		return nil, apperrors.NewError(apperrors.CodeNotFound, "登录会话不存在")
+	}
+	return session, nil
+}
+
+// notifyNewDevice 发送新设备登录提醒
+func (l *SessionLogic) notifyNewDevice(ctx context.Context, user *model.User, session *model.UserSession) {
+	if l.notifier == nil {
+		return
+	}
+	l.notifier.Notify(ctx, user, "新设备登录提醒",
+		fmt.Sprintf("您的账号于 %s 在新设备上登录（%s）。如非本人操作，请立即修改密码，并在“登录设备”中下线该设备。",
+			session.CreatedAt.Format("2006-01-02 15:04"), sessionSummary(session)), "/profile")
+}
+
+// sessionSummary 设备、IP 和所在地的简短描述，用于通知和安全日志
+func sessionSummary(s *model.UserSession) string {
+	parts := []string{s.DeviceName}
+	if s.IP != "" {
+		parts = append(parts, "IP "+s.IP)
+	}
+	if s.Location != "" {
+		parts = append(parts, s.Location)
+	}
+	summary := []rune(strings.Join(parts, "，"))
+	if len(summary) > 255 {
+		summary = summary[:255]
+	}
+	return string(summary)
+}
+
+// deviceFingerprint 设备指纹：优先使用客户端生成并持久保存的设备标识，没有时退化为 User-Agent。
+// 两者都没有（内部调用、直连 gRPC）时返回空，不做新设备判断
+func deviceFingerprint(deviceID, userAgent string) string {
+	var source string
+	switch {
+	case deviceID != "":
+		source = "device:" + deviceID
+	case userAgent != "":
+		source = "ua:" + userAgent
+	default:
+		return ""
+	}
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// 常见浏览器和系统的 User-Agent 特征，按顺序匹配（Edge、Opera、微信的 UA 里也带 Chrome/Safari）
+var (
+	uaBrowsers = []struct{ token, name string }{
+		{"MicroMessenger/", "微信"},
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+	uaSystems = []struct{ token, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// describeUserAgent 由 User-Agent 得到设备名称，如“Chrome / Windows”；
+// 识别不出的返回第一个产品标识（如 curl），为空时返回“未知设备”
+func describeUserAgent(ua string) string {
+	if ua == "" {
+		return "未知设备"
+	}
+	var browser, system string
+	for _, b := range uaBrowsers {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, s := range uaSystems {
+		if strings.Contains(ua, s.token) {
+			system = s.name
+			break
+		}
+	}
+	switch {
+	case browser != "" && system != "":
+		return browser + " / " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	product, _, _ := strings.Cut(ua, " ")
+	product, _, _ = strings.Cut(product, "/")
+	if len(product) > 64 {
+		product = product[:64]
+	}
+	return product
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ecommerce-system/internal/pkg/constants"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// memSessionRepo 内存版登录会话仓储
+type memSessionRepo struct {
+	sessions []*model.UserSession
+}
+
+var _ repository.SessionRepository = (*memSessionRepo)(nil)
+
+func (m *memSessionRepo) Create(ctx context.Context, session *model.UserSession) error {
+	session.ID = uint64(len(m.sessions) + 1)
+	m.sessions = append(m.sessions, session)
+	return nil
+}
+
+func (m *memSessionRepo) GetByID(ctx context.Context, id uint64) (*model.UserSession, error) {
+	for _, s := range m.sessions {
+		if s.ID == id {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memSessionRepo) Touch(ctx context.Context, familyID string, activity *repository.SessionActivity) error {
+	for _, s := range m.sessions {
+		if s.FamilyID == familyID && s.RevokedAt == nil {
+			s.AccessJTI, s.ExpiresAt, s.LastSeenAt = activity.AccessJTI, activity.ExpiresAt, activity.At
+			if activity.IP != "" {
+				s.IP, s.Location = activity.IP, activity.Location
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memSessionRepo) ListByUser(ctx context.Context, userID uint64, activeOnly bool, page, pageSize int) ([]*model.UserSession, int64, error) {
+	var out []*model.UserSession
+	for _, s := range m.sessions {
+		if s.UserID == userID && (!activeOnly || s.Active(time.Now())) {
+			out = append(out, s)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *memSessionRepo) DeviceSeen(ctx context.Context, userID uint64, fingerprint string) (bool, bool, error) {
+	first := true
+	for _, s := range m.sessions {
+		if s.UserID != userID {
+			continue
+		}
+		first = false
+		if s.DeviceFingerprint == fingerprint {
+			return true, false, nil
+		}
+	}
+	return false, first, nil
+}
+
+func (m *memSessionRepo) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	for _, s := range m.sessions {
+		if s.FamilyID == familyID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *memSessionRepo) RevokeByUser(ctx context.Context, userID uint64, at time.Time) error {
+	for _, s := range m.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// memSecurityLogRepo 内存版安全日志仓储
+type memSecurityLogRepo struct {
+	logs []*model.SecurityLog
+}
+
+func (m *memSecurityLogRepo) Create(ctx context.Context, log *model.SecurityLog) error {
+	m.logs = append(m.logs, log)
+	return nil
+}
+
+func clientContext(deviceID, ua, ip string) context.Context {
+	ctx := utils.WithClientIP(context.Background(), ip)
+	ctx = utils.WithUserAgent(ctx, ua)
+	return utils.WithDeviceID(ctx, deviceID)
+}
+
+func TestSessionLogic_NewDeviceAndRevoke(t *testing.T) {
+	user := &model.User{ID: 7, Username: "alice", Status: constants.UserStatusNormal}
+	sessions := &memSessionRepo{}
+	logs := &memSecurityLogRepo{}
+	guard := NewLoginGuardLogic(nil, nil, nil, logs)
+	logic := NewTokenLogic(&mockUserRepo{users: map[uint64]*model.User{user.ID: user}}, &memTokenRepo{}, nil, nil,
+		NewSessionLogic(sessions, nil, nil, guard), "test-secret", 900, 3600)
+
+	chrome := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36"
+	// 第一次登录和同一设备再次登录都不算新设备
+	first, _ := logic.IssueTokens(clientContext("dev-1", chrome, "10.0.0.1"), user, "")
+	if _, err := logic.IssueTokens(clientContext("dev-1", chrome, "10.0.0.2"), user, ""); err != nil {
+		t.Fatalf("IssueTokens() error = %v", err)
+	}
+	if len(logs.logs) != 0 {
+		t.Fatalf("known device should not be reported: %+v", logs.logs)
+	}
+	if _, err := logic.IssueTokens(clientContext("dev-2", "curl/8.0", "10.0.0.3"), user, ""); err != nil {
+		t.Fatalf("IssueTokens() error = %v", err)
+	}
+	if len(logs.logs) != 1 || logs.logs[0].Event != model.SecurityEventNewDeviceLogin {
+		t.Fatalf("expected one new_device_login log, got %+v", logs.logs)
+	}
+	if s := sessions.sessions[0]; s.DeviceName != "Chrome / Windows" || s.Location != "局域网" {
+		t.Fatalf("unexpected session %+v", s)
+	}
+
+	// 刷新令牌更新会话的 IP 和当前访问令牌
+	_, pair, err := logic.Refresh(clientContext("dev-1", chrome, "10.0.0.9"), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	claims, _ := utils.ParseToken(pair.AccessToken, "test-secret")
+	if s := sessions.sessions[0]; s.IP != "10.0.0.9" || s.AccessJTI != claims.ID {
+		t.Fatalf("session not touched: %+v", s)
+	}
+
+	// 不能下线别人的会话；下线后刷新令牌失效
+	if err := logic.RevokeSession(context.Background(), 8, 1, 8, ""); err == nil {
+		t.Fatal("revoking another user's session should fail")
+	}
+	if err := logic.RevokeSession(context.Background(), user.ID, 1, user.ID, ""); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if sessions.sessions[0].RevokedAt == nil {
+		t.Fatal("session should be revoked")
+	}
+	if _, _, err := logic.Refresh(context.Background(), pair.RefreshToken); err == nil {
+		t.Fatal("refresh token of revoked session should be rejected")
+	}
+	if active, _, _ := sessions.ListByUser(context.Background(), user.ID, true, 1, 20); len(active) != 2 {
+		t.Fatalf("expected 2 active sessions, got %d", len(active))
+	}
+}
+
+func TestDescribeUserAgent(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36 Edg/126.0":                   "Edge / Windows",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1": "Safari / iPhone",
+		"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 Chrome/126.0 Mobile Safari/537.36 MicroMessenger/8.0.49":                              "微信 / Android",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.5; rv:127.0) Gecko/20100101 Firefox/127.0":                                                     "Firefox / macOS",
+		"curl/8.6.0": "curl",
+		"":           "未知设备",
+	}
+	for ua, want := range cases {
+		if got := describeUserAgent(ua); got != want {
+			t.Errorf("describeUserAgent(%q) = %q, want %q", ua, got, want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"ecommerce-system/internal/pkg/constants"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// TokenLogic 令牌签发、刷新和吊销。
+// 访问令牌为短期 JWT；刷新令牌为随机串，库里只存哈希，每次刷新轮换，
+// 已轮换的刷新令牌再次出现说明被盗用，整族吊销，用户需要重新登录。
+type TokenLogic struct {
+	userRepo   repository.UserRepository
+	tokenRepo  repository.RefreshTokenRepository
+	roleRepo   repository.RoleRepository
+	denylist   *revocation.Denylist
+	sessions   *SessionLogic // 为 nil 时不记录登录会话
+	jwtSecret  string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenLogic 创建令牌业务逻辑
+func NewTokenLogic(
+	userRepo repository.UserRepository,
+	tokenRepo repository.RefreshTokenRepository,
+	roleRepo repository.RoleRepository,
+	denylist *revocation.Denylist,
+	sessions *SessionLogic,
+	jwtSecret string,
+	accessExpire, refreshExpire int64,
+) *TokenLogic {
+	return &TokenLogic{
+		userRepo:   userRepo,
+		tokenRepo:  tokenRepo,
+		roleRepo:   roleRepo,
+		denylist:   denylist,
+		sessions:   sessions,
+		jwtSecret:  jwtSecret,
+		accessTTL:  time.Duration(accessExpire) * time.Second,
+		refreshTTL: time.Duration(refreshExpire) * time.Second,
+	}
+}
+
+// TokenPair 一次签发的访问令牌和刷新令牌
+type TokenPair struct {
+	AccessToken     string
+	AccessExpireAt  time.Time
+	RefreshToken    string
+	RefreshExpireAt time.Time
+}
+
+// IssueTokens 签发令牌对；familyID 为空表示新登录，开启新的令牌族并记录登录会话。
+// 每次签发都重新读取用户角色，角色变更在下一次刷新时生效。
+func (l *TokenLogic) IssueTokens(ctx context.Context, user *model.User, familyID string) (*TokenPair, error) {
+	newLogin := familyID == ""
+	if newLogin {
+		familyID = uuid.NewString()
+	}
+
+	sub := utils.TokenSubject{UserID: user.ID, Username: user.Username}
+	if l.roleRepo != nil {
+		roles, err := l.roleRepo.GetUserRoles(ctx, user.ID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("查询用户角色失败: " + err.Error())
+		}
+		sub.Roles, sub.Permissions = grantsOf(roles)
+	}
+
+	now := time.Now()
+	accessToken, jti, err := utils.GenerateAccessToken(sub, l.jwtSecret, int64(l.accessTTL.Seconds()))
+	if err != nil {
+		return nil, apperrors.NewInternalError("生成Token失败: " + err.Error())
+	}
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, apperrors.NewInternalError("生成刷新令牌失败: " + err.Error())
+	}
+
+	pair := &TokenPair{
+		AccessToken:     accessToken,
+		AccessExpireAt:  now.Add(l.accessTTL),
+		RefreshToken:    refreshToken,
+		RefreshExpireAt: now.Add(l.refreshTTL),
+	}
+	if err := l.tokenRepo.Create(ctx, &model.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hashRefreshToken(refreshToken),
+		AccessJTI:       jti,
+		AccessExpiresAt: pair.AccessExpireAt,
+		ExpiresAt:       pair.RefreshExpireAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}); err != nil {
+		return nil, apperrors.NewInternalError("保存刷新令牌失败: " + err.Error())
+	}
+
+	if newLogin {
+		if err := l.sessions.Start(ctx, user, familyID, jti, pair.RefreshExpireAt); err != nil {
+			return nil, err
+		}
+	} else {
+		l.sessions.Touch(ctx, familyID, jti, pair.RefreshExpireAt)
+	}
+	return pair, nil
+}
+
+// Refresh 用刷新令牌换取新的令牌对，旧刷新令牌随即失效
+func (l *TokenLogic) Refresh(ctx context.Context, refreshToken string) (*model.User, *TokenPair, error) {
+	if refreshToken == "" {
+		return nil, nil, apperrors.NewInvalidParamError("刷新令牌不能为空")
+	}
+
+	token, err := l.tokenRepo.GetByTokenHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("查询刷新令牌失败: " + err.Error())
+	}
+	if token == nil || token.RevokedAt != nil {
+		return nil, nil, apperrors.NewError(apperrors.CodeTokenInvalid, "刷新令牌无效，请重新登录")
+	}
+	if token.UsedAt != nil {
+		l.revokeReusedFamily(ctx, token)
+		return nil, nil, apperrors.NewError(apperrors.CodeTokenInvalid, "刷新令牌已被使用，请重新登录")
+	}
+	now := time.Now()
+	if now.After(token.ExpiresAt) {
+		return nil, nil, apperrors.NewError(apperrors.CodeTokenExpired, "刷新令牌已过期，请重新登录")
+	}
+
+	user, err := l.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil || user.Status != constants.UserStatusNormal {
+		l.revokeFamily(ctx, token.FamilyID)
+		return nil, nil, apperrors.NewError(apperrors.CodeUserDisabled, "用户不存在或已被禁用")
+	}
+
+	// 条件更新保证同一个刷新令牌只能轮换一次，并发请求中落败的一方按重放处理
+	ok, err := l.tokenRepo.MarkUsed(ctx, token.ID, now)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("更新刷新令牌失败: " + err.Error())
+	}
+	if !ok {
+		l.revokeReusedFamily(ctx, token)
+		return nil, nil, apperrors.NewError(apperrors.CodeTokenInvalid, "刷新令牌已被使用，请重新登录")
+	}
+
+	pair, err := l.IssueTokens(ctx, user, token.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, pair, nil
+}
+
+// Logout 登出：吊销当前访问令牌；带上刷新令牌时同时吊销该登录的整个令牌族
+func (l *TokenLogic) Logout(ctx context.Context, userID uint64, claims *utils.JWTClaims, refreshToken string) error {
+	if claims != nil && claims.ExpiresAt != nil && l.denylist != nil {
+		if err := l.denylist.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return apperrors.NewInternalError("吊销访问令牌失败: " + err.Error())
+		}
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := l.tokenRepo.GetByTokenHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return apperrors.NewInternalError("查询刷新令牌失败: " + err.Error())
+	}
+	// 不属于当前用户的刷新令牌直接忽略，不暴露令牌是否存在
+	if token == nil || token.UserID != userID {
+		return nil
+	}
+	return l.revokeFamily(ctx, token.FamilyID)
+}
+
+// RevokeAllSessions 吊销用户的所有登录（改密、删号、下线所有设备），返回被吊销的登录数
+func (l *TokenLogic) RevokeAllSessions(ctx context.Context, userID uint64) (int, error) {
+	now := time.Now()
+	tokens, err := l.tokenRepo.RevokeByUser(ctx, userID, now)
+	if err != nil {
+		return 0, apperrors.NewInternalError("吊销刷新令牌失败: " + err.Error())
+	}
+	if l.denylist != nil {
+		if err := l.denylist.RevokeUser(ctx, userID, now, l.accessTTL); err != nil {
+			return 0, apperrors.NewInternalError("吊销访问令牌失败: " + err.Error())
+		}
+	}
+	// 与吊销时间同一秒内签发的访问令牌不受按用户吊销约束，逐个按 jti 吊销
+	l.denyAccessTokens(ctx, tokens)
+	l.sessions.EndAll(ctx, userID)
+
+	families := make(map[string]struct{}, len(tokens))
+	for _, t := range tokens {
+		if t.UsedAt == nil {
+			families[t.FamilyID] = struct{}{}
+		}
+	}
+	return len(families), nil
+}
+
+// RevokeSession 下线用户的一个登录会话（吊销对应的令牌族），operatorID 为管理员时表示后台强制下线
+func (l *TokenLogic) RevokeSession(ctx context.Context, userID, sessionID, operatorID uint64, ip string) error {
+	if l.sessions == nil {
+		return apperrors.NewInternalError("未启用登录会话记录")
+	}
+	session, err := l.sessions.get(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	if err := l.revokeFamily(ctx, session.FamilyID); err != nil {
+		return err
+	}
+	l.sessions.guard.audit(ctx, &model.SecurityLog{
+		UserID:     userID,
+		Event:      model.SecurityEventSessionRevoked,
+		OperatorID: operatorID,
+		ClientIP:   ip,
+		Detail:     sessionSummary(session),
+	})
+	return nil
+}
+
+// revokeReusedFamily 已轮换的刷新令牌被再次使用，吊销整族
+func (l *TokenLogic) revokeReusedFamily(ctx context.Context, token *model.RefreshToken) {
+	logx.WithContext(ctx).Infof("检测到刷新令牌重放，吊销令牌族: user_id=%d family_id=%s", token.UserID, token.FamilyID)
+	if err := l.revokeFamily(ctx, token.FamilyID); err != nil {
+		logx.WithContext(ctx).Errorf("吊销令牌族失败: %v", err)
+	}
+}
+
+// revokeFamily 吊销令牌族，并把其中仍有效的访问令牌加入黑名单
+func (l *TokenLogic) revokeFamily(ctx context.Context, familyID string) error {
+	tokens, err := l.tokenRepo.RevokeFamily(ctx, familyID, time.Now())
+	if err != nil {
+		return apperrors.NewInternalError("吊销刷新令牌失败: " + err.Error())
+	}
+	l.denyAccessTokens(ctx, tokens)
+	l.sessions.EndFamily(ctx, familyID)
+	return nil
+}
+
+// denyAccessTokens 把刷新令牌记录对应的未过期访问令牌加入黑名单
+func (l *TokenLogic) denyAccessTokens(ctx context.Context, tokens []*model.RefreshToken) {
+	if l.denylist == nil {
+		return
+	}
+	now := time.Now()
+	for _, t := range tokens {
+		if !t.AccessExpiresAt.After(now) {
+			continue
+		}
+		if err := l.denylist.RevokeToken(ctx, t.AccessJTI, t.AccessExpiresAt); err != nil {
+			logx.WithContext(ctx).Errorf("吊销访问令牌失败: jti=%s err=%v", t.AccessJTI, err)
+		}
+	}
+}
+
+// newRefreshToken 生成 256 位随机刷新令牌
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken 刷新令牌的 SHA-256，库中只保存哈希
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
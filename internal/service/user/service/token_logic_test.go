@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ecommerce-system/internal/pkg/constants"
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+// mockUserRepo 只实现 GetByID，其余方法调用时 panic
+type mockUserRepo struct {
+	repository.UserRepository
+	users map[uint64]*model.User
+}
+
+func (m *mockUserRepo) GetByID(ctx context.Context, id uint64) (*model.User, error) {
+	return m.users[id], nil
+}
+
+// memTokenRepo 内存版刷新令牌仓储
+type memTokenRepo struct {
+	tokens []*model.RefreshToken
+}
+
+var _ repository.RefreshTokenRepository = (*memTokenRepo)(nil)
+
+func (m *memTokenRepo) Create(ctx context.Context, token *model.RefreshToken) error {
+	token.ID = uint64(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *memTokenRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memTokenRepo) MarkUsed(ctx context.Context, id uint64, at time.Time) (bool, error) {
+	for _, t := range m.tokens {
+		if t.ID == id && t.UsedAt == nil && t.RevokedAt == nil {
+			t.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memTokenRepo) RevokeFamily(ctx context.Context, familyID string, at time.Time) ([]*model.RefreshToken, error) {
+	return m.revoke(func(t *model.RefreshToken) bool { return t.FamilyID == familyID }, at), nil
+}
+
+func (m *memTokenRepo) RevokeByUser(ctx context.Context, userID uint64, at time.Time) ([]*model.RefreshToken, error) {
+	return m.revoke(func(t *model.RefreshToken) bool { return t.UserID == userID }, at), nil
+}
+
+func (m *memTokenRepo) revoke(match func(*model.RefreshToken) bool, at time.Time) []*model.RefreshToken {
+	var revoked []*model.RefreshToken
+	for _, t := range m.tokens {
+		if match(t) && t.RevokedAt == nil {
+			t.RevokedAt = &at
+			revoked = append(revoked, t)
+		}
+	}
+	return revoked
+}
+
+func newTestTokenLogic() (*TokenLogic, *memTokenRepo, *model.User) {
+	user := &model.User{ID: 7, Username: "alice", Status: constants.UserStatusNormal}
+	repo := &memTokenRepo{}
+	logic := NewTokenLogic(&mockUserRepo{users: map[uint64]*model.User{user.ID: user}}, repo, nil, nil, nil, "test-secret", 900, 3600)
+	return logic, repo, user
+}
+
+func TestTokenLogic_RefreshRotates(t *testing.T) {
+	logic, _, user := newTestTokenLogic()
+	ctx := context.Background()
+
+	first, err := logic.IssueTokens(ctx, user, "")
+	if err != nil {
+		t.Fatalf("IssueTokens() error = %v", err)
+	}
+	claims, err := utils.ParseToken(first.AccessToken, "test-secret")
+	if err != nil || claims.ID == "" || claims.UserID != user.ID {
+		t.Fatalf("access token should carry jti and user id, claims=%+v err=%v", claims, err)
+	}
+
+	_, second, err := logic.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Fatal("refresh should rotate both tokens")
+	}
+	if _, _, err := logic.Refresh(ctx, second.RefreshToken); err != nil {
+		t.Fatalf("rotated refresh token should be usable, err = %v", err)
+	}
+}
+
+func TestTokenLogic_ReuseRevokesFamily(t *testing.T) {
+	logic, repo, user := newTestTokenLogic()
+	ctx := context.Background()
+
+	first, _ := logic.IssueTokens(ctx, user, "")
+	_, second, err := logic.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	// 旧令牌被重放：拒绝，并且整族（包括刚轮换出的新令牌）失效
+	_, _, err = logic.Refresh(ctx, first.RefreshToken)
+	if bizErr, ok := err.(*apperrors.BusinessError); !ok || bizErr.Code != apperrors.CodeTokenInvalid {
+		t.Fatalf("reused token should fail with CodeTokenInvalid, got %v", err)
+	}
+	if _, _, err := logic.Refresh(ctx, second.RefreshToken); err == nil {
+		t.Fatal("family should be revoked after reuse")
+	}
+	for _, tok := range repo.tokens {
+		if tok.RevokedAt == nil {
+			t.Fatalf("token %d not revoked", tok.ID)
+		}
+	}
+
+	// 其他登录不受影响
+	other, _ := logic.IssueTokens(ctx, user, "")
+	if _, _, err := logic.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Fatalf("other family should still work, err = %v", err)
+	}
+}
+
+func TestTokenLogic_RevokeAllSessions(t *testing.T) {
+	logic, _, user := newTestTokenLogic()
+	ctx := context.Background()
+
+	a, _ := logic.IssueTokens(ctx, user, "")
+	b, _ := logic.IssueTokens(ctx, user, "")
+	if _, _, err := logic.Refresh(ctx, a.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := logic.RevokeAllSessions(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("RevokeAllSessions() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("revoked sessions = %d, want 2", n)
+	}
+	if _, _, err := logic.Refresh(ctx, b.RefreshToken); err == nil {
+		t.Fatal("refresh token should be revoked")
+	}
+}
@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"time"
 
 	"ecommerce-system/internal/pkg/cache"
 	"ecommerce-system/internal/pkg/constants"
 	apperrors "ecommerce-system/internal/pkg/errors"
 	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/pkg/verifycode"
 	"ecommerce-system/internal/service/user/model"
 	"ecommerce-system/internal/service/user/repository"
 )
@@ -18,6 +21,10 @@ type UserLogic struct {
 	credentialRepo repository.CredentialRepository
 	addressRepo    repository.AddressRepository
 	cache          *cache.CacheOperations
+	tokens         *TokenLogic
+	verifyCodes    *VerifyCodeLogic
+	guard          *LoginGuardLogic
+	mfa            *MFALogic
 }
 
 // NewUserLogic 创建用户业务逻辑
@@ -26,12 +33,20 @@ func NewUserLogic(
 	credentialRepo repository.CredentialRepository,
 	addressRepo repository.AddressRepository,
 	cache *cache.CacheOperations,
+	tokens *TokenLogic,
+	verifyCodes *VerifyCodeLogic,
+	guard *LoginGuardLogic,
+	mfa *MFALogic,
 ) *UserLogic {
 	return &UserLogic{
 		userRepo:       userRepo,
 		credentialRepo: credentialRepo,
 		addressRepo:    addressRepo,
 		cache:          cache,
+		tokens:         tokens,
+		verifyCodes:    verifyCodes,
+		guard:          guard,
+		mfa:            mfa,
 	}
 }
 
@@ -57,8 +72,8 @@ func (l *UserLogic) Register(ctx context.Context, req *RegisterRequest) (*Regist
 	if req.Username == "" {
 		return nil, apperrors.NewInvalidParamError("用户名不能为空")
 	}
-	if req.Password == "" || len(req.Password) < 6 {
-		return nil, apperrors.NewInvalidParamError("密码长度至少6位")
+	if err := utils.ValidatePassword(req.Password, req.Username); err != nil {
+		return nil, apperrors.NewInvalidParamError(err.Error())
 	}
 
 	// 2. 检查用户名是否已存在
@@ -92,13 +107,18 @@ func (l *UserLogic) Register(ctx context.Context, req *RegisterRequest) (*Regist
 		}
 	}
 
-	// 5. 密码加密
+	// 5. 校验手机号/邮箱验证码（优先手机号）
+	if err := l.verifyCodes.ConsumeRegisterCode(ctx, req.Phone, req.Email, req.VerifyCode); err != nil {
+		return nil, err
+	}
+
+	// 6. 密码加密
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		return nil, apperrors.NewInternalError("密码加密失败: " + err.Error())
 	}
 
-	// 6. 创建用户
+	// 7. 创建用户
 	now := time.Now()
 	user := &model.User{
 		Username:    req.Username,
@@ -121,7 +141,7 @@ func (l *UserLogic) Register(ctx context.Context, req *RegisterRequest) (*Regist
 		return nil, apperrors.NewInternalError("创建用户失败: " + err.Error())
 	}
 
-	// 7. 创建密码凭证
+	// 8. 创建密码凭证
 	credential := &model.Credential{
 		UserID:          user.ID,
 		CredentialType:  1, // 1-密码
@@ -145,38 +165,45 @@ func (l *UserLogic) Register(ctx context.Context, req *RegisterRequest) (*Regist
 	}, nil
 }
 
+// LoginTypePhoneCode 手机号 + 短信验证码登录
+const LoginTypePhoneCode = 4
+
+// errLoginFailed 账号不存在、没有密码凭证、密码错误统一返回，避免探测账号是否存在
+var errLoginFailed = apperrors.NewError(apperrors.CodePasswordError, "用户名或密码错误")
+
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username   string // 用户名/手机号/邮箱
-	Password   string
-	LoginType  int    // 1-用户名, 2-手机号, 3-邮箱
-	VerifyCode string // 验证码
+	Username     string // 用户名/手机号/邮箱
+	Password     string
+	LoginType    int    // 1-用户名, 2-手机号, 3-邮箱, 4-手机号+短信验证码
+	VerifyCode   string // 短信验证码（LoginType=4）
+	CaptchaToken string // 人机验证 token（失败次数过多后必填）
+	ClientIP     string
 }
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	UserID     uint64
-	Username   string
-	User       *model.User
-	Token      string
-	ExpireTime int64
+	UserID   uint64
+	Username string
+	User     *model.User
+	Tokens   *TokenPair
+	MFA      *MFAChallenge // 需要两步验证时不签发令牌，返回挑战
 }
 
 // Login 用户登录
-func (l *UserLogic) Login(ctx context.Context, req *LoginRequest, jwtSecret string, jwtExpire int64) (*LoginResponse, error) {
+func (l *UserLogic) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
 	// 1. 参数验证
+	req.Username = strings.TrimSpace(req.Username)
 	if req.Username == "" {
 		return nil, apperrors.NewInvalidParamError("用户名不能为空")
 	}
-	if req.Password == "" {
+	if req.LoginType == LoginTypePhoneCode {
+		if req.VerifyCode == "" {
+			return nil, apperrors.NewInvalidParamError("验证码不能为空")
+		}
+	} else if req.Password == "" {
 		return nil, apperrors.NewInvalidParamError("密码不能为空")
 	}
-	// 验证码验证（前端已做基础验证，这里可以进一步验证或记录）
-	// 注意：验证码可以为空（如果前端没有发送），但如果有值则应该验证
-	if req.VerifyCode != "" {
-		// 这里可以添加后端验证码验证逻辑（如 Redis 缓存验证）
-		// 目前前端已经做了基础验证，这里暂时只做非空检查
-	}
 
 	// 2. 根据登录类型查找用户
 	var user *model.User
@@ -185,7 +212,7 @@ func (l *UserLogic) Login(ctx context.Context, req *LoginRequest, jwtSecret stri
 	switch req.LoginType {
 	case 1: // 用户名登录
 		user, err = l.userRepo.GetByUsername(ctx, req.Username)
-	case 2: // 手机号登录
+	case 2, LoginTypePhoneCode: // 手机号登录
 		user, err = l.userRepo.GetByPhone(ctx, req.Username)
 	case 3: // 邮箱登录
 		user, err = l.userRepo.GetByEmail(ctx, req.Username)
@@ -196,60 +223,103 @@ func (l *UserLogic) Login(ctx context.Context, req *LoginRequest, jwtSecret stri
 	if err != nil {
 		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
 	}
+
+	// 3. 防暴力破解：锁定、递增等待、人机验证（账号不存在时同样计数）
+	account := loginAccount(user, req.Username)
+	if err := l.guard.Check(ctx, account, req.ClientIP, req.CaptchaToken); err != nil {
+		return nil, err
+	}
+
+	// 4. 校验验证码或密码
+	if req.LoginType == LoginTypePhoneCode {
+		// 未注册的手机号不会收到验证码，这里统一按验证码错误处理
+		if err := l.verifyCodes.ConsumeVerifyCode(ctx, verifycode.SceneLogin, req.Username, req.VerifyCode); err != nil {
+			l.guard.Fail(ctx, user, account, req.ClientIP)
+			return nil, err
+		}
+	} else if err := l.checkPassword(ctx, user, req.Password); err != nil {
+		if err == errLoginFailed {
+			l.guard.Fail(ctx, user, account, req.ClientIP)
+		}
+		return nil, err
+	}
 	if user == nil {
-		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+		return nil, errLoginFailed
 	}
+	l.guard.Succeed(ctx, account)
 
-	// 3. 检查用户状态
+	// 5. 检查用户状态
 	if user.Status != constants.UserStatusNormal {
 		return nil, apperrors.NewError(apperrors.CodeForbidden, "用户已被禁用")
 	}
 
-	// 4. 验证密码
-	credential, err := l.credentialRepo.GetByUserIDAndType(ctx, user.ID, 1) // 1-密码
+	// 6. 需要两步验证时返回挑战，由 VerifyMFA 完成登录
+	challenge, err := l.mfa.Challenge(ctx, user)
 	if err != nil {
-		return nil, apperrors.NewInternalError("查询凭证失败: " + err.Error())
+		return nil, err
 	}
-	if credential == nil {
-		return nil, apperrors.NewError(apperrors.CodePasswordError, "密码凭证不存在")
+	if challenge != nil {
+		return &LoginResponse{
+			UserID:   user.ID,
+			Username: user.Username,
+			User:     user,
+			MFA:      challenge,
+		}, nil
 	}
 
-	if !utils.CheckPassword(req.Password, credential.CredentialValue) {
-		return nil, apperrors.NewError(apperrors.CodePasswordError, "密码错误")
-	}
-
-	// 5. 生成JWT Token
-	token, err := utils.GenerateToken(user.ID, user.Username, jwtSecret, jwtExpire)
+	// 7. 签发访问令牌和刷新令牌（新登录开启新的令牌族）
+	tokens, err := l.tokens.IssueTokens(ctx, user, "")
 	if err != nil {
-		return nil, apperrors.NewInternalError("生成Token失败: " + err.Error())
+		return nil, err
 	}
 
-	expireTime := time.Now().Add(time.Duration(jwtExpire) * time.Second).Unix()
-
-	// 缓存用户会话信息
 	if l.cache != nil {
-		sessionKey := cache.BuildKey(cache.KeyPrefixUserSession, token)
-		sessionData := map[string]interface{}{
-			"user_id":   user.ID,
-			"username":  user.Username,
-			"expire_at": expireTime,
-		}
-		_ = l.cache.Set(ctx, sessionKey, sessionData, time.Duration(jwtExpire)*time.Second)
-
 		// 缓存用户信息
 		userKey := cache.BuildKey(cache.KeyPrefixUserInfo, user.ID)
 		_ = l.cache.Set(ctx, userKey, user, 30*time.Minute)
 	}
 
 	return &LoginResponse{
-		UserID:     user.ID,
-		Username:   user.Username,
-		User:       user,
-		Token:      token,
-		ExpireTime: expireTime,
+		UserID:   user.ID,
+		Username: user.Username,
+		User:     user,
+		Tokens:   tokens,
 	}, nil
 }
 
+// checkPassword 校验密码，账号不存在时也做一次哈希比较，响应时间不暴露账号是否存在
+func (l *UserLogic) checkPassword(ctx context.Context, user *model.User, password string) error {
+	if user == nil {
+		utils.CheckPassword(password, dummyPasswordHash())
+		return errLoginFailed
+	}
+	credential, err := l.credentialRepo.GetByUserIDAndType(ctx, user.ID, 1) // 1-密码
+	if err != nil {
+		return apperrors.NewInternalError("查询凭证失败: " + err.Error())
+	}
+	if credential == nil {
+		utils.CheckPassword(password, dummyPasswordHash())
+		return errLoginFailed
+	}
+	if !utils.CheckPassword(password, credential.CredentialValue) {
+		return errLoginFailed
+	}
+	return nil
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// dummyPasswordHash 用于账号不存在时的等时比较
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = utils.HashPassword("dummy-password-for-timing")
+	})
+	return dummyHash
+}
+
 // GetUserInfoRequest 获取用户信息请求
 type GetUserInfoRequest struct {
 	UserID uint64
@@ -369,6 +439,48 @@ func (l *UserLogic) UpdateUserInfo(ctx context.Context, req *UpdateUserInfoReque
 	}, nil
 }
 
+// ChangePhone 更换手机号，新手机号需要短信验证码
+func (l *UserLogic) ChangePhone(ctx context.Context, userID uint64, phone, verifyCode string) (*model.User, error) {
+	phone = strings.TrimSpace(phone)
+	if userID == 0 {
+		return nil, apperrors.NewInvalidParamError("用户ID不能为空")
+	}
+	if !phonePattern.MatchString(phone) {
+		return nil, apperrors.NewInvalidParamError("请输入正确的手机号")
+	}
+
+	user, err := l.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if user == nil {
+		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
+	}
+	if user.Phone == phone {
+		return user, nil
+	}
+	existing, err := l.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	if existing != nil {
+		return nil, apperrors.NewError(apperrors.CodeUserAlreadyExists, "手机号已被注册")
+	}
+	if err := l.verifyCodes.ConsumeVerifyCode(ctx, verifycode.SceneChangePhone, phone, verifyCode); err != nil {
+		return nil, err
+	}
+
+	user.Phone = phone
+	user.UpdatedAt = time.Now()
+	if err := l.userRepo.Update(ctx, user); err != nil {
+		return nil, apperrors.NewInternalError("更换手机号失败: " + err.Error())
+	}
+	if l.cache != nil {
+		_ = l.cache.Delete(ctx, cache.BuildKey(cache.KeyPrefixUserInfo, userID))
+	}
+	return user, nil
+}
+
 // ListUsersRequest 用户列表请求
 type ListUsersRequest struct {
 	Page     int
@@ -414,39 +526,3 @@ func (l *UserLogic) ListUsers(ctx context.Context, req *ListUsersRequest) (*List
 		PageSize: req.PageSize,
 	}, nil
 }
-
-// DeleteUserRequest 删除用户请求
-type DeleteUserRequest struct {
-	UserID uint64
-}
-
-// DeleteUserResponse 删除用户响应
-type DeleteUserResponse struct {
-}
-
-// DeleteUser 删除用户（管理后台）
-func (l *UserLogic) DeleteUser(ctx context.Context, req *DeleteUserRequest) (*DeleteUserResponse, error) {
-	if l.userRepo == nil {
-		return nil, apperrors.NewInternalError("数据库连接未初始化")
-	}
-
-	if req.UserID == 0 {
-		return nil, apperrors.NewInvalidParamError("用户ID不能为空")
-	}
-
-	// 检查用户是否存在
-	user, err := l.userRepo.GetByID(ctx, req.UserID)
-	if err != nil {
-		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
-	}
-	if user == nil {
-		return nil, apperrors.NewError(apperrors.CodeUserNotFound, "用户不存在")
-	}
-
-	// 删除用户（软删除）
-	if err := l.userRepo.Delete(ctx, req.UserID); err != nil {
-		return nil, apperrors.NewInternalError("删除用户失败: " + err.Error())
-	}
-
-	return &DeleteUserResponse{}, nil
-}
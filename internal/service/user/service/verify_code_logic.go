@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/sender"
+	"ecommerce-system/internal/pkg/verifycode"
+	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
+)
+
+var (
+	phonePattern = regexp.MustCompile(`^1[3-9]\d{9}$`)
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// VerifyCodeLogic 短信/邮件验证码。
+// 登录和重置密码场景下目标未注册时不发送但同样返回成功，避免通过发送接口探测账号是否存在。
+type VerifyCodeLogic struct {
+	store    *verifycode.Store
+	sender   sender.Sender
+	userRepo repository.UserRepository
+	// requireOnRegister 注册时填写了手机号/邮箱必须带验证码
+	requireOnRegister bool
+}
+
+// NewVerifyCodeLogic 创建验证码业务逻辑
+func NewVerifyCodeLogic(store *verifycode.Store, s sender.Sender, userRepo repository.UserRepository, requireOnRegister bool) *VerifyCodeLogic {
+	return &VerifyCodeLogic{
+		store:             store,
+		sender:            s,
+		userRepo:          userRepo,
+		requireOnRegister: requireOnRegister,
+	}
+}
+
+// SendVerifyCodeRequest 发送验证码请求
+type SendVerifyCodeRequest struct {
+	Scene    int
+	Target   string // 手机号或邮箱
+	ClientIP string
+}
+
+// SendVerifyCode 发送验证码，返回距下次可发送的间隔（秒）
+func (l *VerifyCodeLogic) SendVerifyCode(ctx context.Context, req *SendVerifyCodeRequest) (int64, error) {
+	target, channel, err := normalizeTarget(req.Target)
+	if err != nil {
+		return 0, err
+	}
+
+	// 按场景检查目标
+	var user *model.User
+	switch req.Scene {
+	case verifycode.SceneRegister, verifycode.SceneChangePhone:
+		if req.Scene == verifycode.SceneChangePhone && channel != sender.ChannelSMS {
+			return 0, apperrors.NewInvalidParamError("请输入正确的手机号")
+		}
+		user, err = l.findByTarget(ctx, target, channel)
+		if err != nil {
+			return 0, err
+		}
+		if user != nil {
+			return 0, apperrors.NewError(apperrors.CodeUserAlreadyExists, targetName(channel)+"已被注册")
+		}
+	case verifycode.SceneLogin, verifycode.SceneResetPassword:
+		if req.Scene == verifycode.SceneLogin && channel != sender.ChannelSMS {
+			return 0, apperrors.NewInvalidParamError("验证码登录仅支持手机号")
+		}
+		user, err = l.findByTarget(ctx, target, channel)
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, apperrors.NewInvalidParamError("验证码场景错误")
+	}
+
+	code, err := l.store.Issue(ctx, req.Scene, target, req.ClientIP)
+	if err != nil {
+		return 0, convertIssueError(err)
+	}
+	interval := int64(l.store.SendInterval().Seconds())
+
+	if (req.Scene == verifycode.SceneLogin || req.Scene == verifycode.SceneResetPassword) && user == nil {
+		logx.WithContext(ctx).Infof("验证码目标未注册，跳过发送: scene=%d target=%s", req.Scene, target)
+		_ = l.store.Discard(ctx, req.Scene, target)
+		return interval, nil
+	}
+
+	msg := &sender.Message{
+		Channel: channel,
+		To:      target,
+		Subject: "验证码",
+		Content: fmt.Sprintf("您的验证码是 %s，%d 分钟内有效。如非本人操作，请忽略。", code, int(l.store.TTL().Minutes())),
+	}
+	if err := l.sender.Send(ctx, msg); err != nil {
+		_ = l.store.Discard(ctx, req.Scene, target)
+		return 0, apperrors.NewInternalError("发送验证码失败: " + err.Error())
+	}
+	return interval, nil
+}
+
+// CheckVerifyCode 校验验证码但不作废（分步表单提前校验），最终提交时用 ConsumeVerifyCode
+func (l *VerifyCodeLogic) CheckVerifyCode(ctx context.Context, scene int, target, code string) error {
+	return l.verify(ctx, scene, target, code, false)
+}
+
+// ConsumeVerifyCode 校验验证码，通过后立即作废
+func (l *VerifyCodeLogic) ConsumeVerifyCode(ctx context.Context, scene int, target, code string) error {
+	return l.verify(ctx, scene, target, code, true)
+}
+
+// ConsumeRegisterCode 注册时校验手机号（没有手机号时校验邮箱）的验证码。
+// 只用用户名注册时不需要验证码；未开启 requireOnRegister 时不带验证码也允许注册。
+func (l *VerifyCodeLogic) ConsumeRegisterCode(ctx context.Context, phone, email, code string) error {
+	target := phone
+	if target == "" {
+		target = email
+	}
+	if target == "" {
+		return nil
+	}
+	if code == "" {
+		if l.requireOnRegister {
+			return apperrors.NewError(apperrors.CodeVerifyCodeError, "请输入验证码")
+		}
+		return nil
+	}
+	return l.ConsumeVerifyCode(ctx, verifycode.SceneRegister, target, code)
+}
+
+// verify 校验验证码
+func (l *VerifyCodeLogic) verify(ctx context.Context, scene int, target, code string, consume bool) error {
+	target, _, err := normalizeTarget(target)
+	if err != nil {
+		return err
+	}
+	if code == "" {
+		return apperrors.NewInvalidParamError("验证码不能为空")
+	}
+
+	err = l.store.Verify(ctx, scene, target, code, consume)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, verifycode.ErrNotFound):
+		return apperrors.NewError(apperrors.CodeVerifyCodeError, "验证码已过期，请重新获取")
+	case errors.Is(err, verifycode.ErrTooManyAttempts):
+		return apperrors.NewError(apperrors.CodeVerifyCodeError, "验证码错误次数过多，请重新获取")
+	case errors.Is(err, verifycode.ErrMismatch):
+		return apperrors.NewError(apperrors.CodeVerifyCodeError, "验证码错误")
+	default:
+		return apperrors.NewInternalError("校验验证码失败: " + err.Error())
+	}
+}
+
+// findByTarget 按手机号或邮箱查找用户
+func (l *VerifyCodeLogic) findByTarget(ctx context.Context, target, channel string) (*model.User, error) {
+	var user *model.User
+	var err error
+	if channel == sender.ChannelSMS {
+		user, err = l.userRepo.GetByPhone(ctx, target)
+	} else {
+		user, err = l.userRepo.GetByEmail(ctx, target)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternalError("查询用户失败: " + err.Error())
+	}
+	return user, nil
+}
+
+// normalizeTarget 规范化手机号/邮箱并识别发送通道
+func normalizeTarget(target string) (string, string, error) {
+	target = strings.TrimSpace(target)
+	switch {
+	case phonePattern.MatchString(target):
+		return target, sender.ChannelSMS, nil
+	case emailPattern.MatchString(target):
+		return strings.ToLower(target), sender.ChannelEmail, nil
+	default:
+		return "", "", apperrors.NewInvalidParamError("请输入正确的手机号或邮箱")
+	}
+}
+
+// targetName 通道对应的目标名称
+func targetName(channel string) string {
+	if channel == sender.ChannelSMS {
+		return "手机号"
+	}
+	return "邮箱"
+}
+
+// convertIssueError 转换发送限流错误
+func convertIssueError(err error) error {
+	switch {
+	case errors.Is(err, verifycode.ErrTooFrequent):
+		return apperrors.NewError(apperrors.CodeTooManyRequests, "验证码发送过于频繁，请稍后再试")
+	case errors.Is(err, verifycode.ErrTargetLimited):
+		return apperrors.NewError(apperrors.CodeTooManyRequests, "今日验证码发送次数已达上限")
+	case errors.Is(err, verifycode.ErrIPLimited):
+		return apperrors.NewError(apperrors.CodeTooManyRequests, "当前网络发送验证码次数过多，请稍后再试")
+	default:
+		return apperrors.NewInternalError("生成验证码失败: " + err.Error())
+	}
+}
@@ -1,25 +1,67 @@
 package user
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
 
 	v1 "ecommerce-system/api/user/v1"
+	"ecommerce-system/internal/pkg/apikey"
 	"ecommerce-system/internal/pkg/cache"
+	"ecommerce-system/internal/pkg/captcha"
+	"ecommerce-system/internal/pkg/client"
 	"ecommerce-system/internal/pkg/database"
+	"ecommerce-system/internal/pkg/geoip"
+	"ecommerce-system/internal/pkg/idgen"
+	"ecommerce-system/internal/pkg/loginguard"
+	"ecommerce-system/internal/pkg/membership"
+	"ecommerce-system/internal/pkg/mq"
+	"ecommerce-system/internal/pkg/oidc"
+	"ecommerce-system/internal/pkg/region"
+	"ecommerce-system/internal/pkg/revocation"
+	"ecommerce-system/internal/pkg/sender"
+	"ecommerce-system/internal/pkg/verifycode"
 	"ecommerce-system/internal/service/user/repository"
 	userservice "ecommerce-system/internal/service/user/service"
 )
 
 // ServiceContext 服务上下文
 type ServiceContext struct {
-	Config         Config
-	DB             *gorm.DB
-	Redis          *redis.Client
-	Cache          *cache.CacheOperations
-	UserRepo       repository.UserRepository
-	CredentialRepo repository.CredentialRepository
-	AddressRepo    repository.AddressRepository
+	Config          Config
+	DB              *gorm.DB
+	Redis           *redis.Client
+	Cache           *cache.CacheOperations
+	UserRepo        repository.UserRepository
+	CredentialRepo  repository.CredentialRepository
+	AddressRepo     repository.AddressRepository
+	ApiKeyRepo      repository.ApiKeyRepository
+	ApiKeyCipher    *apikey.Cipher
+	TokenRepo       repository.RefreshTokenRepository
+	Denylist        *revocation.Denylist
+	RoleRepo        repository.RoleRepository
+	VerifyCodes     *verifycode.Store
+	Sender          sender.Sender
+	SecurityLogRepo repository.SecurityLogRepository
+	LoginGuard      *loginguard.Guard
+	Captcha         captcha.Verifier
+	OAuthProviders  map[string]*oidc.Provider
+	MFACipher       *apikey.Cipher
+	MessageClient   *client.MessageClient // 为 nil 时不发站内信
+	MemberRepo      repository.MemberRepository
+	MemberTiers     []membership.Tier // 已校验排序的会员等级定义
+	DataRequestRepo repository.DataRequestRepository
+	PersonalRepo    repository.PersonalDataRepository
+	IDGen           *idgen.Generator
+	// DataSubjects 持有个人数据的业务服务客户端，key 为服务名
+	DataSubjects map[string]*client.DataSubjectClient
+	FileClient   *client.FileClient // 为 nil 时不能导出个人数据
+	Regions      *region.Dataset    // 行政区划数据，用于地址校验和省市区级联
+	SessionRepo  repository.SessionRepository
+	GeoIP        *geoip.Reader // 为 nil 时登录会话不显示所在地
 }
 
 // NewServiceContext 创建服务上下文。DB/Redis 初始化失败直接 Fatal，不静默放行。
@@ -46,6 +88,75 @@ func NewServiceContext(c Config) *ServiceContext {
 		MinIdleConns: c.BizRedis.MinIdleConns,
 	})
 
+	// API Key secret 加密器：未配置主密钥时为 nil，签发/验签接口返回错误
+	var apiKeyCipher *apikey.Cipher
+	if c.ApiKey.EncryptionKey != "" {
+		cipher, err := apikey.NewCipher(c.ApiKey.EncryptionKey)
+		logx.Must(err)
+		apiKeyCipher = cipher
+	}
+
+	// 验证码发送通道：短信和邮件分别配置，开发环境写入日志文件
+	smsSender, err := sender.New(c.VerifyCode.SMS)
+	logx.Must(err)
+	emailSender, err := sender.New(c.VerifyCode.Email)
+	logx.Must(err)
+
+	// 人机验证：开发环境使用 stub，token 等于配置的答案即通过
+	verifier, err := captcha.New(c.Captcha)
+	logx.Must(err)
+
+	// 第三方登录提供方：发现文档在首次使用时加载，提供方不可用不影响启动
+	oauthProviders, err := oidc.NewProviders(c.OAuth.Providers)
+	logx.Must(err)
+
+	// TOTP 密钥加密器：要求角色开启两步验证时必须配置主密钥
+	var mfaCipher *apikey.Cipher
+	if c.MFA.EncryptionKey != "" {
+		mfaCipher, err = apikey.NewCipher(c.MFA.EncryptionKey)
+		logx.Must(err)
+	} else if len(c.MFA.RequiredRoles) > 0 {
+		logx.Must(errors.New("MFA.RequiredRoles 已配置但缺少 MFA.EncryptionKey"))
+	}
+
+	// 消息服务客户端（endpoint 为空则跳过，方便单独启动调试）
+	var messageClient *client.MessageClient
+	if c.MessageRpc.Endpoint != "" {
+		messageClient, err = client.NewMessageClient(c.MessageRpc)
+		logx.Must(err)
+	}
+
+	// 会员等级定义：配置错误（缺少 0 级、门槛倒挂等）直接 Fatal
+	memberTiers, err := membership.Normalize(c.Membership.Tiers)
+	logx.Must(err)
+
+	// 个人数据请求参与方和导出包存储
+	dataSubjects := make(map[string]*client.DataSubjectClient, len(c.DataSubject.Services))
+	for _, svc := range c.DataSubject.Services {
+		dsc, err := client.NewDataSubjectClient(client.RpcConf{Endpoint: svc.Endpoint, Timeout: svc.Timeout}, c.JWT.Secret)
+		logx.Must(err)
+		dataSubjects[svc.Name] = dsc
+	}
+	var fileClient *client.FileClient
+	if c.DataSubject.FileRpc.Endpoint != "" {
+		fileClient, err = client.NewFileClient(c.DataSubject.FileRpc)
+		logx.Must(err)
+	}
+
+	// 行政区划：配置了数据文件时加载文件（区划调整后不用发版），否则用内置数据
+	regions := region.Default()
+	if c.RegionDataFile != "" {
+		regions, err = region.LoadFile(c.RegionDataFile)
+		logx.Must(err)
+	}
+
+	// IP 地址库：只在本地查询，不调用外部接口
+	var geoReader *geoip.Reader
+	if c.GeoIPFile != "" {
+		geoReader, err = geoip.Open(c.GeoIPFile)
+		logx.Must(err)
+	}
+
 	return &ServiceContext{
 		Config:         c,
 		DB:             db,
@@ -54,6 +165,32 @@ func NewServiceContext(c Config) *ServiceContext {
 		UserRepo:       repository.NewUserRepository(db),
 		CredentialRepo: repository.NewCredentialRepository(db),
 		AddressRepo:    repository.NewAddressRepository(db),
+		ApiKeyRepo:     repository.NewApiKeyRepository(db),
+		ApiKeyCipher:   apiKeyCipher,
+		TokenRepo:      repository.NewRefreshTokenRepository(db),
+		Denylist:       revocation.NewDenylist(rdb),
+		RoleRepo:       repository.NewRoleRepository(db),
+		VerifyCodes:    verifycode.NewStore(rdb, c.VerifyCode.Config),
+		Sender: sender.ChannelSender{
+			sender.ChannelSMS:   smsSender,
+			sender.ChannelEmail: emailSender,
+		},
+		SecurityLogRepo: repository.NewSecurityLogRepository(db),
+		LoginGuard:      loginguard.NewGuard(rdb, c.LoginGuard),
+		Captcha:         verifier,
+		OAuthProviders:  oauthProviders,
+		MFACipher:       mfaCipher,
+		MessageClient:   messageClient,
+		MemberRepo:      repository.NewMemberRepository(db),
+		MemberTiers:     memberTiers,
+		DataRequestRepo: repository.NewDataRequestRepository(db),
+		PersonalRepo:    repository.NewPersonalDataRepository(db),
+		IDGen:           idgen.New(rdb),
+		DataSubjects:    dataSubjects,
+		FileClient:      fileClient,
+		Regions:         regions,
+		SessionRepo:     repository.NewSessionRepository(db),
+		GeoIP:           geoReader,
 	}
 }
 
@@ -63,13 +200,142 @@ type UserService struct {
 	svcCtx       *ServiceContext
 	logic        *userservice.UserLogic
 	addressLogic *userservice.AddressLogic
+	tokenLogic   *userservice.TokenLogic
+	// verifyCodeLogic 短信/邮件验证码
+	verifyCodeLogic *userservice.VerifyCodeLogic
+	// guardLogic 登录防暴力破解
+	guardLogic *userservice.LoginGuardLogic
+	// oauthLogic 第三方登录
+	oauthLogic *userservice.OAuthLogic
+	// mfaLogic 两步验证
+	mfaLogic *userservice.MFALogic
+	// passwordLogic 找回密码、修改密码
+	passwordLogic *userservice.PasswordLogic
+	// memberLogic 会员等级和权益
+	memberLogic *userservice.MemberLogic
+	// dataRequestLogic 个人数据导出和账号注销
+	dataRequestLogic *userservice.DataRequestLogic
+	// sessionLogic 登录会话（登录设备）
+	sessionLogic *userservice.SessionLogic
 }
 
 // NewUserService 创建用户服务
 func NewUserService(svcCtx *ServiceContext) *UserService {
+	// 获取 JWT 配置
+	jwtSecret := svcCtx.Config.JWT.Secret
+	if jwtSecret == "" {
+		jwtSecret = "default-secret-key" // 开发环境默认值
+	}
+	guardLogic := userservice.NewLoginGuardLogic(svcCtx.LoginGuard, svcCtx.Captcha, svcCtx.UserRepo, svcCtx.SecurityLogRepo)
+
+	// 接口变量不能直接赋 nil 指针，未配置消息服务时保持 nil
+	var inbox userservice.Inbox
+	if svcCtx.MessageClient != nil {
+		inbox = svcCtx.MessageClient
+	}
+	notifier := userservice.NewNotifier(svcCtx.Sender, inbox)
+
+	sessionLogic := userservice.NewSessionLogic(svcCtx.SessionRepo, svcCtx.GeoIP, notifier, guardLogic)
+	tokenLogic := userservice.NewTokenLogic(svcCtx.UserRepo, svcCtx.TokenRepo, svcCtx.RoleRepo, svcCtx.Denylist,
+		sessionLogic, jwtSecret, accessExpire(svcCtx.Config), svcCtx.Config.JWT.RefreshExpire)
+
+	verifyCodeLogic := userservice.NewVerifyCodeLogic(svcCtx.VerifyCodes, svcCtx.Sender, svcCtx.UserRepo,
+		svcCtx.Config.VerifyCode.RequireOnRegister)
+	mfaLogic := userservice.NewMFALogic(svcCtx.CredentialRepo, svcCtx.UserRepo, svcCtx.RoleRepo, svcCtx.MFACipher,
+		svcCtx.Redis, tokenLogic, guardLogic, userservice.MFAPolicy{
+			Issuer:        svcCtx.Config.MFA.Issuer,
+			RequiredRoles: svcCtx.Config.MFA.RequiredRoles,
+			ChallengeTTL:  time.Duration(svcCtx.Config.MFA.ChallengeTTL) * time.Second,
+			MaxAttempts:   svcCtx.Config.MFA.MaxAttempts,
+		})
+
+	passwordLogic := userservice.NewPasswordLogic(svcCtx.UserRepo, svcCtx.CredentialRepo, svcCtx.Redis, svcCtx.Sender,
+		notifier, tokenLogic, guardLogic, mfaLogic, userservice.PasswordResetPolicy{
+			TokenTTL:       time.Duration(svcCtx.Config.PasswordReset.TokenTTL) * time.Second,
+			ResendInterval: time.Duration(svcCtx.Config.PasswordReset.ResendInterval) * time.Second,
+			ResetURL:       svcCtx.Config.PasswordReset.ResetURL,
+		})
+
+	memberLogic := userservice.NewMemberLogic(svcCtx.MemberRepo, svcCtx.UserRepo, svcCtx.Cache, svcCtx.Redis,
+		notifier, userservice.MemberPolicy{
+			Window: time.Duration(svcCtx.Config.Membership.WindowDays) * 24 * time.Hour,
+			Tiers:  svcCtx.MemberTiers,
+		})
+	startMembershipWorkers(svcCtx.Config, memberLogic)
+
+	participants := make(map[string]userservice.DataParticipant, len(svcCtx.DataSubjects))
+	for name, c := range svcCtx.DataSubjects {
+		participants[name] = c
+	}
+	var archive userservice.ArchiveStore
+	if svcCtx.FileClient != nil {
+		archive = svcCtx.FileClient
+	}
+	dataRequestLogic := userservice.NewDataRequestLogic(svcCtx.DataRequestRepo, svcCtx.PersonalRepo, svcCtx.UserRepo,
+		svcCtx.CredentialRepo, svcCtx.Cache, svcCtx.Redis, svcCtx.IDGen, participants, archive, notifier,
+		tokenLogic, guardLogic, mfaLogic, userservice.DataRequestPolicy{
+			Category:       svcCtx.Config.DataSubject.FileCategory,
+			DownloadTTL:    time.Duration(svcCtx.Config.DataSubject.DownloadTTL) * time.Second,
+			ExportInterval: time.Duration(svcCtx.Config.DataSubject.ExportInterval) * time.Second,
+		})
+	// 服务重启前未处理完的导出/注销请求继续处理
+	go dataRequestLogic.Resume(context.Background())
+
 	return &UserService{
-		svcCtx:       svcCtx,
-		logic:        userservice.NewUserLogic(svcCtx.UserRepo, svcCtx.CredentialRepo, svcCtx.AddressRepo, svcCtx.Cache),
-		addressLogic: userservice.NewAddressLogic(svcCtx.AddressRepo, svcCtx.Cache),
+		svcCtx:          svcCtx,
+		logic:           userservice.NewUserLogic(svcCtx.UserRepo, svcCtx.CredentialRepo, svcCtx.AddressRepo, svcCtx.Cache, tokenLogic, verifyCodeLogic, guardLogic, mfaLogic),
+		addressLogic:    userservice.NewAddressLogic(svcCtx.AddressRepo, svcCtx.Cache, svcCtx.Regions),
+		tokenLogic:      tokenLogic,
+		verifyCodeLogic: verifyCodeLogic,
+		guardLogic:      guardLogic,
+		oauthLogic: userservice.NewOAuthLogic(svcCtx.OAuthProviders, svcCtx.Redis, svcCtx.Config.OAuth.StateTTL,
+			svcCtx.UserRepo, svcCtx.CredentialRepo, tokenLogic, mfaLogic),
+		mfaLogic:      mfaLogic,
+		passwordLogic: passwordLogic,
+		memberLogic:   memberLogic,
+
+		dataRequestLogic: dataRequestLogic,
+		sessionLogic:     sessionLogic,
+	}
+}
+
+// startMembershipWorkers 启动会员等级后台任务：消费订单完成/退款事件，周期评估降级
+func startMembershipWorkers(c Config, memberLogic *userservice.MemberLogic) {
+	if c.Kafka != nil && len(c.Kafka.Brokers) > 0 {
+		consumerGroup := c.Kafka.ConsumerGroup
+		if consumerGroup == "" {
+			consumerGroup = "user-service"
+		}
+		consumer, err := mq.NewConsumer(&mq.Config{
+			Brokers:       c.Kafka.Brokers,
+			Version:       c.Kafka.Version,
+			ConsumerGroup: consumerGroup,
+		})
+		if err != nil {
+			logx.Errorf("初始化Kafka消费者失败，会员等级不会自动更新: %v", err)
+		} else {
+			mc := userservice.NewMemberConsumer(memberLogic)
+			consumer.RegisterHandler(mq.TopicOrderCompleted, mc.HandleOrderCompleted)
+			consumer.RegisterHandler(mq.TopicPaymentRefunded, mc.HandlePaymentRefunded)
+
+			go func() {
+				topics := []string{mq.TopicOrderCompleted, mq.TopicPaymentRefunded}
+				if err := consumer.Start(context.Background(), topics); err != nil {
+					logx.Errorf("Kafka消费者退出: %v", err)
+				}
+			}()
+		}
+	}
+
+	if c.Membership.EvaluateInterval > 0 {
+		go memberLogic.RunEvaluator(context.Background(), time.Duration(c.Membership.EvaluateInterval)*time.Second)
+	}
+}
+
+// accessExpire 访问令牌有效期（秒）
+func accessExpire(c Config) int64 {
+	if c.JWT.Expire == 0 {
+		return 900 // 默认 15 分钟
 	}
+	return c.JWT.Expire
 }
@@ -6,8 +6,11 @@ import (
 
 	v1 "ecommerce-system/api/user/v1"
 	apperrors "ecommerce-system/internal/pkg/errors"
+	"ecommerce-system/internal/pkg/membership"
 	"ecommerce-system/internal/pkg/utils"
+	"ecommerce-system/internal/pkg/verifycode"
 	"ecommerce-system/internal/service/user/model"
+	"ecommerce-system/internal/service/user/repository"
 	userservice "ecommerce-system/internal/service/user/service"
 
 	"google.golang.org/grpc/codes"
@@ -51,24 +54,16 @@ func (s *UserService) Register(ctx context.Context, req *v1.RegisterRequest) (*v
 func (s *UserService) Login(ctx context.Context, req *v1.LoginRequest) (*v1.LoginResponse, error) {
 	// 转换请求
 	loginReq := &userservice.LoginRequest{
-		Username:   req.Username,
-		Password:   req.Password,
-		LoginType:  int(req.LoginType),
-		VerifyCode: req.VerifyCode,
-	}
-
-	// 获取 JWT 配置
-	jwtSecret := s.svcCtx.Config.JWT.Secret
-	if jwtSecret == "" {
-		jwtSecret = "default-secret-key" // 开发环境默认值
-	}
-	jwtExpire := s.svcCtx.Config.JWT.Expire
-	if jwtExpire == 0 {
-		jwtExpire = 7200 // 默认 2 小时
+		Username:     req.Username,
+		Password:     req.Password,
+		LoginType:    int(req.LoginType),
+		VerifyCode:   req.VerifyCode,
+		CaptchaToken: req.CaptchaToken,
+		ClientIP:     utils.GetClientIP(ctx),
 	}
 
 	// 调用业务逻辑
-	resp, err := s.logic.Login(ctx, loginReq, jwtSecret, jwtExpire)
+	resp, err := s.logic.Login(ctx, loginReq)
 	if err != nil {
 		// 业务错误：返回结构化响应（避免 Gateway 把 gRPC error 转成 HTTP 500 文本）
 		if bizErr, ok := err.(*apperrors.BusinessError); ok {
@@ -81,18 +76,450 @@ func (s *UserService) Login(ctx context.Context, req *v1.LoginRequest) (*v1.Logi
 		return nil, convertError(err)
 	}
 
+	// 需要两步验证：返回挑战，由 VerifyMFA 完成登录
+	if resp.MFA != nil {
+		return &v1.LoginResponse{
+			Code:    0,
+			Message: "请完成两步验证",
+			Data:    convertMFAChallengeToProto(resp.User, resp.MFA),
+		}, nil
+	}
+
 	// 转换响应
 	return &v1.LoginResponse{
 		Code:    0,
 		Message: "登录成功",
-		Data: &v1.LoginData{
-			User:       convertUserToProto(resp.User),
-			Token:      resp.Token,
-			ExpireTime: resp.ExpireTime,
+		Data:    convertLoginDataToProto(resp.User, resp.Tokens),
+	}, nil
+}
+
+// RefreshToken 刷新令牌
+func (s *UserService) RefreshToken(ctx context.Context, req *v1.RefreshTokenRequest) (*v1.RefreshTokenResponse, error) {
+	// 刷新失败返回 Unauthenticated（HTTP 401），客户端据此跳转登录
+	user, tokens, err := s.tokenLogic.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RefreshTokenResponse{
+		Code:    0,
+		Message: "刷新成功",
+		Data:    convertLoginDataToProto(user, tokens),
+	}, nil
+}
+
+// Logout 登出
+func (s *UserService) Logout(ctx context.Context, req *v1.LogoutRequest) (*v1.LogoutResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+	claims, _ := utils.GetClaims(ctx)
+
+	if err := s.tokenLogic.Logout(ctx, userID, claims, req.RefreshToken); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.LogoutResponse{
+		Code:    0,
+		Message: "已退出登录",
+	}, nil
+}
+
+// RevokeAllSessions 退出所有设备
+func (s *UserService) RevokeAllSessions(ctx context.Context, req *v1.RevokeAllSessionsRequest) (*v1.RevokeAllSessionsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	revoked, err := s.tokenLogic.RevokeAllSessions(ctx, userID)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RevokeAllSessionsResponse{
+		Code:    0,
+		Message: "已退出所有设备",
+		Revoked: int32(revoked),
+	}, nil
+}
+
+// ListMySessions 我的登录设备
+func (s *UserService) ListMySessions(ctx context.Context, req *v1.ListMySessionsRequest) (*v1.ListMySessionsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	sessions, _, err := s.sessionLogic.List(ctx, userID, true, 1, 100)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	var currentJTI string
+	if claims, ok := utils.GetClaims(ctx); ok {
+		currentJTI = claims.ID
+	}
+	data := make([]*v1.Session, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, convertSessionToProto(session, currentJTI))
+	}
+	return &v1.ListMySessionsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
+// RevokeSession 下线一个登录设备
+func (s *UserService) RevokeSession(ctx context.Context, req *v1.RevokeSessionRequest) (*v1.RevokeSessionResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	if err := s.tokenLogic.RevokeSession(ctx, userID, uint64(req.SessionId), userID, utils.GetClientIP(ctx)); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RevokeSessionResponse{
+		Code:    0,
+		Message: "设备已下线",
+	}, nil
+}
+
+// SendVerifyCode 发送验证码
+func (s *UserService) SendVerifyCode(ctx context.Context, req *v1.SendVerifyCodeRequest) (*v1.SendVerifyCodeResponse, error) {
+	// 更换手机号需要登录，避免匿名刷短信
+	if req.Scene == verifycode.SceneChangePhone {
+		if _, ok := utils.GetUserID(ctx); !ok {
+			return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+		}
+	}
+
+	retryAfter, err := s.verifyCodeLogic.SendVerifyCode(ctx, &userservice.SendVerifyCodeRequest{
+		Scene:    int(req.Scene),
+		Target:   req.Target,
+		ClientIP: utils.GetClientIP(ctx),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.SendVerifyCodeResponse{
+		Code:       0,
+		Message:    "验证码已发送",
+		RetryAfter: retryAfter,
+	}, nil
+}
+
+// CheckVerifyCode 校验验证码
+func (s *UserService) CheckVerifyCode(ctx context.Context, req *v1.CheckVerifyCodeRequest) (*v1.CheckVerifyCodeResponse, error) {
+	if err := s.verifyCodeLogic.CheckVerifyCode(ctx, int(req.Scene), req.Target, req.VerifyCode); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.CheckVerifyCodeResponse{
+		Code:    0,
+		Message: "验证码正确",
+	}, nil
+}
+
+// ChangePhone 更换手机号
+func (s *UserService) ChangePhone(ctx context.Context, req *v1.ChangePhoneRequest) (*v1.ChangePhoneResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	user, err := s.logic.ChangePhone(ctx, userID, req.Phone, req.VerifyCode)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.ChangePhoneResponse{
+		Code:    0,
+		Message: "手机号已更换",
+		Data:    convertUserToProto(user),
+	}, nil
+}
+
+// RequestPasswordReset 申请重置密码
+func (s *UserService) RequestPasswordReset(ctx context.Context, req *v1.RequestPasswordResetRequest) (*v1.RequestPasswordResetResponse, error) {
+	retryAfter, err := s.passwordLogic.RequestReset(ctx, req.Target)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RequestPasswordResetResponse{
+		Code:       0,
+		Message:    "如果该手机号/邮箱已注册，重置链接已发送",
+		RetryAfter: retryAfter,
+	}, nil
+}
+
+// ResetPassword 用重置 token 设置新密码
+func (s *UserService) ResetPassword(ctx context.Context, req *v1.ResetPasswordRequest) (*v1.ResetPasswordResponse, error) {
+	if err := s.passwordLogic.Reset(ctx, req.Token, req.NewPassword, utils.GetClientIP(ctx)); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.ResetPasswordResponse{
+		Code:    0,
+		Message: "密码已重置，请重新登录",
+	}, nil
+}
+
+// ChangePassword 修改密码
+func (s *UserService) ChangePassword(ctx context.Context, req *v1.ChangePasswordRequest) (*v1.ChangePasswordResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	user, tokens, err := s.passwordLogic.Change(ctx, userID, req.OldPassword, req.NewPassword, req.MfaCode, utils.GetClientIP(ctx))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.ChangePasswordResponse{
+		Code:    0,
+		Message: "密码已修改",
+		Data:    convertLoginDataToProto(user, tokens),
+	}, nil
+}
+
+// ListOAuthProviders 获取可用的第三方登录方式
+func (s *UserService) ListOAuthProviders(ctx context.Context, req *v1.ListOAuthProvidersRequest) (*v1.ListOAuthProvidersResponse, error) {
+	providers := s.oauthLogic.ListProviders()
+	data := make([]*v1.OAuthProvider, 0, len(providers))
+	for _, p := range providers {
+		data = append(data, &v1.OAuthProvider{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return &v1.ListOAuthProvidersResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
+// GetOAuthAuthURL 获取第三方授权地址
+func (s *UserService) GetOAuthAuthURL(ctx context.Context, req *v1.GetOAuthAuthURLRequest) (*v1.GetOAuthAuthURLResponse, error) {
+	// 绑定流程把当前用户记在 state 中，回调时校验是同一个用户
+	var userID uint64
+	if req.Link {
+		id, ok := utils.GetUserID(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+		}
+		userID = id
+	}
+
+	authURL, state, err := s.oauthLogic.AuthURL(ctx, req.Provider, userID)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.GetOAuthAuthURLResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    &v1.OAuthAuthURL{AuthUrl: authURL, State: state},
+	}, nil
+}
+
+// OAuthLogin 第三方登录
+func (s *UserService) OAuthLogin(ctx context.Context, req *v1.OAuthLoginRequest) (*v1.OAuthLoginResponse, error) {
+	resp, err := s.oauthLogic.Login(ctx, req.Provider, req.Code, req.State)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if resp.MFA != nil {
+		return &v1.OAuthLoginResponse{
+			Code:      0,
+			Message:   "请完成两步验证",
+			Data:      convertMFAChallengeToProto(resp.User, resp.MFA),
+			IsNewUser: resp.IsNewUser,
+		}, nil
+	}
+	return &v1.OAuthLoginResponse{
+		Code:      0,
+		Message:   "登录成功",
+		Data:      convertLoginDataToProto(resp.User, resp.Tokens),
+		IsNewUser: resp.IsNewUser,
+	}, nil
+}
+
+// LinkOAuthIdentity 绑定第三方账号
+func (s *UserService) LinkOAuthIdentity(ctx context.Context, req *v1.LinkOAuthIdentityRequest) (*v1.LinkOAuthIdentityResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	identity, err := s.oauthLogic.Link(ctx, userID, req.Provider, req.Code, req.State)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.LinkOAuthIdentityResponse{
+		Code:    0,
+		Message: "绑定成功",
+		Data:    convertOAuthIdentityToProto(identity),
+	}, nil
+}
+
+// ListOAuthIdentities 获取已绑定的第三方账号
+func (s *UserService) ListOAuthIdentities(ctx context.Context, req *v1.ListOAuthIdentitiesRequest) (*v1.ListOAuthIdentitiesResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	identities, err := s.oauthLogic.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	data := make([]*v1.OAuthIdentity, 0, len(identities))
+	for _, id := range identities {
+		data = append(data, convertOAuthIdentityToProto(id))
+	}
+	return &v1.ListOAuthIdentitiesResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
+// UnlinkOAuthIdentity 解绑第三方账号
+func (s *UserService) UnlinkOAuthIdentity(ctx context.Context, req *v1.UnlinkOAuthIdentityRequest) (*v1.UnlinkOAuthIdentityResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	if err := s.oauthLogic.Unlink(ctx, userID, req.Provider); err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.UnlinkOAuthIdentityResponse{
+		Code:    0,
+		Message: "解绑成功",
+	}, nil
+}
+
+// VerifyMFA 登录第二步
+func (s *UserService) VerifyMFA(ctx context.Context, req *v1.VerifyMFARequest) (*v1.VerifyMFAResponse, error) {
+	result, err := s.mfaLogic.Verify(ctx, req.MfaToken, req.Code, utils.GetClientIP(ctx))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.VerifyMFAResponse{
+		Code:          0,
+		Message:       "登录成功",
+		Data:          convertLoginDataToProto(result.User, result.Tokens),
+		RecoveryCodes: result.RecoveryCodes,
+	}, nil
+}
+
+// GetMFAStatus 获取两步验证状态
+func (s *UserService) GetMFAStatus(ctx context.Context, req *v1.GetMFAStatusRequest) (*v1.GetMFAStatusResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	st, err := s.mfaLogic.Status(ctx, userID)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.GetMFAStatusResponse{
+		Code:    0,
+		Message: "成功",
+		Data: &v1.MFAStatus{
+			Enabled:           st.Enabled,
+			Required:          st.Required,
+			RecoveryCodesLeft: int32(st.RecoveryCodesLeft),
 		},
 	}, nil
 }
 
+// SetupTOTP 生成 TOTP 密钥
+func (s *UserService) SetupTOTP(ctx context.Context, req *v1.SetupTOTPRequest) (*v1.SetupTOTPResponse, error) {
+	var setup *userservice.TOTPSetup
+	var err error
+	if req.MfaToken != "" {
+		// 登录过程中角色要求开启但尚未绑定
+		setup, err = s.mfaLogic.SetupWithChallenge(ctx, req.MfaToken)
+	} else {
+		userID, ok := utils.GetUserID(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+		}
+		setup, err = s.mfaLogic.Setup(ctx, userID)
+	}
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.SetupTOTPResponse{
+		Code:    0,
+		Message: "成功",
+		Data: &v1.TOTPSetup{
+			Secret:          setup.Secret,
+			ProvisioningUri: setup.ProvisioningURI,
+		},
+	}, nil
+}
+
+// ConfirmTOTP 确认绑定 TOTP
+func (s *UserService) ConfirmTOTP(ctx context.Context, req *v1.ConfirmTOTPRequest) (*v1.ConfirmTOTPResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	recoveryCodes, err := s.mfaLogic.Confirm(ctx, userID, req.Code)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.ConfirmTOTPResponse{
+		Code:          0,
+		Message:       "两步验证已开启",
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// DisableTOTP 关闭两步验证
+func (s *UserService) DisableTOTP(ctx context.Context, req *v1.DisableTOTPRequest) (*v1.DisableTOTPResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	if err := s.mfaLogic.Disable(ctx, userID, req.Code); err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.DisableTOTPResponse{
+		Code:    0,
+		Message: "两步验证已关闭",
+	}, nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, req *v1.RegenerateRecoveryCodesRequest) (*v1.RegenerateRecoveryCodesResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	recoveryCodes, err := s.mfaLogic.RegenerateRecoveryCodes(ctx, userID, req.Code)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &v1.RegenerateRecoveryCodesResponse{
+		Code:          0,
+		Message:       "恢复码已重新生成",
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
 // GetUserInfo 获取用户信息
 func (s *UserService) GetUserInfo(ctx context.Context, req *v1.GetUserInfoRequest) (*v1.GetUserInfoResponse, error) {
 	// 优先从 context 取 user_id（由 gRPC interceptor 从 Authorization 解析得到）
@@ -198,23 +625,302 @@ func (s *UserService) ListUsers(ctx context.Context, req *v1.ListUsersRequest) (
 	}, nil
 }
 
-// DeleteUser 删除用户（管理后台）
+// DeleteUser 删除用户（管理后台），按注销流程删除或匿名化各服务中的个人数据
 func (s *UserService) DeleteUser(ctx context.Context, req *v1.DeleteUserRequest) (*v1.DeleteUserResponse, error) {
-	// 转换请求
-	deleteReq := &userservice.DeleteUserRequest{
-		UserID: uint64(req.Id), // 路径参数 :id 映射到 req.Id
+	operatorID, _ := utils.GetUserID(ctx)
+	// 路径参数 :id 映射到 req.Id
+	detail, err := s.dataRequestLogic.RequestErasureByAdmin(ctx, uint64(req.Id), operatorID, utils.GetClientIP(ctx))
+	if err != nil {
+		return nil, convertError(err)
 	}
 
-	// 调用业务逻辑
-	_, err := s.logic.DeleteUser(ctx, deleteReq)
+	return &v1.DeleteUserResponse{
+		Code:    0,
+		Message: "账号已停用，个人数据正在删除: " + detail.Request.RequestNo,
+	}, nil
+}
+
+// UnlockUser 解除账号锁定（管理后台）
+func (s *UserService) UnlockUser(ctx context.Context, req *v1.UnlockUserRequest) (*v1.UnlockUserResponse, error) {
+	operatorID, _ := utils.GetUserID(ctx)
+	if err := s.guardLogic.Unlock(ctx, uint64(req.UserId), operatorID, utils.GetClientIP(ctx)); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.UnlockUserResponse{
+		Code:    0,
+		Message: "已解除锁定",
+	}, nil
+}
+
+// ListUserSessions 查看用户的登录会话（管理后台）
+func (s *UserService) ListUserSessions(ctx context.Context, req *v1.ListUserSessionsRequest) (*v1.ListUserSessionsResponse, error) {
+	sessions, total, err := s.sessionLogic.List(ctx, uint64(req.UserId), !req.IncludeInactive, int(req.Page), int(req.PageSize))
 	if err != nil {
 		return nil, convertError(err)
 	}
 
-	// 转换响应
-	return &v1.DeleteUserResponse{
+	data := make([]*v1.Session, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, convertSessionToProto(session, ""))
+	}
+	return &v1.ListUserSessionsResponse{
 		Code:    0,
-		Message: "删除成功",
+		Message: "成功",
+		Data:    data,
+		Total:   total,
+	}, nil
+}
+
+// RevokeUserSession 强制下线用户的登录会话（管理后台）
+func (s *UserService) RevokeUserSession(ctx context.Context, req *v1.RevokeUserSessionRequest) (*v1.RevokeUserSessionResponse, error) {
+	operatorID, _ := utils.GetUserID(ctx)
+	if err := s.tokenLogic.RevokeSession(ctx, uint64(req.UserId), uint64(req.SessionId), operatorID, utils.GetClientIP(ctx)); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RevokeUserSessionResponse{
+		Code:    0,
+		Message: "已强制下线",
+	}, nil
+}
+
+// GetMemberBenefits 获取会员等级和权益
+func (s *UserService) GetMemberBenefits(ctx context.Context, req *v1.GetMemberBenefitsRequest) (*v1.GetMemberBenefitsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		// 促销、物流服务结算时按请求中的 user_id 查询
+		if req.UserId > 0 {
+			userID = uint64(req.UserId)
+		} else {
+			return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+		}
+	}
+
+	benefits, err := s.memberLogic.Benefits(ctx, userID)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := &v1.MemberBenefits{
+		Tier:                  convertMemberTierToProto(benefits.Tier),
+		Gmv:                   benefits.GMV,
+		OrderCount:            int32(benefits.OrderCount),
+		FreeShippingUsed:      int32(benefits.FreeShippingUsed),
+		FreeShippingRemaining: int32(benefits.FreeShippingLeft),
+	}
+	if benefits.Next != nil {
+		data.NextTier = convertMemberTierToProto(*benefits.Next)
+	}
+	return &v1.GetMemberBenefitsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
+// ListMemberTiers 获取会员等级定义
+func (s *UserService) ListMemberTiers(ctx context.Context, req *v1.ListMemberTiersRequest) (*v1.ListMemberTiersResponse, error) {
+	tiers := s.memberLogic.Tiers()
+	data := make([]*v1.MemberTier, 0, len(tiers))
+	for _, t := range tiers {
+		data = append(data, convertMemberTierToProto(t))
+	}
+	return &v1.ListMemberTiersResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
+// ListMemberLevelLogs 获取当前用户的会员等级变更记录
+func (s *UserService) ListMemberLevelLogs(ctx context.Context, req *v1.ListMemberLevelLogsRequest) (*v1.ListMemberLevelLogsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	logs, total, err := s.memberLogic.ListLevelLogs(ctx, userID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.MemberLevelLog, 0, len(logs))
+	for _, l := range logs {
+		data = append(data, &v1.MemberLevelLog{
+			Id:         int64(l.ID),
+			FromLevel:  int32(l.FromLevel),
+			ToLevel:    int32(l.ToLevel),
+			Reason:     l.Reason,
+			Gmv:        l.GMV,
+			OrderCount: int32(l.OrderCount),
+			CreatedAt:  formatTime(&l.CreatedAt),
+		})
+	}
+	return &v1.ListMemberLevelLogsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+		Total:   total,
+	}, nil
+}
+
+// EvaluateMemberLevels 立即执行一次会员等级评估（管理后台）
+func (s *UserService) EvaluateMemberLevels(ctx context.Context, req *v1.EvaluateMemberLevelsRequest) (*v1.EvaluateMemberLevelsResponse, error) {
+	changed, err := s.memberLogic.EvaluateAll(ctx)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.EvaluateMemberLevelsResponse{
+		Code:    0,
+		Message: "评估完成",
+		Changed: int32(changed),
+	}, nil
+}
+
+// UseMemberFreeShipping 为订单占用一次会员包邮次数
+func (s *UserService) UseMemberFreeShipping(ctx context.Context, req *v1.UseMemberFreeShippingRequest) (*v1.UseMemberFreeShippingResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		if req.UserId > 0 {
+			userID = uint64(req.UserId)
+		} else {
+			return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+		}
+	}
+
+	granted, remaining, err := s.memberLogic.UseFreeShipping(ctx, userID, req.OrderNo)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.UseMemberFreeShippingResponse{
+		Code:      0,
+		Message:   "成功",
+		Granted:   granted,
+		Remaining: int32(remaining),
+	}, nil
+}
+
+// ExportMyData 申请导出个人数据
+func (s *UserService) ExportMyData(ctx context.Context, req *v1.ExportMyDataRequest) (*v1.ExportMyDataResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	detail, err := s.dataRequestLogic.RequestExport(ctx, userID, utils.GetClientIP(ctx))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.ExportMyDataResponse{
+		Code:    0,
+		Message: "已提交导出申请，完成后将通知您下载",
+		Data:    convertDataRequestToProto(detail),
+	}, nil
+}
+
+// EraseMyAccount 注销账号
+func (s *UserService) EraseMyAccount(ctx context.Context, req *v1.EraseMyAccountRequest) (*v1.EraseMyAccountResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	detail, err := s.dataRequestLogic.RequestErasure(ctx, userID, req.Password, req.MfaCode, utils.GetClientIP(ctx))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.EraseMyAccountResponse{
+		Code:    0,
+		Message: "账号已停用，个人数据正在删除",
+		Data:    convertDataRequestToProto(detail),
+	}, nil
+}
+
+// GetDataRequest 获取个人数据请求详情
+func (s *UserService) GetDataRequest(ctx context.Context, req *v1.GetDataRequestRequest) (*v1.GetDataRequestResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	detail, err := s.dataRequestLogic.Get(ctx, userID, req.RequestNo)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.GetDataRequestResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    convertDataRequestToProto(detail),
+	}, nil
+}
+
+// ListMyDataRequests 获取我的个人数据请求列表
+func (s *UserService) ListMyDataRequests(ctx context.Context, req *v1.ListMyDataRequestsRequest) (*v1.ListMyDataRequestsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "未授权，请先登录")
+	}
+
+	reqs, total, err := s.dataRequestLogic.List(ctx, repository.DataRequestFilter{UserID: userID, Status: -1},
+		int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.DataRequest, 0, len(reqs))
+	for _, r := range reqs {
+		data = append(data, convertDataRequestToProto(&userservice.DataRequestDetail{Request: r}))
+	}
+	return &v1.ListMyDataRequestsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+		Total:   total,
+	}, nil
+}
+
+// ListDataRequests 获取个人数据请求列表（管理后台）
+func (s *UserService) ListDataRequests(ctx context.Context, req *v1.ListDataRequestsRequest) (*v1.ListDataRequestsResponse, error) {
+	filter := repository.DataRequestFilter{
+		UserID: uint64(req.UserId),
+		Type:   req.Type,
+		Status: int8(req.Status),
+	}
+	reqs, total, err := s.dataRequestLogic.List(ctx, filter, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.DataRequest, 0, len(reqs))
+	for _, r := range reqs {
+		data = append(data, convertDataRequestToProto(&userservice.DataRequestDetail{Request: r}))
+	}
+	return &v1.ListDataRequestsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+		Total:   total,
+	}, nil
+}
+
+// RetryDataRequest 重新执行失败的个人数据请求（管理后台）
+func (s *UserService) RetryDataRequest(ctx context.Context, req *v1.RetryDataRequestRequest) (*v1.RetryDataRequestResponse, error) {
+	operatorID, _ := utils.GetUserID(ctx)
+	detail, err := s.dataRequestLogic.Retry(ctx, req.RequestNo, operatorID)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return &v1.RetryDataRequestResponse{
+		Code:    0,
+		Message: "已重新提交处理",
+		Data:    convertDataRequestToProto(detail),
 	}, nil
 }
 
@@ -274,6 +980,9 @@ func (s *UserService) AddAddress(ctx context.Context, req *v1.AddAddressRequest)
 		Detail:        req.Detail,
 		PostalCode:    req.PostalCode,
 		IsDefault:     int8(req.IsDefault),
+		ProvinceCode:  req.ProvinceCode,
+		CityCode:      req.CityCode,
+		DistrictCode:  req.DistrictCode,
 	}
 
 	// 调用业务逻辑
@@ -313,6 +1022,9 @@ func (s *UserService) UpdateAddress(ctx context.Context, req *v1.UpdateAddressRe
 		Detail:        req.Detail,
 		PostalCode:    req.PostalCode,
 		IsDefault:     int8(req.IsDefault),
+		ProvinceCode:  req.ProvinceCode,
+		CityCode:      req.CityCode,
+		DistrictCode:  req.DistrictCode,
 	}
 
 	// 调用业务逻辑
@@ -359,6 +1071,30 @@ func (s *UserService) DeleteAddress(ctx context.Context, req *v1.DeleteAddressRe
 	}, nil
 }
 
+// GetRegions 获取行政区划（省市区级联选择）
+func (s *UserService) GetRegions(ctx context.Context, req *v1.GetRegionsRequest) (*v1.GetRegionsResponse, error) {
+	regions, err := s.addressLogic.GetRegions(ctx, req.ParentCode)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	data := make([]*v1.Region, 0, len(regions))
+	for _, r := range regions {
+		data = append(data, &v1.Region{
+			Code:        r.Code,
+			Name:        r.Name,
+			Level:       int32(r.Level),
+			ParentCode:  r.ParentCode,
+			HasChildren: r.HasChildren,
+		})
+	}
+	return &v1.GetRegionsResponse{
+		Code:    0,
+		Message: "成功",
+		Data:    data,
+	}, nil
+}
+
 // convertError 转换业务错误为 gRPC 错误
 func convertError(err error) error {
 	if err == nil {
@@ -373,10 +1109,18 @@ func convertError(err error) error {
 			grpcCode = codes.NotFound
 		case apperrors.CodeInvalidParam:
 			grpcCode = codes.InvalidArgument
-		case apperrors.CodeUnauthorized:
+		case apperrors.CodeUnauthorized, apperrors.CodeTokenInvalid, apperrors.CodeTokenExpired:
 			grpcCode = codes.Unauthenticated
-		case apperrors.CodeForbidden:
+		case apperrors.CodeForbidden, apperrors.CodeUserDisabled:
 			grpcCode = codes.PermissionDenied
+		case apperrors.CodeAlreadyExists, apperrors.CodeUserAlreadyExists:
+			grpcCode = codes.AlreadyExists
+		case apperrors.CodePasswordError, apperrors.CodeVerifyCodeError, apperrors.CodeMFACodeError:
+			grpcCode = codes.InvalidArgument
+		case apperrors.CodeTooManyRequests, apperrors.CodeAccountLocked:
+			grpcCode = codes.ResourceExhausted
+		case apperrors.CodeCaptchaRequired:
+			grpcCode = codes.FailedPrecondition
 		default:
 			grpcCode = codes.Internal
 		}
@@ -409,6 +1153,39 @@ func convertUserToProto(user *model.User) *v1.User {
 	}
 }
 
+// convertLoginDataToProto 转换登录结果为 Protobuf 消息
+func convertLoginDataToProto(user *model.User, tokens *userservice.TokenPair) *v1.LoginData {
+	return &v1.LoginData{
+		User:              convertUserToProto(user),
+		Token:             tokens.AccessToken,
+		ExpireTime:        tokens.AccessExpireAt.Unix(),
+		RefreshToken:      tokens.RefreshToken,
+		RefreshExpireTime: tokens.RefreshExpireAt.Unix(),
+	}
+}
+
+// convertMFAChallengeToProto 需要两步验证时的登录数据，不含令牌
+func convertMFAChallengeToProto(user *model.User, c *userservice.MFAChallenge) *v1.LoginData {
+	return &v1.LoginData{
+		User:              convertUserToProto(user),
+		MfaRequired:       true,
+		MfaToken:          c.Token,
+		MfaExpireTime:     c.ExpireAt.Unix(),
+		MfaEnrollRequired: c.EnrollRequired,
+	}
+}
+
+// convertOAuthIdentityToProto 转换第三方身份为 Protobuf 消息
+func convertOAuthIdentityToProto(id *userservice.OAuthIdentity) *v1.OAuthIdentity {
+	return &v1.OAuthIdentity{
+		Provider: id.Provider,
+		Subject:  id.Subject,
+		Email:    id.Email,
+		Name:     id.Name,
+		LinkedAt: formatTime(&id.LinkedAt),
+	}
+}
+
 // convertAddressToProto 转换地址模型为 Protobuf 消息
 func convertAddressToProto(addr *model.Address) *v1.Address {
 	if addr == nil {
@@ -428,6 +1205,67 @@ func convertAddressToProto(addr *model.Address) *v1.Address {
 		IsDefault:     int32(addr.IsDefault),
 		CreatedAt:     formatTime(&addr.CreatedAt),
 		UpdatedAt:     formatTime(&addr.UpdatedAt),
+		ProvinceCode:  addr.ProvinceCode,
+		CityCode:      addr.CityCode,
+		DistrictCode:  addr.DistrictCode,
+	}
+}
+
+// convertMemberTierToProto 转换会员等级定义
+func convertMemberTierToProto(t membership.Tier) *v1.MemberTier {
+	return &v1.MemberTier{
+		Level:             int32(t.Level),
+		Name:              t.Name,
+		MinGmv:            t.MinGMV,
+		MinOrders:         int32(t.MinOrders),
+		DiscountRate:      t.DiscountRate,
+		FreeShippingQuota: int32(t.FreeShippingQuota),
+		PointsMultiplier:  t.PointsMultiplier,
+	}
+}
+
+// convertDataRequestToProto 转换个人数据请求，列表中不含任务明细
+func convertDataRequestToProto(d *userservice.DataRequestDetail) *v1.DataRequest {
+	r := d.Request
+	out := &v1.DataRequest{
+		RequestNo:         r.RequestNo,
+		UserId:            int64(r.UserID),
+		Type:              r.Type,
+		Status:            int32(r.Status),
+		Progress:          int32(r.Progress),
+		Error:             r.Error,
+		CreatedAt:         formatTime(&r.CreatedAt),
+		FinishedAt:        formatTime(r.FinishedAt),
+		DownloadUrl:       d.DownloadURL,
+		DownloadExpiresAt: d.DownloadExpiresAt,
+	}
+	for _, t := range d.Tasks {
+		out.Tasks = append(out.Tasks, &v1.DataRequestTask{
+			Service:    t.Service,
+			Status:     int32(t.Status),
+			Summary:    t.Summary,
+			Error:      t.Error,
+			FinishedAt: formatTime(t.FinishedAt),
+		})
+	}
+	return out
+}
+
+// convertSessionToProto 转换登录会话，currentJTI 为当前请求的访问令牌 jti，用于标记当前设备
+func convertSessionToProto(session *model.UserSession, currentJTI string) *v1.Session {
+	return &v1.Session{
+		Id:         int64(session.ID),
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		Ip:         session.IP,
+		Location:   session.Location,
+		LoginIp:    session.LoginIP,
+		CreatedAt:  formatTime(&session.CreatedAt),
+		LastSeenAt: formatTime(&session.LastSeenAt),
+		ExpiresAt:  formatTime(&session.ExpiresAt),
+		RevokedAt:  formatTime(session.RevokedAt),
+		Active:     session.Active(time.Now()),
+		Current:    currentJTI != "" && session.AccessJTI == currentJTI,
 	}
 }
 
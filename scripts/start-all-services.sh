@@ -104,7 +104,7 @@ if [ "$GATEWAY" = true ]; then
         if [ -f "bin/api-gateway" ]; then
             ./bin/api-gateway -f "$gateway_config" &
         else
-            go run cmd/api-gateway/main.go -f "$gateway_config" &
+            go run ./cmd/api-gateway -f "$gateway_config" &
         fi
         PIDS+=($!)
     else
@@ -646,7 +646,7 @@ if [ "$GATEWAY" = true ]; then
             if [ -f "bin/api-gateway" ]; then
                 ./bin/api-gateway -f "$GATEWAY_CONFIG" > "$log_file" 2>&1 &
             else
-                go run cmd/api-gateway/main.go -f "$GATEWAY_CONFIG" > "$log_file" 2>&1 &
+                go run ./cmd/api-gateway -f "$GATEWAY_CONFIG" > "$log_file" 2>&1 &
             fi
             
             PIDS+=($!)
@@ -712,36 +712,24 @@ print_section "启动 Swagger UI"
 
 SWAGGER_STARTED=false
 
-if command -v protoc >/dev/null 2>&1 && command -v protoc-gen-openapiv2 >/dev/null 2>&1; then
-    print_info "生成 OpenAPI 文档..."
-    if bash scripts/generate-openapi.sh > logs/generate-openapi.log 2>&1; then
-        print_success "OpenAPI 文档生成完成"
-    else
-        print_warning "OpenAPI 文档生成失败，查看 logs/generate-openapi.log"
-    fi
-
-    print_info "合并 Swagger 文档..."
-    if go run cmd/generate-swagger/main.go > logs/generate-swagger.log 2>&1; then
-        print_success "Swagger 文档合并完成"
-    else
-        print_warning "Swagger 文档合并失败，查看 logs/generate-swagger.log"
-    fi
+print_info "生成 OpenAPI 3.1 文档..."
+if go run ./cmd/generate-swagger -ts "" > logs/generate-swagger.log 2>&1; then
+    print_success "OpenAPI 文档生成完成"
+else
+    print_warning "OpenAPI 文档生成失败，查看 logs/generate-swagger.log"
+fi
 
-    if [ -f "docs/swagger/all.swagger.json" ] && command -v docker >/dev/null 2>&1; then
-        docker rm -f swagger-ui > /dev/null 2>&1 || true
-        docker run -d --name swagger-ui \
-            -p 8088:8080 \
-            -e SWAGGER_JSON=/docs/all.swagger.json \
-            -v "$(pwd)/docs/swagger:/docs" \
-            swaggerapi/swagger-ui > /dev/null 2>&1
-        print_success "Swagger UI 已启动: http://localhost:8088"
-        SWAGGER_STARTED=true
-    else
-        print_warning "未生成 all.swagger.json 或 Docker 不可用，跳过 Swagger UI"
-    fi
+if [ -f "docs/openapi/openapi.json" ] && command -v docker >/dev/null 2>&1; then
+    docker rm -f swagger-ui > /dev/null 2>&1 || true
+    docker run -d --name swagger-ui \
+        -p 8088:8080 \
+        -e SWAGGER_JSON=/docs/openapi.json \
+        -v "$(pwd)/docs/openapi:/docs" \
+        swaggerapi/swagger-ui > /dev/null 2>&1
+    print_success "Swagger UI 已启动: http://localhost:8088"
+    SWAGGER_STARTED=true
 else
-    print_warning "未安装 protoc 或 protoc-gen-openapiv2，跳过 Swagger UI"
-    print_info "安装命令: go install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2@latest"
+    print_warning "未生成 openapi.json 或 Docker 不可用，跳过 Swagger UI"
 fi
 
 # ============================================
@@ -12,7 +12,7 @@ if [ $# -eq 0 ]; then
     echo "  user-service, product-service, order-service, payment-service"
     echo "  inventory-service, cart-service, promotion-service, review-service"
     echo "  logistics-service, message-service, search-service, recommend-service"
-    echo "  file-service, job-service, api-gateway"
+    echo "  file-service, job-service, api-gateway, push-gateway"
     exit 1
 fi
 
@@ -36,6 +36,7 @@ declare -A SERVICE_CONFIGS=(
     ["file-service"]="configs/dev/file-config.yaml"
     ["job-service"]="configs/dev/job-config.yaml"
     ["api-gateway"]="configs/dev/gateway.yaml"
+    ["push-gateway"]="configs/dev/push-gateway.yaml"
 )
 
 # 确定配置文件
@@ -72,6 +73,6 @@ if [ -f "bin/$SERVICE_NAME" ]; then
     ./bin/$SERVICE_NAME -f "$CONFIG_FILE"
 else
     # 直接运行 Go 程序
-    go run "cmd/$SERVICE_NAME/main.go" -f "$CONFIG_FILE"
+    go run "./cmd/$SERVICE_NAME" -f "$CONFIG_FILE"
 fi
 